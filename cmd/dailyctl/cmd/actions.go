@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// ActionsResult lists the tasks created from a day's action items
+type ActionsResult struct {
+	Date     string                  `json:"date" yaml:"date"`
+	Created  []storage.DailyLogEntry `json:"created" yaml:"created"`
+	Existing int                     `json:"existing" yaml:"existing"`
+}
+
+// actionsCmd represents the actions command
+var actionsCmd = &cobra.Command{
+	Use:   "actions",
+	Short: "Turn action items in a day's entries into tasks",
+	Long: `Scan a day's entry descriptions for action items and create a task note for
+each, linked to its source entry by source_id metadata. Lines like these are
+picked up, as are the action items of meeting entries:
+
+  TODO: send the report
+  - [ ] book the venue
+  Action: review the budget
+  Follow up: ask about the contract
+
+Tasks get a priority so they count as open tasks in plans. Running it again
+only creates tasks for new action items.
+
+Examples:
+  dailyctl actions
+  dailyctl actions --date yesterday --priority 2
+  dailyctl actions --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runActions,
+}
+
+func init() {
+	rootCmd.AddCommand(actionsCmd)
+
+	actionsCmd.Flags().String("date", "", "Day to scan (YYYY-MM-DD or e.g. \"yesterday\"; default: today)")
+	actionsCmd.Flags().Int("priority", storage.DefaultActionPriority, "Priority of the created tasks (1-5)")
+	actionsCmd.Flags().Bool("dry-run", false, dryRunUsage)
+}
+
+func runActions(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	priority, _ := cmd.Flags().GetInt("priority")
+
+	if priority < 1 || priority > 5 {
+		return invalidArgf("priority must be between 1 and 5")
+	}
+
+	date := time.Now()
+	if dateStr != "" {
+		var err error
+		date, err = datetime.ParseDate(dateStr, time.Now())
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
+		}
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	dayLog, err := storageProvider.GetDay(date)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+
+	actions := storage.ExtractActions(dayLog.Entries)
+	requests := storage.ActionTaskRequests(actions, dayLog.Entries, priority)
+
+	result := ActionsResult{
+		Date:     date.Format("2006-01-02"),
+		Created:  []storage.DailyLogEntry{},
+		Existing: len(actions) - len(requests),
+	}
+	for _, createReq := range requests {
+		entry, err := storageProvider.CreateEntry(createReq)
+		if err != nil {
+			return fmt.Errorf("failed to create task %q: %w", createReq.Title, err)
+		}
+		result.Created = append(result.Created, *entry)
+	}
+
+	if err := outputActionsResult(result, preview != nil); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
+
+func outputActionsResult(result ActionsResult, dryRun bool) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	if quiet() {
+		printIDs(result.Created)
+		return nil
+	}
+
+	verb := "Created"
+	if dryRun {
+		verb = "Would create"
+	}
+	if len(result.Created) == 0 {
+		fmt.Printf("No new action items on %s", result.Date)
+	} else {
+		fmt.Printf("%s %d task(s) from %s:\n", verb, len(result.Created), result.Date)
+		for _, task := range result.Created {
+			fmt.Printf("  • %s (priority %d, %s)\n", task.Title, task.Priority, task.Description)
+		}
+	}
+	if result.Existing > 0 {
+		fmt.Printf("\n%d action item(s) already have tasks", result.Existing)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// analyzeCmd represents the analyze command
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Cross-reference what the log records",
+}
+
+var analyzeSleepCmd = &cobra.Command{
+	Use:   "sleep",
+	Short: "Correlate sleep with the next day's mood and productivity",
+	Long: `Join the sleep metric entries with how the following day went: its
+average status (mood) and the time tracked in its entries (productivity).
+Shows both day by day, the days after short and long nights side by side,
+and how closely each moved with sleep.
+
+Sleep logged before noon is taken to be for the night before, and later
+in the day for the coming night, so both "dailyctl metric sleep 7h" in the
+morning and "dailyctl metric sleep 7h --datetime '10pm'" the evening before
+work. Correlations need at least 5 days with both values.
+
+Examples:
+  dailyctl analyze sleep
+  dailyctl analyze sleep --days 90
+  dailyctl analyze sleep --date-start 2025-09-01 --date-end 2025-09-30 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runAnalyzeSleep,
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.AddCommand(analyzeSleepCmd)
+
+	analyzeSleepCmd.Flags().Int("days", 30, "Days to analyze, ending today")
+	analyzeSleepCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD or e.g. \"3 weeks ago\"), overrides --days")
+	analyzeSleepCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+}
+
+// SleepReport is the result of the analyze sleep command
+type SleepReport struct {
+	Period                string `json:"period" yaml:"period"`
+	storage.SleepAnalysis `yaml:",inline"`
+}
+
+func runAnalyzeSleep(cmd *cobra.Command, args []string) error {
+	days, _ := cmd.Flags().GetInt("days")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	if days < 1 {
+		return invalidArgf("--days must be at least 1")
+	}
+
+	now := time.Now()
+	if dateStartStr == "" {
+		end := now
+		if dateEndStr != "" {
+			parsed, err := datetime.ParseDate(dateEndStr, now)
+			if err != nil {
+				return invalidArgf("invalid end date: %s (use YYYY-MM-DD or e.g. \"end of last month\")", dateEndStr)
+			}
+			end = parsed
+		}
+		dateStartStr = datetime.StartOfDay(end).AddDate(0, 0, 1-days).Format("2006-01-02")
+	}
+	start, end, err := resolveTimePeriod("", dateStartStr, dateEndStr, now)
+	if err != nil {
+		return err
+	}
+	if start.After(end) {
+		return invalidArgf("start date cannot be after end date")
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	dayLogs, err := storageProvider.GetDateRange(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+	var entries []storage.DailyLogEntry
+	for _, day := range dayLogs {
+		entries = append(entries, day.Entries...)
+	}
+
+	report := SleepReport{
+		Period:        fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+		SleepAnalysis: storage.AnalyzeSleep(entries),
+	}
+	if ok, err := outputStructured(report); ok {
+		return err
+	}
+	printSleepReport(report)
+	return nil
+}
+
+// printSleepReport renders sleep against the next day's mood and tracked
+// time, by day and by length of night
+func printSleepReport(report SleepReport) {
+	title := "Sleep and the next day - " + report.Period
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+
+	if len(report.Days) == 0 {
+		fmt.Println("No sleep logged (use 'dailyctl metric sleep 7.5h').")
+		return
+	}
+	fmt.Println(style(styleDim, fmt.Sprintf("%d nights, %.1fh average", len(report.Days), report.AverageSleep)))
+
+	// One column per night, so the sleep and mood bars line up
+	var sleep, mood strings.Builder
+	for _, day := range report.Days {
+		sleep.WriteString(style(styleBlue, string(sparkBlocks[sparkLevel(day.Sleep, 4, 10)])))
+		mood.WriteString(moodSparkline([]MoodDay{{Date: day.Date, Status: day.Status}}))
+	}
+	fmt.Println()
+	fmt.Println("  Sleep  " + sleep.String())
+	fmt.Println("  Mood   " + mood.String())
+
+	fmt.Println()
+	fmt.Println(style(styleBold, fmt.Sprintf("  %-6s %6s %6s %10s", "SLEEP", "NIGHTS", "MOOD", "TRACKED")))
+	for _, band := range report.Bands {
+		status := fmt.Sprintf("%6s", "-")
+		bar := ""
+		if band.AverageStatus > 0 {
+			status = style(statusStyle(int(band.AverageStatus+0.5)), fmt.Sprintf("%6.1f", band.AverageStatus))
+			bar = style(statusStyle(int(band.AverageStatus+0.5)), strings.Repeat("█", int(band.AverageStatus*2+0.5)))
+		}
+		fmt.Printf("  %-6s %6d %s %10s  %s\n", band.Label, band.Days, status, formatMinutes(int(band.AverageTracked+0.5)), bar)
+	}
+
+	fmt.Println()
+	for _, c := range []*storage.MetricCorrelation{report.Mood, report.Productivity} {
+		if c == nil {
+			continue
+		}
+		fmt.Printf("Sleep and next-day %s: %s correlation (r = %.2f over %d days)\n", c.Metric, c.Strength(), c.Correlation, c.Days)
+	}
+	if report.Mood == nil && report.Productivity == nil {
+		fmt.Println(style(styleDim, "Too few days with both sleep and a status or tracked time to correlate"))
+	}
+}
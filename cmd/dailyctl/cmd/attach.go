@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// attachFile stores a file in the log repository as the attachment of an
+// entry logged at t, and returns its path there for the entry's metadata.
+// It is called before the entry is created, so an entry never points at a
+// missing file. With dryRun it only reports where the file would go.
+func attachFile(filePath string, t time.Time, dryRun bool) (string, error) {
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return "", fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	attachment := primary.AttachmentPath(t, filePath)
+	if dryRun {
+		fmt.Fprintf(os.Stderr, "Would attach %s as %s\n", filePath, attachment)
+		return attachment, nil
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", filePath, err)
+	}
+	if err := primary.SaveAttachment(attachment, data); err != nil {
+		return "", err
+	}
+	return attachment, nil
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create, list, and restore backup snapshots",
+	Long: `Snapshot day files into timestamped tar.gz archives and restore from them.
+
+Snapshots are written to backup.path (default ~/.dailyctl/backups), or to the
+backups/ directory of a second GitHub repository when backup.repo is set.`,
+}
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Snapshot day files",
+	Long: `Snapshot every day file, or the days within a date range, into a new archive.
+
+Examples:
+  dailyctl backup create
+  dailyctl backup create --date-start 2025-09-01 --date-end 2025-09-30`,
+	Args: cobra.NoArgs,
+	RunE: runBackupCreate,
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots",
+	Args:  cobra.NoArgs,
+	RunE:  runBackupList,
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <date|start..end>",
+	Short: "Restore days from a snapshot",
+	Long: `Restore one day or a range of days, replacing what is currently stored.
+By default each day comes from the newest snapshot that contains it.
+
+Examples:
+  dailyctl backup restore 2025-09-20
+  dailyctl backup restore 2025-09-01..2025-09-30
+  dailyctl backup restore 2025-09-20 --snapshot dailylog-20250925T120000Z.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBackupRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+
+	backupCmd.PersistentFlags().String("path", "", "Local directory for snapshots (default ~/.dailyctl/backups)")
+	backupCmd.PersistentFlags().String("repo", "", "GitHub repository for snapshots (owner/repo) instead of a local directory")
+
+	backupCreateCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD), default: all days")
+	backupCreateCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD), default: all days")
+
+	backupRestoreCmd.Flags().String("snapshot", "", "Restore from this snapshot instead of the newest")
+
+	_ = viper.BindPFlag("backup.path", backupCmd.PersistentFlags().Lookup("path"))
+	_ = viper.BindPFlag("backup.repo", backupCmd.PersistentFlags().Lookup("repo"))
+}
+
+func runBackupCreate(cmd *cobra.Command, args []string) error {
+	start, end, err := parseMirrorRange(cmd)
+	if err != nil {
+		return err
+	}
+
+	backups, err := createBackupStorage()
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	days, err := storageProvider.ListDays(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to list days: %w", err)
+	}
+
+	for _, day := range days {
+		dayLog, err := storageProvider.GetDay(day)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", day.Format("2006-01-02"), err)
+		}
+		data, err := dayLog.ToJSON()
+		if err != nil {
+			return fmt.Errorf("failed to serialize %s: %w", day.Format("2006-01-02"), err)
+		}
+		if err := backups.BackupDay(day, data); err != nil {
+			return err
+		}
+	}
+
+	snapshot, err := backups.Commit()
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if snapshot == nil {
+		fmt.Println("No days to back up")
+		return nil
+	}
+
+	if ok, err := outputStructured(snapshot); ok {
+		return err
+	}
+
+	fmt.Printf("✓ Created %s with %d days\n", snapshot.Name, len(snapshot.Days))
+	return nil
+}
+
+func runBackupList(cmd *cobra.Command, args []string) error {
+	backups, err := createBackupStorage()
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := backups.Snapshots()
+	if err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	if ok, err := outputStructured(snapshots); ok {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		fmt.Println("No snapshots found")
+		return nil
+	}
+	fmt.Printf("%-36s %-17s %5s  %s\n", "SNAPSHOT", "CREATED", "DAYS", "RANGE")
+	for _, snapshot := range snapshots {
+		dayRange := ""
+		if len(snapshot.Days) > 0 {
+			dayRange = snapshot.Days[0].Format("2006-01-02") + " - " + snapshot.Days[len(snapshot.Days)-1].Format("2006-01-02")
+		}
+		fmt.Printf("%-36s %-17s %5d  %s\n", snapshot.Name,
+			snapshot.CreatedAt.Local().Format("2006-01-02 15:04"), len(snapshot.Days), dayRange)
+	}
+	return nil
+}
+
+func runBackupRestore(cmd *cobra.Command, args []string) error {
+	snapshotName, _ := cmd.Flags().GetString("snapshot")
+
+	start, end, err := parseRestoreRange(args[0])
+	if err != nil {
+		return err
+	}
+
+	backups, err := createBackupStorage()
+	if err != nil {
+		return err
+	}
+
+	// Restoring brings back what was already written, so hooks don't run
+	storageProvider, err := createBackendProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	restored := []string{}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		var data []byte
+		if snapshotName != "" {
+			data, err = backups.RestoreDayFrom(snapshotName, day)
+		} else {
+			data, err = backups.RestoreDay(day)
+		}
+		if err != nil {
+			if _, ok := err.(storage.NotFoundError); ok && !start.Equal(end) {
+				continue
+			}
+			return fmt.Errorf("failed to restore %s: %w", day.Format("2006-01-02"), err)
+		}
+
+		if err := restoreDay(storageProvider, data); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", day.Format("2006-01-02"), err)
+		}
+		restored = append(restored, day.Format("2006-01-02"))
+		fmt.Fprintf(statusOut(), "✓ Restored %s\n", day.Format("2006-01-02"))
+	}
+
+	if len(restored) == 0 {
+		return fmt.Errorf("no backed-up days found in %s", args[0])
+	}
+	if ok, err := outputStructured(map[string][]string{"restored": restored}); ok {
+		return err
+	}
+	return nil
+}
+
+// restoreDay overwrites the stored day with serialized backup data
+func restoreDay(store storage.DailyLogStorage, data []byte) error {
+	var dayLog storage.DayLog
+	if err := dayLog.FromJSON(data); err != nil {
+		return fmt.Errorf("failed to parse backup: %w", err)
+	}
+	return replaceDay(store, &dayLog)
+}
+
+// replaceDay overwrites whatever is currently stored for the day with dayLog
+func replaceDay(store storage.DailyLogStorage, dayLog *storage.DayLog) error {
+	current, err := store.GetDay(dayLog.Date)
+	if err != nil {
+		return err
+	}
+	dayLog.Revision = current.Revision
+	dayLog.Format = current.Format
+	dayLog.UpdatedAt = time.Now()
+
+	return store.SaveDay(dayLog)
+}
+
+// parseRestoreRange parses "YYYY-MM-DD" or "YYYY-MM-DD..YYYY-MM-DD"
+func parseRestoreRange(arg string) (time.Time, time.Time, error) {
+	startStr, endStr, isRange := strings.Cut(arg, "..")
+	if !isRange {
+		endStr = startStr
+	}
+
+	start, err := time.Parse("2006-01-02", startStr)
+	if err != nil {
+		return start, start, fmt.Errorf("invalid start date format: %w", err)
+	}
+	end, err := time.Parse("2006-01-02", endStr)
+	if err != nil {
+		return start, end, fmt.Errorf("invalid end date format: %w", err)
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("end date %s is before start date %s", endStr, startStr)
+	}
+	return start, end, nil
+}
+
+// createBackupStorage opens the configured snapshot destination
+func createBackupStorage() (*providers.ArchiveBackupStorage, error) {
+	if backupRepo := viper.GetString("backup.repo"); backupRepo != "" {
+		repoProvider, err := providers.NewGitHubStorageProvider(storage.Config{
+			StorageType:     "github",
+			GitHubRepo:      backupRepo,
+			GitHubToken:     viper.GetString("github.token"),
+			GitHubBaseURL:   viper.GetString("github.base_url"),
+			GitHubUploadURL: viper.GetString("github.upload_url"),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open backup repository: %w", err)
+		}
+		return providers.NewArchiveBackupStorage(providers.NewGitHubSnapshotStore(repoProvider, "backups")), nil
+	}
+
+	backupPath := viper.GetString("backup.path")
+	if backupPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		backupPath = filepath.Join(home, ".dailyctl", "backups")
+	}
+
+	store, err := providers.NewLocalSnapshotStore(backupPath)
+	if err != nil {
+		return nil, err
+	}
+	return providers.NewArchiveBackupStorage(store), nil
+}
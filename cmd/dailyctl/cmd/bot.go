@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/storage"
+	"dailylog/internal/telegram"
+)
+
+// botCmd represents the bot command
+var botCmd = &cobra.Command{
+	Use:   "bot",
+	Short: "Capture entries from chat messages",
+}
+
+var botTelegramCmd = &cobra.Command{
+	Use:   "telegram",
+	Short: "Log messages sent to a Telegram bot as notes",
+	Long: `Run a Telegram bot that logs each message sent to it as a note: the first
+line is the title and the rest the description, #hashtags become tags, and the
+entry is logged at the time the message was sent. A photo is attached to its
+note, stored in the log repository like 'dailyctl log photo', with its caption
+as the text.
+
+The bot polls Telegram for messages until interrupted, so it needs no public
+address. Messages sent while it isn't running are logged when it starts again,
+for up to a day.
+
+Create a bot with @BotFather and configure its token, and the chats allowed
+to log (the bot replies with a chat's ID until it is allowed):
+  telegram.token  DAILYLOG_TELEGRAM_TOKEN
+  telegram.chats  DAILYLOG_TELEGRAM_CHATS  chat IDs, e.g. 123456789
+
+Examples:
+  dailyctl bot telegram
+  dailyctl bot telegram --chats 123456789`,
+	Args: cobra.NoArgs,
+	RunE: runBotTelegram,
+}
+
+func init() {
+	rootCmd.AddCommand(botCmd)
+	botCmd.AddCommand(botTelegramCmd)
+
+	botTelegramCmd.Flags().String("telegram-token", "", "Telegram bot token from @BotFather")
+	botTelegramCmd.Flags().StringSlice("chats", nil, "IDs of the chats allowed to log entries")
+	_ = viper.BindPFlag("telegram.token", botTelegramCmd.Flags().Lookup("telegram-token"))
+	_ = viper.BindPFlag("telegram.chats", botTelegramCmd.Flags().Lookup("chats"))
+}
+
+func runBotTelegram(cmd *cobra.Command, args []string) error {
+	client, err := telegram.NewClient(viper.GetString("telegram.token"), viper.GetString("telegram.api_url"))
+	if err != nil {
+		return fmt.Errorf("%w (use --telegram-token or set DAILYLOG_TELEGRAM_TOKEN)", err)
+	}
+	var chats []int64
+	for _, chat := range config.List(viper.GetViper(), "telegram.chats") {
+		id, err := strconv.ParseInt(chat, 10, 64)
+		if err != nil {
+			return invalidArgf("invalid chat ID %q in telegram.chats", chat)
+		}
+		chats = append(chats, id)
+	}
+
+	storageProvider, _, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	bot := telegram.NewBot(client, storageProvider, chats)
+	bot.Attach = func(t time.Time, name string, data []byte) (string, error) {
+		primary, err := createPrimaryProvider()
+		if err != nil {
+			return "", err
+		}
+		attachment := primary.AttachmentPath(t, name)
+		if err := primary.SaveAttachment(attachment, data); err != nil {
+			return "", err
+		}
+		return attachment, nil
+	}
+	bot.OnLogged = func(entry *storage.DailyLogEntry) {
+		fmt.Printf("Logged %s %s: %s\n", entry.Timestamp.Format("2006-01-02 15:04"), entry.Type, entry.Title)
+	}
+	bot.OnError = func(err error) {
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if len(chats) == 0 {
+		fmt.Fprintln(os.Stderr, "⚠ No chats are allowed to log yet: message the bot to get a chat's ID, then add it to telegram.chats")
+	}
+	fmt.Println("Waiting for Telegram messages (Ctrl+C to stop)")
+	return bot.Run(ctx)
+}
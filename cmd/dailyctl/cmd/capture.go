@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// entryDraft is the front matter of an entry composed in an editor
+type entryDraft struct {
+	Title    string   `yaml:"title"`
+	Tags     []string `yaml:"tags,flow"`
+	Location string   `yaml:"location"`
+}
+
+const frontMatterDelimiter = "---"
+
+// logArgs requires a title unless the entry is composed in an editor
+func logArgs(cmd *cobra.Command, args []string) error {
+	if useEditor, _ := cmd.Flags().GetBool("editor"); useEditor {
+		return cobra.MaximumNArgs(1)(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// readPipedInput returns stdin when it is a pipe or a redirected file, e.g.
+// `git log -1 | dailyctl log activity "Released"`. An interactive terminal
+// is never read.
+func readPipedInput() (string, error) {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return "", nil
+	}
+	if info.Mode()&os.ModeNamedPipe == 0 && !info.Mode().IsRegular() {
+		return "", nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// composeInEditor opens $VISUAL or $EDITOR on a front-matter template
+// prefilled from draft and description, and returns the edited values
+func composeInEditor(draft entryDraft, description string) (entryDraft, string, error) {
+	frontMatter, err := yaml.Marshal(draft)
+	if err != nil {
+		return draft, "", fmt.Errorf("failed to build editor template: %w", err)
+	}
+
+	var template bytes.Buffer
+	template.WriteString(frontMatterDelimiter + "\n")
+	template.Write(frontMatter)
+	template.WriteString("# Write the description below the closing ---. Save with an empty title to cancel.\n")
+	template.WriteString(frontMatterDelimiter + "\n\n")
+	if description != "" {
+		template.WriteString(description + "\n")
+	}
+
+	file, err := os.CreateTemp("", "dailyctl-*.md")
+	if err != nil {
+		return draft, "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(template.Bytes()); err != nil {
+		file.Close()
+		return draft, "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return draft, "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := runEditor(file.Name()); err != nil {
+		return draft, "", err
+	}
+
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return draft, "", fmt.Errorf("failed to read edited entry: %w", err)
+	}
+
+	edited, body, err := parseEntryDraft(string(content))
+	if err != nil {
+		return draft, "", err
+	}
+	if strings.TrimSpace(edited.Title) == "" {
+		return draft, "", fmt.Errorf("entry cancelled: title is empty")
+	}
+	return edited, body, nil
+}
+
+// runEditor opens path in the user's editor and waits for it to exit
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Allow editors with arguments, e.g. EDITOR="code --wait"
+	fields := strings.Fields(editor)
+	editorCmd := exec.Command(fields[0], append(fields[1:], path)...)
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	editorCmd.Stdin = os.Stdin
+
+	// Stdin may already have been consumed as a piped description
+	if tty, err := os.Open("/dev/tty"); err == nil {
+		defer tty.Close()
+		editorCmd.Stdin = tty
+	}
+
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("editor %q failed: %w", editor, err)
+	}
+	return nil
+}
+
+// parseEntryDraft splits an edited entry into its front matter and description
+func parseEntryDraft(content string) (entryDraft, string, error) {
+	var draft entryDraft
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontMatterDelimiter {
+		return draft, "", fmt.Errorf("edited entry must start with %s front matter", frontMatterDelimiter)
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontMatterDelimiter {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return draft, "", fmt.Errorf("edited entry is missing the closing %s", frontMatterDelimiter)
+	}
+
+	if err := yaml.Unmarshal([]byte(strings.Join(lines[1:end], "\n")), &draft); err != nil {
+		return draft, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	body := strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+	return draft, body, nil
+}
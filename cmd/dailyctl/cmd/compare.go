@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/digest"
+	"dailylog/internal/storage"
+)
+
+// compareCmd represents the compare command
+var compareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare what was planned for a week with what got done",
+	Long: `Compare the entries planned for a week with what actually got logged, for
+retros: the completion rate, the plans done, the plans that slipped, and the
+work that wasn't planned.
+
+Plan ahead by logging entries with --planned, e.g. on Monday morning. A plan
+is done when an entry with the same title (ignoring case) is logged that
+week; a plan for an earlier day that wasn't done has slipped. Plans for today
+onwards count only once they are done.
+
+Examples:
+  dailyctl log activity "Write the design doc" --planned --date 2025-09-29
+  dailyctl compare --week
+  dailyctl compare --week="last week"
+  dailyctl compare --week=2025-09-29 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+
+	compareCmd.Flags().String("week", "today", "A date in the week to compare (YYYY-MM-DD or e.g. \"last week\")")
+	compareCmd.Flags().Lookup("week").NoOptDefVal = "today"
+}
+
+// CompareResult is the result of the compare command
+type CompareResult struct {
+	Start                  string `json:"start" yaml:"start"`
+	End                    string `json:"end" yaml:"end"`
+	storage.PlanComparison `yaml:",inline"`
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	weekStr, _ := cmd.Flags().GetString("week")
+	now := time.Now()
+	date, err := datetime.ParseDate(weekStr, now)
+	if err != nil {
+		return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last week\")", weekStr)
+	}
+	start, end := digest.Range(digest.PeriodWeek, date)
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	days, err := storageProvider.GetDateRange(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+	var entries []storage.DailyLogEntry
+	for _, day := range days {
+		entries = append(entries, day.Entries...)
+	}
+
+	result := CompareResult{
+		Start:          start.Format("2006-01-02"),
+		End:            end.Format("2006-01-02"),
+		PlanComparison: storage.ComparePlans(entries, now),
+	}
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	printComparison(result)
+	return nil
+}
+
+// printComparison renders the plan against what was done
+func printComparison(result CompareResult) {
+	title := fmt.Sprintf("Plan vs. actual - week of %s to %s", result.Start, result.End)
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+
+	if result.Planned == 0 {
+		fmt.Println("Nothing was planned this week (log plans with --planned)")
+	} else {
+		due := result.Done + len(result.Slipped)
+		summary := fmt.Sprintf("%d of %d plans due done", result.Done, due)
+		if due > 0 {
+			summary += fmt.Sprintf(" (%.0f%%)", result.CompletionRate*100)
+		}
+		if len(result.Upcoming) > 0 {
+			summary += fmt.Sprintf(", %d still to come", len(result.Upcoming))
+		}
+		fmt.Println(summary)
+	}
+
+	printPlanItems("Done", result.Completed, style(styleGreen, "✓"))
+	printPlanItems("Slipped", result.Slipped, style(styleRed, "✗"))
+	printPlanItems("Upcoming", result.Upcoming, style(styleDim, "☐"))
+
+	if len(result.Unplanned) > 0 {
+		fmt.Println()
+		fmt.Println(style(styleBold, "Unplanned"))
+		for _, entry := range result.Unplanned {
+			line := "  • " + entry.Timestamp.Format("Mon") + "  " + entry.Title
+			if entry.Duration != nil && *entry.Duration > 0 {
+				line += style(styleDim, " ("+formatMinutes(*entry.Duration)+")")
+			}
+			fmt.Println(line)
+		}
+	}
+}
+
+// printPlanItems prints a section of plans, each with the day it was
+// planned for and, once done, the day it was done
+func printPlanItems(heading string, items []storage.PlanItem, mark string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Println()
+	fmt.Println(style(styleBold, heading))
+	for _, item := range items {
+		line := "  " + mark + " " + item.Plan.Timestamp.Format("Mon") + "  " + item.Plan.Title
+		if item.Plan.Priority > 0 {
+			line += " " + formatPriority(item.Plan.Priority)
+		}
+		if item.DoneBy != nil && item.DoneBy.Timestamp.Format("2006-01-02") != item.Plan.Timestamp.Format("2006-01-02") {
+			line += style(styleDim, " · done "+item.DoneBy.Timestamp.Format("Mon"))
+		}
+		fmt.Println(line)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCacheTTL is how long tag history fetched for completion is reused
+const completionCacheTTL = 10 * time.Minute
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for your shell. Besides commands and flags,
+--tags completes from the tags used in the last 90 days and --type from the
+entry types. Tag history is fetched from storage and cached for 10 minutes in
+~/.dailyctl/cache/tags.json, so completion stays fast.
+
+Bash (requires bash-completion):
+  source <(dailyctl completion bash)
+  dailyctl completion bash > /etc/bash_completion.d/dailyctl
+
+Zsh:
+  dailyctl completion zsh > "${fpath[1]}/_dailyctl"
+
+Fish:
+  dailyctl completion fish > ~/.config/fish/completions/dailyctl.fish
+
+PowerShell:
+  dailyctl completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletionV2(out, true)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(out)
+		case "fish":
+			return cmd.Root().GenFishCompletion(out, true)
+		default:
+			return cmd.Root().GenPowerShellCompletionWithDesc(out)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// completeEntryTypes completes --type from the known entry types
+func completeEntryTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return entryTypes, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTags completes the last tag of a comma-separated --tags value
+// from recent tag history
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix, partial := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, partial = toComplete[:i+1], toComplete[i+1:]
+	}
+	used := make(map[string]bool)
+	for _, tag := range strings.Split(prefix, ",") {
+		used[tag] = true
+	}
+
+	var completions []string
+	for _, tag := range cachedTagHistory() {
+		if !used[tag] && strings.HasPrefix(strings.ToLower(tag), strings.ToLower(partial)) {
+			completions = append(completions, prefix+tag)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeProjects completes project names from the stored project list
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := createProjectStore()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	list, err := store.GetProjects()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []string
+	for _, project := range list.Projects {
+		if strings.HasPrefix(strings.ToLower(project.Name), strings.ToLower(toComplete)) {
+			names = append(names, project.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// tagCache is the on-disk cache of recent tags used for completion
+type tagCache struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Tags      []string  `json:"tags"`
+}
+
+// cachedTagHistory returns recent tags, most used first, from the cache when
+// it is fresh and from storage otherwise. Completion must never fail loudly,
+// so errors just mean no suggestions.
+func cachedTagHistory() []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	cachePath := filepath.Join(home, ".dailyctl", "cache", "tags.json")
+
+	var cache tagCache
+	if data, err := os.ReadFile(cachePath); err == nil && json.Unmarshal(data, &cache) == nil {
+		if time.Since(cache.FetchedAt) < completionCacheTTL {
+			return cache.Tags
+		}
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return cache.Tags
+	}
+	cache = tagCache{FetchedAt: time.Now(), Tags: tagHistory(storageProvider, time.Now())}
+
+	if data, err := json.Marshal(cache); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o600)
+		}
+	}
+	return cache.Tags
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+)
+
+// demoCmd represents the demo command
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Try dailyctl with sample data, without a GitHub repository",
+	Long: `Serve the web dashboard (see 'dailyctl serve') over two weeks of sample
+data held in memory, so you can try dailyctl without a GitHub repository or
+token. Nothing is saved.
+
+Any other command runs against the same sample data with --demo, or with
+DAILYLOG_DEMO set.
+
+Examples:
+  dailyctl demo
+  dailyctl demo --addr 127.0.0.1:9000
+  dailyctl --demo get week
+  dailyctl --demo search --query review
+  dailyctl --demo stats`,
+	RunE: runDemo,
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+
+	demoCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+}
+
+func runDemo(cmd *cobra.Command, args []string) error {
+	viper.Set("demo", true)
+	if cmd.Flags().Changed("addr") {
+		addr, _ := cmd.Flags().GetString("addr")
+		viper.Set("serve.addr", addr)
+	}
+
+	fmt.Printf("Demo mode: %d days of sample data in memory; nothing is saved\n", providers.DemoDays)
+	return runServe(cmd, args)
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <before> <after>",
+	Short: "Compare the entries of two days or periods",
+	Long: `Compare two days, weeks, months, or years, git style: the entries added,
+removed, and changed from one to the other, the change in average status
+(mood), and the shift in time tracked, by tag, type, or project.
+
+Entries of the same type and title (ignoring case) are taken to be the same
+entry, so a routine logged on both days shows as changed only if its status,
+priority, duration, location, tags, or description differ.
+
+Examples:
+  dailyctl diff 2025-09-28 2025-09-29
+  dailyctl diff yesterday today
+  dailyctl diff "last week" "this week"
+  dailyctl diff "last month" "this month" --by project
+  dailyctl diff yesterday today -o json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+
+	diffCmd.Flags().String("by", storage.GroupByTag, "Group time by: tag, type, project")
+}
+
+// DiffResult is the result of the diff command
+type DiffResult struct {
+	Before             DiffPeriod `json:"before" yaml:"before"`
+	After              DiffPeriod `json:"after" yaml:"after"`
+	storage.PeriodDiff `yaml:",inline"`
+}
+
+// DiffPeriod is one side of a diff
+type DiffPeriod struct {
+	Start   string `json:"start" yaml:"start"`
+	End     string `json:"end" yaml:"end"`
+	Entries int    `json:"entries" yaml:"entries"`
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	groupBy, _ := cmd.Flags().GetString("by")
+	switch groupBy {
+	case storage.GroupByTag, storage.GroupByType, storage.GroupByProject:
+	default:
+		return invalidArgf("--by must be one of tag, type, project (got %q)", groupBy)
+	}
+
+	now := time.Now()
+	var starts, ends [2]time.Time
+	for i, arg := range args {
+		start, end, err := datetime.ParseRange(arg, now)
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last week\")", arg)
+		}
+		starts[i], ends[i] = start, end
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	var entries [2][]storage.DailyLogEntry
+	for i := range args {
+		days, err := storageProvider.GetDateRange(starts[i], ends[i])
+		if err != nil {
+			return fmt.Errorf("failed to get entries: %w", err)
+		}
+		for _, day := range days {
+			entries[i] = append(entries[i], day.Entries...)
+		}
+	}
+
+	result := DiffResult{PeriodDiff: storage.DiffEntries(entries[0], entries[1], groupBy)}
+	for i, period := range []*DiffPeriod{&result.Before, &result.After} {
+		*period = DiffPeriod{
+			Start:   starts[i].Format("2006-01-02"),
+			End:     ends[i].Format("2006-01-02"),
+			Entries: len(entries[i]),
+		}
+	}
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	printDiff(result)
+	return nil
+}
+
+// Label names the period, as a day or a range of days
+func (p DiffPeriod) Label() string {
+	if p.Start == p.End {
+		return p.Start
+	}
+	return p.Start + ".." + p.End
+}
+
+// printDiff renders a diff with +, -, and ~ lines, then the mood and time shifts
+func printDiff(result DiffResult) {
+	fmt.Println(style(styleBold, fmt.Sprintf("--- %s (%d entries)", result.Before.Label(), result.Before.Entries)))
+	fmt.Println(style(styleBold, fmt.Sprintf("+++ %s (%d entries)", result.After.Label(), result.After.Entries)))
+	fmt.Println()
+
+	// Entries show their date too when a period spans several days
+	layout := "15:04"
+	if result.Before.Start != result.Before.End || result.After.Start != result.After.End {
+		layout = "Mon 01-02 15:04"
+	}
+	line := func(mark string, entry storage.DailyLogEntry) string {
+		return mark + " " + entry.Timestamp.Format(layout) + "  " + column(styleType(entry.Type), 9) + entry.Title
+	}
+
+	if len(result.Added)+len(result.Removed)+len(result.Changed) == 0 {
+		fmt.Println("No entries added, removed, or changed.")
+	}
+	for _, entry := range result.Removed {
+		fmt.Println(style(styleRed, line("-", entry)))
+	}
+	for _, entry := range result.Added {
+		fmt.Println(style(styleGreen, line("+", entry)))
+	}
+	for _, change := range result.Changed {
+		changes := make([]string, len(change.Fields))
+		for i, field := range change.Fields {
+			changes[i] = describeFieldChange(change, field)
+		}
+		fmt.Println(style(styleYellow, line("~", change.After)) + style(styleDim, "  "+strings.Join(changes, ", ")))
+	}
+	if result.Unchanged > 0 {
+		fmt.Println(style(styleDim, fmt.Sprintf("  %d unchanged", result.Unchanged)))
+	}
+
+	fmt.Println()
+	fmt.Printf("Mood: %s → %s%s\n", formatAverage(result.StatusBefore), formatAverage(result.StatusAfter),
+		formatShift(result.StatusBefore, result.StatusAfter))
+	timeShift := ""
+	if delta := result.MinutesAfter - result.MinutesBefore; delta != 0 {
+		timeShift = " (" + formatMinutesDelta(delta) + ")"
+	}
+	fmt.Printf("Time tracked: %s → %s%s\n", formatMinutes(result.MinutesBefore), formatMinutes(result.MinutesAfter), timeShift)
+
+	if len(result.Allocation) > 0 {
+		fmt.Println()
+		fmt.Println(style(styleBold, fmt.Sprintf("%-24s %10s %10s %10s", "BY "+strings.ToUpper(result.GroupBy), "BEFORE", "AFTER", "CHANGE")))
+		for _, shift := range result.Allocation {
+			// Padded before styling, as escape codes would throw %10s off
+			delta := fmt.Sprintf("%10s", formatMinutesDelta(shift.Delta))
+			switch {
+			case shift.Delta > 0:
+				delta = style(styleGreen, delta)
+			case shift.Delta < 0:
+				delta = style(styleRed, delta)
+			}
+			fmt.Printf("%-24s %10s %10s %s\n", shift.Key, formatMinutes(shift.Before), formatMinutes(shift.After), delta)
+		}
+	}
+}
+
+// describeFieldChange renders how a field of a changed entry differs, e.g.
+// "status 6→8"
+func describeFieldChange(change storage.EntryChange, field string) string {
+	before, after := change.Before, change.After
+	switch field {
+	case "status":
+		return fmt.Sprintf("status %d→%d", before.Status, after.Status)
+	case "priority":
+		return fmt.Sprintf("priority %d→%d", before.Priority, after.Priority)
+	case "duration":
+		return "duration " + formatDuration(before.Duration) + "→" + formatDuration(after.Duration)
+	case "location":
+		return fmt.Sprintf("location %s→%s", orNone(before.Location), orNone(after.Location))
+	case "tags":
+		return "tags " + orNone(strings.Join(before.Tags, ",")) + "→" + orNone(strings.Join(after.Tags, ","))
+	default:
+		return field
+	}
+}
+
+// formatDuration renders an optional duration, "-" if it's unset
+func formatDuration(minutes *int) string {
+	if minutes == nil || *minutes == 0 {
+		return "-"
+	}
+	return formatMinutes(*minutes)
+}
+
+// orNone renders an empty value as "-"
+func orNone(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatAverage renders a status average, "-" without any ratings
+func formatAverage(average float64) string {
+	if average == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", average)
+}
+
+// formatShift renders the change between two averages, e.g. " (+0.7)",
+// or nothing if either is missing
+func formatShift(before, after float64) string {
+	if before == 0 || after == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%+.1f)", after-before)
+}
+
+// formatMinutesDelta renders a change in minutes, e.g. "+1h 30m"
+func formatMinutesDelta(delta int) string {
+	switch {
+	case delta > 0:
+		return "+" + formatMinutes(delta)
+	case delta < 0:
+		return "-" + formatMinutes(-delta)
+	default:
+		return "0m"
+	}
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/digest"
+	"dailylog/internal/notify"
+	"dailylog/internal/storage"
+)
+
+// digestCmd represents the digest command
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Email a weekly or monthly digest of the log",
+	Long: `Render the summary and statistics of a week or month as an HTML email
+(with a plain text alternative) and send it through the SMTP server in the
+config:
+
+  smtp:
+    host: smtp.example.com
+    port: 587            # 465 for implicit TLS
+    username: me@example.com
+    password: app-password
+    from: "Daily Log <me@example.com>"
+
+The SMTP settings can also come from DAILYLOG_SMTP_HOST, DAILYLOG_SMTP_PORT,
+DAILYLOG_SMTP_USERNAME, DAILYLOG_SMTP_PASSWORD, and DAILYLOG_SMTP_FROM.
+
+With --cron the digest covers the last complete week or month, and is sent
+only once per period and only when something was logged, so it can run from
+cron or a systemd timer as often as convenient. Sent periods are remembered in
+digest.state_path (~/.dailyctl/digest.json).
+
+Examples:
+  dailyctl digest --period week --email me@example.com
+  dailyctl digest --period month --date "last month" --email me@example.com
+  dailyctl digest --dry-run > digest.html
+  0 8 * * 1  dailyctl digest --cron --email me@example.com`,
+	Args: cobra.NoArgs,
+	RunE: runDigest,
+}
+
+func init() {
+	rootCmd.AddCommand(digestCmd)
+
+	digestCmd.Flags().String("period", digest.PeriodWeek, "Period to digest: week, month")
+	digestCmd.Flags().String("date", "", "A date in the period (YYYY-MM-DD or e.g. \"last week\", defaults to today)")
+	digestCmd.Flags().StringSlice("email", nil, "Address to send the digest to (repeatable)")
+	digestCmd.Flags().String("audience", storage.VisibilityPrivate, "Only include entries visible to: private, team, public")
+	digestCmd.Flags().Bool("cron", false, "Send the last complete period's digest once, if anything was logged")
+	digestCmd.Flags().Bool("dry-run", false, "Print the HTML email instead of sending it")
+
+	_ = digestCmd.RegisterFlagCompletionFunc("period", cobra.FixedCompletions(
+		[]string{digest.PeriodWeek, digest.PeriodMonth},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+
+	_ = viper.BindPFlag("digest.period", digestCmd.Flags().Lookup("period"))
+	_ = viper.BindPFlag("digest.email", digestCmd.Flags().Lookup("email"))
+	_ = viper.BindPFlag("digest.audience", digestCmd.Flags().Lookup("audience"))
+}
+
+// DigestResult is the outcome of the digest command
+type DigestResult struct {
+	Period     string   `json:"period" yaml:"period"`
+	Start      string   `json:"start" yaml:"start"`
+	End        string   `json:"end" yaml:"end"`
+	Entries    int      `json:"entries" yaml:"entries"`
+	Recipients []string `json:"recipients" yaml:"recipients"`
+	Sent       bool     `json:"sent" yaml:"sent"`
+	Skipped    string   `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	cron, _ := cmd.Flags().GetBool("cron")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	period := viper.GetString("digest.period")
+	recipients := viper.GetStringSlice("digest.email")
+	audience := viper.GetString("digest.audience")
+
+	if err := digest.ValidatePeriod(period); err != nil {
+		return err
+	}
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+	if cron && dateStr != "" {
+		return invalidArgf("--cron picks the period itself and cannot be combined with --date")
+	}
+
+	smtpConfig := notify.SMTPConfig{
+		Host:     viper.GetString("smtp.host"),
+		Port:     viper.GetInt("smtp.port"),
+		Username: viper.GetString("smtp.username"),
+		Password: viper.GetString("smtp.password"),
+		From:     viper.GetString("smtp.from"),
+	}
+	if !dryRun {
+		if len(recipients) == 0 {
+			return invalidArgf("no recipients (use --email or set digest.email)")
+		}
+		if err := smtpConfig.Validate(); err != nil {
+			return fmt.Errorf("%w (set smtp.host and smtp.from)", err)
+		}
+	}
+
+	date := time.Now()
+	if dateStr != "" {
+		parsed, err := datetime.ParseDate(dateStr, time.Now())
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last week\")", dateStr)
+		}
+		date = parsed
+	}
+	start, end := digest.Range(period, date)
+	if cron {
+		// The period before the current one is the last complete one
+		start, end = digest.Range(period, start.AddDate(0, 0, -1))
+	}
+
+	result := DigestResult{
+		Period:     period,
+		Start:      start.Format("2006-01-02"),
+		End:        end.Format("2006-01-02"),
+		Recipients: recipients,
+	}
+
+	statePath, err := digestStatePath()
+	if err != nil {
+		return err
+	}
+	sent, err := loadDigestState(statePath)
+	if err != nil {
+		return err
+	}
+	stateKey := period + ":" + result.Start
+	if cron && sent[stateKey] != "" {
+		result.Skipped = "already sent on " + sent[stateKey]
+		return outputDigestResult(result, cron)
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	summary, err := storageProvider.GenerateSummary(storage.SummaryRequest{
+		Type:     period,
+		Date:     start,
+		Audience: audience,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate summary: %w", err)
+	}
+
+	d := digest.New(period, start, end, summary.Summary, summary.Entries)
+	result.Entries = d.Totals.Count
+	html, err := d.HTML()
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Print(html)
+		return nil
+	}
+	if cron && result.Entries == 0 {
+		result.Skipped = "nothing was logged"
+		return outputDigestResult(result, cron)
+	}
+
+	text, err := d.Text()
+	if err != nil {
+		return err
+	}
+	if err := notify.SendEmail(smtpConfig, notify.Email{
+		To:      recipients,
+		Subject: d.Subject(),
+		Text:    text,
+		HTML:    html,
+	}); err != nil {
+		return err
+	}
+	result.Sent = true
+
+	sent[stateKey] = time.Now().Format("2006-01-02 15:04")
+	if err := saveDigestState(statePath, sent); err != nil {
+		return err
+	}
+	return outputDigestResult(result, cron)
+}
+
+func outputDigestResult(result DigestResult, cron bool) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	// Cron mails any output, so it stays quiet unless asked
+	if (cron && !viper.GetBool("verbose")) || quiet() {
+		return nil
+	}
+
+	if result.Sent {
+		fmt.Printf("✓ Sent the %s digest for %s to %s (%d entries)\n", result.Period, result.Start, joinRecipients(result.Recipients), result.Entries)
+	} else {
+		fmt.Printf("Skipped the %s digest for %s: %s\n", result.Period, result.Start, result.Skipped)
+	}
+	return nil
+}
+
+func joinRecipients(recipients []string) string {
+	if len(recipients) == 1 {
+		return recipients[0]
+	}
+	return fmt.Sprintf("%d recipients", len(recipients))
+}
+
+// digestStatePath returns where the periods already sent are remembered
+func digestStatePath() (string, error) {
+	if path := viper.GetString("digest.state_path"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate home directory: %w", err)
+	}
+	return filepath.Join(home, ".dailyctl", "digest.json"), nil
+}
+
+// loadDigestState reads when each period's digest was sent, keyed by period and start date
+func loadDigestState(path string) (map[string]string, error) {
+	sent := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read digest state: %w", err)
+	}
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, fmt.Errorf("failed to parse digest state %s: %w", path, err)
+	}
+	return sent, nil
+}
+
+func saveDigestState(path string, sent map[string]string) error {
+	data, err := json.MarshalIndent(sent, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to save digest state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save digest state: %w", err)
+	}
+	return nil
+}
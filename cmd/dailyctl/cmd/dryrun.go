@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// dryRunUsage is the help text of the --dry-run flag on commands that write
+const dryRunUsage = "Show what would be written, and the resulting commits, without saving anything"
+
+// duplicatesUsage is the help text of the --duplicates flag
+const duplicatesUsage = "What to do with entries that duplicate one already logged: allow, warn, skip (default: duplicates.mode, or allow)"
+
+// createWriteProvider creates the storage provider for a command that writes.
+// With --dry-run, writes are previewed by the returned DryRunProvider instead.
+func createWriteProvider(cmd *cobra.Command) (storage.DailyLogStorage, *providers.DryRunProvider, error) {
+	backend, err := createBackendProvider()
+	if err != nil {
+		return nil, nil, err
+	}
+	hooks, err := entryHooks()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var preview *providers.DryRunProvider
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		// Hook and tag in front of the preview so it shows what would be
+		// saved, or refused. After hooks act on what was written, so a
+		// preview skips them.
+		preview = providers.NewDryRunProvider(backend)
+		if preview.Messages, err = storage.NewCommitMessages(commitConfig()); err != nil {
+			return nil, nil, err
+		}
+		backend = preview
+		for i := range hooks {
+			hooks[i] = hooks[i].BeforeOnly()
+		}
+	}
+
+	linked, err := withIssueLinks(providers.NewHookedProvider(backend, hooks...))
+	if err != nil {
+		return nil, nil, err
+	}
+	checked, err := withDuplicateCheck(cmd, withSentiment(withWeather(linked)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return checked, preview, nil
+}
+
+// withDuplicateCheck looks for duplicates of new entries as set by
+// --duplicates or duplicates.mode, within duplicates.window of each other
+func withDuplicateCheck(cmd *cobra.Command, backend storage.DailyLogStorage) (storage.DailyLogStorage, error) {
+	mode := viper.GetString("duplicates.mode")
+	if flag := cmd.Flags().Lookup("duplicates"); flag != nil && flag.Changed {
+		mode = flag.Value.String()
+	}
+	if err := providers.ValidateDuplicateMode(mode); err != nil {
+		return nil, err
+	}
+	if mode == "" || mode == providers.DuplicatesAllow {
+		return backend, nil
+	}
+
+	window := storage.DefaultDuplicateWindow
+	if viper.IsSet("duplicates.window") {
+		window = viper.GetDuration("duplicates.window")
+	}
+
+	checker := providers.NewDuplicateProvider(backend, mode == providers.DuplicatesSkip, window)
+	checker.OnDuplicate = func(req storage.CreateLogEntryRequest, existing storage.DailyLogEntry) {
+		fmt.Fprintf(os.Stderr, "Warning: %q looks like a duplicate of entry %s logged at %s\n",
+			req.Title, existing.ID, existing.Timestamp.Format("15:04"))
+	}
+	return checker, nil
+}
+
+// reportDryRun lists the commits a dry run would have made. It writes to
+// stderr so JSON and YAML output on stdout stay parseable.
+func reportDryRun(preview *providers.DryRunProvider) {
+	if preview == nil {
+		return
+	}
+
+	writes := preview.Writes()
+	if len(writes) == 0 {
+		fmt.Fprintln(os.Stderr, "Dry run: nothing would be saved.")
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Dry run: nothing was saved. Would commit:")
+	for _, write := range writes {
+		if write.Delete {
+			fmt.Fprintf(os.Stderr, "  %s\n", write.CommitMessage)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %s (%d entries)\n", write.CommitMessage, write.Entries)
+	}
+}
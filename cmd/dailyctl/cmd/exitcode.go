@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// Exit codes, so scripts can tell why a command failed
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitNotFound    = 3
+	ExitValidation  = 4
+	ExitStorage     = 5
+	ExitAuth        = 6
+	ExitRateLimited = 7
+)
+
+// exitCodeNames name each exit code in JSON and YAML error reports
+var exitCodeNames = map[int]string{
+	ExitError:       "error",
+	ExitNotFound:    "not_found",
+	ExitValidation:  "validation",
+	ExitStorage:     "storage_unavailable",
+	ExitAuth:        "unauthorized",
+	ExitRateLimited: "rate_limited",
+}
+
+// errNotConfigured marks missing GitHub credentials, which exit like rejected ones
+var errNotConfigured = errors.New("not configured")
+
+// ExitCode maps an error returned by Execute to the process exit code
+func ExitCode(err error) int {
+	var notFound storage.NotFoundError
+	var invalid storage.ValidationError
+	var usage usageError
+	var storageErr storage.StorageError
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &notFound):
+		return ExitNotFound
+	case errors.As(err, &invalid), errors.As(err, &usage):
+		return ExitValidation
+	case providers.IsRateLimited(err):
+		return ExitRateLimited
+	case errors.Is(err, errNotConfigured), providers.IsUnauthorized(err):
+		return ExitAuth
+	case errors.As(err, &storageErr):
+		return ExitStorage
+	default:
+		return ExitError
+	}
+}
+
+// quiet reports whether --quiet asked for only IDs in table output
+func quiet() bool {
+	return viper.GetBool("output.quiet")
+}
+
+// printIDs prints one entry ID per line, the --quiet form of entry listings
+func printIDs(entries []storage.DailyLogEntry) {
+	for _, entry := range entries {
+		fmt.Println(entry.ID)
+	}
+}
+
+// usageError is a problem with a command's arguments or flags
+type usageError struct{ error }
+
+func (e usageError) Unwrap() error { return e.error }
+
+// invalidArgf reports invalid arguments or flags, which exit with ExitValidation
+func invalidArgf(format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,523 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/exporters"
+	"dailylog/internal/storage"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export log entries to other formats",
+	Long: `Export log entries to formats understood by other tools.
+
+Examples:
+  dailyctl export ics --date-start 2025-09-01 --date-end 2025-09-30 --file september.ics
+  dailyctl export jsonl --all | jq 'select(.type == "activity")'
+  dailyctl export jsonl --date-start 2025-01-01 --file 2025.jsonl
+  dailyctl export jsonl --all --anonymize --file dataset.jsonl
+  dailyctl export caldav --date-start 2025-09-01
+  dailyctl export anki --file lessons.txt
+
+With --anonymize (ics and jsonl), entries can be shared with a coach or for
+research: IDs, tags, locations, and metadata values are replaced by
+pseudonyms, the same for the same value so statistics still group, and names
+or other sensitive terms listed in anonymize.terms or with --anonymize-term
+are replaced wherever they appear in titles and descriptions. Dates, times,
+types, status, priority, and durations are kept. --anonymize=strip also drops
+titles, descriptions, and locations. Pseudonyms differ between exports unless
+anonymize.salt is set.
+
+  anonymize:
+    terms: [Alice, Bob, Acme]`,
+}
+
+var exportICSCmd = &cobra.Command{
+	Use:   "ics",
+	Short: "Export timed entries as an iCalendar (.ics) file",
+	Long: `Export entries that have a duration as iCalendar VEVENTs, so logged work
+can be overlaid on a calendar. Entries without a duration are skipped.`,
+	RunE: runExportICS,
+}
+
+var exportJSONLCmd = &cobra.Command{
+	Use:   "jsonl",
+	Short: "Stream entries as JSON Lines",
+	Long: `Stream entries as JSON Lines: one JSON object per entry, with the entry's
+date added as a "date" field. Days are fetched and written one at a time, so
+the output can be piped into jq, DuckDB, or notebooks without loading the
+whole archive into memory. Progress is reported on stderr.`,
+	RunE: runExportJSONL,
+}
+
+var exportCalDAVCmd = &cobra.Command{
+	Use:   "caldav",
+	Short: "Publish entries as journal items on a CalDAV server",
+	Long: `Publish entries as VJOURNAL items to a CalDAV calendar collection
+(Nextcloud, Fastmail, Radicale, ...), so they show up in calendar and journal
+clients. Each entry becomes one item; its type and tags become categories and
+its visibility the item's class.
+
+Running it again syncs the range: changed entries are uploaded again, unchanged
+ones skipped, and items for entries deleted from the log removed. What was
+published is remembered in caldav.state_path (~/.dailyctl/caldav.json).
+
+Configure the collection in ~/.dailyctl.yaml or the environment:
+  caldav.url       DAILYLOG_CALDAV_URL       e.g. https://cloud.example.com/remote.php/dav/calendars/me/journal/
+  caldav.username  DAILYLOG_CALDAV_USERNAME
+  caldav.password  DAILYLOG_CALDAV_PASSWORD  an app password
+
+Examples:
+  dailyctl export caldav --date-start 2025-09-01 --dry-run
+  dailyctl export caldav --date-start 2025-09-01 --audience team`,
+	RunE: runExportCalDAV,
+}
+
+var exportAnkiCmd = &cobra.Command{
+	Use:   "anki",
+	Short: "Turn lessons learned into an Anki flashcard deck",
+	Long: `Turn lessons learned into Anki flashcards: lesson entries ('dailyctl log
+lesson'), and entries of any type tagged "lesson" or with a tag aliased to it.
+The title is the front of a card, and the description and date its back.
+Other tags carry over, with / between levels becoming ::.
+
+Without --push, a text file is written for Anki's File > Import; each card is
+keyed by its entry's ID, so importing again updates cards rather than
+duplicating them. With --push, the cards are added straight to a running Anki
+through the AnkiConnect add-on, skipping those already in the deck.
+
+  anki:
+    deck: Daily Log Lessons
+    connect_url: http://127.0.0.1:8765
+
+Examples:
+  dailyctl export anki --file lessons.txt
+  dailyctl export anki --date-start 2025-09-01 --deck "Work::Lessons" --file september.txt
+  dailyctl export anki --push`,
+	RunE: runExportAnki,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.AddCommand(exportICSCmd)
+	exportCmd.AddCommand(exportJSONLCmd)
+	exportCmd.AddCommand(exportCalDAVCmd)
+	exportCmd.AddCommand(exportAnkiCmd)
+
+	exportICSCmd.Flags().String("date-start", "", "Start date for export (YYYY-MM-DD, required)")
+	exportICSCmd.Flags().String("date-end", "", "End date for export (YYYY-MM-DD, defaults to today)")
+	exportICSCmd.Flags().StringP("file", "f", "", "Write to file instead of stdout")
+	exportICSCmd.Flags().String("audience", storage.VisibilityPrivate, "Only export entries visible to: private, team, public")
+	addAnonymizeFlags(exportICSCmd)
+	_ = exportICSCmd.MarkFlagRequired("date-start")
+
+	exportJSONLCmd.Flags().Bool("all", false, "Export every day in the archive")
+	exportJSONLCmd.Flags().String("date-start", "", "Start date for export (YYYY-MM-DD)")
+	exportJSONLCmd.Flags().String("date-end", "", "End date for export (YYYY-MM-DD, defaults to today)")
+	exportJSONLCmd.Flags().StringP("file", "f", "", "Write to file instead of stdout")
+	exportJSONLCmd.Flags().Bool("progress", true, "Report progress on stderr")
+	exportJSONLCmd.Flags().String("audience", storage.VisibilityPrivate, "Only export entries visible to: private, team, public")
+	addAnonymizeFlags(exportJSONLCmd)
+	exportJSONLCmd.MarkFlagsMutuallyExclusive("all", "date-start")
+	exportJSONLCmd.MarkFlagsMutuallyExclusive("all", "date-end")
+
+	exportCalDAVCmd.Flags().String("date-start", "", "Start date to publish (YYYY-MM-DD, required)")
+	exportCalDAVCmd.Flags().String("date-end", "", "End date to publish (YYYY-MM-DD, defaults to today)")
+	exportCalDAVCmd.Flags().String("audience", storage.VisibilityPrivate, "Only publish entries visible to: private, team, public")
+	exportCalDAVCmd.Flags().Bool("dry-run", false, "Count what would be uploaded and deleted without changing the server")
+	_ = exportCalDAVCmd.MarkFlagRequired("date-start")
+
+	exportAnkiCmd.Flags().String("date-start", "", "Start date for export (YYYY-MM-DD, defaults to the whole archive)")
+	exportAnkiCmd.Flags().String("date-end", "", "End date for export (YYYY-MM-DD, defaults to today)")
+	exportAnkiCmd.Flags().StringP("file", "f", "", "Write to file instead of stdout")
+	exportAnkiCmd.Flags().String("deck", exporters.DefaultAnkiDeck, "Deck to add the cards to")
+	exportAnkiCmd.Flags().Bool("push", false, "Add the cards to a running Anki through AnkiConnect instead of writing a file")
+	exportAnkiCmd.Flags().String("ankiconnect-url", exporters.DefaultAnkiConnectURL, "Address of the AnkiConnect add-on")
+	exportAnkiCmd.Flags().String("audience", storage.VisibilityPrivate, "Only export entries visible to: private, team, public")
+	exportAnkiCmd.MarkFlagsMutuallyExclusive("push", "file")
+	_ = viper.BindPFlag("anki.deck", exportAnkiCmd.Flags().Lookup("deck"))
+	_ = viper.BindPFlag("anki.connect_url", exportAnkiCmd.Flags().Lookup("ankiconnect-url"))
+}
+
+// AnkiExportResult is the outcome of pushing lessons to Anki
+type AnkiExportResult struct {
+	URL                      string `json:"url" yaml:"url"`
+	Deck                     string `json:"deck" yaml:"deck"`
+	exporters.AnkiPushResult `yaml:",inline"`
+}
+
+// CalDAVExportResult is the outcome of publishing entries to a CalDAV server
+type CalDAVExportResult struct {
+	URL                        string `json:"url" yaml:"url"`
+	exporters.CalDAVSyncResult `yaml:",inline"`
+	DryRun                     bool `json:"dry_run" yaml:"dry_run"`
+}
+
+func runExportCalDAV(cmd *cobra.Command, args []string) error {
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	audience, _ := cmd.Flags().GetString("audience")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+
+	startDate, err := time.Parse("2006-01-02", dateStartStr)
+	if err != nil {
+		return invalidArgf("invalid start date format: %s (use YYYY-MM-DD)", dateStartStr)
+	}
+	endDate := time.Now()
+	if dateEndStr != "" {
+		endDate, err = time.Parse("2006-01-02", dateEndStr)
+		if err != nil {
+			return invalidArgf("invalid end date format: %s (use YYYY-MM-DD)", dateEndStr)
+		}
+	}
+	if startDate.After(endDate) {
+		return fmt.Errorf("start date cannot be after end date")
+	}
+
+	client, err := exporters.NewCalDAVClient(
+		viper.GetString("caldav.url"),
+		viper.GetString("caldav.username"),
+		viper.GetString("caldav.password"),
+	)
+	if err != nil {
+		return fmt.Errorf("%w (set caldav.url or DAILYLOG_CALDAV_URL)", err)
+	}
+
+	statePath := viper.GetString("caldav.state_path")
+	if statePath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		statePath = filepath.Join(home, ".dailyctl", "caldav.json")
+	}
+	state, err := exporters.LoadCalDAVState(statePath, client.URL())
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	days, err := storageProvider.GetDateRange(startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+
+	policy := visibilityPolicy()
+	for i := range days {
+		days[i] = policy.FilterDay(days[i], audience)
+	}
+
+	synced, syncErr := exporters.SyncCalDAV(client, state, days, startDate, endDate, dryRun)
+	// Keep track of what was published even when the sync stopped part way
+	if !dryRun {
+		if err := state.Save(statePath); err != nil {
+			return err
+		}
+	}
+	if syncErr != nil {
+		return syncErr
+	}
+
+	result := CalDAVExportResult{URL: client.URL(), CalDAVSyncResult: synced, DryRun: dryRun}
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	verb := "Published"
+	if dryRun {
+		verb = "Would publish"
+	}
+	fmt.Printf("%s %d entries to %s (%d unchanged, %d deleted)\n", verb, synced.Uploaded, client.URL(), synced.Unchanged, synced.Deleted)
+	return nil
+}
+
+func runExportICS(cmd *cobra.Command, args []string) error {
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	file, _ := cmd.Flags().GetString("file")
+	audience, _ := cmd.Flags().GetString("audience")
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+	anonymizer, err := exportAnonymizer(cmd)
+	if err != nil {
+		return err
+	}
+
+	startDate, err := time.Parse("2006-01-02", dateStartStr)
+	if err != nil {
+		return invalidArgf("invalid start date format: %s (use YYYY-MM-DD)", dateStartStr)
+	}
+	endDate := time.Now()
+	if dateEndStr != "" {
+		endDate, err = time.Parse("2006-01-02", dateEndStr)
+		if err != nil {
+			return invalidArgf("invalid end date format: %s (use YYYY-MM-DD)", dateEndStr)
+		}
+	}
+	if startDate.After(endDate) {
+		return fmt.Errorf("start date cannot be after end date")
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	days, err := storageProvider.GetDateRange(startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+
+	policy := visibilityPolicy()
+	for i := range days {
+		days[i] = policy.FilterDay(days[i], audience)
+		if anonymizer != nil {
+			days[i] = anonymizer.Day(days[i])
+		}
+	}
+
+	out, closeOut, err := openExportOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	count, err := exporters.WriteICS(out, days)
+	if err != nil {
+		return err
+	}
+
+	if file != "" {
+		fmt.Printf("✓ Exported %d events to %s\n", count, file)
+	}
+	return nil
+}
+
+func runExportJSONL(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	file, _ := cmd.Flags().GetString("file")
+	showProgress, _ := cmd.Flags().GetBool("progress")
+	audience, _ := cmd.Flags().GetString("audience")
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+	anonymizer, err := exportAnonymizer(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !all && dateStartStr == "" {
+		return invalidArgf("either --all or --date-start must be provided")
+	}
+
+	// A zero start and end export the whole archive
+	var startDate, endDate time.Time
+	if !all {
+		startDate, err = time.Parse("2006-01-02", dateStartStr)
+		if err != nil {
+			return invalidArgf("invalid start date format: %s (use YYYY-MM-DD)", dateStartStr)
+		}
+		endDate = time.Now()
+		if dateEndStr != "" {
+			endDate, err = time.Parse("2006-01-02", dateEndStr)
+			if err != nil {
+				return invalidArgf("invalid end date format: %s (use YYYY-MM-DD)", dateEndStr)
+			}
+		}
+		if startDate.After(endDate) {
+			return fmt.Errorf("start date cannot be after end date")
+		}
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	days, err := storageProvider.ListDays(startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to list days: %w", err)
+	}
+
+	out, closeOut, err := openExportOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	opts := exporters.JSONLOptions{
+		Policy:     visibilityPolicy(),
+		Audience:   audience,
+		Anonymizer: anonymizer,
+	}
+	if showProgress {
+		opts.Progress = func(done, total int, day time.Time, entries int) {
+			fmt.Fprintf(os.Stderr, "\r[%d/%d] %s (%d entries)", done, total, day.Format("2006-01-02"), entries)
+		}
+	}
+
+	count, err := exporters.WriteJSONL(out, storageProvider, days, opts)
+	if showProgress && len(days) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if err != nil {
+		return fmt.Errorf("export failed after %d entries: %w", count, err)
+	}
+
+	if showProgress {
+		fmt.Fprintf(os.Stderr, "✓ Exported %d entries from %d days\n", count, len(days))
+	}
+	return nil
+}
+
+// addAnonymizeFlags adds the flags that anonymize an export
+func addAnonymizeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("anonymize", "", "Anonymize entries for sharing: hash, strip")
+	cmd.Flags().Lookup("anonymize").NoOptDefVal = exporters.AnonymizeHash
+	cmd.Flags().StringSlice("anonymize-term", nil, "Name or other sensitive term to hash in free text (repeatable, adds to anonymize.terms)")
+	_ = cmd.RegisterFlagCompletionFunc("anonymize", cobra.FixedCompletions(
+		[]string{exporters.AnonymizeHash, exporters.AnonymizeStrip},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+}
+
+// exportAnonymizer returns the anonymizer asked for with --anonymize, or nil
+func exportAnonymizer(cmd *cobra.Command) (*exporters.Anonymizer, error) {
+	mode, _ := cmd.Flags().GetString("anonymize")
+	terms, _ := cmd.Flags().GetStringSlice("anonymize-term")
+	if mode == "" {
+		if len(terms) > 0 {
+			return nil, invalidArgf("--anonymize-term requires --anonymize")
+		}
+		return nil, nil
+	}
+	return exporters.NewAnonymizer(exporters.AnonymizeOptions{
+		Mode:  mode,
+		Terms: append(viper.GetStringSlice("anonymize.terms"), terms...),
+		Salt:  viper.GetString("anonymize.salt"),
+	})
+}
+
+func runExportAnki(cmd *cobra.Command, args []string) error {
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	file, _ := cmd.Flags().GetString("file")
+	push, _ := cmd.Flags().GetBool("push")
+	audience, _ := cmd.Flags().GetString("audience")
+	deck := viper.GetString("anki.deck")
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+	if deck == "" {
+		return invalidArgf("--deck cannot be empty")
+	}
+
+	// A zero start and end export the whole archive
+	var startDate, endDate time.Time
+	var err error
+	if dateStartStr != "" {
+		startDate, err = time.Parse("2006-01-02", dateStartStr)
+		if err != nil {
+			return invalidArgf("invalid start date format: %s (use YYYY-MM-DD)", dateStartStr)
+		}
+		endDate = time.Now()
+	}
+	if dateEndStr != "" {
+		endDate, err = time.Parse("2006-01-02", dateEndStr)
+		if err != nil {
+			return invalidArgf("invalid end date format: %s (use YYYY-MM-DD)", dateEndStr)
+		}
+	}
+	if !startDate.IsZero() && startDate.After(endDate) {
+		return fmt.Errorf("start date cannot be after end date")
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	dates, err := storageProvider.ListDays(startDate, endDate)
+	if err != nil {
+		return fmt.Errorf("failed to list days: %w", err)
+	}
+	policy := visibilityPolicy()
+	days := make([]storage.DayLog, 0, len(dates))
+	for _, date := range dates {
+		day, err := storageProvider.GetDay(date)
+		if err != nil {
+			return fmt.Errorf("failed to get %s: %w", date.Format("2006-01-02"), err)
+		}
+		days = append(days, policy.FilterDay(*day, audience))
+	}
+	cards := exporters.LessonCards(days, storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases")))
+
+	if push {
+		client := exporters.NewAnkiConnectClient(viper.GetString("anki.connect_url"))
+		pushed, err := client.Push(deck, cards)
+		if err != nil {
+			return err
+		}
+		result := AnkiExportResult{URL: client.URL(), Deck: deck, AnkiPushResult: pushed}
+		if ok, err := outputStructured(result); ok {
+			return err
+		}
+		fmt.Printf("Added %d lessons to %q (%d already there)\n", pushed.Added, deck, pushed.Skipped)
+		return nil
+	}
+
+	out, closeOut, err := openExportOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+
+	count, err := exporters.WriteAnkiText(out, deck, cards)
+	if err != nil {
+		return err
+	}
+
+	if file != "" {
+		fmt.Printf("✓ Exported %d lessons to %s\n", count, file)
+	}
+	return nil
+}
+
+// openExportOutput returns the file to write to, or stdout when no file is given
+func openExportOutput(file string) (io.Writer, func(), error) {
+	if file == "" {
+		startPager()
+		return os.Stdout, func() {}, nil
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %s: %w", file, err)
+	}
+	return f, func() { _ = f.Close() }, nil
+}
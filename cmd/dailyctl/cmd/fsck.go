@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// fsckCmd represents the fsck command
+var fsckCmd = &cobra.Command{
+	Use:   "fsck",
+	Short: "Check the stored day files for problems, and repair them",
+	Long: `Check every day file in the repository for problems:
+
+  malformed       the file can't be read as a day log
+  wrong_path      the file isn't where github.layout and its format put it
+  duplicate_day   the same day is stored in more than one file
+  duplicate_id    an entry ID is missing or used more than once
+  date            the date inside doesn't match the file name
+  total_entries   total_entries doesn't count the entries
+  status_average  status_average doesn't match the entries' statuses
+
+With --fix, the problems are repaired in batches of days per commit: a day
+stored twice is merged into one file, files are moved, totals recalculated,
+repeated copies of an entry dropped, and reused IDs replaced with new ones.
+Malformed files are left for you to fix by hand or with restore --history.
+If a run is interrupted, run it again to repair the rest.
+
+fsck exits with an error while problems remain.
+
+Examples:
+  dailyctl fsck
+  dailyctl fsck --fix
+  dailyctl fsck -o json`,
+	Args: cobra.NoArgs,
+	RunE: runFsck,
+}
+
+func init() {
+	rootCmd.AddCommand(fsckCmd)
+
+	fsckCmd.Flags().Bool("fix", false, "Repair the problems found")
+	fsckCmd.Flags().Int("batch-size", providers.DefaultFsckBatchSize, "Days to repair per commit")
+}
+
+func runFsck(cmd *cobra.Command, args []string) error {
+	fix, _ := cmd.Flags().GetBool("fix")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	if batchSize < 1 {
+		return invalidArgf("--batch-size must be at least 1")
+	}
+
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	result, err := primary.Fsck(fix, batchSize, func(repaired, total int) {
+		if !quiet() {
+			fmt.Fprintf(os.Stderr, "Repaired %d of %d days\n", repaired, total)
+		}
+	})
+	if err != nil {
+		if result.Commits > 0 {
+			return fmt.Errorf("repaired days in %d commits before failing: %w", result.Commits, err)
+		}
+		return err
+	}
+
+	// Problems left are findings, not misuse
+	cmd.SilenceUsage = true
+	if ok, err := outputStructured(result); ok {
+		if err != nil {
+			return err
+		}
+		return fsckRemaining(result)
+	}
+
+	if len(result.Problems) == 0 {
+		fmt.Printf("✓ Checked %d files for %d days: no problems found\n", result.Files, result.Days)
+		return nil
+	}
+	fmt.Printf("Checked %d files for %d days:\n", result.Files, result.Days)
+	lastPath := ""
+	for _, problem := range result.Problems {
+		if problem.Path != lastPath {
+			fmt.Println(style(styleBold, problem.Path))
+			lastPath = problem.Path
+		}
+		mark := style(styleRed, "✗")
+		if problem.Fixed {
+			mark = style(styleGreen, "✓")
+		}
+		fmt.Printf("  %s %s %s\n", mark, column(style(styleYellow, problem.Kind), 15), problem.Message)
+	}
+	if result.Commits > 0 {
+		fmt.Printf("✓ Repaired the problems in %d commits\n", result.Commits)
+	}
+	return fsckRemaining(result)
+}
+
+// fsckRemaining reports the problems left unrepaired as an error
+func fsckRemaining(result providers.FsckResult) error {
+	fixable, malformed := 0, 0
+	for _, problem := range result.Problems {
+		switch {
+		case problem.Fixed:
+		case problem.Kind == storage.ProblemMalformed:
+			malformed++
+		default:
+			fixable++
+		}
+	}
+	switch {
+	case fixable > 0:
+		return fmt.Errorf("found %d problems; run dailyctl fsck --fix to repair them", fixable+malformed)
+	case malformed > 0:
+		return fmt.Errorf("found %d malformed files to repair by hand", malformed)
+	default:
+		return nil
+	}
+}
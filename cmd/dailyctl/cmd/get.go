@@ -6,24 +6,39 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
+	"dailylog/internal/datetime"
 	"dailylog/internal/storage"
 )
 
 // getCmd represents the get command
 var getCmd = &cobra.Command{
-	Use:   "get",
+	Use:   "get [date]",
 	Short: "Get log entries",
 	Long: `Get log entries for specific dates or date ranges.
 
+Dates can be written as YYYY-MM-DD or in natural language, such as
+"last friday", "3 days ago", "last week", or "end of last month".
+
 Examples:
   dailyctl get today
   dailyctl get yesterday  
   dailyctl get 2025-09-29
+  dailyctl get "last friday"
+  dailyctl get "last month"
   dailyctl get --date-start 2025-09-01 --date-end 2025-09-30
+  dailyctl get --date-start "3 weeks ago"
   dailyctl get week
-  dailyctl get month`,
+  dailyctl get month
+  dailyctl get today --format raycast
+  dailyctl get today --watch
+
+With --watch, the entries are shown again whenever they change, until
+interrupted, for keeping the day in view on a second monitor. A single day
+stored on GitHub is checked every --interval with a conditional request,
+which is cheap and doesn't count against the rate limit; ranges and other
+storage are redrawn every --interval.`,
+	RunE: runGet,
 }
 
 var getTodayCmd = &cobra.Command{
@@ -51,12 +66,12 @@ var getMonthCmd = &cobra.Command{
 }
 
 var getDateCmd = &cobra.Command{
-	Use:   "date [YYYY-MM-DD]",
+	Use:   "date [YYYY-MM-DD|expression]",
 	Short: "Get entries for a specific date",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		date := args[0]
-		return getEntriesForDate(date)
+		return getEntriesForDate(cmd, date)
 	},
 }
 
@@ -71,12 +86,64 @@ func init() {
 	getCmd.AddCommand(getDateCmd)
 
 	// Add flags
-	getCmd.PersistentFlags().String("date-start", "", "Start date for range query (YYYY-MM-DD)")
-	getCmd.PersistentFlags().String("date-end", "", "End date for range query (YYYY-MM-DD)")
+	getCmd.PersistentFlags().String("date-start", "", "Start date for range query (YYYY-MM-DD or e.g. \"3 weeks ago\")")
+	getCmd.PersistentFlags().String("date-end", "", "End date for range query (YYYY-MM-DD or e.g. \"end of last month\", defaults to today)")
 	getCmd.PersistentFlags().String("type", "", "Filter by entry type")
 	getCmd.PersistentFlags().StringSlice("tags", []string{}, "Filter by tags")
 	getCmd.PersistentFlags().Int("limit", 0, "Maximum number of entries to return")
+	getCmd.PersistentFlags().Bool("pinned", false, "Only show pinned entries")
+	getCmd.PersistentFlags().Bool("unreviewed", false, "Only show entries not yet marked reviewed")
+	getCmd.PersistentFlags().String("author", "", "Only show entries by this author")
 	getCmd.PersistentFlags().Bool("stats", false, "Include summary statistics")
+	getCmd.PersistentFlags().String("sort", "", sortUsage)
+	getCmd.PersistentFlags().String("format", "", launcherFormatUsage)
+	getCmd.PersistentFlags().Bool("watch", false, "Keep showing the entries, redrawn when they change")
+	getCmd.PersistentFlags().Duration("interval", 30*time.Second, "How often --watch checks for changes")
+
+	_ = getCmd.RegisterFlagCompletionFunc("type", completeEntryTypes)
+	_ = getCmd.RegisterFlagCompletionFunc("tags", completeTags)
+}
+
+// runGet gets the entries for a date expression or a --date-start/--date-end range
+func runGet(cmd *cobra.Command, args []string) error {
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	now := time.Now()
+
+	if len(args) > 0 {
+		expression := strings.Join(args, " ")
+		start, end, err := datetime.ParseRange(expression, now)
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", expression)
+		}
+		if start.Equal(end) {
+			return getEntries(cmd, start, nil, nil)
+		}
+		return getEntries(cmd, time.Time{}, &start, &end)
+	}
+
+	if dateStartStr == "" {
+		if dateEndStr != "" {
+			return fmt.Errorf("--date-end requires --date-start")
+		}
+		return cmd.Help()
+	}
+
+	start, err := datetime.ParseDate(dateStartStr, now)
+	if err != nil {
+		return invalidArgf("invalid start date: %s (use YYYY-MM-DD or e.g. \"3 weeks ago\")", dateStartStr)
+	}
+	end := datetime.StartOfDay(now)
+	if dateEndStr != "" {
+		end, err = datetime.ParseDate(dateEndStr, now)
+		if err != nil {
+			return invalidArgf("invalid end date: %s (use YYYY-MM-DD or e.g. \"end of last month\")", dateEndStr)
+		}
+	}
+	if start.After(end) {
+		return fmt.Errorf("start date cannot be after end date")
+	}
+	return getEntries(cmd, time.Time{}, &start, &end)
 }
 
 func runGetEntries(period string) func(cmd *cobra.Command, args []string) error {
@@ -112,30 +179,69 @@ func runGetEntries(period string) func(cmd *cobra.Command, args []string) error
 	}
 }
 
-func getEntriesForDate(dateStr string) error {
-	targetDate, err := time.Parse("2006-01-02", dateStr)
+func getEntriesForDate(cmd *cobra.Command, dateStr string) error {
+	targetDate, err := datetime.ParseDate(dateStr, time.Now())
 	if err != nil {
-		return fmt.Errorf("invalid date format: %s (use YYYY-MM-DD)", dateStr)
+		return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
 	}
 
-	return getEntries(nil, targetDate, nil, nil)
+	return getEntries(cmd, targetDate, nil, nil)
 }
 
 func getEntries(cmd *cobra.Command, targetDate time.Time, dateStart, dateEnd *time.Time) error {
+	order, err := entrySortOrder(cmd)
+	if err != nil {
+		return err
+	}
+	format, err := launcherFormat(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Create storage provider
 	storageProvider, err := createStorageProvider()
 	if err != nil {
-		return fmt.Errorf("failed to create storage provider: %v", err)
+		return fmt.Errorf("failed to create storage provider: %w", err)
 	}
 
+	show := func(watching bool) error {
+		return showEntries(cmd, storageProvider, targetDate, dateStart, dateEnd, order, format, watching)
+	}
+	if cmd == nil {
+		return show(false)
+	}
+	if watch, _ := cmd.Flags().GetBool("watch"); watch {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		if interval <= 0 {
+			return invalidArgf("--interval must be positive")
+		}
+		var changed func() (bool, error)
+		if dateStart == nil {
+			changed = dayChangeCheck(targetDate)
+		}
+		return watchEntries(interval, changed, func() error { return show(true) })
+	}
+	return show(false)
+}
+
+// showEntries fetches and prints entries, redrawing the screen rather than
+// paging when watching
+func showEntries(cmd *cobra.Command, storageProvider storage.DailyLogStorage, targetDate time.Time, dateStart, dateEnd *time.Time, order storage.SortOrder, format string, watching bool) error {
 	var entries []storage.DailyLogEntry
 	var period string
+	var stats *entryStats
+
+	showStats := false
+	if cmd != nil {
+		showStats, _ = cmd.Flags().GetBool("stats")
+	}
 
 	if dateStart != nil && dateEnd != nil {
 		// Get entries for date range
 		searchReq := storage.LogSearchRequest{
 			DateStart: dateStart,
 			DateEnd:   dateEnd,
+			Sort:      order.String(),
 		}
 
 		if cmd != nil {
@@ -143,54 +249,101 @@ func getEntries(cmd *cobra.Command, targetDate time.Time, dateStart, dateEnd *ti
 			entryType, _ := cmd.Flags().GetString("type")
 			tags, _ := cmd.Flags().GetStringSlice("tags")
 			limit, _ := cmd.Flags().GetInt("limit")
+			pinned, _ := cmd.Flags().GetBool("pinned")
+			unreviewed, _ := cmd.Flags().GetBool("unreviewed")
+			author, _ := cmd.Flags().GetString("author")
 
 			searchReq.Type = entryType
 			searchReq.Tags = tags
 			searchReq.Limit = limit
+			searchReq.Pinned = pinned
+			searchReq.Unreviewed = unreviewed
+			searchReq.Author = author
+		}
+		if showStats {
+			searchReq.Aggregations = []string{storage.GroupByType, storage.GroupByTag}
 		}
 
 		searchResult, err := storageProvider.SearchLogs(searchReq)
 		if err != nil {
-			return fmt.Errorf("failed to search logs: %v", err)
+			return fmt.Errorf("failed to search logs: %w", err)
 		}
 
 		entries = searchResult.Entries
 		period = fmt.Sprintf("%s to %s", dateStart.Format("2006-01-02"), dateEnd.Format("2006-01-02"))
+		if showStats {
+			stats = statsFromSearch(searchResult)
+		}
 
 	} else {
 		// Get entries for specific date
 		dayLog, err := storageProvider.GetDay(targetDate)
 		if err != nil {
-			return fmt.Errorf("failed to get day: %v", err)
+			return fmt.Errorf("failed to get day: %w", err)
 		}
 
 		entries = dayLog.Entries
+		if cmd != nil {
+			if pinned, _ := cmd.Flags().GetBool("pinned"); pinned {
+				entries = storage.PinnedEntries(entries)
+			}
+			if unreviewed, _ := cmd.Flags().GetBool("unreviewed"); unreviewed {
+				entries = storage.UnreviewedEntries(entries)
+			}
+			if author, _ := cmd.Flags().GetString("author"); author != "" {
+				entries = storage.AuthorEntries(entries, author)
+			}
+		}
+		storage.SortEntries(entries, order)
 		period = targetDate.Format("2006-01-02")
+		if showStats {
+			stats = calculateStats(entries)
+		}
 	}
 
 	// Output results
-	outputFormat := viper.GetString("output.format")
-	switch outputFormat {
-	case "json":
-		return outputJSON(map[string]interface{}{
-			"entries":     entries,
-			"total_count": len(entries),
-			"period":      period,
-		})
-	case "yaml":
-		return outputYAML(map[string]interface{}{
-			"entries":     entries,
-			"total_count": len(entries),
-			"period":      period,
-		})
-	default:
-		return outputEntriesTable(entries, period, cmd)
+	result := map[string]interface{}{
+		"entries":     entries,
+		"total_count": len(entries),
+		"period":      period,
 	}
+	if stats != nil {
+		result["stats"] = stats
+	}
+
+	if watching {
+		clearScreen()
+	}
+	if format != "" {
+		return outputLauncher(format, entries)
+	}
+	if !watching {
+		startPager()
+	}
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	return outputEntriesTable(entries, period, stats)
 }
 
-func outputEntriesTable(entries []storage.DailyLogEntry, period string, cmd *cobra.Command) error {
-	fmt.Printf("Daily Log Entries - %s\n", period)
-	fmt.Printf("=====================%s\n", strings.Repeat("=", len(period)))
+func outputEntriesTable(entries []storage.DailyLogEntry, period string, stats *entryStats) error {
+	if quiet() {
+		printIDs(entries)
+		return nil
+	}
+	if ok, err := printOutputTemplate("get", map[string]any{
+		"Period":  period,
+		"Entries": templateEntries(entries),
+		"Total":   len(entries),
+		"Stats":   stats,
+	}); ok {
+		return err
+	}
+
+	title := fmt.Sprintf("Daily Log Entries - %s", period)
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
 	fmt.Println()
 
 	if len(entries) == 0 {
@@ -198,43 +351,49 @@ func outputEntriesTable(entries []storage.DailyLogEntry, period string, cmd *cob
 		return nil
 	}
 
+	// Titles take the rest of the terminal and wrap rather than being cut short
+	ids := shortIDs(entries)
+	idWidth := len("ID")
+	for _, id := range ids {
+		idWidth = max(idWidth, len(id))
+	}
+	const fixedWidth = 9 + 9 + 7 + 9
+	indent := fixedWidth + idWidth + 1
+	titleWidth := wrapWidth(indent)
+
 	// Table header
-	fmt.Printf("%-12s %-8s %-15s %-30s %-20s %-6s %-8s\n",
-		"TIME", "TYPE", "TAGS", "TITLE", "LOCATION", "STATUS", "PRIORITY")
-	fmt.Println(strings.Repeat("-", 100))
+	header := column("TIME", 9) + column("TYPE", 9) + column("STATUS", 7) + column("PRIORITY", 9) + column("ID", idWidth+1) + "TITLE"
+	fmt.Println(style(styleBold, header))
+	fmt.Println(rule("-", indent+max(titleWidth, 30)))
 
 	// Table rows
 	for _, entry := range entries {
-		timeStr := entry.Timestamp.Format("15:04:05")
-		tagsStr := strings.Join(entry.Tags, ",")
-		if len(tagsStr) > 15 {
-			tagsStr = tagsStr[:12] + "..."
+		titleLines := wrapText(pinnedTitle(entry), titleWidth)
+		fmt.Println(column(entry.Timestamp.Format("15:04:05"), 9) +
+			column(styleType(entry.Type), 9) +
+			column(formatStatus(entry.Status), 7) +
+			column(formatPriority(entry.Priority), 9) +
+			column(style(styleDim, ids[entry.ID]), idWidth+1) +
+			titleLines[0])
+		for _, line := range titleLines[1:] {
+			fmt.Println(strings.Repeat(" ", indent) + line)
 		}
-		titleStr := entry.Title
-		if len(titleStr) > 30 {
-			titleStr = titleStr[:27] + "..."
+
+		var details []string
+		if len(entry.Tags) > 0 {
+			details = append(details, "Tags: "+strings.Join(entry.Tags, ", "))
 		}
-		locationStr := entry.Location
-		if len(locationStr) > 20 {
-			locationStr = locationStr[:17] + "..."
+		if entry.Location != "" {
+			details = append(details, "Location: "+entry.Location)
 		}
-
-		statusStr := ""
-		if entry.Status > 0 {
-			statusStr = fmt.Sprintf("%d/10", entry.Status)
+		if entry.Duration != nil && *entry.Duration > 0 {
+			details = append(details, "Duration: "+formatMinutes(*entry.Duration))
 		}
-
-		priorityStr := ""
-		if entry.Priority > 0 {
-			priorityStr = fmt.Sprintf("%d/5", entry.Priority)
+		if len(details) > 0 {
+			printWrapped(strings.Join(details, " · "), 9, styleDim)
 		}
-
-		fmt.Printf("%-12s %-8s %-15s %-30s %-20s %-6s %-8s\n",
-			timeStr, entry.Type, tagsStr, titleStr, locationStr, statusStr, priorityStr)
-
-		// Show description if available and not too long
-		if entry.Description != "" && len(entry.Description) <= 80 {
-			fmt.Printf("             %s\n", entry.Description)
+		if entry.Description != "" {
+			printWrapped(entry.Description, 9, "")
 		}
 	}
 
@@ -242,90 +401,68 @@ func outputEntriesTable(entries []storage.DailyLogEntry, period string, cmd *cob
 	fmt.Printf("Total entries: %d\n", len(entries))
 
 	// Show stats if requested
-	if cmd != nil {
-		showStats, _ := cmd.Flags().GetBool("stats")
-		if showStats {
-			stats := calculateStats(entries)
-			fmt.Println("\nStatistics:")
-			fmt.Printf("  Average status: %.1f\n", stats["average_status"])
-			fmt.Printf("  Average priority: %.1f\n", stats["average_priority"])
-			fmt.Printf("  Most common type: %s\n", stats["common_type"])
-			fmt.Printf("  Most common tags: %s\n", stats["common_tags"])
-		}
+	if stats != nil {
+		fmt.Println("\n" + style(styleBold, "Statistics:"))
+		fmt.Printf("  Average status: %.1f\n", stats.Totals.AverageStatus)
+		fmt.Printf("  Average priority: %.1f\n", stats.Totals.AveragePriority)
+		fmt.Printf("  Total duration: %dm\n", stats.Totals.TotalDuration)
+		fmt.Printf("  Most common type: %s\n", mostCommon(stats.ByType))
+		fmt.Printf("  Most common tags: %s\n", mostCommon(stats.ByTag))
 	}
 
 	return nil
 }
 
-func calculateStats(entries []storage.DailyLogEntry) map[string]interface{} {
-	if len(entries) == 0 {
-		return map[string]interface{}{}
+// shortIDs returns the shortest unambiguous ID prefix of each entry, for display
+func shortIDs(entries []storage.DailyLogEntry) map[string]string {
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
 	}
+	return storage.ShortEntryIDs(ids)
+}
 
-	// Count by type
-	typeCount := make(map[string]int)
-	tagCount := make(map[string]int)
-	statusSum := 0
-	statusCount := 0
-	prioritySum := 0
-	priorityCount := 0
-
-	for _, entry := range entries {
-		typeCount[entry.Type]++
-
-		if entry.Status > 0 {
-			statusSum += entry.Status
-			statusCount++
-		}
-
-		if entry.Priority > 0 {
-			prioritySum += entry.Priority
-			priorityCount++
-		}
-
-		for _, tag := range entry.Tags {
-			tagCount[tag]++
-		}
-	}
+// entryStats holds the --stats rollups shown alongside entries
+type entryStats struct {
+	Totals storage.AggregationBucket   `json:"totals" yaml:"totals"`
+	ByType []storage.AggregationBucket `json:"by_type" yaml:"by_type"`
+	ByTag  []storage.AggregationBucket `json:"by_tag" yaml:"by_tag"`
+}
 
-	// Find most common type
-	var commonType string
-	maxTypeCount := 0
-	for entryType, count := range typeCount {
-		if count > maxTypeCount {
-			maxTypeCount = count
-			commonType = entryType
-		}
+// statsFromSearch uses the rollups the storage provider computed for a search
+func statsFromSearch(result *storage.LogSearchResponse) *entryStats {
+	stats := &entryStats{}
+	if result.Totals != nil {
+		stats.Totals = *result.Totals
 	}
-
-	// Find most common tags
-	var commonTags []string
-	maxTagCount := 0
-	for tag, count := range tagCount {
-		if count > maxTagCount {
-			maxTagCount = count
-			commonTags = []string{tag}
-		} else if count == maxTagCount {
-			commonTags = append(commonTags, tag)
+	for _, aggregation := range result.Aggregations {
+		switch aggregation.GroupBy {
+		case storage.GroupByType:
+			stats.ByType = aggregation.Buckets
+		case storage.GroupByTag:
+			stats.ByTag = aggregation.Buckets
 		}
 	}
+	return stats
+}
 
-	stats := map[string]interface{}{
-		"common_type": commonType,
-		"common_tags": strings.Join(commonTags, ", "),
-	}
-
-	if statusCount > 0 {
-		stats["average_status"] = float64(statusSum) / float64(statusCount)
-	} else {
-		stats["average_status"] = 0.0
+// calculateStats rolls up entries that were fetched without a search
+func calculateStats(entries []storage.DailyLogEntry) *entryStats {
+	return &entryStats{
+		Totals: storage.SummarizeEntries(entries),
+		ByType: storage.AggregateEntries(entries, storage.GroupByType).Buckets,
+		ByTag:  storage.AggregateEntries(entries, storage.GroupByTag).Buckets,
 	}
+}
 
-	if priorityCount > 0 {
-		stats["average_priority"] = float64(prioritySum) / float64(priorityCount)
-	} else {
-		stats["average_priority"] = 0.0
+// mostCommon lists the keys of the buckets sharing the highest count
+func mostCommon(buckets []storage.AggregationBucket) string {
+	var keys []string
+	for _, bucket := range buckets {
+		if bucket.Count < buckets[0].Count {
+			break
+		}
+		keys = append(keys, bucket.Key)
 	}
-
-	return stats
+	return strings.Join(keys, ", ")
 }
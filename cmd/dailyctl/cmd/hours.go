@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/storage"
+)
+
+// hoursCmd represents the hours command
+var hoursCmd = &cobra.Command{
+	Use:   "hours",
+	Short: "Check work time against daily and weekly targets and limits",
+	Long: `Sum the work time of each day and week, the durations of entries other
+than breaks (as classified by the focus rules), metrics, and planned
+entries, and check it against targets and legal limits, for timesheets
+that must stay within working time rules. Days and weeks over the limits
+are flagged, as are weekdays and finished weeks short of their targets.
+
+The targets and limits default to 8h a day and 40h a week, with at most
+10h a day and 48h a week; set them in the config file, or 0 to not check
+one. Weekly summaries flag days and weeks over the limits too:
+
+  hours:
+    daily_target: 7h30m
+    weekly_target: 37h30m
+    daily_max: 10h
+    weekly_max: 48h
+
+Examples:
+  dailyctl hours
+  dailyctl hours --period last-month
+  dailyctl hours --date-start 2025-09-01 --date-end 2025-09-30 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runHours,
+}
+
+func init() {
+	rootCmd.AddCommand(hoursCmd)
+
+	hoursCmd.Flags().String("period", "week", "Period: today, week, month, last-week, last-month")
+	hoursCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD or e.g. \"3 weeks ago\"), overrides --period")
+	hoursCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+	hoursCmd.Flags().Duration("daily-max", 0, "Flag days with more work time than this (e.g. 10h)")
+	hoursCmd.Flags().Duration("weekly-max", 0, "Flag weeks with more work time than this (e.g. 48h)")
+
+	_ = viper.BindPFlag("hours.daily_max", hoursCmd.Flags().Lookup("daily-max"))
+	_ = viper.BindPFlag("hours.weekly_max", hoursCmd.Flags().Lookup("weekly-max"))
+}
+
+func runHours(cmd *cobra.Command, args []string) error {
+	period, _ := cmd.Flags().GetString("period")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+
+	now := time.Now()
+	start, end, err := resolveTimePeriod(period, dateStartStr, dateEndStr, now)
+	if err != nil {
+		return err
+	}
+	if start.After(end) {
+		return invalidArgf("the report starts %s, after it ends", start.Format("2006-01-02"))
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{DateStart: &start, DateEnd: &end})
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	report := storage.CheckWorkHours(result.Entries, start, end, now, config.WorkHours(viper.GetViper()),
+		config.Focus(viper.GetViper()), storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases")))
+
+	if ok, err := outputStructured(report); ok {
+		return err
+	}
+	outputHoursReport(report)
+	return nil
+}
+
+func outputHoursReport(report storage.WorkHoursReport) {
+	title := "Work Hours - " + report.Period
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+	fmt.Println()
+
+	limits := report.Limits
+	fmt.Println(style(styleBold, fmt.Sprintf("%-14s %10s", "DAY", "WORK")))
+	for _, day := range report.Days {
+		date, _ := time.Parse("2006-01-02", day.Start)
+		fmt.Printf("%s %s %10s%s\n", day.Start, date.Format("Mon"), formatMinutes(day.Minutes),
+			hoursFlag(day, limits.DailyMax, limits.DailyTarget))
+	}
+
+	fmt.Println()
+	fmt.Println(style(styleBold, fmt.Sprintf("%-14s %10s", "WEEK OF", "WORK")))
+	for _, week := range report.Weeks {
+		fmt.Printf("%-14s %10s%s\n", week.Start, formatMinutes(week.Minutes),
+			hoursFlag(week, limits.WeeklyMax, limits.WeeklyTarget))
+	}
+
+	fmt.Println()
+	fmt.Printf("Total %s", formatMinutes(report.TotalMinutes))
+	if report.DaysOver+report.WeeksOver == 0 {
+		fmt.Println(", " + style(styleGreen, "within the limits"))
+		return
+	}
+	fmt.Println()
+	printWorkHoursFlags(report)
+}
+
+// hoursFlag marks a day or week over its limit or short of its target
+func hoursFlag(period storage.WorkHoursPeriod, max, target int) string {
+	switch {
+	case period.Over:
+		return style(styleRed, fmt.Sprintf("  ⚠ over %s", formatMinutes(max)))
+	case period.Under:
+		return style(styleDim, fmt.Sprintf("  under %s", formatMinutes(target)))
+	}
+	return ""
+}
+
+// printWorkHoursFlags lists the days and weeks over the limits
+func printWorkHoursFlags(report storage.WorkHoursReport) {
+	for _, day := range report.Days {
+		if day.Over {
+			fmt.Printf("    ⚠ %s worked %s, over %s\n", day.Start, formatMinutes(day.Minutes), formatMinutes(report.Limits.DailyMax))
+		}
+	}
+	for _, week := range report.Weeks {
+		if week.Over {
+			fmt.Printf("    ⚠ week of %s worked %s, over %s\n", week.Start, formatMinutes(week.Minutes), formatMinutes(report.Limits.WeeklyMax))
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/importers"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import entries from external services",
+	Long: `Import entries from external services such as calendars, GitHub, and time trackers.
+
+Imported entries record their source and external ID in metadata, so
+running the same import again only adds entries that are new.
+
+Examples:
+  dailyctl import gcal
+  dailyctl import gcal --date 2025-09-29
+  dailyctl import gcal --every 30m
+  dailyctl import github-activity --user me --date today
+  dailyctl import toggl --date yesterday
+  dailyctl import clockify
+  dailyctl import jira --jql "project = ENG" --date yesterday
+  dailyctl import apple-health export.zip --date-start 2025-01-01
+  dailyctl import google-fit takeout.zip`,
+}
+
+var importGCalCmd = &cobra.Command{
+	Use:   "gcal",
+	Short: "Import Google Calendar events as meeting activities",
+	Long: `Import timed Google Calendar events as activity entries tagged "meeting".
+
+Each event becomes an entry with its duration, location, and attendees
+(in metadata). All-day, cancelled, and declined events are skipped.
+
+An OAuth access token with the calendar.readonly scope is required
+(use --gcal-token or set DAILYLOG_GCAL_TOKEN).
+
+With --every, the command keeps running and re-imports the day at the given
+interval, which is handy under launchd, systemd, or cron-less setups.`,
+	RunE: runImportGCal,
+}
+
+var importGitHubActivityCmd = &cobra.Command{
+	Use:   "github-activity",
+	Short: "Import GitHub activity as engineering activities",
+	Long: `Import commits, pull requests opened/merged/reviewed, and closed issues
+from the GitHub Events API as activity entries tagged "github" and by repository.
+
+The configured GitHub token is used (--github-token or DAILYLOG_GITHUB_TOKEN).
+Note that GitHub only exposes roughly the last 90 days of events.`,
+	RunE: runImportGitHubActivity,
+}
+
+var importTogglCmd = &cobra.Command{
+	Use:   "toggl",
+	Short: "Import Toggl Track time entries",
+	Long: `Import completed Toggl Track time entries as activity entries with their
+duration, tagged by project and Toggl tags.
+
+An API token is required (use --toggl-token or set DAILYLOG_TOGGL_TOKEN).`,
+	RunE: runImportToggl,
+}
+
+var importClockifyCmd = &cobra.Command{
+	Use:   "clockify",
+	Short: "Import Clockify time entries",
+	Long: `Import completed Clockify time entries as activity entries with their
+duration, tagged by project and Clockify tags.
+
+An API key is required (use --clockify-token or set DAILYLOG_CLOCKIFY_TOKEN).
+The active workspace is used unless --workspace is given.`,
+	RunE: runImportClockify,
+}
+
+var importJiraCmd = &cobra.Command{
+	Use:   "jira",
+	Short: "Import Jira status changes and worklogs as activities",
+	Long: `Import what you did on Jira issues: each status change you made becomes an
+activity ("ENG-123 moved to In Review: ..."), and each worklog an activity
+with its duration and comment. Entries are tagged "jira" and by project, and
+link their issue in metadata.
+
+Issues are those matching --jql (default: assigned to you or worklogged by
+you) updated since the day imported.
+
+Configure the site in ~/.dailyctl.yaml or the environment:
+  jira.url    DAILYLOG_JIRA_URL    e.g. https://acme.atlassian.net
+  jira.email  DAILYLOG_JIRA_EMAIL  Jira Cloud: your account's email
+  jira.token  DAILYLOG_JIRA_TOKEN  Jira Cloud: an API token; Data Center
+                                   and Server: a personal access token`,
+	RunE: runImportJira,
+}
+
+var importAppleHealthCmd = &cobra.Command{
+	Use:   "apple-health <export>",
+	Short: "Import steps, sleep, weight, and workouts from an Apple Health export",
+	Long: `Import an Apple Health export (export.zip from Health > Profile > Export All
+Health Data, or the export.xml inside it).
+
+Each day's steps and each night's sleep become a metric entry, logged when the
+last sample ends, with sleep counted towards the day it ends on. When a phone
+and a watch both record a day, the source that recorded the most is used
+rather than adding them up. Weight readings become metric entries, and
+workouts become activity entries with their duration, tagged "workout" and by
+activity. All entries are private.
+
+The whole export is imported unless --date-start or --date-end is given.
+Import finished days only: a day already imported is not updated.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportAppleHealth,
+}
+
+var importGoogleFitCmd = &cobra.Command{
+	Use:   "google-fit <export>",
+	Short: "Import steps, sleep, weight, and workouts from a Google Fit Takeout export",
+	Long: `Import Google Fit data from a Google Takeout export (the zip, or the extracted
+Takeout or Fit folder).
+
+Sessions become activity entries with their duration, tagged "workout" and by
+activity, except sleep sessions, which are added up into a sleep metric entry
+per night, counted towards the day they end on. The daily step counts and
+weights in "Daily activity metrics.csv" become metric entries logged at the
+end of their day. All entries are private.
+
+The whole export is imported unless --date-start or --date-end is given.
+Import finished days only: a day already imported is not updated.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportGoogleFit,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.AddCommand(importGCalCmd)
+	importCmd.AddCommand(importGitHubActivityCmd)
+	importCmd.AddCommand(importTogglCmd)
+	importCmd.AddCommand(importClockifyCmd)
+	importCmd.AddCommand(importJiraCmd)
+	importCmd.AddCommand(importAppleHealthCmd)
+	importCmd.AddCommand(importGoogleFitCmd)
+
+	// Common flags for all importers
+	addImportFlags := func(cmd *cobra.Command) {
+		cmd.Flags().String("date", "", "Date to import (YYYY-MM-DD, today, yesterday; defaults to today)")
+		cmd.Flags().Duration("every", 0, "Re-import today at this interval until interrupted (e.g. 30m)")
+		cmd.Flags().Bool("dry-run", false, dryRunUsage)
+		cmd.Flags().String("duplicates", "", duplicatesUsage)
+	}
+
+	addImportFlags(importGCalCmd)
+	addImportFlags(importGitHubActivityCmd)
+	addImportFlags(importTogglCmd)
+	addImportFlags(importClockifyCmd)
+	addImportFlags(importJiraCmd)
+
+	// Exports cover many days, so they are imported by range
+	addExportImportFlags := func(cmd *cobra.Command) {
+		cmd.Flags().String("date-start", "", "First date to import (YYYY-MM-DD or e.g. \"last monday\"), default: all")
+		cmd.Flags().String("date-end", "", "Last date to import (YYYY-MM-DD or e.g. \"yesterday\"), default: all")
+		cmd.Flags().Bool("dry-run", false, dryRunUsage)
+		cmd.Flags().String("duplicates", "", duplicatesUsage)
+	}
+
+	addExportImportFlags(importAppleHealthCmd)
+	addExportImportFlags(importGoogleFitCmd)
+
+	importGCalCmd.Flags().String("calendar", "primary", "Calendar ID to import from")
+	importGCalCmd.Flags().String("gcal-token", "", "Google Calendar OAuth access token")
+
+	_ = viper.BindPFlag("gcal.token", importGCalCmd.Flags().Lookup("gcal-token"))
+	_ = viper.BindPFlag("gcal.calendar", importGCalCmd.Flags().Lookup("calendar"))
+
+	importGitHubActivityCmd.Flags().String("user", "me", "GitHub user whose activity to import (me = token owner)")
+
+	importTogglCmd.Flags().String("toggl-token", "", "Toggl Track API token")
+	_ = viper.BindPFlag("toggl.token", importTogglCmd.Flags().Lookup("toggl-token"))
+
+	importClockifyCmd.Flags().String("clockify-token", "", "Clockify API key")
+	importClockifyCmd.Flags().String("workspace", "", "Clockify workspace ID (defaults to the active workspace)")
+	_ = viper.BindPFlag("clockify.token", importClockifyCmd.Flags().Lookup("clockify-token"))
+	_ = viper.BindPFlag("clockify.workspace", importClockifyCmd.Flags().Lookup("workspace"))
+
+	importJiraCmd.Flags().String("jql", importers.DefaultJiraJQL, "JQL selecting the issues to import from")
+	importJiraCmd.Flags().String("jira-url", "", "Jira site URL")
+	importJiraCmd.Flags().String("jira-email", "", "Jira Cloud account email (leave empty for a personal access token)")
+	importJiraCmd.Flags().String("jira-token", "", "Jira API token or personal access token")
+	_ = viper.BindPFlag("jira.jql", importJiraCmd.Flags().Lookup("jql"))
+	_ = viper.BindPFlag("jira.url", importJiraCmd.Flags().Lookup("jira-url"))
+	_ = viper.BindPFlag("jira.email", importJiraCmd.Flags().Lookup("jira-email"))
+	_ = viper.BindPFlag("jira.token", importJiraCmd.Flags().Lookup("jira-token"))
+}
+
+func runImportGCal(cmd *cobra.Command, args []string) error {
+	importer, err := importers.NewGoogleCalendarImporter(
+		viper.GetString("gcal.token"),
+		viper.GetString("gcal.calendar"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create calendar importer: %w (use --gcal-token or set DAILYLOG_GCAL_TOKEN)", err)
+	}
+
+	return runImport(cmd, importer)
+}
+
+func runImportGitHubActivity(cmd *cobra.Command, args []string) error {
+	user, _ := cmd.Flags().GetString("user")
+
+	importer, err := importers.NewGitHubActivityImporter(viper.GetString("github.token"), viper.GetString("github.base_url"), user)
+	if err != nil {
+		return fmt.Errorf("failed to create GitHub activity importer: %w", err)
+	}
+
+	return runImport(cmd, importer)
+}
+
+func runImportToggl(cmd *cobra.Command, args []string) error {
+	importer, err := importers.NewTogglImporter(viper.GetString("toggl.token"))
+	if err != nil {
+		return fmt.Errorf("failed to create Toggl importer: %w (use --toggl-token or set DAILYLOG_TOGGL_TOKEN)", err)
+	}
+
+	return runImport(cmd, importer)
+}
+
+func runImportClockify(cmd *cobra.Command, args []string) error {
+	importer, err := importers.NewClockifyImporter(
+		viper.GetString("clockify.token"),
+		viper.GetString("clockify.workspace"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create Clockify importer: %w (use --clockify-token or set DAILYLOG_CLOCKIFY_TOKEN)", err)
+	}
+
+	return runImport(cmd, importer)
+}
+
+func runImportJira(cmd *cobra.Command, args []string) error {
+	importer, err := importers.NewJiraImporter(
+		viper.GetString("jira.url"),
+		viper.GetString("jira.email"),
+		viper.GetString("jira.token"),
+		viper.GetString("jira.jql"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create Jira importer: %w (use --jira-url and --jira-token or set DAILYLOG_JIRA_URL and DAILYLOG_JIRA_TOKEN)", err)
+	}
+
+	return runImport(cmd, importer)
+}
+
+func runImportAppleHealth(cmd *cobra.Command, args []string) error {
+	importer, err := importers.NewAppleHealthImporter(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create Apple Health importer: %w", err)
+	}
+
+	return runImportRange(cmd, importer)
+}
+
+func runImportGoogleFit(cmd *cobra.Command, args []string) error {
+	importer, err := importers.NewGoogleFitImporter(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to create Google Fit importer: %w", err)
+	}
+
+	return runImportRange(cmd, importer)
+}
+
+// runImportRange imports everything between --date-start and --date-end,
+// either of which may be left open
+func runImportRange(cmd *cobra.Command, importer importers.Importer) error {
+	startStr, _ := cmd.Flags().GetString("date-start")
+	endStr, _ := cmd.Flags().GetString("date-end")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	var start, end time.Time
+	period := "all dates"
+	if startStr != "" {
+		day, err := parseImportDate(startStr)
+		if err != nil {
+			return err
+		}
+		start = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if endStr != "" {
+		day, err := parseImportDate(endStr)
+		if err != nil {
+			return err
+		}
+		end = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return invalidArgf("--date-start must not be after --date-end")
+	}
+	switch {
+	case !start.IsZero() && !end.IsZero():
+		period = start.Format("2006-01-02") + " to " + end.AddDate(0, 0, -1).Format("2006-01-02")
+	case !start.IsZero():
+		period = start.Format("2006-01-02") + " onwards"
+	case !end.IsZero():
+		period = "dates up to " + end.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	result, err := importers.Import(storageProvider, importer, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to import %s entries: %w", importer.Name(), err)
+	}
+	if err := outputImportResult(result, importer.Name(), period, "2006-01-02 15:04", dryRun); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
+
+// runImport imports a single day, or keeps re-importing today when --every is set
+func runImport(cmd *cobra.Command, importer importers.Importer) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	every, _ := cmd.Flags().GetDuration("every")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if dateStr != "" && every > 0 {
+		return fmt.Errorf("--date and --every cannot be used together")
+	}
+	if dryRun && every > 0 {
+		return fmt.Errorf("--dry-run and --every cannot be used together")
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	importDay := func(day time.Time) error {
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+		end := start.AddDate(0, 0, 1)
+
+		result, err := importers.Import(storageProvider, importer, start, end)
+		if err != nil {
+			return fmt.Errorf("failed to import %s entries: %w", importer.Name(), err)
+		}
+		if err := outputImportResult(result, importer.Name(), start.Format("2006-01-02"), "15:04", dryRun); err != nil {
+			return err
+		}
+		reportDryRun(preview)
+		return nil
+	}
+
+	if every <= 0 {
+		targetDate := time.Now()
+		if dateStr != "" {
+			targetDate, err = parseImportDate(dateStr)
+			if err != nil {
+				return err
+			}
+		}
+		return importDay(targetDate)
+	}
+
+	// Scheduled mode: keep importing today's entries until interrupted
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		if err := importDay(time.Now()); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+// parseImportDate accepts YYYY-MM-DD or a relative day such as "today" or "last friday"
+func parseImportDate(dateStr string) (time.Time, error) {
+	date, err := datetime.ParseDate(dateStr, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date format: %s (use YYYY-MM-DD, today, or yesterday)", dateStr)
+	}
+	return date, nil
+}
+
+// outputImportResult reports an import over period, listing entries with
+// their time in timeLayout
+func outputImportResult(result *importers.ImportResult, source, period, timeLayout string, dryRun bool) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	verb := "✓ Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d %s entries for %s (%d already present)\n",
+		verb, len(result.Created), source, period, result.Skipped)
+	for _, entry := range result.Created {
+		duration := ""
+		if entry.Duration != nil {
+			duration = fmt.Sprintf(" (%dm)", *entry.Duration)
+		}
+		fmt.Printf("  %s  %s%s\n", entry.Timestamp.Format(timeLayout), entry.Title, duration)
+	}
+	return nil
+}
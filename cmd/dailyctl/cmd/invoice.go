@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/datetime"
+	"dailylog/internal/exporters"
+	"dailylog/internal/storage"
+)
+
+// invoiceCmd represents the invoice command
+var invoiceCmd = &cobra.Command{
+	Use:   "invoice",
+	Short: "Bill a project's or tags' tracked time at hourly rates",
+	Long: `Bill the time tracked for a project (see dailyctl project), or on entries
+with some tags, over a month or range, at the hourly rates in the config
+file. Each entry with a duration becomes a line item named by its title;
+--merge bills entries of the same title together. Entries tagged
+non-billable are left off.
+
+Rates are matched in order, by project and/or tags; a rate with neither
+matches everything, so put it last. Lines no rate matches are billed at 0
+and reported. round_to rounds each line up to a multiple of minutes:
+
+  billing:
+    currency: EUR
+    round_to: 15
+    rates:
+      - {project: search-api, rate: 120}
+      - {tags: [consulting], rate: 95}
+      - {rate: 80}
+
+The invoice covers last month unless --month or --date-start says otherwise.
+
+Examples:
+  dailyctl invoice --project search-api --month 2025-09
+  dailyctl invoice --project search-api --month "last month" --merge --format markdown
+  dailyctl invoice --tags acme --date-start 2025-09-15 --format csv --file acme.csv`,
+	Args: cobra.NoArgs,
+	RunE: runInvoice,
+}
+
+func init() {
+	rootCmd.AddCommand(invoiceCmd)
+
+	invoiceCmd.Flags().String("project", "", "Bill the entries of this project")
+	invoiceCmd.Flags().StringSlice("tags", []string{}, "Bill the entries with one of these tags")
+	invoiceCmd.Flags().String("month", "", "Month to bill (YYYY-MM or e.g. \"last month\")")
+	invoiceCmd.Flags().String("date-start", "", "Start of the range to bill (YYYY-MM-DD or e.g. \"3 weeks ago\")")
+	invoiceCmd.Flags().String("date-end", "", "End of the range (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+	invoiceCmd.Flags().Bool("merge", false, "Bill entries of the same title and rate as one line")
+	invoiceCmd.Flags().String("format", "", "Write the invoice as csv or markdown instead of a table")
+	invoiceCmd.Flags().StringP("file", "f", "", "Write to file instead of stdout")
+
+	invoiceCmd.MarkFlagsMutuallyExclusive("month", "date-start")
+	_ = invoiceCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	_ = invoiceCmd.RegisterFlagCompletionFunc("tags", completeTags)
+}
+
+func runInvoice(cmd *cobra.Command, args []string) error {
+	projectName, _ := cmd.Flags().GetString("project")
+	filterTags, _ := cmd.Flags().GetStringSlice("tags")
+	month, _ := cmd.Flags().GetString("month")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	merge, _ := cmd.Flags().GetBool("merge")
+	format, _ := cmd.Flags().GetString("format")
+	file, _ := cmd.Flags().GetString("file")
+
+	if projectName == "" && len(filterTags) == 0 {
+		return invalidArgf("choose what to bill with --project or --tags")
+	}
+	switch format {
+	case "csv", "markdown":
+	case "":
+		if file != "" {
+			return invalidArgf("--file requires --format csv or markdown")
+		}
+	default:
+		return invalidArgf("--format must be csv or markdown (got %q)", format)
+	}
+
+	start, end, err := invoicePeriod(month, dateStartStr, dateEndStr, time.Now())
+	if err != nil {
+		return err
+	}
+	billing, err := config.Billing(viper.GetViper())
+	if err != nil {
+		return err
+	}
+	tags := storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases"))
+
+	projectStore, err := createProjectStore()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	projects, err := projectStore.GetProjects()
+	if err != nil {
+		return err
+	}
+	var project *storage.Project
+	if projectName != "" {
+		var ok bool
+		if project, ok = projects.Find(projectName); !ok {
+			// Entries can name a project by metadata alone
+			project = &storage.Project{Name: projectName}
+		}
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{DateStart: &start, DateEnd: &end})
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	var entries []storage.DailyLogEntry
+	for _, entry := range result.Entries {
+		if project != nil && !project.Includes(entry, tags) {
+			continue
+		}
+		if len(filterTags) > 0 && !tags.HasAnyTag(entry, filterTags) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	invoice := storage.BuildInvoice(entries, billing, projects, tags, merge)
+	invoice.Period = fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if project != nil {
+		invoice.Project = project.Name
+	} else {
+		invoice.Project = strings.Join(filterTags, ", ")
+	}
+	if invoice.Unrated > 0 {
+		fmt.Fprintf(os.Stderr, "⚠ %d lines matched no rate in billing.rates and are billed at 0\n", invoice.Unrated)
+	}
+
+	if format == "" {
+		if ok, err := outputStructured(invoice); ok {
+			return err
+		}
+		outputInvoiceTable(invoice)
+		return nil
+	}
+
+	out, closeOut, err := openExportOutput(file)
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+	if format == "csv" {
+		err = exporters.WriteInvoiceCSV(out, invoice)
+	} else {
+		err = exporters.WriteInvoiceMarkdown(out, invoice)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write invoice: %w", err)
+	}
+	if file != "" {
+		fmt.Printf("✓ Wrote %d lines totalling %.2f %s to %s\n", len(invoice.Lines), invoice.Total, invoice.Currency, file)
+	}
+	return nil
+}
+
+// invoicePeriod returns the days selected by --month or --date-start and
+// --date-end, by default last month
+func invoicePeriod(month, dateStartStr, dateEndStr string, now time.Time) (time.Time, time.Time, error) {
+	if month == "" {
+		if dateStartStr == "" && dateEndStr != "" {
+			return time.Time{}, time.Time{}, invalidArgf("--date-end requires --date-start")
+		}
+		return resolveTimePeriod("last-month", dateStartStr, dateEndStr, now)
+	}
+	if dateEndStr != "" {
+		return time.Time{}, time.Time{}, invalidArgf("--month cannot be combined with --date-end")
+	}
+	if first, err := time.ParseInLocation("2006-01", month, now.Location()); err == nil {
+		return first, first.AddDate(0, 1, -1), nil
+	}
+	start, end, err := datetime.ParseRange(month, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, invalidArgf("invalid month: %s (use YYYY-MM or e.g. \"last month\")", month)
+	}
+	return start, end, nil
+}
+
+func outputInvoiceTable(invoice storage.Invoice) {
+	title := fmt.Sprintf("Invoice %s - %s", invoice.Project, invoice.Period)
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+	fmt.Println()
+
+	if len(invoice.Lines) == 0 {
+		fmt.Println("No billable time found.")
+		return
+	}
+
+	titleWidth := 36
+	fmt.Println(style(styleBold, fmt.Sprintf("%-10s  %-*s %7s %9s %10s", "DATE", titleWidth, "DESCRIPTION", "HOURS", "RATE", "AMOUNT")))
+	for _, line := range invoice.Lines {
+		rate := fmt.Sprintf("%9.2f", line.Rate)
+		if line.Unrated {
+			rate = style(styleYellow, fmt.Sprintf("%9s", "no rate"))
+		}
+		titleLines := wrapText(line.Title, titleWidth)
+		fmt.Printf("%-10s  %-*s %7.2f %s %10.2f\n", line.Date, titleWidth, titleLines[0], float64(line.Minutes)/60, rate, line.Amount)
+		for _, extra := range titleLines[1:] {
+			fmt.Printf("%-10s  %s\n", "", extra)
+		}
+	}
+	fmt.Println(rule("-", titleWidth+42))
+	fmt.Printf("%-10s  %-*s %7.2f %9s %10.2f %s\n", "", titleWidth, "Total", float64(invoice.Minutes)/60, "", invoice.Total, invoice.Currency)
+}
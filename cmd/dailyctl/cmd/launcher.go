@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/storage"
+)
+
+// launcherFormatUsage describes the --format flag of get and search
+const launcherFormatUsage = "Output for launchers: raycast (a JSON object per line) or alfred (Script Filter JSON)"
+
+// typeIcons are the icons launchers show for each entry type
+var typeIcons = map[string]string{
+	"activity": "✅",
+	"status":   "📊",
+	"note":     "📝",
+	"summary":  "📋",
+	"meeting":  "👥",
+	"metric":   "📈",
+	"lesson":   "💡",
+}
+
+// RaycastItem is an entry as a Raycast list item
+type RaycastItem struct {
+	ID          string                `json:"id"`
+	Title       string                `json:"title"`
+	Subtitle    string                `json:"subtitle,omitempty"`
+	Icon        string                `json:"icon"`
+	Accessories []RaycastAccessory    `json:"accessories,omitempty"`
+	Keywords    []string              `json:"keywords,omitempty"`
+	Date        string                `json:"date"`
+	Type        string                `json:"type"`
+	Entry       storage.DailyLogEntry `json:"entry"`
+}
+
+// RaycastAccessory is text or a tag shown at the right of a Raycast item
+type RaycastAccessory struct {
+	Text string `json:"text,omitempty"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+// AlfredItem is an entry as an Alfred Script Filter result; actioning it
+// passes on the entry ID
+type AlfredItem struct {
+	UID          string     `json:"uid"`
+	Title        string     `json:"title"`
+	Subtitle     string     `json:"subtitle"`
+	Arg          string     `json:"arg"`
+	Match        string     `json:"match"`
+	Autocomplete string     `json:"autocomplete"`
+	Text         AlfredText `json:"text"`
+}
+
+// AlfredText is what Alfred copies and shows in large type for an item
+type AlfredText struct {
+	Copy      string `json:"copy"`
+	LargeType string `json:"largetype"`
+}
+
+// launcherFormat reads --format, which is empty unless set
+func launcherFormat(cmd *cobra.Command) (string, error) {
+	if cmd == nil {
+		return "", nil
+	}
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "", "raycast", "alfred":
+		return format, nil
+	default:
+		return "", invalidArgf("invalid format: %s (use raycast or alfred)", format)
+	}
+}
+
+// outputLauncher prints entries in a launcher format, each on one line
+func outputLauncher(format string, entries []storage.DailyLogEntry) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	if format == "alfred" {
+		items := make([]AlfredItem, 0, len(entries))
+		for _, entry := range entries {
+			items = append(items, alfredItem(entry))
+		}
+		return encoder.Encode(map[string]any{"items": items})
+	}
+
+	for _, entry := range entries {
+		if err := encoder.Encode(raycastItem(entry)); err != nil {
+			return fmt.Errorf("failed to write entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// raycastItem renders an entry with its details as Raycast accessories
+func raycastItem(entry storage.DailyLogEntry) RaycastItem {
+	item := RaycastItem{
+		ID:       entry.ID,
+		Title:    entry.Title,
+		Subtitle: launcherSubtitle(entry, false),
+		Icon:     typeIcon(entry.Type),
+		Keywords: entry.Tags,
+		Date:     entry.Timestamp.Format(time.RFC3339),
+		Type:     entry.Type,
+		Entry:    entry,
+	}
+	if entry.Status > 0 {
+		item.Accessories = append(item.Accessories, RaycastAccessory{Text: fmt.Sprintf("%d/10", entry.Status)})
+	}
+	if entry.Duration != nil && *entry.Duration > 0 {
+		item.Accessories = append(item.Accessories, RaycastAccessory{Text: formatMinutes(*entry.Duration)})
+	}
+	for _, tag := range entry.Tags {
+		item.Accessories = append(item.Accessories, RaycastAccessory{Tag: "#" + tag})
+	}
+	return item
+}
+
+// alfredItem renders an entry with its details in the subtitle, as Alfred
+// has no accessories and takes icons only as files
+func alfredItem(entry storage.DailyLogEntry) AlfredItem {
+	text := entry.Title
+	if entry.Description != "" {
+		text += "\n\n" + entry.Description
+	}
+	return AlfredItem{
+		UID:          entry.ID,
+		Title:        typeIcon(entry.Type) + " " + entry.Title,
+		Subtitle:     launcherSubtitle(entry, true),
+		Arg:          entry.ID,
+		Match:        strings.Join(append([]string{entry.Title, entry.Type}, entry.Tags...), " "),
+		Autocomplete: entry.Title,
+		Text:         AlfredText{Copy: entry.Title, LargeType: text},
+	}
+}
+
+// launcherSubtitle is the time, type, and, with details, the status,
+// duration, and tags of an entry, then the start of its description
+func launcherSubtitle(entry storage.DailyLogEntry, details bool) string {
+	parts := []string{entry.Timestamp.Format("2006-01-02 15:04"), entry.Type}
+	if details {
+		if entry.Status > 0 {
+			parts = append(parts, fmt.Sprintf("%d/10", entry.Status))
+		}
+		if entry.Duration != nil && *entry.Duration > 0 {
+			parts = append(parts, formatMinutes(*entry.Duration))
+		}
+		for _, tag := range entry.Tags {
+			parts = append(parts, "#"+tag)
+		}
+	}
+	if description, _, _ := strings.Cut(strings.TrimSpace(entry.Description), "\n"); description != "" {
+		parts = append(parts, truncateText(80, description))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// typeIcon is the icon of an entry type, or a bullet for custom types
+func typeIcon(entryType string) string {
+	if icon, ok := typeIcons[entryType]; ok {
+		return icon
+	}
+	return "•"
+}
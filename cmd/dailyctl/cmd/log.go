@@ -1,16 +1,19 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"dailylog/internal/config"
+	"dailylog/internal/datetime"
 	"dailylog/internal/providers"
+	"dailylog/internal/sentiment"
 	"dailylog/internal/storage"
 )
 
@@ -25,37 +28,67 @@ Examples:
   dailyctl log status "Feeling great today" --status 9 --datetime "yesterday 3pm"
   dailyctl log note "Remember to call mom" --priority 3 --datetime "2 hours ago"
   dailyctl log activity "Completed project" --datetime "2025-09-29 14:30" --status 10
-  dailyctl log summary "Productive day overall" --date "2025-09-28"`,
+  dailyctl log summary "Productive day overall" --date "2025-09-28"
+  dailyctl log meeting "Sprint planning" --attendees alice,bob --decision "Ship on Friday" --action "Alice: update the docs"
+  git log -1 | dailyctl log activity "Released v1.2" --tags release
+  dailyctl log note --editor
+  dailyctl log activity "Deployed v2" --tags release --dry-run
+  dailyctl log -i`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			return runLogWizard(cmd)
+		}
+		return cmd.Help()
+	},
 }
 
 var logActivityCmd = &cobra.Command{
 	Use:   "activity [title]",
 	Short: "Log a daily activity",
-	Args:  cobra.ExactArgs(1),
+	Args:  logArgs,
 	RunE:  runLogEntry("activity"),
 }
 
 var logStatusCmd = &cobra.Command{
 	Use:   "status [description]",
 	Short: "Log status information",
-	Args:  cobra.ExactArgs(1),
+	Args:  logArgs,
 	RunE:  runLogEntry("status"),
 }
 
 var logNoteCmd = &cobra.Command{
 	Use:   "note [content]",
 	Short: "Create a note entry",
-	Args:  cobra.ExactArgs(1),
+	Args:  logArgs,
 	RunE:  runLogEntry("note"),
 }
 
 var logSummaryCmd = &cobra.Command{
 	Use:   "summary [content]",
 	Short: "Create a summary entry",
-	Args:  cobra.ExactArgs(1),
+	Args:  logArgs,
 	RunE:  runLogEntry("summary"),
 }
 
+var logMeetingCmd = &cobra.Command{
+	Use:   "meeting [title]",
+	Short: "Log a meeting with its attendees, decisions, and action items",
+	Long: `Log a meeting with its attendees, decisions, and action items. Action items
+show up as open tasks in the next day's standup and plan.`,
+	Args: logArgs,
+	RunE: runLogEntry(storage.EntryTypeMeeting),
+}
+
+var logLessonCmd = &cobra.Command{
+	Use:   "lesson [what was learned]",
+	Short: "Log a lesson learned",
+	Long: `Log a lesson learned, with its context as the description. Lessons, and
+entries of other types tagged "lesson", can be turned into flashcards with
+'dailyctl export anki'.`,
+	Args: logArgs,
+	RunE: runLogEntry(storage.EntryTypeLesson),
+}
+
 func init() {
 	rootCmd.AddCommand(logCmd)
 
@@ -64,17 +97,29 @@ func init() {
 	logCmd.AddCommand(logStatusCmd)
 	logCmd.AddCommand(logNoteCmd)
 	logCmd.AddCommand(logSummaryCmd)
+	logCmd.AddCommand(logMeetingCmd)
+	logCmd.AddCommand(logLessonCmd)
+
+	logCmd.Flags().BoolP("interactive", "i", false, "Walk through logging one or more entries interactively")
+	logCmd.PersistentFlags().Bool("dry-run", false, dryRunUsage)
+	logCmd.PersistentFlags().String("duplicates", "", duplicatesUsage)
 
 	// Common flags for all log commands
 	addLogFlags := func(cmd *cobra.Command) {
 		cmd.Flags().String("date", "", "Date for the entry (YYYY-MM-DD, defaults to today)")
 		cmd.Flags().String("datetime", "", "Date and time for the entry (flexible format, e.g. '2025-09-29 14:30', 'yesterday 3pm', '2 hours ago')")
-		cmd.Flags().String("description", "", "Detailed description")
+		cmd.Flags().String("description", "", "Detailed description (read from stdin when piped)")
+		cmd.Flags().Bool("editor", false, "Compose the title and description in $EDITOR")
 		cmd.Flags().StringSlice("tags", []string{}, "Tags for categorization")
 		cmd.Flags().Int("status", 0, "Status rating (1-10)")
 		cmd.Flags().Int("priority", 0, "Priority level (1-5)")
 		cmd.Flags().Int("duration", 0, "Duration in minutes")
 		cmd.Flags().String("location", "", "Location")
+		cmd.Flags().String("visibility", "", "Visibility: private, team, public (defaults to privacy.default, or team)")
+		cmd.Flags().String("project", "", "Project the entry belongs to (see 'dailyctl project')")
+		cmd.Flags().Bool("planned", false, "Log a plan for the date rather than something done (see 'dailyctl compare')")
+		_ = cmd.RegisterFlagCompletionFunc("tags", completeTags)
+		_ = cmd.RegisterFlagCompletionFunc("project", completeProjects)
 		
 		// Make date and datetime mutually exclusive
 		cmd.MarkFlagsMutuallyExclusive("date", "datetime")
@@ -84,11 +129,20 @@ func init() {
 	addLogFlags(logStatusCmd)
 	addLogFlags(logNoteCmd)
 	addLogFlags(logSummaryCmd)
+	addLogFlags(logMeetingCmd)
+	addLogFlags(logLessonCmd)
+
+	logMeetingCmd.Flags().StringSlice("attendees", []string{}, "People who attended")
+	logMeetingCmd.Flags().StringArray("decision", []string{}, "A decision made (repeatable)")
+	logMeetingCmd.Flags().StringArray("action", []string{}, "An action item to follow up (repeatable)")
 }
 
 func runLogEntry(entryType string) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		title := args[0]
+		title := ""
+		if len(args) > 0 {
+			title = args[0]
+		}
 
 		// Parse flags
 		dateStr, _ := cmd.Flags().GetString("date")
@@ -99,14 +153,41 @@ func runLogEntry(entryType string) func(cmd *cobra.Command, args []string) error
 		priority, _ := cmd.Flags().GetInt("priority")
 		duration, _ := cmd.Flags().GetInt("duration")
 		location, _ := cmd.Flags().GetString("location")
+		visibility, _ := cmd.Flags().GetString("visibility")
+		useEditor, _ := cmd.Flags().GetBool("editor")
+		planned, _ := cmd.Flags().GetBool("planned")
+		project, _ := cmd.Flags().GetString("project")
+
+		// Only meetings have these flags
+		var meeting storage.Meeting
+		meeting.Attendees, _ = cmd.Flags().GetStringSlice("attendees")
+		meeting.Decisions, _ = cmd.Flags().GetStringArray("decision")
+		meeting.ActionItems, _ = cmd.Flags().GetStringArray("action")
+
+		// Piped input becomes the description
+		if description == "" {
+			piped, err := readPipedInput()
+			if err != nil {
+				return err
+			}
+			description = piped
+		}
+
+		if useEditor {
+			draft, body, err := composeInEditor(entryDraft{Title: title, Tags: tags, Location: location}, description)
+			if err != nil {
+				return err
+			}
+			title, tags, location, description = draft.Title, draft.Tags, draft.Location, body
+		}
 
 		// Parse date/datetime
 		var entryDate time.Time
 		var err error
 		if datetimeStr != "" {
-			entryDate, err = parseFlexibleDateTime(datetimeStr)
+			entryDate, err = datetime.Parse(datetimeStr, time.Now())
 			if err != nil {
-				return fmt.Errorf("invalid datetime format: %s (%v)", datetimeStr, err)
+				return invalidArgf("invalid datetime format: %s (%w)", datetimeStr, err)
 			}
 			// Debug output (remove this later)
 			if viper.GetBool("verbose") {
@@ -116,7 +197,7 @@ func runLogEntry(entryType string) func(cmd *cobra.Command, args []string) error
 			// Parse date and use current time
 			dateOnly, err := time.Parse("2006-01-02", dateStr)
 			if err != nil {
-				return fmt.Errorf("invalid date format: %s (use YYYY-MM-DD)", dateStr)
+				return invalidArgf("invalid date format: %s (use YYYY-MM-DD)", dateStr)
 			}
 			now := time.Now()
 			entryDate = time.Date(dateOnly.Year(), dateOnly.Month(), dateOnly.Day(), 
@@ -127,18 +208,22 @@ func runLogEntry(entryType string) func(cmd *cobra.Command, args []string) error
 
 		// Validate status range
 		if status < 0 || status > 10 {
-			return fmt.Errorf("status must be between 1 and 10")
+			return invalidArgf("status must be between 1 and 10")
 		}
 
 		// Validate priority range
 		if priority < 0 || priority > 5 {
-			return fmt.Errorf("priority must be between 1 and 5")
+			return invalidArgf("priority must be between 1 and 5")
+		}
+
+		if err := storage.ValidateVisibility(visibility); err != nil {
+			return err
 		}
 
 		// Create storage provider
-		storageProvider, err := createStorageProvider()
+		storageProvider, preview, err := createWriteProvider(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to create storage provider: %v", err)
+			return fmt.Errorf("failed to create storage provider: %w", err)
 		}
 
 		// Create the log entry
@@ -149,6 +234,7 @@ func runLogEntry(entryType string) func(cmd *cobra.Command, args []string) error
 			Description: description,
 			Tags:        tags,
 			Location:    location,
+			Visibility:  visibility,
 		}
 
 		if status > 0 {
@@ -160,203 +246,271 @@ func runLogEntry(entryType string) func(cmd *cobra.Command, args []string) error
 		if duration > 0 {
 			createReq.Duration = &duration
 		}
+		if !meeting.IsEmpty() {
+			createReq.Metadata = meeting.Metadata(createReq.Metadata)
+		}
+		if planned {
+			if createReq.Metadata == nil {
+				createReq.Metadata = make(map[string]string)
+			}
+			createReq.Metadata[storage.MetadataPlanned] = "true"
+		}
+		if project = strings.TrimSpace(project); project != "" {
+			if createReq.Metadata == nil {
+				createReq.Metadata = make(map[string]string)
+			}
+			createReq.Metadata[storage.MetadataProject] = project
+		}
 
 		entry, err := storageProvider.CreateEntry(createReq)
+		var duplicate storage.DuplicateError
+		if errors.As(err, &duplicate) {
+			return outputSkippedDuplicate(&duplicate.Existing)
+		}
 		if err != nil {
-			return fmt.Errorf("failed to create entry: %v", err)
+			return fmt.Errorf("failed to create entry: %w", err)
 		}
 
-		// Output result
-		outputFormat := viper.GetString("output.format")
-		switch outputFormat {
-		case "json":
-			return outputJSON(entry)
-		case "yaml":
-			return outputYAML(entry)
-		default:
-			fmt.Printf("✓ Created %s entry: %s\n", entryType, entry.Title)
-			fmt.Printf("  ID: %s\n", entry.ID)
-			fmt.Printf("  Date: %s\n", entryDate.Format("2006-01-02"))
-			fmt.Printf("  Time: %s\n", entry.Timestamp.Format("15:04:05"))
-			if len(entry.Tags) > 0 {
-				fmt.Printf("  Tags: %s\n", strings.Join(entry.Tags, ", "))
-			}
-			if entry.Status > 0 {
-				fmt.Printf("  Status: %d/10\n", entry.Status)
-			}
-			if entry.Priority > 0 {
-				fmt.Printf("  Priority: %d/5\n", entry.Priority)
-			}
-			if entry.Duration != nil && *entry.Duration > 0 {
-				fmt.Printf("  Duration: %d minutes\n", *entry.Duration)
-			}
-			if entry.Location != "" {
-				fmt.Printf("  Location: %s\n", entry.Location)
-			}
+		if err := outputCreatedEntry(entry, preview != nil); err != nil {
+			return err
 		}
+		reportDryRun(preview)
+		return nil
+	}
+}
+
+// outputCreatedEntry reports a newly created entry in the configured output
+// format, or the entry a dry run would have created
+func outputCreatedEntry(entry *storage.DailyLogEntry, dryRun bool) error {
+	if ok, err := outputStructured(entry); ok {
+		return err
+	}
 
+	if quiet() {
+		fmt.Println(entry.ID)
 		return nil
 	}
+	if dryRun {
+		fmt.Printf("Would create %s entry: %s\n", entry.Type, entry.Title)
+	} else {
+		fmt.Printf("✓ Created %s entry: %s\n", entry.Type, entry.Title)
+	}
+	printEntryDetails(entry)
+
+	return nil
 }
 
-// parseFlexibleDateTime parses various datetime formats, similar to GNU date
-func parseFlexibleDateTime(input string) (time.Time, error) {
-	input = strings.TrimSpace(input)
-	now := time.Now()
-	
-	// Try common datetime formats first
-	formats := []string{
-		"2006-01-02 15:04:05",     // YYYY-MM-DD HH:MM:SS
-		"2006-01-02 15:04",        // YYYY-MM-DD HH:MM
-		"2006-01-02T15:04:05",     // ISO format with T
-		"2006-01-02T15:04",        // ISO format with T, no seconds
-		"01/02/2006 15:04:05",     // MM/DD/YYYY HH:MM:SS
-		"01/02/2006 15:04",        // MM/DD/YYYY HH:MM
-		"01/02/2006 3:04 PM",      // MM/DD/YYYY H:MM PM
-		"01/02/2006 3:04PM",       // MM/DD/YYYY H:MMPM
-		"2006-01-02 3:04 PM",      // YYYY-MM-DD H:MM PM
-		"2006-01-02 3:04PM",       // YYYY-MM-DD H:MMPM
-		"Jan 2, 2006 15:04",       // Month DD, YYYY HH:MM
-		"Jan 2, 2006 3:04 PM",     // Month DD, YYYY H:MM PM
-		"2 Jan 2006 15:04",        // DD Month YYYY HH:MM
-		"2 Jan 2006 3:04 PM",      // DD Month YYYY H:MM PM
-		"15:04",                   // HH:MM (today)
-		"3:04 PM",                 // H:MM PM (today)
-		"3:04PM",                  // H:MMPM (today)
-	}
-	
-	for _, format := range formats {
-		if t, err := time.Parse(format, input); err == nil {
-			// If no date specified (time only), use today
-			if format == "15:04" || format == "3:04 PM" || format == "3:04PM" {
-				return time.Date(now.Year(), now.Month(), now.Day(), 
-					t.Hour(), t.Minute(), t.Second(), 0, now.Location()), nil
-			}
-			// For other formats, ensure we use the local timezone
-			return time.Date(t.Year(), t.Month(), t.Day(), 
-				t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), now.Location()), nil
-		}
+// outputSkippedDuplicate reports the existing entry a new one duplicated
+// instead of being created, in the configured output format
+func outputSkippedDuplicate(existing *storage.DailyLogEntry) error {
+	if ok, err := outputStructured(existing); ok {
+		return err
 	}
-	
-	// Handle relative time expressions
-	lower := strings.ToLower(input)
-	
-	// "now", "today"
-	if lower == "now" || lower == "today" {
-		return now, nil
-	}
-	
-	// "yesterday", "tomorrow"
-	if lower == "yesterday" {
-		return now.AddDate(0, 0, -1), nil
-	}
-	if lower == "tomorrow" {
-		return now.AddDate(0, 0, 1), nil
-	}
-	
-	// "X hours ago", "X minutes ago", "X days ago"
-	if matched, err := parseRelativeTime(input, now); matched {
-		return err, nil
-	}
-	
-	// "yesterday 3pm", "tomorrow 9am"
-	if strings.Contains(lower, "yesterday") || strings.Contains(lower, "tomorrow") {
-		parts := strings.Fields(lower)
-		if len(parts) >= 2 {
-			var baseDate time.Time
-			if strings.Contains(parts[0], "yesterday") {
-				baseDate = now.AddDate(0, 0, -1)
-			} else {
-				baseDate = now.AddDate(0, 0, 1)
-			}
-			
-			// Try to parse the time part
-			timeStr := strings.Join(parts[1:], " ")
-			if t, err := parseTimeString(timeStr); err == nil {
-				return time.Date(baseDate.Year(), baseDate.Month(), baseDate.Day(),
-					t.Hour(), t.Minute(), 0, 0, now.Location()), nil
-			}
-		}
+
+	if quiet() {
+		fmt.Println(existing.ID)
+		return nil
+	}
+	fmt.Printf("Skipped duplicate of existing %s entry: %s\n", existing.Type, existing.Title)
+	printEntryDetails(existing)
+	return nil
+}
+
+// printEntryDetails prints an entry's ID, time, and the fields that are set
+func printEntryDetails(entry *storage.DailyLogEntry) {
+	fmt.Printf("  ID: %s\n", entry.ID)
+	fmt.Printf("  Date: %s\n", entry.Timestamp.Format("2006-01-02"))
+	fmt.Printf("  Time: %s\n", entry.Timestamp.Format("15:04:05"))
+	if len(entry.Tags) > 0 {
+		fmt.Printf("  Tags: %s\n", strings.Join(entry.Tags, ", "))
+	}
+	if entry.Status > 0 {
+		fmt.Printf("  Status: %d/10\n", entry.Status)
+	}
+	if entry.Priority > 0 {
+		fmt.Printf("  Priority: %d/5\n", entry.Priority)
+	}
+	if entry.Duration != nil && *entry.Duration > 0 {
+		fmt.Printf("  Duration: %d minutes\n", *entry.Duration)
+	}
+	if entry.Location != "" {
+		fmt.Printf("  Location: %s\n", entry.Location)
+	}
+	if entry.Visibility != "" {
+		fmt.Printf("  Visibility: %s\n", entry.Visibility)
+	}
+	if entry.Author != "" {
+		fmt.Printf("  Author: %s\n", entry.Author)
+	}
+	if entry.Pinned {
+		fmt.Println("  Pinned: yes")
+	}
+	if entry.Reviewed() {
+		fmt.Printf("  Reviewed: %s\n", entry.ReviewedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if entry.Type == storage.EntryTypeMeeting {
+		printMeetingDetails(storage.MeetingOf(*entry))
+	}
+}
+
+// printMeetingDetails prints a meeting's attendees, decisions, and action items
+func printMeetingDetails(meeting storage.Meeting) {
+	if len(meeting.Attendees) > 0 {
+		fmt.Printf("  Attendees: %s\n", strings.Join(meeting.Attendees, ", "))
+	}
+	for _, decision := range meeting.Decisions {
+		fmt.Printf("  Decision: %s\n", decision)
+	}
+	for _, item := range meeting.ActionItems {
+		fmt.Printf("  Action: %s\n", item)
 	}
-	
-	return time.Time{}, fmt.Errorf("unable to parse datetime: %s", input)
 }
 
-// parseRelativeTime handles "X hours ago", "X minutes ago", etc.
-func parseRelativeTime(input string, now time.Time) (bool, time.Time) {
-	// Regex for "X units ago" or "X units from now"
-	re := regexp.MustCompile(`(\d+)\s+(second|minute|hour|day|week|month|year)s?\s+(ago|from\s+now)`)
-	matches := re.FindStringSubmatch(strings.ToLower(input))
-	
-	if len(matches) != 4 {
-		return false, time.Time{}
-	}
-	
-	amount, err := strconv.Atoi(matches[1])
+func createStorageProvider() (storage.DailyLogStorage, error) {
+	provider, err := createBaseProvider()
+	if err != nil {
+		return nil, err
+	}
+	linked, err := withIssueLinks(provider)
 	if err != nil {
-		return false, time.Time{}
-	}
-	
-	unit := matches[2]
-	direction := matches[3]
-	
-	if direction == "ago" {
-		amount = -amount
-	}
-	
-	switch unit {
-	case "second":
-		return true, now.Add(time.Duration(amount) * time.Second)
-	case "minute":
-		return true, now.Add(time.Duration(amount) * time.Minute)
-	case "hour":
-		return true, now.Add(time.Duration(amount) * time.Hour)
-	case "day":
-		return true, now.AddDate(0, 0, amount)
-	case "week":
-		return true, now.AddDate(0, 0, amount*7)
-	case "month":
-		return true, now.AddDate(0, amount, 0)
-	case "year":
-		return true, now.AddDate(amount, 0, 0)
-	}
-	
-	return false, time.Time{}
+		return nil, err
+	}
+	return withSentiment(withWeather(linked)), nil
 }
 
-// parseTimeString parses time strings like "3pm", "14:30", "9:15am"
-func parseTimeString(input string) (time.Time, error) {
-	timeFormats := []string{
-		"15:04",     // 24-hour
-		"3:04 PM",   // 12-hour with space
-		"3:04PM",    // 12-hour without space
-		"3PM",       // hour only with PM
-		"15",        // hour only 24-hour
-	}
-	
-	for _, format := range timeFormats {
-		if t, err := time.Parse(format, input); err == nil {
-			return t, nil
+// createBaseProvider creates the storage chain without the tagging applied to
+// new entries, running the hooks around entry writes
+func createBaseProvider() (storage.DailyLogStorage, error) {
+	provider, err := createBackendProvider()
+	if err != nil {
+		return nil, err
+	}
+	hooks, err := entryHooks()
+	if err != nil {
+		return nil, err
+	}
+	return providers.NewHookedProvider(provider, hooks...), nil
+}
+
+// entryHooks returns the hooks run around entry writes: validation,
+// attribution to the configured author, then the script hooks configured
+// under hooks
+func entryHooks() ([]providers.Hook, error) {
+	scripts, err := config.Hooks(viper.GetViper())
+	if err != nil {
+		return nil, err
+	}
+	hooks := []providers.Hook{providers.ValidationHook()}
+	if author := config.Author(viper.GetViper()); author != "" {
+		hooks = append(hooks, providers.AuthorHook(author))
+	}
+	for _, script := range scripts {
+		hooks = append(hooks, script.Hook(func(name string, err error) {
+			fmt.Fprintf(os.Stderr, "⚠ Hook %s failed: %v\n", name, err)
+		}))
+	}
+	return hooks, nil
+}
+
+// createBackendProvider creates the storage chain: the configured storage,
+// mirrored and queued for offline use as configured
+func createBackendProvider() (storage.DailyLogStorage, error) {
+	if storageConfig := config.Storage(viper.GetViper()); storageConfig.StorageType == storage.StorageTypeMemory {
+		return createMemoryProvider(storageConfig)
+	}
+
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	var provider storage.DailyLogStorage = primary
+
+	mirror, err := createMirrorStore()
+	if err != nil {
+		return nil, err
+	}
+	if mirror != nil {
+		provider = newMirroredProvider(provider, mirror)
+	}
+
+	if viper.GetBool("offline.enabled") {
+		queue, err := newOfflineQueue(provider)
+		if err != nil {
+			return nil, err
 		}
+		provider = queue
 	}
-	
-	return time.Time{}, fmt.Errorf("unable to parse time: %s", input)
+	return provider, nil
 }
 
-func createStorageProvider() (storage.DailyLogStorage, error) {
-	config := storage.Config{
-		StorageType: "github",
-		GitHubRepo:  viper.GetString("github.repo"),
-		GitHubToken: viper.GetString("github.token"),
-		GitHubPath:  viper.GetString("github.path"),
+// withIssueLinks links new entries to the issues their titles mention when
+// issues.trackers or jira.url is set
+func withIssueLinks(backend storage.DailyLogStorage) (storage.DailyLogStorage, error) {
+	trackers, err := config.Issues(viper.GetViper())
+	if err != nil {
+		return nil, err
 	}
+	if len(trackers) == 0 {
+		return backend, nil
+	}
+	return providers.NewIssueLinkProvider(backend, trackers), nil
+}
 
-	if config.GitHubRepo == "" {
-		return nil, fmt.Errorf("GitHub repository not configured (use --github-repo or set DAILYLOG_GITHUB_REPO)")
+// withSentiment tags new entries with their sentiment when sentiment.enabled is set
+func withSentiment(backend storage.DailyLogStorage) storage.DailyLogStorage {
+	if !viper.GetBool("sentiment.enabled") {
+		return backend
 	}
-	if config.GitHubToken == "" {
-		return nil, fmt.Errorf("GitHub token not configured (use --github-token or set DAILYLOG_GITHUB_TOKEN)")
+	provider := providers.NewSentimentProvider(backend, sentiment.NewLexicon(), config.List(viper.GetViper(), "sentiment.types"))
+	provider.OnError = func(err error) {
+		fmt.Fprintf(os.Stderr, "⚠ Sentiment analysis failed: %v\n", err)
+	}
+	return provider
+}
+
+// createPrimaryProvider creates the GitHub provider that holds the authoritative copy of the logs
+func createPrimaryProvider() (*providers.GitHubStorageProvider, error) {
+	storageConfig := config.Storage(viper.GetViper())
+	if storageConfig.GitHubRepo == "" {
+		return nil, fmt.Errorf("GitHub repository %w (use --github-repo or set DAILYLOG_GITHUB_REPO)", errNotConfigured)
 	}
+	if storageConfig.GitHubToken == "" && !providers.Replaying() {
+		return nil, fmt.Errorf("GitHub token %w (use --github-token or set DAILYLOG_GITHUB_TOKEN)", errNotConfigured)
+	}
+
+	provider, err := providers.NewGitHubStorageProvider(storageConfig)
+	if err != nil {
+		return nil, err
+	}
+	provider.OnPullRequestOpened = func(url string) {
+		fmt.Fprintf(os.Stderr, "Opened pull request %s\n", url)
+	}
+	provider.OnPullRequestError = func(err error) {
+		fmt.Fprintf(os.Stderr, "⚠ Failed to open a pull request: %v\n", err)
+	}
+	return provider, nil
+}
+
+// createMemoryProvider creates in-memory storage, seeded with sample data in
+// demo mode. Nothing is mirrored or queued, as nothing outlives the command.
+func createMemoryProvider(storageConfig storage.Config) (storage.DailyLogStorage, error) {
+	provider := providers.NewMemoryStorageProvider(storageConfig)
+	if viper.GetBool("demo") {
+		if err := providers.SeedDemo(provider, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	return provider, nil
+}
+
+// commitConfig builds the commit message templates, identity, and signing
+// settings from the commit.* configuration
+func commitConfig() storage.CommitConfig {
+	return config.Commit(viper.GetViper())
+}
 
-	return providers.NewGitHubStorageProvider(config)
+// visibilityPolicy builds the audience filtering policy from the privacy.* configuration
+func visibilityPolicy() storage.VisibilityPolicy {
+	return config.Visibility(viper.GetViper())
 }
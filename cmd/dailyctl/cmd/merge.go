@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/storage"
+)
+
+// MergeResult describes entries merged into one
+type MergeResult struct {
+	Entry  storage.DailyLogEntry `json:"entry" yaml:"entry"`
+	Merged []string              `json:"merged" yaml:"merged"`
+	DryRun bool                  `json:"dry_run" yaml:"dry_run"`
+}
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <id> <id>...",
+	Short: "Merge several entries of a day into one",
+	Long: `Merge several small entries logged on the same day into one, saved in a
+single commit.
+
+The earliest entry is kept, with its ID, time, and type. The other entries'
+titles and descriptions are added to its description, their tags are combined,
+their durations added up, and their statuses averaged. The kept entry lists the
+merged IDs in merged_from metadata, and task notes linked to a merged entry are
+relinked to it.
+
+Examples:
+  dailyctl merge 01J3F2QK 01J3F2RM 01J3F2TB
+  dailyctl merge 01J3F2QK 01J3F2RM --title "Code review" --dry-run`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMerge,
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+
+	mergeCmd.Flags().String("title", "", "Title of the merged entry (default: the earliest entry's)")
+	mergeCmd.Flags().String("date", "", "Day the entries are logged on (YYYY-MM-DD or e.g. \"yesterday\")")
+	mergeCmd.Flags().Bool("dry-run", false, dryRunUsage)
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	title, _ := cmd.Flags().GetString("title")
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	session, err := beginEntryEdit(cmd, storageProvider, args[0])
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	for _, id := range args {
+		entry, err := storage.ResolveEntryID(id, session.Day().Entries)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, entry.ID)
+	}
+
+	merged, err := session.Merge(ids, title)
+	if err != nil {
+		return err
+	}
+	if err := session.Commit(); err != nil {
+		return fmt.Errorf("failed to save merged entry: %w", err)
+	}
+
+	result := MergeResult{Entry: *merged, Merged: []string{}, DryRun: preview != nil}
+	for _, id := range ids {
+		if id != merged.ID {
+			result.Merged = append(result.Merged, id)
+		}
+	}
+	if err := outputMergeResult(result); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
+
+func outputMergeResult(result MergeResult) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	if quiet() {
+		fmt.Println(result.Entry.ID)
+		return nil
+	}
+	if result.DryRun {
+		fmt.Printf("Would merge %s into %s entry: %s\n", strings.Join(result.Merged, ", "), result.Entry.Type, result.Entry.Title)
+	} else {
+		fmt.Printf("✓ Merged %s into %s entry: %s\n", strings.Join(result.Merged, ", "), result.Entry.Type, result.Entry.Title)
+	}
+	printEntryDetails(&result.Entry)
+	return nil
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// metricCmd represents the metric command
+var metricCmd = &cobra.Command{
+	Use:   "metric <name> <value>",
+	Short: "Log a health metric such as sleep, steps, or weight",
+	Long: `Log a numeric health metric as a "metric" entry. The value takes an optional
+unit; sleep is kept in hours, steps in steps, and weight in kilograms, with
+other units converted, and any other metric keeps the unit it is given.
+
+Metrics are rolled up in stats and summaries (a day's steps add up, other
+metrics average out), and analyze_status correlates them with mood.
+"dailyctl analyze sleep" sets sleep against the next day's mood and tracked
+time.
+
+Examples:
+  dailyctl metric sleep 7.5h
+  dailyctl metric sleep 7h20m --date yesterday
+  dailyctl metric steps 9400
+  dailyctl metric weight 181lb
+  dailyctl metric water 2.5l --note "Hot day"`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return storage.KnownMetrics(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: runMetric,
+}
+
+func init() {
+	rootCmd.AddCommand(metricCmd)
+
+	metricCmd.Flags().String("date", "", "Date for the entry (YYYY-MM-DD or e.g. \"yesterday\", defaults to today)")
+	metricCmd.Flags().String("datetime", "", "Date and time for the entry (e.g. '2025-09-29 07:00', 'yesterday 10pm')")
+	metricCmd.Flags().String("note", "", "Note to keep with the value")
+	metricCmd.Flags().StringSlice("tags", []string{}, "Tags for categorization")
+	metricCmd.Flags().String("visibility", "", "Visibility: private, team, public (defaults to privacy.default, or team)")
+	metricCmd.Flags().Bool("dry-run", false, dryRunUsage)
+	metricCmd.Flags().String("duplicates", "", duplicatesUsage)
+	_ = metricCmd.RegisterFlagCompletionFunc("tags", completeTags)
+	metricCmd.MarkFlagsMutuallyExclusive("date", "datetime")
+}
+
+func runMetric(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	datetimeStr, _ := cmd.Flags().GetString("datetime")
+	note, _ := cmd.Flags().GetString("note")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	visibility, _ := cmd.Flags().GetString("visibility")
+
+	metric, err := storage.ParseMetric(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if err := storage.ValidateVisibility(visibility); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entryDate := now
+	switch {
+	case datetimeStr != "":
+		entryDate, err = datetime.Parse(datetimeStr, now)
+		if err != nil {
+			return invalidArgf("invalid datetime format: %s (%w)", datetimeStr, err)
+		}
+	case dateStr != "":
+		day, err := datetime.ParseDate(dateStr, now)
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+		}
+		entryDate = time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), 0, now.Location())
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	entry, err := storageProvider.CreateEntry(storage.CreateLogEntryRequest{
+		Date:        entryDate,
+		Type:        storage.EntryTypeMetric,
+		Title:       metric.Title(),
+		Description: strings.TrimSpace(note),
+		Tags:        tags,
+		Visibility:  visibility,
+		Metadata:    metric.Metadata(nil),
+	})
+	var duplicate storage.DuplicateError
+	if errors.As(err, &duplicate) {
+		return outputSkippedDuplicate(&duplicate.Existing)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	if err := outputCreatedEntry(entry, preview != nil); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// MigrateLayoutResult lists the day files moved to another layout
+type MigrateLayoutResult struct {
+	Layout string                 `json:"layout" yaml:"layout"`
+	Moves  []providers.LayoutMove `json:"moves" yaml:"moves"`
+	DryRun bool                   `json:"dry_run" yaml:"dry_run"`
+}
+
+// migrateLayoutCmd represents the migrate-layout command
+var migrateLayoutCmd = &cobra.Command{
+	Use:   "migrate-layout",
+	Short: "Move day files into another directory layout",
+	Long: `Move the day files in the repository into another directory layout, in
+batches of files per commit. Layouts, shown for 2025-09-29:
+
+  monthly    2025/09/2025-09-29.json (the default)
+  quarterly  2025/Q3/2025-09-29.json
+  weekly     2025/W40/2025-09-29.json (ISO weeks)
+  yearly     2025/2025-09-29.json
+  flat       2025-09-29.json
+
+Files keep their content and format. Once the move is done, set github.layout
+(or DAILYLOG_LAYOUT) to the new layout so days are read from and written to
+it; until then other clients will not see the moved days. If a run is
+interrupted, run it again to move the rest. restore --history only follows a
+day's file back to when it was moved.
+
+Examples:
+  dailyctl migrate-layout --to quarterly --dry-run
+  dailyctl migrate-layout --to quarterly
+  dailyctl migrate-layout --to flat --batch-size 500`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateLayout,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateLayoutCmd)
+
+	migrateLayoutCmd.Flags().String("to", "", "Layout to move day files into: monthly, quarterly, weekly, yearly, flat")
+	migrateLayoutCmd.Flags().Int("batch-size", providers.DefaultLayoutBatchSize, "Day files to move per commit")
+	migrateLayoutCmd.Flags().Bool("dry-run", false, "List the files that would be moved without moving them")
+
+	_ = migrateLayoutCmd.MarkFlagRequired("to")
+	_ = migrateLayoutCmd.RegisterFlagCompletionFunc("to", cobra.FixedCompletions(
+		[]string{storage.LayoutMonthly, storage.LayoutQuarterly, storage.LayoutWeekly, storage.LayoutYearly, storage.LayoutFlat},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+}
+
+func runMigrateLayout(cmd *cobra.Command, args []string) error {
+	layout, _ := cmd.Flags().GetString("to")
+	batchSize, _ := cmd.Flags().GetInt("batch-size")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := storage.ValidateLayout(layout); err != nil {
+		return err
+	}
+	if batchSize < 1 {
+		return invalidArgf("--batch-size must be at least 1")
+	}
+
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	moves, err := primary.MigrateLayout(layout, batchSize, dryRun, func(moved, total int) {
+		if !quiet() {
+			fmt.Fprintf(os.Stderr, "Moved %d of %d files\n", moved, total)
+		}
+	})
+	if err != nil {
+		if len(moves) > 0 {
+			return fmt.Errorf("moved %d files before failing: %w", len(moves), err)
+		}
+		return err
+	}
+
+	result := MigrateLayoutResult{Layout: layout, Moves: moves, DryRun: dryRun}
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	switch {
+	case len(moves) == 0:
+		fmt.Printf("All day files are already in the %s layout\n", layout)
+	case dryRun:
+		fmt.Printf("Would move %d files to the %s layout:\n", len(moves), layout)
+		for _, move := range moves {
+			fmt.Printf("  %s -> %s\n", move.From, move.To)
+		}
+	default:
+		fmt.Printf("✓ Moved %d files to the %s layout\n", len(moves), layout)
+		fmt.Printf("Set github.layout to %s to use it\n", layout)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// verifyMirrorCmd represents the verify-mirror command
+var verifyMirrorCmd = &cobra.Command{
+	Use:   "verify-mirror",
+	Short: "Compare the mirror with the primary storage",
+	Long: `When a mirror is configured (mirror.path for a local directory, or
+mirror.repo for a second GitHub repository), every write to GitHub is also
+replicated to the mirror in the background. verify-mirror lists days that are
+missing from the mirror, differ from the primary, or exist only in the mirror.
+
+With --repair, missing and differing days are copied from the primary.
+Days that exist only in the mirror are reported but never deleted.
+
+Examples:
+  dailyctl verify-mirror
+  dailyctl verify-mirror --date-start 2025-09-01 --date-end 2025-09-30
+  dailyctl verify-mirror --repair`,
+	RunE: runVerifyMirror,
+}
+
+// openMirrors tracks mirrored providers so pending replication can finish before exit
+var openMirrors []*providers.MirroredProvider
+
+func init() {
+	rootCmd.AddCommand(verifyMirrorCmd)
+
+	verifyMirrorCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD), default: all days")
+	verifyMirrorCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD), default: all days")
+	verifyMirrorCmd.Flags().Bool("repair", false, "Copy missing and differing days from the primary to the mirror")
+}
+
+func runVerifyMirror(cmd *cobra.Command, args []string) error {
+	repair, _ := cmd.Flags().GetBool("repair")
+	start, end, err := parseMirrorRange(cmd)
+	if err != nil {
+		return err
+	}
+
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	mirror, err := createMirrorStore()
+	if err != nil {
+		return err
+	}
+	if mirror == nil {
+		return fmt.Errorf("no mirror configured (set mirror.path or mirror.repo)")
+	}
+
+	report, err := providers.VerifyMirror(primary, mirror, start, end)
+	if err != nil {
+		return fmt.Errorf("failed to verify mirror: %w", err)
+	}
+
+	repaired := 0
+	if repair {
+		for _, day := range append(append([]string{}, report.MissingInMirror...), report.Different...) {
+			date, _ := time.Parse("2006-01-02", day)
+			if err := providers.CopyDay(primary, mirror, date); err != nil {
+				return fmt.Errorf("failed to repair %s: %w", day, err)
+			}
+			repaired++
+		}
+	}
+
+	if ok, err := outputStructured(report); ok {
+		return err
+	}
+
+	printMirrorReport(report, repaired)
+
+	if !report.InSync() && !repair {
+		return fmt.Errorf("mirror is out of sync")
+	}
+	return nil
+}
+
+func printMirrorReport(report *providers.MirrorReport, repaired int) {
+	fmt.Printf("Checked %d days\n", report.Checked)
+	for _, day := range report.MissingInMirror {
+		fmt.Printf("  missing    %s\n", day)
+	}
+	for _, day := range report.Different {
+		fmt.Printf("  different  %s\n", day)
+	}
+	for _, day := range report.ExtraInMirror {
+		fmt.Printf("  extra      %s\n", day)
+	}
+
+	switch {
+	case report.InSync():
+		fmt.Println("✓ Mirror is in sync")
+	case repaired > 0:
+		fmt.Printf("✓ Repaired %d days\n", repaired)
+	}
+}
+
+func parseMirrorRange(cmd *cobra.Command) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+
+	if s, _ := cmd.Flags().GetString("date-start"); s != "" {
+		if start, err = time.Parse("2006-01-02", s); err != nil {
+			return start, end, fmt.Errorf("invalid start date format: %w", err)
+		}
+	}
+	if s, _ := cmd.Flags().GetString("date-end"); s != "" {
+		if end, err = time.Parse("2006-01-02", s); err != nil {
+			return start, end, fmt.Errorf("invalid end date format: %w", err)
+		}
+	}
+	return start, end, nil
+}
+
+// createMirrorStore creates the configured mirror, or returns nil if none is configured
+func createMirrorStore() (storage.DayStore, error) {
+	if mirrorPath := viper.GetString("mirror.path"); mirrorPath != "" {
+		return providers.NewLocalDayStore(mirrorPath)
+	}
+
+	if mirrorRepo := viper.GetString("mirror.repo"); mirrorRepo != "" {
+		token := viper.GetString("mirror.token")
+		if token == "" {
+			token = viper.GetString("github.token")
+		}
+		// A mirror on another GitHub host sets its own URLs
+		baseURL, uploadURL := viper.GetString("mirror.base_url"), viper.GetString("mirror.upload_url")
+		if baseURL == "" {
+			baseURL, uploadURL = viper.GetString("github.base_url"), viper.GetString("github.upload_url")
+		}
+		return providers.NewGitHubStorageProvider(storage.Config{
+			StorageType:     "github",
+			GitHubRepo:      mirrorRepo,
+			GitHubToken:     token,
+			GitHubPath:      viper.GetString("github.path"),
+			GitHubBaseURL:   baseURL,
+			GitHubUploadURL: uploadURL,
+			DayFormat:       viper.GetString("github.day_format"),
+			Layout:          viper.GetString("github.layout"),
+			Commit:          commitConfig(),
+		})
+	}
+
+	return nil, nil
+}
+
+// newMirroredProvider wraps a provider so writes are replicated to the mirror
+func newMirroredProvider(primary storage.DailyLogStorage, mirror storage.DayStore) *providers.MirroredProvider {
+	mirrored := providers.NewMirroredProvider(primary, mirror)
+	mirrored.OnMirrorError = func(date time.Time, err error) {
+		fmt.Fprintf(os.Stderr, "⚠ Failed to mirror %s: %v\n", date.Format("2006-01-02"), err)
+	}
+	openMirrors = append(openMirrors, mirrored)
+	return mirrored
+}
+
+// closeMirrors waits for background replication started by this command to finish
+func closeMirrors() {
+	failed := false
+	for _, mirrored := range openMirrors {
+		if err := mirrored.Close(); err != nil {
+			failed = true
+		}
+	}
+	openMirrors = nil
+
+	if failed {
+		fmt.Fprintln(os.Stderr, "⚠ Mirror is out of date; run 'dailyctl verify-mirror --repair'")
+	}
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// MoveResult describes an entry moved or copied to another day
+type MoveResult struct {
+	From   string                `json:"from" yaml:"from"`
+	To     string                `json:"to" yaml:"to"`
+	Entry  storage.DailyLogEntry `json:"entry" yaml:"entry"`
+	Copy   bool                  `json:"copy" yaml:"copy"`
+	DryRun bool                  `json:"dry_run" yaml:"dry_run"`
+}
+
+// moveCmd represents the move command
+var moveCmd = &cobra.Command{
+	Use:   "move <id>",
+	Short: "Move or copy an entry to another day",
+	Long: `Move an entry logged against the wrong date to the right one. The entry
+keeps its ID, time of day, tags, and metadata; only its date changes. With
+--copy the original stays where it is and the copy gets a new ID.
+
+The entry is found by ID or ID prefix like show does; pass --from with its day
+if the prefix alone does not find it. It is written to its new day before it
+is removed from the old one, so an interrupted move never loses it.
+
+A moved entry's ID still encodes its old date, so look it up with
+show --date once it is more than a day away.
+
+Examples:
+  dailyctl move 01J3F2QK --to 2025-09-30
+  dailyctl move 01J3F2QK --from 2025-09-29 --to yesterday
+  dailyctl move 01J3F2QK --to 2025-09-30 --copy --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMove,
+}
+
+func init() {
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.Flags().String("from", "", "Day the entry is logged on (YYYY-MM-DD or e.g. \"yesterday\")")
+	moveCmd.Flags().String("to", "", "Day to move the entry to (YYYY-MM-DD or e.g. \"yesterday\")")
+	moveCmd.Flags().Bool("copy", false, "Copy the entry, with a new ID, instead of moving it")
+	moveCmd.Flags().Bool("dry-run", false, dryRunUsage)
+
+	_ = moveCmd.MarkFlagRequired("to")
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+	copyEntry, _ := cmd.Flags().GetBool("copy")
+
+	to, err := datetime.ParseDate(toStr, time.Now())
+	if err != nil {
+		return invalidArgf("invalid --to date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", toStr)
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	var from time.Time
+	if fromStr != "" {
+		from, err = datetime.ParseDate(fromStr, time.Now())
+		if err != nil {
+			return invalidArgf("invalid --from date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", fromStr)
+		}
+	} else {
+		entry, err := storage.FindEntry(storageProvider, id)
+		if _, notFound := err.(storage.NotFoundError); notFound {
+			return fmt.Errorf("%w (pass --from with the entry's day)", err)
+		}
+		if err != nil {
+			return err
+		}
+		id, from = entry.ID, entry.Timestamp
+	}
+
+	relocate := storage.MoveEntry
+	if copyEntry {
+		relocate = storage.CopyEntry
+	}
+	entry, err := relocate(storageProvider, id, from, to)
+	if err != nil {
+		return err
+	}
+
+	result := MoveResult{
+		From:   from.Format("2006-01-02"),
+		To:     to.Format("2006-01-02"),
+		Entry:  *entry,
+		Copy:   copyEntry,
+		DryRun: preview != nil,
+	}
+	if err := outputMoveResult(result); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
+
+func outputMoveResult(result MoveResult) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	if quiet() {
+		fmt.Println(result.Entry.ID)
+		return nil
+	}
+	action := "✓ Moved"
+	switch {
+	case result.DryRun && result.Copy:
+		action = "Would copy"
+	case result.DryRun:
+		action = "Would move"
+	case result.Copy:
+		action = "✓ Copied"
+	}
+	fmt.Printf("%s %s entry %s from %s to %s: %s\n", action, result.Entry.Type,
+		result.Entry.ID, result.From, result.To, result.Entry.Title)
+	return nil
+}
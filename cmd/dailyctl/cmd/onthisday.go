@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// OnThisDayResult holds the entries logged on one earlier date
+type OnThisDayResult struct {
+	Date    string                  `json:"date" yaml:"date"`
+	Ago     string                  `json:"ago" yaml:"ago"`
+	Entries []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+}
+
+// onThisDayCmd represents the onthisday command
+var onThisDayCmd = &cobra.Command{
+	Use:   "onthisday",
+	Short: "Show entries from the same date in earlier years and months",
+	Long: `Show what was logged on the same calendar date in previous years, and
+optionally previous months, for reflection and to resurface old notes. Dates
+with nothing logged are left out.
+
+Examples:
+  dailyctl onthisday
+  dailyctl onthisday --years 10
+  dailyctl onthisday --months 6 --date 2025-09-30`,
+	Args: cobra.NoArgs,
+	RunE: runOnThisDay,
+}
+
+func init() {
+	rootCmd.AddCommand(onThisDayCmd)
+
+	onThisDayCmd.Flags().String("date", "", "Date to look back from (YYYY-MM-DD or e.g. \"tomorrow\"; default: today)")
+	onThisDayCmd.Flags().Int("years", storage.DefaultOnThisDayYears, "How many previous years to look at")
+	onThisDayCmd.Flags().Int("months", 0, "How many previous months to look at")
+}
+
+func runOnThisDay(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	years, _ := cmd.Flags().GetInt("years")
+	months, _ := cmd.Flags().GetInt("months")
+
+	if years < 0 || months < 0 {
+		return invalidArgf("--years and --months cannot be negative")
+	}
+
+	date := time.Now()
+	if dateStr != "" {
+		var err error
+		date, err = datetime.ParseDate(dateStr, time.Now())
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
+		}
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	results := []OnThisDayResult{}
+	for _, anniversary := range storage.OnThisDay(date, years, months) {
+		dayLog, err := storageProvider.GetDay(anniversary.Date)
+		if err != nil {
+			return fmt.Errorf("failed to get entries for %s: %w", anniversary.Date.Format("2006-01-02"), err)
+		}
+		if len(dayLog.Entries) == 0 {
+			continue
+		}
+		results = append(results, OnThisDayResult{
+			Date:    anniversary.Date.Format("2006-01-02"),
+			Ago:     anniversary.Ago,
+			Entries: dayLog.Entries,
+		})
+	}
+
+	if ok, err := outputStructured(results); ok {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No earlier entries on %s.\n", date.Format("January 2"))
+		return nil
+	}
+	for i, result := range results {
+		if i > 0 {
+			fmt.Println()
+		}
+		day, _ := time.Parse("2006-01-02", result.Date)
+		fmt.Printf("%s - %s\n", result.Ago, day.Format("Monday, 2006-01-02"))
+		for _, entry := range result.Entries {
+			fmt.Printf("  %s [%s] %s\n", entry.Timestamp.Format("15:04"), entry.Type, entry.Title)
+			if entry.Description != "" {
+				fmt.Printf("    %s\n", entry.Description)
+			}
+		}
+	}
+	return nil
+}
@@ -3,15 +3,89 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrorReport is how a failed command is reported with --output json or yaml
+type ErrorReport struct {
+	Error    string `json:"error" yaml:"error"`
+	Code     string `json:"code" yaml:"code"`
+	ExitCode int    `json:"exit_code" yaml:"exit_code"`
+}
+
+// outputStructured prints data as JSON or YAML when --output asks for it,
+// reporting false for the table format so the caller prints its own
+func outputStructured(data interface{}) (bool, error) {
+	switch viper.GetString("output.format") {
+	case "json":
+		return true, outputJSON(data)
+	case "yaml":
+		return true, outputYAML(data)
+	default:
+		return false, nil
+	}
+}
+
+// checkOutputFormat rejects unknown --output formats before a command runs,
+// and leaves usage out of JSON and YAML error reports
+func checkOutputFormat(cmd *cobra.Command, args []string) error {
+	switch format := viper.GetString("output.format"); format {
+	case "table", "":
+		return nil
+	case "json", "yaml":
+		cmd.SilenceUsage = true
+		return nil
+	default:
+		return invalidArgf("invalid output format: %s (use table, json, or yaml)", format)
+	}
+}
+
+// structuredOutput reports whether stdout carries JSON or YAML
+func structuredOutput() bool {
+	format := viper.GetString("output.format")
+	return format == "json" || format == "yaml"
+}
+
+// statusOut is where progress and status messages go: stdout for tables,
+// and stderr when stdout carries JSON or YAML, so that stays parseable
+func statusOut() io.Writer {
+	if structuredOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// PrintError reports a failed command on stderr, as JSON or YAML when that
+// is the output format
+func PrintError(err error) {
+	code := ExitCode(err)
+	report := ErrorReport{Error: err.Error(), Code: exitCodeNames[code], ExitCode: code}
+
+	var data []byte
+	switch viper.GetString("output.format") {
+	case "json":
+		data, _ = json.MarshalIndent(report, "", "  ")
+	case "yaml":
+		data, _ = yaml.Marshal(report)
+	}
+	if len(data) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, strings.TrimRight(string(data), "\n"))
+}
+
 // outputJSON outputs data as formatted JSON
 func outputJSON(data interface{}) error {
 	jsonData, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %v", err)
+		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 	fmt.Println(string(jsonData))
 	return nil
@@ -21,7 +95,7 @@ func outputJSON(data interface{}) error {
 func outputYAML(data interface{}) error {
 	yamlData, err := yaml.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("failed to marshal YAML: %v", err)
+		return fmt.Errorf("failed to marshal YAML: %w", err)
 	}
 	fmt.Println(string(yamlData))
 	return nil
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// terminal is the original stdout, which the pager writes to while
+// os.Stdout feeds the pager
+var terminal = os.Stdout
+
+// pager is the running pager, if any
+var pager struct {
+	cmd  *exec.Cmd
+	pipe *os.File
+}
+
+// pagerCommand returns the pager to use: pager in the config file or
+// DAILYLOG_PAGER, then $PAGER, then less. "cat" or an empty value disables it.
+func pagerCommand() string {
+	if command := viper.GetString("pager"); viper.IsSet("pager") {
+		return command
+	}
+	if command, ok := os.LookupEnv("PAGER"); ok {
+		return command
+	}
+	return "less"
+}
+
+// startPager sends the rest of stdout through the pager when it's a
+// terminal, as git does, unless --no-pager, --quiet, or output.no_pager is
+// set. With less, LESS defaults to FRX: quit if the output fits on one
+// screen, keep colors, and leave the output on screen.
+func startPager() {
+	if pager.cmd != nil || viper.GetBool("output.no_pager") || quiet() || !isTerminal() {
+		return
+	}
+	args := strings.Fields(pagerCommand())
+	if len(args) == 0 || args[0] == "cat" {
+		return
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = r
+	cmd.Stdout = terminal
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+
+	// A missing pager isn't worth failing over; print directly instead
+	if err := cmd.Start(); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		return
+	}
+	_ = r.Close()
+
+	pager.cmd, pager.pipe = cmd, w
+	os.Stdout = w
+}
+
+// stopPager waits for the user to finish with the pager and restores stdout
+func stopPager() {
+	if pager.cmd == nil {
+		return
+	}
+	_ = pager.pipe.Close()
+	os.Stdout = terminal
+	_ = pager.cmd.Wait()
+	pager.cmd, pager.pipe = nil, nil
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/exif"
+	"dailylog/internal/storage"
+)
+
+var logPhotoCmd = &cobra.Command{
+	Use:   "photo <image>",
+	Short: "Log a photo as a note",
+	Long: `Log a photo as a note entry, with the image attached: it is stored in the
+log repository under attachments/, and the entry's "attachment" metadata holds
+its path.
+
+The entry is logged at the time the photo was taken and where it was taken,
+as recorded in its EXIF data. The location is "latitude,longitude", which the
+weather lookup understands; the coordinates and the camera are also kept in
+the entry's metadata. Without EXIF data the file's modification time is used.
+--date, --datetime, and --location override what the photo says.
+
+The image is attached as it is, EXIF data and GPS position included: anyone
+who can read the log repository can see it, whatever the entry's visibility.
+
+Examples:
+  dailyctl log photo img.jpg --title "Hike"
+  dailyctl log photo summit.jpg --title "Summit" --tags hiking --visibility private
+  dailyctl log photo whiteboard.jpg --location office --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogPhoto,
+}
+
+func init() {
+	logCmd.AddCommand(logPhotoCmd)
+
+	logPhotoCmd.Flags().String("title", "", "Title for the entry (default: the file name)")
+	logPhotoCmd.Flags().String("description", "", "Description of the photo")
+	logPhotoCmd.Flags().StringSlice("tags", []string{}, "Tags for categorization")
+	logPhotoCmd.Flags().String("visibility", "", "Visibility: private, team, public (defaults to privacy.default, or team)")
+	logPhotoCmd.Flags().String("date", "", "Date for the entry (default: when the photo was taken)")
+	logPhotoCmd.Flags().String("datetime", "", "Date and time for the entry (default: when the photo was taken)")
+	logPhotoCmd.Flags().String("location", "", "Location (default: where the photo was taken)")
+	_ = logPhotoCmd.RegisterFlagCompletionFunc("tags", completeTags)
+	logPhotoCmd.MarkFlagsMutuallyExclusive("date", "datetime")
+}
+
+func runLogPhoto(cmd *cobra.Command, args []string) error {
+	imagePath := args[0]
+	title, _ := cmd.Flags().GetString("title")
+	description, _ := cmd.Flags().GetString("description")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	visibility, _ := cmd.Flags().GetString("visibility")
+	dateStr, _ := cmd.Flags().GetString("date")
+	datetimeStr, _ := cmd.Flags().GetString("datetime")
+	location, _ := cmd.Flags().GetString("location")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := storage.ValidateVisibility(visibility); err != nil {
+		return err
+	}
+	file, err := os.Open(imagePath)
+	if err != nil {
+		return invalidArgf("cannot read %s: %v", imagePath, err)
+	}
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		file.Close()
+		return invalidArgf("%s is not an image file", imagePath)
+	}
+	photo, err := exif.Read(file, time.Local)
+	file.Close()
+	if err != nil {
+		if !errors.Is(err, exif.ErrNoExif) {
+			return fmt.Errorf("failed to read %s: %w", imagePath, err)
+		}
+		if !quiet() {
+			fmt.Fprintf(os.Stderr, "⚠ %s has no EXIF data; using the file's modification time\n", imagePath)
+		}
+		photo = &exif.Info{}
+	}
+
+	metadata := map[string]string{}
+	if photo.Camera != "" {
+		metadata[storage.MetadataCamera] = photo.Camera
+	}
+	if photo.HasLocation {
+		latitude := strconv.FormatFloat(photo.Latitude, 'f', 5, 64)
+		longitude := strconv.FormatFloat(photo.Longitude, 'f', 5, 64)
+		metadata[storage.MetadataLatitude] = latitude
+		metadata[storage.MetadataLongitude] = longitude
+		if location == "" {
+			location = latitude + "," + longitude
+		}
+	}
+
+	now := time.Now()
+	entryDate := photo.Taken
+	if entryDate.IsZero() {
+		entryDate = info.ModTime()
+	}
+	switch {
+	case datetimeStr != "":
+		if entryDate, err = datetime.Parse(datetimeStr, now); err != nil {
+			return invalidArgf("invalid datetime format: %s (%w)", datetimeStr, err)
+		}
+	case dateStr != "":
+		day, err := datetime.ParseDate(dateStr, now)
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+		}
+		entryDate = time.Date(day.Year(), day.Month(), day.Day(), entryDate.Hour(), entryDate.Minute(), entryDate.Second(), 0, now.Location())
+	}
+
+	if strings.TrimSpace(title) == "" {
+		title = strings.TrimSuffix(filepath.Base(imagePath), filepath.Ext(imagePath))
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	if metadata[storage.MetadataAttachment], err = attachFile(imagePath, entryDate, dryRun); err != nil {
+		return err
+	}
+
+	entry, err := storageProvider.CreateEntry(storage.CreateLogEntryRequest{
+		Date:        entryDate,
+		Type:        "note",
+		Title:       title,
+		Description: description,
+		Tags:        tags,
+		Location:    location,
+		Visibility:  visibility,
+		Metadata:    metadata,
+	})
+	var duplicate storage.DuplicateError
+	if errors.As(err, &duplicate) {
+		return outputSkippedDuplicate(&duplicate.Existing)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	if err := outputCreatedEntry(entry, dryRun); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// PinResult lists the entries pinned or unpinned
+type PinResult struct {
+	Entries []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+	Pinned  bool                    `json:"pinned" yaml:"pinned"`
+	DryRun  bool                    `json:"dry_run" yaml:"dry_run"`
+}
+
+// pinCmd represents the pin command
+var pinCmd = &cobra.Command{
+	Use:     "pin <id>...",
+	Aliases: []string{"favorite"},
+	Short:   "Pin entries as highlights worth revisiting",
+	Long: `Pin entries to mark them as highlights worth revisiting. Pinned entries are
+listed first in weekly and monthly summaries, and get --pinned and
+search --pinned list only them.
+
+Entries are found by ID or ID prefix like show does; pass --date with their
+day if the prefix alone does not find them.
+
+Examples:
+  dailyctl pin 01J3F2QK
+  dailyctl pin 01J3F2QK 01J3F9AB
+  dailyctl search --pinned --date-start "start of this year"
+  dailyctl unpin 01J3F2QK`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPin(true),
+}
+
+// unpinCmd represents the unpin command
+var unpinCmd = &cobra.Command{
+	Use:   "unpin <id>...",
+	Short: "Unpin entries",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runPin(false),
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+	rootCmd.AddCommand(unpinCmd)
+
+	for _, cmd := range []*cobra.Command{pinCmd, unpinCmd} {
+		cmd.Flags().String("date", "", "Day the entries are logged on (YYYY-MM-DD or e.g. \"yesterday\")")
+		cmd.Flags().Bool("dry-run", false, dryRunUsage)
+	}
+}
+
+func runPin(pinned bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		dateStr, _ := cmd.Flags().GetString("date")
+		var date time.Time
+		if dateStr != "" {
+			var err error
+			if date, err = datetime.ParseDate(dateStr, time.Now()); err != nil {
+				return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+			}
+		}
+
+		storageProvider, preview, err := createWriteProvider(cmd)
+		if err != nil {
+			return fmt.Errorf("failed to create storage provider: %w", err)
+		}
+
+		result := PinResult{Entries: []storage.DailyLogEntry{}, Pinned: pinned, DryRun: preview != nil}
+		for _, id := range args {
+			entry, err := updateEntryByID(storageProvider, id, date, storage.UpdateLogEntryRequest{Pinned: &pinned})
+			if err != nil {
+				return err
+			}
+			result.Entries = append(result.Entries, *entry)
+		}
+
+		if err := outputPinResult(result); err != nil {
+			return err
+		}
+		reportDryRun(preview)
+		return nil
+	}
+}
+
+// updateEntryByID applies req to the entry id, found on date or, if that's
+// zero, by its ID alone
+func updateEntryByID(store storage.DailyLogStorage, id string, date time.Time, req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	if date.IsZero() {
+		entry, err := storage.FindEntry(store, id)
+		if _, notFound := err.(storage.NotFoundError); notFound {
+			return nil, fmt.Errorf("%w (pass --date with the entry's day)", err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		id, date = entry.ID, entry.Timestamp
+	}
+
+	session, err := storage.BeginDayEdit(store, date)
+	if err != nil {
+		return nil, err
+	}
+	req.ID = id
+	entry, err := session.Update(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Commit(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// pinnedTitle is an entry's title, marked if it's pinned
+func pinnedTitle(entry storage.DailyLogEntry) string {
+	if entry.Pinned {
+		return "📌 " + entry.Title
+	}
+	return entry.Title
+}
+
+func outputPinResult(result PinResult) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	if quiet() {
+		printIDs(result.Entries)
+		return nil
+	}
+
+	action := "📌 Pinned"
+	switch {
+	case result.DryRun && result.Pinned:
+		action = "Would pin"
+	case result.DryRun:
+		action = "Would unpin"
+	case !result.Pinned:
+		action = "✓ Unpinned"
+	}
+	for _, entry := range result.Entries {
+		fmt.Printf("%s %s entry %s: %s\n", action, entry.Type, entry.ID, entry.Title)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// projectCmd represents the project command
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Group entries into long-running projects and report on them",
+	Long: `Group entries into long-running projects, reported on across any range of
+dates. Projects are kept in projects.json next to the logs in the repository.
+
+An entry belongs to a project when its project metadata names it, as set by
+log --project and by time tracking imports, or when it has one of the
+project's tags or their children, so work already tagged can be reported on
+without relogging it.
+
+Examples:
+  dailyctl project create search-api --tags work/search --start 2025-06-01
+  dailyctl log activity "Tuned the ranking" --project search-api --duration 90
+  dailyctl project list
+  dailyctl project report search-api
+  dailyctl project report search-api --date-start "start of last month" --entries`,
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a project",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runProjectCreate,
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the projects",
+	Args:  cobra.NoArgs,
+	RunE:  runProjectList,
+}
+
+var projectReportCmd = &cobra.Command{
+	Use:   "report <name>",
+	Short: "Roll up a project's entries, time, and summaries",
+	Long: `Roll up a project's entries: their count, tracked time, and average status,
+by week and by type, with the summaries and lessons logged for it. The
+report runs from the project's start to today unless --date-start and
+--date-end say otherwise.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProjectReport,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return completeProjects(cmd, args, toComplete)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(projectCmd)
+	projectCmd.AddCommand(projectCreateCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectReportCmd)
+
+	projectCreateCmd.Flags().String("description", "", "What the project is about")
+	projectCreateCmd.Flags().StringSlice("tags", []string{}, "Tags whose entries, and their children's, belong to the project")
+	projectCreateCmd.Flags().String("start", "", "First day of the project (YYYY-MM-DD or e.g. \"start of last month\"), default today")
+	_ = projectCreateCmd.RegisterFlagCompletionFunc("tags", completeTags)
+
+	projectReportCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD or e.g. \"3 weeks ago\"), default the project's start")
+	projectReportCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+	projectReportCmd.Flags().Bool("entries", false, "List every entry of the project")
+}
+
+// createProjectStore returns the storage holding the project list: the
+// GitHub repository, or memory as configured
+func createProjectStore() (storage.ProjectStore, error) {
+	if storageConfig := config.Storage(viper.GetViper()); storageConfig.StorageType == storage.StorageTypeMemory {
+		provider, err := createMemoryProvider(storageConfig)
+		if err != nil {
+			return nil, err
+		}
+		return provider.(storage.ProjectStore), nil
+	}
+	return createPrimaryProvider()
+}
+
+func runProjectCreate(cmd *cobra.Command, args []string) error {
+	description, _ := cmd.Flags().GetString("description")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	startStr, _ := cmd.Flags().GetString("start")
+	now := time.Now()
+
+	start := datetime.StartOfDay(now)
+	if startStr != "" {
+		var err error
+		if start, err = datetime.ParseDate(startStr, now); err != nil {
+			return invalidArgf("invalid start date: %s (use YYYY-MM-DD or e.g. \"start of last month\")", startStr)
+		}
+	}
+
+	store, err := createProjectStore()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	list, err := store.GetProjects()
+	if err != nil {
+		return err
+	}
+	project := storage.Project{
+		Name:        strings.TrimSpace(args[0]),
+		Description: description,
+		Tags:        storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases")).NormalizeTags(tags),
+		Start:       start,
+		CreatedAt:   now,
+	}
+	if err := list.Add(project); err != nil {
+		return err
+	}
+	if err := store.SaveProjects(list); err != nil {
+		return err
+	}
+
+	if ok, err := outputStructured(project); ok {
+		return err
+	}
+	if !quiet() {
+		fmt.Printf("✓ Created project %s, starting %s\n", project.Name, project.Start.Format("2006-01-02"))
+	}
+	return nil
+}
+
+func runProjectList(cmd *cobra.Command, args []string) error {
+	store, err := createProjectStore()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	list, err := store.GetProjects()
+	if err != nil {
+		return err
+	}
+
+	if ok, err := outputStructured(list.Projects); ok {
+		return err
+	}
+	if quiet() {
+		for _, project := range list.Projects {
+			fmt.Println(project.Name)
+		}
+		return nil
+	}
+	if len(list.Projects) == 0 {
+		fmt.Println("No projects yet; create one with dailyctl project create <name>")
+		return nil
+	}
+
+	fmt.Println(style(styleBold, fmt.Sprintf("%-20s %-10s %-24s %s", "PROJECT", "START", "TAGS", "DESCRIPTION")))
+	for _, project := range list.Projects {
+		fmt.Printf("%s %-10s %-24s %s\n", column(style(styleBold, project.Name), 20), project.Start.Format("2006-01-02"),
+			strings.Join(project.Tags, ","), project.Description)
+	}
+	return nil
+}
+
+func runProjectReport(cmd *cobra.Command, args []string) error {
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	listEntries, _ := cmd.Flags().GetBool("entries")
+
+	store, err := createProjectStore()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	list, err := store.GetProjects()
+	if err != nil {
+		return err
+	}
+	project, ok := list.Find(args[0])
+	if !ok {
+		return storage.NotFoundError{Resource: "project", ID: args[0]}
+	}
+
+	if dateStartStr == "" {
+		dateStartStr = project.Start.Format("2006-01-02")
+	}
+	start, end, err := resolveTimePeriod("", dateStartStr, dateEndStr, time.Now())
+	if err != nil {
+		return err
+	}
+	if start.After(end) {
+		return invalidArgf("the report starts %s, after it ends", start.Format("2006-01-02"))
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{DateStart: &start, DateEnd: &end})
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	report := storage.ReportProject(*project, result.Entries, storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases")))
+	report.Period = fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if ok, err := outputStructured(report); ok {
+		return err
+	}
+	if quiet() {
+		printIDs(report.Entries)
+		return nil
+	}
+	outputProjectReport(report, listEntries)
+	return nil
+}
+
+func outputProjectReport(report storage.ProjectReport, listEntries bool) {
+	title := fmt.Sprintf("Project %s - %s", report.Project.Name, report.Period)
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+	if report.Project.Description != "" {
+		printWrapped(report.Project.Description, 0, "")
+	}
+	if len(report.Project.Tags) > 0 {
+		fmt.Println(style(styleDim, "Tags: "+strings.Join(report.Project.Tags, ", ")))
+	}
+	fmt.Println()
+
+	if report.Totals.Count == 0 {
+		fmt.Println("No entries found.")
+		return
+	}
+	totals := fmt.Sprintf("%d entries, %s tracked", report.Totals.Count, formatMinutes(report.Totals.TotalDuration))
+	if report.Totals.AverageStatus > 0 {
+		totals += fmt.Sprintf(", average status %.1f", report.Totals.AverageStatus)
+	}
+	fmt.Println(totals)
+	fmt.Printf("First entry %s, last %s\n", report.FirstEntry.Local().Format("Mon 2006-01-02"), report.LastEntry.Local().Format("Mon 2006-01-02"))
+
+	fmt.Println()
+	fmt.Println(style(styleBold, fmt.Sprintf("%-10s %7s %10s %7s", "WEEK", "ENTRIES", "TIME", "STATUS")))
+	for _, week := range report.Weeks {
+		status := fmt.Sprintf("%7s", "-")
+		if week.AverageStatus > 0 {
+			status = style(statusStyle(int(week.AverageStatus+0.5)), fmt.Sprintf("%7.1f", week.AverageStatus))
+		}
+		fmt.Printf("%-10s %7d %10s %s\n", week.Key, week.Count, formatMinutes(week.TotalDuration), status)
+	}
+
+	fmt.Println()
+	fmt.Println(style(styleBold, fmt.Sprintf("%-10s %7s %10s", "TYPE", "ENTRIES", "TIME")))
+	for _, entryType := range report.Types {
+		fmt.Printf("%s %7d %10s\n", column(styleType(entryType.Key), 10), entryType.Count, formatMinutes(entryType.TotalDuration))
+	}
+
+	if len(report.Summaries) > 0 {
+		fmt.Println()
+		fmt.Println(style(styleBold, "Summaries and lessons:"))
+		for _, entry := range report.Summaries {
+			fmt.Printf("  %s %s\n", style(styleDim, entry.Timestamp.Local().Format("2006-01-02")), entry.Title)
+			if entry.Description != "" {
+				printWrapped(entry.Description, 4, styleDim)
+			}
+		}
+	}
+
+	if listEntries {
+		fmt.Println()
+		fmt.Println(style(styleBold, "Entries:"))
+		ids := shortIDs(report.Entries)
+		for _, entry := range report.Entries {
+			duration := ""
+			if entry.Duration != nil {
+				duration = style(styleDim, " ("+formatMinutes(*entry.Duration)+")")
+			}
+			fmt.Printf("  %s %s %s %s%s\n", style(styleDim, entry.Timestamp.Local().Format("01-02 15:04")),
+				column(styleType(entry.Type), 10), ids[entry.ID], entry.Title, duration)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/prompts"
+)
+
+// promptsCmd represents the prompts command
+var promptsCmd = &cobra.Command{
+	Use:   "prompts",
+	Short: "Inspect the prompt templates used by AI features",
+	Long: `Inspect the prompt templates used for AI summaries, insights, and the MCP
+ai_assist actions. Defaults are built in; a file named <name>.tmpl in the
+prompts directory (prompts.dir, ~/.dailyctl/prompts by default) overrides one.
+
+Templates use Go template syntax with these variables: .Period, .Entries,
+.Stats, .StatusAverage, .Tasks, .Facts, and .Prompt, plus the join and date
+functions.
+
+Examples:
+  dailyctl prompts list
+  dailyctl prompts show weekly_retro
+  dailyctl prompts show weekly_retro > ~/.dailyctl/prompts/weekly_retro.tmpl`,
+}
+
+var promptsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List prompt templates and whether each is overridden",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		library := promptLibrary()
+		for _, name := range prompts.Names() {
+			_, override, err := library.Source(name)
+			if err != nil {
+				return err
+			}
+			source := "built-in"
+			if override {
+				source = "override"
+			}
+			fmt.Printf("%-20s %s\n", name, source)
+		}
+		if library.Dir() != "" {
+			fmt.Printf("\nOverrides are read from %s\n", library.Dir())
+		}
+		return nil
+	},
+}
+
+var promptsShowCmd = &cobra.Command{
+	Use:       "show [name]",
+	Short:     "Print the template in effect for a prompt",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: prompts.Names(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, _, err := promptLibrary().Source(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(source)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promptsCmd)
+	promptsCmd.AddCommand(promptsListCmd)
+	promptsCmd.AddCommand(promptsShowCmd)
+}
+
+// promptLibrary returns the prompt templates with the configured overrides
+func promptLibrary() *prompts.Library {
+	dir := viper.GetString("prompts.dir")
+	if dir == "" {
+		dir = prompts.DefaultDir()
+	}
+	return prompts.NewLibrary(dir)
+}
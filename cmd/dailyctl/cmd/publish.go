@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/site"
+	"dailylog/internal/storage"
+)
+
+// publishCmd represents the publish command
+var publishCmd = &cobra.Command{
+	Use:   "publish",
+	Short: "Render public entries as a static site",
+	Long: `Render the entries visible to an audience (public by default) into a small
+static HTML site: an index of months and tags, a page per month with its
+statistics and entries, and a page per tag.
+
+The site is written to --out, and with --push also committed to a branch of
+the log repository (gh-pages by default), replacing what was there, so it can
+be served by GitHub Pages. The branch is created if needed.
+
+Examples:
+  dailyctl publish --out site/
+  dailyctl publish --out site/ --date-start 2025-01-01
+  dailyctl publish --out site/ --title "Work Journal" --push
+  dailyctl publish --push --branch pages --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runPublish,
+}
+
+func init() {
+	rootCmd.AddCommand(publishCmd)
+
+	publishCmd.Flags().String("out", "site", "Directory to write the site to")
+	publishCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD), default: all days")
+	publishCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD), default: all days")
+	publishCmd.Flags().String("title", "Daily Log", "Title of the site")
+	publishCmd.Flags().String("audience", storage.VisibilityPublic, "Only include entries visible to: private, team, public")
+	publishCmd.Flags().Bool("push", false, "Also commit the site to a branch of the log repository")
+	publishCmd.Flags().String("branch", "gh-pages", "Branch to push the site to")
+	publishCmd.Flags().Bool("dry-run", false, "Render the site without writing or pushing it")
+
+	_ = viper.BindPFlag("publish.out", publishCmd.Flags().Lookup("out"))
+	_ = viper.BindPFlag("publish.title", publishCmd.Flags().Lookup("title"))
+	_ = viper.BindPFlag("publish.audience", publishCmd.Flags().Lookup("audience"))
+	_ = viper.BindPFlag("publish.branch", publishCmd.Flags().Lookup("branch"))
+}
+
+// PublishResult is the outcome of the publish command
+type PublishResult struct {
+	Out     string `json:"out,omitempty" yaml:"out,omitempty"`
+	Branch  string `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Pushed  bool   `json:"pushed" yaml:"pushed"`
+	Files   int    `json:"files" yaml:"files"`
+	Months  int    `json:"months" yaml:"months"`
+	Tags    int    `json:"tags" yaml:"tags"`
+	Entries int    `json:"entries" yaml:"entries"`
+	DryRun  bool   `json:"dry_run" yaml:"dry_run"`
+}
+
+func runPublish(cmd *cobra.Command, args []string) error {
+	push, _ := cmd.Flags().GetBool("push")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	out := viper.GetString("publish.out")
+	title := viper.GetString("publish.title")
+	audience := viper.GetString("publish.audience")
+	branch := viper.GetString("publish.branch")
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+	if out == "" && !push {
+		return invalidArgf("nothing to do (use --out or --push)")
+	}
+	if push && branch == "" {
+		return invalidArgf("--push requires a branch")
+	}
+
+	start, end, err := parseMirrorRange(cmd)
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	dates, err := storageProvider.ListDays(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to list days: %w", err)
+	}
+	policy := visibilityPolicy()
+	days := make([]storage.DayLog, 0, len(dates))
+	entries := 0
+	for _, date := range dates {
+		dayLog, err := storageProvider.GetDay(date)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", date.Format("2006-01-02"), err)
+		}
+		day := policy.FilterDay(*dayLog, audience)
+		// Day summaries have no visibility of their own, so only private sites show them
+		if audience != storage.VisibilityPrivate {
+			day.DaySummary = ""
+		}
+		entries += len(day.Entries)
+		days = append(days, day)
+	}
+
+	journal := site.New(title, days)
+	files, err := journal.Render()
+	if err != nil {
+		return err
+	}
+
+	result := PublishResult{
+		Out:     out,
+		Files:   len(files),
+		Months:  len(journal.Months),
+		Tags:    len(journal.Tags),
+		Entries: entries,
+		DryRun:  dryRun,
+	}
+	if out != "" && !dryRun {
+		if err := site.Write(out, files); err != nil {
+			return err
+		}
+	}
+	if push {
+		result.Branch = branch
+		if !dryRun {
+			message := fmt.Sprintf("Publish %s (%d entries)", title, entries)
+			result.Pushed, err = storageProvider.PublishFiles(branch, files, message)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	if quiet() {
+		return nil
+	}
+
+	verb := "Published"
+	if dryRun {
+		verb = "Would publish"
+	}
+	fmt.Printf("%s %d entries in %d months and %d tags", verb, result.Entries, result.Months, result.Tags)
+	if out != "" {
+		fmt.Printf(" to %s", out)
+	}
+	fmt.Println()
+	if push && !dryRun {
+		if result.Pushed {
+			fmt.Printf("✓ Pushed the site to %s\n", branch)
+		} else {
+			fmt.Printf("Branch %s is already up to date\n", branch)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// quickCmd represents the q command
+var quickCmd = &cobra.Command{
+	Use:     "q [text]",
+	Aliases: []string{"quick"},
+	Short:   "Quickly capture an entry from one line of text",
+	Long: `Capture an entry from one line of text, with its details written inline:
+
+  #tag        add a tag (repeatable)
+  @location   set the location
+  !p2 or !2   set the priority (1-5)
+  ~45m        set the duration (~90, ~2h, ~1h30m)
+  status:7    set the status (1-10)
+
+Everything else becomes the title. Quote the text so the shell leaves # and !
+alone.
+
+Examples:
+  dailyctl q "Fixed prod bug #work #incident @office !p2 ~45m status:7"
+  dailyctl q "Lunch with Sam @cafe ~1h" --type note
+  dailyctl q "Gym session #health ~1h status:8" --datetime "yesterday 6pm"
+  dailyctl q "Try the new parser #work" --dry-run`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runQuick,
+}
+
+func init() {
+	rootCmd.AddCommand(quickCmd)
+
+	quickCmd.Flags().String("type", "activity", "Entry type: activity, status, note, summary, meeting, lesson")
+	quickCmd.Flags().String("datetime", "", "Date and time for the entry (flexible format, defaults to now)")
+	quickCmd.Flags().String("visibility", "", "Visibility: private, team, public (defaults to privacy.default, or team)")
+	quickCmd.Flags().Bool("dry-run", false, dryRunUsage)
+	quickCmd.Flags().String("duplicates", "", duplicatesUsage)
+
+	_ = quickCmd.RegisterFlagCompletionFunc("type", completeEntryTypes)
+}
+
+func runQuick(cmd *cobra.Command, args []string) error {
+	entryType, _ := cmd.Flags().GetString("type")
+	datetimeStr, _ := cmd.Flags().GetString("datetime")
+	visibility, _ := cmd.Flags().GetString("visibility")
+
+	switch entryType {
+	case "activity", "status", "note", "summary", storage.EntryTypeMeeting, storage.EntryTypeLesson:
+	default:
+		return invalidArgf("--type must be one of activity, status, note, summary, meeting, lesson (got %q)", entryType)
+	}
+	if err := storage.ValidateVisibility(visibility); err != nil {
+		return err
+	}
+
+	createReq, err := storage.ParseQuickEntry(strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+
+	createReq.Date = time.Now()
+	if datetimeStr != "" {
+		createReq.Date, err = datetime.Parse(datetimeStr, time.Now())
+		if err != nil {
+			return invalidArgf("invalid datetime format: %s (%w)", datetimeStr, err)
+		}
+	}
+	createReq.Type = entryType
+	createReq.Visibility = visibility
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	entry, err := storageProvider.CreateEntry(createReq)
+	var duplicate storage.DuplicateError
+	if errors.As(err, &duplicate) {
+		return outputSkippedDuplicate(&duplicate.Existing)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	if err := outputCreatedEntry(entry, preview != nil); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
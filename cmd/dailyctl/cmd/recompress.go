@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// RecompressResult lists the days rewritten in another file format
+type RecompressResult struct {
+	Format string   `json:"format" yaml:"format"`
+	Days   []string `json:"days" yaml:"days"`
+	DryRun bool     `json:"dry_run" yaml:"dry_run"`
+}
+
+// recompressCmd represents the recompress command
+var recompressCmd = &cobra.Command{
+	Use:   "recompress",
+	Short: "Rewrite stored days in another file format",
+	Long: `Rewrite day files already in the repository in a smaller format, in a single
+commit. Day files can be stored as:
+
+  pretty   indented JSON (.json), the default
+  compact  single-line JSON (.json)
+  gzip     gzip-compressed compact JSON (.json.gz)
+
+github.day_format (or DAILYLOG_DAY_FORMAT) sets the format new days are written
+in; days already stored keep theirs until recompressed. Every format is read
+transparently, so the two can be mixed while history is converted.
+
+Examples:
+  dailyctl recompress --format gzip --dry-run
+  dailyctl recompress --format gzip
+  dailyctl recompress --format compact --from 2024-01-01 --to 2024-12-31`,
+	Args: cobra.NoArgs,
+	RunE: runRecompress,
+}
+
+func init() {
+	rootCmd.AddCommand(recompressCmd)
+
+	recompressCmd.Flags().String("format", "", "Format to rewrite days in: pretty, compact, gzip (default: github.day_format, or pretty)")
+	recompressCmd.Flags().String("from", "", "First day to rewrite (YYYY-MM-DD or e.g. \"last month\"; default: the first stored)")
+	recompressCmd.Flags().String("to", "", "Last day to rewrite (YYYY-MM-DD or e.g. \"yesterday\"; default: the last stored)")
+	recompressCmd.Flags().Bool("dry-run", false, "List the days that would be rewritten without writing anything")
+}
+
+func runRecompress(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	fromStr, _ := cmd.Flags().GetString("from")
+	toStr, _ := cmd.Flags().GetString("to")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if format == "" {
+		format = viper.GetString("github.day_format")
+	}
+	if format == "" {
+		format = storage.DayFormatPretty
+	}
+	if err := storage.ValidateDayFormat(format); err != nil {
+		return err
+	}
+
+	var from, to time.Time
+	var err error
+	if fromStr != "" {
+		if from, err = datetime.ParseDate(fromStr, time.Now()); err != nil {
+			return invalidArgf("invalid --from date: %s (use YYYY-MM-DD or e.g. \"last month\")", fromStr)
+		}
+	}
+	if toStr != "" {
+		if to, err = datetime.ParseDate(toStr, time.Now()); err != nil {
+			return invalidArgf("invalid --to date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", toStr)
+		}
+	}
+
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	days, err := primary.ListDays(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to list days: %w", err)
+	}
+	rewritten, err := primary.RecompressDays(days, format, dryRun)
+	if err != nil {
+		return err
+	}
+
+	result := RecompressResult{Format: format, Days: []string{}, DryRun: dryRun}
+	for _, day := range rewritten {
+		result.Days = append(result.Days, day.Format("2006-01-02"))
+	}
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	switch {
+	case len(result.Days) == 0:
+		fmt.Printf("All %d days are already stored as %s\n", len(days), format)
+	case dryRun:
+		fmt.Printf("Would rewrite %d of %d days as %s:\n", len(result.Days), len(days), format)
+		for _, day := range result.Days {
+			fmt.Printf("  %s\n", day)
+		}
+	default:
+		fmt.Printf("✓ Rewrote %d of %d days as %s in one commit\n", len(result.Days), len(days), format)
+	}
+	return nil
+}
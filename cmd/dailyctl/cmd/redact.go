@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/ai"
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// RedactPreview is what would be sent to an AI provider once redacted
+type RedactPreview struct {
+	Period     string                  `json:"period,omitempty" yaml:"period,omitempty"`
+	Rules      int                     `json:"rules" yaml:"rules"`
+	Redactions int                     `json:"redactions" yaml:"redactions"`
+	Text       string                  `json:"text,omitempty" yaml:"text,omitempty"`
+	Entries    []storage.DailyLogEntry `json:"entries,omitempty" yaml:"entries,omitempty"`
+}
+
+// redactCmd represents the redact command
+var redactCmd = &cobra.Command{
+	Use:   "redact",
+	Short: "Inspect the redaction applied before text is sent to AI providers",
+	Long: `Inspect the redaction rules applied to entry text before it is sent to an
+AI provider. Rules are read from ai.redact in the config file, or from the
+YAML file named by ai.redact_file (DAILYLOG_AI_REDACT_FILE), which the MCP
+server reads too. Each rule has a regular expression pattern and/or a list of
+keywords, matched as whole words ignoring case, plus an optional replacement
+("[redacted]" by default):
+
+  ai:
+    redact:
+      - name: clients
+        keywords: [Acme, Globex]
+        replacement: "[client]"
+      - name: amounts
+        pattern: '[$€£]\s?\d[\d,]*(\.\d+)?'
+
+Examples:
+  dailyctl redact preview
+  dailyctl redact preview --date-start "last monday" --date-end today
+  dailyctl redact preview --text "Invoiced Acme $1,200"`,
+}
+
+var redactPreviewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Show entries as an AI provider would see them",
+	Args:  cobra.NoArgs,
+	RunE:  runRedactPreview,
+}
+
+func init() {
+	rootCmd.AddCommand(redactCmd)
+	redactCmd.AddCommand(redactPreviewCmd)
+
+	redactPreviewCmd.Flags().String("date", "", "Date to preview (YYYY-MM-DD or e.g. \"yesterday\"; default: today)")
+	redactPreviewCmd.Flags().String("date-start", "", "Start of a range to preview")
+	redactPreviewCmd.Flags().String("date-end", "", "End of a range to preview")
+	redactPreviewCmd.Flags().String("text", "", "Preview redaction of this text instead of logged entries")
+}
+
+// redactionRules returns the configured redaction rules
+func redactionRules() ([]ai.RedactionRule, error) {
+	if path := viper.GetString("ai.redact_file"); path != "" {
+		return ai.LoadRedactionRules(path)
+	}
+	var rules []ai.RedactionRule
+	if err := viper.UnmarshalKey("ai.redact", &rules); err != nil {
+		return nil, invalidArgf("invalid ai.redact configuration: %w", err)
+	}
+	return rules, nil
+}
+
+func runRedactPreview(cmd *cobra.Command, args []string) error {
+	rules, err := redactionRules()
+	if err != nil {
+		return err
+	}
+	redactor, err := ai.NewRedactor(rules)
+	if err != nil {
+		return err
+	}
+	preview := RedactPreview{Rules: len(rules)}
+
+	if cmd.Flags().Changed("text") {
+		text, _ := cmd.Flags().GetString("text")
+		preview.Text, preview.Redactions = redactor.Redact(text)
+		return outputRedactPreview(preview)
+	}
+
+	start, end, err := redactPreviewRange(cmd)
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	days, err := storageProvider.GetDateRange(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+
+	var entries []storage.DailyLogEntry
+	for _, day := range days {
+		entries = append(entries, day.Entries...)
+	}
+	preview.Entries, preview.Redactions = redactor.RedactEntries(entries)
+	preview.Period = start.Format("2006-01-02")
+	if !end.Equal(start) {
+		preview.Period += " to " + end.Format("2006-01-02")
+	}
+	return outputRedactPreview(preview)
+}
+
+// redactPreviewRange returns the days selected by --date or --date-start/--date-end
+func redactPreviewRange(cmd *cobra.Command) (time.Time, time.Time, error) {
+	dateStr, _ := cmd.Flags().GetString("date")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	now := time.Now()
+
+	if dateStartStr == "" && dateEndStr == "" {
+		if dateStr == "" {
+			return now, now, nil
+		}
+		date, err := datetime.ParseDate(dateStr, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
+		}
+		return date, date, nil
+	}
+
+	if dateStr != "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--date cannot be combined with --date-start or --date-end")
+	}
+	if dateStartStr == "" || dateEndStr == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("a range requires both --date-start and --date-end")
+	}
+	start, err1 := datetime.ParseDate(dateStartStr, now)
+	end, err2 := datetime.ParseDate(dateEndStr, now)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid date format in range (use YYYY-MM-DD or e.g. \"start of last month\")")
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start date cannot be after end date")
+	}
+	return start, end, nil
+}
+
+func outputRedactPreview(preview RedactPreview) error {
+	if ok, err := outputStructured(preview); ok {
+		return err
+	}
+
+	if preview.Rules == 0 {
+		fmt.Println("No redaction rules configured; text is sent as is.")
+		fmt.Println()
+	}
+
+	if preview.Period == "" {
+		fmt.Println(preview.Text)
+	} else if len(preview.Entries) == 0 {
+		fmt.Printf("No entries for %s\n", preview.Period)
+	} else {
+		fmt.Printf("Entries for %s as sent to the AI provider:\n\n", preview.Period)
+		for _, entry := range preview.Entries {
+			fmt.Printf("%s [%s] %s\n", entry.Timestamp.Format("2006-01-02 15:04"), entry.Type, entry.Title)
+			if entry.Description != "" {
+				fmt.Printf("  %s\n", entry.Description)
+			}
+			if entry.Location != "" {
+				fmt.Printf("  Location: %s\n", entry.Location)
+			}
+			if len(entry.Tags) > 0 {
+				fmt.Printf("  Tags: %v\n", entry.Tags)
+			}
+		}
+	}
+
+	fmt.Printf("\n%d redaction(s) by %d rule(s)\n", preview.Redactions, preview.Rules)
+	return nil
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/notify"
+	"dailylog/internal/storage"
+)
+
+// remindCmd represents the remind command
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Nudge when nothing has been logged yet today",
+	Long: `Send a desktop notification and/or webhook ping when no entry has been
+logged today, to help build the journaling habit.
+
+Without --daemon the check runs once and exits, which suits cron or launchd.
+With --daemon the command keeps running and checks at each --at time.
+
+Examples:
+  dailyctl remind
+  dailyctl remind --daemon --at 12:00,17:00
+  dailyctl remind --daemon --webhook https://hooks.slack.com/services/...
+  dailyctl remind --daemon --desktop=false --webhook https://example.com/hook`,
+	RunE: runRemind,
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+
+	remindCmd.Flags().Bool("daemon", false, "Keep running and check at each --at time")
+	remindCmd.Flags().StringSlice("at", []string{"12:00", "17:00"}, "Times of day to check (HH:MM)")
+	remindCmd.Flags().Bool("desktop", true, "Send desktop notifications")
+	remindCmd.Flags().String("webhook", "", "Webhook URL to ping (Slack, Discord, or generic JSON)")
+	remindCmd.Flags().String("message", "Nothing logged yet today - what have you been up to?", "Reminder message")
+
+	_ = viper.BindPFlag("remind.times", remindCmd.Flags().Lookup("at"))
+	_ = viper.BindPFlag("remind.desktop", remindCmd.Flags().Lookup("desktop"))
+	_ = viper.BindPFlag("remind.webhook", remindCmd.Flags().Lookup("webhook"))
+	_ = viper.BindPFlag("remind.message", remindCmd.Flags().Lookup("message"))
+}
+
+func runRemind(cmd *cobra.Command, args []string) error {
+	daemon, _ := cmd.Flags().GetBool("daemon")
+
+	times, err := parseReminderTimes(viper.GetStringSlice("remind.times"))
+	if err != nil {
+		return err
+	}
+
+	notifier, err := buildReminderNotifier()
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	if !daemon {
+		return checkAndRemind(storageProvider, notifier, time.Now())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	runReminderLoop(ctx, storageProvider, notifier, times)
+	return nil
+}
+
+// runReminderLoop checks at each reminder time until the context is cancelled
+func runReminderLoop(ctx context.Context, store storage.DailyLogStorage, notifier notify.Notifier, times []int) {
+	for {
+		next := nextReminderTime(time.Now(), times)
+		if viper.GetBool("verbose") {
+			fmt.Fprintf(os.Stderr, "Next reminder check at %s\n", next.Format("2006-01-02 15:04"))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := checkAndRemind(store, notifier, next); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
+
+// checkAndRemind sends a reminder if the day has no entries yet
+func checkAndRemind(store storage.DailyLogStorage, notifier notify.Notifier, now time.Time) error {
+	dayLog, err := store.GetDay(now)
+	if err != nil {
+		return fmt.Errorf("failed to check today's entries: %w", err)
+	}
+
+	if len(dayLog.Entries) > 0 {
+		if viper.GetBool("verbose") {
+			fmt.Fprintf(os.Stderr, "%d entries logged today, no reminder needed\n", len(dayLog.Entries))
+		}
+		return nil
+	}
+
+	if err := notifier.Notify("Daily Log", viper.GetString("remind.message")); err != nil {
+		return err
+	}
+	fmt.Printf("Reminder sent at %s\n", now.Format("15:04"))
+	return nil
+}
+
+func buildReminderNotifier() (notify.Notifier, error) {
+	var notifiers notify.Multi
+	if viper.GetBool("remind.desktop") {
+		notifiers = append(notifiers, notify.DesktopNotifier{})
+	}
+	if webhook := viper.GetString("remind.webhook"); webhook != "" {
+		notifiers = append(notifiers, notify.NewWebhookNotifier(webhook))
+	}
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("no notification channel configured (enable --desktop or set --webhook)")
+	}
+	return notifiers, nil
+}
+
+// parseReminderTimes parses HH:MM strings into minutes after midnight, sorted
+func parseReminderTimes(values []string) ([]int, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("at least one reminder time is required")
+	}
+
+	var minutes []int
+	for _, value := range values {
+		t, err := time.Parse("15:04", value)
+		if err != nil {
+			return nil, invalidArgf("invalid reminder time: %s (use HH:MM)", value)
+		}
+		minutes = append(minutes, t.Hour()*60+t.Minute())
+	}
+	sort.Ints(minutes)
+	return minutes, nil
+}
+
+// nextReminderTime returns the first configured time strictly after now
+func nextReminderTime(now time.Time, times []int) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, m := range times {
+		candidate := midnight.Add(time.Duration(m) * time.Minute)
+		if candidate.After(now) {
+			return candidate
+		}
+	}
+	tomorrow := midnight.AddDate(0, 0, 1)
+	return tomorrow.Add(time.Duration(times[0]) * time.Minute)
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+// ANSI styles used in table output
+const (
+	styleReset  = "\033[0m"
+	styleBold   = "\033[1m"
+	styleDim    = "\033[2m"
+	styleRed    = "\033[31m"
+	styleGreen  = "\033[32m"
+	styleYellow = "\033[33m"
+	styleBlue   = "\033[34m"
+	stylePurple = "\033[35m"
+	styleCyan   = "\033[36m"
+)
+
+// typeStyles colors each entry type
+var typeStyles = map[string]string{
+	"activity": styleBlue,
+	"status":   styleGreen,
+	"note":     styleYellow,
+	"summary":  stylePurple,
+	"meeting":  styleCyan,
+	"metric":   styleGreen,
+	"lesson":   styleYellow,
+}
+
+// isTerminal reports whether stdout is a terminal rather than a pipe or file.
+// It looks past the pager, so paged output is still rendered for the terminal.
+func isTerminal() bool {
+	info, err := terminal.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled reports whether output may be colored: stdout is a terminal,
+// and neither --no-color, output.no_color, nor NO_COLOR turns it off
+func colorEnabled() bool {
+	if viper.GetBool("output.no_color") || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isTerminal()
+}
+
+// terminalWidth returns the width to wrap output to, or 0 to not wrap when
+// output goes to a pipe or file. COLUMNS overrides the detected width.
+func terminalWidth() int {
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	if !isTerminal() {
+		return 0
+	}
+	if width := ttyWidth(); width > 0 {
+		return width
+	}
+	return 80
+}
+
+// style wraps text in an ANSI style when color is enabled
+func style(code, text string) string {
+	if code == "" || text == "" || !colorEnabled() {
+		return text
+	}
+	return code + text + styleReset
+}
+
+// styleType colors an entry type
+func styleType(entryType string) string {
+	return style(typeStyles[entryType], entryType)
+}
+
+// statusStyle is red for low moods, yellow for middling, and green for good
+func statusStyle(status int) string {
+	switch {
+	case status <= 0:
+		return ""
+	case status <= 3:
+		return styleRed
+	case status <= 6:
+		return styleYellow
+	default:
+		return styleGreen
+	}
+}
+
+// priorityStyle is red for the most urgent priorities and yellow for the next
+func priorityStyle(priority int) string {
+	switch priority {
+	case 1:
+		return styleRed + styleBold
+	case 2:
+		return styleRed
+	case 3:
+		return styleYellow
+	default:
+		return ""
+	}
+}
+
+// formatStatus renders a 1-10 status, colored by mood
+func formatStatus(status int) string {
+	if status <= 0 {
+		return ""
+	}
+	return style(statusStyle(status), fmt.Sprintf("%d/10", status))
+}
+
+// formatPriority renders a 1-5 priority, colored by urgency
+func formatPriority(priority int) string {
+	if priority <= 0 {
+		return ""
+	}
+	return style(priorityStyle(priority), fmt.Sprintf("%d/5", priority))
+}
+
+// column left-aligns styled text in width characters, ignoring the width of
+// ANSI codes, so colored cells still line up
+func column(text string, width int) string {
+	if visible := visibleLen(text); visible < width {
+		return text + strings.Repeat(" ", width-visible)
+	}
+	return text
+}
+
+// visibleLen counts the characters of text, leaving out ANSI codes
+func visibleLen(text string) int {
+	n := 0
+	for i := 0; i < len(text); {
+		if text[i] == '\033' {
+			if end := strings.IndexByte(text[i:], 'm'); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		n++
+	}
+	return n
+}
+
+// wrapText breaks text into lines of at most width characters at spaces,
+// keeping its own line breaks. Words longer than width are split. A width
+// of 0 or less leaves lines unwrapped.
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		if width <= 0 {
+			lines = append(lines, paragraph)
+			continue
+		}
+
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			if word == "" {
+				continue
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// wrapWidth is the width left for text indented by indent, or 0 to not wrap
+func wrapWidth(indent int) int {
+	width := terminalWidth()
+	if width <= 0 {
+		return 0
+	}
+	return max(width-indent, 20)
+}
+
+// printWrapped prints text wrapped to the terminal with every line indented
+// and styled with code, if set
+func printWrapped(text string, indent int, code string) {
+	prefix := strings.Repeat(" ", indent)
+	for _, line := range wrapText(text, wrapWidth(indent)) {
+		fmt.Println(prefix + style(code, line))
+	}
+}
+
+// rule returns a horizontal line at most the terminal's width
+func rule(char string, length int) string {
+	if width := terminalWidth(); width > 0 && width < length {
+		length = width
+	}
+	return style(styleDim, strings.Repeat(char, length))
+}
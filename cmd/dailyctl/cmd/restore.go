@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// RestoreResult describes a day put back to an earlier revision
+type RestoreResult struct {
+	Date     string                  `json:"date" yaml:"date"`
+	Revision providers.DayRevision   `json:"revision" yaml:"revision"`
+	Entries  []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+	DryRun   bool                    `json:"dry_run" yaml:"dry_run"`
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a day from the repository history",
+	Long: `Recover a day's log as it was at an earlier point in time, using the commit
+history of the GitHub repository. This undoes accidental overwrites or
+deletions without digging through git manually.
+
+The restored version is written as a new commit, so the restore itself can be
+undone the same way.
+
+Examples:
+  dailyctl restore --date 2025-09-20 --history
+  dailyctl restore --date 2025-09-20 --as-of "2025-09-25T12:00Z" --dry-run
+  dailyctl restore --date 2025-09-20 --as-of "2025-09-25 12:00"`,
+	Args: cobra.NoArgs,
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().String("date", "", "Day to restore (YYYY-MM-DD)")
+	restoreCmd.Flags().String("as-of", "", "Restore the version stored at this time (RFC 3339 or local date/time)")
+	restoreCmd.Flags().Bool("history", false, "List the stored versions of the day instead of restoring")
+	restoreCmd.Flags().Bool("dry-run", false, "Show the version that would be restored without writing it")
+
+	_ = restoreCmd.MarkFlagRequired("date")
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	asOfStr, _ := cmd.Flags().GetString("as-of")
+	history, _ := cmd.Flags().GetBool("history")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		return invalidArgf("invalid date format: %w", err)
+	}
+
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	if history {
+		revisions, err := primary.DayHistory(date)
+		if err != nil {
+			return err
+		}
+		return outputDayHistory(revisions, date)
+	}
+
+	if asOfStr == "" {
+		return fmt.Errorf("--as-of is required unless --history is set")
+	}
+	asOf, err := parseAsOf(asOfStr)
+	if err != nil {
+		return err
+	}
+
+	dayLog, revision, err := primary.GetDayAsOf(date, asOf)
+	if err != nil {
+		return err
+	}
+
+	result := RestoreResult{Date: dateStr, Revision: *revision, Entries: dayLog.Entries, DryRun: dryRun}
+	if dryRun {
+		if ok, err := outputStructured(result); ok {
+			return err
+		}
+		fmt.Printf("Would restore %s from %s (%s, %d entries):\n",
+			dateStr, revision.ShortSHA(), revision.Time.Local().Format("2006-01-02 15:04"), len(dayLog.Entries))
+		for _, entry := range dayLog.Entries {
+			fmt.Printf("  %s  [%s] %s\n", entry.Timestamp.Local().Format("15:04"), entry.Type, entry.Title)
+		}
+		return nil
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	if err := replaceDay(storageProvider, dayLog); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", dateStr, err)
+	}
+
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	fmt.Printf("✓ Restored %s to its state at %s (%s, %d entries)\n",
+		dateStr, revision.Time.Local().Format("2006-01-02 15:04"), revision.ShortSHA(), len(dayLog.Entries))
+	return nil
+}
+
+func outputDayHistory(revisions []providers.DayRevision, date time.Time) error {
+	if ok, err := outputStructured(revisions); ok {
+		return err
+	}
+
+	if len(revisions) == 0 {
+		fmt.Printf("No history found for %s\n", date.Format("2006-01-02"))
+		return nil
+	}
+	fmt.Printf("History of %s:\n", date.Format("2006-01-02"))
+	for _, revision := range revisions {
+		message, _, _ := strings.Cut(revision.Message, "\n")
+		fmt.Printf("  %s  %s  %s\n", revision.Time.Local().Format("2006-01-02 15:04:05"), revision.ShortSHA(), message)
+	}
+	return nil
+}
+
+// parseAsOf parses an RFC 3339 timestamp (seconds optional) or a local date/time
+func parseAsOf(input string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if t, err := time.Parse(layout, input); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.ParseInLocation("2006-01-02", input, time.Local); err == nil {
+		// A bare date means the end of that day
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	if t, err := datetime.Parse(input, time.Now()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, storage.ValidationError{
+		Field:   "as-of",
+		Message: fmt.Sprintf("unable to parse time: %s", input),
+	}
+}
@@ -0,0 +1,290 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// ReviewResult lists the entries marked reviewed or unreviewed
+type ReviewResult struct {
+	Entries  []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+	Reviewed bool                    `json:"reviewed" yaml:"reviewed"`
+	// Skipped counts the entries of the period that were already marked so
+	Skipped int  `json:"skipped" yaml:"skipped"`
+	DryRun  bool `json:"dry_run" yaml:"dry_run"`
+}
+
+// ReviewStatus is the review backlog of a period
+type ReviewStatus struct {
+	Period                string `json:"period" yaml:"period"`
+	storage.ReviewBacklog `yaml:",inline"`
+}
+
+// reviewCmd represents the review command
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Track which entries have been reviewed",
+	Long: `Track which entries have been looked over, for a GTD-style weekly review of
+the journal: go through the week's entries with get --unreviewed, follow up
+on what needs it, then mark them reviewed. review status shows the backlog
+left to review.
+
+Entries are marked by ID or ID prefix, or all at once for a period; marking
+a period skips the entries already marked, which keep the time they were
+first reviewed.
+
+Examples:
+  dailyctl get week --unreviewed
+  dailyctl review mark --week
+  dailyctl review mark --last-week --dry-run
+  dailyctl review mark 01J3F2QK 01J3F9AB
+  dailyctl review unmark 01J3F2QK
+  dailyctl review status --period last-month`,
+}
+
+var reviewMarkCmd = &cobra.Command{
+	Use:   "mark [id]...",
+	Short: "Mark entries, or a period's entries, reviewed",
+	RunE:  runReviewMark(true),
+}
+
+var reviewUnmarkCmd = &cobra.Command{
+	Use:   "unmark [id]...",
+	Short: "Mark entries, or a period's entries, not yet reviewed",
+	RunE:  runReviewMark(false),
+}
+
+var reviewStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the entries left to review, by week",
+	Args:  cobra.NoArgs,
+	RunE:  runReviewStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(reviewCmd)
+	reviewCmd.AddCommand(reviewMarkCmd)
+	reviewCmd.AddCommand(reviewUnmarkCmd)
+	reviewCmd.AddCommand(reviewStatusCmd)
+
+	for _, cmd := range []*cobra.Command{reviewMarkCmd, reviewUnmarkCmd} {
+		cmd.Flags().Bool("week", false, "Every entry of this week")
+		cmd.Flags().Bool("last-week", false, "Every entry of last week")
+		cmd.Flags().String("date", "", "Day the entries are logged on; without IDs, every entry of the day")
+		cmd.Flags().String("date-start", "", "Start of a range whose entries to mark (YYYY-MM-DD or e.g. \"3 weeks ago\")")
+		cmd.Flags().String("date-end", "", "End of the range (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+		cmd.Flags().Bool("dry-run", false, dryRunUsage)
+	}
+
+	reviewStatusCmd.Flags().String("period", "month", "Period: today, week, month, last-week, last-month")
+	reviewStatusCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD or e.g. \"3 weeks ago\"), overrides --period")
+	reviewStatusCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+}
+
+func runReviewMark(reviewed bool) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		week, _ := cmd.Flags().GetBool("week")
+		lastWeek, _ := cmd.Flags().GetBool("last-week")
+		dateStr, _ := cmd.Flags().GetString("date")
+		dateStartStr, _ := cmd.Flags().GetString("date-start")
+		dateEndStr, _ := cmd.Flags().GetString("date-end")
+		now := time.Now()
+
+		var date time.Time
+		if dateStr != "" {
+			var err error
+			if date, err = datetime.ParseDate(dateStr, now); err != nil {
+				return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+			}
+		}
+
+		ranges := 0
+		for _, set := range []bool{week, lastWeek, dateStartStr != "" || dateEndStr != "", dateStr != "" && len(args) == 0} {
+			if set {
+				ranges++
+			}
+		}
+		switch {
+		case len(args) > 0 && ranges > 0:
+			return invalidArgf("entry IDs cannot be combined with --week, --last-week, or --date-start")
+		case len(args) == 0 && ranges == 0:
+			return invalidArgf("give entry IDs, or mark a period with --week, --last-week, --date, or --date-start")
+		case ranges > 1:
+			return invalidArgf("use only one of --week, --last-week, --date, and --date-start")
+		}
+
+		// An unmarked entry is given the zero time, which clears its review
+		reviewedAt := time.Time{}
+		if reviewed {
+			reviewedAt = now.UTC().Truncate(time.Second)
+		}
+
+		storageProvider, preview, err := createWriteProvider(cmd)
+		if err != nil {
+			return fmt.Errorf("failed to create storage provider: %w", err)
+		}
+
+		result := ReviewResult{Entries: []storage.DailyLogEntry{}, Reviewed: reviewed, DryRun: preview != nil}
+		if len(args) > 0 {
+			for _, id := range args {
+				entry, err := updateEntryByID(storageProvider, id, date, storage.UpdateLogEntryRequest{ReviewedAt: &reviewedAt})
+				if err != nil {
+					return err
+				}
+				result.Entries = append(result.Entries, *entry)
+			}
+		} else {
+			start, end := date, date
+			switch {
+			case week:
+				start, end, err = resolveTimePeriod("week", "", "", now)
+			case lastWeek:
+				start, end, err = resolveTimePeriod("last-week", "", "", now)
+			case dateStartStr != "":
+				start, end, err = resolveTimePeriod("", dateStartStr, dateEndStr, now)
+			case dateEndStr != "":
+				return invalidArgf("--date-end requires --date-start")
+			}
+			if err != nil {
+				return err
+			}
+			if err := markPeriodReviewed(storageProvider, start, end, reviewedAt, &result); err != nil {
+				if len(result.Entries) > 0 {
+					return fmt.Errorf("marked %d entries before failing: %w", len(result.Entries), err)
+				}
+				return err
+			}
+		}
+
+		if err := outputReviewResult(result); err != nil {
+			return err
+		}
+		reportDryRun(preview)
+		return nil
+	}
+}
+
+// markPeriodReviewed marks every entry from start to end that isn't marked
+// so yet, one write per day, adding them to result
+func markPeriodReviewed(store storage.DailyLogStorage, start, end, reviewedAt time.Time, result *ReviewResult) error {
+	dates, err := store.ListDays(start, end)
+	if err != nil {
+		return fmt.Errorf("failed to list days: %w", err)
+	}
+	for _, date := range dates {
+		session, err := storage.BeginDayEdit(store, date)
+		if err != nil {
+			return err
+		}
+		entries := append([]storage.DailyLogEntry(nil), session.Day().Entries...)
+		for _, entry := range entries {
+			if entry.Reviewed() == result.Reviewed {
+				result.Skipped++
+				continue
+			}
+			updated, err := session.Update(storage.UpdateLogEntryRequest{ID: entry.ID, ReviewedAt: &reviewedAt})
+			if err != nil {
+				return err
+			}
+			result.Entries = append(result.Entries, *updated)
+		}
+		if err := session.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func outputReviewResult(result ReviewResult) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+	if quiet() {
+		printIDs(result.Entries)
+		return nil
+	}
+
+	state := "reviewed"
+	if !result.Reviewed {
+		state = "not reviewed"
+	}
+	already := ""
+	if result.Skipped > 0 {
+		already = fmt.Sprintf(" (%d already were)", result.Skipped)
+	}
+	switch {
+	case len(result.Entries) == 0:
+		fmt.Printf("No entries to mark %s%s\n", state, already)
+		return nil
+	case result.DryRun:
+		fmt.Printf("Would mark %d entries %s%s\n", len(result.Entries), state, already)
+	default:
+		fmt.Printf("✓ Marked %d entries %s%s\n", len(result.Entries), state, already)
+	}
+	ids := shortIDs(result.Entries)
+	for _, entry := range result.Entries {
+		fmt.Printf("  %s %s %s %s\n", style(styleDim, entry.Timestamp.Local().Format("01-02 15:04")),
+			column(styleType(entry.Type), 10), ids[entry.ID], entry.Title)
+	}
+	return nil
+}
+
+func runReviewStatus(cmd *cobra.Command, args []string) error {
+	period, _ := cmd.Flags().GetString("period")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+
+	start, end, err := resolveTimePeriod(period, dateStartStr, dateEndStr, time.Now())
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{DateStart: &start, DateEnd: &end})
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	status := ReviewStatus{
+		Period:        fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+		ReviewBacklog: storage.SummarizeReviews(result.Entries),
+	}
+	if ok, err := outputStructured(status); ok {
+		return err
+	}
+
+	title := "Review backlog - " + status.Period
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+	fmt.Println()
+
+	switch {
+	case status.Entries == 0:
+		fmt.Println("No entries found.")
+		return nil
+	case status.Unreviewed == 0:
+		fmt.Printf("✓ All %d entries reviewed\n", status.Entries)
+	default:
+		fmt.Printf("%d of %d entries to review, the oldest from %s\n", status.Unreviewed, status.Entries,
+			status.OldestUnreviewed.Local().Format("Mon 2006-01-02"))
+	}
+	fmt.Println()
+
+	fmt.Println(style(styleBold, fmt.Sprintf("%-10s %7s %9s", "WEEK", "ENTRIES", "TO REVIEW")))
+	for _, week := range status.Weeks {
+		toReview := style(styleGreen, fmt.Sprintf("%9s", "✓"))
+		if week.Unreviewed > 0 {
+			toReview = style(styleYellow, fmt.Sprintf("%9d", week.Unreviewed))
+		}
+		fmt.Printf("%-10s %7d %s\n", week.Week, week.Entries, toReview)
+	}
+	return nil
+}
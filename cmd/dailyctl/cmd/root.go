@@ -6,6 +6,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/providers"
 )
 
 var (
@@ -28,6 +31,7 @@ Examples:
   dailyctl get today
   dailyctl search --query "exercise" --status-min 7
   dailyctl summarize week`,
+	PersistentPreRunE: preRun,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -35,6 +39,13 @@ func Execute(v, c, d string) error {
 	version = v
 	commit = c
 	date = d
+	defer closeMirrors()
+	defer stopPager()
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError{err}
+	})
+	// main reports errors, as JSON or YAML when that is the output format
+	rootCmd.SilenceErrors = true
 	return rootCmd.Execute()
 }
 
@@ -42,50 +53,70 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.dailyctl.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $DAILYLOG_CONFIG, or $HOME/.dailyctl.yaml)")
 	rootCmd.PersistentFlags().String("github-repo", "", "GitHub repository for storage (owner/repo)")
 	rootCmd.PersistentFlags().String("github-token", "", "GitHub personal access token")
 	rootCmd.PersistentFlags().String("github-path", "logs", "Path within GitHub repo for logs")
+	rootCmd.PersistentFlags().String("github-base-url", "", "GitHub API URL for GitHub Enterprise Server or a proxy (default: github.com)")
+	rootCmd.PersistentFlags().String("github-branch", "", "Branch to write logs to (default: the repository's default branch)")
+	rootCmd.PersistentFlags().String("record", "", "Record GitHub API requests and responses to this file")
+	rootCmd.PersistentFlags().String("replay", "", "Answer GitHub API requests from a file made with --record, without the network")
+	rootCmd.PersistentFlags().Bool("demo", false, "Use sample data in memory instead of the configured storage; nothing is saved")
 	rootCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table, json, yaml")
 	rootCmd.PersistentFlags().Bool("verbose", false, "Enable verbose output")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print only entry IDs instead of tables, for scripts")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also set by NO_COLOR)")
+	rootCmd.PersistentFlags().Bool("no-pager", false, "Don't send long output of get, search, and export through $PAGER")
 
 	// Bind flags to viper
 	_ = viper.BindPFlag("github.repo", rootCmd.PersistentFlags().Lookup("github-repo"))
 	_ = viper.BindPFlag("github.token", rootCmd.PersistentFlags().Lookup("github-token"))
 	_ = viper.BindPFlag("github.path", rootCmd.PersistentFlags().Lookup("github-path"))
+	_ = viper.BindPFlag("github.base_url", rootCmd.PersistentFlags().Lookup("github-base-url"))
+	_ = viper.BindPFlag("github.branch", rootCmd.PersistentFlags().Lookup("github-branch"))
+	_ = viper.BindPFlag("github.record", rootCmd.PersistentFlags().Lookup("record"))
+	_ = viper.BindPFlag("github.replay", rootCmd.PersistentFlags().Lookup("replay"))
+	_ = viper.BindPFlag("demo", rootCmd.PersistentFlags().Lookup("demo"))
 	_ = viper.BindPFlag("output.format", rootCmd.PersistentFlags().Lookup("output"))
 	_ = viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	_ = viper.BindPFlag("output.quiet", rootCmd.PersistentFlags().Lookup("quiet"))
+	_ = viper.BindPFlag("output.no_color", rootCmd.PersistentFlags().Lookup("no-color"))
+	_ = viper.BindPFlag("output.no_pager", rootCmd.PersistentFlags().Lookup("no-pager"))
 }
 
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
-
-		// Search config in home directory with name ".dailyctl" (without extension).
-		viper.AddConfigPath(home)
-		viper.AddConfigPath(".")
-		viper.SetConfigType("yaml")
-		viper.SetConfigName(".dailyctl")
+// preRun checks the output format and sets up recording or replaying
+// GitHub API requests
+func preRun(cmd *cobra.Command, args []string) error {
+	if err := checkOutputFormat(cmd, args); err != nil {
+		return err
 	}
+	return useCassette()
+}
 
-	// Environment variables
-	viper.SetEnvPrefix("DAILYLOG")
-	viper.AutomaticEnv()
-
-	// Explicit environment variable bindings (viper doesn't auto-map dots to underscores)
-	_ = viper.BindEnv("github.repo", "DAILYLOG_GITHUB_REPO")
-	_ = viper.BindEnv("github.token", "DAILYLOG_GITHUB_TOKEN")
-	_ = viper.BindEnv("github.path", "DAILYLOG_GITHUB_PATH")
+// useCassette records GitHub API requests with github.record, or replays
+// them with github.replay
+func useCassette() error {
+	record, replay := viper.GetString("github.record"), viper.GetString("github.replay")
+	switch {
+	case record != "" && replay != "":
+		return usageError{fmt.Errorf("--record and --replay can't be used together")}
+	case record != "":
+		return providers.UseCassette(record, providers.CassetteRecord)
+	case replay != "":
+		return providers.UseCassette(replay, providers.CassetteReplay)
+	}
+	return nil
+}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil && viper.GetBool("verbose") {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+// initConfig reads in config file and ENV variables if set.
+func initConfig() {
+	used, err := config.Load(viper.GetViper(), cfgFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+		return
+	}
+	if used != "" && viper.GetBool("verbose") {
+		fmt.Fprintln(os.Stderr, "Using config file:", used)
 	}
 }
 
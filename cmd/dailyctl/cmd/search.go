@@ -6,7 +6,9 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
+	"dailylog/internal/datetime"
 	"dailylog/internal/storage"
 )
 
@@ -19,8 +21,12 @@ var searchCmd = &cobra.Command{
 Examples:
   dailyctl search --query "exercise"
   dailyctl search --tags work,meeting
+  dailyctl search --location office --date-start 2025-09-01
   dailyctl search --status-min 8 --status-max 10
-  dailyctl search --query "project" --type activity --date-start 2025-09-01`,
+  dailyctl search --query "project" --type activity --date-start 2025-09-01
+  dailyctl search --tags meeting --date-start 2025-09-01 --aggregate week,tag
+  dailyctl search --type status --sort mood --limit 10
+  dailyctl search --query "project" --format alfred`,
 	RunE: runSearch,
 }
 
@@ -29,13 +35,23 @@ func init() {
 
 	// Add search flags
 	searchCmd.Flags().String("query", "", "Search text in titles and descriptions")
-	searchCmd.Flags().String("date-start", "", "Start date for search range (YYYY-MM-DD)")
-	searchCmd.Flags().String("date-end", "", "End date for search range (YYYY-MM-DD)")
+	searchCmd.Flags().String("date-start", "", "Start date for search range (YYYY-MM-DD or e.g. \"3 weeks ago\")")
+	searchCmd.Flags().String("date-end", "", "End date for search range (YYYY-MM-DD or e.g. \"end of last month\")")
 	searchCmd.Flags().String("type", "", "Filter by entry type")
 	searchCmd.Flags().StringSlice("tags", []string{}, "Filter by tags")
+	searchCmd.Flags().String("location", "", "Filter by location (aliases from locations.aliases match too)")
 	searchCmd.Flags().Int("status-min", 0, "Minimum status rating")
 	searchCmd.Flags().Int("status-max", 0, "Maximum status rating")
+	searchCmd.Flags().Bool("pinned", false, "Only pinned entries")
+	searchCmd.Flags().Bool("unreviewed", false, "Only entries not yet marked reviewed")
+	searchCmd.Flags().String("author", "", "Only entries by this author")
 	searchCmd.Flags().Int("limit", 50, "Maximum number of results")
+	searchCmd.Flags().StringSlice("aggregate", []string{}, "Roll up all matches by: tag, type, day, week, project, location")
+	searchCmd.Flags().String("sort", "", sortUsage)
+	searchCmd.Flags().String("format", "", launcherFormatUsage)
+
+	_ = searchCmd.RegisterFlagCompletionFunc("type", completeEntryTypes)
+	_ = searchCmd.RegisterFlagCompletionFunc("tags", completeTags)
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
@@ -45,57 +61,80 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	dateEndStr, _ := cmd.Flags().GetString("date-end")
 	entryType, _ := cmd.Flags().GetString("type")
 	tags, _ := cmd.Flags().GetStringSlice("tags")
+	location, _ := cmd.Flags().GetString("location")
 	statusMin, _ := cmd.Flags().GetInt("status-min")
 	statusMax, _ := cmd.Flags().GetInt("status-max")
+	pinned, _ := cmd.Flags().GetBool("pinned")
+	unreviewed, _ := cmd.Flags().GetBool("unreviewed")
+	author, _ := cmd.Flags().GetString("author")
 	limit, _ := cmd.Flags().GetInt("limit")
+	aggregations, _ := cmd.Flags().GetStringSlice("aggregate")
+	order, err := entrySortOrder(cmd)
+	if err != nil {
+		return err
+	}
+	format, err := launcherFormat(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Validate that at least one search criterion is provided
-	if query == "" && entryType == "" && len(tags) == 0 && statusMin == 0 && statusMax == 0 {
-		return fmt.Errorf("at least one search criterion must be provided")
+	if query == "" && entryType == "" && len(tags) == 0 && location == "" && statusMin == 0 && statusMax == 0 && !pinned && !unreviewed && author == "" {
+		return invalidArgf("at least one search criterion must be provided")
 	}
 
 	// Parse dates
 	var dateStart, dateEnd *time.Time
 	if dateStartStr != "" {
-		start, err := time.Parse("2006-01-02", dateStartStr)
+		start, err := datetime.ParseDate(dateStartStr, time.Now())
 		if err != nil {
-			return fmt.Errorf("invalid start date format: %s (use YYYY-MM-DD)", dateStartStr)
+			return invalidArgf("invalid start date: %s (use YYYY-MM-DD or e.g. \"3 weeks ago\")", dateStartStr)
 		}
 		dateStart = &start
 	}
 	if dateEndStr != "" {
-		end, err := time.Parse("2006-01-02", dateEndStr)
+		end, err := datetime.ParseDate(dateEndStr, time.Now())
 		if err != nil {
-			return fmt.Errorf("invalid end date format: %s (use YYYY-MM-DD)", dateEndStr)
+			return invalidArgf("invalid end date: %s (use YYYY-MM-DD or e.g. \"end of last month\")", dateEndStr)
 		}
 		dateEnd = &end
 	}
 
 	// Validate status range
 	if statusMin < 0 || statusMin > 10 {
-		return fmt.Errorf("status-min must be between 1 and 10")
+		return invalidArgf("status-min must be between 1 and 10")
 	}
 	if statusMax < 0 || statusMax > 10 {
-		return fmt.Errorf("status-max must be between 1 and 10")
+		return invalidArgf("status-max must be between 1 and 10")
 	}
 	if statusMin > 0 && statusMax > 0 && statusMin > statusMax {
 		return fmt.Errorf("status-min cannot be greater than status-max")
 	}
 
+	if err := storage.ValidateAggregations(aggregations); err != nil {
+		return err
+	}
+
 	// Create storage provider
 	storageProvider, err := createStorageProvider()
 	if err != nil {
-		return fmt.Errorf("failed to create storage provider: %v", err)
+		return fmt.Errorf("failed to create storage provider: %w", err)
 	}
 
 	// Build search request
 	searchReq := storage.LogSearchRequest{
-		SearchText: query,
-		DateStart:  dateStart,
-		DateEnd:    dateEnd,
-		Type:       entryType,
-		Tags:       tags,
-		Limit:      limit,
+		SearchText:   query,
+		DateStart:    dateStart,
+		DateEnd:      dateEnd,
+		Type:         entryType,
+		Tags:         tags,
+		Location:     location,
+		Pinned:       pinned,
+		Unreviewed:   unreviewed,
+		Author:       author,
+		Limit:        limit,
+		Aggregations: aggregations,
+		Sort:         order.String(),
 	}
 
 	if statusMin > 0 {
@@ -108,20 +147,52 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	// Perform search
 	searchResult, err := storageProvider.SearchLogs(searchReq)
 	if err != nil {
-		return fmt.Errorf("search failed: %v", err)
+		return fmt.Errorf("search failed: %w", err)
 	}
 
 	// Output results
+	if format != "" {
+		return outputLauncher(format, searchResult.Entries)
+	}
+	startPager()
+	if ok, err := outputStructured(searchResult); ok {
+		return err
+	}
 	return outputSearchResults(searchResult, query)
 }
 
+// sortUsage describes the --sort flag of get and search
+const sortUsage = "Sort by time, status (or mood), or priority, optionally with :asc or :desc (default: output.sort, or time)"
+
+// entrySortOrder reads --sort, falling back to the output.sort setting
+func entrySortOrder(cmd *cobra.Command) (storage.SortOrder, error) {
+	spec := viper.GetString("output.sort")
+	if cmd != nil && cmd.Flags().Changed("sort") {
+		spec, _ = cmd.Flags().GetString("sort")
+	}
+	return storage.ParseSortOrder(spec)
+}
+
 func outputSearchResults(result *storage.LogSearchResponse, query string) error {
-	fmt.Printf("Search Results")
+	if quiet() {
+		printIDs(result.Entries)
+		return nil
+	}
+	if ok, err := printOutputTemplate("search", map[string]any{
+		"Query":        query,
+		"Entries":      templateEntries(result.Entries),
+		"Total":        result.TotalCount,
+		"Aggregations": result.Aggregations,
+	}); ok {
+		return err
+	}
+
+	title := "Search Results"
 	if query != "" {
-		fmt.Printf(" for '%s'", query)
+		title += fmt.Sprintf(" for '%s'", query)
 	}
-	fmt.Println()
-	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", 50))
 	fmt.Println()
 
 	if len(result.Entries) == 0 {
@@ -129,67 +200,74 @@ func outputSearchResults(result *storage.LogSearchResponse, query string) error
 		return nil
 	}
 
-	// Group entries by date for better readability
-	entriesByDate := make(map[string][]storage.DailyLogEntry)
+	// Entries come sorted; in time order they are grouped under their date,
+	// otherwise each shows its own date so the sort order is kept
+	order, _ := storage.ParseSortOrder(result.SearchQuery.Sort)
+	byTime := order.Key == storage.SortByTime
+	entriesPerDate := make(map[string]int)
 	for _, entry := range result.Entries {
-		dateKey := entry.Timestamp.Format("2006-01-02")
-		entriesByDate[dateKey] = append(entriesByDate[dateKey], entry)
+		entriesPerDate[entry.Timestamp.Format("2006-01-02")]++
 	}
 
-	// Sort dates and display
-	var dates []string
-	for date := range entriesByDate {
-		dates = append(dates, date)
+	timeLayout := "15:04"
+	if !byTime {
+		timeLayout = "2006-01-02 15:04"
 	}
 
-	// Simple sort (for more robust sorting, would use sort package)
-	for i := 0; i < len(dates); i++ {
-		for j := i + 1; j < len(dates); j++ {
-			if dates[i] > dates[j] {
-				dates[i], dates[j] = dates[j], dates[i]
-			}
+	ids := shortIDs(result.Entries)
+	lastDate := ""
+	for _, entry := range result.Entries {
+		date := entry.Timestamp.Format("2006-01-02")
+		if byTime && date != lastDate {
+			fmt.Println(style(styleBold, fmt.Sprintf("📅 %s (%d entries)", date, entriesPerDate[date])))
+			fmt.Println(rule("-", 30))
+			lastDate = date
 		}
-	}
 
-	for _, date := range dates {
-		entries := entriesByDate[date]
-		fmt.Printf("📅 %s (%d entries)\n", date, len(entries))
-		fmt.Println(strings.Repeat("-", 30))
-
-		for _, entry := range entries {
-			fmt.Printf("  🕐 %s - %s [%s]\n",
-				entry.Timestamp.Format("15:04"), entry.Title, entry.Type)
-
-			if entry.Description != "" {
-				fmt.Printf("     %s\n", entry.Description)
-			}
-
-			// Show metadata
-			var metadata []string
-			if len(entry.Tags) > 0 {
-				metadata = append(metadata, fmt.Sprintf("Tags: %s", strings.Join(entry.Tags, ", ")))
-			}
-			if entry.Status > 0 {
-				metadata = append(metadata, fmt.Sprintf("Status: %d/10", entry.Status))
-			}
-			if entry.Priority > 0 {
-				metadata = append(metadata, fmt.Sprintf("Priority: %d/5", entry.Priority))
-			}
-			if entry.Duration != nil && *entry.Duration > 0 {
-				metadata = append(metadata, fmt.Sprintf("Duration: %dm", *entry.Duration))
-			}
-			if entry.Location != "" {
-				metadata = append(metadata, fmt.Sprintf("Location: %s", entry.Location))
-			}
-
-			if len(metadata) > 0 {
-				fmt.Printf("     %s\n", strings.Join(metadata, " | "))
-			}
-
-			fmt.Println()
+		heading := fmt.Sprintf("%s - %s", entry.Timestamp.Format(timeLayout), pinnedTitle(entry))
+		lines := wrapText(heading, wrapWidth(5))
+		fmt.Printf("  🕐 %s [%s]\n", strings.Join(lines, "\n     "), styleType(entry.Type))
+
+		if entry.Description != "" {
+			printWrapped(entry.Description, 5, "")
+		}
+
+		// Show metadata
+		metadata := []string{fmt.Sprintf("ID: %s", ids[entry.ID])}
+		if len(entry.Tags) > 0 {
+			metadata = append(metadata, fmt.Sprintf("Tags: %s", strings.Join(entry.Tags, ", ")))
+		}
+		if entry.Status > 0 {
+			metadata = append(metadata, "Status: "+formatStatus(entry.Status))
+		}
+		if entry.Priority > 0 {
+			metadata = append(metadata, "Priority: "+formatPriority(entry.Priority))
+		}
+		if entry.Duration != nil && *entry.Duration > 0 {
+			metadata = append(metadata, fmt.Sprintf("Duration: %dm", *entry.Duration))
 		}
+		if entry.Location != "" {
+			metadata = append(metadata, fmt.Sprintf("Location: %s", entry.Location))
+		}
+
+		fmt.Printf("     %s\n", strings.Join(metadata, style(styleDim, " | ")))
+
+		fmt.Println()
 	}
 
 	fmt.Printf("Found %d entries total\n", result.TotalCount)
+
+	for _, aggregation := range result.Aggregations {
+		outputAggregation(aggregation)
+	}
 	return nil
 }
+
+func outputAggregation(aggregation storage.Aggregation) {
+	fmt.Printf("\nBy %s:\n", aggregation.GroupBy)
+	fmt.Println(style(styleBold, fmt.Sprintf("  %-20s %6s %9s %7s %9s", strings.ToUpper(aggregation.GroupBy), "COUNT", "DURATION", "STATUS", "PRIORITY")))
+	for _, bucket := range aggregation.Buckets {
+		fmt.Printf("  %-20s %6d %8dm %7.1f %9.1f\n",
+			bucket.Key, bucket.Count, bucket.TotalDuration, bucket.AverageStatus, bucket.AveragePriority)
+	}
+}
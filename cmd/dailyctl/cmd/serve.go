@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/metrics"
+	"dailylog/internal/storage"
+	"dailylog/internal/web"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the web dashboard and webhooks for logging from Slack, email, and phones",
+	Long: `Serve a read-only web dashboard with a calendar, day view, search box,
+and status trend chart, backed by the configured storage, along with the
+webhooks below that log entries from Slack, email, and phone automations.
+
+The dashboard and its API show every entry, private ones included. Set
+serve.token (DAILYLOG_SERVE_TOKEN) to require it, as the password for the
+browser's sign-in prompt (any user name) or as a bearer token. It is required
+to listen on an address other than loopback, or with any webhook enabled, as
+those are meant to be reached from outside. Webhooks check their own secrets.
+
+The dashboard also serves /healthz, which succeeds while the process is up,
+and /readyz, which checks that storage is reachable, for running under
+systemd or Kubernetes. On SIGINT or SIGTERM, /readyz starts failing and
+requests in flight get up to --shutdown-timeout to finish.
+
+With --metrics, Prometheus metrics for GitHub API requests and the AI
+summary cache are served at /metrics too, behind serve.token if set.
+
+With slack.signing_secret set, teammates can log entries from Slack with a
+slash command such as /dailylog note "Pairing went well" #team, sent to
+/slack/command. Create a Slack app with a slash command whose request URL is
+https://<host>/slack/command, and set its signing secret:
+  slack.signing_secret  DAILYLOG_SLACK_SIGNING_SECRET
+  slack.teams           DAILYLOG_SLACK_TEAMS  workspace (team) IDs allowed,
+                                              default any
+Entries record who logged them in slack_user metadata.
+
+With email_in.token set, emails forwarded by a mail service's inbound webhook
+(SendGrid Inbound Parse, Mailgun routes, or any service posting the raw
+message) to /email/inbound?token=<token> are logged as notes: the subject is
+the title, the body the description, and attached files are stored in the log
+repository alongside the entry:
+  email_in.token    DAILYLOG_EMAIL_IN_TOKEN
+  email_in.senders  DAILYLOG_EMAIL_IN_SENDERS  addresses allowed to log,
+                                               default any
+
+With capture.token set, Apple Shortcuts, Android Tasker, and other simple
+clients can log entries with a GET or POST to /capture?token=<token>, with
+parameters such as text="Gym #health ~1h", or title, type, tags, status,
+duration, and datetime; x-success and x-error redirect back to the calling
+app. See the README for ready-made shortcut settings.
+  capture.token  DAILYLOG_CAPTURE_TOKEN
+
+With --remind, reminder checks (see 'dailyctl remind') run alongside the
+dashboard using the remind.* configuration.
+
+Examples:
+  dailyctl serve
+  dailyctl serve --addr 127.0.0.1:9000
+  DAILYLOG_SERVE_TOKEN=... dailyctl serve --addr 0.0.0.0:8080
+  dailyctl serve --remind --at 12:00,17:00
+  dailyctl serve --metrics`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().Duration("shutdown-timeout", 10*time.Second, "How long to let requests in flight finish when stopping")
+	serveCmd.Flags().Bool("metrics", false, "Also serve Prometheus metrics at /metrics")
+	serveCmd.Flags().Bool("remind", false, "Also run reminder checks while serving")
+	serveCmd.Flags().StringSlice("at", []string{"12:00", "17:00"}, "Times of day to check when --remind is set (HH:MM)")
+
+	_ = viper.BindPFlag("serve.addr", serveCmd.Flags().Lookup("addr"))
+	_ = viper.BindPFlag("serve.shutdown_timeout", serveCmd.Flags().Lookup("shutdown-timeout"))
+	_ = viper.BindPFlag("serve.metrics", serveCmd.Flags().Lookup("metrics"))
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	remind, _ := cmd.Flags().GetBool("remind")
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if remind {
+		atValues := viper.GetStringSlice("remind.times")
+		if cmd.Flags().Changed("at") {
+			atValues, _ = cmd.Flags().GetStringSlice("at")
+		}
+		times, err := parseReminderTimes(atValues)
+		if err != nil {
+			return err
+		}
+		notifier, err := buildReminderNotifier()
+		if err != nil {
+			return err
+		}
+		go runReminderLoop(ctx, storageProvider, notifier, times)
+	}
+
+	dashboard := web.NewServer(storageProvider)
+	addr := viper.GetString("serve.addr")
+	if err := requireServeToken(dashboard, addr); err != nil {
+		return err
+	}
+	if viper.GetBool("serve.metrics") {
+		dashboard.Handle("/metrics", metrics.Default.Handler())
+	}
+	if secret := viper.GetString("slack.signing_secret"); secret != "" {
+		slack, err := web.NewSlackHandler(storageProvider, secret, config.List(viper.GetViper(), "slack.teams"))
+		if err != nil {
+			return err
+		}
+		dashboard.HandleWebhook("POST /slack/command", slack)
+	}
+	if token := viper.GetString("capture.token"); token != "" {
+		capture, err := web.NewCaptureHandler(storageProvider, token)
+		if err != nil {
+			return err
+		}
+		dashboard.HandleWebhook("GET /capture", capture)
+		dashboard.HandleWebhook("POST /capture", capture)
+	}
+	if token := viper.GetString("email_in.token"); token != "" {
+		email, err := web.NewEmailHandler(storageProvider, token, config.List(viper.GetViper(), "email_in.senders"))
+		if err != nil {
+			return err
+		}
+		if !viper.GetBool("demo") {
+			primary, err := createPrimaryProvider()
+			if err != nil {
+				return fmt.Errorf("failed to create storage provider: %w", err)
+			}
+			email.Attach = func(t time.Time, name string, data []byte) (string, error) {
+				attachment := primary.AttachmentPath(t, name)
+				if err := primary.SaveAttachment(attachment, data); err != nil {
+					return "", err
+				}
+				return attachment, nil
+			}
+		}
+		dashboard.HandleWebhook("POST /email/inbound", email)
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           dashboard,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	fmt.Printf("Serving dashboard on http://%s (Ctrl+C to stop)\n", addr)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	fmt.Println("Shutting down...")
+	dashboard.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("serve.shutdown_timeout"))
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// webhookKeys are the settings that enable a webhook, served to the outside world
+var webhookKeys = []string{"slack.signing_secret", "email_in.token", "capture.token"}
+
+// requireServeToken puts the dashboard behind serve.token. Without one it
+// refuses to serve anywhere but loopback, or with webhooks enabled, as the
+// dashboard would show every entry to anyone who can reach it.
+func requireServeToken(dashboard *web.Server, addr string) error {
+	if token := viper.GetString("serve.token"); token != "" {
+		dashboard.RequireToken(token)
+		return nil
+	}
+	if !isLoopback(addr) {
+		return storage.ValidationError{Field: "serve.token", Message: fmt.Sprintf(
+			"must be set (or %s) to serve on %s, as the dashboard shows every entry, private ones included",
+			config.EnvName("serve.token"), addr)}
+	}
+	for _, key := range webhookKeys {
+		if viper.GetString(key) != "" {
+			return storage.ValidationError{Field: "serve.token", Message: fmt.Sprintf(
+				"must be set (or %s) to serve with %s set, as the dashboard shows every entry, private ones included, to anyone who can reach the webhooks",
+				config.EnvName("serve.token"), key)}
+		}
+	}
+	return nil
+}
+
+// isLoopback reports whether addr listens only on a loopback interface
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
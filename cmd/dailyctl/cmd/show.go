@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// showCmd represents the show command
+var showCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show one entry by ID or ID prefix",
+	Long: `Show a single entry by its ID or any unambiguous prefix of it, such as the
+short IDs listed by get and search.
+
+Entry IDs are ULIDs that encode the entry's time, so a prefix is enough to
+find the right day. Older entry_<number> IDs encode when the entry was created
+instead; pass --date for those if the entry was logged for a different day.
+
+Examples:
+  dailyctl show 01J3F2QK
+  dailyctl show 01J3F2QKX4TB7V9M2H6DZCRY5A -o json
+  dailyctl show entry_1727612345678901234 --date 2025-09-28`,
+	Args: cobra.ExactArgs(1),
+	RunE: runShow,
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+
+	showCmd.Flags().String("date", "", "Day the entry belongs to (YYYY-MM-DD or e.g. \"yesterday\")")
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	id := args[0]
+	dateStr, _ := cmd.Flags().GetString("date")
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	var entry *storage.DailyLogEntry
+	if dateStr != "" {
+		date, err := datetime.ParseDate(dateStr, time.Now())
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+		}
+		dayLog, err := storageProvider.GetDay(date)
+		if err != nil {
+			return fmt.Errorf("failed to get day: %w", err)
+		}
+		entry, err = storage.ResolveEntryID(id, dayLog.Entries)
+		if err != nil {
+			return err
+		}
+	} else {
+		entry, err = storage.FindEntry(storageProvider, id)
+		if _, notFound := err.(storage.NotFoundError); notFound && storage.IsLegacyEntryID(id) {
+			return fmt.Errorf("%w (not on the day it was created; pass --date with the entry's day)", err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if ok, err := outputStructured(entry); ok {
+		return err
+	}
+
+	fmt.Printf("%s entry: %s\n", entry.Type, entry.Title)
+	if entry.Description != "" {
+		fmt.Printf("  %s\n", entry.Description)
+	}
+	printEntryDetails(entry)
+	if len(entry.Metadata) > 0 {
+		keys := make([]string, 0, len(entry.Metadata))
+		for key := range entry.Metadata {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		fmt.Println("  Metadata:")
+		for _, key := range keys {
+			fmt.Printf("    %s: %s\n", key, entry.Metadata[key])
+		}
+	}
+	return nil
+}
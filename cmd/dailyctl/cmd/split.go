@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// SplitResult lists the entries an entry was split into
+type SplitResult struct {
+	Date    string                  `json:"date" yaml:"date"`
+	Entries []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+	DryRun  bool                    `json:"dry_run" yaml:"dry_run"`
+}
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split <id>",
+	Short: "Split one entry into several",
+	Long: `Split a long entry into separate entries, saved in a single commit.
+
+The entry's title and description are cut into parts, either at each
+--delimiter or where you put a --- line in your editor (--edit). The first
+line of each part is its title and the rest its description. The first part
+keeps the entry's ID, so task notes linked to it still work; the others get new
+IDs and split_from metadata pointing back at it. Every part keeps the entry's
+time, type, tags, and ratings, and its duration is shared out evenly.
+
+Examples:
+  dailyctl split 01J3F2QK --delimiter ";"
+  dailyctl split 01J3F2QK --edit
+  dailyctl split 01J3F2QK --delimiter "\n\n" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSplit,
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().String("delimiter", "", "Split the title and description at each occurrence of this text (\\n for a newline)")
+	splitCmd.Flags().Bool("edit", false, "Mark where to split in $EDITOR with --- lines")
+	splitCmd.Flags().String("date", "", "Day the entry is logged on (YYYY-MM-DD or e.g. \"yesterday\")")
+	splitCmd.Flags().Bool("dry-run", false, dryRunUsage)
+}
+
+func runSplit(cmd *cobra.Command, args []string) error {
+	delimiter, _ := cmd.Flags().GetString("delimiter")
+	edit, _ := cmd.Flags().GetBool("edit")
+	if (delimiter == "") == !edit {
+		return invalidArgf("use either --delimiter or --edit")
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	session, err := beginEntryEdit(cmd, storageProvider, args[0])
+	if err != nil {
+		return err
+	}
+	entry, err := storage.ResolveEntryID(args[0], session.Day().Entries)
+	if err != nil {
+		return err
+	}
+
+	text := entry.Title
+	if entry.Description != "" {
+		text += "\n" + entry.Description
+	}
+	var parts []string
+	if edit {
+		if parts, err = splitInEditor(text); err != nil {
+			return err
+		}
+	} else {
+		parts = storage.SplitText(text, strings.ReplaceAll(delimiter, `\n`, "\n"))
+	}
+
+	split, err := session.Split(entry.ID, parts)
+	if err != nil {
+		return err
+	}
+	if err := session.Commit(); err != nil {
+		return fmt.Errorf("failed to save split entries: %w", err)
+	}
+
+	result := SplitResult{Date: session.Day().GetDateString(), Entries: split, DryRun: preview != nil}
+	if err := outputSplitResult(result); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
+
+func outputSplitResult(result SplitResult) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	if quiet() {
+		printIDs(result.Entries)
+		return nil
+	}
+	if result.DryRun {
+		fmt.Printf("Would split entry %s into %d entries:\n", result.Entries[0].ID, len(result.Entries))
+	} else {
+		fmt.Printf("✓ Split entry %s into %d entries:\n", result.Entries[0].ID, len(result.Entries))
+	}
+	for _, entry := range result.Entries {
+		fmt.Printf("  %s  [%s] %s\n", entry.ID, entry.Type, entry.Title)
+	}
+	return nil
+}
+
+// beginEntryEdit starts editing the day holding the entry with the given ID
+// or ID prefix, which is the day given by --date if set
+func beginEntryEdit(cmd *cobra.Command, store storage.DailyLogStorage, id string) (*storage.DayEditSession, error) {
+	dateStr, _ := cmd.Flags().GetString("date")
+	if dateStr != "" {
+		date, err := datetime.ParseDate(dateStr, time.Now())
+		if err != nil {
+			return nil, invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+		}
+		return storage.BeginDayEdit(store, date)
+	}
+
+	entry, err := storage.FindEntry(store, id)
+	if _, notFound := err.(storage.NotFoundError); notFound {
+		return nil, fmt.Errorf("%w (pass --date with the entry's day)", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return storage.BeginDayEdit(store, entry.Timestamp)
+}
+
+// splitInEditor opens text in the user's editor and returns the parts
+// separated there by --- lines
+func splitInEditor(text string) ([]string, error) {
+	const help = "# Put a line with only --- between the parts to split this entry into.\n" +
+		"# The first line of each part is its title. Lines starting with # here are ignored.\n\n"
+
+	file, err := os.CreateTemp("", "dailyctl-split-*.md")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(help + text + "\n"); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := runEditor(file.Name()); err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited entry: %w", err)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	for len(lines) > 0 && (strings.HasPrefix(lines[0], "#") || strings.TrimSpace(lines[0]) == "") {
+		lines = lines[1:]
+	}
+	var parts []string
+	var current []string
+	for _, line := range append(lines, frontMatterDelimiter) {
+		if strings.TrimSpace(line) != frontMatterDelimiter {
+			current = append(current, line)
+			continue
+		}
+		if part := strings.TrimSpace(strings.Join(current, "\n")); part != "" {
+			parts = append(parts, part)
+		}
+		current = nil
+	}
+	return parts, nil
+}
@@ -4,12 +4,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
+	"dailylog/internal/datetime"
 	"dailylog/internal/storage"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // standupCmd represents the standup command
@@ -19,34 +23,50 @@ var standupCmd = &cobra.Command{
 	Long: `Generate standup reports for daily team meetings.
 
 Supports multiple output formats including Slack-style YAML format.
+Only entries visible to the --audience (team by default) are included, so
+private entries and tags configured under privacy.tags stay out of the report.
+In a log shared by a team, the report has a section for each author, or
+with --author, covers only theirs.
 
 Examples:
   dailyctl standup --format slack-yaml
   dailyctl standup --format slack-yaml --copy
-  dailyctl standup --format json`,
+  dailyctl standup --format json
+  dailyctl standup -o yaml
+  dailyctl standup --audience public
+  dailyctl standup --author alice
+  dailyctl standup team --since "last friday"`,
 	RunE: runStandupReport,
 }
 
 func init() {
 	rootCmd.AddCommand(standupCmd)
 
-	standupCmd.Flags().String("format", "default", "Output format: default, slack-yaml, json")
+	standupCmd.Flags().String("format", "default", "Output format: default, slack-yaml, json, yaml (default follows --output)")
 	standupCmd.Flags().Bool("copy", false, "Copy output to clipboard (macOS)")
-	standupCmd.Flags().String("date", "", "Date for standup (YYYY-MM-DD, defaults to today)")
+	standupCmd.Flags().String("date", "", "Date for standup (YYYY-MM-DD or e.g. \"last friday\", defaults to today)")
+	standupCmd.Flags().String("audience", storage.VisibilityTeam, "Audience to include entries for: private, team, public")
+	standupCmd.Flags().String("author", "", "Only include entries by this author")
 }
 
 func runStandupReport(cmd *cobra.Command, args []string) error {
 	format, _ := cmd.Flags().GetString("format")
 	copyToClipboard, _ := cmd.Flags().GetBool("copy")
 	dateStr, _ := cmd.Flags().GetString("date")
+	audience, _ := cmd.Flags().GetString("audience")
+	author, _ := cmd.Flags().GetString("author")
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
 
 	// Parse date
 	var targetDate time.Time
 	if dateStr != "" {
 		var err error
-		targetDate, err = time.Parse("2006-01-02", dateStr)
+		targetDate, err = datetime.ParseDate(dateStr, time.Now())
 		if err != nil {
-			return fmt.Errorf("invalid date format: %s (use YYYY-MM-DD)", dateStr)
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
 		}
 	} else {
 		targetDate = time.Now()
@@ -55,33 +75,63 @@ func runStandupReport(cmd *cobra.Command, args []string) error {
 	// Create storage provider
 	storageProvider, err := createStorageProvider()
 	if err != nil {
-		return fmt.Errorf("failed to create storage provider: %v", err)
+		return fmt.Errorf("failed to create storage provider: %w", err)
 	}
 
 	// Get yesterday's entries (what was done)
 	yesterday := targetDate.AddDate(0, 0, -1)
 	yesterdayLog, err := storageProvider.GetDay(yesterday)
 	if err != nil {
-		return fmt.Errorf("failed to get yesterday's entries: %v", err)
+		return fmt.Errorf("failed to get yesterday's entries: %w", err)
 	}
 
 	// Get today's entries (what's planned)
 	todayLog, err := storageProvider.GetDay(targetDate)
 	if err != nil {
-		return fmt.Errorf("failed to get today's entries: %v", err)
+		return fmt.Errorf("failed to get today's entries: %w", err)
+	}
+
+	// Keep entries the audience shouldn't see out of the report
+	policy := visibilityPolicy()
+	yesterdayEntries := policy.Filter(yesterdayLog.Entries, audience)
+	todayEntries := policy.Filter(todayLog.Entries, audience)
+	if author != "" {
+		yesterdayEntries = storage.AuthorEntries(yesterdayEntries, author)
+		todayEntries = storage.AuthorEntries(todayEntries, author)
 	}
 
-	// Generate standup report
-	report := generateStandupReport(yesterdayLog.Entries, todayLog.Entries, format, targetDate)
+	// --output json or yaml replace the default format
+	if format == "default" && structuredOutput() {
+		format = viper.GetString("output.format")
+	}
+
+	// Generate standup report; the default format can be replaced by the
+	// output.templates.standup template
+	var report string
+	templated := false
+	if format == "default" {
+		report, templated, err = renderOutputTemplate("standup", map[string]any{
+			"Date":      targetDate,
+			"Yesterday": targetDate.AddDate(0, 0, -1),
+			"Done":      templateEntries(filterActivities(yesterdayEntries)),
+			"Planned":   templateEntries(filterPlannedEntries(yesterdayEntries, todayEntries)),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if !templated {
+		report = generateStandupReport(yesterdayEntries, todayEntries, format, targetDate)
+	}
 
 	if copyToClipboard {
 		// Copy to clipboard (macOS)
 		err := copyToClipboardMacOS(report)
 		if err != nil {
-			fmt.Printf("Warning: Could not copy to clipboard: %v\n\n", err)
+			fmt.Fprintf(statusOut(), "Warning: Could not copy to clipboard: %v\n\n", err)
 		} else {
-			fmt.Println("Report copied to clipboard!")
-			fmt.Println("")
+			fmt.Fprintln(statusOut(), "Report copied to clipboard!")
+			fmt.Fprintln(statusOut(), "")
 		}
 	}
 
@@ -95,6 +145,8 @@ func generateStandupReport(yesterdayEntries, todayEntries []storage.DailyLogEntr
 		return generateSlackYAMLReport(yesterdayEntries, todayEntries, date)
 	case "json":
 		return generateJSONReport(yesterdayEntries, todayEntries, date)
+	case "yaml":
+		return generateYAMLReport(yesterdayEntries, todayEntries, date)
 	default:
 		return generateDefaultReport(yesterdayEntries, todayEntries, date)
 	}
@@ -103,23 +155,43 @@ func generateStandupReport(yesterdayEntries, todayEntries []storage.DailyLogEntr
 func generateSlackYAMLReport(yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time) string {
 	var report strings.Builder
 
-	yesterday := date.AddDate(0, 0, -1)
-
 	report.WriteString(fmt.Sprintf("Standup Report - %s\n", date.Format("2006-01-02")))
 	report.WriteString("```yaml\n")
+	authors := standupAuthors(yesterdayEntries, todayEntries)
+	if len(authors) > 1 {
+		for i, author := range authors {
+			if i > 0 {
+				report.WriteString("\n")
+			}
+			report.WriteString(fmt.Sprintf("%s:\n", authorLabel(author)))
+			writeSlackYAMLSections(&report, storage.AuthorEntries(yesterdayEntries, author),
+				storage.AuthorEntries(todayEntries, author), date, "  ")
+		}
+	} else {
+		writeSlackYAMLSections(&report, yesterdayEntries, todayEntries, date, "")
+	}
+	report.WriteString("```")
+
+	return report.String()
+}
+
+// writeSlackYAMLSections writes the Y and T sections of a standup, indented
+// under an author's name in a team report
+func writeSlackYAMLSections(report *strings.Builder, yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time, indent string) {
+	yesterday := date.AddDate(0, 0, -1)
 
 	// Yesterday's work
-	report.WriteString(fmt.Sprintf("Y: # Yesterday (%s)\n", yesterday.Format("Jan 2")))
+	report.WriteString(fmt.Sprintf("%sY: # Yesterday (%s)\n", indent, yesterday.Format("Jan 2")))
 	if len(yesterdayEntries) == 0 {
-		report.WriteString("  - No activities recorded\n")
+		report.WriteString(indent + "  - No activities recorded\n")
 	} else {
 		for _, entry := range yesterdayEntries {
-			if entry.Type == "activity" {
+			if isDoneEntry(entry) {
 				status := ""
 				if entry.Status > 0 {
 					status = fmt.Sprintf(" (status: %d/10)", entry.Status)
 				}
-				report.WriteString(fmt.Sprintf("  - %s%s\n", entry.Title, status))
+				report.WriteString(fmt.Sprintf("%s  - %s%s\n", indent, entry.Title, status))
 			}
 		}
 	}
@@ -127,29 +199,38 @@ func generateSlackYAMLReport(yesterdayEntries, todayEntries []storage.DailyLogEn
 	report.WriteString("\n")
 
 	// Today's plan
-	report.WriteString(fmt.Sprintf("T: # Today (%s)\n", date.Format("Jan 2")))
-	todayPlanned := filterPlannedEntries(todayEntries)
+	report.WriteString(fmt.Sprintf("%sT: # Today (%s)\n", indent, date.Format("Jan 2")))
+	todayPlanned := filterPlannedEntries(yesterdayEntries, todayEntries)
 	if len(todayPlanned) == 0 {
-		report.WriteString("  - Planning session\n")
+		report.WriteString(indent + "  - Planning session\n")
 	} else {
 		for _, entry := range todayPlanned {
 			priority := ""
 			if entry.Priority > 0 {
 				priority = fmt.Sprintf(" (priority: %d/5)", entry.Priority)
 			}
-			report.WriteString(fmt.Sprintf("  - %s%s\n", entry.Title, priority))
+			report.WriteString(fmt.Sprintf("%s  - %s%s\n", indent, entry.Title, priority))
 		}
 	}
+}
 
-	report.WriteString("```")
+func generateJSONReport(yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time) string {
+	return formatJSON(standupData(yesterdayEntries, todayEntries, date))
+}
 
-	return report.String()
+func generateYAMLReport(yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time) string {
+	data, err := yaml.Marshal(standupData(yesterdayEntries, todayEntries, date))
+	if err != nil {
+		return fmt.Sprintf("Error formatting YAML: %v", err)
+	}
+	return string(data)
 }
 
-func generateJSONReport(yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time) string {
+// standupData is the report behind the json and yaml formats
+func standupData(yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time) map[string]interface{} {
 	yesterday := date.AddDate(0, 0, -1)
 
-	report := map[string]interface{}{
+	data := map[string]interface{}{
 		"date": date.Format("2006-01-02"),
 		"yesterday": map[string]interface{}{
 			"date":       yesterday.Format("2006-01-02"),
@@ -157,29 +238,60 @@ func generateJSONReport(yesterdayEntries, todayEntries []storage.DailyLogEntry,
 		},
 		"today": map[string]interface{}{
 			"date":    date.Format("2006-01-02"),
-			"planned": filterPlannedEntries(todayEntries),
+			"planned": filterPlannedEntries(yesterdayEntries, todayEntries),
 		},
 	}
-
-	return formatJSON(report)
+	if authors := standupAuthors(yesterdayEntries, todayEntries); len(authors) > 1 {
+		var sections []map[string]interface{}
+		for _, author := range authors {
+			authorYesterday := storage.AuthorEntries(yesterdayEntries, author)
+			sections = append(sections, map[string]interface{}{
+				"author":     author,
+				"activities": filterActivities(authorYesterday),
+				"planned":    filterPlannedEntries(authorYesterday, storage.AuthorEntries(todayEntries, author)),
+			})
+		}
+		data["authors"] = sections
+	}
+	return data
 }
 
 func generateDefaultReport(yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time) string {
 	var report strings.Builder
-	yesterday := date.AddDate(0, 0, -1)
 
 	report.WriteString(fmt.Sprintf("Standup Report - %s\n", date.Format("2006-01-02")))
 	report.WriteString(strings.Repeat("=", 40))
 	report.WriteString("\n\n")
 
+	authors := standupAuthors(yesterdayEntries, todayEntries)
+	if len(authors) <= 1 {
+		writeDefaultSections(&report, yesterdayEntries, todayEntries, date, "")
+		return report.String()
+	}
+	for i, author := range authors {
+		if i > 0 {
+			report.WriteString("\n")
+		}
+		report.WriteString(authorLabel(author) + "\n")
+		writeDefaultSections(&report, storage.AuthorEntries(yesterdayEntries, author),
+			storage.AuthorEntries(todayEntries, author), date, "  ")
+	}
+	return report.String()
+}
+
+// writeDefaultSections writes what was done yesterday and is planned today,
+// indented under an author's name in a team report
+func writeDefaultSections(report *strings.Builder, yesterdayEntries, todayEntries []storage.DailyLogEntry, date time.Time, indent string) {
+	yesterday := date.AddDate(0, 0, -1)
+
 	// Yesterday
-	report.WriteString(fmt.Sprintf("Yesterday (%s):\n", yesterday.Format("Jan 2")))
+	report.WriteString(fmt.Sprintf("%sYesterday (%s):\n", indent, yesterday.Format("Jan 2")))
 	if len(yesterdayEntries) == 0 {
-		report.WriteString("  • No activities recorded\n")
+		report.WriteString(indent + "  • No activities recorded\n")
 	} else {
 		for _, entry := range yesterdayEntries {
-			if entry.Type == "activity" {
-				report.WriteString(fmt.Sprintf("  • %s\n", entry.Title))
+			if isDoneEntry(entry) {
+				report.WriteString(fmt.Sprintf("%s  • %s\n", indent, entry.Title))
 			}
 		}
 	}
@@ -187,31 +299,66 @@ func generateDefaultReport(yesterdayEntries, todayEntries []storage.DailyLogEntr
 	report.WriteString("\n")
 
 	// Today
-	report.WriteString(fmt.Sprintf("Today (%s):\n", date.Format("Jan 2")))
-	todayPlanned := filterPlannedEntries(todayEntries)
+	report.WriteString(fmt.Sprintf("%sToday (%s):\n", indent, date.Format("Jan 2")))
+	todayPlanned := filterPlannedEntries(yesterdayEntries, todayEntries)
 	if len(todayPlanned) == 0 {
-		report.WriteString("  • Planning session\n")
+		report.WriteString(indent + "  • Planning session\n")
 	} else {
 		for _, entry := range todayPlanned {
-			report.WriteString(fmt.Sprintf("  • %s\n", entry.Title))
+			report.WriteString(fmt.Sprintf("%s  • %s\n", indent, entry.Title))
 		}
 	}
+}
 
-	return report.String()
+// standupAuthors returns the authors of the entries, sorted, with "" for
+// entries without one last
+func standupAuthors(entries ...[]storage.DailyLogEntry) []string {
+	seen := make(map[string]bool)
+	var authors []string
+	for _, list := range entries {
+		for _, entry := range list {
+			if !seen[strings.ToLower(entry.Author)] {
+				seen[strings.ToLower(entry.Author)] = true
+				authors = append(authors, entry.Author)
+			}
+		}
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i] == "" || authors[j] == "" {
+			return authors[j] == ""
+		}
+		return strings.ToLower(authors[i]) < strings.ToLower(authors[j])
+	})
+	return authors
+}
+
+// authorLabel names an author's section of a team report
+func authorLabel(author string) string {
+	if author == "" {
+		return "(no author)"
+	}
+	return author
+}
+
+// isDoneEntry reports whether an entry is work to report as done: an activity or a meeting
+func isDoneEntry(entry storage.DailyLogEntry) bool {
+	return (entry.Type == "activity" || entry.Type == storage.EntryTypeMeeting) && !storage.IsPlanned(entry)
 }
 
 func filterActivities(entries []storage.DailyLogEntry) []storage.DailyLogEntry {
 	var activities []storage.DailyLogEntry
 	for _, entry := range entries {
-		if entry.Type == "activity" {
+		if isDoneEntry(entry) {
 			activities = append(activities, entry)
 		}
 	}
 	return activities
 }
 
-func filterPlannedEntries(entries []storage.DailyLogEntry) []storage.DailyLogEntry {
-	var planned []storage.DailyLogEntry
+// filterPlannedEntries returns the action items from yesterday's meetings
+// followed by today's activities
+func filterPlannedEntries(yesterdayEntries, entries []storage.DailyLogEntry) []storage.DailyLogEntry {
+	planned := storage.ActionItemTasks(yesterdayEntries)
 	for _, entry := range entries {
 		// Include activities marked as planned, or if no planned activities, include all activities
 		if entry.Type == "activity" {
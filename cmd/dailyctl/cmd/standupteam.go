@@ -0,0 +1,299 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"dailylog/internal/config"
+	"dailylog/internal/datetime"
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// standupTeamCmd represents the standup team command
+var standupTeamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Generate a merged standup report for the whole team",
+	Long: `Generate one standup report for a team, grouped by person: what each did
+since --since and plans today. People are the authors of the entries in the
+log, and the teammates whose own logs are listed under team.logs:
+
+  team:
+    logs:
+      - {member: alice, repo: alice/daily-logs}
+      - {member: bob, repo: bob/worklog, path: journal}
+
+Their logs are read with the same GitHub token, so it needs access to each.
+Only entries visible to the --audience (team by default) are included.
+
+Examples:
+  dailyctl standup team
+  dailyctl standup team --since "last friday" --format slack-yaml
+  dailyctl standup team -o json`,
+	Args: cobra.NoArgs,
+	RunE: runStandupTeam,
+}
+
+func init() {
+	standupCmd.AddCommand(standupTeamCmd)
+
+	standupTeamCmd.Flags().String("since", "yesterday", "First day of work to report (YYYY-MM-DD or e.g. \"last friday\")")
+	standupTeamCmd.Flags().String("date", "", "Date for standup (YYYY-MM-DD or e.g. \"last friday\", defaults to today)")
+	standupTeamCmd.Flags().String("format", "default", "Output format: default, slack-yaml, json, yaml (default follows --output)")
+	standupTeamCmd.Flags().Bool("copy", false, "Copy output to clipboard (macOS)")
+	standupTeamCmd.Flags().String("audience", storage.VisibilityTeam, "Audience to include entries for: private, team, public")
+}
+
+// TeamStandup is the report of standup team
+type TeamStandup struct {
+	Date    string              `json:"date" yaml:"date"`
+	Since   string              `json:"since" yaml:"since"`
+	Members []TeamStandupMember `json:"members" yaml:"members"`
+}
+
+// TeamStandupMember is what one person did since the start of the report
+// and plans on its day
+type TeamStandupMember struct {
+	Author  string                  `json:"author" yaml:"author"`
+	Done    []storage.DailyLogEntry `json:"done" yaml:"done"`
+	Planned []storage.DailyLogEntry `json:"planned" yaml:"planned"`
+}
+
+func runStandupTeam(cmd *cobra.Command, args []string) error {
+	sinceStr, _ := cmd.Flags().GetString("since")
+	dateStr, _ := cmd.Flags().GetString("date")
+	format, _ := cmd.Flags().GetString("format")
+	copyToClipboard, _ := cmd.Flags().GetBool("copy")
+	audience, _ := cmd.Flags().GetString("audience")
+	now := time.Now()
+
+	if err := storage.ValidateVisibility(audience); err != nil {
+		return err
+	}
+	switch format {
+	case "default", "slack-yaml", "json", "yaml":
+	default:
+		return invalidArgf("--format must be one of default, slack-yaml, json, yaml (got %q)", format)
+	}
+	date := datetime.StartOfDay(now)
+	if dateStr != "" {
+		var err error
+		if date, err = datetime.ParseDate(dateStr, now); err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
+		}
+	}
+	since, err := datetime.ParseDate(sinceStr, now)
+	if err != nil {
+		return invalidArgf("invalid --since: %s (use YYYY-MM-DD or e.g. \"last friday\")", sinceStr)
+	}
+	if since.After(date) {
+		return invalidArgf("--since %s is after the standup on %s", since.Format("2006-01-02"), date.Format("2006-01-02"))
+	}
+	teamLogs, err := config.Team(viper.GetViper())
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	entries, err := standupEntries(storageProvider, since, date)
+	if err != nil {
+		return err
+	}
+	if len(teamLogs) > 0 && (viper.GetBool("demo") || config.Storage(viper.GetViper()).StorageType == storage.StorageTypeMemory) {
+		fmt.Fprintln(os.Stderr, "⚠ team.logs are only read from GitHub, not from memory storage")
+		teamLogs = nil
+	}
+	for _, teamLog := range teamLogs {
+		provider, err := createTeamLogProvider(teamLog)
+		if err != nil {
+			return fmt.Errorf("failed to open %s's log: %w", teamLog.Member, err)
+		}
+		memberEntries, err := standupEntries(provider, since, date)
+		if err != nil {
+			return fmt.Errorf("failed to read %s's log: %w", teamLog.Member, err)
+		}
+		entries = append(entries, storage.AttributeTo(memberEntries, teamLog.Member)...)
+	}
+
+	entries = visibilityPolicy().Filter(entries, audience)
+	report := buildTeamStandup(entries, since, date)
+
+	// --output json or yaml replace the default format
+	if format == "default" && structuredOutput() {
+		format = viper.GetString("output.format")
+	}
+	var text string
+	switch format {
+	case "slack-yaml":
+		text = generateTeamSlackYAMLReport(report)
+	case "json":
+		text = formatJSON(report) + "\n"
+	case "yaml":
+		data, err := yaml.Marshal(report)
+		if err != nil {
+			return fmt.Errorf("failed to format YAML: %w", err)
+		}
+		text = string(data)
+	default:
+		text = generateTeamDefaultReport(report)
+	}
+
+	if copyToClipboard {
+		if err := copyToClipboardMacOS(text); err != nil {
+			fmt.Fprintf(statusOut(), "Warning: Could not copy to clipboard: %v\n\n", err)
+		} else {
+			fmt.Fprintln(statusOut(), "Report copied to clipboard!")
+			fmt.Fprintln(statusOut(), "")
+		}
+	}
+	fmt.Print(text)
+	return nil
+}
+
+// createTeamLogProvider opens a teammate's log with the configured token and host
+func createTeamLogProvider(teamLog storage.TeamLog) (*providers.GitHubStorageProvider, error) {
+	path, layout := teamLog.Path, teamLog.Layout
+	if path == "" {
+		path = viper.GetString("github.path")
+	}
+	if layout == "" {
+		layout = viper.GetString("github.layout")
+	}
+	return providers.NewGitHubStorageProvider(storage.Config{
+		StorageType:     "github",
+		GitHubRepo:      teamLog.Repo,
+		GitHubToken:     viper.GetString("github.token"),
+		GitHubPath:      path,
+		GitHubBaseURL:   viper.GetString("github.base_url"),
+		GitHubUploadURL: viper.GetString("github.upload_url"),
+		DayFormat:       viper.GetString("github.day_format"),
+		Layout:          layout,
+		Commit:          commitConfig(),
+	})
+}
+
+// standupEntries reads the entries of a log from since to date
+func standupEntries(store storage.DailyLogStorage, since, date time.Time) ([]storage.DailyLogEntry, error) {
+	result, err := store.SearchLogs(storage.LogSearchRequest{DateStart: &since, DateEnd: &date})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search logs: %w", err)
+	}
+	return result.Entries, nil
+}
+
+// buildTeamStandup groups the entries by author: the work done before date,
+// and the action items of meetings since then and activities of date planned
+func buildTeamStandup(entries []storage.DailyLogEntry, since, date time.Time) TeamStandup {
+	report := TeamStandup{
+		Date:    date.Format("2006-01-02"),
+		Since:   since.Format("2006-01-02"),
+		Members: []TeamStandupMember{},
+	}
+	day := date.Format("2006-01-02")
+	for _, author := range standupAuthors(entries) {
+		var before, today []storage.DailyLogEntry
+		for _, entry := range storage.AuthorEntries(entries, author) {
+			if entry.Timestamp.Local().Format("2006-01-02") < day {
+				before = append(before, entry)
+			} else {
+				today = append(today, entry)
+			}
+		}
+		member := TeamStandupMember{
+			Author:  author,
+			Done:    append([]storage.DailyLogEntry{}, filterActivities(before)...),
+			Planned: append([]storage.DailyLogEntry{}, filterPlannedEntries(before, today)...),
+		}
+		report.Members = append(report.Members, member)
+	}
+	return report
+}
+
+// teamSinceLabel describes the start of the report, e.g. "Yesterday (Oct 17)"
+// or "Since Fri Oct 16"
+func teamSinceLabel(report TeamStandup) string {
+	since, _ := time.Parse("2006-01-02", report.Since)
+	date, _ := time.Parse("2006-01-02", report.Date)
+	if since.Equal(date.AddDate(0, 0, -1)) {
+		return fmt.Sprintf("Yesterday (%s)", since.Format("Jan 2"))
+	}
+	return "Since " + since.Format("Mon Jan 2")
+}
+
+func generateTeamDefaultReport(report TeamStandup) string {
+	var text strings.Builder
+	date, _ := time.Parse("2006-01-02", report.Date)
+
+	text.WriteString(fmt.Sprintf("Team Standup Report - %s\n", report.Date))
+	text.WriteString(strings.Repeat("=", 40))
+	text.WriteString("\n")
+	if len(report.Members) == 0 {
+		text.WriteString("\nNo activities recorded\n")
+	}
+	for _, member := range report.Members {
+		text.WriteString("\n" + authorLabel(member.Author) + "\n")
+		text.WriteString(fmt.Sprintf("  %s:\n", teamSinceLabel(report)))
+		if len(member.Done) == 0 {
+			text.WriteString("    • No activities recorded\n")
+		}
+		for _, entry := range member.Done {
+			text.WriteString(fmt.Sprintf("    • %s\n", entry.Title))
+		}
+		text.WriteString(fmt.Sprintf("  Today (%s):\n", date.Format("Jan 2")))
+		if len(member.Planned) == 0 {
+			text.WriteString("    • Planning session\n")
+		}
+		for _, entry := range member.Planned {
+			text.WriteString(fmt.Sprintf("    • %s\n", entry.Title))
+		}
+	}
+	return text.String()
+}
+
+func generateTeamSlackYAMLReport(report TeamStandup) string {
+	var text strings.Builder
+	date, _ := time.Parse("2006-01-02", report.Date)
+
+	text.WriteString(fmt.Sprintf("Team Standup Report - %s\n", report.Date))
+	text.WriteString("```yaml\n")
+	for i, member := range report.Members {
+		if i > 0 {
+			text.WriteString("\n")
+		}
+		text.WriteString(authorLabel(member.Author) + ":\n")
+		text.WriteString(fmt.Sprintf("  Y: # %s\n", teamSinceLabel(report)))
+		if len(member.Done) == 0 {
+			text.WriteString("    - No activities recorded\n")
+		}
+		for _, entry := range member.Done {
+			status := ""
+			if entry.Status > 0 {
+				status = fmt.Sprintf(" (status: %d/10)", entry.Status)
+			}
+			text.WriteString(fmt.Sprintf("    - %s%s\n", entry.Title, status))
+		}
+		text.WriteString("\n")
+		text.WriteString(fmt.Sprintf("  T: # Today (%s)\n", date.Format("Jan 2")))
+		if len(member.Planned) == 0 {
+			text.WriteString("    - Planning session\n")
+		}
+		for _, entry := range member.Planned {
+			priority := ""
+			if entry.Priority > 0 {
+				priority = fmt.Sprintf(" (priority: %d/5)", entry.Priority)
+			}
+			text.WriteString(fmt.Sprintf("    - %s%s\n", entry.Title, priority))
+		}
+	}
+	text.WriteString("```")
+	return text.String()
+}
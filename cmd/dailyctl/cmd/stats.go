@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/storage"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Break down entries over a period",
+	Long: `Roll up entries over a period by location, tag, type, project, day, or week,
+showing entry counts, tracked time, and average status for each group, then
+the focus ratio: deep work's share of the time tracked outside breaks, over
+the period and for each day and week.
+
+Locations are grouped by their canonical name from locations.aliases, so
+"HQ" and "Main St" can both count as "office":
+
+  locations:
+    aliases:
+      office: [HQ, Main St]
+
+Entries are sorted into deep work, meetings, admin, and breaks by the first
+of the focus.rules they match, by tag, type, and duration in minutes. The
+default rules count meetings, entries tagged admin or break, and activities
+of 45 minutes or more as deep work, shorter ones as admin. For example:
+
+  focus:
+    rules:
+      - {category: breaks, tags: [break, lunch, personal]}
+      - {category: meetings, types: [meeting]}
+      - {category: admin, tags: [admin, email, support]}
+      - {category: deep_work, types: [activity], min_duration: 60}
+      - {category: admin, types: [activity]}
+
+Examples:
+  dailyctl stats --by location
+  dailyctl stats --by tag --period last-month
+  dailyctl stats --by location --date-start 2025-09-01 --date-end 2025-09-30 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().String("period", "month", "Period: today, week, month, last-week, last-month")
+	statsCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD or e.g. \"3 weeks ago\"), overrides --period")
+	statsCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+	statsCmd.Flags().String("by", storage.GroupByLocation, "Group by: location, tag, type, project, day, week")
+}
+
+// StatsReport is the result of the stats command
+type StatsReport struct {
+	Period  string                      `json:"period" yaml:"period"`
+	GroupBy string                      `json:"group_by" yaml:"group_by"`
+	Totals  storage.AggregationBucket   `json:"totals" yaml:"totals"`
+	Groups  []storage.AggregationBucket `json:"groups" yaml:"groups"`
+	Focus   *storage.FocusReport        `json:"focus,omitempty" yaml:"focus,omitempty"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	period, _ := cmd.Flags().GetString("period")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	groupBy, _ := cmd.Flags().GetString("by")
+
+	if err := storage.ValidateAggregations([]string{groupBy}); err != nil {
+		return err
+	}
+
+	start, end, err := resolveTimePeriod(period, dateStartStr, dateEndStr, time.Now())
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{
+		DateStart:    &start,
+		DateEnd:      &end,
+		Aggregations: []string{groupBy},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	report := StatsReport{
+		Period:  fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+		GroupBy: groupBy,
+	}
+	if result.Totals != nil {
+		report.Totals = *result.Totals
+	}
+	if len(result.Aggregations) > 0 {
+		report.Groups = result.Aggregations[0].Buckets
+	}
+	report.Focus = config.Focus(viper.GetViper()).Score(result.Entries,
+		storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases")))
+
+	if ok, err := outputStructured(report); ok {
+		return err
+	}
+
+	outputStatsTable(report)
+	return nil
+}
+
+func outputStatsTable(report StatsReport) {
+	title := fmt.Sprintf("Statistics by %s - %s", report.GroupBy, report.Period)
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+	fmt.Println()
+
+	if report.Totals.Count == 0 {
+		fmt.Println("No entries found.")
+		return
+	}
+
+	// Group names wrap within their column rather than pushing the figures out of line
+	keyWidth := 24
+	if width := terminalWidth(); width > 0 {
+		keyWidth = max(min(keyWidth, width-34), 10)
+	}
+
+	fmt.Println(style(styleBold, fmt.Sprintf("%-*s %7s %6s %10s %7s", keyWidth, strings.ToUpper(report.GroupBy), "ENTRIES", "SHARE", "TIME", "STATUS")))
+	fmt.Println(rule("-", keyWidth+34))
+	for _, group := range report.Groups {
+		share := float64(group.Count) / float64(report.Totals.Count) * 100
+		status := fmt.Sprintf("%7s", "-")
+		if group.AverageStatus > 0 {
+			status = style(statusStyle(int(group.AverageStatus+0.5)), fmt.Sprintf("%7.1f", group.AverageStatus))
+		}
+		keyLines := wrapText(group.Key, keyWidth)
+		fmt.Printf("%-*s %7d %5.0f%% %10s %s\n", keyWidth, keyLines[0], group.Count, share, formatMinutes(group.TotalDuration), status)
+		for _, line := range keyLines[1:] {
+			fmt.Println(line)
+		}
+	}
+	fmt.Println(rule("-", keyWidth+34))
+	fmt.Printf("%-*s %7d %6s %10s\n", keyWidth, "Total", report.Totals.Count, "", formatMinutes(report.Totals.TotalDuration))
+
+	if len(report.Totals.Metrics) > 0 {
+		fmt.Println()
+		fmt.Println(style(styleBold, fmt.Sprintf("%-*s %7s %s", keyWidth, "METRIC", "DAYS", "DAILY AVERAGE (RANGE)")))
+		for _, metric := range report.Totals.Metrics {
+			fmt.Printf("%-*s %7d %s\n", keyWidth, metric.Name, metric.Days, metric.String())
+		}
+	}
+
+	if report.Focus != nil {
+		fmt.Println()
+		fmt.Println(report.Focus.String())
+		scores := report.Focus.Days
+		if len(scores) > maxFocusDays {
+			scores = report.Focus.Weeks
+		}
+		fmt.Println(style(styleBold, fmt.Sprintf("%-*s %7s %10s %10s", keyWidth, "FOCUS", "RATIO", "DEEP WORK", "WORK")))
+		for _, score := range scores {
+			ratio := fmt.Sprintf("%7s", "-")
+			if score.WorkMinutes() > 0 {
+				ratio = style(focusStyle(score.Ratio), fmt.Sprintf("%6.0f%%", score.Ratio*100))
+			}
+			fmt.Printf("%-*s %s %10s %10s\n", keyWidth, score.Period, ratio,
+				formatMinutes(score.Minutes[storage.FocusDeepWork]), formatMinutes(score.WorkMinutes()))
+		}
+	}
+}
+
+// maxFocusDays is the most days the focus ratio is listed for; longer
+// periods list it by week
+const maxFocusDays = 14
+
+// focusStyle colors a focus ratio from red, mostly interrupted, to green
+func focusStyle(ratio float64) string {
+	switch {
+	case ratio >= 0.5:
+		return styleGreen
+	case ratio >= 0.25:
+		return styleYellow
+	default:
+		return styleRed
+	}
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/importers"
+)
+
+// statusLineStreakDays is how far back the streak is counted
+const statusLineStreakDays = 366
+
+// statusLineRefreshTimeout is how long a background refresh may take before
+// another is started
+const statusLineRefreshTimeout = time.Minute
+
+// statusLineCmd represents the status-line command
+var statusLineCmd = &cobra.Command{
+	Use:   "status-line",
+	Short: "Print a one-line summary for a tmux status bar or shell prompt",
+	Long: `Print a compact summary, such as "3 today · 🔥 5d · ⏱ 1h12m Code review":
+the entries logged today, the current streak of days with entries, and the
+running Toggl or Clockify timer, if status_line.timer is set.
+
+The summary is read from a cache in ~/.dailyctl/cache, so printing it is fast
+and never waits for GitHub. Once the cache is older than --max-age, it is
+refreshed in the background and the next prompt shows the new figures; the
+timer's elapsed time is always current. --refresh updates the cache first.
+
+output.templates.status-line replaces the layout, with .Today, .Streak,
+.Timer, .TimerElapsed, and .UpdatedAt.
+
+Examples:
+  dailyctl status-line
+  dailyctl status-line --timer toggl
+  # tmux
+  set -g status-right '#(dailyctl status-line)'
+  set -g status-interval 30
+  # starship, as a custom module
+  [custom.dailylog]
+  command = "dailyctl status-line"
+  when = true`,
+	Args: cobra.NoArgs,
+	RunE: runStatusLine,
+}
+
+func init() {
+	rootCmd.AddCommand(statusLineCmd)
+
+	statusLineCmd.Flags().Duration("max-age", 5*time.Minute, "Refresh the cache in the background once it is older than this")
+	statusLineCmd.Flags().Bool("refresh", false, "Refresh the cache before printing")
+	statusLineCmd.Flags().String("timer", "", "Show the running timer of a time tracker: toggl or clockify")
+
+	_ = viper.BindPFlag("status_line.max_age", statusLineCmd.Flags().Lookup("max-age"))
+	_ = viper.BindPFlag("status_line.timer", statusLineCmd.Flags().Lookup("timer"))
+}
+
+// StatusLine is the summary the status-line command prints
+type StatusLine struct {
+	Today        int       `json:"today" yaml:"today"`
+	Streak       int       `json:"streak" yaml:"streak"`
+	Timer        string    `json:"timer,omitempty" yaml:"timer,omitempty"`
+	TimerElapsed string    `json:"timer_elapsed,omitempty" yaml:"timer_elapsed,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// statusLineCache holds the figures behind the status line as last fetched
+type statusLineCache struct {
+	FetchedAt time.Time               `json:"fetched_at"`
+	Date      string                  `json:"date"`
+	Today     int                     `json:"today"`
+	LastDay   string                  `json:"last_day,omitempty"`
+	Streak    int                     `json:"streak"`
+	Timer     *importers.RunningTimer `json:"timer,omitempty"`
+}
+
+func runStatusLine(cmd *cobra.Command, args []string) error {
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	switch timer := viper.GetString("status_line.timer"); timer {
+	case "", "toggl", "clockify":
+	default:
+		return invalidArgf("invalid timer: %s (use toggl or clockify)", timer)
+	}
+
+	cachePath, err := statusLineCachePath()
+	if err != nil {
+		return err
+	}
+	// Demo data isn't cached, so it never shows in place of the real figures
+	cache, cached := readStatusLineCache(cachePath)
+	if viper.GetBool("demo") {
+		cache, cached = statusLineCache{}, false
+	}
+	now := time.Now()
+
+	switch {
+	case refresh || !cached:
+		// The first run waits, so there is something to show
+		if cache, err = refreshStatusLine(cachePath, now); err != nil {
+			return err
+		}
+	case now.Sub(cache.FetchedAt) > viper.GetDuration("status_line.max_age"):
+		startStatusLineRefresh(cachePath)
+	}
+
+	line := cache.statusLine(now)
+	if ok, err := outputStructured(line); ok {
+		return err
+	}
+	if ok, err := printOutputTemplate("status-line", line); ok {
+		return err
+	}
+	fmt.Println(line.String())
+	return nil
+}
+
+// String lays the status line out as "3 today · 🔥 5d · ⏱ 1h12m Code review"
+func (line StatusLine) String() string {
+	parts := []string{fmt.Sprintf("%d today", line.Today)}
+	if line.Streak > 0 {
+		parts = append(parts, fmt.Sprintf("🔥 %dd", line.Streak))
+	}
+	if line.TimerElapsed != "" {
+		parts = append(parts, strings.TrimSpace("⏱ "+line.TimerElapsed+" "+truncateText(24, line.Timer)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// statusLine is the status line at now from the cached figures: today's
+// count only if fetched today, and the streak only while it's unbroken
+func (cache statusLineCache) statusLine(now time.Time) StatusLine {
+	today := now.Format("2006-01-02")
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+
+	line := StatusLine{UpdatedAt: cache.FetchedAt}
+	if cache.Date == today {
+		line.Today = cache.Today
+	}
+	if cache.LastDay == today || cache.LastDay == yesterday {
+		line.Streak = cache.Streak
+	}
+	if cache.Timer != nil {
+		line.Timer = cache.Timer.Description
+		if line.Timer == "" {
+			line.Timer = cache.Timer.Project
+		}
+		line.TimerElapsed = formatElapsed(now.Sub(cache.Timer.Start))
+	}
+	return line
+}
+
+// formatElapsed renders a running timer's time as 45m or 1h12m
+func formatElapsed(elapsed time.Duration) string {
+	minutes := int(max(elapsed, 0) / time.Minute)
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// refreshStatusLine fetches the figures behind the status line and caches them
+func refreshStatusLine(cachePath string, now time.Time) (statusLineCache, error) {
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return statusLineCache{}, fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	dayLog, err := storageProvider.GetDay(now)
+	if err != nil {
+		return statusLineCache{}, fmt.Errorf("failed to get today: %w", err)
+	}
+	start := now.AddDate(0, 0, -statusLineStreakDays)
+	days, err := storageProvider.ListDays(start, now)
+	if err != nil {
+		return statusLineCache{}, fmt.Errorf("failed to list days: %w", err)
+	}
+
+	cache := statusLineCache{
+		FetchedAt: now,
+		Date:      now.Format("2006-01-02"),
+		Today:     len(dayLog.Entries),
+	}
+	cache.LastDay, cache.Streak = currentStreak(days, len(dayLog.Entries) > 0, now)
+
+	// A failing timer shouldn't hide the rest of the status line
+	if cache.Timer, err = runningTimer(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
+	}
+
+	if viper.GetBool("demo") {
+		return cache, nil
+	}
+	if data, err := json.Marshal(cache); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o600)
+		}
+	}
+	// A failed refresh keeps the lock, so it's retried only once it expires
+	os.Remove(cachePath + ".lock")
+	return cache, nil
+}
+
+// currentStreak counts the consecutive days with logs up to today, or up to
+// yesterday while nothing is logged today, returning the last of them
+func currentStreak(days []time.Time, loggedToday bool, now time.Time) (string, int) {
+	logged := make(map[string]bool, len(days))
+	for _, day := range days {
+		logged[day.Format("2006-01-02")] = true
+	}
+	today := now.Format("2006-01-02")
+	logged[today] = loggedToday
+
+	day := now
+	if !loggedToday {
+		day = now.AddDate(0, 0, -1)
+	}
+	last := day.Format("2006-01-02")
+	streak := 0
+	for logged[day.Format("2006-01-02")] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	if streak == 0 {
+		return "", 0
+	}
+	return last, streak
+}
+
+// runningTimer returns the timer running in the configured time tracker, if any
+func runningTimer() (*importers.RunningTimer, error) {
+	switch viper.GetString("status_line.timer") {
+	case "toggl":
+		importer, err := importers.NewTogglImporter(viper.GetString("toggl.token"))
+		if err != nil {
+			return nil, fmt.Errorf("%w (set DAILYLOG_TOGGL_TOKEN)", err)
+		}
+		return importer.Running()
+	case "clockify":
+		importer, err := importers.NewClockifyImporter(viper.GetString("clockify.token"), viper.GetString("clockify.workspace"))
+		if err != nil {
+			return nil, fmt.Errorf("%w (set DAILYLOG_CLOCKIFY_TOKEN)", err)
+		}
+		return importer.Running()
+	default:
+		return nil, nil
+	}
+}
+
+// startStatusLineRefresh refreshes the cache in a background dailyctl, unless
+// one was started recently, so the prompt isn't held up
+func startStatusLineRefresh(cachePath string) {
+	lockPath := cachePath + ".lock"
+	if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) < statusLineRefreshTimeout {
+		return
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o700); err != nil {
+		return
+	}
+	if err := os.WriteFile(lockPath, nil, 0o600); err != nil {
+		return
+	}
+
+	// Run as invoked, so flags such as --config carry over
+	refresh := exec.Command(executable, append(os.Args[1:], "--refresh")...)
+	if err := refresh.Start(); err != nil {
+		os.Remove(lockPath)
+		return
+	}
+	_ = refresh.Process.Release()
+}
+
+// statusLineCachePath is where the status line's figures are cached
+func statusLineCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find home directory: %w", err)
+	}
+	return filepath.Join(home, ".dailyctl", "cache", "status-line.json"), nil
+}
+
+// readStatusLineCache reads the cached figures, reporting false if there are none
+func readStatusLineCache(cachePath string) (statusLineCache, bool) {
+	var cache statusLineCache
+	data, err := os.ReadFile(cachePath)
+	if err != nil || json.Unmarshal(data, &cache) != nil {
+		return statusLineCache{}, false
+	}
+	return cache, true
+}
@@ -2,13 +2,13 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
+	"dailylog/internal/datetime"
 	"dailylog/internal/storage"
 )
 
@@ -61,12 +61,16 @@ func init() {
 
 	// Add flags
 	addSummaryFlags := func(cmd *cobra.Command) {
-		cmd.Flags().String("date", "", "Date for summary (YYYY-MM-DD, defaults to today)")
-		cmd.Flags().String("date-start", "", "Start date for custom range (YYYY-MM-DD)")
-		cmd.Flags().String("date-end", "", "End date for custom range (YYYY-MM-DD)")
+		cmd.Flags().String("date", "", "Date for summary (YYYY-MM-DD or e.g. \"last friday\", defaults to today)")
+		cmd.Flags().String("date-start", "", "Start date for custom range (YYYY-MM-DD or e.g. \"start of last month\")")
+		cmd.Flags().String("date-end", "", "End date for custom range (YYYY-MM-DD or e.g. \"end of last month\")")
 		cmd.Flags().Bool("ai", false, "Use AI for enhanced summary generation")
 		cmd.Flags().String("prompt", "", "Custom prompt for AI summary")
 		cmd.Flags().Bool("save", false, "Save summary to the log data")
+		cmd.Flags().Bool("dry-run", false, "With --save, show what would be saved without saving it")
+		cmd.Flags().String("audience", storage.VisibilityPrivate, "Only summarize entries visible to: private, team, public")
+		cmd.Flags().StringSlice("tags", []string{}, "Only summarize entries with these tags (work includes work/projectx)")
+		_ = cmd.RegisterFlagCompletionFunc("tags", completeTags)
 	}
 
 	addSummaryFlags(summarizeDayCmd)
@@ -84,31 +88,42 @@ func runSummarize(summaryType string) func(cmd *cobra.Command, args []string) er
 		useAI, _ := cmd.Flags().GetBool("ai")
 		prompt, _ := cmd.Flags().GetString("prompt")
 		save, _ := cmd.Flags().GetBool("save")
+		audience, _ := cmd.Flags().GetString("audience")
+		tags, _ := cmd.Flags().GetStringSlice("tags")
+
+		if err := storage.ValidateVisibility(audience); err != nil {
+			return err
+		}
+		if save && len(tags) > 0 {
+			return fmt.Errorf("--save cannot be combined with --tags, which summarizes only part of the period")
+		}
 
 		// Parse target date
 		var targetDate time.Time
 		var err error
 		if dateStr != "" {
-			targetDate, err = time.Parse("2006-01-02", dateStr)
+			targetDate, err = datetime.ParseDate(dateStr, time.Now())
 			if err != nil {
-				return fmt.Errorf("invalid date format: %s (use YYYY-MM-DD)", dateStr)
+				return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"last friday\")", dateStr)
 			}
 		} else {
 			targetDate = time.Now()
 		}
 
 		// Create storage provider
-		storageProvider, err := createStorageProvider()
+		storageProvider, preview, err := createWriteProvider(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to create storage provider: %v", err)
+			return fmt.Errorf("failed to create storage provider: %w", err)
 		}
 
 		// Build summary request
 		summaryReq := storage.SummaryRequest{
-			Type:   summaryType,
-			Date:   targetDate,
-			UseAI:  useAI,
-			Prompt: prompt,
+			Type:     summaryType,
+			Date:     targetDate,
+			UseAI:    useAI,
+			Prompt:   prompt,
+			Audience: audience,
+			Tags:     tags,
 		}
 
 		// Handle custom date range
@@ -117,10 +132,10 @@ func runSummarize(summaryType string) func(cmd *cobra.Command, args []string) er
 				return fmt.Errorf("custom summary requires both --date-start and --date-end")
 			}
 
-			startDate, err1 := time.Parse("2006-01-02", dateStartStr)
-			endDate, err2 := time.Parse("2006-01-02", dateEndStr)
+			startDate, err1 := datetime.ParseDate(dateStartStr, time.Now())
+			endDate, err2 := datetime.ParseDate(dateEndStr, time.Now())
 			if err1 != nil || err2 != nil {
-				return fmt.Errorf("invalid date format in range")
+				return invalidArgf("invalid date format in range (use YYYY-MM-DD or e.g. \"start of last month\")")
 			}
 
 			if startDate.After(endDate) {
@@ -134,39 +149,66 @@ func runSummarize(summaryType string) func(cmd *cobra.Command, args []string) er
 		// Generate summary
 		summaryResult, err := storageProvider.GenerateSummary(summaryReq)
 		if err != nil {
-			return fmt.Errorf("failed to generate summary: %v", err)
+			return fmt.Errorf("failed to generate summary: %w", err)
 		}
 
 		// Save summary if requested
 		if save {
 			err = storageProvider.SaveSummary(summaryResult, summaryType, targetDate)
 			if err != nil {
-				fmt.Printf("Warning: Failed to save summary: %v\n", err)
+				fmt.Fprintf(statusOut(), "Warning: Failed to save summary: %v\n", err)
+			} else if preview != nil {
+				fmt.Fprintln(statusOut(), "Would save summary to log data")
 			} else {
-				fmt.Println("✓ Summary saved to log data")
+				fmt.Fprintln(statusOut(), "✓ Summary saved to log data")
 			}
 		}
 
 		// Output summary
-		return outputSummary(summaryResult)
+		if err := outputSummary(summaryResult); err != nil {
+			return err
+		}
+		if save {
+			reportDryRun(preview)
+		}
+		return nil
 	}
 }
 
+// riskStyles colors the burnout risk levels
+var riskStyles = map[string]string{
+	storage.RiskLow:      styleGreen,
+	storage.RiskModerate: styleYellow,
+	storage.RiskHigh:     styleRed,
+}
+
 func outputSummary(summary *storage.SummaryResponse) error {
+	if ok, err := outputStructured(summary); ok {
+		return err
+	}
+
 	c := cases.Title(language.English)
-	fmt.Printf("📊 %s Summary - %s\n",
-		c.String(summary.Type), summary.Period)
-	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println(style(styleBold, fmt.Sprintf("📊 %s Summary - %s", c.String(summary.Type), summary.Period)))
+	fmt.Println(rule("=", 50))
 	fmt.Println()
 
+	// Pinned highlights come first
+	if len(summary.Pinned) > 0 {
+		fmt.Println(style(styleBold, "📌 Pinned:"))
+		for _, entry := range summary.Pinned {
+			fmt.Printf("  %s  %s\n", style(styleDim, entry.Timestamp.Format("Mon 01-02")), entry.Title)
+		}
+		fmt.Println()
+	}
+
 	// Main summary content
-	fmt.Println(summary.Summary)
+	printWrapped(summary.Summary, 0, "")
 	fmt.Println()
 
 	// Statistics
 	if len(summary.Stats) > 0 {
-		fmt.Println("📈 Statistics:")
-		fmt.Println(strings.Repeat("-", 20))
+		fmt.Println(style(styleBold, "📈 Statistics:"))
+		fmt.Println(rule("-", 20))
 
 		if totalEntries, ok := summary.Stats["total_entries"].(int); ok {
 			fmt.Printf("  Total entries: %d\n", totalEntries)
@@ -175,11 +217,26 @@ func outputSummary(summary *storage.SummaryResponse) error {
 			fmt.Printf("  Total days: %d\n", totalDays)
 		}
 		if avgStatus, ok := summary.Stats["average_status"].(float64); ok && avgStatus > 0 {
-			fmt.Printf("  Average status: %.1f/10\n", avgStatus)
+			fmt.Printf("  Average status: %s\n", style(statusStyle(int(avgStatus+0.5)), fmt.Sprintf("%.1f/10", avgStatus)))
 		}
 		if entriesPerDay, ok := summary.Stats["entries_per_day"].(float64); ok {
 			fmt.Printf("  Entries per day: %.1f\n", entriesPerDay)
 		}
+		if focus, ok := summary.Stats["focus"].(*storage.FocusReport); ok {
+			fmt.Printf("  Focus ratio: %s of %s outside breaks\n", style(focusStyle(focus.Ratio), fmt.Sprintf("%.0f%%", focus.Ratio*100)), formatMinutes(focus.WorkMinutes()))
+		}
+		if check, ok := summary.Stats["wellbeing"].(storage.WellbeingCheck); ok {
+			fmt.Printf("  Burnout risk: %s\n", style(riskStyles[check.Risk], check.Risk))
+			for _, signal := range check.Signals {
+				if signal.Triggered {
+					fmt.Printf("    ⚠ %s\n", signal.Message)
+				}
+			}
+		}
+		if hours, ok := summary.Stats["work_hours"].(storage.WorkHoursReport); ok && hours.TotalMinutes > 0 {
+			fmt.Printf("  Work hours: %s\n", formatMinutes(hours.TotalMinutes))
+			printWorkHoursFlags(hours)
+		}
 
 		fmt.Println()
 	}
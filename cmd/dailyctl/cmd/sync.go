@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Replay writes queued while offline",
+	Long: `When GitHub cannot be reached, new entries and deletions are appended to a
+local queue instead of failing. The queue is replayed automatically before the
+next successful write; sync replays it on demand.
+
+Replayed writes are merged with any changes made elsewhere in the meantime.
+
+Examples:
+  dailyctl sync
+  dailyctl sync --status`,
+	RunE: runSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	syncCmd.Flags().Bool("status", false, "List queued operations without syncing")
+
+	viper.SetDefault("offline.enabled", true)
+}
+
+// SyncResult is the outcome of a sync run
+type SyncResult struct {
+	Synced  int                         `json:"synced" yaml:"synced"`
+	Pending []providers.QueuedOperation `json:"pending" yaml:"pending"`
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	status, _ := cmd.Flags().GetBool("status")
+
+	storageProvider, err := createBackendProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	queue, ok := storageProvider.(*providers.OfflineQueueProvider)
+	if !ok {
+		return fmt.Errorf("offline queue is disabled (set offline.enabled to true)")
+	}
+
+	result := SyncResult{}
+	var syncErr error
+	if !status {
+		result.Synced, syncErr = queue.Sync()
+	}
+
+	result.Pending, err = queue.Pending()
+	if err != nil {
+		return err
+	}
+
+	if err := outputSyncResult(&result, status); err != nil {
+		return err
+	}
+	if syncErr != nil {
+		return fmt.Errorf("sync stopped with %d operations still queued: %w", len(result.Pending), syncErr)
+	}
+	return nil
+}
+
+func outputSyncResult(result *SyncResult, status bool) error {
+	if ok, err := outputStructured(result); ok {
+		return err
+	}
+
+	if !status {
+		fmt.Printf("✓ Synced %d queued operations\n", result.Synced)
+	}
+	if len(result.Pending) == 0 {
+		fmt.Println("Offline queue is empty")
+		return nil
+	}
+	fmt.Printf("%d operations queued:\n", len(result.Pending))
+	for _, op := range result.Pending {
+		fmt.Printf("  %s  %-6s  %s\n", op.QueuedAt.Format("2006-01-02 15:04"), op.Op, describeQueuedOperation(op))
+	}
+	return nil
+}
+
+func describeQueuedOperation(op providers.QueuedOperation) string {
+	if op.Entry != nil {
+		return fmt.Sprintf("%s (%s)", op.Entry.Title, op.Date.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%s (%s)", op.EntryID, op.Date.Format("2006-01-02"))
+}
+
+// newOfflineQueue wraps a backend so writes are queued locally while it is unreachable
+func newOfflineQueue(backend storage.DailyLogStorage) (*providers.OfflineQueueProvider, error) {
+	queuePath := viper.GetString("offline.queue_path")
+	if queuePath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		queuePath = filepath.Join(home, ".dailyctl", "queue.jsonl")
+	}
+
+	queue, err := providers.NewOfflineQueueProvider(backend, queuePath)
+	if err != nil {
+		return nil, err
+	}
+	queue.OnQueued = func(op providers.QueuedOperation, cause error) {
+		fmt.Fprintf(os.Stderr, "⚠ GitHub unreachable, queued %s for later sync (%v)\n", op.Op, cause)
+	}
+	return queue, nil
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+
+	"dailylog/internal/storage"
+)
+
+// templateEntry is an entry as seen by output templates, with its short ID
+type templateEntry struct {
+	storage.DailyLogEntry
+	ShortID string
+}
+
+// templateFuncs are the functions available to output templates
+var templateFuncs = template.FuncMap{
+	"pad":    padText,
+	"trunc":  truncateText,
+	"join":   strings.Join,
+	"repeat": strings.Repeat,
+	"upper":  strings.ToUpper,
+	"lower":  strings.ToLower,
+	"time":   func(layout string, t time.Time) string { return t.Format(layout) },
+}
+
+// renderOutputTemplate renders the table output of command with the template
+// configured under output.templates.<command>. It reports false when none is
+// configured, so the built-in layout should be used.
+func renderOutputTemplate(command string, data any) (string, bool, error) {
+	key := "output.templates." + command
+	source := viper.GetString(key)
+	if source == "" {
+		return "", false, nil
+	}
+
+	tmpl, err := template.New(command).Funcs(templateFuncs).Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return "", true, storage.ValidationError{Field: key, Message: err.Error()}
+	}
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", true, storage.ValidationError{Field: key, Message: err.Error()}
+	}
+	return rendered.String(), true, nil
+}
+
+// printOutputTemplate prints the configured template's rendering of data,
+// reporting false when none is configured
+func printOutputTemplate(command string, data any) (bool, error) {
+	rendered, ok, err := renderOutputTemplate(command, data)
+	if !ok || err != nil {
+		return ok, err
+	}
+	fmt.Print(rendered)
+	if !strings.HasSuffix(rendered, "\n") {
+		fmt.Println()
+	}
+	return true, nil
+}
+
+// templateEntries pairs entries with their short IDs
+func templateEntries(entries []storage.DailyLogEntry) []templateEntry {
+	ids := shortIDs(entries)
+	views := make([]templateEntry, len(entries))
+	for i, entry := range entries {
+		views[i] = templateEntry{DailyLogEntry: entry, ShortID: ids[entry.ID]}
+	}
+	return views
+}
+
+// truncateText shortens text to width characters, ending in "..." when cut
+func truncateText(width int, text string) string {
+	if width <= 0 || utf8.RuneCountInString(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+// padText truncates or pads text to exactly width characters, for columns
+func padText(width int, text string) string {
+	if width <= 0 {
+		return text
+	}
+	text = truncateText(width, text)
+	return text + strings.Repeat(" ", width-utf8.RuneCountInString(text))
+}
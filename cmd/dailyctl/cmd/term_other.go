@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package cmd
+
+// ttyWidth can't query the terminal on this platform; COLUMNS is used instead
+func ttyWidth() int {
+	return 0
+}
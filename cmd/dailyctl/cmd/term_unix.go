@@ -0,0 +1,20 @@
+//go:build linux || darwin
+
+package cmd
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// ttyWidth returns the width of the terminal on stdout, or 0 if it isn't one
+func ttyWidth() int {
+	var size struct {
+		rows, cols, xpixel, ypixel uint16
+	}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, terminal.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)))
+	if errno != 0 {
+		return 0
+	}
+	return int(size.cols)
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// timeCmd represents the time command
+var timeCmd = &cobra.Command{
+	Use:   "time",
+	Short: "Report tracked time",
+	Long: `Sum entry durations over a period, grouped by tag, type, or project
+(the "project" metadata key), and flag weekdays with less tracked time than
+--min-daily (time.min_daily in the config file).
+
+Examples:
+  dailyctl time
+  dailyctl time --period last-week --by type
+  dailyctl time --period month --by project --min-daily 6h
+  dailyctl time --date-start 2025-09-01 --date-end 2025-09-30 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runTime,
+}
+
+func init() {
+	rootCmd.AddCommand(timeCmd)
+
+	timeCmd.Flags().String("period", "week", "Period: today, week, month, last-week, last-month")
+	timeCmd.Flags().String("date-start", "", "Start date (YYYY-MM-DD or e.g. \"3 weeks ago\"), overrides --period")
+	timeCmd.Flags().String("date-end", "", "End date (YYYY-MM-DD or e.g. \"end of last month\"), default today")
+	timeCmd.Flags().String("by", storage.GroupByTag, "Group by: tag, type, project")
+	timeCmd.Flags().Duration("min-daily", 0, "Flag weekdays with less tracked time than this (e.g. 6h)")
+
+	_ = viper.BindPFlag("time.min_daily", timeCmd.Flags().Lookup("min-daily"))
+}
+
+// TimeReport is the result of the time command
+type TimeReport struct {
+	Period       string                      `json:"period" yaml:"period"`
+	GroupBy      string                      `json:"group_by" yaml:"group_by"`
+	TotalMinutes int                         `json:"total_minutes" yaml:"total_minutes"`
+	Groups       []storage.AggregationBucket `json:"groups" yaml:"groups"`
+	Days         []TimeReportDay             `json:"days" yaml:"days"`
+	MinDaily     int                         `json:"min_daily_minutes,omitempty" yaml:"min_daily_minutes,omitempty"`
+}
+
+// TimeReportDay is the tracked time of one day
+type TimeReportDay struct {
+	Date    string `json:"date" yaml:"date"`
+	Minutes int    `json:"minutes" yaml:"minutes"`
+	Short   bool   `json:"below_threshold,omitempty" yaml:"below_threshold,omitempty"`
+}
+
+func runTime(cmd *cobra.Command, args []string) error {
+	period, _ := cmd.Flags().GetString("period")
+	dateStartStr, _ := cmd.Flags().GetString("date-start")
+	dateEndStr, _ := cmd.Flags().GetString("date-end")
+	groupBy, _ := cmd.Flags().GetString("by")
+	minDaily := viper.GetDuration("time.min_daily")
+
+	switch groupBy {
+	case storage.GroupByTag, storage.GroupByType, storage.GroupByProject:
+	default:
+		return invalidArgf("--by must be one of tag, type, project (got %q)", groupBy)
+	}
+
+	start, end, err := resolveTimePeriod(period, dateStartStr, dateEndStr, time.Now())
+	if err != nil {
+		return err
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{
+		DateStart:    &start,
+		DateEnd:      &end,
+		Aggregations: []string{groupBy, storage.GroupByDay},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search logs: %w", err)
+	}
+
+	report := buildTimeReport(result, groupBy, start, end, minDaily)
+
+	if ok, err := outputStructured(report); ok {
+		return err
+	}
+
+	outputTimeTable(report)
+	return nil
+}
+
+func buildTimeReport(result *storage.LogSearchResponse, groupBy string, start, end time.Time, minDaily time.Duration) *TimeReport {
+	report := &TimeReport{
+		Period:   fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+		GroupBy:  groupBy,
+		MinDaily: int(minDaily.Minutes()),
+	}
+	if result.Totals != nil {
+		report.TotalMinutes = result.Totals.TotalDuration
+	}
+
+	dayMinutes := make(map[string]int)
+	for _, aggregation := range result.Aggregations {
+		switch aggregation.GroupBy {
+		case groupBy:
+			for _, bucket := range aggregation.Buckets {
+				if bucket.TotalDuration > 0 {
+					report.Groups = append(report.Groups, bucket)
+				}
+			}
+		case storage.GroupByDay:
+			for _, bucket := range aggregation.Buckets {
+				dayMinutes[bucket.Key] = bucket.TotalDuration
+			}
+		}
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		return report.Groups[i].TotalDuration > report.Groups[j].TotalDuration
+	})
+
+	// Only flag days that are over; today is still in progress
+	today := time.Now().Format("2006-01-02")
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		day := TimeReportDay{Date: key, Minutes: dayMinutes[key]}
+		isWeekday := d.Weekday() != time.Saturday && d.Weekday() != time.Sunday
+		if report.MinDaily > 0 && isWeekday && key < today && day.Minutes < report.MinDaily {
+			day.Short = true
+		}
+		report.Days = append(report.Days, day)
+	}
+
+	return report
+}
+
+func outputTimeTable(report *TimeReport) {
+	fmt.Printf("Tracked Time - %s\n", report.Period)
+	fmt.Println(strings.Repeat("=", 15+len(report.Period)))
+	fmt.Println()
+
+	if report.TotalMinutes == 0 {
+		fmt.Println("No time tracked in this period.")
+	} else {
+		fmt.Printf("%-24s %10s %7s %7s\n", strings.ToUpper(report.GroupBy), "TIME", "SHARE", "ENTRIES")
+		fmt.Println(strings.Repeat("-", 51))
+		for _, group := range report.Groups {
+			share := float64(group.TotalDuration) / float64(report.TotalMinutes) * 100
+			fmt.Printf("%-24s %10s %6.0f%% %7d\n", group.Key, formatMinutes(group.TotalDuration), share, group.Count)
+		}
+		fmt.Println(strings.Repeat("-", 51))
+		fmt.Printf("%-24s %10s\n", "Total", formatMinutes(report.TotalMinutes))
+	}
+
+	fmt.Println()
+	fmt.Println("By day:")
+	for _, day := range report.Days {
+		flag := ""
+		if day.Short {
+			flag = fmt.Sprintf("  ⚠ below %s", formatMinutes(report.MinDaily))
+		}
+		date, _ := time.Parse("2006-01-02", day.Date)
+		fmt.Printf("  %s %s %8s%s\n", day.Date, date.Format("Mon"), formatMinutes(day.Minutes), flag)
+	}
+}
+
+// formatMinutes renders a duration in minutes as e.g. "7h 30m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// resolveTimePeriod turns a named period or explicit dates into an inclusive date range
+func resolveTimePeriod(period, dateStartStr, dateEndStr string, now time.Time) (time.Time, time.Time, error) {
+	today := datetime.StartOfDay(now)
+
+	end := today
+	if dateEndStr != "" {
+		parsed, err := datetime.ParseDate(dateEndStr, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end date: %s (use YYYY-MM-DD or e.g. \"end of last month\")", dateEndStr)
+		}
+		end = parsed
+	}
+
+	if dateStartStr != "" {
+		start, err := datetime.ParseDate(dateStartStr, now)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start date: %s (use YYYY-MM-DD or e.g. \"3 weeks ago\")", dateStartStr)
+		}
+		return start, end, nil
+	}
+
+	weekStart := datetime.StartOfWeek(today)
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+
+	switch period {
+	case "today":
+		return today, today, nil
+	case "week":
+		return weekStart, today, nil
+	case "month":
+		return monthStart, today, nil
+	case "last-week":
+		return weekStart.AddDate(0, 0, -7), weekStart.AddDate(0, 0, -1), nil
+	case "last-month":
+		return monthStart.AddDate(0, -1, 0), monthStart.AddDate(0, 0, -1), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid period: %s (use today, week, month, last-week, last-month)", period)
+	}
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/storage"
+)
+
+// todayCmd represents the today command
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Show today at a glance",
+	Long: `Show today's entries and tracked time, the open tasks carried over from
+the last week, a checklist of the habits listed under today.habits in the
+config file, and a sparkline of the daily mood (average status) over the
+last --days days, on one screen.
+
+A habit is done when one of today's entries has it as a tag, e.g.
+
+  today:
+    habits: [exercise, reading, meditation]
+
+Examples:
+  dailyctl today
+  dailyctl today --days 30
+  dailyctl today -o json`,
+	Args: cobra.NoArgs,
+	RunE: runToday,
+}
+
+func init() {
+	rootCmd.AddCommand(todayCmd)
+
+	todayCmd.Flags().Int("days", 14, "Days of mood to show in the sparkline")
+}
+
+// TodayView is the result of the today command
+type TodayView struct {
+	Date           string                  `json:"date" yaml:"date"`
+	Entries        []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+	TrackedMinutes int                     `json:"tracked_minutes" yaml:"tracked_minutes"`
+	StatusAverage  float64                 `json:"status_average,omitempty" yaml:"status_average,omitempty"`
+	OpenTasks      []storage.DailyLogEntry `json:"open_tasks" yaml:"open_tasks"`
+	Habits         []HabitCheck            `json:"habits,omitempty" yaml:"habits,omitempty"`
+	Mood           []MoodDay               `json:"mood" yaml:"mood"`
+}
+
+// HabitCheck is whether a habit was done today
+type HabitCheck struct {
+	Habit string `json:"habit" yaml:"habit"`
+	Done  bool   `json:"done" yaml:"done"`
+}
+
+// MoodDay is the average status of one day, 0 when none was logged
+type MoodDay struct {
+	Date   string  `json:"date" yaml:"date"`
+	Status float64 `json:"status" yaml:"status"`
+}
+
+func runToday(cmd *cobra.Command, args []string) error {
+	moodDays, _ := cmd.Flags().GetInt("days")
+	if moodDays < 1 {
+		return invalidArgf("--days must be at least 1")
+	}
+
+	storageProvider, err := createStorageProvider()
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := today.AddDate(0, 0, -max(moodDays-1, storage.OpenTaskLookback))
+	days, err := storageProvider.GetDateRange(start, today)
+	if err != nil {
+		return fmt.Errorf("failed to get entries: %w", err)
+	}
+
+	view := buildTodayView(days, today, moodDays, config.List(viper.GetViper(), "today.habits"),
+		storage.TagAliases(config.Aliases(viper.GetViper(), "tags.aliases")))
+	if ok, err := outputStructured(view); ok {
+		return err
+	}
+	printTodayView(view)
+	return nil
+}
+
+// buildTodayView gathers the sections of the today screen from the days
+// read, which end today
+func buildTodayView(days []storage.DayLog, today time.Time, moodDays int, habits []string, tags storage.TagAliases) *TodayView {
+	view := &TodayView{Date: today.Format("2006-01-02"), Entries: []storage.DailyLogEntry{}}
+
+	byDate := make(map[string]storage.DayLog, len(days))
+	var earlier []storage.DayLog
+	taskStart := today.AddDate(0, 0, -storage.OpenTaskLookback)
+	for _, day := range days {
+		byDate[day.GetDateString()] = day
+		if day.Date.Before(today) && !day.Date.Before(taskStart) {
+			earlier = append(earlier, day)
+		}
+	}
+
+	if day, ok := byDate[view.Date]; ok {
+		view.Entries = day.Entries
+		storage.SortEntries(view.Entries, storage.DefaultSortOrder)
+		view.StatusAverage = day.StatusAverage
+		for _, entry := range day.Entries {
+			if entry.Duration != nil {
+				view.TrackedMinutes += *entry.Duration
+			}
+		}
+	}
+
+	view.OpenTasks = storage.OpenTasks(earlier)
+	if view.OpenTasks == nil {
+		view.OpenTasks = []storage.DailyLogEntry{}
+	}
+
+	for _, habit := range habits {
+		done := false
+		for _, entry := range view.Entries {
+			if tags.HasAnyTag(entry, []string{habit}) {
+				done = true
+				break
+			}
+		}
+		view.Habits = append(view.Habits, HabitCheck{Habit: habit, Done: done})
+	}
+
+	for i := moodDays - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i).Format("2006-01-02")
+		view.Mood = append(view.Mood, MoodDay{Date: date, Status: byDate[date].StatusAverage})
+	}
+	return view
+}
+
+// printTodayView renders the today screen
+func printTodayView(view *TodayView) {
+	date, _ := time.Parse("2006-01-02", view.Date)
+	title := "Today - " + date.Format("Monday, 2006-01-02")
+	fmt.Println(style(styleBold, title))
+	fmt.Println(rule("=", len(title)))
+
+	summary := fmt.Sprintf("%d entries", len(view.Entries))
+	if view.TrackedMinutes > 0 {
+		summary += ", " + formatMinutes(view.TrackedMinutes) + " tracked"
+	}
+	if view.StatusAverage > 0 {
+		summary += fmt.Sprintf(", mood %.1f", view.StatusAverage)
+	}
+	fmt.Println(style(styleDim, summary))
+
+	fmt.Println()
+	fmt.Println(style(styleBold, "Entries"))
+	if len(view.Entries) == 0 {
+		fmt.Println("  Nothing logged yet")
+	}
+	for _, entry := range view.Entries {
+		line := "  " + entry.Timestamp.Format("15:04") + "  " + column(styleType(entry.Type), 9) + entry.Title
+		if entry.Duration != nil && *entry.Duration > 0 {
+			line += style(styleDim, " ("+formatMinutes(*entry.Duration)+")")
+		}
+		if entry.Status > 0 {
+			line += " " + formatStatus(entry.Status)
+		}
+		fmt.Println(line)
+	}
+
+	fmt.Println()
+	fmt.Println(style(styleBold, "Open tasks"))
+	if len(view.OpenTasks) == 0 {
+		fmt.Println("  None carried over")
+	}
+	for _, task := range view.OpenTasks {
+		line := "  ☐ " + task.Title
+		if task.Priority > 0 {
+			line += " " + formatPriority(task.Priority)
+		}
+		fmt.Println(line + style(styleDim, " · "+task.Timestamp.Format("Mon 01-02")))
+	}
+
+	if len(view.Habits) > 0 {
+		fmt.Println()
+		fmt.Println(style(styleBold, "Habits"))
+		var checks []string
+		for _, habit := range view.Habits {
+			if habit.Done {
+				checks = append(checks, style(styleGreen, "✓ "+habit.Habit))
+			} else {
+				checks = append(checks, style(styleDim, "☐ "+habit.Habit))
+			}
+		}
+		fmt.Println("  " + strings.Join(checks, "  "))
+	}
+
+	fmt.Println()
+	fmt.Println(style(styleBold, fmt.Sprintf("Mood, last %d days", len(view.Mood))))
+	fmt.Println("  " + moodSparkline(view.Mood))
+}
+
+// sparkBlocks are the sparkline bars, from the lowest status to the highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// moodSparkline draws a bar per day for statuses 1-10, colored by mood,
+// with a dot for days without one
+func moodSparkline(days []MoodDay) string {
+	var b strings.Builder
+	for _, day := range days {
+		if day.Status <= 0 {
+			b.WriteString(style(styleDim, "·"))
+			continue
+		}
+		b.WriteString(style(statusStyle(int(day.Status+0.5)), string(sparkBlocks[sparkLevel(day.Status, 1, 10)])))
+	}
+	return b.String()
+}
+
+// sparkLevel picks the sparkline bar for value on a scale from lo to hi
+func sparkLevel(value, lo, hi float64) int {
+	level := int((value - lo) / (hi - lo) * float64(len(sparkBlocks)-1))
+	return min(max(level, 0), len(sparkBlocks)-1)
+}
@@ -6,16 +6,27 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// VersionInfo is the build information reported by the version command
+type VersionInfo struct {
+	Version string `json:"version" yaml:"version"`
+	Commit  string `json:"commit" yaml:"commit"`
+	Date    string `json:"date" yaml:"date"`
+}
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show version information",
 	Long:  `Display version, build commit, and build date information for dailyctl.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		v, c, d := GetVersionInfo()
+		if ok, err := outputStructured(VersionInfo{Version: v, Commit: c, Date: d}); ok {
+			return err
+		}
 		fmt.Printf("dailyctl version %s\n", v)
 		fmt.Printf("Build commit: %s\n", c)
 		fmt.Printf("Build date: %s\n", d)
+		return nil
 	},
 }
 
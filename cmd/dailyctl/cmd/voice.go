@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+	"dailylog/internal/transcribe"
+)
+
+// voiceTitleWidth is how much of the transcript's first sentence becomes the title
+const voiceTitleWidth = 72
+
+var logVoiceCmd = &cobra.Command{
+	Use:   "voice <audio file>",
+	Short: "Transcribe a voice memo into a note",
+	Long: `Transcribe an audio file such as a voice memo and log the transcript as a
+note entry, with the recording attached: it is stored in the log repository
+under attachments/, and the entry's "attachment" metadata holds its path.
+
+The entry is logged at the time the file was last modified, which is when
+most recorders finish a memo, unless --date or --datetime is given. Its title
+is the transcript's first sentence unless --title is given.
+
+Transcription uses the Whisper API by default (transcribe.api_key, or
+DAILYLOG_TRANSCRIBE_API_KEY or OPENAI_API_KEY), or any service with the same
+interface at transcribe.url. Set transcribe.provider to whisper-cpp to
+transcribe locally instead, with transcribe.model pointing at a whisper.cpp
+model and transcribe.binary at its command (whisper-cli by default); audio
+other than WAV is converted with ffmpeg first.
+
+Examples:
+  dailyctl log voice memo.m4a
+  dailyctl log voice memo.m4a --tags idea --title "Onboarding idea"
+  dailyctl log voice ~/Recordings/standup.wav --datetime "today 9:30" --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runLogVoice,
+}
+
+func init() {
+	logCmd.AddCommand(logVoiceCmd)
+
+	logVoiceCmd.Flags().String("date", "", "Date for the entry (YYYY-MM-DD or e.g. \"yesterday\")")
+	logVoiceCmd.Flags().String("datetime", "", "Date and time for the entry (default: when the file was last modified)")
+	logVoiceCmd.Flags().String("title", "", "Title for the entry (default: the transcript's first sentence)")
+	logVoiceCmd.Flags().StringSlice("tags", []string{}, "Tags for categorization")
+	logVoiceCmd.Flags().String("visibility", "", "Visibility: private, team, public (defaults to privacy.default, or team)")
+	logVoiceCmd.Flags().String("language", "", "Language spoken, e.g. en (default: detect)")
+	_ = logVoiceCmd.RegisterFlagCompletionFunc("tags", completeTags)
+	logVoiceCmd.MarkFlagsMutuallyExclusive("date", "datetime")
+
+	_ = viper.BindPFlag("transcribe.language", logVoiceCmd.Flags().Lookup("language"))
+}
+
+func runLogVoice(cmd *cobra.Command, args []string) error {
+	audioPath := args[0]
+	dateStr, _ := cmd.Flags().GetString("date")
+	datetimeStr, _ := cmd.Flags().GetString("datetime")
+	title, _ := cmd.Flags().GetString("title")
+	tags, _ := cmd.Flags().GetStringSlice("tags")
+	visibility, _ := cmd.Flags().GetString("visibility")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	if err := storage.ValidateVisibility(visibility); err != nil {
+		return err
+	}
+	info, err := os.Stat(audioPath)
+	if err != nil {
+		return invalidArgf("cannot read %s: %v", audioPath, err)
+	}
+	if info.IsDir() {
+		return invalidArgf("%s is a directory, not an audio file", audioPath)
+	}
+
+	now := time.Now()
+	entryDate := info.ModTime()
+	switch {
+	case datetimeStr != "":
+		if entryDate, err = datetime.Parse(datetimeStr, now); err != nil {
+			return invalidArgf("invalid datetime format: %s (%w)", datetimeStr, err)
+		}
+	case dateStr != "":
+		day, err := datetime.ParseDate(dateStr, now)
+		if err != nil {
+			return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+		}
+		entryDate = time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), 0, now.Location())
+	}
+
+	transcriber, err := newTranscriber()
+	if err != nil {
+		return fmt.Errorf("failed to set up transcription: %w (see dailyctl log voice --help)", err)
+	}
+	if !quiet() {
+		fmt.Fprintf(os.Stderr, "Transcribing %s with %s...\n", audioPath, transcriber.Name())
+	}
+	transcript, err := transcriber.Transcribe(audioPath)
+	if err != nil {
+		return fmt.Errorf("failed to transcribe %s: %w", audioPath, err)
+	}
+	if transcript == "" {
+		return fmt.Errorf("no speech found in %s", audioPath)
+	}
+	if strings.TrimSpace(title) == "" {
+		title = transcriptTitle(transcript)
+	}
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+	attachment, err := attachFile(audioPath, entryDate, dryRun)
+	if err != nil {
+		return err
+	}
+
+	entry, err := storageProvider.CreateEntry(storage.CreateLogEntryRequest{
+		Date:        entryDate,
+		Type:        "note",
+		Title:       title,
+		Description: transcript,
+		Tags:        tags,
+		Visibility:  visibility,
+		Metadata: map[string]string{
+			storage.MetadataAttachment:  attachment,
+			storage.MetadataTranscriber: transcriber.Name(),
+		},
+	})
+	var duplicate storage.DuplicateError
+	if errors.As(err, &duplicate) {
+		return outputSkippedDuplicate(&duplicate.Existing)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create entry: %w", err)
+	}
+
+	if err := outputCreatedEntry(entry, dryRun); err != nil {
+		return err
+	}
+	reportDryRun(preview)
+	return nil
+}
+
+// newTranscriber creates the configured speech-to-text provider
+func newTranscriber() (transcribe.Transcriber, error) {
+	apiKey := viper.GetString("transcribe.api_key")
+	if apiKey == "" {
+		apiKey = os.Getenv("OPENAI_API_KEY")
+	}
+	return transcribe.New(transcribe.Options{
+		Provider: viper.GetString("transcribe.provider"),
+		APIKey:   apiKey,
+		URL:      viper.GetString("transcribe.url"),
+		Model:    viper.GetString("transcribe.model"),
+		Binary:   viper.GetString("transcribe.binary"),
+		Language: viper.GetString("transcribe.language"),
+	})
+}
+
+// transcriptTitle is the transcript's first sentence, shortened to fit a title
+func transcriptTitle(transcript string) string {
+	title := strings.Join(strings.Fields(transcript), " ")
+	if end := strings.IndexAny(title, ".!?"); end > 0 {
+		title = title[:end]
+	}
+	if len([]rune(title)) <= voiceTitleWidth {
+		return title
+	}
+	cut := []rune(title)[:voiceTitleWidth-3]
+	if space := strings.LastIndex(string(cut), " "); space > voiceTitleWidth/2 {
+		return string(cut)[:space] + "..."
+	}
+	return string(cut) + "..."
+}
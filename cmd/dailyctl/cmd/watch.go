@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+	"dailylog/internal/storage"
+)
+
+// watchEntries shows entries with show, then again every interval, or only
+// when changed reports a change if it is set, until interrupted
+func watchEntries(interval time.Duration, changed func() (bool, error), show func() error) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if changed != nil {
+		// Take the baseline first, so a change made while showing isn't missed
+		if _, err := changed(); err != nil {
+			return err
+		}
+	}
+	if err := show(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if changed != nil {
+			ok, err := changed()
+			if err != nil {
+				// Keep the last view up through network blips
+				fmt.Fprintf(os.Stderr, "⚠ %s: %v\n", time.Now().Format("15:04:05"), err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+		}
+		if err := show(); err != nil {
+			fmt.Fprintf(os.Stderr, "⚠ %s: %v\n", time.Now().Format("15:04:05"), err)
+		}
+	}
+}
+
+// dayChangeCheck returns a check for changes to a day stored on GitHub, by
+// its ETag, or nil if the storage has no cheap way to tell
+func dayChangeCheck(date time.Time) func() (bool, error) {
+	if viper.GetBool("demo") || config.Storage(viper.GetViper()).StorageType == storage.StorageTypeMemory {
+		return nil
+	}
+	primary, err := createPrimaryProvider()
+	if err != nil {
+		return nil
+	}
+
+	var etag string
+	return func() (bool, error) {
+		current, changed, err := primary.DayChanged(date, etag)
+		if err != nil {
+			return false, err
+		}
+		etag = current
+		return changed, nil
+	}
+}
+
+// clearScreen clears the terminal for a redraw; piped output is left alone
+func clearScreen() {
+	if isTerminal() {
+		fmt.Print("\033[H\033[2J")
+	}
+}
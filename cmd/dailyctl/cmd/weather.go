@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+	"dailylog/internal/weather"
+)
+
+// weatherCmd represents the weather command
+var weatherCmd = &cobra.Command{
+	Use:   "weather",
+	Short: "Record the weather of logged days",
+	Long: `Look up the weather of a day and record it in the day's metadata, where
+summaries and analyze_status pick it up. Days that already have weather are
+shown as they are unless --refresh is given.
+
+The weather is looked up at the location most of the day's entries were
+logged at, or at weather.location (a place name or "latitude,longitude")
+when they have none or it cannot be found. Open-Meteo is used unless
+weather.forecast_url, weather.archive_url, and weather.geocode_url point at
+another compatible service.
+
+With weather.enabled set, the weather is recorded automatically the first
+time an entry is logged on a day; this command backfills earlier days.
+
+Examples:
+  dailyctl weather
+  dailyctl weather --date yesterday
+  dailyctl weather --date-start 2025-09-01 --date-end 2025-09-30
+  dailyctl weather --date 2025-09-29 --location "Berlin" --refresh`,
+	Args: cobra.NoArgs,
+	RunE: runWeather,
+}
+
+func init() {
+	rootCmd.AddCommand(weatherCmd)
+
+	weatherCmd.Flags().String("date", "", "Date to record (YYYY-MM-DD or e.g. \"yesterday\", default: today)")
+	weatherCmd.Flags().String("date-start", "", "Record every logged day from this date (YYYY-MM-DD)")
+	weatherCmd.Flags().String("date-end", "", "Record every logged day up to this date (YYYY-MM-DD)")
+	weatherCmd.Flags().String("location", "", "Location to use when entries have none (default: weather.location)")
+	weatherCmd.Flags().Bool("refresh", false, "Look up the weather again for days that already have it")
+	weatherCmd.Flags().Bool("dry-run", false, dryRunUsage)
+	weatherCmd.MarkFlagsMutuallyExclusive("date", "date-start")
+	weatherCmd.MarkFlagsMutuallyExclusive("date", "date-end")
+
+	_ = viper.BindPFlag("weather.location", weatherCmd.Flags().Lookup("location"))
+}
+
+// WeatherResult is the weather of one day
+type WeatherResult struct {
+	Date     string           `json:"date" yaml:"date"`
+	Weather  *storage.Weather `json:"weather,omitempty" yaml:"weather,omitempty"`
+	Recorded bool             `json:"recorded" yaml:"recorded"`
+	Error    string           `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func runWeather(cmd *cobra.Command, args []string) error {
+	dateStr, _ := cmd.Flags().GetString("date")
+	refresh, _ := cmd.Flags().GetBool("refresh")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	var dates []time.Time
+	if cmd.Flags().Changed("date-start") || cmd.Flags().Changed("date-end") {
+		start, end, err := parseMirrorRange(cmd)
+		if err != nil {
+			return err
+		}
+		if dates, err = storageProvider.ListDays(start, end); err != nil {
+			return fmt.Errorf("failed to list days: %w", err)
+		}
+	} else {
+		date := time.Now()
+		if dateStr != "" {
+			if date, err = datetime.ParseDate(dateStr, time.Now()); err != nil {
+				return invalidArgf("invalid date: %s (use YYYY-MM-DD or e.g. \"yesterday\")", dateStr)
+			}
+		}
+		dates = []time.Time{date}
+	}
+
+	recorder := providers.NewWeatherProvider(storageProvider, newWeatherSource(), viper.GetString("weather.location"))
+	results := make([]WeatherResult, 0, len(dates))
+	failed := 0
+	for _, date := range dates {
+		result := WeatherResult{Date: date.Format("2006-01-02")}
+		if date.After(time.Now()) {
+			result.Error = "the day has not happened yet"
+		} else if w, recorded, err := recorder.Enrich(date, refresh); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Weather, result.Recorded = w, recorded
+		}
+		if result.Error != "" {
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	if ok, err := outputStructured(results); ok {
+		if err == nil && failed > 0 {
+			err = fmt.Errorf("failed to record the weather for %d of %d days", failed, len(dates))
+		}
+		return err
+	}
+	if !quiet() {
+		for _, result := range results {
+			switch {
+			case result.Error != "":
+				fmt.Printf("%s  ✗ %s\n", result.Date, result.Error)
+			case result.Recorded && dryRun:
+				fmt.Printf("%s  %s, %s (would record)\n", result.Date, result.Weather.Location, result.Weather)
+			case result.Recorded:
+				fmt.Printf("%s  %s, %s ✓\n", result.Date, result.Weather.Location, result.Weather)
+			default:
+				fmt.Printf("%s  %s, %s\n", result.Date, result.Weather.Location, result.Weather)
+			}
+		}
+	}
+	reportDryRun(preview)
+	if failed > 0 {
+		return fmt.Errorf("failed to record the weather for %d of %d days", failed, len(dates))
+	}
+	return nil
+}
+
+// newWeatherSource creates the configured weather service client
+func newWeatherSource() weather.Source {
+	return weather.NewClient(weather.Options{
+		ForecastURL: viper.GetString("weather.forecast_url"),
+		ArchiveURL:  viper.GetString("weather.archive_url"),
+		GeocodeURL:  viper.GetString("weather.geocode_url"),
+	})
+}
+
+// withWeather records the weather of days as entries are logged on them when
+// weather.enabled is set
+func withWeather(backend storage.DailyLogStorage) storage.DailyLogStorage {
+	if !viper.GetBool("weather.enabled") {
+		return backend
+	}
+	provider := providers.NewWeatherProvider(backend, newWeatherSource(), viper.GetString("weather.location"))
+	provider.OnError = func(err error) {
+		fmt.Fprintf(os.Stderr, "⚠ Failed to record the weather: %v\n", err)
+	}
+	return provider
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// entryTypes are the entry types offered by the wizard, in menu order
+var entryTypes = []string{"activity", "status", "note", "summary", storage.EntryTypeMeeting, storage.EntryTypeLesson}
+
+// tagHistoryDays is how far back the wizard looks for tags to suggest
+const tagHistoryDays = 90
+
+// maxTagSuggestions caps the numbered tag suggestions shown per entry
+const maxTagSuggestions = 12
+
+// prompter reads answers to questions one line at a time
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints a question and returns the trimmed answer, or def if it is blank
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// askInt asks until the answer is blank (0) or a number within min and max
+func (p *prompter) askInt(question string, min, max int) (int, error) {
+	for {
+		answer, err := p.ask(question, "")
+		if err != nil || answer == "" {
+			return 0, err
+		}
+		n, err := strconv.Atoi(answer)
+		if err == nil && n >= min && n <= max {
+			return n, nil
+		}
+		fmt.Fprintf(p.out, "  Enter a number from %d to %d, or leave blank to skip\n", min, max)
+	}
+}
+
+// runLogWizard walks through creating entries interactively until the user stops
+func runLogWizard(cmd *cobra.Command) error {
+	storageProvider, preview, err := createWriteProvider(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to create storage provider: %w", err)
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: cmd.OutOrStdout()}
+	knownTags := tagHistory(storageProvider, time.Now())
+
+	fmt.Fprintln(p.out, "Log entries interactively. Leave optional answers blank to skip; press Ctrl-D to stop.")
+
+	created := 0
+	for {
+		fmt.Fprintln(p.out)
+		req, err := askEntry(p, knownTags)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		entry, err := storageProvider.CreateEntry(req)
+		var duplicate storage.DuplicateError
+		switch {
+		case errors.As(err, &duplicate):
+			fmt.Fprintf(p.out, "Skipped duplicate of existing %s entry: %s (%s)\n",
+				duplicate.Existing.Type, duplicate.Existing.Title, duplicate.Existing.ID)
+		case err != nil:
+			return fmt.Errorf("failed to create entry: %w", err)
+		default:
+			created++
+			verb := "✓ Created"
+			if preview != nil {
+				verb = "Would create"
+			}
+			fmt.Fprintf(p.out, "%s %s entry: %s (%s)\n", verb, entry.Type, entry.Title, entry.Timestamp.Format("2006-01-02 15:04"))
+			knownTags = rememberTags(knownTags, entry.Tags)
+		}
+
+		another, err := p.ask("\nLog another entry? (y/n)", "y")
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(strings.ToLower(another), "y") {
+			break
+		}
+	}
+
+	fmt.Fprintf(p.out, "\nLogged %d entries.\n", created)
+	reportDryRun(preview)
+	return nil
+}
+
+// askEntry asks for each field of one entry
+func askEntry(p *prompter, knownTags []string) (storage.CreateLogEntryRequest, error) {
+	var req storage.CreateLogEntryRequest
+
+	entryType, err := askEntryType(p)
+	if err != nil {
+		return req, err
+	}
+	req.Type = entryType
+
+	for req.Title == "" {
+		if req.Title, err = p.ask("Title", ""); err != nil {
+			return req, err
+		}
+	}
+
+	if req.Description, err = p.ask("Description (optional)", ""); err != nil {
+		return req, err
+	}
+
+	if req.Tags, err = askTags(p, knownTags); err != nil {
+		return req, err
+	}
+
+	if req.Type == storage.EntryTypeMeeting {
+		if req.Metadata, err = askMeeting(p); err != nil {
+			return req, err
+		}
+	}
+
+	mood, err := p.askInt("Mood/status 1-10 (optional)", 1, 10)
+	if err != nil {
+		return req, err
+	}
+	if mood > 0 {
+		req.Status = &mood
+	}
+
+	for {
+		answer, err := p.ask("Duration, e.g. 45m or 1h30m (optional)", "")
+		if err != nil {
+			return req, err
+		}
+		if answer == "" {
+			break
+		}
+		if minutes, err := storage.ParseDurationMinutes(answer); err == nil {
+			req.Duration = &minutes
+			break
+		}
+		fmt.Fprintln(p.out, "  Enter minutes (45) or a duration (45m, 1h30m)")
+	}
+
+	for {
+		answer, err := p.ask("When", "now")
+		if err != nil {
+			return req, err
+		}
+		if req.Date, err = datetime.Parse(answer, time.Now()); err == nil {
+			break
+		}
+		fmt.Fprintln(p.out, `  Enter a time such as "2pm", "yesterday 4:30pm", or "2025-09-29 14:30"`)
+	}
+
+	return req, nil
+}
+
+// askMeeting asks for a meeting's attendees, decisions, and action items
+func askMeeting(p *prompter) (map[string]string, error) {
+	var meeting storage.Meeting
+	attendees, err := p.ask("Attendees, comma-separated (optional)", "")
+	if err != nil {
+		return nil, err
+	}
+	meeting.Attendees = strings.Split(attendees, ",")
+
+	if meeting.Decisions, err = askList(p, "Decision"); err != nil {
+		return nil, err
+	}
+	if meeting.ActionItems, err = askList(p, "Action item"); err != nil {
+		return nil, err
+	}
+	if metadata := meeting.Metadata(nil); len(metadata) > 0 {
+		return metadata, nil
+	}
+	return nil, nil
+}
+
+// askList asks for items one at a time until an empty answer
+func askList(p *prompter, question string) ([]string, error) {
+	var items []string
+	for {
+		item, err := p.ask(question+" (empty to finish)", "")
+		if err != nil || item == "" {
+			return items, err
+		}
+		items = append(items, item)
+	}
+}
+
+// askEntryType accepts a type by name, unique prefix, or menu number
+func askEntryType(p *prompter) (string, error) {
+	for i, entryType := range entryTypes {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, entryType)
+	}
+	for {
+		answer, err := p.ask("Type", entryTypes[0])
+		if err != nil {
+			return "", err
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(entryTypes) {
+			return entryTypes[n-1], nil
+		}
+		if choice := completeChoice(answer, entryTypes); choice != "" {
+			return choice, nil
+		}
+		fmt.Fprintf(p.out, "  Choose 1-%d or one of %s\n", len(entryTypes), strings.Join(entryTypes, ", "))
+	}
+}
+
+// askTags suggests frequently used tags and completes numbers and unique
+// prefixes to them; anything else is taken as a new tag
+func askTags(p *prompter, knownTags []string) ([]string, error) {
+	suggestions := knownTags
+	if len(suggestions) > maxTagSuggestions {
+		suggestions = suggestions[:maxTagSuggestions]
+	}
+	if len(suggestions) > 0 {
+		var numbered []string
+		for i, tag := range suggestions {
+			numbered = append(numbered, fmt.Sprintf("%d) %s", i+1, tag))
+		}
+		fmt.Fprintf(p.out, "  Recent tags: %s\n", strings.Join(numbered, "  "))
+	}
+
+	answer, err := p.ask("Tags, comma-separated; numbers or prefixes complete (optional)", "")
+	if err != nil || answer == "" {
+		return nil, err
+	}
+
+	var tags []string
+	var completed []string
+	for _, token := range strings.Split(answer, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		tag := token
+		if n, err := strconv.Atoi(token); err == nil && n >= 1 && n <= len(suggestions) {
+			tag = suggestions[n-1]
+		} else if choice := completeChoice(token, knownTags); choice != "" {
+			tag = choice
+		}
+		if tag != token {
+			completed = append(completed, fmt.Sprintf("%s → %s", token, tag))
+		}
+		tags = append(tags, tag)
+	}
+	if len(completed) > 0 {
+		fmt.Fprintf(p.out, "  Completed %s\n", strings.Join(completed, ", "))
+	}
+	return tags, nil
+}
+
+// completeChoice returns the choice matching answer exactly or by unique
+// prefix, ignoring case, or "" if none does
+func completeChoice(answer string, choices []string) string {
+	lower := strings.ToLower(answer)
+	var matches []string
+	for _, choice := range choices {
+		if strings.ToLower(choice) == lower {
+			return choice
+		}
+		if strings.HasPrefix(strings.ToLower(choice), lower) {
+			matches = append(matches, choice)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0]
+	}
+	return ""
+}
+
+// tagHistory returns the tags used recently, most used first. History is
+// only used for suggestions, so a failed lookup just means none are offered.
+func tagHistory(storageProvider storage.DailyLogStorage, now time.Time) []string {
+	end := datetime.StartOfDay(now)
+	start := end.AddDate(0, 0, -tagHistoryDays)
+	result, err := storageProvider.SearchLogs(storage.LogSearchRequest{
+		DateStart:    &start,
+		DateEnd:      &end,
+		Limit:        1,
+		Aggregations: []string{storage.GroupByTag},
+	})
+	if err != nil || len(result.Aggregations) == 0 {
+		return nil
+	}
+
+	var tags []string
+	for _, bucket := range result.Aggregations[0].Buckets {
+		tags = append(tags, bucket.Key)
+	}
+	return tags
+}
+
+// rememberTags adds newly used tags to the end of the known tags
+func rememberTags(knownTags, tags []string) []string {
+	for _, tag := range tags {
+		known := false
+		for _, existing := range knownTags {
+			if existing == tag {
+				known = true
+				break
+			}
+		}
+		if !known {
+			knownTags = append(knownTags, tag)
+		}
+	}
+	return knownTags
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"dailylog/cmd/dailyctl/cmd"
@@ -15,7 +14,7 @@ var (
 
 func main() {
 	if err := cmd.Execute(version, commit, date); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		cmd.PrintError(err)
+		os.Exit(cmd.ExitCode(err))
 	}
 }
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/prompts"
+	"dailylog/internal/storage"
+)
+
+// ExtractActionsInput defines parameters for extracting action items into tasks
+type ExtractActionsInput struct {
+	Date     string `json:"date,omitempty" jsonschema:"Day to scan in YYYY-MM-DD format (defaults to today)"`
+	Priority *int   `json:"priority,omitempty" jsonschema:"Priority 1-5 of the created tasks (defaults to 3)"`
+	UseAI    bool   `json:"use_ai,omitempty" jsonschema:"Also ask the AI provider for action items in entries without TODO-style lines"`
+	NoCache  bool   `json:"no_cache,omitempty" jsonschema:"Regenerate AI results instead of using cached ones"`
+	DryRun   bool   `json:"dry_run,omitempty" jsonschema:"Return the tasks that would be created without saving anything"`
+}
+
+// ExtractActionsOutput defines the response for extracting action items
+type ExtractActionsOutput struct {
+	Date      string           `json:"date" jsonschema:"Day scanned"`
+	Created   []LogEntryOutput `json:"created" jsonschema:"Task entries created, each linked to its source entry by source_id metadata"`
+	Existing  int              `json:"existing,omitempty" jsonschema:"Action items skipped because a linked task already exists"`
+	DryRun    bool             `json:"dry_run,omitempty" jsonschema:"Whether this is a preview and nothing was saved"`
+	Success   bool             `json:"success" jsonschema:"Whether operation was successful"`
+	Message   string           `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode string           `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
+}
+
+// ExtractActions turns the action items in a day's entries into linked task notes
+func (s *Server) ExtractActions(ctx context.Context, req *mcp.CallToolRequest, input ExtractActionsInput) (
+	*mcp.CallToolResult,
+	ExtractActionsOutput,
+	error,
+) {
+	logToolCall("ExtractActions", input)
+	s = s.forRequest(ctx, req)
+
+	date := time.Now()
+	if input.Date != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return nil, ExtractActionsOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
+			}, nil
+		}
+	}
+
+	priority := storage.DefaultActionPriority
+	if input.Priority != nil {
+		priority = *input.Priority
+	}
+	if priority < 1 || priority > 5 {
+		return nil, ExtractActionsOutput{
+			Success:   false,
+			Message:   "Priority must be between 1 and 5",
+			ErrorCode: errorValidation,
+		}, nil
+	}
+
+	dayLog, err := s.storage.GetDay(date)
+	if err != nil {
+		return nil, ExtractActionsOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to get day: %v", err),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	actions := storage.ExtractActions(dayLog.Entries)
+	if input.UseAI {
+		actions = append(actions, s.suggestActions(dayLog.Entries, actions, input.NoCache)...)
+	}
+	requests := storage.ActionTaskRequests(actions, dayLog.Entries, priority)
+
+	store := s.storage
+	if input.DryRun {
+		store, _ = s.preview()
+	}
+
+	result := ExtractActionsOutput{
+		Date:     date.Format("2006-01-02"),
+		Created:  []LogEntryOutput{},
+		Existing: len(actions) - len(requests),
+		DryRun:   input.DryRun,
+	}
+	for _, createReq := range requests {
+		entry, err := store.CreateEntry(createReq)
+		if err != nil {
+			result.Message = fmt.Sprintf("Created %d of %d tasks before failing: %v", len(result.Created), len(requests), err)
+			result.ErrorCode = errorCode(err)
+			return nil, result, nil
+		}
+		result.Created = append(result.Created, logEntryOutput(entry))
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Created %d tasks from %d action items", len(result.Created), len(actions))
+	if input.DryRun {
+		result.Message = fmt.Sprintf("Would create %d tasks from %d action items", len(result.Created), len(actions))
+	}
+	return nil, result, nil
+}
+
+// suggestActions asks the AI provider for action items in entries that have
+// a description but no action items found by pattern
+func (s *Server) suggestActions(entries []storage.DailyLogEntry, found []storage.Action, noCache bool) []storage.Action {
+	if s.ai == nil {
+		return nil
+	}
+
+	scanned := make(map[string]bool)
+	for _, action := range found {
+		scanned[action.SourceID] = true
+	}
+
+	var suggested []storage.Action
+	for _, entry := range entries {
+		if scanned[entry.ID] || entry.Description == "" || entry.Metadata[storage.MetadataSourceID] != "" {
+			continue
+		}
+		data := prompts.Data{Period: entry.Timestamp.Format("2006-01-02"), Entries: []storage.DailyLogEntry{entry}}
+		reply, err := s.draftWithAI("extract_actions", data, "", noCache)
+		if err != nil {
+			slog.Warn("Failed to extract actions", "entry", entry.ID, "error", err)
+			continue
+		}
+		for _, line := range strings.Split(reply, "\n") {
+			if text, ok := storage.ParseActionLine(line); ok {
+				suggested = append(suggested, storage.Action{
+					Text:       text,
+					SourceID:   entry.ID,
+					Source:     entry.Title,
+					Timestamp:  entry.Timestamp,
+					Visibility: entry.Visibility,
+				})
+			}
+		}
+	}
+	return suggested
+}
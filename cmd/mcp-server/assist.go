@@ -0,0 +1,415 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"dailylog/internal/ai"
+	"dailylog/internal/prompts"
+	"dailylog/internal/storage"
+)
+
+// notableEntries is how many of the best and worst rated entries analyze_status reports
+const notableEntries = 3
+
+// weeklyRetro drafts a what-went-well/what-didn't retrospective for the week containing date
+func (s *Server) weeklyRetro(date time.Time, noCache bool) (string, []string, error) {
+	week, err := s.storage.GetWeek(date)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var entries []storage.DailyLogEntry
+	for _, day := range week.Days {
+		entries = append(entries, day.Entries...)
+	}
+	data := prompts.Data{
+		Period:        fmt.Sprintf("the week of %s to %s", week.WeekStart.Format("2006-01-02"), week.WeekEnd.Format("2006-01-02")),
+		Entries:       entries,
+		StatusAverage: statusAverage(entries),
+	}
+
+	var wentWell, didnt []string
+	for _, entry := range entries {
+		switch {
+		case entry.Status >= 7:
+			wentWell = append(wentWell, entry.Title)
+		case entry.Status > 0 && entry.Status <= 4:
+			didnt = append(didnt, entry.Title)
+		}
+	}
+
+	var draft strings.Builder
+	fmt.Fprintf(&draft, "Weekly retro for %s (%d entries, average status %.1f)\n", data.Period, len(entries), data.StatusAverage)
+	writeSection(&draft, "What went well", wentWell, "Nothing rated 7 or higher this week")
+	writeSection(&draft, "What didn't go well", didnt, "Nothing rated 4 or lower this week")
+
+	data.Facts = draft.String()
+	result, err := s.draftWithAI("weekly_retro", data, draft.String(), noCache)
+	return result, nil, err
+}
+
+// gratitudePrompts drafts gratitude journaling prompts from the day's entries
+func (s *Server) gratitudePrompts(date time.Time, noCache bool) (string, []string, error) {
+	dayLog, err := s.storage.GetDay(date)
+	if err != nil {
+		return "", nil, err
+	}
+	data := prompts.Data{Period: date.Format("Monday, 2006-01-02"), Entries: dayLog.Entries}
+
+	// Ask about the best-rated moments of the day first, then fill in with general prompts
+	best := append([]storage.DailyLogEntry(nil), dayLog.Entries...)
+	sort.SliceStable(best, func(i, j int) bool { return best[i].Status > best[j].Status })
+
+	var prompts []string
+	for _, entry := range best {
+		if entry.Status < 7 || len(prompts) == 3 {
+			break
+		}
+		prompts = append(prompts, fmt.Sprintf("What made %q go well, and who helped make it happen?", entry.Title))
+	}
+	for _, general := range []string{
+		"Who made your day easier, and how could you thank them?",
+		"What small thing today are you glad happened?",
+		"What progress did you make today that you can appreciate?",
+	} {
+		if len(prompts) == 3 {
+			break
+		}
+		prompts = append(prompts, general)
+	}
+
+	draft := fmt.Sprintf("Gratitude prompts for %s:\n- %s", data.Period, strings.Join(prompts, "\n- "))
+	data.Facts = draft
+	result, err := s.draftWithAI("gratitude_prompt", data, draft, noCache)
+	return result, prompts, err
+}
+
+// tomorrowPlan drafts a plan for the day after date from open tasks and recent
+// entries. Open tasks are notes with a priority from the last week.
+func (s *Server) tomorrowPlan(date time.Time, noCache bool) (string, []string, error) {
+	days, err := s.storage.GetDateRange(date.AddDate(0, 0, -storage.OpenTaskLookback), date)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var recent []storage.DailyLogEntry
+	for _, day := range days {
+		recent = append(recent, day.Entries...)
+	}
+	tasks := storage.OpenTasks(days)
+
+	data := prompts.Data{Period: date.AddDate(0, 0, 1).Format("Monday, 2006-01-02"), Entries: recent, Tasks: tasks}
+
+	var plan []string
+	for _, task := range tasks {
+		if len(plan) == 5 {
+			break
+		}
+		if task.Priority > 0 {
+			plan = append(plan, fmt.Sprintf("%s (priority %d)", task.Title, task.Priority))
+		} else {
+			plan = append(plan, fmt.Sprintf("%s (%s)", task.Title, task.Description))
+		}
+	}
+
+	var draft strings.Builder
+	fmt.Fprintf(&draft, "Plan for %s\n", data.Period)
+	writeSection(&draft, "Open tasks", plan, "No open tasks: notes with a priority and meeting action items from the last week show up here")
+
+	data.Facts = draft.String()
+	result, err := s.draftWithAI("tomorrow_plan", data, draft.String(), noCache)
+	return result, plan, err
+}
+
+// generateInsights describes how the day's time was spent and how it went
+func (s *Server) generateInsights(date time.Time, noCache bool) (string, error) {
+	dayLog, err := s.storage.GetDay(date)
+	if err != nil {
+		return "", err
+	}
+	period := date.Format("Monday, 2006-01-02")
+	if len(dayLog.Entries) == 0 {
+		return fmt.Sprintf("No entries for %s yet.", period), nil
+	}
+
+	minutes := 0
+	tagCounts := make(map[string]int)
+	for _, entry := range dayLog.Entries {
+		if entry.Duration != nil {
+			minutes += *entry.Duration
+		}
+		for _, tag := range entry.Tags {
+			tagCounts[tag]++
+		}
+	}
+	tags := make([]string, 0, len(tagCounts))
+	for tag := range tagCounts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tagCounts[tags[i]] != tagCounts[tags[j]] {
+			return tagCounts[tags[i]] > tagCounts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if len(tags) > 3 {
+		tags = tags[:3]
+	}
+
+	facts := fmt.Sprintf("Insights for %s: %d entries, %d minutes logged", period, len(dayLog.Entries), minutes)
+	if average := statusAverage(dayLog.Entries); average > 0 {
+		facts += fmt.Sprintf(", average status %.1f", average)
+	}
+	if len(tags) > 0 {
+		facts += fmt.Sprintf("; most used tags: %s", strings.Join(tags, ", "))
+	}
+	facts += "."
+
+	data := prompts.Data{Period: period, Entries: dayLog.Entries, StatusAverage: statusAverage(dayLog.Entries), Facts: facts}
+	return s.draftWithAI("generate_insights", data, facts, noCache)
+}
+
+// draftWithAI has the AI provider write from the named prompt template and
+// the data's entries, falling back to the draft built from the log when no
+// provider is configured or it fails. Entries that don't fit the token budget
+// in one request are summarized in chunks and combined. Results are cached,
+// and noCache regenerates them.
+func (s *Server) draftWithAI(name string, data prompts.Data, fallback string, noCache bool) (string, error) {
+	if s.ai == nil {
+		return fallback, nil
+	}
+
+	prompt, err := s.prompts.Render(name, data)
+	if err != nil {
+		return "", err
+	}
+	reducePrompt, err := s.prompts.Render("summary_reduce", prompts.Data{Period: data.Period, Prompt: prompt})
+	if err != nil {
+		return "", err
+	}
+
+	var provider storage.AIProvider = s.ai
+	if s.redactor != nil {
+		provider = ai.NewRedactingProvider(provider, s.redactor)
+	}
+	if s.aiCacheDir != "" {
+		cached := ai.NewCachedProvider(s.ai, s.aiCacheDir, s.aiModel)
+		cached.Refresh = noCache
+		provider = cached
+	}
+
+	summarizer := ai.MapReduce{Provider: provider, Budget: s.tokenBudget}
+	if s.verbose {
+		summarizer.Progress = func(format string, args ...any) {
+			slog.Info(fmt.Sprintf(format, args...), "prompt", name)
+		}
+	}
+	result, err := summarizer.Summarize(data.Entries, prompt, reducePrompt)
+	if err != nil {
+		slog.Warn("AI provider failed, using log-based draft", "prompt", name, "error", err)
+		return fallback, nil
+	}
+	return result, nil
+}
+
+// writeSection writes a heading and bullet list, or the placeholder when empty
+func writeSection(b *strings.Builder, heading string, items []string, empty string) {
+	fmt.Fprintf(b, "\n%s:\n", heading)
+	if len(items) == 0 {
+		fmt.Fprintf(b, "- %s\n", empty)
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+}
+
+// statusAverage averages the status of the entries that have one
+func statusAverage(entries []storage.DailyLogEntry) float64 {
+	total, count := 0, 0
+	for _, entry := range entries {
+		if entry.Status > 0 {
+			total += entry.Status
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return float64(total) / float64(count)
+}
+
+// statusPeriod resolves the range analyze_status covers: date_start to
+// date_end when given, otherwise the week ending on date (today by default)
+func statusPeriod(input AIAssistInput, now time.Time) (time.Time, time.Time, error) {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, value := range []string{input.Date, input.DateEnd} {
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", errInvalidDate, value)
+		}
+		end = parsed
+	}
+
+	start := end.AddDate(0, 0, -6)
+	if input.DateStart != "" {
+		parsed, err := time.Parse("2006-01-02", input.DateStart)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", errInvalidDate, input.DateStart)
+		}
+		start = parsed
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, storage.ValidationError{Field: "date_start", Message: "must not be after the end date"}
+	}
+	return start, end, nil
+}
+
+// analyzeStatus computes status statistics for the range and describes them,
+// citing the best and worst rated entries. With an AI provider the
+// description is written from those figures and the entries.
+func (s *Server) analyzeStatus(start, end time.Time, noCache bool) (string, map[string]any, error) {
+	days, err := s.storage.GetDateRange(start, end)
+	if err != nil {
+		return "", nil, err
+	}
+
+	period := fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	var rated, all []storage.DailyLogEntry
+	var dailyAverages []map[string]any
+	var dayValues []float64
+	for _, day := range days {
+		all = append(all, day.Entries...)
+		dayRated := 0
+		for _, entry := range day.Entries {
+			if entry.Status > 0 {
+				rated = append(rated, entry)
+				dayRated++
+			}
+		}
+		if dayRated == 0 {
+			continue
+		}
+		average := statusAverage(day.Entries)
+		dayValues = append(dayValues, average)
+		dailyAverages = append(dailyAverages, map[string]any{
+			"date":    day.Date.Format("2006-01-02"),
+			"average": average,
+			"entries": dayRated,
+		})
+	}
+
+	stats := map[string]any{
+		"period":        period,
+		"rated_entries": len(rated),
+	}
+	if len(rated) == 0 {
+		return fmt.Sprintf("No entries with a status from %s.", period), stats, nil
+	}
+
+	sort.SliceStable(rated, func(i, j int) bool { return rated[i].Status > rated[j].Status })
+	average := statusAverage(rated)
+	var highest, lowest []storage.DailyLogEntry
+	for i := 0; i < len(rated) && len(highest) < notableEntries && float64(rated[i].Status) > average; i++ {
+		highest = append(highest, rated[i])
+	}
+	for i := len(rated) - 1; i >= 0 && len(lowest) < notableEntries && float64(rated[i].Status) < average; i-- {
+		lowest = append(lowest, rated[i])
+	}
+
+	trend := statusTrend(dayValues)
+	stats["average"] = average
+	stats["min"] = rated[len(rated)-1].Status
+	stats["max"] = rated[0].Status
+	stats["trend"] = trend
+	stats["daily_averages"] = dailyAverages
+	stats["highest"] = notableStats(highest)
+	stats["lowest"] = notableStats(lowest)
+
+	var facts strings.Builder
+	fmt.Fprintf(&facts, "Status for %s: average %.1f across %d rated entries (low %d, high %d), %s over the period.",
+		period, average, len(rated), rated[len(rated)-1].Status, rated[0].Status, trend)
+	if len(highest) > 0 {
+		fmt.Fprintf(&facts, "\nHighest: %s.", describeEntries(highest))
+	}
+	if len(lowest) > 0 {
+		fmt.Fprintf(&facts, "\nLowest: %s.", describeEntries(lowest))
+	}
+	if correlations := storage.CorrelateMetricsWithStatus(all); len(correlations) > 0 {
+		stats["metric_correlations"] = correlations
+		for _, c := range correlations {
+			fmt.Fprintf(&facts, "\n%s and status: %s correlation (r = %.2f over %d days).",
+				strings.ToUpper(c.Metric[:1])+c.Metric[1:], c.Strength(), c.Correlation, c.Days)
+		}
+	}
+	if weather := storage.CorrelateWeatherWithStatus(days); weather != nil {
+		stats["weather"] = weather
+		conditions := make([]string, 0, len(weather.Conditions))
+		for _, c := range weather.Conditions {
+			conditions = append(conditions, fmt.Sprintf("%s %.1f (%d days)", c.Condition, c.AverageStatus, c.Days))
+		}
+		fmt.Fprintf(&facts, "\nAverage status by weather: %s.", strings.Join(conditions, ", "))
+		for _, c := range weather.Correlations {
+			fmt.Fprintf(&facts, "\nStatus and %s: %s correlation (r = %.2f over %d days).",
+				c.Metric, c.Strength(), c.Correlation, c.Days)
+		}
+	}
+
+	data := prompts.Data{Period: period, Entries: rated, Stats: stats, StatusAverage: average, Facts: facts.String()}
+	result, err := s.draftWithAI("analyze_status", data, facts.String(), noCache)
+	return result, stats, err
+}
+
+// statusTrend compares the average of the first and second half of the daily
+// averages, ignoring differences under half a point
+func statusTrend(dayValues []float64) string {
+	if len(dayValues) < 2 {
+		return "steady"
+	}
+	half := len(dayValues) / 2
+	first, second := 0.0, 0.0
+	for _, v := range dayValues[:half] {
+		first += v
+	}
+	for _, v := range dayValues[len(dayValues)-half:] {
+		second += v
+	}
+	switch diff := (second - first) / float64(half); {
+	case diff >= 0.5:
+		return "rising"
+	case diff <= -0.5:
+		return "falling"
+	default:
+		return "steady"
+	}
+}
+
+// notableStats lists entries for the structured stats
+func notableStats(entries []storage.DailyLogEntry) []map[string]any {
+	notable := make([]map[string]any, 0, len(entries))
+	for _, entry := range entries {
+		notable = append(notable, map[string]any{
+			"id":     entry.ID,
+			"date":   entry.Timestamp.Format("2006-01-02"),
+			"title":  entry.Title,
+			"status": entry.Status,
+		})
+	}
+	return notable
+}
+
+// describeEntries cites entries by title, status, and date
+func describeEntries(entries []storage.DailyLogEntry) string {
+	cited := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		cited = append(cited, fmt.Sprintf("%q (%d, %s)", entry.Title, entry.Status, entry.Timestamp.Format("2006-01-02")))
+	}
+	return strings.Join(cited, ", ")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// redactedValue replaces secrets in the audit log
+const redactedValue = "[redacted]"
+
+// secretKeyPattern matches argument names whose values are secrets
+var secretKeyPattern = regexp.MustCompile(`(?i)(token|secret|password|passwd|api_?key|authorization|credential)`)
+
+// secretValuePattern matches well-known token formats wherever they appear
+var secretValuePattern = regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|sk-[A-Za-z0-9_-]{20,}|xox[abpr]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16})\b|(?i:bearer\s+[A-Za-z0-9._~+/-]{10,}=*)`)
+
+// auditRecord is one line of the audit log: a tool call and its result
+type auditRecord struct {
+	Time       time.Time `json:"time"`
+	Client     string    `json:"client,omitempty"`
+	Tool       string    `json:"tool"`
+	Arguments  any       `json:"arguments,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Success    bool      `json:"success"`
+	ErrorCode  string    `json:"error_code,omitempty"`
+	Message    string    `json:"message,omitempty"`
+	Result     any       `json:"result,omitempty"`
+}
+
+// auditLog appends a JSON line for every tool call to a file
+type auditLog struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// openAuditLog opens the audit log at path for appending, creating it
+// readable only by its owner
+func openAuditLog(path string) (*auditLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{file: file}, nil
+}
+
+// Close closes the audit log
+func (a *auditLog) Close() error {
+	return a.file.Close()
+}
+
+// middleware records tool calls with their arguments and results, secrets redacted
+func (a *auditLog) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok {
+			return next(ctx, method, req)
+		}
+
+		started := time.Now()
+		result, err := next(ctx, method, req)
+		record := auditRecord{
+			Time:       started,
+			Client:     clientName(call.Session),
+			Tool:       call.Params.Name,
+			Arguments:  redactSecrets(decodeJSON(call.Params.Arguments)),
+			DurationMS: time.Since(started).Milliseconds(),
+		}
+		describeResult(&record, result, err)
+		a.write(record)
+		return result, err
+	}
+}
+
+func (a *auditLog) write(record auditRecord) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		line, _ = json.Marshal(auditRecord{Time: record.Time, Tool: record.Tool, Message: fmt.Sprintf("failed to encode audit record: %v", err)})
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write audit log: %v\n", err)
+	}
+}
+
+// describeResult fills in how a tool call turned out. Tools report failures
+// with success false and an error_code in their output.
+func describeResult(record *auditRecord, result mcp.Result, err error) {
+	if err != nil {
+		record.Message = err.Error()
+		return
+	}
+	toolResult, ok := result.(*mcp.CallToolResult)
+	if !ok || toolResult == nil {
+		return
+	}
+	if toolResult.IsError {
+		for _, content := range toolResult.Content {
+			if text, ok := content.(*mcp.TextContent); ok {
+				record.Message = text.Text
+				if code, _, found := strings.Cut(text.Text, ":"); found && (code == errorRateLimited || code == errorShuttingDown) {
+					record.ErrorCode = code
+				}
+			}
+		}
+		return
+	}
+
+	encoded, err := json.Marshal(toolResult.StructuredContent)
+	if err != nil {
+		record.Message = fmt.Sprintf("failed to encode result: %v", err)
+		return
+	}
+	output := redactSecrets(decodeJSON(encoded))
+	record.Result = output
+	record.Success = true
+	if fields, ok := output.(map[string]any); ok {
+		if success, ok := fields["success"].(bool); ok {
+			record.Success = success
+		}
+		record.ErrorCode, _ = fields["error_code"].(string)
+		record.Message, _ = fields["message"].(string)
+	}
+}
+
+// clientName names the client of a session, from what it said when connecting
+func clientName(session *mcp.ServerSession) string {
+	if session == nil {
+		return ""
+	}
+	params := session.InitializeParams()
+	if params == nil || params.ClientInfo == nil {
+		return ""
+	}
+	if params.ClientInfo.Version != "" {
+		return params.ClientInfo.Name + " " + params.ClientInfo.Version
+	}
+	return params.ClientInfo.Name
+}
+
+// decodeJSON decodes raw JSON into maps and slices, or returns nil
+func decodeJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+	return value
+}
+
+// redactSecrets replaces the values of secret-looking keys, and tokens found
+// in any string, with a placeholder
+func redactSecrets(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if secretKeyPattern.MatchString(key) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactSecrets(field)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactSecrets(item)
+		}
+	case string:
+		return secretValuePattern.ReplaceAllString(v, redactedValue)
+	}
+	return value
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/web"
+)
+
+// Transports the server can run over
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http"
+)
+
+// defaultHTTPAddr is where the HTTP transport listens by default: every
+// interface, as a container's published port needs
+const defaultHTTPAddr = ":8080"
+
+// runHTTP serves MCP over streamable HTTP at /mcp, beside /healthz, /readyz,
+// and /metrics, until ctx is done. With a token, /mcp requires it as a
+// bearer token.
+func runHTTP(ctx context.Context, server *mcp.Server, addr, token string, readiness *web.Readiness) error {
+	var handler http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	if token != "" {
+		handler = requireToken(token, handler)
+	} else {
+		slog.Warn("DAILYLOG_HTTP_TOKEN is not set: anyone who can reach the server can use it", "addr", addr)
+	}
+	mux := opsMux(readiness)
+	mux.Handle("/mcp", handler)
+
+	httpServer := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	slog.Info("Serving MCP over HTTP", "addr", addr, "path", "/mcp")
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return httpServer.Shutdown(shutdownCtx)
+}
+
+// requireToken refuses requests without the bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="dailylog"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
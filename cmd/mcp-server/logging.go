@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// contentFields are the tool arguments that hold journal text, left out of
+// tool call logs unless debug logging is on
+var contentFields = map[string]bool{
+	"title":        true,
+	"description":  true,
+	"text":         true,
+	"prompt":       true,
+	"search_text":  true,
+	"location":     true,
+	"attendees":    true,
+	"decisions":    true,
+	"action_items": true,
+	"metadata":     true,
+	"value":        true,
+}
+
+// newLogger creates the server's logger, writing to w at level (debug, info,
+// warn, or error; info by default) in format (text, the default, or json)
+func newLogger(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q (use debug, info, warn, or error)", level)
+		}
+	}
+	options := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	}
+	return nil, fmt.Errorf("invalid log format %q (use text or json)", format)
+}
+
+// logToolCall logs a tool call with its input. Journal text in the input is
+// only logged at debug level; otherwise its size stands in for it.
+func logToolCall(handler string, input any) {
+	ctx := context.Background()
+	if !slog.Default().Enabled(ctx, slog.LevelInfo) {
+		return
+	}
+	var fields map[string]any
+	if encoded, err := json.Marshal(input); err == nil {
+		_ = json.Unmarshal(encoded, &fields)
+	}
+	if !slog.Default().Enabled(ctx, slog.LevelDebug) {
+		for key, value := range fields {
+			if contentFields[key] {
+				fields[key] = redactedSize(value)
+			}
+		}
+	}
+	slog.Info("Tool called", "handler", handler, "input", redactSecrets(fields))
+}
+
+// redactedSize describes a redacted value by its size
+func redactedSize(value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%s (%d chars)", redactedValue, len([]rune(v)))
+	case []any:
+		return fmt.Sprintf("%s (%d items)", redactedValue, len(v))
+	case map[string]any:
+		return fmt.Sprintf("%s (%d keys)", redactedValue, len(v))
+	}
+	return redactedValue
+}
+
+// fatalf logs an error and exits
+func fatalf(format string, args ...any) {
+	slog.Error(fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
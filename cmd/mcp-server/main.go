@@ -2,16 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/viper"
 
+	"dailylog/internal/ai"
+	"dailylog/internal/config"
+	"dailylog/internal/prompts"
 	"dailylog/internal/providers"
+	"dailylog/internal/sentiment"
 	"dailylog/internal/storage"
+	"dailylog/internal/weather"
+	"dailylog/internal/web"
 )
 
 // Version information (set by build)
@@ -25,14 +38,83 @@ var (
 // Server holds our daily log implementation
 type Server struct {
 	storage storage.DailyLogStorage
+
+	// ai, if set, writes AI summaries and the analyze_status,
+	// generate_insights, weekly_retro, gratitude_prompt, and tomorrow_plan
+	// results; without it they are drafted from the log alone
+	ai storage.AIProvider
+
+	// sampling has clients that support it write those results with their
+	// own model instead of ai (see forRequest)
+	sampling bool
+
+	// prompts renders the prompt templates given to ai
+	prompts *prompts.Library
+
+	// tokenBudget caps the estimated tokens of each request to ai
+	tokenBudget int
+
+	// verbose logs progress through long-running requests
+	verbose bool
+
+	// aiCacheDir, if set, caches ai results there, keyed on aiModel, the
+	// prompt, and the entries
+	aiCacheDir string
+	aiModel    string
+
+	// redactor, if set, scrubs everything sent to ai
+	redactor *ai.Redactor
+
+	// sentiment, if set, tags new entries of sentimentTypes with their
+	// sentiment; with sentimentAI, clients that support sampling have their
+	// model analyze it instead (see forRequest)
+	sentiment      sentiment.Analyzer
+	sentimentTypes []string
+	sentimentAI    bool
+
+	// weather, if set, records the weather of days as entries are logged on them
+	weather         weather.Source
+	weatherLocation string
+
+	// issues link new entries to the issues their titles mention
+	issues storage.IssueTrackers
+
+	// duplicates is how LogEntry handles duplicates of logged entries by
+	// default: allow, warn, or skip, matching within duplicateWindow
+	duplicates      string
+	duplicateWindow time.Duration
+
+	// commitMessages renders the commit messages dry runs preview
+	commitMessages *storage.CommitMessages
+
+	// hooks run around entry writes; dry runs run their before functions
+	hooks []providers.Hook
+
+	// focus classifies entries as meetings and breaks for wellbeing checks
+	focus storage.FocusRules
+	tags  storage.TagAliases
 }
 
+// Error codes reported in the error_code field of failed tool calls
+const (
+	errorInvalidDate        = "invalid_date"
+	errorNotFound           = "not_found"
+	errorReadOnly           = "read_only"
+	errorRateLimited        = "rate_limited"
+	errorShuttingDown       = "shutting_down"
+	errorStorageUnavailable = "storage_unavailable"
+	errorValidation         = "validation"
+)
+
+// errInvalidDate marks errors caused by an unparseable date argument
+var errInvalidDate = errors.New("Invalid date format")
+
 // === MCP INPUT/OUTPUT TYPES ===
 
 // LogEntryInput defines parameters for creating a log entry
 type LogEntryInput struct {
 	Date        string            `json:"date,omitempty" jsonschema:"Date in YYYY-MM-DD format (defaults to today)"`
-	Type        string            `json:"type" jsonschema:"Entry type: activity, status, note, summary"`
+	Type        string            `json:"type" jsonschema:"Entry type: activity, status, note, summary, meeting, metric, lesson (a lesson learned)"`
 	Title       string            `json:"title" jsonschema:"Entry title"`
 	Description string            `json:"description" jsonschema:"Entry description"`
 	Tags        []string          `json:"tags,omitempty" jsonschema:"Tags for categorization"`
@@ -40,25 +122,78 @@ type LogEntryInput struct {
 	Priority    *int              `json:"priority,omitempty" jsonschema:"Priority 1-5"`
 	Duration    *int              `json:"duration,omitempty" jsonschema:"Duration in minutes"`
 	Location    string            `json:"location,omitempty" jsonschema:"Location"`
+	Visibility  string            `json:"visibility,omitempty" jsonschema:"Visibility: private, team, public (defaults to team)"`
 	Metadata    map[string]string `json:"metadata,omitempty" jsonschema:"Additional metadata"`
+	Attendees   []string          `json:"attendees,omitempty" jsonschema:"Meeting attendees (meeting entries)"`
+	Decisions   []string          `json:"decisions,omitempty" jsonschema:"Decisions made (meeting entries)"`
+	ActionItems []string          `json:"action_items,omitempty" jsonschema:"Action items, surfaced as open tasks in the next day's plan (meeting entries)"`
+	Metric      string            `json:"metric,omitempty" jsonschema:"Metric name such as sleep, steps, or weight (metric entries)"`
+	Value       string            `json:"value,omitempty" jsonschema:"Metric value with an optional unit, e.g. 7.5h, 9400, or 181lb; sleep is kept in hours and weight in kg (metric entries)"`
+	DryRun      bool              `json:"dry_run,omitempty" jsonschema:"Return the entry that would be created and its commit message without saving anything"`
+	Duplicates  string            `json:"duplicates,omitempty" jsonschema:"What to do if the entry duplicates one already logged (same title and type close in time, or same external_id metadata): allow, warn, or skip to return the existing entry instead; defaults to the server setting"`
 }
 
 // LogEntryOutput defines the response for log entry operations
 type LogEntryOutput struct {
-	ID          string            `json:"id" jsonschema:"Entry ID"`
-	Date        string            `json:"date" jsonschema:"Entry date"`
-	Timestamp   string            `json:"timestamp" jsonschema:"Entry timestamp"`
-	Type        string            `json:"type" jsonschema:"Entry type"`
-	Title       string            `json:"title" jsonschema:"Entry title"`
-	Description string            `json:"description" jsonschema:"Entry description"`
-	Tags        []string          `json:"tags,omitempty" jsonschema:"Entry tags"`
-	Status      int               `json:"status,omitempty" jsonschema:"Status rating"`
-	Priority    int               `json:"priority,omitempty" jsonschema:"Priority"`
-	Duration    *int              `json:"duration,omitempty" jsonschema:"Duration in minutes"`
-	Location    string            `json:"location,omitempty" jsonschema:"Location"`
-	Metadata    map[string]string `json:"metadata,omitempty" jsonschema:"Metadata"`
-	Success     bool              `json:"success" jsonschema:"Whether operation was successful"`
-	Message     string            `json:"message,omitempty" jsonschema:"Success or error message"`
+	ID            string            `json:"id" jsonschema:"Entry ID"`
+	Date          string            `json:"date" jsonschema:"Entry date"`
+	Timestamp     string            `json:"timestamp" jsonschema:"Entry timestamp"`
+	Type          string            `json:"type" jsonschema:"Entry type"`
+	Title         string            `json:"title" jsonschema:"Entry title"`
+	Description   string            `json:"description" jsonschema:"Entry description"`
+	Tags          []string          `json:"tags,omitempty" jsonschema:"Entry tags"`
+	Status        int               `json:"status,omitempty" jsonschema:"Status rating"`
+	Priority      int               `json:"priority,omitempty" jsonschema:"Priority"`
+	Duration      *int              `json:"duration,omitempty" jsonschema:"Duration in minutes"`
+	Location      string            `json:"location,omitempty" jsonschema:"Location"`
+	Visibility    string            `json:"visibility,omitempty" jsonschema:"Visibility"`
+	Pinned        bool              `json:"pinned,omitempty" jsonschema:"Whether the entry is pinned as a highlight"`
+	ReviewedAt    string            `json:"reviewed_at,omitempty" jsonschema:"When the entry was marked reviewed, if it has been"`
+	Author        string            `json:"author,omitempty" jsonschema:"Who logged the entry, in a log shared by a team"`
+	Metadata      map[string]string `json:"metadata,omitempty" jsonschema:"Metadata"`
+	DryRun        bool              `json:"dry_run,omitempty" jsonschema:"Whether this is a preview and nothing was saved"`
+	CommitMessage string            `json:"commit_message,omitempty" jsonschema:"Commit message the write would be saved with, one per line when several days change (dry runs only)"`
+	Duplicate     bool              `json:"duplicate,omitempty" jsonschema:"Whether nothing was created because the entry duplicates the existing entry returned"`
+	DuplicateOf   string            `json:"duplicate_of,omitempty" jsonschema:"ID of an existing entry the new entry looks like a duplicate of (warn mode)"`
+	Success       bool              `json:"success" jsonschema:"Whether operation was successful"`
+	Message       string            `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode     string            `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
+}
+
+// GetEntryInput defines parameters for retrieving a single log entry
+type GetEntryInput struct {
+	ID   string `json:"id" jsonschema:"Entry ID or an unambiguous prefix of it, e.g. 01J3F2QK"`
+	Date string `json:"date,omitempty" jsonschema:"Day of the entry in YYYY-MM-DD format; needed only for older entry_<number> IDs logged for a different day than they were created"`
+}
+
+// MoveEntryInput defines parameters for moving an entry to another day
+type MoveEntryInput struct {
+	ID     string `json:"id" jsonschema:"Entry ID or an unambiguous prefix of it, e.g. 01J3F2QK"`
+	From   string `json:"from,omitempty" jsonschema:"Day the entry is logged on in YYYY-MM-DD format; needed only when the ID alone does not find it"`
+	To     string `json:"to" jsonschema:"Day to move the entry to in YYYY-MM-DD format"`
+	Copy   bool   `json:"copy,omitempty" jsonschema:"Copy the entry, with a new ID, instead of moving it"`
+	DryRun bool   `json:"dry_run,omitempty" jsonschema:"Return the entry as it would be moved and the commit messages without saving anything"`
+}
+
+// GetDayInput defines parameters for retrieving a whole day's log
+type GetDayInput struct {
+	Date string `json:"date,omitempty" jsonschema:"Date in YYYY-MM-DD format (defaults to today)"`
+	Sort string `json:"sort,omitempty" jsonschema:"Sort by time, status (or mood), or priority, optionally with :asc or :desc, e.g. status:desc; defaults to time, oldest first"`
+}
+
+// GetDayOutput defines the response for getting a day's log
+type GetDayOutput struct {
+	Date          string           `json:"date" jsonschema:"Date of the log"`
+	Entries       []LogEntryOutput `json:"entries" jsonschema:"Log entries for the day"`
+	DaySummary    string           `json:"day_summary,omitempty" jsonschema:"Summary written for the day"`
+	StatusAverage float64          `json:"status_average,omitempty" jsonschema:"Average status of the day's entries"`
+	TotalEntries  int              `json:"total_entries" jsonschema:"Number of entries"`
+	CreatedAt     string           `json:"created_at,omitempty" jsonschema:"When the day's log was created"`
+	UpdatedAt     string           `json:"updated_at,omitempty" jsonschema:"When the day's log was last updated"`
+	Metadata      map[string]any   `json:"metadata,omitempty" jsonschema:"Day-level metadata"`
+	Success       bool             `json:"success" jsonschema:"Whether operation was successful"`
+	Message       string           `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode     string           `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
 }
 
 // GetEntriesInput defines parameters for retrieving log entries
@@ -70,6 +205,8 @@ type GetEntriesInput struct {
 	Tags         []string `json:"tags,omitempty" jsonschema:"Filter by tags"`
 	Limit        int      `json:"limit,omitempty" jsonschema:"Maximum number of entries to return"`
 	IncludeStats bool     `json:"include_stats,omitempty" jsonschema:"Include summary statistics"`
+	Sort         string   `json:"sort,omitempty" jsonschema:"Sort by time, status (or mood), or priority, optionally with :asc or :desc, e.g. status:desc; defaults to time, oldest first"`
+	Author       string   `json:"author,omitempty" jsonschema:"Only entries by this author, in a log shared by a team"`
 }
 
 // GetEntriesOutput defines the response for getting entries
@@ -80,64 +217,100 @@ type GetEntriesOutput struct {
 	Period     string           `json:"period,omitempty" jsonschema:"Time period covered"`
 	Success    bool             `json:"success" jsonschema:"Whether operation was successful"`
 	Message    string           `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode  string           `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
 }
 
 // SearchLogsInput defines parameters for searching logs
 type SearchLogsInput struct {
-	Query     string   `json:"query,omitempty" jsonschema:"Search text in titles and descriptions"`
-	DateStart string   `json:"date_start,omitempty" jsonschema:"Start date for search range"`
-	DateEnd   string   `json:"date_end,omitempty" jsonschema:"End date for search range"`
-	Type      string   `json:"type,omitempty" jsonschema:"Filter by entry type"`
-	Tags      []string `json:"tags,omitempty" jsonschema:"Filter by tags"`
-	StatusMin *int     `json:"status_min,omitempty" jsonschema:"Minimum status rating"`
-	StatusMax *int     `json:"status_max,omitempty" jsonschema:"Maximum status rating"`
-	Limit     int      `json:"limit,omitempty" jsonschema:"Maximum number of results"`
+	Query        string   `json:"query,omitempty" jsonschema:"Search text in titles and descriptions"`
+	DateStart    string   `json:"date_start,omitempty" jsonschema:"Start date for search range"`
+	DateEnd      string   `json:"date_end,omitempty" jsonschema:"End date for search range"`
+	Type         string   `json:"type,omitempty" jsonschema:"Filter by entry type"`
+	Tags         []string `json:"tags,omitempty" jsonschema:"Filter by tags"`
+	Location     string   `json:"location,omitempty" jsonschema:"Filter by location (known aliases of the place also match)"`
+	StatusMin    *int     `json:"status_min,omitempty" jsonschema:"Minimum status rating"`
+	StatusMax    *int     `json:"status_max,omitempty" jsonschema:"Maximum status rating"`
+	Pinned       bool     `json:"pinned,omitempty" jsonschema:"Only pinned entries, the highlights worth revisiting"`
+	Unreviewed   bool     `json:"unreviewed,omitempty" jsonschema:"Only entries not yet marked reviewed"`
+	Author       string   `json:"author,omitempty" jsonschema:"Only entries by this author, in a log shared by a team"`
+	Limit        int      `json:"limit,omitempty" jsonschema:"Maximum number of results"`
+	Aggregations []string `json:"aggregations,omitempty" jsonschema:"Roll up all matches by: tag, type, day, week, project, location (counts, total minutes, average status and priority)"`
+	Sort         string   `json:"sort,omitempty" jsonschema:"Sort by time, status (or mood), or priority, optionally with :asc or :desc, e.g. status:desc; defaults to time, oldest first"`
 }
 
 // SearchLogsOutput defines the response for searching logs
 type SearchLogsOutput struct {
-	Entries     []LogEntryOutput `json:"entries" jsonschema:"Matching log entries"`
-	TotalCount  int              `json:"total_count" jsonschema:"Total number of matches"`
-	SearchQuery string           `json:"search_query,omitempty" jsonschema:"The search query used"`
-	Success     bool             `json:"success" jsonschema:"Whether operation was successful"`
-	Message     string           `json:"message,omitempty" jsonschema:"Success or error message"`
+	Entries      []LogEntryOutput           `json:"entries" jsonschema:"Matching log entries"`
+	TotalCount   int                        `json:"total_count" jsonschema:"Total number of matches"`
+	SearchQuery  string                     `json:"search_query,omitempty" jsonschema:"The search query used"`
+	Aggregations []storage.Aggregation      `json:"aggregations,omitempty" jsonschema:"Rollups of all matches, one per requested group-by key"`
+	Totals       *storage.AggregationBucket `json:"totals,omitempty" jsonschema:"Rollup of all matches when aggregations are requested"`
+	Success      bool                       `json:"success" jsonschema:"Whether operation was successful"`
+	Message      string                     `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode    string                     `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
 }
 
 // SummarizePeriodInput defines parameters for generating summaries
 type SummarizePeriodInput struct {
-	Type      string `json:"type" jsonschema:"Summary type: day, week, month"`
-	Date      string `json:"date,omitempty" jsonschema:"Date for summary (defaults to today)"`
-	DateStart string `json:"date_start,omitempty" jsonschema:"Start date for custom range"`
-	DateEnd   string `json:"date_end,omitempty" jsonschema:"End date for custom range"`
-	UseAI     bool   `json:"use_ai,omitempty" jsonschema:"Use AI for enhanced summary generation"`
-	Prompt    string `json:"prompt,omitempty" jsonschema:"Custom prompt for AI summary"`
+	Type      string   `json:"type" jsonschema:"Summary type: day, week, month"`
+	Date      string   `json:"date,omitempty" jsonschema:"Date for summary (defaults to today)"`
+	DateStart string   `json:"date_start,omitempty" jsonschema:"Start date for custom range"`
+	DateEnd   string   `json:"date_end,omitempty" jsonschema:"End date for custom range"`
+	UseAI     bool     `json:"use_ai,omitempty" jsonschema:"Use AI for enhanced summary generation"`
+	Prompt    string   `json:"prompt,omitempty" jsonschema:"Custom prompt for AI summary"`
+	NoCache   bool     `json:"no_cache,omitempty" jsonschema:"Regenerate the AI summary even if a cached one matches"`
+	Audience  string   `json:"audience,omitempty" jsonschema:"Only summarize entries visible to: private (default), team, public"`
+	Tags      []string `json:"tags,omitempty" jsonschema:"Only summarize entries with these tags; a parent tag like work includes work/projectx"`
 }
 
 // SummarizePeriodOutput defines the response for summary generation
 type SummarizePeriodOutput struct {
-	Summary   string         `json:"summary" jsonschema:"Generated summary"`
-	Type      string         `json:"type" jsonschema:"Summary type"`
-	Period    string         `json:"period" jsonschema:"Time period summarized"`
-	Stats     map[string]any `json:"stats" jsonschema:"Statistical information"`
-	Timestamp string         `json:"timestamp" jsonschema:"When summary was generated"`
+	Summary   string           `json:"summary" jsonschema:"Generated summary"`
+	Type      string           `json:"type" jsonschema:"Summary type"`
+	Period    string           `json:"period" jsonschema:"Time period summarized"`
+	Stats     map[string]any   `json:"stats" jsonschema:"Statistical information"`
+	Pinned    []LogEntryOutput `json:"pinned,omitempty" jsonschema:"Pinned entries of a week or month, its highlights"`
+	Timestamp string           `json:"timestamp" jsonschema:"When summary was generated"`
+	Success   bool             `json:"success" jsonschema:"Whether operation was successful"`
+	Message   string           `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode string           `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
+}
+
+// GetStatsInput defines parameters for retrieving statistics
+type GetStatsInput struct {
+	Period    string `json:"period,omitempty" jsonschema:"Relative period: today, week, month, last_week, last_month (defaults to week)"`
+	DateStart string `json:"date_start,omitempty" jsonschema:"Start date in YYYY-MM-DD format (overrides period)"`
+	DateEnd   string `json:"date_end,omitempty" jsonschema:"End date in YYYY-MM-DD format (defaults to today)"`
+}
+
+// GetStatsOutput defines the response for statistics
+type GetStatsOutput struct {
+	Stats     map[string]any `json:"stats" jsonschema:"Entry counts, average status, and durations in minutes by type and tag"`
+	Period    string         `json:"period" jsonschema:"Time period covered"`
 	Success   bool           `json:"success" jsonschema:"Whether operation was successful"`
 	Message   string         `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode string         `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
 }
 
 // AIAssistInput defines parameters for AI assistance features
 type AIAssistInput struct {
-	Action string `json:"action" jsonschema:"AI action: improve_wording, suggest_tags, analyze_status, generate_insights"`
-	Text   string `json:"text,omitempty" jsonschema:"Text to improve or analyze"`
-	Date   string `json:"date,omitempty" jsonschema:"Date for context (for analysis actions)"`
+	Action    string `json:"action" jsonschema:"AI action: improve_wording, suggest_tags, analyze_status, generate_insights, weekly_retro, gratitude_prompt, tomorrow_plan"`
+	Text      string `json:"text,omitempty" jsonschema:"Text to improve or analyze"`
+	Date      string `json:"date,omitempty" jsonschema:"Date for context in YYYY-MM-DD format (defaults to today): the last day analyze_status covers, any day of the week for weekly_retro, or the day before the one tomorrow_plan plans"`
+	DateStart string `json:"date_start,omitempty" jsonschema:"Start date in YYYY-MM-DD format for analyze_status (defaults to the week ending on date)"`
+	DateEnd   string `json:"date_end,omitempty" jsonschema:"End date in YYYY-MM-DD format for analyze_status"`
+	NoCache   bool   `json:"no_cache,omitempty" jsonschema:"Regenerate AI results even if cached ones match"`
 }
 
 // AIAssistOutput defines the response for AI assistance
 type AIAssistOutput struct {
-	Result      string   `json:"result" jsonschema:"AI-generated result"`
-	Action      string   `json:"action" jsonschema:"Action performed"`
-	Suggestions []string `json:"suggestions,omitempty" jsonschema:"Additional suggestions"`
-	Success     bool     `json:"success" jsonschema:"Whether operation was successful"`
-	Message     string   `json:"message,omitempty" jsonschema:"Success or error message"`
+	Result      string         `json:"result" jsonschema:"AI-generated result"`
+	Action      string         `json:"action" jsonschema:"Action performed"`
+	Suggestions []string       `json:"suggestions,omitempty" jsonschema:"Additional suggestions"`
+	Stats       map[string]any `json:"stats,omitempty" jsonschema:"Statistics the result is based on (analyze_status)"`
+	Success     bool           `json:"success" jsonschema:"Whether operation was successful"`
+	Message     string         `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode   string         `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
 }
 
 // === TOOL IMPLEMENTATIONS ===
@@ -148,7 +321,8 @@ func (s *Server) LogEntry(ctx context.Context, req *mcp.CallToolRequest, input L
 	LogEntryOutput,
 	error,
 ) {
-	log.Printf("LogEntry called with input: %+v", input)
+	logToolCall("LogEntry", input)
+	s = s.forRequest(ctx, req)
 
 	// Parse date
 	var entryDate time.Time
@@ -157,8 +331,9 @@ func (s *Server) LogEntry(ctx context.Context, req *mcp.CallToolRequest, input L
 		entryDate, err = time.Parse("2006-01-02", input.Date)
 		if err != nil {
 			return nil, LogEntryOutput{
-				Success: false,
-				Message: fmt.Sprintf("Invalid date format: %s", input.Date),
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 	} else {
@@ -168,14 +343,51 @@ func (s *Server) LogEntry(ctx context.Context, req *mcp.CallToolRequest, input L
 	// Validate required fields
 	if input.Type == "" {
 		return nil, LogEntryOutput{
-			Success: false,
-			Message: "Entry type is required",
+			Success:   false,
+			Message:   "Entry type is required",
+			ErrorCode: errorValidation,
 		}, nil
 	}
+	var metric storage.Metric
+	if input.Type == storage.EntryTypeMetric {
+		var err error
+		metric, err = storage.ParseMetric(input.Metric, input.Value)
+		if err != nil {
+			return nil, LogEntryOutput{
+				Success:   false,
+				Message:   err.Error(),
+				ErrorCode: errorCode(err),
+			}, nil
+		}
+		if input.Title == "" {
+			input.Title = metric.Title()
+		}
+	}
 	if input.Title == "" {
 		return nil, LogEntryOutput{
-			Success: false,
-			Message: "Entry title is required",
+			Success:   false,
+			Message:   "Entry title is required",
+			ErrorCode: errorValidation,
+		}, nil
+	}
+
+	if err := storage.ValidateVisibility(input.Visibility); err != nil {
+		return nil, LogEntryOutput{
+			Success:   false,
+			Message:   err.Error(),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	duplicates := s.duplicates
+	if input.Duplicates != "" {
+		duplicates = input.Duplicates
+	}
+	if err := providers.ValidateDuplicateMode(duplicates); err != nil {
+		return nil, LogEntryOutput{
+			Success:   false,
+			Message:   err.Error(),
+			ErrorCode: errorCode(err),
 		}, nil
 	}
 
@@ -190,20 +402,227 @@ func (s *Server) LogEntry(ctx context.Context, req *mcp.CallToolRequest, input L
 		Priority:    input.Priority,
 		Duration:    input.Duration,
 		Location:    input.Location,
+		Visibility:  input.Visibility,
 		Metadata:    input.Metadata,
 	}
 
-	entry, err := s.storage.CreateEntry(createReq)
+	meeting := storage.Meeting{Attendees: input.Attendees, Decisions: input.Decisions, ActionItems: input.ActionItems}
+	if !meeting.IsEmpty() {
+		createReq.Metadata = meeting.Metadata(createReq.Metadata)
+	}
+	if input.Type == storage.EntryTypeMetric {
+		createReq.Metadata = metric.Metadata(createReq.Metadata)
+	}
+
+	store := s.storage
+	var preview *providers.DryRunProvider
+	if input.DryRun {
+		store, preview = s.preview()
+	}
+	if len(s.issues) > 0 {
+		store = providers.NewIssueLinkProvider(store, s.issues)
+	}
+	if s.weather != nil {
+		recorder := providers.NewWeatherProvider(store, s.weather, s.weatherLocation)
+		recorder.OnError = func(err error) {
+			slog.Warn("Failed to record the weather", "error", err)
+		}
+		store = recorder
+	}
+	if s.sentiment != nil {
+		tagger := providers.NewSentimentProvider(store, s.sentiment, s.sentimentTypes)
+		tagger.OnError = func(err error) {
+			slog.Warn("Sentiment analysis failed", "error", err)
+		}
+		store = tagger
+	}
+	var duplicateOf string
+	if duplicates == providers.DuplicatesWarn || duplicates == providers.DuplicatesSkip {
+		checker := providers.NewDuplicateProvider(store, duplicates == providers.DuplicatesSkip, s.duplicateWindow)
+		checker.OnDuplicate = func(req storage.CreateLogEntryRequest, existing storage.DailyLogEntry) {
+			duplicateOf = existing.ID
+		}
+		store = checker
+	}
+
+	entry, err := store.CreateEntry(createReq)
+	var duplicate storage.DuplicateError
+	if errors.As(err, &duplicate) {
+		result := logEntryOutput(&duplicate.Existing)
+		result.Duplicate = true
+		result.Message = fmt.Sprintf("Entry '%s' was already logged as %s; nothing was created", duplicate.Existing.Title, duplicate.Existing.ID)
+		return nil, result, nil
+	}
 	if err != nil {
 		return nil, LogEntryOutput{
-			Success: false,
-			Message: fmt.Sprintf("Failed to create entry: %v", err),
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to create entry: %v", err),
+			ErrorCode: errorCode(err),
 		}, nil
 	}
 
-	result := LogEntryOutput{
+	result := logEntryOutput(entry)
+	result.Message = fmt.Sprintf("Entry '%s' created successfully", entry.Title)
+	if duplicateOf != "" {
+		result.DuplicateOf = duplicateOf
+		result.Message += fmt.Sprintf(" (it looks like a duplicate of %s)", duplicateOf)
+	}
+	if preview != nil {
+		result.DryRun = true
+		if writes := preview.Writes(); len(writes) > 0 {
+			result.CommitMessage = writes[len(writes)-1].CommitMessage
+		}
+		result.Message = fmt.Sprintf("Entry '%s' would be created (dry run, nothing was saved)", entry.Title)
+	}
+
+	return nil, result, nil
+}
+
+// GetEntry implements the dailylog_get_entry tool
+func (s *Server) GetEntry(ctx context.Context, req *mcp.CallToolRequest, input GetEntryInput) (
+	*mcp.CallToolResult,
+	LogEntryOutput,
+	error,
+) {
+	logToolCall("GetEntry", input)
+	s = s.forRequest(ctx, req)
+
+	var entry *storage.DailyLogEntry
+	var err error
+	if input.Date != "" {
+		date, parseErr := time.Parse("2006-01-02", input.Date)
+		if parseErr != nil {
+			return nil, LogEntryOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
+			}, nil
+		}
+		dayLog, dayErr := s.storage.GetDay(date)
+		if dayErr != nil {
+			return nil, LogEntryOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to get day: %v", dayErr),
+				ErrorCode: errorCode(dayErr),
+			}, nil
+		}
+		entry, err = storage.ResolveEntryID(input.ID, dayLog.Entries)
+	} else {
+		entry, err = storage.FindEntry(s.storage, input.ID)
+	}
+	if err != nil {
+		return nil, LogEntryOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to get entry: %v", err),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	result := logEntryOutput(entry)
+	result.Message = fmt.Sprintf("Found entry '%s'", entry.Title)
+	return nil, result, nil
+}
+
+// MoveEntry implements the dailylog_move_entry tool
+func (s *Server) MoveEntry(ctx context.Context, req *mcp.CallToolRequest, input MoveEntryInput) (
+	*mcp.CallToolResult,
+	LogEntryOutput,
+	error,
+) {
+	logToolCall("MoveEntry", input)
+	s = s.forRequest(ctx, req)
+
+	store := s.storage
+	var preview *providers.DryRunProvider
+	if input.DryRun {
+		store, preview = s.preview()
+	}
+
+	to, err := time.Parse("2006-01-02", input.To)
+	if err != nil {
+		return nil, LogEntryOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Invalid date format: %s", input.To),
+			ErrorCode: errorInvalidDate,
+		}, nil
+	}
+
+	id := input.ID
+	var from time.Time
+	if input.From != "" {
+		if from, err = time.Parse("2006-01-02", input.From); err != nil {
+			return nil, LogEntryOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.From),
+				ErrorCode: errorInvalidDate,
+			}, nil
+		}
+	} else {
+		entry, err := storage.FindEntry(store, input.ID)
+		if err != nil {
+			return nil, LogEntryOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to find entry: %v", err),
+				ErrorCode: errorCode(err),
+			}, nil
+		}
+		id, from = entry.ID, entry.Timestamp
+	}
+
+	relocate, verb := storage.MoveEntry, "Moved"
+	if input.Copy {
+		relocate, verb = storage.CopyEntry, "Copied"
+	}
+	entry, err := relocate(store, id, from, to)
+	if err != nil {
+		return nil, LogEntryOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to move entry: %v", err),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	result := logEntryOutput(entry)
+	result.Message = fmt.Sprintf("%s '%s' from %s to %s", verb, entry.Title, from.Format("2006-01-02"), input.To)
+	if preview != nil {
+		result.DryRun = true
+		messages := make([]string, 0, len(preview.Writes()))
+		for _, write := range preview.Writes() {
+			messages = append(messages, write.CommitMessage)
+		}
+		result.CommitMessage = strings.Join(messages, "\n")
+		result.Message = fmt.Sprintf("Entry '%s' would be %s from %s to %s (dry run, nothing was saved)",
+			entry.Title, strings.ToLower(verb), from.Format("2006-01-02"), input.To)
+	}
+	return nil, result, nil
+}
+
+// errorCode maps an error onto the error code reported to clients. Anything
+// not recognized as bad input is treated as the storage backend failing.
+func errorCode(err error) string {
+	var notFound storage.NotFoundError
+	var invalid storage.ValidationError
+	switch {
+	case errors.Is(err, errInvalidDate):
+		return errorInvalidDate
+	case errors.As(err, &notFound):
+		return errorNotFound
+	case errors.As(err, &invalid):
+		return errorValidation
+	case errors.Is(err, storage.ErrReadOnly):
+		return errorReadOnly
+	case providers.IsRateLimited(err):
+		return errorRateLimited
+	default:
+		return errorStorageUnavailable
+	}
+}
+
+// logEntryOutput converts an entry to the tool response format
+func logEntryOutput(entry *storage.DailyLogEntry) LogEntryOutput {
+	output := LogEntryOutput{
 		ID:          entry.ID,
-		Date:        entryDate.Format("2006-01-02"),
+		Date:        entry.Timestamp.Format("2006-01-02"),
 		Timestamp:   entry.Timestamp.Format("2006-01-02T15:04:05Z07:00"),
 		Type:        entry.Type,
 		Title:       entry.Title,
@@ -213,9 +632,75 @@ func (s *Server) LogEntry(ctx context.Context, req *mcp.CallToolRequest, input L
 		Priority:    entry.Priority,
 		Duration:    entry.Duration,
 		Location:    entry.Location,
+		Visibility:  entry.Visibility,
+		Pinned:      entry.Pinned,
+		Author:      entry.Author,
 		Metadata:    entry.Metadata,
 		Success:     true,
-		Message:     fmt.Sprintf("Entry '%s' created successfully", entry.Title),
+	}
+	if entry.Reviewed() {
+		output.ReviewedAt = entry.ReviewedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	return output
+}
+
+// GetDay implements the dailylog_get_day tool
+func (s *Server) GetDay(ctx context.Context, req *mcp.CallToolRequest, input GetDayInput) (
+	*mcp.CallToolResult,
+	GetDayOutput,
+	error,
+) {
+	logToolCall("GetDay", input)
+	s = s.forRequest(ctx, req)
+
+	order, err := storage.ParseSortOrder(input.Sort)
+	if err != nil {
+		return nil, GetDayOutput{Success: false, Message: err.Error(), ErrorCode: errorValidation}, nil
+	}
+
+	date := time.Now()
+	if input.Date != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return nil, GetDayOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
+			}, nil
+		}
+	}
+
+	dayLog, err := s.storage.GetDay(date)
+	if err != nil {
+		return nil, GetDayOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to get day: %v", err),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	storage.SortEntries(dayLog.Entries, order)
+	entries := make([]LogEntryOutput, 0, len(dayLog.Entries))
+	for i := range dayLog.Entries {
+		entries = append(entries, logEntryOutput(&dayLog.Entries[i]))
+	}
+
+	result := GetDayOutput{
+		Date:          date.Format("2006-01-02"),
+		Entries:       entries,
+		DaySummary:    dayLog.DaySummary,
+		StatusAverage: dayLog.StatusAverage,
+		TotalEntries:  len(entries),
+		Metadata:      dayLog.Metadata,
+		Success:       true,
+		Message:       fmt.Sprintf("Found %d entries for %s", len(entries), date.Format("2006-01-02")),
+	}
+	if !dayLog.CreatedAt.IsZero() {
+		result.CreatedAt = dayLog.CreatedAt.Format("2006-01-02T15:04:05Z07:00")
+	}
+	if !dayLog.UpdatedAt.IsZero() {
+		result.UpdatedAt = dayLog.UpdatedAt.Format("2006-01-02T15:04:05Z07:00")
 	}
 
 	return nil, result, nil
@@ -227,7 +712,13 @@ func (s *Server) GetEntries(ctx context.Context, req *mcp.CallToolRequest, input
 	GetEntriesOutput,
 	error,
 ) {
-	log.Printf("GetEntries called with input: %+v", input)
+	logToolCall("GetEntries", input)
+	s = s.forRequest(ctx, req)
+
+	order, err := storage.ParseSortOrder(input.Sort)
+	if err != nil {
+		return nil, GetEntriesOutput{Success: false, Message: err.Error(), ErrorCode: errorValidation}, nil
+	}
 
 	var entries []storage.DailyLogEntry
 	var period string
@@ -237,16 +728,18 @@ func (s *Server) GetEntries(ctx context.Context, req *mcp.CallToolRequest, input
 		date, parseErr := time.Parse("2006-01-02", input.Date)
 		if parseErr != nil {
 			return nil, GetEntriesOutput{
-				Success: false,
-				Message: fmt.Sprintf("Invalid date format: %s", input.Date),
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 
 		dayLog, err := s.storage.GetDay(date)
 		if err != nil {
 			return nil, GetEntriesOutput{
-				Success: false,
-				Message: fmt.Sprintf("Failed to get day: %v", err),
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to get day: %v", err),
+				ErrorCode: errorCode(err),
 			}, nil
 		}
 
@@ -259,8 +752,9 @@ func (s *Server) GetEntries(ctx context.Context, req *mcp.CallToolRequest, input
 		endDate, err2 := time.Parse("2006-01-02", input.DateEnd)
 		if err1 != nil || err2 != nil {
 			return nil, GetEntriesOutput{
-				Success: false,
-				Message: "Invalid date format in range",
+				Success:   false,
+				Message:   "Invalid date format in range",
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 
@@ -269,14 +763,18 @@ func (s *Server) GetEntries(ctx context.Context, req *mcp.CallToolRequest, input
 			DateEnd:   &endDate,
 			Type:      input.Type,
 			Tags:      input.Tags,
+			Author:    input.Author,
 			Limit:     input.Limit,
+			Sort:      input.Sort,
+			Progress:  progressNotifier(ctx, req, "Getting entries"),
 		}
 
 		searchResult, err := s.storage.SearchLogs(searchReq)
 		if err != nil {
 			return nil, GetEntriesOutput{
-				Success: false,
-				Message: fmt.Sprintf("Failed to search logs: %v", err),
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to search logs: %v", err),
+				ErrorCode: errorCode(err),
 			}, nil
 		}
 
@@ -289,16 +787,21 @@ func (s *Server) GetEntries(ctx context.Context, req *mcp.CallToolRequest, input
 		dayLog, err := s.storage.GetDay(today)
 		if err != nil {
 			return nil, GetEntriesOutput{
-				Success: false,
-				Message: fmt.Sprintf("Failed to get today's entries: %v", err),
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to get today's entries: %v", err),
+				ErrorCode: errorCode(err),
 			}, nil
 		}
 
 		entries = dayLog.Entries
 		period = today.Format("2006-01-02")
 	}
+	if input.Author != "" {
+		entries = storage.AuthorEntries(entries, input.Author)
+	}
 
 	// Convert to output format
+	storage.SortEntries(entries, order)
 	outputEntries := make([]LogEntryOutput, 0, len(entries))
 	for _, entry := range entries {
 		outputEntry := LogEntryOutput{
@@ -313,6 +816,8 @@ func (s *Server) GetEntries(ctx context.Context, req *mcp.CallToolRequest, input
 			Priority:    entry.Priority,
 			Duration:    entry.Duration,
 			Location:    entry.Location,
+			Visibility:  entry.Visibility,
+			Author:      entry.Author,
 			Metadata:    entry.Metadata,
 			Success:     true,
 		}
@@ -341,16 +846,31 @@ func (s *Server) SearchLogs(ctx context.Context, req *mcp.CallToolRequest, input
 	SearchLogsOutput,
 	error,
 ) {
-	log.Printf("SearchLogs called with input: %+v", input)
+	logToolCall("SearchLogs", input)
+	s = s.forRequest(ctx, req)
 
 	// Build search request
 	searchReq := storage.LogSearchRequest{
-		SearchText: input.Query,
-		Type:       input.Type,
-		Tags:       input.Tags,
-		StatusMin:  input.StatusMin,
-		StatusMax:  input.StatusMax,
-		Limit:      input.Limit,
+		SearchText:   input.Query,
+		Type:         input.Type,
+		Tags:         input.Tags,
+		Location:     input.Location,
+		StatusMin:    input.StatusMin,
+		StatusMax:    input.StatusMax,
+		Pinned:       input.Pinned,
+		Unreviewed:   input.Unreviewed,
+		Author:       input.Author,
+		Limit:        input.Limit,
+		Aggregations: input.Aggregations,
+		Sort:         input.Sort,
+	}
+
+	if err := storage.ValidateAggregations(input.Aggregations); err != nil {
+		return nil, SearchLogsOutput{
+			Success:   false,
+			Message:   err.Error(),
+			ErrorCode: errorCode(err),
+		}, nil
 	}
 
 	// Parse date range if provided
@@ -358,8 +878,9 @@ func (s *Server) SearchLogs(ctx context.Context, req *mcp.CallToolRequest, input
 		startDate, err := time.Parse("2006-01-02", input.DateStart)
 		if err != nil {
 			return nil, SearchLogsOutput{
-				Success: false,
-				Message: fmt.Sprintf("Invalid start date format: %s", input.DateStart),
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid start date format: %s", input.DateStart),
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 		searchReq.DateStart = &startDate
@@ -369,19 +890,22 @@ func (s *Server) SearchLogs(ctx context.Context, req *mcp.CallToolRequest, input
 		endDate, err := time.Parse("2006-01-02", input.DateEnd)
 		if err != nil {
 			return nil, SearchLogsOutput{
-				Success: false,
-				Message: fmt.Sprintf("Invalid end date format: %s", input.DateEnd),
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid end date format: %s", input.DateEnd),
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 		searchReq.DateEnd = &endDate
 	}
 
 	// Perform search
+	searchReq.Progress = progressNotifier(ctx, req, "Searching")
 	searchResult, err := s.storage.SearchLogs(searchReq)
 	if err != nil {
 		return nil, SearchLogsOutput{
-			Success: false,
-			Message: fmt.Sprintf("Search failed: %v", err),
+			Success:   false,
+			Message:   fmt.Sprintf("Search failed: %v", err),
+			ErrorCode: errorCode(err),
 		}, nil
 	}
 
@@ -400,6 +924,8 @@ func (s *Server) SearchLogs(ctx context.Context, req *mcp.CallToolRequest, input
 			Priority:    entry.Priority,
 			Duration:    entry.Duration,
 			Location:    entry.Location,
+			Visibility:  entry.Visibility,
+			Author:      entry.Author,
 			Metadata:    entry.Metadata,
 			Success:     true,
 		}
@@ -407,11 +933,13 @@ func (s *Server) SearchLogs(ctx context.Context, req *mcp.CallToolRequest, input
 	}
 
 	result := SearchLogsOutput{
-		Entries:     outputEntries,
-		TotalCount:  searchResult.TotalCount,
-		SearchQuery: input.Query,
-		Success:     true,
-		Message:     fmt.Sprintf("Found %d matching entries", len(outputEntries)),
+		Entries:      outputEntries,
+		TotalCount:   searchResult.TotalCount,
+		SearchQuery:  input.Query,
+		Aggregations: searchResult.Aggregations,
+		Totals:       searchResult.Totals,
+		Success:      true,
+		Message:      fmt.Sprintf("Found %d matching entries", len(outputEntries)),
 	}
 
 	return nil, result, nil
@@ -423,7 +951,8 @@ func (s *Server) SummarizePeriod(ctx context.Context, req *mcp.CallToolRequest,
 	SummarizePeriodOutput,
 	error,
 ) {
-	log.Printf("SummarizePeriod called with input: %+v", input)
+	logToolCall("SummarizePeriod", input)
+	s = s.forRequest(ctx, req)
 
 	// Parse date
 	var targetDate time.Time
@@ -432,20 +961,32 @@ func (s *Server) SummarizePeriod(ctx context.Context, req *mcp.CallToolRequest,
 		targetDate, err = time.Parse("2006-01-02", input.Date)
 		if err != nil {
 			return nil, SummarizePeriodOutput{
-				Success: false,
-				Message: fmt.Sprintf("Invalid date format: %s", input.Date),
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 	} else {
 		targetDate = time.Now()
 	}
 
+	if err := storage.ValidateVisibility(input.Audience); err != nil {
+		return nil, SummarizePeriodOutput{
+			Success:   false,
+			Message:   err.Error(),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
 	// Create summary request
 	summaryReq := storage.SummaryRequest{
-		Type:   input.Type,
-		Date:   targetDate,
-		UseAI:  input.UseAI,
-		Prompt: input.Prompt,
+		Type:     input.Type,
+		Date:     targetDate,
+		UseAI:    input.UseAI,
+		Prompt:   input.Prompt,
+		Audience: input.Audience,
+		Tags:     input.Tags,
+		Progress: progressNotifier(ctx, req, "Summarizing"),
 	}
 
 	// Handle custom date range
@@ -454,8 +995,9 @@ func (s *Server) SummarizePeriod(ctx context.Context, req *mcp.CallToolRequest,
 		endDate, err2 := time.Parse("2006-01-02", input.DateEnd)
 		if err1 != nil || err2 != nil {
 			return nil, SummarizePeriodOutput{
-				Success: false,
-				Message: "Invalid date format in range",
+				Success:   false,
+				Message:   "Invalid date format in range",
+				ErrorCode: errorInvalidDate,
 			}, nil
 		}
 		summaryReq.StartDate = &startDate
@@ -467,11 +1009,30 @@ func (s *Server) SummarizePeriod(ctx context.Context, req *mcp.CallToolRequest,
 	summaryResult, err := s.storage.GenerateSummary(summaryReq)
 	if err != nil {
 		return nil, SummarizePeriodOutput{
-			Success: false,
-			Message: fmt.Sprintf("Failed to generate summary: %v", err),
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to generate summary: %v", err),
+			ErrorCode: errorCode(err),
 		}, nil
 	}
 
+	if input.UseAI {
+		data := prompts.Data{
+			Period:  summaryResult.Period,
+			Entries: summaryResult.Entries,
+			Stats:   summaryResult.Stats,
+			Facts:   summaryResult.Summary,
+			Prompt:  input.Prompt,
+		}
+		summaryResult.Summary, err = s.draftWithAI("summary", data, summaryResult.Summary, input.NoCache)
+		if err != nil {
+			return nil, SummarizePeriodOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to generate summary: %v", err),
+				ErrorCode: errorCode(err),
+			}, nil
+		}
+	}
+
 	result := SummarizePeriodOutput{
 		Summary:   summaryResult.Summary,
 		Type:      summaryResult.Type,
@@ -481,28 +1042,70 @@ func (s *Server) SummarizePeriod(ctx context.Context, req *mcp.CallToolRequest,
 		Success:   true,
 		Message:   fmt.Sprintf("Summary generated for %s", summaryResult.Period),
 	}
+	for _, entry := range summaryResult.Pinned {
+		result.Pinned = append(result.Pinned, logEntryOutput(&entry))
+	}
 
 	return nil, result, nil
 }
 
+// GetStats implements the dailylog_get_stats tool
+func (s *Server) GetStats(ctx context.Context, req *mcp.CallToolRequest, input GetStatsInput) (
+	*mcp.CallToolResult,
+	GetStatsOutput,
+	error,
+) {
+	logToolCall("GetStats", input)
+	s = s.forRequest(ctx, req)
+
+	startDate, endDate, err := statsPeriod(input, time.Now())
+	if err != nil {
+		return nil, GetStatsOutput{
+			Success:   false,
+			Message:   err.Error(),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	stats, err := s.storage.GetStats(startDate, endDate)
+	if err != nil {
+		return nil, GetStatsOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to get stats: %v", err),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+
+	period := fmt.Sprintf("%s to %s", startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	return nil, GetStatsOutput{
+		Stats:   stats,
+		Period:  period,
+		Success: true,
+		Message: fmt.Sprintf("Stats for %s", period),
+	}, nil
+}
+
 // AIAssist implements the dailylog_ai_assist tool
 func (s *Server) AIAssist(ctx context.Context, req *mcp.CallToolRequest, input AIAssistInput) (
 	*mcp.CallToolResult,
 	AIAssistOutput,
 	error,
 ) {
-	log.Printf("AIAssist called with input: %+v", input)
+	logToolCall("AIAssist", input)
+	s = s.forRequest(ctx, req)
 
 	// Basic implementation - would integrate with actual AI services
 	var result string
 	var suggestions []string
+	var stats map[string]any
 
 	switch input.Action {
 	case "improve_wording":
 		if input.Text == "" {
 			return nil, AIAssistOutput{
-				Success: false,
-				Message: "Text is required for improve_wording action",
+				Success:   false,
+				Message:   "Text is required for improve_wording action",
+				ErrorCode: errorValidation,
 			}, nil
 		}
 		result = s.improveWording(input.Text)
@@ -510,29 +1113,70 @@ func (s *Server) AIAssist(ctx context.Context, req *mcp.CallToolRequest, input A
 	case "suggest_tags":
 		if input.Text == "" {
 			return nil, AIAssistOutput{
-				Success: false,
-				Message: "Text is required for suggest_tags action",
+				Success:   false,
+				Message:   "Text is required for suggest_tags action",
+				ErrorCode: errorValidation,
 			}, nil
 		}
 		suggestions = s.suggestTags(input.Text)
 		result = fmt.Sprintf("Suggested tags: %s", strings.Join(suggestions, ", "))
 
 	case "analyze_status":
-		if input.Date == "" {
-			input.Date = time.Now().Format("2006-01-02")
+		start, end, err := statusPeriod(input, time.Now())
+		if err != nil {
+			return nil, AIAssistOutput{
+				Success:   false,
+				Message:   err.Error(),
+				ErrorCode: errorCode(err),
+			}, nil
+		}
+		result, stats, err = s.analyzeStatus(start, end, input.NoCache)
+		if err != nil {
+			return nil, AIAssistOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to analyze status: %v", err),
+				ErrorCode: errorCode(err),
+			}, nil
 		}
-		result = s.analyzeStatus(input.Date)
 
-	case "generate_insights":
-		if input.Date == "" {
-			input.Date = time.Now().Format("2006-01-02")
+	case "generate_insights", "weekly_retro", "gratitude_prompt", "tomorrow_plan":
+		date := time.Now()
+		if input.Date != "" {
+			var err error
+			date, err = time.Parse("2006-01-02", input.Date)
+			if err != nil {
+				return nil, AIAssistOutput{
+					Success:   false,
+					Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+					ErrorCode: errorInvalidDate,
+				}, nil
+			}
+		}
+
+		var err error
+		switch input.Action {
+		case "generate_insights":
+			result, err = s.generateInsights(date, input.NoCache)
+		case "weekly_retro":
+			result, suggestions, err = s.weeklyRetro(date, input.NoCache)
+		case "gratitude_prompt":
+			result, suggestions, err = s.gratitudePrompts(date, input.NoCache)
+		default:
+			result, suggestions, err = s.tomorrowPlan(date, input.NoCache)
+		}
+		if err != nil {
+			return nil, AIAssistOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to run %s: %v", input.Action, err),
+				ErrorCode: errorCode(err),
+			}, nil
 		}
-		result = s.generateInsights(input.Date)
 
 	default:
 		return nil, AIAssistOutput{
-			Success: false,
-			Message: fmt.Sprintf("Unknown AI action: %s", input.Action),
+			Success:   false,
+			Message:   fmt.Sprintf("Unknown AI action: %s", input.Action),
+			ErrorCode: errorValidation,
 		}, nil
 	}
 
@@ -540,6 +1184,7 @@ func (s *Server) AIAssist(ctx context.Context, req *mcp.CallToolRequest, input A
 		Result:      result,
 		Action:      input.Action,
 		Suggestions: suggestions,
+		Stats:       stats,
 		Success:     true,
 		Message:     fmt.Sprintf("AI %s completed successfully", input.Action),
 	}
@@ -558,53 +1203,98 @@ func (s *Server) calculateEntryStats(entries []storage.DailyLogEntry) map[string
 		return stats
 	}
 
-	// Count by type
-	typeCount := make(map[string]int)
-	statusSum := 0
-	statusCount := 0
-	prioritySum := 0
-	priorityCount := 0
-	tagCount := make(map[string]int)
+	totals := storage.SummarizeEntries(entries)
+	stats["total_duration"] = totals.TotalDuration
+	stats["by_type"] = storage.AggregateEntries(entries, storage.GroupByType).Buckets
+	stats["top_tags"] = storage.AggregateEntries(entries, storage.GroupByTag).Buckets
 
-	for _, entry := range entries {
-		typeCount[entry.Type]++
+	if totals.AverageStatus > 0 {
+		stats["average_status"] = totals.AverageStatus
+	}
 
-		if entry.Status > 0 {
-			statusSum += entry.Status
-			statusCount++
-		}
+	if totals.AveragePriority > 0 {
+		stats["average_priority"] = totals.AveragePriority
+	}
 
-		if entry.Priority > 0 {
-			prioritySum += entry.Priority
-			priorityCount++
-		}
+	return stats
+}
+
+// statsPeriod resolves the date range of a stats request
+func statsPeriod(input GetStatsInput, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 
-		for _, tag := range entry.Tags {
-			tagCount[tag]++
+	endDate := today
+	if input.DateEnd != "" {
+		parsed, err := time.Parse("2006-01-02", input.DateEnd)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", errInvalidDate, input.DateEnd)
 		}
+		endDate = parsed
 	}
 
-	stats["by_type"] = typeCount
-	stats["top_tags"] = tagCount
-
-	if statusCount > 0 {
-		stats["average_status"] = float64(statusSum) / float64(statusCount)
+	if input.DateStart != "" {
+		startDate, err := time.Parse("2006-01-02", input.DateStart)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("%w: %s", errInvalidDate, input.DateStart)
+		}
+		return startDate, endDate, nil
 	}
 
-	if priorityCount > 0 {
-		stats["average_priority"] = float64(prioritySum) / float64(priorityCount)
+	weekStart := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+
+	switch input.Period {
+	case "today":
+		return today, today, nil
+	case "", "week":
+		return weekStart, today, nil
+	case "month":
+		return monthStart, today, nil
+	case "last_week":
+		return weekStart.AddDate(0, 0, -7), weekStart.AddDate(0, 0, -1), nil
+	case "last_month":
+		return monthStart.AddDate(0, -1, 0), monthStart.AddDate(0, 0, -1), nil
+	default:
+		return time.Time{}, time.Time{}, storage.ValidationError{
+			Field:   "period",
+			Message: fmt.Sprintf("must be one of today, week, month, last_week, last_month (got %q)", input.Period),
+		}
 	}
+}
 
-	return stats
+// redacted returns the AI provider, behind the redactor when there is one
+func (s *Server) redacted() storage.AIProvider {
+	if s.redactor != nil {
+		return ai.NewRedactingProvider(s.ai, s.redactor)
+	}
+	return s.ai
 }
 
-// Basic AI simulation methods (would be replaced with actual AI integration)
+// improveWording has the AI provider reword text, or marks it as a
+// placeholder without one
 func (s *Server) improveWording(text string) string {
-	// Placeholder implementation
+	if s.ai != nil {
+		improved, err := s.redacted().ImproveWording(text)
+		if err == nil {
+			return improved
+		}
+		slog.Warn("AI provider failed", "action", "improve_wording", "error", err)
+	}
 	return fmt.Sprintf("Enhanced: %s", text)
 }
 
+// suggestTags has the AI provider suggest tags, falling back to keywords
 func (s *Server) suggestTags(text string) []string {
+	if s.ai != nil {
+		tags, err := s.redacted().SuggestTags(text)
+		if err == nil && len(tags) > 0 {
+			return tags
+		}
+		if err != nil {
+			slog.Warn("AI provider failed", "action", "suggest_tags", "error", err)
+		}
+	}
+
 	// Placeholder implementation - basic keyword extraction
 	words := strings.Fields(strings.ToLower(text))
 	tags := []string{}
@@ -627,16 +1317,6 @@ func (s *Server) suggestTags(text string) []string {
 	return tags
 }
 
-func (s *Server) analyzeStatus(dateStr string) string {
-	// Placeholder implementation
-	return fmt.Sprintf("Status analysis for %s: Overall positive trend with minor fluctuations", dateStr)
-}
-
-func (s *Server) generateInsights(dateStr string) string {
-	// Placeholder implementation
-	return fmt.Sprintf("Insights for %s: Productive day with good work-life balance", dateStr)
-}
-
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -646,35 +1326,344 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
-func main() {
-	// Initialize GitHub storage provider
-	config := storage.Config{
-		StorageType: "github",
-		GitHubRepo:  os.Getenv("DAILYLOG_GITHUB_REPO"),
-		GitHubToken: os.Getenv("DAILYLOG_GITHUB_TOKEN"),
-		GitHubPath:  os.Getenv("DAILYLOG_GITHUB_PATH"),
+// setupMessage explains how to configure the server when the repository or
+// token is missing
+func setupMessage(configPath string) string {
+	source := "no configuration file was found"
+	if configPath != "" {
+		source = "read configuration from " + configPath
 	}
+	return fmt.Sprintf(`DailyLog is not configured (%s).
 
-	// Fallback to default values if env vars not set
-	if config.GitHubRepo == "" {
-		config.GitHubRepo = "cloudygreybeard/daily-logs" // Replace with actual repo
-	}
-	if config.GitHubPath == "" {
-		config.GitHubPath = "logs"
+Set the GitHub repository holding your log and a token with access to it,
+either in the environment:
+
+  DAILYLOG_GITHUB_REPO=your-user/daily-logs
+  DAILYLOG_GITHUB_TOKEN=ghp_...
+
+or in ~/.dailyctl.yaml, shared with dailyctl, or a file named with --config:
+
+  github:
+    repo: your-user/daily-logs
+    token: ghp_...
+
+With keychain: true, the token can be kept in the system keychain instead,
+under the service "dailylog" and the account "github.token".
+
+To try the server with sample data instead, set DAILYLOG_DEMO=true.
+`, source)
+}
+
+// addTools registers the daily log tools of s, namespaced by profile if it has one
+func addTools(server *mcp.Server, tools *toolExposure, s *Server, profile string) {
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_entry",
+		Description: "Create a new daily log entry for activities, status updates, notes, summaries, meetings, or health metrics (type metric, with metric and value)",
+	}, s.LogEntry)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_get_entry",
+		Description: "Get a single log entry by its ID or a short ID prefix",
+	}, s.GetEntry)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_move_entry",
+		Description: "Move a log entry to another day, keeping its ID, time of day, and metadata, or copy it there with a new ID",
+	}, s.MoveEntry)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_get_day",
+		Description: "Get the complete log for one day: its entries, day summary, status average, and day-level metadata",
+	}, s.GetDay)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_get_entries",
+		Description: "Get log entries for a specific date or date range",
+	}, s.GetEntries)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_search",
+		Description: "Search through log entries by text, tags, status, or other criteria",
+	}, s.SearchLogs)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_summarize",
+		Description: "Generate summaries for daily, weekly, monthly, or custom periods",
+	}, s.SummarizePeriod)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_get_stats",
+		Description: "Get aggregate statistics for a period: entry counts, average status, total minutes logged by type and tag, and the focus ratio (deep work against meetings and admin) by day and week",
+	}, s.GetStats)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_ai_assist",
+		Description: "AI assistance for wording improvements, tag suggestions, status analysis, insights, weekly retrospectives, gratitude prompts, and planning tomorrow",
+	}, s.AIAssist)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_extract_actions",
+		Description: "Find TODOs and action items in a day's entries and create linked task notes for them, returning the tasks created",
+	}, s.ExtractActions)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_on_this_day",
+		Description: "Get entries logged on the same calendar date in previous years (and optionally months), for reflection and resurfacing old notes",
+	}, s.OnThisDay)
+
+	addTool(server, tools, profile, &mcp.Tool{
+		Name:        "dailylog_wellbeing_check",
+		Description: "Check a week for early signs of burnout against the four weeks before it: a declining mood, more time in meetings, less in breaks, and more entries late in the evening, giving a low, moderate, or high risk",
+	}, s.WellbeingCheck)
+}
+
+// openStorage creates the storage the tools read and write, as configured
+// in cfg: the GitHub repository, or memory, seeded with sample data in demo
+// mode, with its mirror and hooks, refusing writes in read-only mode. It
+// returns the commit messages dry runs preview and a function closing it.
+func openStorage(cfg *viper.Viper, configPath string, readOnly bool) (storage.DailyLogStorage, *storage.CommitMessages, func()) {
+	storageConfig := config.Storage(cfg)
+	if storageConfig.StorageType == storage.StorageTypeGitHub &&
+		(storageConfig.GitHubRepo == "" || (storageConfig.GitHubToken == "" && !providers.Replaying())) {
+		fmt.Fprint(os.Stderr, setupMessage(configPath))
+		os.Exit(1)
 	}
 
-	storageProvider, err := providers.NewGitHubStorageProvider(config)
+	storageProvider, err := providers.NewStorageProvider(storageConfig)
 	if err != nil {
-		log.Fatalf("Failed to create storage provider: %v", err)
+		fatalf("Failed to create storage provider: %v", err)
+	}
+	var commitMessages *storage.CommitMessages
+	switch provider := storageProvider.(type) {
+	case *providers.GitHubStorageProvider:
+		provider.OnPullRequestOpened = func(url string) {
+			slog.Info("Opened pull request", "url", url)
+		}
+		provider.OnPullRequestError = func(err error) {
+			slog.Error("Failed to open a pull request", "error", err)
+		}
+		commitMessages = provider.CommitMessages()
+	case *providers.MemoryStorageProvider:
+		if cfg.GetBool("demo") {
+			if err := providers.SeedDemo(provider, time.Now()); err != nil {
+				fatalf("Failed to seed sample data: %v", err)
+			}
+			slog.Info("Demo mode: serving sample data from memory; nothing is saved", "days", providers.DemoDays)
+		} else {
+			slog.Warn("Storing logs in memory; they are lost when the server stops")
+		}
 	}
 
 	// Verify storage is accessible
 	if err := storageProvider.HealthCheck(); err != nil {
-		log.Fatalf("Storage health check failed: %v", err)
+		fatalf("Storage health check failed: %v", err)
+	}
+
+	// Optionally replicate writes to a local mirror
+	var result storage.DailyLogStorage = storageProvider
+	closeStorage := func() {}
+	if mirrorPath := cfg.GetString("mirror.path"); mirrorPath != "" {
+		mirror, err := providers.NewLocalDayStore(mirrorPath)
+		if err != nil {
+			fatalf("Failed to create mirror: %v", err)
+		}
+		mirrored := providers.NewMirroredProvider(storageProvider, mirror)
+		mirrored.OnMirrorError = func(date time.Time, err error) {
+			slog.Error("Failed to mirror", "date", date.Format("2006-01-02"), "error", err)
+		}
+		closeStorage = func() { _ = mirrored.Close() }
+		result = mirrored
+	}
+
+	result = providers.NewHookedProvider(result, entryHooks(cfg)...)
+
+	if readOnly {
+		result = providers.NewReadOnlyProvider(result)
+	}
+	return result, commitMessages, closeStorage
+}
+
+// entryHooks returns the hooks run around entry writes: validation,
+// attribution to the configured author, then the configured scripts
+func entryHooks(cfg *viper.Viper) []providers.Hook {
+	scripts, err := config.Hooks(cfg)
+	if err != nil {
+		fatalf("Invalid hooks: %v", err)
+	}
+	hooks := []providers.Hook{providers.ValidationHook()}
+	if author := config.Author(cfg); author != "" {
+		hooks = append(hooks, providers.AuthorHook(author))
+	}
+	for _, script := range scripts {
+		hooks = append(hooks, script.Hook(func(name string, err error) {
+			slog.Warn("Hook failed", "hook", name, "error", err)
+		}))
+	}
+	return hooks
+}
+
+// preview returns storage previewing writes instead of making them. The
+// before hooks still run, so it shows what would be saved, or refused;
+// after hooks act on what was written, so it skips them.
+func (s *Server) preview() (storage.DailyLogStorage, *providers.DryRunProvider) {
+	preview := providers.NewDryRunProvider(s.storage)
+	preview.Messages = s.commitMessages
+	hooks := make([]providers.Hook, len(s.hooks))
+	for i, hook := range s.hooks {
+		hooks[i] = hook.BeforeOnly()
+	}
+	return providers.NewHookedProvider(preview, hooks...), preview
+}
+
+func main() {
+	configFlag := flag.String("config", "", "YAML configuration file (default: $DAILYLOG_CONFIG, or the first of ~/.dailyctl.yaml, ./.dailyctl.yaml, and "+config.ContainerPath+" that exists)")
+	transportFlag := flag.String("transport", "", "Transport: stdio or http (default: $DAILYLOG_TRANSPORT, or stdio)")
+	addrFlag := flag.String("addr", "", "Address for the http transport (default: $DAILYLOG_HTTP_ADDR, or "+defaultHTTPAddr+")")
+	flag.Parse()
+
+	// Resolve settings from flags, the environment, the configuration file
+	// shared with dailyctl, and the keychain, in that order
+	cfg := viper.New()
+	cfg.SetDefault("transport", transportStdio)
+	cfg.SetDefault("http_addr", defaultHTTPAddr)
+	configPath, err := config.Load(cfg, *configFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if *transportFlag != "" {
+		cfg.Set("transport", *transportFlag)
+	}
+	if *addrFlag != "" {
+		cfg.Set("http_addr", *addrFlag)
+	}
+	transport, httpAddr := cfg.GetString("transport"), cfg.GetString("http_addr")
+	if transport != transportStdio && transport != transportHTTP {
+		fmt.Fprintf(os.Stderr, "Invalid transport %q: use %s or %s\n", transport, transportStdio, transportHTTP)
+		os.Exit(1)
+	}
+
+	// Log to stderr to avoid JSON-RPC interference
+	logger, err := newLogger(os.Stderr, cfg.GetString("log_level"), cfg.GetString("log_format"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid logging configuration: %v\n", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	// Optionally trace tool calls through storage to the GitHub API, to the
+	// OTLP endpoint in OTEL_EXPORTER_OTLP_ENDPOINT
+	flushSpans := setupTracing()
+	defer flushSpans()
+
+	// Optionally record GitHub API requests to a file, or replay them from
+	// one without the network
+	if record := cfg.GetString("github.record"); record != "" {
+		if err := providers.UseCassette(record, providers.CassetteRecord); err != nil {
+			fatalf("Failed to record GitHub API requests: %v", err)
+		}
+	} else if replay := cfg.GetString("github.replay"); replay != "" {
+		if err := providers.UseCassette(replay, providers.CassetteReplay); err != nil {
+			fatalf("Failed to replay GitHub API requests: %v", err)
+		}
 	}
 
+	// Read-only mode registers only the tools that read the log (see
+	// writeTools), and refuses writes from the rest, for giving agents access
+	// without letting them edit
+	readOnly := cfg.GetBool("readonly")
+
 	// Create our server instance
-	dailyLogServer := &Server{storage: storageProvider}
+	promptsDir := cfg.GetString("prompts.dir")
+	if promptsDir == "" {
+		promptsDir = prompts.DefaultDir()
+	}
+	tokenBudget := ai.DefaultTokenBudget
+	if budget := cfg.GetString("ai.token_budget"); budget != "" {
+		tokenBudget, err = strconv.Atoi(budget)
+		if err != nil || tokenBudget <= 0 {
+			fatalf("Invalid DAILYLOG_AI_TOKEN_BUDGET: %s", budget)
+		}
+	}
+	verbose := cfg.GetBool("verbose")
+
+	dailyLogServer := &Server{
+		prompts:     prompts.NewLibrary(promptsDir),
+		tokenBudget: tokenBudget,
+		verbose:     verbose,
+		aiCacheDir:  cfg.GetString("ai.cache_dir"),
+		aiModel:     cfg.GetString("ai.model"),
+		sampling:    true,
+	}
+	if dailyLogServer.aiCacheDir == "" {
+		dailyLogServer.aiCacheDir = ai.DefaultCacheDir()
+	}
+	if sampling := cfg.GetString("ai.sampling"); sampling != "" {
+		if dailyLogServer.sampling, err = strconv.ParseBool(sampling); err != nil {
+			fatalf("Invalid DAILYLOG_AI_SAMPLING: %s", sampling)
+		}
+	}
+	if redactFile := cfg.GetString("ai.redact_file"); redactFile != "" {
+		rules, err := ai.LoadRedactionRules(redactFile)
+		if err != nil {
+			fatalf("Failed to load redaction rules: %v", err)
+		}
+		dailyLogServer.redactor, err = ai.NewRedactor(rules)
+		if err != nil {
+			fatalf("Invalid redaction rules: %v", err)
+		}
+	}
+
+	// Optionally tag new entries with their sentiment
+	if cfg.GetBool("sentiment.enabled") {
+		switch analyzer := cfg.GetString("sentiment.analyzer"); analyzer {
+		case "", "local":
+			dailyLogServer.sentiment = sentiment.NewLexicon()
+		case "ai":
+			// The client's model analyzes it through sampling; clients
+			// without sampling get the local analyzer
+			if !dailyLogServer.sampling {
+				fatalf("DAILYLOG_SENTIMENT_ANALYZER=ai asks the client's model, so needs DAILYLOG_AI_SAMPLING enabled")
+			}
+			dailyLogServer.sentiment = sentiment.NewLexicon()
+			dailyLogServer.sentimentAI = true
+		default:
+			fatalf("Invalid DAILYLOG_SENTIMENT_ANALYZER: %s (use local or ai)", analyzer)
+		}
+		dailyLogServer.sentimentTypes = config.List(cfg, "sentiment.types")
+	}
+
+	// Link new entries to the issues their titles mention
+	if dailyLogServer.issues, err = config.Issues(cfg); err != nil {
+		fatalf("Invalid issue trackers: %v", err)
+	}
+
+	// Optionally record the weather of days as entries are logged
+	if cfg.GetBool("weather.enabled") {
+		dailyLogServer.weather = weather.NewClient(weather.Options{
+			ForecastURL: cfg.GetString("weather.forecast_url"),
+			ArchiveURL:  cfg.GetString("weather.archive_url"),
+			GeocodeURL:  cfg.GetString("weather.geocode_url"),
+		})
+		dailyLogServer.weatherLocation = cfg.GetString("weather.location")
+	}
+
+	// Optionally check new entries for duplicates
+	dailyLogServer.duplicates = cfg.GetString("duplicates.mode")
+	if err := providers.ValidateDuplicateMode(dailyLogServer.duplicates); err != nil {
+		fatalf("Invalid DAILYLOG_DUPLICATES: %v", err)
+	}
+	dailyLogServer.duplicateWindow = storage.DefaultDuplicateWindow
+	if window := cfg.GetString("duplicates.window"); window != "" {
+		var err error
+		if dailyLogServer.duplicateWindow, err = time.ParseDuration(window); err != nil {
+			fatalf("Invalid DAILYLOG_DUPLICATE_WINDOW: %v", err)
+		}
+	}
+
+	// Serve the configured log, or each profile's under tools of its own
+	profiles, closeStorage := openProfiles(cfg, configPath, readOnly, dailyLogServer)
+	defer closeStorage()
 
 	// Create MCP server with our implementation info
 	server := mcp.NewServer(&mcp.Implementation{
@@ -682,38 +1671,98 @@ func main() {
 		Version: version,
 	}, nil)
 
-	// Add daily log tools
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "dailylog_entry",
-		Description: "Create a new daily log entry for activities, status updates, notes, or summaries",
-	}, dailyLogServer.LogEntry)
+	// Track tool calls in flight, innermost so calls refused while shutting
+	// down are still rate limited, audited, and counted
+	shutdownTimeout := defaultShutdownTimeout
+	if timeout := cfg.GetString("shutdown_timeout"); timeout != "" {
+		if shutdownTimeout, err = time.ParseDuration(timeout); err != nil {
+			fatalf("Invalid DAILYLOG_SHUTDOWN_TIMEOUT: %v", err)
+		}
+	}
+	drain := &drainer{}
+	server.AddReceivingMiddleware(drain.middleware)
 
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "dailylog_get_entries",
-		Description: "Get log entries for a specific date or date range",
-	}, dailyLogServer.GetEntries)
+	// Optionally rate limit tool calls and audit them; the audit log wraps
+	// the rate limiter so refused calls are recorded too
+	if limits := strings.Join(config.List(cfg, "rate_limits"), ","); limits != "" {
+		parsed, err := parseRateLimits(limits)
+		if err != nil {
+			fatalf("Invalid DAILYLOG_RATE_LIMITS: %v", err)
+		}
+		server.AddReceivingMiddleware(newRateLimiter(parsed).middleware)
+	}
+	if auditPath := cfg.GetString("audit_log"); auditPath != "" {
+		audit, err := openAuditLog(auditPath)
+		if err != nil {
+			fatalf("Failed to open audit log: %v", err)
+		}
+		defer audit.Close()
+		server.AddReceivingMiddleware(audit.middleware)
+	}
 
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "dailylog_search",
-		Description: "Search through log entries by text, tags, status, or other criteria",
-	}, dailyLogServer.SearchLogs)
+	// Tool call metrics and spans are recorded outside the rate limiter and
+	// audit log; DAILYLOG_METRICS_ADDR exposes the metrics, with the GitHub
+	// API and AI cache metrics, at /metrics, beside /healthz and /readyz
+	server.AddReceivingMiddleware(metricsMiddleware)
+	server.AddReceivingMiddleware(tracingMiddleware)
+	readiness := web.NewReadiness(profiles[0].server.storage)
+	var httpServer *http.Server
+	if metricsAddr := cfg.GetString("metrics_addr"); metricsAddr != "" {
+		httpServer = serveOps(metricsAddr, readiness)
+	}
 
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "dailylog_summarize",
-		Description: "Generate summaries for daily, weekly, monthly, or custom periods",
-	}, dailyLogServer.SummarizePeriod)
+	// Add daily log tools, as configured
+	tools, err := newToolExposure(strings.Join(config.List(cfg, "tools"), ","), strings.Join(config.List(cfg, "disabled_tools"), ","),
+		strings.Join(config.List(cfg, "tool_names"), ","), readOnly)
+	if err != nil {
+		fatalf("Invalid DAILYLOG_TOOL_NAMES: %v", err)
+	}
+	for _, profile := range profiles {
+		addTools(server, tools, profile.server, profile.name)
+	}
+	if err := tools.check(); err != nil {
+		fatalf("Invalid tool configuration: %v", err)
+	}
 
-	mcp.AddTool(server, &mcp.Tool{
-		Name:        "dailylog_ai_assist",
-		Description: "AI assistance for wording improvements, tag suggestions, status analysis, and insights",
-	}, dailyLogServer.AIAssist)
+	slog.Info("Starting DailyLog MCP server", "version", version, "transport", transport)
+	if readOnly {
+		slog.Info("Read-only mode: tools that write to the log are not available")
+	}
 
-	// Set up logging to stderr to avoid JSON-RPC interference
-	log.SetOutput(os.Stderr)
-	log.Println("Starting DailyLog MCP server...")
+	// On SIGINT or SIGTERM, stop taking tool calls and let those in flight
+	// finish before disconnecting
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	runCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-signalCtx.Done():
+		case <-runCtx.Done():
+			return
+		}
+		slog.Info("Shutting down", "timeout", shutdownTimeout)
+		readiness.Drain()
+		if !drain.drain(shutdownTimeout) {
+			slog.Warn("Tool calls still running at shutdown were cancelled")
+		}
+		cancel()
+	}()
 
-	// Run the server over stdin/stdout until client disconnects
-	if err := server.Run(context.Background(), &mcp.StdioTransport{}); err != nil {
-		log.Fatal("Server failed:", err)
+	// Run the server over stdin/stdout until the client disconnects, or
+	// over HTTP until stopped
+	if transport == transportHTTP {
+		err = runHTTP(runCtx, server, httpAddr, cfg.GetString("http_token"), readiness)
+	} else {
+		err = server.Run(runCtx, &mcp.StdioTransport{})
+	}
+	if httpServer != nil {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = httpServer.Shutdown(shutdownCtx)
+		cancelShutdown()
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		fatalf("Server failed: %v", err)
 	}
+	slog.Info("Server stopped")
 }
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/metrics"
+	"dailylog/internal/web"
+)
+
+// Tool call metrics
+var (
+	toolCalls = metrics.Default.NewCounter("dailylog_mcp_tool_calls_total",
+		"MCP tool calls, by tool and result (ok, or the error code).", "tool", "result")
+	toolDuration = metrics.Default.NewHistogram("dailylog_mcp_tool_call_duration_seconds",
+		"MCP tool call latency.", metrics.DefaultBuckets, "tool")
+)
+
+// metricsMiddleware records the count and latency of tool calls
+func metricsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok {
+			return next(ctx, method, req)
+		}
+
+		started := time.Now()
+		result, err := next(ctx, method, req)
+		toolDuration.Observe(time.Since(started).Seconds(), call.Params.Name)
+
+		var record auditRecord
+		describeResult(&record, result, err)
+		outcome := "ok"
+		switch {
+		case record.ErrorCode != "":
+			outcome = record.ErrorCode
+		case !record.Success:
+			outcome = "error"
+		}
+		toolCalls.Inc(call.Params.Name, outcome)
+		return result, err
+	}
+}
+
+// opsMux serves /metrics for Prometheus to scrape, and /healthz and /readyz
+// for probes
+func opsMux(readiness *web.Readiness) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Default.Handler())
+	mux.HandleFunc("GET /healthz", web.Healthz)
+	mux.Handle("GET /readyz", readiness)
+	return mux
+}
+
+// serveOps serves opsMux at addr until the returned server is shut down
+func serveOps(addr string, readiness *web.Readiness) *http.Server {
+	server := &http.Server{Addr: addr, Handler: opsMux(readiness), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("HTTP server failed", "addr", addr, "error", err)
+		}
+	}()
+	slog.Info("Serving metrics and health checks", "addr", addr)
+	return server
+}
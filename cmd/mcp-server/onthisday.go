@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/storage"
+)
+
+// OnThisDayInput defines parameters for looking back at the same calendar date
+type OnThisDayInput struct {
+	Date   string `json:"date,omitempty" jsonschema:"Date to look back from in YYYY-MM-DD format (defaults to today)"`
+	Years  *int   `json:"years,omitempty" jsonschema:"How many previous years to look at (defaults to 5)"`
+	Months int    `json:"months,omitempty" jsonschema:"How many previous months to look at (defaults to 0)"`
+}
+
+// OnThisDayDay holds the entries logged on one earlier date
+type OnThisDayDay struct {
+	Date    string           `json:"date" jsonschema:"Earlier date"`
+	Ago     string           `json:"ago" jsonschema:"How long before, e.g. 1 year ago"`
+	Entries []LogEntryOutput `json:"entries" jsonschema:"Entries logged that day"`
+}
+
+// OnThisDayOutput defines the response for looking back at the same calendar date
+type OnThisDayOutput struct {
+	Date      string         `json:"date" jsonschema:"Date looked back from"`
+	Days      []OnThisDayDay `json:"days" jsonschema:"Earlier dates with entries, most recent first"`
+	Success   bool           `json:"success" jsonschema:"Whether operation was successful"`
+	Message   string         `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode string         `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
+}
+
+// OnThisDay returns the entries logged on the same calendar date in earlier years and months
+func (s *Server) OnThisDay(ctx context.Context, req *mcp.CallToolRequest, input OnThisDayInput) (
+	*mcp.CallToolResult,
+	OnThisDayOutput,
+	error,
+) {
+	logToolCall("OnThisDay", input)
+	s = s.forRequest(ctx, req)
+
+	date := time.Now()
+	if input.Date != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return nil, OnThisDayOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
+			}, nil
+		}
+	}
+
+	years := storage.DefaultOnThisDayYears
+	if input.Years != nil {
+		years = *input.Years
+	}
+	if years < 0 || input.Months < 0 {
+		return nil, OnThisDayOutput{
+			Success:   false,
+			Message:   "Years and months cannot be negative",
+			ErrorCode: errorValidation,
+		}, nil
+	}
+
+	result := OnThisDayOutput{Date: date.Format("2006-01-02"), Days: []OnThisDayDay{}}
+	total := 0
+	for _, anniversary := range storage.OnThisDay(date, years, input.Months) {
+		dayLog, err := s.storage.GetDay(anniversary.Date)
+		if err != nil {
+			return nil, OnThisDayOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Failed to get %s: %v", anniversary.Date.Format("2006-01-02"), err),
+				ErrorCode: errorCode(err),
+			}, nil
+		}
+		if len(dayLog.Entries) == 0 {
+			continue
+		}
+
+		day := OnThisDayDay{Date: anniversary.Date.Format("2006-01-02"), Ago: anniversary.Ago}
+		for i := range dayLog.Entries {
+			day.Entries = append(day.Entries, logEntryOutput(&dayLog.Entries[i]))
+		}
+		result.Days = append(result.Days, day)
+		total += len(day.Entries)
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("Found %d entries on %d earlier dates", total, len(result.Days))
+	return nil, result, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"dailylog/internal/config"
+)
+
+// profileNamePattern is what a profile name may be, as it becomes part of
+// tool names
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// profileServer serves a profile's log, or with no profiles, the configured one
+type profileServer struct {
+	name   string // "" without profiles
+	server *Server
+}
+
+// openProfiles returns a copy of base for each profile under profiles in
+// cfg, over the storage its settings configure, or with none, base over the
+// configured storage. Profiles keep logs apart, e.g. work and personal, so
+// an agent can't write to one meaning the other. The function returned
+// closes the storage.
+func openProfiles(cfg *viper.Viper, configPath string, readOnly bool, base *Server) ([]profileServer, func()) {
+	names := config.Profiles(cfg)
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	var profiles []profileServer
+	var closers []func()
+	for _, name := range names {
+		settings := cfg
+		if name != "" {
+			if !profileNamePattern.MatchString(name) {
+				fatalf("Invalid profile name %q: use lowercase letters, digits, - and _", name)
+			}
+			var err error
+			if settings, err = config.Profile(cfg, name); err != nil {
+				fatalf("Invalid profile: %v", err)
+			}
+		}
+
+		server := *base
+		var closeStorage func()
+		server.storage, server.commitMessages, closeStorage = openStorage(settings, configPath, readOnly)
+		storageConfig := config.Storage(settings)
+		server.focus, server.tags = storageConfig.Focus, storageConfig.TagAliases
+		server.hooks = entryHooks(settings)
+		profiles = append(profiles, profileServer{name: name, server: &server})
+		closers = append(closers, closeStorage)
+		if name != "" {
+			slog.Info("Serving profile", "profile", name, "storage", storageConfig.StorageType, "repo", storageConfig.GitHubRepo)
+		}
+	}
+	return profiles, func() {
+		for _, closeStorage := range closers {
+			closeStorage()
+		}
+	}
+}
+
+// profileToolName namespaces a tool name by profile, e.g. dailylog_entry
+// becomes dailylog_work_entry
+func profileToolName(name, profile string) string {
+	if profile == "" {
+		return name
+	}
+	if rest, ok := strings.CutPrefix(name, "dailylog_"); ok {
+		return "dailylog_" + profile + "_" + rest
+	}
+	return profile + "_" + name
+}
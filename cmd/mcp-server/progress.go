@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/storage"
+)
+
+// progressInterval is the least time between progress notifications for a call
+const progressInterval = 250 * time.Millisecond
+
+// progressNotifier returns a progress function that sends MCP progress
+// notifications for the days a tool call has read, so clients can show
+// progress through long range scans. It returns nil when the client did not
+// ask for progress with a progress token.
+func progressNotifier(ctx context.Context, req *mcp.CallToolRequest, operation string) storage.ProgressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+
+	var last time.Time
+	return func(done, total int) {
+		// Always send the last one, so clients see the scan finish
+		if done < total && time.Since(last) < progressInterval {
+			return
+		}
+		last = time.Now()
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(done),
+			Total:         float64(total),
+			Message:       fmt.Sprintf("%s: read %d of %d days", operation, done, total),
+		})
+		if err != nil {
+			slog.Warn("Failed to send progress notification", "error", err)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// anyTool keys the rate limit for tools without one of their own
+const anyTool = "*"
+
+// rateLimit allows calls per period
+type rateLimit struct {
+	calls  int
+	period time.Duration
+}
+
+func (l rateLimit) String() string {
+	switch l.period {
+	case time.Second:
+		return fmt.Sprintf("%d calls per second", l.calls)
+	case time.Minute:
+		return fmt.Sprintf("%d calls per minute", l.calls)
+	case time.Hour:
+		return fmt.Sprintf("%d calls per hour", l.calls)
+	}
+	return fmt.Sprintf("%d calls per %s", l.calls, l.period)
+}
+
+// parseRateLimits parses "tool=calls/period" pairs separated by commas, e.g.
+// "*=60/m,dailylog_entry=10/m". Periods are s, m, h, or a duration such as
+// 10m; "*" sets the limit for tools without one of their own.
+func parseRateLimits(list string) (map[string]rateLimit, error) {
+	limits := make(map[string]rateLimit)
+	for _, pair := range strings.Split(list, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		tool, spec, ok := strings.Cut(pair, "=")
+		calls, per, ok2 := strings.Cut(spec, "/")
+		tool = strings.TrimSpace(tool)
+		if !ok || !ok2 || tool == "" {
+			return nil, fmt.Errorf("%q is not tool=calls/period", strings.TrimSpace(pair))
+		}
+		limit := rateLimit{}
+		var err error
+		if limit.calls, err = strconv.Atoi(strings.TrimSpace(calls)); err != nil || limit.calls < 1 {
+			return nil, fmt.Errorf("%q: calls must be a positive number", strings.TrimSpace(pair))
+		}
+		switch per = strings.TrimSpace(per); per {
+		case "s":
+			limit.period = time.Second
+		case "m":
+			limit.period = time.Minute
+		case "h":
+			limit.period = time.Hour
+		default:
+			if limit.period, err = time.ParseDuration(per); err != nil || limit.period <= 0 {
+				return nil, fmt.Errorf("%q: period must be s, m, h, or a duration such as 10m", strings.TrimSpace(pair))
+			}
+		}
+		limits[tool] = limit
+	}
+	return limits, nil
+}
+
+// rateLimiter caps how often each tool can be called, over a sliding window
+type rateLimiter struct {
+	limits map[string]rateLimit
+
+	mu    sync.Mutex
+	calls map[string][]time.Time // Recent calls by tool, oldest first
+}
+
+func newRateLimiter(limits map[string]rateLimit) *rateLimiter {
+	return &rateLimiter{limits: limits, calls: make(map[string][]time.Time)}
+}
+
+// allow records a call of tool at now if its limit allows it, and otherwise
+// returns the limit and how long until it would
+func (r *rateLimiter) allow(tool string, now time.Time) (bool, rateLimit, time.Duration) {
+	limit, ok := r.limits[tool]
+	if !ok {
+		if limit, ok = r.limits[anyTool]; !ok {
+			return true, rateLimit{}, 0
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	recent := r.calls[tool]
+	for len(recent) > 0 && now.Sub(recent[0]) >= limit.period {
+		recent = recent[1:]
+	}
+	if len(recent) >= limit.calls {
+		r.calls[tool] = recent
+		return false, limit, recent[0].Add(limit.period).Sub(now)
+	}
+	r.calls[tool] = append(recent, now)
+	return true, limit, 0
+}
+
+// middleware refuses tool calls over their rate limit
+func (r *rateLimiter) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok {
+			return next(ctx, method, req)
+		}
+		allowed, limit, wait := r.allow(call.Params.Name, time.Now())
+		if allowed {
+			return next(ctx, method, req)
+		}
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+				"%s: %s is limited to %s; try again in %s",
+				errorRateLimited, call.Params.Name, limit, wait.Round(time.Second))}},
+		}, nil
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/sentiment"
+	"dailylog/internal/storage"
+)
+
+// samplingMaxTokens caps each reply requested from the client's model
+const samplingMaxTokens = 1024
+
+// samplingModel keys cached results written through sampling, whichever
+// model the client picked
+const samplingModel = "mcp-sampling"
+
+// samplingSystemPrompt is sent with every sampling request
+const samplingSystemPrompt = "You help the writer of a personal daily log reflect on it. " +
+	"Answer from the log entries given, without inventing events, and reply with the requested text only."
+
+// forRequest returns the server to handle a tool call with, its storage
+// used under ctx so it is traced as part of the call. When sampling is
+// enabled and the client supports it, AI features use the client's model
+// through sampling, so no API key is needed; otherwise they use the
+// configured AI provider, if any.
+func (s *Server) forRequest(ctx context.Context, req *mcp.CallToolRequest) *Server {
+	scoped := *s
+	scoped.storage = storage.WithContext(ctx, s.storage)
+	if !s.sampling || req == nil || !supportsSampling(req.Session) {
+		return &scoped
+	}
+	provider := &samplingProvider{ctx: ctx, session: req.Session, fallback: s.ai}
+	scoped.ai = provider
+	scoped.aiModel = samplingModel
+	if s.sentimentAI {
+		scoped.sentiment = &samplingSentiment{provider: provider, fallback: s.sentiment}
+	}
+	return &scoped
+}
+
+// samplingSentiment asks the client's model for the sentiment of text.
+// Requests the client fails or declines go to fallback.
+type samplingSentiment struct {
+	provider *samplingProvider
+	fallback sentiment.Analyzer
+}
+
+// Analyze labels text positive, negative, or neutral
+func (a *samplingSentiment) Analyze(text string) (sentiment.Result, error) {
+	answer, err := a.provider.createMessage("Is the feeling of this log entry positive, negative, or neutral? " +
+		"Reply with one of those words.\n\n" + text)
+	if err == nil {
+		if result, ok := sentiment.FromLabel(answer); ok {
+			return result, nil
+		}
+		err = fmt.Errorf("the model answered %q, not a sentiment", answer)
+	}
+	if a.fallback != nil {
+		return a.fallback.Analyze(text)
+	}
+	return sentiment.Result{}, err
+}
+
+// supportsSampling reports whether the client declared the sampling capability
+func supportsSampling(session *mcp.ServerSession) bool {
+	if session == nil {
+		return false
+	}
+	params := session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Sampling != nil
+}
+
+// samplingProvider is an AI provider that asks the MCP client's model through
+// sampling/createMessage. Requests the client fails or declines go to
+// fallback, the configured provider, when there is one.
+type samplingProvider struct {
+	ctx      context.Context
+	session  *mcp.ServerSession
+	fallback storage.AIProvider
+}
+
+// GenerateSummary writes from the prompt and the entries
+func (p *samplingProvider) GenerateSummary(entries []storage.DailyLogEntry, prompt string) (string, error) {
+	result, err := p.createMessage(prompt + "\n\nLog entries:\n" + formatEntries(entries))
+	if err != nil && p.fallback != nil {
+		return p.fallback.GenerateSummary(entries, prompt)
+	}
+	return result, err
+}
+
+// SuggestTags suggests tags for a description
+func (p *samplingProvider) SuggestTags(description string) ([]string, error) {
+	result, err := p.createMessage("Suggest up to five short, lowercase tags for this log entry, " +
+		"as a comma-separated list:\n\n" + description)
+	if err != nil {
+		if p.fallback != nil {
+			return p.fallback.SuggestTags(description)
+		}
+		return nil, err
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(result, ",") {
+		tag = strings.ToLower(strings.Trim(strings.TrimSpace(tag), "#.\"'"))
+		if tag != "" && !contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// AnalyzeStatus describes how the writer's status went in the entries
+func (p *samplingProvider) AnalyzeStatus(entries []storage.DailyLogEntry) (map[string]any, error) {
+	result, err := p.createMessage("Describe how the writer's status (rated 1-10) went in these log entries " +
+		"and what seems to affect it, in a short paragraph.\n\nLog entries:\n" + formatEntries(entries))
+	if err != nil {
+		if p.fallback != nil {
+			return p.fallback.AnalyzeStatus(entries)
+		}
+		return nil, err
+	}
+	return map[string]any{"analysis": result}, nil
+}
+
+// GenerateInsights describes patterns across the days
+func (p *samplingProvider) GenerateInsights(dayLogs []storage.DayLog) (string, error) {
+	var entries []storage.DailyLogEntry
+	for _, dayLog := range dayLogs {
+		entries = append(entries, dayLog.Entries...)
+	}
+	result, err := p.createMessage("Point out patterns in how the writer spent these days and how they went, " +
+		"in a few bullet points.\n\nLog entries:\n" + formatEntries(entries))
+	if err != nil && p.fallback != nil {
+		return p.fallback.GenerateInsights(dayLogs)
+	}
+	return result, err
+}
+
+// ImproveWording rewrites text to read more clearly
+func (p *samplingProvider) ImproveWording(text string) (string, error) {
+	result, err := p.createMessage("Rewrite this log entry to read more clearly and concisely, " +
+		"keeping its meaning and first-person voice:\n\n" + text)
+	if err != nil && p.fallback != nil {
+		return p.fallback.ImproveWording(text)
+	}
+	return result, err
+}
+
+// createMessage asks the client's model for a reply to one user message
+func (p *samplingProvider) createMessage(text string) (string, error) {
+	result, err := p.session.CreateMessage(p.ctx, &mcp.CreateMessageParams{
+		Messages:     []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: text}}},
+		SystemPrompt: samplingSystemPrompt,
+		MaxTokens:    samplingMaxTokens,
+	})
+	if err != nil {
+		slog.Warn("Sampling request failed", "error", err)
+		return "", fmt.Errorf("sampling failed: %v", err)
+	}
+	content, ok := result.Content.(*mcp.TextContent)
+	if !ok || strings.TrimSpace(content.Text) == "" {
+		return "", fmt.Errorf("sampling returned no text")
+	}
+	return strings.TrimSpace(content.Text), nil
+}
+
+// formatEntries lists entries one per line for a prompt
+func formatEntries(entries []storage.DailyLogEntry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "- %s [%s] %s", entry.Timestamp.Format("2006-01-02 15:04"), entry.Type, entry.Title)
+		var details []string
+		if entry.Status > 0 {
+			details = append(details, fmt.Sprintf("status %d/10", entry.Status))
+		}
+		if len(entry.Tags) > 0 {
+			details = append(details, "tags: "+strings.Join(entry.Tags, ", "))
+		}
+		if entry.Location != "" {
+			details = append(details, "at "+entry.Location)
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(details, "; "))
+		}
+		if description := strings.TrimSpace(entry.Description); description != "" {
+			fmt.Fprintf(&b, ": %s", strings.Join(strings.Fields(description), " "))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultShutdownTimeout is how long tool calls in flight get to finish
+// when the server is stopped
+const defaultShutdownTimeout = 10 * time.Second
+
+// drainer tracks tool calls in flight so shutdown can wait for them, and
+// refuses new ones once shutdown has started
+type drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight sync.WaitGroup
+}
+
+// middleware counts tool calls in flight and refuses them while draining
+func (d *drainer) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if _, ok := req.(*mcp.CallToolRequest); !ok {
+			return next(ctx, method, req)
+		}
+		d.mu.Lock()
+		if d.draining {
+			d.mu.Unlock()
+			return &mcp.CallToolResult{
+				IsError: true,
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(
+					"%s: the server is shutting down", errorShuttingDown)}},
+			}, nil
+		}
+		d.inFlight.Add(1)
+		d.mu.Unlock()
+		defer d.inFlight.Done()
+		return next(ctx, method, req)
+	}
+}
+
+// drain refuses new tool calls and waits up to timeout for those in flight,
+// reporting whether they all finished
+func (d *drainer) drain(timeout time.Duration) bool {
+	d.mu.Lock()
+	d.draining = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inFlight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
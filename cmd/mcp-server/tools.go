@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// writeTools are the tools that change the log, left out in read-only mode
+var writeTools = map[string]bool{
+	"dailylog_entry":           true,
+	"dailylog_move_entry":      true,
+	"dailylog_extract_actions": true,
+}
+
+// toolExposure decides which tools are registered and under what names, for
+// clients with limits on the number of tools or conventions for naming them.
+// Tools are configured by their built-in names.
+type toolExposure struct {
+	// only, if not empty, lists the only tools to register
+	only map[string]bool
+	// disabled lists tools not to register
+	disabled map[string]bool
+	// names maps built-in names to the names tools are registered under
+	names map[string]string
+	// readOnly leaves out writeTools
+	readOnly bool
+
+	known      map[string]bool   // Built-in names of the tools seen
+	registered map[string]string // Built-in names by registered name
+	conflicts  []string
+}
+
+// newToolExposure parses the tool lists, comma-separated names, and renames,
+// "name=alias" pairs separated by commas
+func newToolExposure(only, disabled, renames string, readOnly bool) (*toolExposure, error) {
+	t := &toolExposure{
+		only:       nameSet(only),
+		disabled:   nameSet(disabled),
+		names:      make(map[string]string),
+		readOnly:   readOnly,
+		known:      make(map[string]bool),
+		registered: make(map[string]string),
+	}
+	for _, pair := range strings.Split(renames, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, alias, ok := strings.Cut(pair, "=")
+		name, alias = strings.TrimSpace(name), strings.TrimSpace(alias)
+		if !ok || name == "" || alias == "" {
+			return nil, fmt.Errorf("%q is not name=alias", strings.TrimSpace(pair))
+		}
+		t.names[name] = alias
+	}
+	return t, nil
+}
+
+func nameSet(list string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// enabled reports whether the tool with the built-in name is registered
+func (t *toolExposure) enabled(name string) bool {
+	switch {
+	case t.readOnly && writeTools[name]:
+		return false
+	case len(t.only) > 0 && !t.only[name]:
+		return false
+	}
+	return !t.disabled[name]
+}
+
+// addTool registers tool with the server unless it is disabled, under its
+// alias if it has one, namespaced by profile if there is one
+func addTool[In, Out any](server *mcp.Server, t *toolExposure, profile string, tool *mcp.Tool, handler mcp.ToolHandlerFor[In, Out]) {
+	t.known[tool.Name] = true
+	if !t.enabled(tool.Name) {
+		return
+	}
+	name := tool.Name
+	if alias, ok := t.names[name]; ok || profile != "" {
+		if !ok {
+			alias = name
+		}
+		renamed := *tool
+		renamed.Name = profileToolName(alias, profile)
+		if profile != "" {
+			renamed.Description = "In the " + profile + " log: " + tool.Description
+		}
+		tool = &renamed
+	}
+	if other, ok := t.registered[tool.Name]; ok {
+		t.conflicts = append(t.conflicts, fmt.Sprintf("%s and %s are both named %s", other, name, tool.Name))
+		return
+	}
+	t.registered[tool.Name] = name
+	mcp.AddTool(server, tool, handler)
+}
+
+// check reports configuration that names tools that don't exist, gives two
+// tools the same name, or leaves none; call it once every tool has been added
+func (t *toolExposure) check() error {
+	var unknown []string
+	for _, names := range []map[string]bool{t.only, t.disabled} {
+		for name := range names {
+			if !t.known[name] {
+				unknown = append(unknown, name)
+			}
+		}
+	}
+	for name := range t.names {
+		if !t.known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown tools: %s", strings.Join(unknown, ", "))
+	}
+	if len(t.conflicts) > 0 {
+		return fmt.Errorf("%s", strings.Join(t.conflicts, "; "))
+	}
+	if len(t.registered) == 0 {
+		return fmt.Errorf("no tools are enabled")
+	}
+	return nil
+}
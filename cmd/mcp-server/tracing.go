@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"dailylog/internal/tracing"
+)
+
+// tracingMiddleware starts a span for each tool call, which the storage
+// and GitHub API spans of the call are recorded under (see forRequest)
+func tracingMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		call, ok := req.(*mcp.CallToolRequest)
+		if !ok {
+			return next(ctx, method, req)
+		}
+
+		ctx, span := tracing.Start(ctx, method+" "+call.Params.Name, trace.SpanKindServer,
+			attribute.String("mcp.method.name", method),
+			attribute.String("gen_ai.tool.name", call.Params.Name))
+		result, err := next(ctx, method, req)
+		if toolResult, ok := result.(*mcp.CallToolResult); ok && err == nil && toolResult.IsError {
+			span.SetStatus(codes.Error, "tool call failed")
+		}
+		tracing.End(span, err)
+		return result, err
+	}
+}
+
+// setupTracing exports spans when an OTLP endpoint is configured, and
+// returns a function flushing them before exit
+func setupTracing() func() {
+	shutdown, err := tracing.Setup(context.Background(), "dailylog-mcp")
+	if err != nil {
+		fatalf("Failed to set up tracing: %v", err)
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			slog.Warn("Failed to export spans", "error", err)
+		}
+	}
+}
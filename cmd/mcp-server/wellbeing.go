@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"dailylog/internal/storage"
+)
+
+// WellbeingCheckInput defines parameters for checking a week's burnout risk
+type WellbeingCheckInput struct {
+	Date string `json:"date,omitempty" jsonschema:"A date in the week to check in YYYY-MM-DD format (defaults to today)"`
+}
+
+// WellbeingCheckOutput defines the response for checking a week's burnout risk
+type WellbeingCheckOutput struct {
+	WeekStart string                    `json:"week_start" jsonschema:"First day (Monday) of the week checked"`
+	WeekEnd   string                    `json:"week_end" jsonschema:"Last day of the week checked"`
+	Risk      string                    `json:"risk" jsonschema:"Burnout risk: low, moderate, or high"`
+	Score     int                       `json:"score" jsonschema:"How many of the four warning signals fired"`
+	Signals   []storage.WellbeingSignal `json:"signals" jsonschema:"Each signal: the week's figure against its usual weekly value over the four weeks before, and whether it fired"`
+	Success   bool                      `json:"success" jsonschema:"Whether operation was successful"`
+	Message   string                    `json:"message,omitempty" jsonschema:"Success or error message"`
+	ErrorCode string                    `json:"error_code,omitempty" jsonschema:"Machine-readable failure reason: invalid_date, not_found, rate_limited, storage_unavailable, or validation"`
+}
+
+// WellbeingCheck implements the dailylog_wellbeing_check tool
+func (s *Server) WellbeingCheck(ctx context.Context, req *mcp.CallToolRequest, input WellbeingCheckInput) (
+	*mcp.CallToolResult,
+	WellbeingCheckOutput,
+	error,
+) {
+	logToolCall("WellbeingCheck", input)
+	s = s.forRequest(ctx, req)
+
+	date := time.Now()
+	if input.Date != "" {
+		var err error
+		date, err = time.Parse("2006-01-02", input.Date)
+		if err != nil {
+			return nil, WellbeingCheckOutput{
+				Success:   false,
+				Message:   fmt.Sprintf("Invalid date format: %s", input.Date),
+				ErrorCode: errorInvalidDate,
+			}, nil
+		}
+	}
+
+	weekStart := date.AddDate(0, 0, -(int(date.Weekday())+6)%7)
+	start, end := storage.WellbeingWindow(weekStart)
+	days, err := s.storage.GetDateRange(start, end)
+	if err != nil {
+		return nil, WellbeingCheckOutput{
+			Success:   false,
+			Message:   fmt.Sprintf("Failed to get entries: %v", err),
+			ErrorCode: errorCode(err),
+		}, nil
+	}
+	var entries []storage.DailyLogEntry
+	for _, day := range days {
+		entries = append(entries, day.Entries...)
+	}
+
+	check := storage.CheckWellbeing(entries, weekStart, s.focus, s.tags)
+	return nil, WellbeingCheckOutput{
+		WeekStart: check.WeekStart,
+		WeekEnd:   check.WeekEnd,
+		Risk:      check.Risk,
+		Score:     check.Score,
+		Signals:   check.Signals,
+		Success:   true,
+		Message:   check.String(),
+	}, nil
+}
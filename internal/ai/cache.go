@@ -0,0 +1,99 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"dailylog/internal/metrics"
+	"dailylog/internal/storage"
+)
+
+// CachedProvider caches GenerateSummary results on disk, keyed on the model,
+// the prompt, and the content of the entries, so summarizing a period that
+// hasn't changed doesn't make another request. Other methods pass through.
+type CachedProvider struct {
+	storage.AIProvider
+
+	dir   string
+	model string
+
+	// Refresh regenerates results instead of reading them, updating the cache
+	Refresh bool
+}
+
+// cachedSummary is one cached result
+type cachedSummary struct {
+	Model     string    `json:"model,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Summary   string    `json:"summary"`
+}
+
+// NewCachedProvider caches provider's summaries in dir. model names the model
+// behind provider, so switching models doesn't reuse old results.
+func NewCachedProvider(provider storage.AIProvider, dir, model string) *CachedProvider {
+	return &CachedProvider{AIProvider: provider, dir: dir, model: model}
+}
+
+// cacheLookups counts summaries served from the cache and generated afresh
+var cacheLookups = metrics.Default.NewCounter("dailylog_ai_cache_lookups_total",
+	"AI summary cache lookups, by result (hit or miss).", "result")
+
+// DefaultCacheDir returns the default cache directory, ~/.dailyctl/cache/ai
+func DefaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".dailyctl", "cache", "ai")
+}
+
+// GenerateSummary returns the cached summary for the same model, prompt, and
+// entries, or generates and caches it. Cache failures only cost a request.
+func (c *CachedProvider) GenerateSummary(entries []storage.DailyLogEntry, prompt string) (string, error) {
+	key, err := c.key(entries, prompt)
+	if err != nil {
+		return c.AIProvider.GenerateSummary(entries, prompt)
+	}
+	path := filepath.Join(c.dir, key+".json")
+
+	if !c.Refresh {
+		var cached cachedSummary
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &cached) == nil {
+			cacheLookups.Inc("hit")
+			return cached.Summary, nil
+		}
+		cacheLookups.Inc("miss")
+	}
+
+	summary, err := c.AIProvider.GenerateSummary(entries, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(cachedSummary{Model: c.model, CreatedAt: time.Now(), Summary: summary})
+	if err == nil && os.MkdirAll(c.dir, 0o700) == nil {
+		_ = os.WriteFile(path, data, 0o600)
+	}
+	return summary, nil
+}
+
+// key hashes everything the result depends on
+func (c *CachedProvider) key(entries []storage.DailyLogEntry, prompt string) (string, error) {
+	content, err := json.Marshal(entries)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	for _, part := range [][]byte{[]byte(c.model), []byte(prompt), content} {
+		// Length-prefix each part so different splits can't collide
+		_ = binary.Write(hash, binary.BigEndian, uint64(len(part)))
+		_, _ = hash.Write(part)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
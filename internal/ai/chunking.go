@@ -0,0 +1,130 @@
+// Package ai holds helpers for sending log entries to an AI provider.
+package ai
+
+import (
+	"fmt"
+	"strings"
+
+	"dailylog/internal/storage"
+)
+
+// DefaultTokenBudget is the default size of one request to the AI provider, prompt included
+const DefaultTokenBudget = 8000
+
+// minChunkTokens keeps chunks useful when the prompt takes up most of the budget
+const minChunkTokens = 500
+
+// entryOverheadTokens accounts for the field names and punctuation around each entry
+const entryOverheadTokens = 12
+
+// EstimateTokens approximates the tokens in text at four characters a token,
+// which is close enough for English to keep requests under a budget
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// EntryTokens estimates how many tokens an entry takes up in a request
+func EntryTokens(entry storage.DailyLogEntry) int {
+	text := entry.Type + entry.Title + entry.Description + entry.Location + strings.Join(entry.Tags, ",")
+	return EstimateTokens(text) + entryOverheadTokens
+}
+
+// Chunk splits entries, in order, into chunks of at most budget estimated
+// tokens. An entry larger than the budget gets a chunk of its own.
+func Chunk(entries []storage.DailyLogEntry, budget int) [][]storage.DailyLogEntry {
+	var chunks [][]storage.DailyLogEntry
+	var current []storage.DailyLogEntry
+	size := 0
+	for _, entry := range entries {
+		tokens := EntryTokens(entry)
+		if len(current) > 0 && size+tokens > budget {
+			chunks = append(chunks, current)
+			current, size = nil, 0
+		}
+		current = append(current, entry)
+		size += tokens
+	}
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+	return chunks
+}
+
+// MapReduce summarizes more entries than fit in one request. Entries are
+// split into chunks that fit the budget alongside the prompt, each chunk is
+// summarized on its own, and the partial summaries are combined with the
+// reduce prompt, in further rounds if they don't fit in one request either.
+type MapReduce struct {
+	Provider storage.AIProvider
+	// Budget is the estimated tokens allowed per request, prompt included
+	Budget int
+	// Progress, if set, is told about each request made for a chunk
+	Progress func(format string, args ...any)
+}
+
+// Summarize summarizes entries with prompt, combining partial summaries with reducePrompt
+func (m MapReduce) Summarize(entries []storage.DailyLogEntry, prompt, reducePrompt string) (string, error) {
+	chunks := Chunk(entries, m.chunkBudget(prompt))
+	if len(chunks) <= 1 {
+		return m.Provider.GenerateSummary(entries, prompt)
+	}
+
+	partials, err := m.summarizeChunks("chunk", chunks, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	for round := 1; ; round++ {
+		chunks = Chunk(partials, m.chunkBudget(reducePrompt))
+		// Combine in one request once they fit, or when grouping no longer shrinks them
+		if len(chunks) <= 1 || len(chunks) == len(partials) {
+			m.progress("Combining %d partial summaries", len(partials))
+			return m.Provider.GenerateSummary(partials, reducePrompt)
+		}
+		partials, err = m.summarizeChunks(fmt.Sprintf("reduce round %d, group", round), chunks, reducePrompt)
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// summarizeChunks summarizes each chunk, returning the summaries as entries
+// so they can be combined like any other entries
+func (m MapReduce) summarizeChunks(label string, chunks [][]storage.DailyLogEntry, prompt string) ([]storage.DailyLogEntry, error) {
+	partials := make([]storage.DailyLogEntry, 0, len(chunks))
+	for i, chunk := range chunks {
+		m.progress("Summarizing %s %d of %d (%d entries)", label, i+1, len(chunks), len(chunk))
+		summary, err := m.Provider.GenerateSummary(chunk, prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize %s %d of %d: %v", label, i+1, len(chunks), err)
+		}
+
+		first, last := chunk[0].Timestamp, chunk[len(chunk)-1].Timestamp
+		partials = append(partials, storage.DailyLogEntry{
+			Timestamp:   first,
+			Type:        "summary",
+			Title:       fmt.Sprintf("Part %d of %d: %s to %s", i+1, len(chunks), first.Format("2006-01-02"), last.Format("2006-01-02")),
+			Description: summary,
+		})
+	}
+	return partials, nil
+}
+
+// chunkBudget is what remains of the budget for entries once prompt is sent
+func (m MapReduce) chunkBudget(prompt string) int {
+	budget := m.Budget
+	if budget <= 0 {
+		budget = DefaultTokenBudget
+	}
+	budget -= EstimateTokens(prompt)
+	if budget < minChunkTokens {
+		return minChunkTokens
+	}
+	return budget
+}
+
+func (m MapReduce) progress(format string, args ...any) {
+	if m.Progress != nil {
+		m.Progress(format, args...)
+	}
+}
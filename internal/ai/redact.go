@@ -0,0 +1,197 @@
+package ai
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"dailylog/internal/storage"
+)
+
+// DefaultReplacement stands in for redacted text when a rule sets none
+const DefaultReplacement = "[redacted]"
+
+// RedactionRule replaces text matching a regular expression, or any of a
+// list of keywords, before entries are sent to an AI provider. Keywords match
+// whole words, ignoring case.
+type RedactionRule struct {
+	Name        string   `json:"name,omitempty" yaml:"name,omitempty" mapstructure:"name"`
+	Pattern     string   `json:"pattern,omitempty" yaml:"pattern,omitempty" mapstructure:"pattern"`
+	Keywords    []string `json:"keywords,omitempty" yaml:"keywords,omitempty" mapstructure:"keywords"`
+	Replacement string   `json:"replacement,omitempty" yaml:"replacement,omitempty" mapstructure:"replacement"`
+}
+
+// compiledRule is a rule ready to apply
+type compiledRule struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Redactor applies redaction rules, in order, to text bound for an AI provider
+type Redactor struct {
+	rules []compiledRule
+}
+
+// NewRedactor compiles the rules
+func NewRedactor(rules []RedactionRule) (*Redactor, error) {
+	r := &Redactor{}
+	for i, rule := range rules {
+		name := rule.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+
+		var patterns []string
+		if rule.Pattern != "" {
+			patterns = append(patterns, rule.Pattern)
+		}
+		for _, keyword := range rule.Keywords {
+			if keyword = strings.TrimSpace(keyword); keyword != "" {
+				patterns = append(patterns, keywordPattern(keyword))
+			}
+		}
+		if len(patterns) == 0 {
+			return nil, storage.ValidationError{Field: "redaction " + name, Message: "needs a pattern or keywords"}
+		}
+
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = DefaultReplacement
+		}
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, storage.ValidationError{Field: "redaction " + name, Message: err.Error()}
+			}
+			r.rules = append(r.rules, compiledRule{re: re, replacement: replacement})
+		}
+	}
+	return r, nil
+}
+
+// keywordPattern matches keyword as a whole word, ignoring case. Word
+// boundaries are only required next to letters and digits, so keywords such
+// as "C++" or "$100" still match.
+func keywordPattern(keyword string) string {
+	pattern := regexp.QuoteMeta(keyword)
+	if isWordChar(keyword[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordChar(keyword[len(keyword)-1]) {
+		pattern += `\b`
+	}
+	return "(?i)" + pattern
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// LoadRedactionRules reads a YAML list of redaction rules from a file
+func LoadRedactionRules(path string) ([]RedactionRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redaction rules: %v", err)
+	}
+	var rules []RedactionRule
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse redaction rules %s: %v", path, err)
+	}
+	return rules, nil
+}
+
+// Redact returns text with every rule applied and how many replacements were made
+func (r *Redactor) Redact(text string) (string, int) {
+	count := 0
+	for _, rule := range r.rules {
+		text = rule.re.ReplaceAllStringFunc(text, func(string) string {
+			count++
+			return rule.replacement
+		})
+	}
+	return text, count
+}
+
+// RedactEntries returns redacted copies of the entries' free text: title,
+// description, location, tags, and metadata values
+func (r *Redactor) RedactEntries(entries []storage.DailyLogEntry) ([]storage.DailyLogEntry, int) {
+	total := 0
+	redact := func(text string) string {
+		redacted, count := r.Redact(text)
+		total += count
+		return redacted
+	}
+
+	redacted := make([]storage.DailyLogEntry, len(entries))
+	for i, entry := range entries {
+		entry.Title = redact(entry.Title)
+		entry.Description = redact(entry.Description)
+		entry.Location = redact(entry.Location)
+		if len(entry.Tags) > 0 {
+			tags := make([]string, len(entry.Tags))
+			for j, tag := range entry.Tags {
+				tags[j] = redact(tag)
+			}
+			entry.Tags = tags
+		}
+		if len(entry.Metadata) > 0 {
+			metadata := make(map[string]string, len(entry.Metadata))
+			for key, value := range entry.Metadata {
+				metadata[key] = redact(value)
+			}
+			entry.Metadata = metadata
+		}
+		redacted[i] = entry
+	}
+	return redacted, total
+}
+
+// RedactingProvider redacts everything passed to the AI provider it wraps
+type RedactingProvider struct {
+	provider storage.AIProvider
+	redactor *Redactor
+}
+
+// NewRedactingProvider wraps provider so that it only sees redacted text
+func NewRedactingProvider(provider storage.AIProvider, redactor *Redactor) *RedactingProvider {
+	return &RedactingProvider{provider: provider, redactor: redactor}
+}
+
+// GenerateSummary summarizes redacted entries with a redacted prompt
+func (p *RedactingProvider) GenerateSummary(entries []storage.DailyLogEntry, prompt string) (string, error) {
+	redacted, _ := p.redactor.RedactEntries(entries)
+	prompt, _ = p.redactor.Redact(prompt)
+	return p.provider.GenerateSummary(redacted, prompt)
+}
+
+// SuggestTags suggests tags for a redacted description
+func (p *RedactingProvider) SuggestTags(description string) ([]string, error) {
+	description, _ = p.redactor.Redact(description)
+	return p.provider.SuggestTags(description)
+}
+
+// AnalyzeStatus analyzes redacted entries
+func (p *RedactingProvider) AnalyzeStatus(entries []storage.DailyLogEntry) (map[string]any, error) {
+	redacted, _ := p.redactor.RedactEntries(entries)
+	return p.provider.AnalyzeStatus(redacted)
+}
+
+// GenerateInsights generates insights from days with redacted entries
+func (p *RedactingProvider) GenerateInsights(dayLogs []storage.DayLog) (string, error) {
+	redacted := make([]storage.DayLog, len(dayLogs))
+	for i, dayLog := range dayLogs {
+		dayLog.Entries, _ = p.redactor.RedactEntries(dayLog.Entries)
+		dayLog.DaySummary, _ = p.redactor.Redact(dayLog.DaySummary)
+		redacted[i] = dayLog
+	}
+	return p.provider.GenerateInsights(redacted)
+}
+
+// ImproveWording improves redacted text
+func (p *RedactingProvider) ImproveWording(text string) (string, error) {
+	text, _ = p.redactor.Redact(text)
+	return p.provider.ImproveWording(text)
+}
@@ -0,0 +1,394 @@
+// Package config resolves settings the same way for dailyctl and the MCP
+// server: from flags, then DAILYLOG_* environment variables, then the
+// configuration file, then, for secrets, the system keychain.
+//
+// Settings have dotted keys such as github.repo. Each can be set with the
+// environment variable named by EnvName, DAILYLOG_GITHUB_REPO for
+// github.repo, or in a YAML configuration file, nested or not.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// EnvPrefix starts every environment variable name
+const EnvPrefix = "DAILYLOG"
+
+// ContainerPath is where a configuration file is read from when there is
+// none in the home or current directory, e.g. mounted into a container
+const ContainerPath = "/etc/dailylog/config.yaml"
+
+// DefaultGitHubPath is the directory in the repository holding the logs
+const DefaultGitHubPath = "logs"
+
+// envNames are the environment variables of keys whose names don't follow
+// from the key
+var envNames = map[string]string{
+	"duplicates.mode":   "DAILYLOG_DUPLICATES",
+	"duplicates.window": "DAILYLOG_DUPLICATE_WINDOW",
+	"github.day_format": "DAILYLOG_DAY_FORMAT",
+	"github.layout":     "DAILYLOG_LAYOUT",
+	"locations.aliases": "DAILYLOG_LOCATION_ALIASES",
+	"privacy.tags":      "DAILYLOG_PRIVATE_TAGS",
+	"sentiment.enabled": "DAILYLOG_SENTIMENT",
+	"tags.aliases":      "DAILYLOG_TAG_ALIASES",
+	"weather.enabled":   "DAILYLOG_WEATHER",
+}
+
+// Secrets are the keys that can also be read from a file named by their
+// environment variable with a _FILE suffix, such as a Docker or Kubernetes
+// secret, or from the system keychain
+var Secrets = []string{
+	"github.token",
+	"mirror.token",
+	"http_token",
+	"smtp.password",
+	"caldav.password",
+	"gcal.token",
+	"jira.token",
+	"slack.signing_secret",
+	"email_in.token",
+	"capture.token",
+	"serve.token",
+	"telegram.token",
+	"toggl.token",
+	"clockify.token",
+	"transcribe.api_key",
+}
+
+// EnvName returns the environment variable that sets key
+func EnvName(key string) string {
+	if name, ok := envNames[key]; ok {
+		return name
+	}
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Load sets v up to read the environment and the configuration file at
+// path, or if path is empty, the one named by DAILYLOG_CONFIG or the first
+// found of ~/.dailyctl.yaml, ./.dailyctl.yaml, and ContainerPath. It returns
+// the file read, or "" if there was none.
+func Load(v *viper.Viper, path string) (string, error) {
+	readEnv(v)
+	v.SetDefault("github.path", DefaultGitHubPath)
+	v.SetDefault("storage.type", storage.StorageTypeGitHub)
+
+	if err := readSecretFiles(); err != nil {
+		return "", err
+	}
+
+	if path == "" {
+		path = os.Getenv(EnvPrefix + "_CONFIG")
+	}
+	if path == "" {
+		path = findFile()
+	}
+	if path != "" {
+		v.SetConfigFile(path)
+		v.SetConfigType("yaml")
+		if err := v.ReadInConfig(); err != nil {
+			var notFound viper.ConfigFileNotFoundError
+			if errors.As(err, &notFound) || errors.Is(err, fs.ErrNotExist) {
+				return "", fmt.Errorf("configuration file %s not found", path)
+			}
+			return "", fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	if v.GetBool("keychain") {
+		readKeychain(v)
+	}
+	return path, nil
+}
+
+// readEnv sets v up to read DAILYLOG_* environment variables
+func readEnv(v *viper.Viper) {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+	for key, name := range envNames {
+		_ = v.BindEnv(key, name)
+	}
+}
+
+// Profiles returns the names of the profiles under profiles, sorted
+func Profiles(v *viper.Viper) []string {
+	names := make([]string, 0)
+	for name := range v.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the settings of the named profile: those under
+// profiles.<name>, e.g. profiles.work.github.repo, over the rest of v's
+func Profile(v *viper.Viper, name string) (*viper.Viper, error) {
+	overrides := v.Sub("profiles." + name)
+	if overrides == nil {
+		return nil, storage.ValidationError{Field: "profiles", Message: fmt.Sprintf("no profile named %q", name)}
+	}
+	profile := viper.New()
+	readEnv(profile)
+	for _, key := range v.AllKeys() {
+		if !strings.HasPrefix(key, "profiles.") {
+			profile.Set(key, v.Get(key))
+		}
+	}
+	for _, key := range overrides.AllKeys() {
+		profile.Set(key, overrides.Get(key))
+	}
+	return profile, nil
+}
+
+// findFile returns the first configuration file found, or ""
+func findFile() string {
+	var candidates []string
+	dirs := []string{"."}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append([]string{home}, dirs...)
+	}
+	for _, dir := range dirs {
+		candidates = append(candidates, filepath.Join(dir, ".dailyctl.yaml"), filepath.Join(dir, ".dailyctl.yml"))
+	}
+	for _, path := range append(candidates, ContainerPath) {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// readSecretFiles sets each unset secret's variable from its _FILE variable
+func readSecretFiles() error {
+	for _, key := range Secrets {
+		name := EnvName(key)
+		path := os.Getenv(name + "_FILE")
+		if path == "" || os.Getenv(name) != "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s_FILE: %w", name, err)
+		}
+		_ = os.Setenv(name, strings.TrimSpace(string(data)))
+	}
+	return nil
+}
+
+// List reads a list, given as a YAML sequence or a comma-separated string
+func List(v *viper.Viper, key string) []string {
+	var items []string
+	switch value := v.Get(key).(type) {
+	case string:
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	default:
+		items = v.GetStringSlice(key)
+	}
+	return items
+}
+
+// Aliases reads a map from each name to its aliases, given as a YAML map
+// or as "alias=name" pairs separated by commas, e.g. "HQ=office,Main St=office"
+func Aliases(v *viper.Viper, key string) map[string][]string {
+	value, ok := v.Get(key).(string)
+	if !ok {
+		return v.GetStringMapStringSlice(key)
+	}
+	aliases := make(map[string][]string)
+	for _, pair := range strings.Split(value, ",") {
+		alias, name, ok := strings.Cut(pair, "=")
+		alias, name = strings.TrimSpace(alias), strings.TrimSpace(name)
+		if ok && alias != "" && name != "" {
+			aliases[name] = append(aliases[name], alias)
+		}
+	}
+	return aliases
+}
+
+// Visibility reads the privacy.* audience filtering policy. privacy.tags is
+// a YAML map from tag to visibility, or a comma-separated list of tags that
+// are private, or "tag=visibility" for another visibility.
+func Visibility(v *viper.Viper) storage.VisibilityPolicy {
+	policy := storage.VisibilityPolicy{Default: v.GetString("privacy.default")}
+	value, ok := v.Get("privacy.tags").(string)
+	if !ok {
+		policy.Tags = v.GetStringMapString("privacy.tags")
+		return policy
+	}
+	policy.Tags = make(map[string]string)
+	for _, tag := range strings.Split(value, ",") {
+		tag, visibility, _ := strings.Cut(tag, "=")
+		tag, visibility = strings.TrimSpace(tag), strings.TrimSpace(visibility)
+		if visibility == "" {
+			visibility = storage.VisibilityPrivate
+		}
+		if tag != "" {
+			policy.Tags[tag] = visibility
+		}
+	}
+	return policy
+}
+
+// Hooks reads the script hooks run on entry events from the hooks list
+func Hooks(v *viper.Viper) ([]providers.ScriptHook, error) {
+	var hooks []providers.ScriptHook
+	if err := v.UnmarshalKey("hooks", &hooks); err != nil {
+		return nil, fmt.Errorf("invalid hooks: %w", err)
+	}
+	for _, hook := range hooks {
+		if err := hook.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return hooks, nil
+}
+
+// Focus reads the focus.rules classifying entries for focus scoring, or
+// the default rules when there are none or they can't be read
+func Focus(v *viper.Viper) storage.FocusRules {
+	var rules storage.FocusRules
+	if err := v.UnmarshalKey("focus.rules", &rules); err != nil || len(rules) == 0 {
+		return storage.DefaultFocusRules
+	}
+	return rules
+}
+
+// WorkHours reads the hours.* daily and weekly targets and limits of work
+// time, e.g. "8h", each DefaultWorkHours where unset; "0" turns one off
+func WorkHours(v *viper.Viper) storage.WorkHoursConfig {
+	hours := storage.DefaultWorkHours
+	for key, minutes := range map[string]*int{
+		"hours.daily_target":  &hours.DailyTarget,
+		"hours.weekly_target": &hours.WeeklyTarget,
+		"hours.daily_max":     &hours.DailyMax,
+		"hours.weekly_max":    &hours.WeeklyMax,
+	} {
+		if v.IsSet(key) {
+			*minutes = int(v.GetDuration(key).Minutes())
+		}
+	}
+	return hours
+}
+
+// Billing reads the billing.* currency, hourly rates, and rounding invoices are priced with
+func Billing(v *viper.Viper) (storage.BillingConfig, error) {
+	var billing storage.BillingConfig
+	if err := v.UnmarshalKey("billing", &billing); err != nil {
+		return billing, fmt.Errorf("invalid billing configuration: %w", err)
+	}
+	for _, rate := range billing.Rates {
+		if rate.Rate < 0 {
+			return billing, storage.ValidationError{Field: "billing.rates", Message: fmt.Sprintf("rate must not be negative (got %g)", rate.Rate)}
+		}
+	}
+	if billing.RoundTo < 0 {
+		return billing, storage.ValidationError{Field: "billing.round_to", Message: "must not be negative"}
+	}
+	return billing, nil
+}
+
+// Team reads the team.logs of teammates keeping logs in repositories of
+// their own, for team standups
+func Team(v *viper.Viper) ([]storage.TeamLog, error) {
+	var logs []storage.TeamLog
+	if err := v.UnmarshalKey("team.logs", &logs); err != nil {
+		return nil, fmt.Errorf("invalid team.logs: %w", err)
+	}
+	for _, log := range logs {
+		if err := log.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return logs, nil
+}
+
+// Issues reads the issues.trackers linking issue keys in entry titles.
+// With none, issues of any project link to the Jira site at jira.url, if set.
+func Issues(v *viper.Viper) (storage.IssueTrackers, error) {
+	var trackers storage.IssueTrackers
+	if err := v.UnmarshalKey("issues.trackers", &trackers); err != nil {
+		return nil, fmt.Errorf("invalid issues.trackers: %w", err)
+	}
+	if len(trackers) == 0 && v.GetString("jira.url") != "" {
+		return storage.IssueTrackers{{Kind: storage.TrackerJira, URL: v.GetString("jira.url")}}, nil
+	}
+	for _, tracker := range trackers {
+		if err := tracker.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return trackers, nil
+}
+
+// Author reads the author new entries are attributed to: author, or
+// commit.author_name, or else the git user.name, if git has one
+func Author(v *viper.Viper) string {
+	for _, key := range []string{"author", "commit.author_name"} {
+		if author := strings.TrimSpace(v.GetString(key)); author != "" {
+			return author
+		}
+	}
+	out, err := exec.Command("git", "config", "--get", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// Commit reads the commit.* message templates, identity, and signing settings
+func Commit(v *viper.Viper) storage.CommitConfig {
+	return storage.CommitConfig{
+		SaveTemplate:   v.GetString("commit.save_template"),
+		DeleteTemplate: v.GetString("commit.delete_template"),
+		AuthorName:     v.GetString("commit.author_name"),
+		AuthorEmail:    v.GetString("commit.author_email"),
+		CommitterName:  v.GetString("commit.committer_name"),
+		CommitterEmail: v.GetString("commit.committer_email"),
+		SigningKey:     v.GetString("commit.signing_key"),
+		SigningProgram: v.GetString("commit.signing_program"),
+	}
+}
+
+// Storage reads the configuration of the storage holding the logs: the
+// GitHub repository, or with demo set, memory seeded with sample data
+func Storage(v *viper.Viper) storage.Config {
+	storageType := v.GetString("storage.type")
+	if v.GetBool("demo") {
+		storageType = storage.StorageTypeMemory
+	}
+	return storage.Config{
+		StorageType:     storageType,
+		GitHubRepo:      v.GetString("github.repo"),
+		GitHubToken:     v.GetString("github.token"),
+		GitHubPath:      v.GetString("github.path"),
+		GitHubBaseURL:   v.GetString("github.base_url"),
+		GitHubUploadURL: v.GetString("github.upload_url"),
+		DayFormat:       v.GetString("github.day_format"),
+		Layout:          v.GetString("github.layout"),
+		GitHubBranch:    v.GetString("github.branch"),
+		GitHubAutoPR:    v.GetBool("github.auto_pr"),
+		Visibility:      Visibility(v),
+		LocationAliases: Aliases(v, "locations.aliases"),
+		TagAliases:      Aliases(v, "tags.aliases"),
+		Focus:           Focus(v),
+		WorkHours:       WorkHours(v),
+		Commit:          Commit(v),
+	}
+}
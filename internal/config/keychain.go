@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// KeychainService is the service secrets are stored under in the keychain,
+// each with its key (e.g. github.token) as the account
+const KeychainService = "dailylog"
+
+// keychainLookup reads a secret from the system keychain: the macOS
+// keychain through security, or the Secret Service (GNOME Keyring, KWallet)
+// through secret-tool elsewhere
+var keychainLookup = func(key string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", KeychainService, "-a", key, "-w")
+	case "windows":
+		return "", fmt.Errorf("the keychain is not supported on Windows")
+	default:
+		cmd = exec.Command("secret-tool", "lookup", "service", KeychainService, "key", key)
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// readKeychain fills in secrets that aren't set otherwise from the keychain,
+// beneath every other source
+func readKeychain(v *viper.Viper) {
+	for _, key := range Secrets {
+		if v.GetString(key) != "" {
+			continue
+		}
+		if secret, err := keychainLookup(key); err == nil && secret != "" {
+			v.SetDefault(key, secret)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Package datetime parses the absolute and natural-language date expressions
+// accepted on the command line, such as "2025-09-29 14:30", "yesterday 3pm",
+// "last friday", "3 weeks ago", or "end of last month".
+package datetime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateTimeFormats are the absolute formats tried before any natural-language parsing
+var dateTimeFormats = []string{
+	"2006-01-02 15:04:05", // YYYY-MM-DD HH:MM:SS
+	"2006-01-02 15:04",    // YYYY-MM-DD HH:MM
+	"2006-01-02T15:04:05", // ISO format with T
+	"2006-01-02T15:04",    // ISO format with T, no seconds
+	"01/02/2006 15:04:05", // MM/DD/YYYY HH:MM:SS
+	"01/02/2006 15:04",    // MM/DD/YYYY HH:MM
+	"01/02/2006 3:04 PM",  // MM/DD/YYYY H:MM PM
+	"01/02/2006 3:04PM",   // MM/DD/YYYY H:MMPM
+	"2006-01-02 3:04 PM",  // YYYY-MM-DD H:MM PM
+	"2006-01-02 3:04PM",   // YYYY-MM-DD H:MMPM
+	"Jan 2, 2006 15:04",   // Month DD, YYYY HH:MM
+	"Jan 2, 2006 3:04 PM", // Month DD, YYYY H:MM PM
+	"2 Jan 2006 15:04",    // DD Month YYYY HH:MM
+	"2 Jan 2006 3:04 PM",  // DD Month YYYY H:MM PM
+}
+
+// dateFormats are absolute formats that name a day without a time
+var dateFormats = []string{
+	"2006-01-02",
+	"01/02/2006",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"2 Jan 2006",
+}
+
+// timeFormats are the formats accepted for a time of day
+var timeFormats = []string{
+	"15:04:05", // 24-hour with seconds
+	"15:04",    // 24-hour
+	"3:04 PM",  // 12-hour with space
+	"3:04PM",   // 12-hour without space
+	"3 PM",     // hour only with space
+	"3PM",      // hour only with PM
+}
+
+var (
+	relativePattern = regexp.MustCompile(`^(?:in\s+)?(\d+|an?)\s+(second|minute|hour|day|week|month|year)s?(?:\s+(ago|from\s+now))?$`)
+	weekdayPattern  = regexp.MustCompile(`^(?:(last|this|next)\s+)?(monday|tuesday|wednesday|thursday|friday|saturday|sunday)$`)
+	spanPattern     = regexp.MustCompile(`^(?:(start|beginning|end)\s+of\s+)?(?:(last|this|next)\s+)?(week|month|year)$`)
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// Parse parses an absolute or relative datetime, similar to GNU date. Days
+// named without a time, such as "yesterday" or "last friday", keep the time
+// of day of now.
+func Parse(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+
+	for _, format := range dateTimeFormats {
+		if t, err := time.Parse(format, input); err == nil {
+			return inLocation(t, now.Location()), nil
+		}
+	}
+	for _, format := range dateFormats {
+		if t, err := time.Parse(format, input); err == nil {
+			return inLocation(t, now.Location()), nil
+		}
+	}
+
+	lower := strings.ToLower(strings.Join(strings.Fields(input), " "))
+
+	// A time alone is today
+	if t, err := ParseTime(lower); err == nil {
+		return atTime(now, t), nil
+	}
+
+	if lower == "now" {
+		return now, nil
+	}
+	if t, ok := parseRelative(lower, now); ok {
+		return t, nil
+	}
+	if day, ok := parseDay(lower, now); ok {
+		return day, nil
+	}
+
+	// A day followed by a time, e.g. "yesterday 3pm" or "last friday 9:30am"
+	fields := strings.Fields(lower)
+	for i := len(fields) - 1; i > 0; i-- {
+		t, err := ParseTime(strings.Join(fields[i:], " "))
+		if err != nil {
+			continue
+		}
+		if day, ok := parseDay(strings.Join(fields[:i], " "), now); ok {
+			return atTime(day, t), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse datetime: %s", input)
+}
+
+// ParseDate parses a date expression and returns midnight of that day
+func ParseDate(input string, now time.Time) (time.Time, error) {
+	t, err := Parse(input, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse date: %s", strings.TrimSpace(input))
+	}
+	return StartOfDay(t), nil
+}
+
+// ParseRange parses an expression naming either a single day or a whole
+// week, month, or year, such as "last week", and returns the first and last
+// day it covers
+func ParseRange(input string, now time.Time) (time.Time, time.Time, error) {
+	lower := strings.ToLower(strings.Join(strings.Fields(input), " "))
+	if m := spanPattern.FindStringSubmatch(lower); m != nil && m[1] == "" {
+		start, end := span(m[2], m[3], now)
+		return start, end, nil
+	}
+
+	day, err := ParseDate(input, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return day, day, nil
+}
+
+// ParseTime parses a time of day like "3pm", "14:30", or "9:15 am"
+func ParseTime(input string) (time.Time, error) {
+	upper := strings.ToUpper(strings.TrimSpace(input))
+	for _, format := range timeFormats {
+		if t, err := time.Parse(format, upper); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unable to parse time: %s", input)
+}
+
+// StartOfDay returns midnight at the start of t's day
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek returns midnight on the Monday of t's week
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// parseRelative handles "3 hours ago", "2 weeks from now", "in 5 days", and "a month ago"
+func parseRelative(input string, now time.Time) (time.Time, bool) {
+	m := relativePattern.FindStringSubmatch(input)
+	if m == nil {
+		return time.Time{}, false
+	}
+	// A bare "3 days" is ambiguous; require a direction
+	if m[3] == "" && !strings.HasPrefix(input, "in ") {
+		return time.Time{}, false
+	}
+
+	amount := 1
+	if m[1] != "a" && m[1] != "an" {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		amount = n
+	}
+	if m[3] == "ago" {
+		amount = -amount
+	}
+
+	switch m[2] {
+	case "second":
+		return now.Add(time.Duration(amount) * time.Second), true
+	case "minute":
+		return now.Add(time.Duration(amount) * time.Minute), true
+	case "hour":
+		return now.Add(time.Duration(amount) * time.Hour), true
+	case "day":
+		return now.AddDate(0, 0, amount), true
+	case "week":
+		return now.AddDate(0, 0, amount*7), true
+	case "month":
+		return addMonths(now, amount), true
+	case "year":
+		return addMonths(now, 12*amount), true
+	}
+	return time.Time{}, false
+}
+
+// addMonths moves t by months, to the same day of the month or the last
+// day of shorter months: a month before March 31 is February 28, not March 3
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1,
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	day := min(t.Day(), first.AddDate(0, 1, -1).Day())
+	return first.AddDate(0, 0, day-1)
+}
+
+// parseDay resolves an expression naming a day relative to now, keeping
+// now's time of day
+func parseDay(input string, now time.Time) (time.Time, bool) {
+	switch input {
+	case "today":
+		return now, true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), true
+	}
+
+	if t, ok := parseRelative(input, now); ok {
+		return t, true
+	}
+
+	// "friday" and "this friday" are in the current week; "last friday" is
+	// the most recent one before today and "next friday" the first after it
+	if m := weekdayPattern.FindStringSubmatch(input); m != nil {
+		target := weekdays[m[2]]
+		switch m[1] {
+		case "last":
+			diff := (int(now.Weekday()) - int(target) + 7) % 7
+			if diff == 0 {
+				diff = 7
+			}
+			return now.AddDate(0, 0, -diff), true
+		case "next":
+			diff := (int(target) - int(now.Weekday()) + 7) % 7
+			if diff == 0 {
+				diff = 7
+			}
+			return now.AddDate(0, 0, diff), true
+		default:
+			offset := (int(target)+6)%7 - (int(now.Weekday())+6)%7
+			return now.AddDate(0, 0, offset), true
+		}
+	}
+
+	// "last week" is its first day; "end of last month" its last
+	if m := spanPattern.FindStringSubmatch(input); m != nil && (m[1] != "" || m[2] != "") {
+		start, end := span(m[2], m[3], now)
+		day := start
+		if m[1] == "end" {
+			day = end
+		}
+		return atTime(day, now), true
+	}
+
+	return time.Time{}, false
+}
+
+// span returns the first and last day of this, last, or next week, month, or year
+func span(which, unit string, now time.Time) (time.Time, time.Time) {
+	shift := 0
+	switch which {
+	case "last":
+		shift = -1
+	case "next":
+		shift = 1
+	}
+
+	today := StartOfDay(now)
+	switch unit {
+	case "week":
+		start := StartOfWeek(today).AddDate(0, 0, 7*shift)
+		return start, start.AddDate(0, 0, 6)
+	case "month":
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location()).AddDate(0, shift, 0)
+		return start, start.AddDate(0, 1, -1)
+	default:
+		start := time.Date(today.Year()+shift, time.January, 1, 0, 0, 0, 0, today.Location())
+		return start, start.AddDate(1, 0, -1)
+	}
+}
+
+// atTime returns day with the time of day of clock
+func atTime(day, clock time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		clock.Hour(), clock.Minute(), clock.Second(), 0, day.Location())
+}
+
+// inLocation reinterprets a parsed wall-clock time in loc
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
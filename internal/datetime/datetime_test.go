@@ -0,0 +1,148 @@
+package datetime
+
+import (
+	"testing"
+	"time"
+)
+
+// zone is east of UTC, so results must stay in now's location
+var zone = time.FixedZone("UTC+9", 9*60*60)
+
+// at returns a time in zone, the location of every now in these tests
+func at(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, zone)
+}
+
+// wednesday is now in most cases: Wednesday 5 March 2025, 14:30
+var wednesday = at(2025, time.March, 5, 14, 30)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		now   time.Time
+		want  time.Time
+	}{
+		// Absolute dates and times, in now's location
+		{name: "ISO date and time", input: "2025-09-29 14:30", now: wednesday, want: at(2025, time.September, 29, 14, 30)},
+		{name: "ISO with T", input: "2025-09-29T08:05", now: wednesday, want: at(2025, time.September, 29, 8, 5)},
+		{name: "US date, 12-hour time", input: "09/29/2025 3:04 PM", now: wednesday, want: at(2025, time.September, 29, 15, 4)},
+		{name: "month name", input: "Sep 29, 2025", now: wednesday, want: at(2025, time.September, 29, 0, 0)},
+		{name: "time alone is today", input: "3pm", now: wednesday, want: at(2025, time.March, 5, 15, 0)},
+		{name: "now", input: "now", now: wednesday, want: wednesday},
+
+		// Days relative to today keep now's time of day
+		{name: "today", input: "today", now: wednesday, want: wednesday},
+		{name: "yesterday", input: "yesterday", now: wednesday, want: at(2025, time.March, 4, 14, 30)},
+		{name: "tomorrow", input: "Tomorrow", now: wednesday, want: at(2025, time.March, 6, 14, 30)},
+		{name: "yesterday with a time", input: "yesterday 3pm", now: wednesday, want: at(2025, time.March, 4, 15, 0)},
+		{name: "yesterday across a year", input: "yesterday", now: at(2025, time.January, 1, 8, 0), want: at(2024, time.December, 31, 8, 0)},
+
+		// Weekdays: bare and "this" are in the current week, Monday to Sunday
+		{name: "weekday later this week", input: "friday", now: wednesday, want: at(2025, time.March, 7, 14, 30)},
+		{name: "weekday earlier this week", input: "this monday", now: wednesday, want: at(2025, time.March, 3, 14, 30)},
+		{name: "sunday ends the week", input: "sunday", now: wednesday, want: at(2025, time.March, 9, 14, 30)},
+		{name: "today's weekday", input: "wednesday", now: wednesday, want: wednesday},
+		{name: "last weekday", input: "last friday", now: wednesday, want: at(2025, time.February, 28, 14, 30)},
+		{name: "last of today's weekday", input: "last wednesday", now: wednesday, want: at(2025, time.February, 26, 14, 30)},
+		{name: "next weekday", input: "next monday", now: wednesday, want: at(2025, time.March, 10, 14, 30)},
+		{name: "next of today's weekday", input: "next wednesday", now: wednesday, want: at(2025, time.March, 12, 14, 30)},
+		{name: "last weekday with a time", input: "last monday 9:30am", now: wednesday, want: at(2025, time.March, 3, 9, 30)},
+		{name: "sunday before a monday", input: "last sunday", now: at(2025, time.March, 3, 9, 0), want: at(2025, time.March, 2, 9, 0)},
+
+		// Relative offsets
+		{name: "days ago", input: "3 days ago", now: wednesday, want: at(2025, time.March, 2, 14, 30)},
+		{name: "in weeks", input: "in 2 weeks", now: wednesday, want: at(2025, time.March, 19, 14, 30)},
+		{name: "weeks from now", input: "2 weeks from now", now: wednesday, want: at(2025, time.March, 19, 14, 30)},
+		{name: "an hour ago", input: "an hour ago", now: wednesday, want: at(2025, time.March, 5, 13, 30)},
+		{name: "minutes across midnight", input: "90 minutes ago", now: at(2025, time.March, 5, 0, 30), want: at(2025, time.March, 4, 23, 0)},
+		{name: "a year ago", input: "a year ago", now: wednesday, want: at(2024, time.March, 5, 14, 30)},
+
+		// Month ends: offsets take the last day of shorter months
+		{name: "month ago from the 31st", input: "a month ago", now: at(2025, time.March, 31, 10, 0), want: at(2025, time.February, 28, 10, 0)},
+		{name: "month ago in a leap year", input: "1 month ago", now: at(2024, time.March, 31, 10, 0), want: at(2024, time.February, 29, 10, 0)},
+		{name: "month ahead from the 31st", input: "in 1 month", now: at(2025, time.March, 31, 10, 0), want: at(2025, time.April, 30, 10, 0)},
+		{name: "months across a year", input: "3 months ago", now: at(2025, time.January, 31, 10, 0), want: at(2024, time.October, 31, 10, 0)},
+		{name: "year from a leap day", input: "a year ago", now: at(2024, time.February, 29, 10, 0), want: at(2023, time.February, 28, 10, 0)},
+		{name: "month ago mid-month", input: "a month ago", now: wednesday, want: at(2025, time.February, 5, 14, 30)},
+
+		// Spans name their first day, or last with "end of"
+		{name: "last week", input: "last week", now: wednesday, want: at(2025, time.February, 24, 14, 30)},
+		{name: "end of last week", input: "end of last week", now: wednesday, want: at(2025, time.March, 2, 14, 30)},
+		{name: "end of last month", input: "end of last month", now: at(2025, time.March, 31, 10, 0), want: at(2025, time.February, 28, 10, 0)},
+		{name: "end of this month", input: "end of this month", now: at(2024, time.February, 3, 10, 0), want: at(2024, time.February, 29, 10, 0)},
+		{name: "start of next month", input: "start of next month", now: at(2025, time.December, 31, 10, 0), want: at(2026, time.January, 1, 10, 0)},
+		{name: "beginning of the year", input: "beginning of this year", now: wednesday, want: at(2025, time.January, 1, 14, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.now)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) || got.Location() != zone {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, input := range []string{"", "3 days", "someday", "next blursday", "last fortnight", "2025-02-30"} {
+		if got, err := Parse(input, wednesday); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", input, got)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	got, err := ParseDate("last friday 3pm", wednesday)
+	if err != nil {
+		t.Fatalf("ParseDate: %v", err)
+	}
+	if want := at(2025, time.February, 28, 0, 0); !got.Equal(want) {
+		t.Errorf("ParseDate = %v, want %v", got, want)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		now        time.Time
+		start, end time.Time
+	}{
+		{name: "this week", input: "this week", now: wednesday, start: at(2025, time.March, 3, 0, 0), end: at(2025, time.March, 9, 0, 0)},
+		{name: "last week", input: "last week", now: wednesday, start: at(2025, time.February, 24, 0, 0), end: at(2025, time.March, 2, 0, 0)},
+		{name: "next week", input: "next week", now: wednesday, start: at(2025, time.March, 10, 0, 0), end: at(2025, time.March, 16, 0, 0)},
+		{name: "week on a sunday", input: "week", now: at(2025, time.March, 9, 22, 0), start: at(2025, time.March, 3, 0, 0), end: at(2025, time.March, 9, 0, 0)},
+		{name: "last week across a year", input: "last week", now: at(2025, time.January, 1, 8, 0), start: at(2024, time.December, 23, 0, 0), end: at(2024, time.December, 29, 0, 0)},
+		{name: "this month", input: "this month", now: wednesday, start: at(2025, time.March, 1, 0, 0), end: at(2025, time.March, 31, 0, 0)},
+		{name: "last month from the 31st", input: "last month", now: at(2025, time.March, 31, 10, 0), start: at(2025, time.February, 1, 0, 0), end: at(2025, time.February, 28, 0, 0)},
+		{name: "leap February", input: "month", now: at(2024, time.February, 10, 10, 0), start: at(2024, time.February, 1, 0, 0), end: at(2024, time.February, 29, 0, 0)},
+		{name: "last month across a year", input: "last month", now: at(2025, time.January, 15, 8, 0), start: at(2024, time.December, 1, 0, 0), end: at(2024, time.December, 31, 0, 0)},
+		{name: "next year", input: "next year", now: wednesday, start: at(2026, time.January, 1, 0, 0), end: at(2026, time.December, 31, 0, 0)},
+		{name: "single day", input: "yesterday", now: wednesday, start: at(2025, time.March, 4, 0, 0), end: at(2025, time.March, 4, 0, 0)},
+		{name: "end of a span is one day", input: "end of last month", now: wednesday, start: at(2025, time.February, 28, 0, 0), end: at(2025, time.February, 28, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseRange(tt.input, tt.now)
+			if err != nil {
+				t.Fatalf("ParseRange(%q): %v", tt.input, err)
+			}
+			if !start.Equal(tt.start) || !end.Equal(tt.end) {
+				t.Errorf("ParseRange(%q) = %v to %v, want %v to %v", tt.input, start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	for day := 3; day <= 9; day++ {
+		now := at(2025, time.March, day, 23, 59)
+		if got, want := StartOfWeek(now), at(2025, time.March, 3, 0, 0); !got.Equal(want) {
+			t.Errorf("StartOfWeek(%s) = %v, want %v", now.Weekday(), got, want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Periods a digest can cover
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// maxTags is how many of the most used tags a digest lists
+const maxTags = 10
+
+// Digest is a period's summary and statistics, ready to render
+type Digest struct {
+	Period  string                      `json:"period" yaml:"period"`
+	Start   time.Time                   `json:"start" yaml:"start"`
+	End     time.Time                   `json:"end" yaml:"end"`
+	Summary string                      `json:"summary" yaml:"summary"`
+	Totals  storage.AggregationBucket   `json:"totals" yaml:"totals"`
+	Types   []storage.AggregationBucket `json:"types" yaml:"types"`
+	Tags    []storage.AggregationBucket `json:"tags" yaml:"tags"`
+	Days    []Day                       `json:"days" yaml:"days"`
+}
+
+// Day is one day's entries in a digest
+type Day struct {
+	Date    time.Time               `json:"date" yaml:"date"`
+	Entries []storage.DailyLogEntry `json:"entries" yaml:"entries"`
+}
+
+// ValidatePeriod checks a digest period
+func ValidatePeriod(period string) error {
+	if period != PeriodWeek && period != PeriodMonth {
+		return storage.ValidationError{Field: "period", Message: fmt.Sprintf("must be week or month (got %q)", period)}
+	}
+	return nil
+}
+
+// Range returns the first and last day of the period containing date.
+// Weeks start on Monday.
+func Range(period string, date time.Time) (time.Time, time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	if period == PeriodMonth {
+		start := day.AddDate(0, 0, 1-day.Day())
+		return start, start.AddDate(0, 1, -1)
+	}
+	start := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	return start, start.AddDate(0, 0, 6)
+}
+
+// New builds the digest of entries logged between start and end
+func New(period string, start, end time.Time, summary string, entries []storage.DailyLogEntry) Digest {
+	d := Digest{
+		Period:  period,
+		Start:   start,
+		End:     end,
+		Summary: summary,
+		Totals:  storage.SummarizeEntries(entries),
+		Types:   storage.AggregateEntries(entries, storage.GroupByType).Buckets,
+		Tags:    storage.AggregateEntries(entries, storage.GroupByTag).Buckets,
+		Days:    []Day{},
+	}
+	if len(d.Tags) > maxTags {
+		d.Tags = d.Tags[:maxTags]
+	}
+
+	byDay := make(map[string]*Day)
+	for _, entry := range entries {
+		key := entry.Timestamp.Format("2006-01-02")
+		day, ok := byDay[key]
+		if !ok {
+			date, _ := time.Parse("2006-01-02", key)
+			day = &Day{Date: date}
+			byDay[key] = day
+		}
+		day.Entries = append(day.Entries, entry)
+	}
+	for _, day := range byDay {
+		sort.SliceStable(day.Entries, func(i, j int) bool { return day.Entries[i].Timestamp.Before(day.Entries[j].Timestamp) })
+		d.Days = append(d.Days, *day)
+	}
+	sort.Slice(d.Days, func(i, j int) bool { return d.Days[i].Date.Before(d.Days[j].Date) })
+	return d
+}
+
+// Subject returns the email subject of the digest
+func (d Digest) Subject() string {
+	label := "Weekly"
+	if d.Period == PeriodMonth {
+		label = "Monthly"
+	}
+	return fmt.Sprintf("%s log digest: %s to %s", label, d.Start.Format("Jan 2"), d.End.Format("Jan 2, 2006"))
+}
+
+var funcs = map[string]any{
+	"day":     func(t time.Time) string { return t.Format("Monday, Jan 2") },
+	"minutes": formatMinutes,
+	"percent": func(part, total int) string {
+		if total == 0 {
+			return "0%"
+		}
+		return fmt.Sprintf("%.0f%%", float64(part)/float64(total)*100)
+	},
+	"status": func(average float64) string {
+		if average <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f/10", average)
+	},
+	"join":  strings.Join,
+	"deref": func(n *int) int { return *n },
+}
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("digest").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: -apple-system, Helvetica, Arial, sans-serif; color: #24292f; max-width: 640px;">
+<h2 style="margin-bottom: 4px;">{{.Subject}}</h2>
+{{if .Summary}}<p style="color: #57606a;">{{.Summary}}</p>{{end}}
+<table style="border-collapse: collapse; margin: 16px 0;">
+<tr><td style="padding: 4px 16px 4px 0;">Entries</td><td><strong>{{.Totals.Count}}</strong></td></tr>
+<tr><td style="padding: 4px 16px 4px 0;">Days logged</td><td><strong>{{len .Days}}</strong></td></tr>
+<tr><td style="padding: 4px 16px 4px 0;">Tracked time</td><td><strong>{{minutes .Totals.TotalDuration}}</strong></td></tr>
+<tr><td style="padding: 4px 16px 4px 0;">Average status</td><td><strong>{{status .Totals.AverageStatus}}</strong></td></tr>
+</table>
+{{if .Types}}<h3>By type</h3>
+<table style="border-collapse: collapse;">
+{{range .Types}}<tr><td style="padding: 2px 16px 2px 0;">{{.Key}}</td><td style="padding: 2px 16px 2px 0; text-align: right;">{{.Count}}</td><td style="padding: 2px 16px 2px 0; text-align: right;">{{percent .Count $.Totals.Count}}</td><td style="text-align: right;">{{minutes .TotalDuration}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Tags}}<h3>Top tags</h3>
+<p>{{range $i, $tag := .Tags}}{{if $i}}, {{end}}<strong>{{$tag.Key}}</strong> ({{$tag.Count}}){{end}}</p>{{end}}
+{{if .Days}}<h3>Entries</h3>
+{{range .Days}}<h4 style="margin: 12px 0 4px;">{{day .Date}}</h4>
+<ul style="margin-top: 0;">
+{{range .Entries}}<li><strong>{{.Title}}</strong> <span style="color: #57606a;">{{.Type}}{{if .Tags}} · {{join .Tags ", "}}{{end}}{{if .Duration}} · {{minutes (deref .Duration)}}{{end}}</span>{{if .Description}}<br><span style="color: #57606a;">{{.Description}}</span>{{end}}</li>
+{{end}}</ul>
+{{end}}{{else}}<p>Nothing was logged in this period.</p>{{end}}
+</body>
+</html>
+`))
+
+var textTemplate = template.Must(template.New("digest").Funcs(funcs).Parse(`{{.Subject}}
+{{if .Summary}}
+{{.Summary}}
+{{end}}
+Entries:        {{.Totals.Count}}
+Days logged:    {{len .Days}}
+Tracked time:   {{minutes .Totals.TotalDuration}}
+Average status: {{status .Totals.AverageStatus}}
+{{if .Types}}
+By type:
+{{range .Types}}  {{.Key}}: {{.Count}} ({{percent .Count $.Totals.Count}}), {{minutes .TotalDuration}}
+{{end}}{{end}}{{if .Tags}}
+Top tags: {{range $i, $tag := .Tags}}{{if $i}}, {{end}}{{$tag.Key}} ({{$tag.Count}}){{end}}
+{{end}}{{range .Days}}
+{{day .Date}}
+{{range .Entries}}  - {{.Title}} [{{.Type}}]{{if .Tags}} {{join .Tags ", "}}{{end}}
+{{end}}{{else}}
+Nothing was logged in this period.
+{{end}}`))
+
+// HTML renders the digest as an HTML email body
+func (d Digest) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render digest: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// Text renders the digest as a plain text email body
+func (d Digest) Text() (string, error) {
+	var buf bytes.Buffer
+	if err := textTemplate.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render digest: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// formatMinutes formats a duration in minutes as e.g. "3h 20m"
+func formatMinutes(minutes int) string {
+	if minutes <= 0 {
+		return "-"
+	}
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
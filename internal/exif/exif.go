@@ -0,0 +1,245 @@
+// Package exif reads the few EXIF fields a photo entry needs from a JPEG:
+// when it was taken, where, and with what camera.
+package exif
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrNoExif is returned for images without EXIF data
+var ErrNoExif = errors.New("no EXIF data")
+
+// Tags read from the image
+const (
+	tagMake               = 0x010F
+	tagModel              = 0x0110
+	tagDateTime           = 0x0132
+	tagExifIFD            = 0x8769
+	tagGPSIFD             = 0x8825
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+	tagGPSLatitudeRef     = 0x0001
+	tagGPSLatitude        = 0x0002
+	tagGPSLongitudeRef    = 0x0003
+	tagGPSLongitude       = 0x0004
+)
+
+// Info is what was found in an image's EXIF data
+type Info struct {
+	Taken       time.Time // Zero if unknown
+	HasLocation bool
+	Latitude    float64
+	Longitude   float64
+	Camera      string // Make and model, e.g. "Apple iPhone 15"
+}
+
+// Read reads the EXIF data of a JPEG image. Times without an offset are
+// taken to be in loc.
+func Read(r io.Reader, loc *time.Location) (*Info, error) {
+	payload, err := findExif(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	return parseTIFF(payload, loc)
+}
+
+// findExif returns the TIFF data in a JPEG's APP1 Exif segment
+func findExif(r *bufio.Reader) ([]byte, error) {
+	var soi [2]byte
+	if _, err := io.ReadFull(r, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return nil, fmt.Errorf("%w: not a JPEG image", ErrNoExif)
+	}
+	for {
+		marker, err := r.ReadByte()
+		if err != nil {
+			return nil, ErrNoExif
+		}
+		if marker != 0xFF {
+			continue
+		}
+		kind, err := r.ReadByte()
+		if err != nil {
+			return nil, ErrNoExif
+		}
+		switch {
+		case kind == 0xFF || kind == 0x01 || kind >= 0xD0 && kind <= 0xD7:
+			continue // Padding and markers without a length
+		case kind == 0xDA || kind == 0xD9:
+			return nil, ErrNoExif // Image data starts; metadata comes before it
+		}
+
+		var length uint16
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil || length < 2 {
+			return nil, ErrNoExif
+		}
+		segment := make([]byte, length-2)
+		if _, err := io.ReadFull(r, segment); err != nil {
+			return nil, ErrNoExif
+		}
+		if kind == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return segment[6:], nil
+		}
+	}
+}
+
+// tiff reads IFD entries from TIFF data
+type tiff struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+type ifdEntry struct {
+	typ   uint16
+	count uint32
+	value []byte // The entry's 4 value bytes, or what they point to
+}
+
+func parseTIFF(data []byte, loc *time.Location) (*Info, error) {
+	if len(data) < 8 {
+		return nil, ErrNoExif
+	}
+	t := &tiff{data: data}
+	switch string(data[:2]) {
+	case "II":
+		t.order = binary.LittleEndian
+	case "MM":
+		t.order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("%w: bad TIFF header", ErrNoExif)
+	}
+
+	ifd0 := t.readIFD(t.order.Uint32(data[4:]))
+	info := &Info{}
+	maker, model := t.ascii(ifd0[tagMake]), t.ascii(ifd0[tagModel])
+	if model != "" && !strings.HasPrefix(model, maker) {
+		model = strings.TrimSpace(maker + " " + model)
+	}
+	info.Camera = model
+	if info.Camera == "" {
+		info.Camera = maker
+	}
+
+	taken := t.ascii(ifd0[tagDateTime])
+	offset := ""
+	if entry, ok := ifd0[tagExifIFD]; ok {
+		exifIFD := t.readIFD(t.order.Uint32(entry.value))
+		if original := t.ascii(exifIFD[tagDateTimeOriginal]); original != "" {
+			taken = original
+			offset = t.ascii(exifIFD[tagOffsetTimeOriginal])
+		}
+	}
+	info.Taken = parseTime(taken, offset, loc)
+
+	if entry, ok := ifd0[tagGPSIFD]; ok {
+		gps := t.readIFD(t.order.Uint32(entry.value))
+		latitude, okLat := t.degrees(gps[tagGPSLatitude])
+		longitude, okLon := t.degrees(gps[tagGPSLongitude])
+		if okLat && okLon {
+			if t.ascii(gps[tagGPSLatitudeRef]) == "S" {
+				latitude = -latitude
+			}
+			if t.ascii(gps[tagGPSLongitudeRef]) == "W" {
+				longitude = -longitude
+			}
+			info.HasLocation, info.Latitude, info.Longitude = true, latitude, longitude
+		}
+	}
+	return info, nil
+}
+
+// readIFD reads the entries of the IFD at offset, ignoring what does not fit
+func (t *tiff) readIFD(offset uint32) map[uint16]ifdEntry {
+	entries := make(map[uint16]ifdEntry)
+	if uint64(offset)+2 > uint64(len(t.data)) {
+		return entries
+	}
+	count := int(t.order.Uint16(t.data[offset:]))
+	for i := 0; i < count; i++ {
+		start := uint64(offset) + 2 + uint64(i)*12
+		if start+12 > uint64(len(t.data)) {
+			break
+		}
+		raw := t.data[start : start+12]
+		entry := ifdEntry{
+			typ:   t.order.Uint16(raw[2:]),
+			count: t.order.Uint32(raw[4:]),
+			value: raw[8:12],
+		}
+		size := uint64(entry.count) * uint64(typeSize(entry.typ))
+		if size > 4 {
+			offset := uint64(t.order.Uint32(raw[8:]))
+			if offset+size > uint64(len(t.data)) {
+				continue
+			}
+			entry.value = t.data[offset : offset+size]
+		}
+		entries[t.order.Uint16(raw)] = entry
+	}
+	return entries
+}
+
+func typeSize(typ uint16) int {
+	switch typ {
+	case 3: // SHORT
+		return 2
+	case 4, 9: // LONG, SLONG
+		return 4
+	case 5, 10: // RATIONAL, SRATIONAL
+		return 8
+	}
+	return 1 // BYTE, ASCII, UNDEFINED
+}
+
+// ascii reads an ASCII entry, or "" for another type
+func (t *tiff) ascii(entry ifdEntry) string {
+	if entry.typ != 2 || entry.count == 0 {
+		return ""
+	}
+	value := entry.value
+	if int(entry.count) < len(value) {
+		value = value[:entry.count]
+	}
+	return strings.TrimSpace(strings.TrimRight(string(value), "\x00"))
+}
+
+// degrees reads a GPS coordinate: degrees, minutes, and seconds as rationals
+func (t *tiff) degrees(entry ifdEntry) (float64, bool) {
+	if entry.typ != 5 || entry.count != 3 || len(entry.value) < 24 {
+		return 0, false
+	}
+	var parts [3]float64
+	for i := range parts {
+		numerator := t.order.Uint32(entry.value[i*8:])
+		denominator := t.order.Uint32(entry.value[i*8+4:])
+		if denominator == 0 {
+			return 0, false
+		}
+		parts[i] = float64(numerator) / float64(denominator)
+	}
+	return parts[0] + parts[1]/60 + parts[2]/3600, true
+}
+
+// parseTime reads an EXIF time such as "2025:09:29 14:30:05", with an
+// offset such as "+02:00" when there is one
+func parseTime(value, offset string, loc *time.Location) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	if offset != "" {
+		if t, err := time.Parse("2006:01:02 15:04:05-07:00", value+offset); err == nil {
+			return t
+		}
+	}
+	t, err := time.ParseInLocation("2006:01:02 15:04:05", value, loc)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
@@ -0,0 +1,207 @@
+package exporters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// DefaultAnkiDeck is the deck lessons are exported to
+const DefaultAnkiDeck = "Daily Log Lessons"
+
+// DefaultAnkiConnectURL is where the AnkiConnect add-on listens
+const DefaultAnkiConnectURL = "http://127.0.0.1:8765"
+
+// AnkiCard is a lesson learned as a flashcard of Anki's Basic note type
+type AnkiCard struct {
+	ID    string   `json:"id"` // The entry's, so a card is updated rather than added again
+	Front string   `json:"front"`
+	Back  string   `json:"back"`
+	Tags  []string `json:"tags"`
+}
+
+// LessonCards makes a card of each lesson learned among the entries of
+// days. The title is the front, and the description, with the date, the back.
+func LessonCards(days []storage.DayLog, tags storage.TagAliases) []AnkiCard {
+	var cards []AnkiCard
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			if !storage.IsLesson(entry, tags) {
+				continue
+			}
+			back := strings.ReplaceAll(html.EscapeString(strings.TrimSpace(entry.Description)), "\n", "<br>")
+			if back != "" {
+				back += "<br><br>"
+			}
+			back += "<small>" + day.GetDateString() + "</small>"
+
+			card := AnkiCard{ID: entry.ID, Front: html.EscapeString(entry.Title), Back: back, Tags: []string{"dailylog"}}
+			for _, tag := range tags.NormalizeTags(entry.Tags) {
+				if tag != storage.TagLesson {
+					card.Tags = append(card.Tags, ankiTag(tag))
+				}
+			}
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+// ankiTag writes a tag as Anki does, with :: between levels and no spaces
+func ankiTag(tag string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(tag, "/", "::")), "_")
+}
+
+// WriteAnkiText writes cards as a text file for Anki's File > Import, into
+// deck. Each card's GUID is its entry's ID, so importing again updates the
+// cards rather than duplicating them. It returns the number of cards written.
+func WriteAnkiText(w io.Writer, deck string, cards []AnkiCard) (int, error) {
+	bw := bufio.NewWriter(w)
+	for _, header := range []string{"#separator:tab", "#html:true", "#notetype:Basic", "#deck:" + deck, "#guid column:1", "#tags column:4"} {
+		bw.WriteString(header + "\n")
+	}
+	for _, card := range cards {
+		fields := []string{card.ID, card.Front, card.Back, strings.Join(card.Tags, " ")}
+		for i, field := range fields {
+			// Fields are escaped HTML, so a tab is the only separator left to avoid
+			fields[i] = strings.ReplaceAll(field, "\t", " ")
+		}
+		bw.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write Anki cards: %v", err)
+	}
+	return len(cards), nil
+}
+
+// AnkiConnectClient adds cards to a running Anki through the AnkiConnect add-on
+type AnkiConnectClient struct {
+	client *http.Client
+	url    string
+}
+
+// NewAnkiConnectClient creates a client for AnkiConnect at url, or at
+// DefaultAnkiConnectURL if url is empty
+func NewAnkiConnectClient(url string) *AnkiConnectClient {
+	if url == "" {
+		url = DefaultAnkiConnectURL
+	}
+	return &AnkiConnectClient{client: &http.Client{Timeout: 30 * time.Second}, url: url}
+}
+
+// URL returns the address of AnkiConnect
+func (c *AnkiConnectClient) URL() string {
+	return c.url
+}
+
+// AnkiPushResult counts the cards pushed to Anki
+type AnkiPushResult struct {
+	Added   int `json:"added" yaml:"added"`
+	Skipped int `json:"skipped" yaml:"skipped"` // Already in the deck
+}
+
+// ankiNote is a note as AnkiConnect takes it
+type ankiNote struct {
+	DeckName  string            `json:"deckName"`
+	ModelName string            `json:"modelName"`
+	Fields    map[string]string `json:"fields"`
+	Tags      []string          `json:"tags"`
+	Options   map[string]any    `json:"options"`
+}
+
+// Push creates deck if needed and adds the cards not already in it, by front
+func (c *AnkiConnectClient) Push(deck string, cards []AnkiCard) (AnkiPushResult, error) {
+	var result AnkiPushResult
+	if err := c.invoke("createDeck", map[string]string{"deck": deck}, nil); err != nil {
+		return result, err
+	}
+	if len(cards) == 0 {
+		return result, nil
+	}
+
+	notes := make([]ankiNote, len(cards))
+	for i, card := range cards {
+		notes[i] = ankiNote{
+			DeckName:  deck,
+			ModelName: "Basic",
+			Fields:    map[string]string{"Front": card.Front, "Back": card.Back},
+			Tags:      card.Tags,
+			Options:   map[string]any{"allowDuplicate": false, "duplicateScope": "deck"},
+		}
+	}
+	var addable []bool
+	if err := c.invoke("canAddNotes", map[string]any{"notes": notes}, &addable); err != nil {
+		return result, err
+	}
+	var add []ankiNote
+	for i, note := range notes {
+		if i < len(addable) && addable[i] {
+			add = append(add, note)
+		} else {
+			result.Skipped++
+		}
+	}
+	if len(add) == 0 {
+		return result, nil
+	}
+
+	var ids []*int64
+	if err := c.invoke("addNotes", map[string]any{"notes": add}, &ids); err != nil {
+		return result, err
+	}
+	for _, id := range ids {
+		if id != nil {
+			result.Added++
+		} else {
+			result.Skipped++
+		}
+	}
+	return result, nil
+}
+
+// invoke calls an AnkiConnect action, decoding its result into result if set
+func (c *AnkiConnectClient) invoke(action string, params any, result any) error {
+	body, err := json.Marshal(map[string]any{"action": action, "version": 6, "params": params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach AnkiConnect at %s (is Anki running with the add-on?): %w", c.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("AnkiConnect %s failed: %s", action, resp.Status)
+	}
+
+	var reply struct {
+		Result json.RawMessage `json:"result"`
+		Error  *string         `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("invalid AnkiConnect response to %s: %w", action, err)
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("AnkiConnect %s failed: %s", action, *reply.Error)
+	}
+	if result != nil && len(reply.Result) > 0 {
+		if err := json.Unmarshal(reply.Result, result); err != nil {
+			return fmt.Errorf("invalid AnkiConnect response to %s: %w", action, err)
+		}
+	}
+	return nil
+}
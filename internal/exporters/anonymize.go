@@ -0,0 +1,168 @@
+package exporters
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"dailylog/internal/storage"
+)
+
+// Anonymization modes
+const (
+	AnonymizeHash  = "hash"  // Replace identifying values with pseudonyms
+	AnonymizeStrip = "strip" // Also drop free text and locations
+)
+
+// ValidateAnonymizeMode checks an anonymization mode
+func ValidateAnonymizeMode(mode string) error {
+	if mode != AnonymizeHash && mode != AnonymizeStrip {
+		return storage.ValidationError{Field: "anonymize", Message: fmt.Sprintf("must be hash or strip (got %q)", mode)}
+	}
+	return nil
+}
+
+// AnonymizeOptions controls how entries are anonymized
+type AnonymizeOptions struct {
+	Mode  string
+	Terms []string // Names and other sensitive terms to hash in free text
+	Salt  string   // Random if empty, so separate exports cannot be joined
+}
+
+// Anonymizer rewrites entries so they can be shared without identifying
+// anyone, while keeping what statistics need: dates, times, types, status,
+// priority, durations, and visibility are kept, and IDs, tags, locations, and
+// metadata values become pseudonyms, the same for the same value, so
+// grouping by them still works. Tags keep their hierarchy.
+type Anonymizer struct {
+	strip bool
+	key   []byte
+	terms *regexp.Regexp
+}
+
+// NewAnonymizer creates an anonymizer
+func NewAnonymizer(opts AnonymizeOptions) (*Anonymizer, error) {
+	if err := ValidateAnonymizeMode(opts.Mode); err != nil {
+		return nil, err
+	}
+
+	a := &Anonymizer{strip: opts.Mode == AnonymizeStrip, key: []byte(opts.Salt)}
+	if opts.Salt == "" {
+		a.key = make([]byte, 32)
+		if _, err := rand.Read(a.key); err != nil {
+			return nil, fmt.Errorf("failed to generate anonymization salt: %v", err)
+		}
+	}
+
+	var patterns []string
+	for _, term := range opts.Terms {
+		if term = strings.TrimSpace(term); term != "" {
+			patterns = append(patterns, termPattern(term))
+		}
+	}
+	if len(patterns) > 0 {
+		re, err := regexp.Compile("(?i)" + strings.Join(patterns, "|"))
+		if err != nil {
+			return nil, storage.ValidationError{Field: "anonymize terms", Message: err.Error()}
+		}
+		a.terms = re
+	}
+	return a, nil
+}
+
+// termPattern matches term as a whole word. Word boundaries are only required
+// next to letters and digits, so terms such as "C++" still match.
+func termPattern(term string) string {
+	pattern := regexp.QuoteMeta(term)
+	if isWordByte(term[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(term[len(term)-1]) {
+		pattern += `\b`
+	}
+	return pattern
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// pseudonym returns a stable stand-in for value, e.g. "location-3f9a0c1e"
+func (a *Anonymizer) pseudonym(kind, value string) string {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(kind + "\x00" + strings.ToLower(strings.TrimSpace(value))))
+	return kind + "-" + hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// Text anonymizes free text: in hash mode the sensitive terms in it are
+// replaced by pseudonyms, in strip mode it is dropped
+func (a *Anonymizer) Text(text string) string {
+	if a.strip {
+		return ""
+	}
+	if a.terms == nil {
+		return text
+	}
+	return a.terms.ReplaceAllStringFunc(text, func(term string) string {
+		return a.pseudonym("term", term)
+	})
+}
+
+// Entry returns an anonymized copy of entry
+func (a *Anonymizer) Entry(entry storage.DailyLogEntry) storage.DailyLogEntry {
+	if entry.ID != "" {
+		entry.ID = a.pseudonym("entry", entry.ID)
+	}
+	entry.Title = a.Text(entry.Title)
+	if entry.Title == "" {
+		// Keep something to show where a title is required, e.g. in calendars
+		entry.Title = entry.Type
+	}
+	entry.Description = a.Text(entry.Description)
+
+	if a.strip {
+		entry.Location = ""
+	} else if entry.Location != "" {
+		entry.Location = a.pseudonym("location", entry.Location)
+	}
+	if len(entry.Tags) > 0 {
+		tags := make([]string, len(entry.Tags))
+		for i, tag := range entry.Tags {
+			segments := strings.Split(tag, storage.TagSeparator)
+			for j, segment := range segments {
+				segments[j] = a.pseudonym("tag", segment)
+			}
+			tags[i] = strings.Join(segments, storage.TagSeparator)
+		}
+		entry.Tags = tags
+	}
+	if len(entry.Metadata) > 0 {
+		metadata := make(map[string]string, len(entry.Metadata))
+		for key, value := range entry.Metadata {
+			metadata[key] = a.pseudonym(key, value)
+		}
+		entry.Metadata = metadata
+	}
+	return entry
+}
+
+// Entries returns anonymized copies of entries
+func (a *Anonymizer) Entries(entries []storage.DailyLogEntry) []storage.DailyLogEntry {
+	anonymized := make([]storage.DailyLogEntry, len(entries))
+	for i, entry := range entries {
+		anonymized[i] = a.Entry(entry)
+	}
+	return anonymized
+}
+
+// Day returns an anonymized copy of dayLog, without its metadata
+func (a *Anonymizer) Day(dayLog storage.DayLog) storage.DayLog {
+	dayLog.Entries = a.Entries(dayLog.Entries)
+	dayLog.DaySummary = a.Text(dayLog.DaySummary)
+	dayLog.Metadata = nil
+	return dayLog
+}
@@ -0,0 +1,345 @@
+package exporters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// WriteVJournal writes an entry of the day date as an iCalendar object
+// holding a single VJOURNAL, as stored on a CalDAV server
+func WriteVJournal(w io.Writer, date time.Time, entry storage.DailyLogEntry) error {
+	bw := bufio.NewWriter(w)
+
+	writeICSLine(bw, "BEGIN:VCALENDAR")
+	writeICSLine(bw, "VERSION:2.0")
+	writeICSLine(bw, "PRODID:-//dailylog//dailyctl//EN")
+	writeICSLine(bw, "BEGIN:VJOURNAL")
+	writeICSLine(bw, "UID:"+entry.ID+"@dailylog")
+	// DTSTAMP is derived from the entry so unchanged entries render identically
+	stamp := entry.Timestamp
+	if stamp.IsZero() {
+		stamp = date
+	}
+	writeICSLine(bw, "DTSTAMP:"+stamp.UTC().Format(icsTimeFormat))
+	if entry.Timestamp.IsZero() {
+		writeICSLine(bw, "DTSTART;VALUE=DATE:"+date.Format("20060102"))
+	} else {
+		writeICSLine(bw, "DTSTART:"+entry.Timestamp.UTC().Format(icsTimeFormat))
+	}
+	writeICSLine(bw, "SUMMARY:"+escapeICSText(entry.Title))
+	if entry.Description != "" {
+		writeICSLine(bw, "DESCRIPTION:"+escapeICSText(entry.Description))
+	}
+	// VJOURNAL has no LOCATION property
+	if entry.Location != "" {
+		writeICSLine(bw, "COMMENT:"+escapeICSText("Location: "+entry.Location))
+	}
+	categories := []string{escapeICSText(entry.Type)}
+	for _, tag := range entry.Tags {
+		categories = append(categories, escapeICSText(tag))
+	}
+	writeICSLine(bw, "CATEGORIES:"+strings.Join(categories, ","))
+	writeICSLine(bw, "CLASS:"+journalClass(entry.Visibility))
+	writeICSLine(bw, "STATUS:FINAL")
+	writeICSLine(bw, "END:VJOURNAL")
+	writeICSLine(bw, "END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write iCalendar data: %v", err)
+	}
+	return nil
+}
+
+// journalClass maps an entry's visibility to an iCalendar access classification
+func journalClass(visibility string) string {
+	switch visibility {
+	case storage.VisibilityPublic:
+		return "PUBLIC"
+	case storage.VisibilityPrivate:
+		return "PRIVATE"
+	default:
+		return "CONFIDENTIAL"
+	}
+}
+
+// CalDAVClient stores iCalendar objects in a CalDAV calendar collection
+type CalDAVClient struct {
+	client     *http.Client
+	ctx        context.Context
+	collection *url.URL
+	username   string
+	password   string
+}
+
+// NewCalDAVClient creates a client for the calendar collection at
+// collectionURL, such as https://cloud.example.com/remote.php/dav/calendars/me/journal/
+func NewCalDAVClient(collectionURL, username, password string) (*CalDAVClient, error) {
+	if collectionURL == "" {
+		return nil, fmt.Errorf("CalDAV collection URL is required")
+	}
+	collection, err := url.Parse(collectionURL)
+	if err != nil || (collection.Scheme != "http" && collection.Scheme != "https") || collection.Host == "" {
+		return nil, fmt.Errorf("invalid CalDAV collection URL: %s", collectionURL)
+	}
+	if !strings.HasSuffix(collection.Path, "/") {
+		collection.Path += "/"
+	}
+
+	return &CalDAVClient{
+		client:     http.DefaultClient,
+		ctx:        context.Background(),
+		collection: collection,
+		username:   username,
+		password:   password,
+	}, nil
+}
+
+// URL returns the collection's URL
+func (c *CalDAVClient) URL() string {
+	return c.collection.String()
+}
+
+// itemPath returns the path of the object named name in the collection
+func (c *CalDAVClient) itemPath(name string) string {
+	return c.collection.Path + name
+}
+
+// List returns the ETags of the objects in the collection, by path
+func (c *CalDAVClient) List() (map[string]string, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/></d:prop></d:propfind>`
+	req, err := c.newRequest("PROPFIND", c.collection.Path, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Depth", "1")
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list CalDAV collection: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, fmt.Errorf("failed to list CalDAV collection: %s", resp.Status)
+	}
+
+	var status struct {
+		Responses []struct {
+			Href  string   `xml:"href"`
+			ETags []string `xml:"propstat>prop>getetag"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV collection listing: %v", err)
+	}
+
+	etags := make(map[string]string)
+	for _, response := range status.Responses {
+		href, err := c.collection.Parse(strings.TrimSpace(response.Href))
+		if err != nil || !strings.HasSuffix(href.Path, ".ics") {
+			continue
+		}
+		etag := ""
+		if len(response.ETags) > 0 {
+			etag = response.ETags[0]
+		}
+		etags[href.Path] = etag
+	}
+	return etags, nil
+}
+
+// Put stores data as the object named name, returning its new ETag if the
+// server reports one
+func (c *CalDAVClient) Put(name string, data []byte) (string, error) {
+	req, err := c.newRequest(http.MethodPut, c.itemPath(name), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s: %v", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("failed to upload %s: %s", name, resp.Status)
+	}
+	return resp.Header.Get("ETag"), nil
+}
+
+// Delete removes the object at itemPath; a missing object is not an error
+func (c *CalDAVClient) Delete(itemPath string) error {
+	req, err := c.newRequest(http.MethodDelete, itemPath, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %v", itemPath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		return fmt.Errorf("failed to delete %s: %s", itemPath, resp.Status)
+	}
+	return nil
+}
+
+func (c *CalDAVClient) newRequest(method, itemPath string, body io.Reader) (*http.Request, error) {
+	target := *c.collection
+	target.Path = itemPath
+	target.RawPath = ""
+	req, err := http.NewRequestWithContext(c.ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return req, nil
+}
+
+// CalDAVItem is an entry published to a CalDAV collection
+type CalDAVItem struct {
+	Path string `json:"path"`
+	Date string `json:"date"`
+	ETag string `json:"etag,omitempty"`
+	Hash string `json:"hash"`
+}
+
+// CalDAVState remembers what was published to a collection, so unchanged
+// entries are skipped and deleted ones removed
+type CalDAVState struct {
+	URL   string                `json:"url"`
+	Items map[string]CalDAVItem `json:"items"` // By entry ID
+}
+
+// LoadCalDAVState reads the state of the collection at collectionURL from
+// path; a missing file, or one for another collection, starts afresh
+func LoadCalDAVState(path, collectionURL string) (*CalDAVState, error) {
+	state := &CalDAVState{URL: collectionURL, Items: make(map[string]CalDAVItem)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CalDAV sync state: %v", err)
+	}
+
+	var stored CalDAVState
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("failed to parse CalDAV sync state %s: %v", path, err)
+	}
+	if stored.URL == collectionURL && stored.Items != nil {
+		state.Items = stored.Items
+	}
+	return state, nil
+}
+
+// Save writes the state to path
+func (s *CalDAVState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to save CalDAV sync state: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save CalDAV sync state: %v", err)
+	}
+	return nil
+}
+
+// CalDAVSyncResult counts what a sync did, or would do in a dry run
+type CalDAVSyncResult struct {
+	Uploaded  int `json:"uploaded" yaml:"uploaded"`
+	Unchanged int `json:"unchanged" yaml:"unchanged"`
+	Deleted   int `json:"deleted" yaml:"deleted"`
+}
+
+// SyncCalDAV publishes the entries of days, all between start and end, as
+// VJOURNALs. Entries whose content and server copy are unchanged since the
+// last sync are skipped, and entries published before that are no longer in
+// days within start and end are deleted from the server. state is updated
+// unless dryRun is set.
+func SyncCalDAV(client *CalDAVClient, state *CalDAVState, days []storage.DayLog, start, end time.Time, dryRun bool) (CalDAVSyncResult, error) {
+	var result CalDAVSyncResult
+
+	remote, err := client.List()
+	if err != nil {
+		return result, err
+	}
+
+	current := make(map[string]bool)
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			current[entry.ID] = true
+
+			var buf bytes.Buffer
+			if err := WriteVJournal(&buf, day.Date, entry); err != nil {
+				return result, err
+			}
+			sum := sha256.Sum256(buf.Bytes())
+			hash := hex.EncodeToString(sum[:])
+
+			name := strings.ReplaceAll(entry.ID, "/", "_") + ".ics"
+			previous, published := state.Items[entry.ID]
+			etag, onServer := remote[previous.Path]
+			if published && onServer && previous.Hash == hash && (previous.ETag == "" || previous.ETag == etag) {
+				result.Unchanged++
+				continue
+			}
+
+			result.Uploaded++
+			if dryRun {
+				continue
+			}
+			etag, err := client.Put(name, buf.Bytes())
+			if err != nil {
+				return result, err
+			}
+			state.Items[entry.ID] = CalDAVItem{
+				Path: client.itemPath(name),
+				Date: day.GetDateString(),
+				ETag: etag,
+				Hash: hash,
+			}
+		}
+	}
+
+	from, to := start.Format("2006-01-02"), end.Format("2006-01-02")
+	for id, item := range state.Items {
+		if current[id] || item.Date < from || item.Date > to {
+			continue
+		}
+		result.Deleted++
+		if dryRun {
+			continue
+		}
+		if err := client.Delete(item.Path); err != nil {
+			return result, err
+		}
+		delete(state.Items, id)
+	}
+
+	return result, nil
+}
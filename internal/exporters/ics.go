@@ -0,0 +1,96 @@
+package exporters
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// WriteICS writes timed entries (timestamp plus duration) as iCalendar VEVENTs.
+// Entries without a duration are skipped. It returns the number of events written.
+func WriteICS(w io.Writer, days []storage.DayLog) (int, error) {
+	bw := bufio.NewWriter(w)
+	stamp := time.Now().UTC().Format(icsTimeFormat)
+
+	writeICSLine(bw, "BEGIN:VCALENDAR")
+	writeICSLine(bw, "VERSION:2.0")
+	writeICSLine(bw, "PRODID:-//dailylog//dailyctl//EN")
+	writeICSLine(bw, "CALSCALE:GREGORIAN")
+	writeICSLine(bw, "X-WR-CALNAME:Daily Log")
+
+	count := 0
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			if entry.Duration == nil || *entry.Duration <= 0 {
+				continue
+			}
+
+			start := entry.Timestamp.UTC()
+			end := start.Add(time.Duration(*entry.Duration) * time.Minute)
+
+			writeICSLine(bw, "BEGIN:VEVENT")
+			writeICSLine(bw, "UID:"+entry.ID+"@dailylog")
+			writeICSLine(bw, "DTSTAMP:"+stamp)
+			writeICSLine(bw, "DTSTART:"+start.Format(icsTimeFormat))
+			writeICSLine(bw, "DTEND:"+end.Format(icsTimeFormat))
+			writeICSLine(bw, "SUMMARY:"+escapeICSText(entry.Title))
+			if entry.Description != "" {
+				writeICSLine(bw, "DESCRIPTION:"+escapeICSText(entry.Description))
+			}
+			if entry.Location != "" {
+				writeICSLine(bw, "LOCATION:"+escapeICSText(entry.Location))
+			}
+			if len(entry.Tags) > 0 {
+				tags := make([]string, len(entry.Tags))
+				for i, tag := range entry.Tags {
+					tags[i] = escapeICSText(tag)
+				}
+				writeICSLine(bw, "CATEGORIES:"+strings.Join(tags, ","))
+			}
+			writeICSLine(bw, "END:VEVENT")
+			count++
+		}
+	}
+
+	writeICSLine(bw, "END:VCALENDAR")
+
+	if err := bw.Flush(); err != nil {
+		return count, fmt.Errorf("failed to write iCalendar data: %v", err)
+	}
+	return count, nil
+}
+
+// writeICSLine writes a content line, folding it at 75 octets as RFC 5545 requires
+func writeICSLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Avoid splitting a multi-byte UTF-8 sequence
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		w.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards the limit
+		limit = 74
+	}
+	w.WriteString(line + "\r\n")
+}
+
+// escapeICSText escapes characters with special meaning in iCalendar TEXT values
+func escapeICSText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(s)
+}
@@ -0,0 +1,61 @@
+package exporters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"dailylog/internal/storage"
+)
+
+// WriteInvoiceCSV writes an invoice's lines as CSV, one row per line, with
+// hours in decimal for spreadsheets and accounting tools
+func WriteInvoiceCSV(w io.Writer, invoice storage.Invoice) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"date", "description", "hours", "rate", "amount", "currency"})
+	for _, line := range invoice.Lines {
+		_ = cw.Write([]string{
+			line.Date,
+			line.Title,
+			fmt.Sprintf("%.2f", float64(line.Minutes)/60),
+			fmt.Sprintf("%.2f", line.Rate),
+			fmt.Sprintf("%.2f", line.Amount),
+			invoice.Currency,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteInvoiceMarkdown writes an invoice as a Markdown table with a total,
+// ready to paste into an invoice or an email
+func WriteInvoiceMarkdown(w io.Writer, invoice storage.Invoice) error {
+	title := "Invoice"
+	if invoice.Project != "" {
+		title += ": " + invoice.Project
+	}
+	currency := ""
+	if invoice.Currency != "" {
+		currency = " " + invoice.Currency
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	fmt.Fprintf(&b, "Period: %s\n\n", invoice.Period)
+	b.WriteString("| Date | Description | Hours | Rate | Amount |\n")
+	b.WriteString("|------|-------------|------:|-----:|-------:|\n")
+	for _, line := range invoice.Lines {
+		fmt.Fprintf(&b, "| %s | %s | %.2f | %.2f | %.2f |\n",
+			line.Date, markdownCell(line.Title), float64(line.Minutes)/60, line.Rate, line.Amount)
+	}
+	fmt.Fprintf(&b, "| | **Total** | **%.2f** | | **%.2f%s** |\n", float64(invoice.Minutes)/60, invoice.Total, currency)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes text for a table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,70 @@
+package exporters
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// JSONLRecord is a single line of JSON Lines output: an entry plus the day it belongs to
+type JSONLRecord struct {
+	Date string `json:"date"`
+	storage.DailyLogEntry
+}
+
+// ProgressFunc is called after each day is exported
+type ProgressFunc func(done, total int, day time.Time, entries int)
+
+// JSONLOptions controls what WriteJSONL exports and how it reports progress
+type JSONLOptions struct {
+	Policy     storage.VisibilityPolicy
+	Audience   string
+	Anonymizer *Anonymizer // Optional
+	Progress   ProgressFunc
+}
+
+// WriteJSONL streams the entries of the given days as one JSON object per line.
+// Days are fetched one at a time so the whole archive is never held in memory.
+// It returns the number of entries written.
+func WriteJSONL(w io.Writer, store storage.DailyLogStorage, days []time.Time, opts JSONLOptions) (int, error) {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+
+	count := 0
+	for i, day := range days {
+		dayLog, err := store.GetDay(day)
+		if err != nil {
+			return count, err
+		}
+
+		entries := opts.Policy.Filter(dayLog.Entries, opts.Audience)
+		if opts.Anonymizer != nil {
+			entries = opts.Anonymizer.Entries(entries)
+		}
+		for _, entry := range entries {
+			record := JSONLRecord{
+				Date:          dayLog.GetDateString(),
+				DailyLogEntry: entry,
+			}
+			if err := encoder.Encode(record); err != nil {
+				return count, fmt.Errorf("failed to encode entry %s: %v", entry.ID, err)
+			}
+			count++
+		}
+
+		// Flush per day so downstream consumers see output as it is produced
+		if err := bw.Flush(); err != nil {
+			return count, fmt.Errorf("failed to write JSON Lines data: %v", err)
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(days), day, len(entries))
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,263 @@
+package importers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Apple Health record and workout types read from export.xml
+const (
+	appleStepCount      = "HKQuantityTypeIdentifierStepCount"
+	appleBodyMass       = "HKQuantityTypeIdentifierBodyMass"
+	appleSleepAnalysis  = "HKCategoryTypeIdentifierSleepAnalysis"
+	appleAsleepPrefix   = "HKCategoryValueSleepAnalysisAsleep"
+	appleWorkoutPrefix  = "HKWorkoutActivityType"
+	appleDateTimeFormat = "2006-01-02 15:04:05 -0700"
+)
+
+// AppleHealthImporter imports an Apple Health export (the export.zip from the
+// Health app, or its export.xml): daily steps and sleep and each weight
+// reading become metric entries, and workouts become activity entries.
+type AppleHealthImporter struct {
+	path string
+}
+
+// NewAppleHealthImporter creates an importer for the export at path
+func NewAppleHealthImporter(path string) (*AppleHealthImporter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path to the Apple Health export is required")
+	}
+	return &AppleHealthImporter{path: path}, nil
+}
+
+// Name returns the source identifier for Apple Health imports
+func (a *AppleHealthImporter) Name() string {
+	return "apple-health"
+}
+
+type appleRecord struct {
+	Type       string `xml:"type,attr"`
+	SourceName string `xml:"sourceName,attr"`
+	Unit       string `xml:"unit,attr"`
+	Value      string `xml:"value,attr"`
+	StartDate  string `xml:"startDate,attr"`
+	EndDate    string `xml:"endDate,attr"`
+}
+
+type appleWorkout struct {
+	ActivityType          string `xml:"workoutActivityType,attr"`
+	Duration              string `xml:"duration,attr"`
+	DurationUnit          string `xml:"durationUnit,attr"`
+	TotalDistance         string `xml:"totalDistance,attr"`
+	TotalDistanceUnit     string `xml:"totalDistanceUnit,attr"`
+	TotalEnergyBurned     string `xml:"totalEnergyBurned,attr"`
+	TotalEnergyBurnedUnit string `xml:"totalEnergyBurnedUnit,attr"`
+	SourceName            string `xml:"sourceName,attr"`
+	StartDate             string `xml:"startDate,attr"`
+	EndDate               string `xml:"endDate,attr"`
+	Statistics            []struct {
+		Type string `xml:"type,attr"`
+		Sum  string `xml:"sum,attr"`
+		Unit string `xml:"unit,attr"`
+	} `xml:"WorkoutStatistics"`
+}
+
+// Fetch reads the export and returns entries for everything between start and end
+func (a *AppleHealthImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	fsys, root, closeExport, err := openExport(a.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeExport()
+
+	var exportPath string
+	err = fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && path.Ext(p) == ".xml" && (p == root || path.Base(p) == "export.xml") {
+			exportPath = p
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Apple Health export: %v", err)
+	}
+	if exportPath == "" {
+		return nil, fmt.Errorf("no export.xml found in %s", a.path)
+	}
+
+	file, err := fsys.Open(exportPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", exportPath, err)
+	}
+	defer file.Close()
+
+	reqs, err := a.parse(file, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", exportPath, err)
+	}
+	return reqs, nil
+}
+
+// parse streams export.xml, which can run to gigabytes
+func (a *AppleHealthImporter) parse(r io.Reader, start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	var reqs []storage.CreateLogEntryRequest
+	steps := make(dailyTotals)
+	sleep := make(dailyTotals)
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch element.Name.Local {
+		case "Record":
+			if !isAppleRecordWanted(element) {
+				if err := decoder.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			var record appleRecord
+			if err := decoder.DecodeElement(&record, &element); err != nil {
+				return nil, err
+			}
+			recordStart, err1 := time.Parse(appleDateTimeFormat, record.StartDate)
+			recordEnd, err2 := time.Parse(appleDateTimeFormat, record.EndDate)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+
+			switch record.Type {
+			case appleStepCount:
+				count, err := strconv.ParseFloat(record.Value, 64)
+				if err == nil {
+					steps.add(startOfDay(recordStart), record.SourceName, count, recordEnd)
+				}
+			case appleSleepAnalysis:
+				// Nights count towards the day they end on
+				if strings.HasPrefix(record.Value, appleAsleepPrefix) {
+					sleep.add(startOfDay(recordEnd), record.SourceName, recordEnd.Sub(recordStart).Hours(), recordEnd)
+				}
+			case appleBodyMass:
+				weight, err := storage.ParseMetric("weight", record.Value+record.Unit)
+				if err == nil && inRange(recordStart, start, end) {
+					id := "weight:" + recordStart.Format(time.RFC3339)
+					reqs = append(reqs, metricRequest(a.Name(), id, recordStart, weight, 0))
+				}
+			}
+
+		case "Workout":
+			var w appleWorkout
+			if err := decoder.DecodeElement(&w, &element); err != nil {
+				return nil, err
+			}
+			if req, ok := a.workoutRequest(w); ok && inRange(req.Date, start, end) {
+				reqs = append(reqs, req)
+			}
+		}
+	}
+
+	for _, total := range steps.totals() {
+		at := total.last
+		if at.After(endOfDay(total.day)) {
+			at = endOfDay(total.day)
+		}
+		if inRange(at, start, end) {
+			metric := storage.Metric{Name: "steps", Value: total.value, Unit: "steps"}
+			reqs = append(reqs, metricRequest(a.Name(), "steps:"+total.day.Format("2006-01-02"), at, metric, 0))
+		}
+	}
+	for _, total := range sleep.totals() {
+		if inRange(total.last, start, end) {
+			metric := storage.Metric{Name: "sleep", Value: total.value, Unit: "h"}
+			duration := time.Duration(total.value * float64(time.Hour))
+			reqs = append(reqs, metricRequest(a.Name(), "sleep:"+total.day.Format("2006-01-02"), total.last, metric, duration))
+		}
+	}
+
+	sortRequests(reqs)
+	return reqs, nil
+}
+
+// isAppleRecordWanted checks a record's type before decoding it, as most of
+// an export is records of other types
+func isAppleRecordWanted(element xml.StartElement) bool {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == "type" {
+			return attr.Value == appleStepCount || attr.Value == appleBodyMass || attr.Value == appleSleepAnalysis
+		}
+	}
+	return false
+}
+
+func (a *AppleHealthImporter) workoutRequest(w appleWorkout) (storage.CreateLogEntryRequest, bool) {
+	workoutStart, err := time.Parse(appleDateTimeFormat, w.StartDate)
+	if err != nil {
+		return storage.CreateLogEntryRequest{}, false
+	}
+	workoutEnd, err := time.Parse(appleDateTimeFormat, w.EndDate)
+	if err != nil {
+		workoutEnd = workoutStart
+	}
+
+	activity := strings.TrimPrefix(w.ActivityType, appleWorkoutPrefix)
+	converted := workout{
+		ID:       "workout:" + workoutStart.Format(time.RFC3339) + ":" + activity,
+		Activity: activityName(activity),
+		Start:    workoutStart,
+		End:      workoutEnd,
+		Distance: appleAmount(w.TotalDistance, w.TotalDistanceUnit),
+		Energy:   appleAmount(w.TotalEnergyBurned, w.TotalEnergyBurnedUnit),
+		Device:   w.SourceName,
+	}
+	if duration, err := strconv.ParseFloat(w.Duration, 64); err == nil {
+		switch w.DurationUnit {
+		case "min", "":
+			converted.Duration = time.Duration(duration * float64(time.Minute))
+		case "hr":
+			converted.Duration = time.Duration(duration * float64(time.Hour))
+		case "s":
+			converted.Duration = time.Duration(duration * float64(time.Second))
+		}
+	}
+	// Newer exports keep totals in statistics instead of attributes
+	for _, stat := range w.Statistics {
+		switch {
+		case converted.Distance == "" && strings.Contains(stat.Type, "Distance"):
+			converted.Distance = appleAmount(stat.Sum, stat.Unit)
+		case converted.Energy == "" && strings.HasSuffix(stat.Type, "ActiveEnergyBurned"):
+			converted.Energy = appleAmount(stat.Sum, stat.Unit)
+		}
+	}
+
+	return workoutRequest(converted, a.Name()), true
+}
+
+// appleAmount formats a total such as "5.2 km", or returns "" without one
+func appleAmount(value, unit string) string {
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount == 0 {
+		return ""
+	}
+	return formatAmount(amount, unit)
+}
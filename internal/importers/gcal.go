@@ -0,0 +1,205 @@
+package importers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"dailylog/internal/storage"
+)
+
+const googleCalendarAPIBase = "https://www.googleapis.com/calendar/v3"
+
+// GoogleCalendarImporter imports calendar events as meeting activities
+type GoogleCalendarImporter struct {
+	client     *http.Client
+	ctx        context.Context
+	calendarID string
+	baseURL    string
+}
+
+// gcalEventList mirrors the subset of the Calendar API events.list response we use
+type gcalEventList struct {
+	Items         []gcalEvent `json:"items"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+type gcalEvent struct {
+	ID          string         `json:"id"`
+	Status      string         `json:"status"`
+	Summary     string         `json:"summary"`
+	Description string         `json:"description"`
+	Location    string         `json:"location"`
+	HTMLLink    string         `json:"htmlLink"`
+	Start       gcalEventTime  `json:"start"`
+	End         gcalEventTime  `json:"end"`
+	Attendees   []gcalAttendee `json:"attendees"`
+}
+
+type gcalEventTime struct {
+	DateTime string `json:"dateTime"`
+	Date     string `json:"date"`
+}
+
+type gcalAttendee struct {
+	Email          string `json:"email"`
+	DisplayName    string `json:"displayName"`
+	Self           bool   `json:"self"`
+	Resource       bool   `json:"resource"`
+	ResponseStatus string `json:"responseStatus"`
+}
+
+// NewGoogleCalendarImporter creates a new Google Calendar importer
+func NewGoogleCalendarImporter(token, calendarID string) (*GoogleCalendarImporter, error) {
+	if token == "" {
+		return nil, fmt.Errorf("Google Calendar access token is required")
+	}
+	if calendarID == "" {
+		calendarID = "primary"
+	}
+
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+
+	return &GoogleCalendarImporter{
+		client:     oauth2.NewClient(context.Background(), ts),
+		ctx:        context.Background(),
+		calendarID: calendarID,
+		baseURL:    googleCalendarAPIBase,
+	}, nil
+}
+
+// Name returns the source identifier for calendar imports
+func (g *GoogleCalendarImporter) Name() string {
+	return "gcal"
+}
+
+// Fetch retrieves timed events between start and end as meeting activities.
+// All-day events, cancelled events and events the user declined are skipped.
+func (g *GoogleCalendarImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	var reqs []storage.CreateLogEntryRequest
+	pageToken := ""
+
+	for {
+		list, err := g.listEvents(start, end, pageToken)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range list.Items {
+			if req, ok := g.toEntryRequest(event); ok {
+				reqs = append(reqs, req)
+			}
+		}
+
+		if list.NextPageToken == "" {
+			break
+		}
+		pageToken = list.NextPageToken
+	}
+
+	return reqs, nil
+}
+
+func (g *GoogleCalendarImporter) listEvents(start, end time.Time, pageToken string) (*gcalEventList, error) {
+	params := url.Values{}
+	params.Set("timeMin", start.Format(time.RFC3339))
+	params.Set("timeMax", end.Format(time.RFC3339))
+	params.Set("singleEvents", "true")
+	params.Set("orderBy", "startTime")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	endpoint := fmt.Sprintf("%s/calendars/%s/events?%s",
+		g.baseURL, url.PathEscape(g.calendarID), params.Encode())
+
+	req, err := http.NewRequestWithContext(g.ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list calendar events: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list calendar events: %s", resp.Status)
+	}
+
+	var list gcalEventList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to parse calendar events: %v", err)
+	}
+
+	return &list, nil
+}
+
+func (g *GoogleCalendarImporter) toEntryRequest(event gcalEvent) (storage.CreateLogEntryRequest, bool) {
+	if event.Status == "cancelled" || event.Start.DateTime == "" {
+		return storage.CreateLogEntryRequest{}, false
+	}
+
+	startTime, err := time.Parse(time.RFC3339, event.Start.DateTime)
+	if err != nil {
+		return storage.CreateLogEntryRequest{}, false
+	}
+	endTime, err := time.Parse(time.RFC3339, event.End.DateTime)
+	if err != nil {
+		endTime = startTime
+	}
+
+	var attendees []string
+	for _, attendee := range event.Attendees {
+		if attendee.Self && attendee.ResponseStatus == "declined" {
+			return storage.CreateLogEntryRequest{}, false
+		}
+		if attendee.Resource || attendee.Self {
+			continue
+		}
+		name := attendee.Email
+		if attendee.DisplayName != "" {
+			name = attendee.DisplayName
+		}
+		attendees = append(attendees, name)
+	}
+
+	title := event.Summary
+	if title == "" {
+		title = "(untitled meeting)"
+	}
+
+	metadata := map[string]string{
+		MetadataSource:     g.Name(),
+		MetadataExternalID: event.ID,
+	}
+	if len(attendees) > 0 {
+		metadata["attendees"] = strings.Join(attendees, ", ")
+	}
+	if event.HTMLLink != "" {
+		metadata["link"] = event.HTMLLink
+	}
+
+	req := storage.CreateLogEntryRequest{
+		Date:        startTime.Local(),
+		Type:        "activity",
+		Title:       title,
+		Description: event.Description,
+		Tags:        []string{"meeting"},
+		Location:    event.Location,
+		Metadata:    metadata,
+	}
+
+	if minutes := int(endTime.Sub(startTime).Minutes()); minutes > 0 {
+		req.Duration = &minutes
+	}
+
+	return req, true
+}
@@ -0,0 +1,179 @@
+package importers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+// GitHubActivityImporter imports a user's GitHub events as activity entries
+type GitHubActivityImporter struct {
+	client *github.Client
+	ctx    context.Context
+	user   string
+}
+
+// NewGitHubActivityImporter creates a new GitHub activity importer.
+// A user of "me" (or empty) resolves to the owner of the token. baseURL, if
+// set, is the API of GitHub Enterprise Server or a proxy.
+func NewGitHubActivityImporter(token, baseURL, user string) (*GitHubActivityImporter, error) {
+	if token == "" {
+		return nil, fmt.Errorf("GitHub token is required")
+	}
+
+	client, err := providers.NewGitHubClient(token, baseURL, "")
+	if err != nil {
+		return nil, err
+	}
+
+	importer := &GitHubActivityImporter{
+		client: client,
+		ctx:    context.Background(),
+		user:   user,
+	}
+
+	if user == "" || user == "me" {
+		authUser, _, err := importer.client.Users.Get(importer.ctx, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve authenticated user: %v", err)
+		}
+		importer.user = authUser.GetLogin()
+	}
+
+	return importer, nil
+}
+
+// Name returns the source identifier for GitHub activity imports
+func (g *GitHubActivityImporter) Name() string {
+	return "github"
+}
+
+// Fetch retrieves pushes, pull request activity, and closed issues between start and end.
+// The Events API only exposes roughly the last 90 days of activity.
+func (g *GitHubActivityImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	var reqs []storage.CreateLogEntryRequest
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		events, resp, err := g.client.Activity.ListEventsPerformedByUser(g.ctx, g.user, false, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list GitHub events for %s: %v", g.user, err)
+		}
+
+		reachedStart := false
+		for _, event := range events {
+			createdAt := event.GetCreatedAt().Time
+			if createdAt.Before(start) {
+				reachedStart = true
+				continue
+			}
+			if !createdAt.Before(end) {
+				continue
+			}
+			if req, ok := g.toEntryRequest(event); ok {
+				reqs = append(reqs, req)
+			}
+		}
+
+		// Events are returned newest first, so stop paging once we are past the range
+		if reachedStart || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return reqs, nil
+}
+
+func (g *GitHubActivityImporter) toEntryRequest(event *github.Event) (storage.CreateLogEntryRequest, bool) {
+	payload, err := event.ParsePayload()
+	if err != nil {
+		return storage.CreateLogEntryRequest{}, false
+	}
+
+	repoName := event.GetRepo().GetName()
+	var title, description, link string
+	var tags []string
+
+	switch p := payload.(type) {
+	case *github.PushEvent:
+		if len(p.Commits) == 0 {
+			return storage.CreateLogEntryRequest{}, false
+		}
+		branch := strings.TrimPrefix(p.GetRef(), "refs/heads/")
+		title = fmt.Sprintf("Pushed %d commit(s) to %s@%s", len(p.Commits), repoName, branch)
+		var messages []string
+		for _, commit := range p.Commits {
+			firstLine := strings.SplitN(commit.GetMessage(), "\n", 2)[0]
+			messages = append(messages, fmt.Sprintf("- %s %s", shortSHA(commit.GetSHA()), firstLine))
+		}
+		description = strings.Join(messages, "\n")
+		tags = []string{"commit"}
+
+	case *github.PullRequestEvent:
+		pr := p.GetPullRequest()
+		switch {
+		case p.GetAction() == "opened":
+			title = fmt.Sprintf("Opened PR %s#%d: %s", repoName, pr.GetNumber(), pr.GetTitle())
+		case p.GetAction() == "closed" && pr.GetMerged():
+			title = fmt.Sprintf("Merged PR %s#%d: %s", repoName, pr.GetNumber(), pr.GetTitle())
+		default:
+			return storage.CreateLogEntryRequest{}, false
+		}
+		link = pr.GetHTMLURL()
+		tags = []string{"pull-request"}
+
+	case *github.PullRequestReviewEvent:
+		pr := p.GetPullRequest()
+		title = fmt.Sprintf("Reviewed PR %s#%d: %s", repoName, pr.GetNumber(), pr.GetTitle())
+		if state := p.GetReview().GetState(); state != "" {
+			description = "Review: " + strings.ToLower(state)
+		}
+		link = pr.GetHTMLURL()
+		tags = []string{"review"}
+
+	case *github.IssuesEvent:
+		if p.GetAction() != "closed" {
+			return storage.CreateLogEntryRequest{}, false
+		}
+		issue := p.GetIssue()
+		title = fmt.Sprintf("Closed issue %s#%d: %s", repoName, issue.GetNumber(), issue.GetTitle())
+		link = issue.GetHTMLURL()
+		tags = []string{"issue"}
+
+	default:
+		return storage.CreateLogEntryRequest{}, false
+	}
+
+	metadata := map[string]string{
+		MetadataSource:     g.Name(),
+		MetadataExternalID: event.GetID(),
+		"repo":             repoName,
+	}
+	if link != "" {
+		metadata["link"] = link
+	}
+
+	return storage.CreateLogEntryRequest{
+		Date:        event.GetCreatedAt().Time.Local(),
+		Type:        "activity",
+		Title:       title,
+		Description: description,
+		Tags:        append([]string{"github", path.Base(repoName)}, tags...),
+		Metadata:    metadata,
+	}, true
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
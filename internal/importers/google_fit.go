@@ -0,0 +1,257 @@
+package importers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// googleFitIdleActivities are session types that are not workouts
+var googleFitIdleActivities = map[string]bool{
+	"still":      true,
+	"unknown":    true,
+	"in_vehicle": true,
+	"tilting":    true,
+}
+
+// GoogleFitImporter imports Google Fit data from a Google Takeout export (the
+// zip, or its extracted Fit folder): sleep sessions and the daily step counts
+// and weights become metric entries, and other sessions become activity
+// entries.
+type GoogleFitImporter struct {
+	path string
+}
+
+// NewGoogleFitImporter creates an importer for the export at path
+func NewGoogleFitImporter(path string) (*GoogleFitImporter, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path to the Google Takeout export is required")
+	}
+	return &GoogleFitImporter{path: path}, nil
+}
+
+// Name returns the source identifier for Google Fit imports
+func (g *GoogleFitImporter) Name() string {
+	return "google-fit"
+}
+
+type googleFitSession struct {
+	ID              string `json:"id"`
+	FitnessActivity string `json:"fitnessActivity"`
+	StartTime       string `json:"startTime"`
+	EndTime         string `json:"endTime"`
+	Duration        string `json:"duration"` // e.g. "1800.000s"
+	Aggregate       []struct {
+		MetricName string   `json:"metricName"`
+		FloatValue *float64 `json:"floatValue"`
+		IntValue   *int64   `json:"intValue"`
+	} `json:"aggregate"`
+}
+
+// Fetch reads the export and returns entries for everything between start and end
+func (g *GoogleFitImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	fsys, root, closeExport, err := openExport(g.path)
+	if err != nil {
+		return nil, err
+	}
+	defer closeExport()
+
+	var reqs []storage.CreateLogEntryRequest
+	sleep := make(dailyTotals)
+	found := false
+
+	err = fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch {
+		case path.Ext(p) == ".json" && path.Base(path.Dir(p)) == "All Sessions":
+			found = true
+			session, err := readGoogleFitSession(fsys, p)
+			if err != nil {
+				return err
+			}
+			if req, ok := g.sessionRequest(session, sleep); ok && inRange(req.Date, start, end) {
+				reqs = append(reqs, req)
+			}
+		case path.Ext(p) == ".csv" && strings.HasPrefix(path.Base(p), "Daily"):
+			daily, ok, err := g.readDailyMetrics(fsys, p, start, end)
+			if err != nil {
+				return err
+			}
+			if ok {
+				found = true
+				reqs = append(reqs, daily...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google Fit export: %v", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("no Google Fit sessions or daily metrics found in %s", g.path)
+	}
+
+	for _, total := range sleep.totals() {
+		if inRange(total.last, start, end) {
+			metric := storage.Metric{Name: "sleep", Value: total.value, Unit: "h"}
+			duration := time.Duration(total.value * float64(time.Hour))
+			reqs = append(reqs, metricRequest(g.Name(), "sleep:"+total.day.Format("2006-01-02"), total.last, metric, duration))
+		}
+	}
+
+	sortRequests(reqs)
+	return reqs, nil
+}
+
+func readGoogleFitSession(fsys fs.FS, p string) (googleFitSession, error) {
+	var session googleFitSession
+	data, err := fs.ReadFile(fsys, p)
+	if err != nil {
+		return session, err
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return session, fmt.Errorf("%s: %v", p, err)
+	}
+	return session, nil
+}
+
+// sessionRequest converts a workout session, or adds a sleep session to the
+// night it ends on
+func (g *GoogleFitImporter) sessionRequest(session googleFitSession, sleep dailyTotals) (storage.CreateLogEntryRequest, bool) {
+	sessionStart, err := time.Parse(time.RFC3339, session.StartTime)
+	if err != nil {
+		return storage.CreateLogEntryRequest{}, false
+	}
+	sessionEnd, err := time.Parse(time.RFC3339, session.EndTime)
+	if err != nil {
+		sessionEnd = sessionStart
+	}
+	sessionStart, sessionEnd = sessionStart.Local(), sessionEnd.Local()
+
+	activity := session.FitnessActivity
+	if activity == "sleep" || strings.HasPrefix(activity, "sleep.") {
+		sleep.add(startOfDay(sessionEnd), g.Name(), sessionEnd.Sub(sessionStart).Hours(), sessionEnd)
+		return storage.CreateLogEntryRequest{}, false
+	}
+	if activity == "" || googleFitIdleActivities[activity] {
+		return storage.CreateLogEntryRequest{}, false
+	}
+
+	converted := workout{
+		ID:       session.ID,
+		Activity: activityName(activity),
+		Start:    sessionStart,
+		End:      sessionEnd,
+	}
+	if converted.ID == "" {
+		converted.ID = "workout:" + sessionStart.UTC().Format(time.RFC3339) + ":" + activity
+	}
+	if duration, err := time.ParseDuration(session.Duration); err == nil {
+		converted.Duration = duration
+	}
+	for _, aggregate := range session.Aggregate {
+		var value float64
+		switch {
+		case aggregate.FloatValue != nil:
+			value = *aggregate.FloatValue
+		case aggregate.IntValue != nil:
+			value = float64(*aggregate.IntValue)
+		}
+		if value <= 0 {
+			continue
+		}
+		switch aggregate.MetricName {
+		case "com.google.distance.delta":
+			converted.Distance = formatAmount(value/1000, "km")
+		case "com.google.calories.expended":
+			converted.Energy = formatAmount(value, "kcal")
+		}
+	}
+
+	return workoutRequest(converted, g.Name()), true
+}
+
+// readDailyMetrics reads the daily summary CSV ("Daily activity metrics.csv"),
+// whose rows are whole days, so their values are logged at the end of the
+// day. Other CSV files, such as the per-day breakdowns, are not recognized.
+func (g *GoogleFitImporter) readDailyMetrics(fsys fs.FS, p string, start, end time.Time) ([]storage.CreateLogEntryRequest, bool, error) {
+	file, err := fsys.Open(p)
+	if err != nil {
+		return nil, false, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %v", p, err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	dateColumn, ok := columns["Date"]
+	if !ok {
+		return nil, false, nil
+	}
+	stepsColumn, hasSteps := columns["Step count"]
+	weightColumn, hasWeight := columns["Average weight (kg)"]
+	if !hasSteps && !hasWeight {
+		return nil, false, nil
+	}
+
+	field := func(row []string, column int, present bool) (float64, bool) {
+		if !present || column >= len(row) || strings.TrimSpace(row[column]) == "" {
+			return 0, false
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(row[column]), 64)
+		return value, err == nil && value > 0
+	}
+
+	var reqs []storage.CreateLogEntryRequest
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("%s: %v", p, err)
+		}
+		if dateColumn >= len(row) {
+			continue
+		}
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(row[dateColumn]), time.Local)
+		if err != nil {
+			continue
+		}
+		at := endOfDay(day)
+		if !inRange(at, start, end) {
+			continue
+		}
+		key := day.Format("2006-01-02")
+
+		if steps, ok := field(row, stepsColumn, hasSteps); ok {
+			metric := storage.Metric{Name: "steps", Value: steps, Unit: "steps"}
+			reqs = append(reqs, metricRequest(g.Name(), "steps:"+key, at, metric, 0))
+		}
+		if weight, ok := field(row, weightColumn, hasWeight); ok {
+			metric := storage.Metric{Name: "weight", Value: weight, Unit: "kg"}
+			reqs = append(reqs, metricRequest(g.Name(), "weight:"+key, at, metric, 0))
+		}
+	}
+	return reqs, true, nil
+}
@@ -0,0 +1,212 @@
+package importers
+
+import (
+	"archive/zip"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"dailylog/internal/storage"
+)
+
+// Health exports are files, not services, so the health importers read
+// everything in the export and keep what falls between start and end. A zero
+// start or end leaves that side of the range open.
+
+// openExport opens an export given as a zip archive, a directory, or a single
+// file. It returns the file system to walk, the root to walk from, and a
+// function that releases the export.
+func openExport(path string) (fs.FS, string, func() error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("failed to open export: %v", err)
+	}
+	if info.IsDir() {
+		return os.DirFS(path), ".", func() error { return nil }, nil
+	}
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("failed to open export archive: %v", err)
+		}
+		return archive, ".", archive.Close, nil
+	}
+	return os.DirFS(filepath.Dir(path)), filepath.Base(path), func() error { return nil }, nil
+}
+
+// inRange reports whether t falls between start (inclusive) and end (exclusive)
+func inRange(t, start, end time.Time) bool {
+	return (start.IsZero() || !t.Before(start)) && (end.IsZero() || t.Before(end))
+}
+
+// dailyTotals adds up samples such as step counts per day and recording
+// source. Phones and watches record the same steps and sleep, so a day's
+// total is taken from the source that recorded the most rather than summed.
+type dailyTotals map[string]map[string]*dailyTotal
+
+type dailyTotal struct {
+	day   time.Time
+	value float64
+	last  time.Time // End of the latest sample
+}
+
+func (d dailyTotals) add(day time.Time, source string, value float64, end time.Time) {
+	key := day.Format("2006-01-02")
+	if d[key] == nil {
+		d[key] = make(map[string]*dailyTotal)
+	}
+	total := d[key][source]
+	if total == nil {
+		total = &dailyTotal{day: day}
+		d[key][source] = total
+	}
+	total.value += value
+	if end.After(total.last) {
+		total.last = end
+	}
+}
+
+// totals returns each day's total, by day
+func (d dailyTotals) totals() []dailyTotal {
+	totals := make([]dailyTotal, 0, len(d))
+	for _, sources := range d {
+		var best *dailyTotal
+		for _, total := range sources {
+			if best == nil || total.value > best.value {
+				best = total
+			}
+		}
+		totals = append(totals, *best)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].day.Before(totals[j].day) })
+	return totals
+}
+
+// startOfDay returns midnight of t's day, in t's location
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// endOfDay returns the last minute of day, which daily totals are logged at
+// when their samples carry on past midnight or have no time at all
+func endOfDay(day time.Time) time.Time {
+	return startOfDay(day).Add(24*time.Hour - time.Minute)
+}
+
+// metricRequest builds a private metric entry for a health value
+func metricRequest(source, externalID string, at time.Time, metric storage.Metric, duration time.Duration) storage.CreateLogEntryRequest {
+	req := storage.CreateLogEntryRequest{
+		Date:       at,
+		Type:       storage.EntryTypeMetric,
+		Title:      metric.Title(),
+		Tags:       []string{"health"},
+		Visibility: storage.VisibilityPrivate,
+		Metadata: metric.Metadata(map[string]string{
+			MetadataSource:     source,
+			MetadataExternalID: externalID,
+		}),
+	}
+	if minutes := int(duration.Round(time.Minute).Minutes()); minutes > 0 {
+		req.Duration = &minutes
+	}
+	return req
+}
+
+// workout is the common shape of a workout before conversion
+type workout struct {
+	ID       string
+	Activity string // e.g. "Running"
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration // Moving time when known, else End - Start
+	Distance string        // e.g. "5.2 km"
+	Energy   string        // e.g. "310 kcal"
+	Device   string
+}
+
+// workoutRequest builds a private activity entry for a workout, tagged
+// "workout" and by activity
+func workoutRequest(w workout, source string) storage.CreateLogEntryRequest {
+	metadata := map[string]string{
+		MetadataSource:     source,
+		MetadataExternalID: w.ID,
+	}
+	var details []string
+	if w.Distance != "" {
+		metadata["distance"] = w.Distance
+		details = append(details, w.Distance)
+	}
+	if w.Energy != "" {
+		metadata["energy"] = w.Energy
+		details = append(details, w.Energy)
+	}
+	if w.Device != "" {
+		metadata["device"] = w.Device
+	}
+
+	req := storage.CreateLogEntryRequest{
+		Date:        w.Start,
+		Type:        "activity",
+		Title:       w.Activity,
+		Description: strings.Join(details, ", "),
+		Tags:        []string{"workout", tagFromName(w.Activity)},
+		Visibility:  storage.VisibilityPrivate,
+		Metadata:    metadata,
+	}
+
+	duration := w.Duration
+	if duration <= 0 {
+		duration = w.End.Sub(w.Start)
+	}
+	if minutes := int(duration.Round(time.Minute).Minutes()); minutes > 0 {
+		req.Duration = &minutes
+	}
+	return req
+}
+
+// activityName turns an activity identifier such as "TraditionalStrengthTraining"
+// or "biking.road" into words: "Traditional strength training", "Biking road"
+func activityName(identifier string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = nil
+		}
+	}
+	for _, r := range identifier {
+		switch {
+		case r == '.' || r == '_' || r == '-' || unicode.IsSpace(r):
+			flush()
+		case unicode.IsUpper(r):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+	if len(words) == 0 {
+		return "Workout"
+	}
+	name := strings.Join(words, " ")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// formatAmount formats a total with at most one decimal, e.g. "5.2 km"
+func formatAmount(amount float64, unit string) string {
+	return strings.TrimSpace(strconv.FormatFloat(math.Round(amount*10)/10, 'f', -1, 64) + " " + unit)
+}
+
+// sortRequests orders requests by time, so they are created in order
+func sortRequests(reqs []storage.CreateLogEntryRequest) {
+	sort.SliceStable(reqs, func(i, j int) bool { return reqs[i].Date.Before(reqs[j].Date) })
+}
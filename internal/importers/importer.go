@@ -0,0 +1,85 @@
+package importers
+
+import (
+	"errors"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Metadata keys used to trace imported entries back to their source
+const (
+	MetadataSource     = storage.MetadataSource
+	MetadataExternalID = storage.MetadataExternalID
+)
+
+// Importer defines the interface for pulling entries from external services
+type Importer interface {
+	// Name returns the source identifier recorded in entry metadata
+	Name() string
+	// Fetch returns entry requests for everything between start and end
+	Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error)
+}
+
+// ImportResult reports the outcome of an import run
+type ImportResult struct {
+	Created []storage.DailyLogEntry `json:"created"`
+	Skipped int                     `json:"skipped"`
+}
+
+// Import fetches entries from the importer and creates those not already present.
+// Entries are matched on their source and external ID so re-running an import is safe.
+// Entries the store turns down as duplicates are counted as skipped too.
+func Import(store storage.DailyLogStorage, imp Importer, start, end time.Time) (*ImportResult, error) {
+	reqs, err := imp.Fetch(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ImportResult{Created: []storage.DailyLogEntry{}}
+	seen := make(map[string]map[string]bool)
+
+	for _, req := range reqs {
+		dateKey := req.Date.Format("2006-01-02")
+		if _, ok := seen[dateKey]; !ok {
+			dayLog, err := store.GetDay(req.Date)
+			if err != nil {
+				return result, err
+			}
+			seen[dateKey] = existingExternalIDs(dayLog, imp.Name())
+		}
+
+		externalID := req.Metadata[MetadataExternalID]
+		if externalID != "" && seen[dateKey][externalID] {
+			result.Skipped++
+			continue
+		}
+
+		entry, err := store.CreateEntry(req)
+		var duplicate storage.DuplicateError
+		if errors.As(err, &duplicate) {
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Created = append(result.Created, *entry)
+		if externalID != "" {
+			seen[dateKey][externalID] = true
+		}
+	}
+
+	return result, nil
+}
+
+// existingExternalIDs collects external IDs already imported from a source on a day
+func existingExternalIDs(dayLog *storage.DayLog, source string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, entry := range dayLog.Entries {
+		if entry.Metadata[MetadataSource] == source && entry.Metadata[MetadataExternalID] != "" {
+			ids[entry.Metadata[MetadataExternalID]] = true
+		}
+	}
+	return ids
+}
@@ -0,0 +1,247 @@
+package importers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// DefaultJiraJQL selects the issues worked on: assigned to or worklogged by
+// the token's owner
+const DefaultJiraJQL = "assignee = currentUser() OR worklogAuthor = currentUser()"
+
+// jiraTimeLayout is how Jira writes times, e.g. 2025-09-29T10:15:00.000+0200
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// jiraPageSize is how many issues are fetched per search request
+const jiraPageSize = 50
+
+// JiraImporter imports the status transitions and worklogs of the token
+// owner on Jira issues as activities
+type JiraImporter struct {
+	client  *http.Client
+	ctx     context.Context
+	baseURL string
+	email   string
+	token   string
+	jql     string
+}
+
+// NewJiraImporter creates a Jira importer for the site at baseURL. With an
+// email, token is a Jira Cloud API token; without, a Data Center or Server
+// personal access token. jql selects the issues looked at, DefaultJiraJQL
+// if empty.
+func NewJiraImporter(baseURL, email, token, jql string) (*JiraImporter, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("Jira URL is required")
+	}
+	if token == "" {
+		return nil, fmt.Errorf("Jira API token is required")
+	}
+	if jql == "" {
+		jql = DefaultJiraJQL
+	}
+
+	return &JiraImporter{
+		client:  http.DefaultClient,
+		ctx:     context.Background(),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		email:   email,
+		token:   token,
+		jql:     jql,
+	}, nil
+}
+
+// Name returns the source identifier for Jira imports
+func (j *JiraImporter) Name() string {
+	return "jira"
+}
+
+// jiraIssue is an issue as the search API returns it, with its changelog
+type jiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+		Worklog struct {
+			Total    int           `json:"total"`
+			Worklogs []jiraWorklog `json:"worklogs"`
+		} `json:"worklog"`
+	} `json:"fields"`
+	Changelog struct {
+		Histories []struct {
+			ID      string   `json:"id"`
+			Author  jiraUser `json:"author"`
+			Created string   `json:"created"`
+			Items   []struct {
+				Field      string `json:"field"`
+				FromString string `json:"fromString"`
+				ToString   string `json:"toString"`
+			} `json:"items"`
+		} `json:"histories"`
+	} `json:"changelog"`
+}
+
+type jiraWorklog struct {
+	ID               string   `json:"id"`
+	Author           jiraUser `json:"author"`
+	Comment          string   `json:"comment"`
+	Started          string   `json:"started"`
+	TimeSpentSeconds int      `json:"timeSpentSeconds"`
+}
+
+// jiraUser is identified by accountId on Cloud and by name on Server
+type jiraUser struct {
+	AccountID string `json:"accountId"`
+	Name      string `json:"name"`
+}
+
+func (u jiraUser) is(other jiraUser) bool {
+	if u.AccountID != "" || other.AccountID != "" {
+		return u.AccountID == other.AccountID
+	}
+	return u.Name == other.Name
+}
+
+// Fetch retrieves the token owner's status transitions and worklogs between
+// start and end on the issues matching the JQL and updated since start
+func (j *JiraImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	var me jiraUser
+	if err := j.get("/rest/api/2/myself", nil, &me); err != nil {
+		return nil, fmt.Errorf("failed to get Jira user: %v", err)
+	}
+
+	jql := fmt.Sprintf("(%s) AND updated >= %q ORDER BY updated ASC", j.jql, start.Format("2006-01-02"))
+	var reqs []storage.CreateLogEntryRequest
+	params := url.Values{}
+	params.Set("jql", jql)
+	params.Set("fields", "summary,project,worklog")
+	params.Set("expand", "changelog")
+	params.Set("maxResults", strconv.Itoa(jiraPageSize))
+	for startAt := 0; ; {
+		// Jira Cloud searches at search/jql, a page token at a time; Data
+		// Center and Server at search, by offset
+		endpoint := "/rest/api/2/search"
+		if j.email != "" {
+			endpoint = "/rest/api/2/search/jql"
+		} else {
+			params.Set("startAt", strconv.Itoa(startAt))
+		}
+
+		var page struct {
+			Total         int         `json:"total"`
+			Issues        []jiraIssue `json:"issues"`
+			NextPageToken string      `json:"nextPageToken"`
+			IsLast        bool        `json:"isLast"`
+		}
+		if err := j.get(endpoint, params, &page); err != nil {
+			return nil, fmt.Errorf("failed to search Jira issues: %v", err)
+		}
+
+		for _, issue := range page.Issues {
+			worklogs := issue.Fields.Worklog.Worklogs
+			// Search results carry only the first worklogs of busy issues
+			if issue.Fields.Worklog.Total > len(worklogs) {
+				var all struct {
+					Worklogs []jiraWorklog `json:"worklogs"`
+				}
+				if err := j.get("/rest/api/2/issue/"+url.PathEscape(issue.Key)+"/worklog", nil, &all); err != nil {
+					return nil, fmt.Errorf("failed to list worklogs of %s: %v", issue.Key, err)
+				}
+				worklogs = all.Worklogs
+			}
+			reqs = append(reqs, j.issueRequests(issue, worklogs, me, start, end)...)
+		}
+
+		startAt += len(page.Issues)
+		if j.email != "" {
+			if page.IsLast || page.NextPageToken == "" {
+				break
+			}
+			params.Set("nextPageToken", page.NextPageToken)
+		} else if len(page.Issues) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	return reqs, nil
+}
+
+// issueRequests turns the user's transitions and worklogs on an issue
+// between start and end into entry requests
+func (j *JiraImporter) issueRequests(issue jiraIssue, worklogs []jiraWorklog, me jiraUser, start, end time.Time) []storage.CreateLogEntryRequest {
+	var reqs []storage.CreateLogEntryRequest
+	newRequest := func(externalID, title string, at time.Time) storage.CreateLogEntryRequest {
+		return storage.CreateLogEntryRequest{
+			Date:  at.Local(),
+			Type:  "activity",
+			Title: title,
+			Tags:  []string{"jira", tagFromName(issue.Fields.Project.Key)},
+			Metadata: map[string]string{
+				MetadataSource:           j.Name(),
+				MetadataExternalID:       externalID,
+				storage.MetadataIssue:    issue.Key,
+				storage.MetadataIssueURL: j.baseURL + "/browse/" + issue.Key,
+			},
+		}
+	}
+
+	for _, history := range issue.Changelog.Histories {
+		at, err := time.Parse(jiraTimeLayout, history.Created)
+		if err != nil || at.Before(start) || !at.Before(end) || !history.Author.is(me) {
+			continue
+		}
+		for _, item := range history.Items {
+			if item.Field != "status" {
+				continue
+			}
+			req := newRequest(issue.Key+"/transition/"+history.ID,
+				fmt.Sprintf("%s moved to %s: %s", issue.Key, item.ToString, issue.Fields.Summary), at)
+			req.Description = fmt.Sprintf("Moved from %s to %s", item.FromString, item.ToString)
+			reqs = append(reqs, req)
+		}
+	}
+
+	for _, worklog := range worklogs {
+		at, err := time.Parse(jiraTimeLayout, worklog.Started)
+		if err != nil || at.Before(start) || !at.Before(end) || !worklog.Author.is(me) {
+			continue
+		}
+		req := newRequest(issue.Key+"/worklog/"+worklog.ID, issue.Key+" "+issue.Fields.Summary, at)
+		req.Description = strings.TrimSpace(worklog.Comment)
+		if minutes := (worklog.TimeSpentSeconds + 30) / 60; minutes > 0 {
+			req.Duration = &minutes
+		}
+		reqs = append(reqs, req)
+	}
+
+	return reqs
+}
+
+func (j *JiraImporter) get(endpoint string, params url.Values, out any) error {
+	target := j.baseURL + endpoint
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(j.ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	if j.email != "" {
+		req.SetBasicAuth(j.email, j.token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+j.token)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	return doJSONRequest(j.client, req, out)
+}
@@ -0,0 +1,376 @@
+package importers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+const (
+	togglAPIBase    = "https://api.track.toggl.com/api/v9"
+	clockifyAPIBase = "https://api.clockify.me/api/v1"
+)
+
+// timeEntry is the common shape of a time tracking entry before conversion
+type timeEntry struct {
+	ID          string
+	Description string
+	Project     string
+	Tags        []string
+	Start       time.Time
+	Stop        time.Time
+}
+
+// RunningTimer is a time tracking timer that hasn't been stopped yet
+type RunningTimer struct {
+	Description string    `json:"description"`
+	Project     string    `json:"project,omitempty"`
+	Start       time.Time `json:"start"`
+}
+
+// TogglImporter imports Toggl Track time entries as activities
+type TogglImporter struct {
+	client  *http.Client
+	ctx     context.Context
+	token   string
+	baseURL string
+}
+
+// NewTogglImporter creates a new Toggl Track importer
+func NewTogglImporter(token string) (*TogglImporter, error) {
+	if token == "" {
+		return nil, fmt.Errorf("Toggl API token is required")
+	}
+
+	return &TogglImporter{
+		client:  http.DefaultClient,
+		ctx:     context.Background(),
+		token:   token,
+		baseURL: togglAPIBase,
+	}, nil
+}
+
+// Name returns the source identifier for Toggl imports
+func (t *TogglImporter) Name() string {
+	return "toggl"
+}
+
+// Fetch retrieves completed time entries between start and end
+func (t *TogglImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	var projects []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := t.get("/me/projects", nil, &projects); err != nil {
+		return nil, fmt.Errorf("failed to list Toggl projects: %v", err)
+	}
+	projectNames := make(map[int64]string, len(projects))
+	for _, project := range projects {
+		projectNames[project.ID] = project.Name
+	}
+
+	params := url.Values{}
+	params.Set("start_date", start.Format(time.RFC3339))
+	params.Set("end_date", end.Format(time.RFC3339))
+
+	var entries []struct {
+		ID          int64    `json:"id"`
+		Description string   `json:"description"`
+		ProjectID   *int64   `json:"project_id"`
+		Tags        []string `json:"tags"`
+		Start       string   `json:"start"`
+		Stop        *string  `json:"stop"`
+	}
+	if err := t.get("/me/time_entries", params, &entries); err != nil {
+		return nil, fmt.Errorf("failed to list Toggl time entries: %v", err)
+	}
+
+	var reqs []storage.CreateLogEntryRequest
+	for _, e := range entries {
+		// Running timers have no stop time yet; they are picked up on a later import
+		if e.Stop == nil {
+			continue
+		}
+		startTime, err1 := time.Parse(time.RFC3339, e.Start)
+		stopTime, err2 := time.Parse(time.RFC3339, *e.Stop)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		entry := timeEntry{
+			ID:          strconv.FormatInt(e.ID, 10),
+			Description: e.Description,
+			Tags:        e.Tags,
+			Start:       startTime,
+			Stop:        stopTime,
+		}
+		if e.ProjectID != nil {
+			entry.Project = projectNames[*e.ProjectID]
+		}
+		reqs = append(reqs, timeEntryToRequest(entry, t.Name()))
+	}
+
+	return reqs, nil
+}
+
+// Running returns the timer running now, or nil if none is
+func (t *TogglImporter) Running() (*RunningTimer, error) {
+	var current *struct {
+		Description string `json:"description"`
+		Start       string `json:"start"`
+	}
+	if err := t.get("/me/time_entries/current", nil, &current); err != nil {
+		return nil, fmt.Errorf("failed to get the running Toggl timer: %v", err)
+	}
+	if current == nil {
+		return nil, nil
+	}
+	start, err := time.Parse(time.RFC3339, current.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Toggl timer start %q", current.Start)
+	}
+	return &RunningTimer{Description: current.Description, Start: start}, nil
+}
+
+func (t *TogglImporter) get(endpoint string, params url.Values, out any) error {
+	target := t.baseURL + endpoint
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(t.ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.token, "api_token")
+
+	return doJSONRequest(t.client, req, out)
+}
+
+// ClockifyImporter imports Clockify time entries as activities
+type ClockifyImporter struct {
+	client      *http.Client
+	ctx         context.Context
+	token       string
+	workspaceID string
+	baseURL     string
+}
+
+// NewClockifyImporter creates a new Clockify importer.
+// If workspaceID is empty, the user's active workspace is used.
+func NewClockifyImporter(token, workspaceID string) (*ClockifyImporter, error) {
+	if token == "" {
+		return nil, fmt.Errorf("Clockify API key is required")
+	}
+
+	return &ClockifyImporter{
+		client:      http.DefaultClient,
+		ctx:         context.Background(),
+		token:       token,
+		workspaceID: workspaceID,
+		baseURL:     clockifyAPIBase,
+	}, nil
+}
+
+// Name returns the source identifier for Clockify imports
+func (c *ClockifyImporter) Name() string {
+	return "clockify"
+}
+
+// Fetch retrieves completed time entries between start and end
+func (c *ClockifyImporter) Fetch(start, end time.Time) ([]storage.CreateLogEntryRequest, error) {
+	userID, workspaceID, err := c.user()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("start", start.UTC().Format("2006-01-02T15:04:05Z"))
+	params.Set("end", end.UTC().Format("2006-01-02T15:04:05Z"))
+	params.Set("hydrated", "true")
+	params.Set("page-size", "1000")
+
+	var entries []struct {
+		ID           string `json:"id"`
+		Description  string `json:"description"`
+		TimeInterval struct {
+			Start string  `json:"start"`
+			End   *string `json:"end"`
+		} `json:"timeInterval"`
+		Project *struct {
+			Name string `json:"name"`
+		} `json:"project"`
+		Tags []struct {
+			Name string `json:"name"`
+		} `json:"tags"`
+	}
+	if err := c.get(clockifyEntriesEndpoint(workspaceID, userID), params, &entries); err != nil {
+		return nil, fmt.Errorf("failed to list Clockify time entries: %v", err)
+	}
+
+	var reqs []storage.CreateLogEntryRequest
+	for _, e := range entries {
+		// Running timers have no end time yet; they are picked up on a later import
+		if e.TimeInterval.End == nil {
+			continue
+		}
+		startTime, err1 := time.Parse(time.RFC3339, e.TimeInterval.Start)
+		stopTime, err2 := time.Parse(time.RFC3339, *e.TimeInterval.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		entry := timeEntry{
+			ID:          e.ID,
+			Description: e.Description,
+			Start:       startTime,
+			Stop:        stopTime,
+		}
+		if e.Project != nil {
+			entry.Project = e.Project.Name
+		}
+		for _, tag := range e.Tags {
+			entry.Tags = append(entry.Tags, tag.Name)
+		}
+		reqs = append(reqs, timeEntryToRequest(entry, c.Name()))
+	}
+
+	return reqs, nil
+}
+
+// Running returns the timer running now, or nil if none is
+func (c *ClockifyImporter) Running() (*RunningTimer, error) {
+	userID, workspaceID, err := c.user()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("in-progress", "true")
+	params.Set("hydrated", "true")
+	var entries []struct {
+		Description  string `json:"description"`
+		TimeInterval struct {
+			Start string `json:"start"`
+		} `json:"timeInterval"`
+		Project *struct {
+			Name string `json:"name"`
+		} `json:"project"`
+	}
+	if err := c.get(clockifyEntriesEndpoint(workspaceID, userID), params, &entries); err != nil {
+		return nil, fmt.Errorf("failed to get the running Clockify timer: %v", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	start, err := time.Parse(time.RFC3339, entries[0].TimeInterval.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Clockify timer start %q", entries[0].TimeInterval.Start)
+	}
+	timer := &RunningTimer{Description: entries[0].Description, Start: start}
+	if entries[0].Project != nil {
+		timer.Project = entries[0].Project.Name
+	}
+	return timer, nil
+}
+
+// user returns the user's ID and the workspace to read, the configured one
+// or else the user's active workspace
+func (c *ClockifyImporter) user() (string, string, error) {
+	var user struct {
+		ID              string `json:"id"`
+		ActiveWorkspace string `json:"activeWorkspace"`
+	}
+	if err := c.get("/user", nil, &user); err != nil {
+		return "", "", fmt.Errorf("failed to get Clockify user: %v", err)
+	}
+	if c.workspaceID != "" {
+		return user.ID, c.workspaceID, nil
+	}
+	return user.ID, user.ActiveWorkspace, nil
+}
+
+// clockifyEntriesEndpoint lists a user's time entries in a workspace
+func clockifyEntriesEndpoint(workspaceID, userID string) string {
+	return fmt.Sprintf("/workspaces/%s/user/%s/time-entries", url.PathEscape(workspaceID), url.PathEscape(userID))
+}
+
+func (c *ClockifyImporter) get(endpoint string, params url.Values, out any) error {
+	target := c.baseURL + endpoint
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", c.token)
+
+	return doJSONRequest(c.client, req, out)
+}
+
+// Helper functions
+
+func doJSONRequest(client *http.Client, req *http.Request, out any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func timeEntryToRequest(entry timeEntry, source string) storage.CreateLogEntryRequest {
+	title := entry.Description
+	if title == "" {
+		title = entry.Project
+	}
+	if title == "" {
+		title = "(no description)"
+	}
+
+	tags := []string{}
+	metadata := map[string]string{
+		MetadataSource:     source,
+		MetadataExternalID: entry.ID,
+	}
+	if entry.Project != "" {
+		tags = append(tags, tagFromName(entry.Project))
+		metadata["project"] = entry.Project
+	}
+	for _, tag := range entry.Tags {
+		tags = append(tags, tagFromName(tag))
+	}
+
+	req := storage.CreateLogEntryRequest{
+		Date:     entry.Start.Local(),
+		Type:     "activity",
+		Title:    title,
+		Tags:     tags,
+		Metadata: metadata,
+	}
+
+	if minutes := int(entry.Stop.Sub(entry.Start).Round(time.Minute).Minutes()); minutes > 0 {
+		req.Duration = &minutes
+	}
+
+	return req
+}
+
+// tagFromName turns a project or tag name into a lowercase, dash-separated tag
+func tagFromName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
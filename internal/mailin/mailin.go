@@ -0,0 +1,225 @@
+// Package mailin turns emails into entries, for capture by sending an email
+// to an address a mail service forwards to dailyctl serve.
+package mailin
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Source is the source recorded in the metadata of entries from emails
+const Source = "email"
+
+// MetadataFrom holds the address an entry was emailed from
+const MetadataFrom = "email_from"
+
+// maxPartSize bounds each decoded part of a message
+const maxPartSize = 25 << 20
+
+// Message is an email reduced to what an entry keeps
+type Message struct {
+	ID          string
+	From        string // Address only
+	FromName    string // Display name, if any
+	Subject     string
+	Date        time.Time
+	Body        string // Plain text, without the signature
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to an email
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+var (
+	htmlBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTags   = regexp.MustCompile(`(?s)<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// Parse reads a raw RFC 5322 message. The body is its first plain text
+// part, or its HTML part as text; files are kept as attachments.
+func Parse(raw []byte) (*Message, error) {
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	var decoder mime.WordDecoder
+	msg := &Message{ID: strings.Trim(m.Header.Get("Message-Id"), "<> ")}
+	if msg.Subject, err = decoder.DecodeHeader(m.Header.Get("Subject")); err != nil {
+		msg.Subject = m.Header.Get("Subject")
+	}
+	if from, err := mail.ParseAddress(m.Header.Get("From")); err == nil {
+		msg.From = strings.ToLower(from.Address)
+		msg.FromName = from.Name
+	}
+	if date, err := m.Header.Date(); err == nil {
+		msg.Date = date
+	}
+
+	var plain, htmlBody string
+	err = walkPart(m.Header, m.Body, func(header partHeader, data []byte) {
+		mediaType, params, _ := mime.ParseMediaType(header.Get("Content-Type"))
+		disposition, dispParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+		name := dispParams["filename"]
+		if name == "" {
+			name = params["name"]
+		}
+		switch {
+		case disposition == "attachment" || (name != "" && !strings.HasPrefix(mediaType, "text/")):
+			if name == "" {
+				name = "attachment"
+			}
+			if decoded, err := decoder.DecodeHeader(name); err == nil {
+				name = decoded
+			}
+			msg.Attachments = append(msg.Attachments, Attachment{Name: name, ContentType: mediaType, Data: data})
+		case (mediaType == "text/plain" || mediaType == "") && plain == "":
+			plain = string(data)
+		case mediaType == "text/html" && htmlBody == "":
+			htmlBody = string(data)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	body := plain
+	if strings.TrimSpace(body) == "" && htmlBody != "" {
+		body = htmlToText(htmlBody)
+	}
+	msg.Body = trimSignature(strings.ReplaceAll(body, "\r\n", "\n"))
+	return msg, nil
+}
+
+// partHeader is the header of a message or of one of its parts
+type partHeader interface {
+	Get(key string) string
+}
+
+// walkPart calls visit with each leaf part of a message, decoded
+func walkPart(header partHeader, body io.Reader, visit func(partHeader, []byte)) error {
+	mediaType, params, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := walkPart(part.Header, part, visit); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch strings.ToLower(header.Get("Content-Transfer-Encoding")) {
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, newlineStripper{body})
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxPartSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxPartSize {
+		return fmt.Errorf("a part is larger than %d MB", maxPartSize>>20)
+	}
+	visit(header, data)
+	return nil
+}
+
+// newlineStripper drops the line breaks base64 bodies are wrapped with
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (s newlineStripper) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// htmlToText keeps the text of an HTML body, a line per paragraph or break
+func htmlToText(body string) string {
+	body = htmlBreaks.ReplaceAllString(body, "\n")
+	body = html.UnescapeString(htmlTags.ReplaceAllString(body, ""))
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+}
+
+// trimSignature drops the signature after a "-- " line, and surrounding space
+func trimSignature(body string) string {
+	if i := strings.Index(body, "\n-- \n"); i >= 0 {
+		body = body[:i]
+	}
+	return strings.TrimSpace(body)
+}
+
+// Request turns msg into a note: the subject is the title and the body the
+// description. Without a subject, the body's first line is the title.
+func (msg *Message) Request() (storage.CreateLogEntryRequest, error) {
+	title, description := strings.TrimSpace(msg.Subject), msg.Body
+	if title == "" {
+		title, description, _ = strings.Cut(description, "\n")
+		title = strings.TrimSpace(title)
+	}
+	if title == "" && len(msg.Attachments) > 0 {
+		title = msg.Attachments[0].Name
+	}
+	if title == "" {
+		return storage.CreateLogEntryRequest{}, storage.ValidationError{Field: "title", Message: "the email needs a subject or some text"}
+	}
+
+	req := storage.CreateLogEntryRequest{
+		Date:        msg.Date,
+		Type:        "note",
+		Title:       title,
+		Description: strings.TrimSpace(description),
+		Metadata: map[string]string{
+			storage.MetadataSource: Source,
+			MetadataFrom:           msg.From,
+		},
+	}
+	if msg.ID != "" {
+		req.Metadata[storage.MetadataExternalID] = msg.ID
+	}
+	// Whoever sent the email wrote the entry, not the server's owner
+	req.Author = msg.FromName
+	if req.Author == "" {
+		req.Author = msg.From
+	}
+	if req.Date.IsZero() || req.Date.After(time.Now()) {
+		req.Date = time.Now()
+	}
+	return req, nil
+}
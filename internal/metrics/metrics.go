@@ -0,0 +1,318 @@
+// Package metrics collects counters, gauges, and histograms and serves them
+// in the Prometheus text format, for scraping from the MCP server and the
+// dashboard.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are histogram buckets in seconds, for request latencies
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Default is the registry the rest of the module records to
+var Default = NewRegistry()
+
+// Registry holds metrics by name
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// metric is a family of series sharing a name
+type metric interface {
+	write(w io.Writer) error
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: " + name + " registered twice")
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// Write writes every metric in the Prometheus text format
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+	for _, m := range metrics {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves the registry's metrics, e.g. at /metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.Write(w)
+	})
+}
+
+// family holds the series of one metric, by label values
+type family[S any] struct {
+	name, help, kind string
+	labels           []string
+
+	mu     sync.Mutex
+	series map[string]*S
+	values map[string][]string
+	create func() *S
+}
+
+func newFamily[S any](name, help, kind string, labels []string, create func() *S) *family[S] {
+	return &family[S]{
+		name: name, help: help, kind: kind, labels: labels,
+		series: make(map[string]*S),
+		values: make(map[string][]string),
+		create: create,
+	}
+}
+
+// with returns the series for the label values, creating it the first time
+func (f *family[S]) with(values []string) *S {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = f.create()
+		f.series[key] = s
+		f.values[key] = append([]string(nil), values...)
+	}
+	return s
+}
+
+// each calls fn for every series in a stable order
+func (f *family[S]) each(fn func(values []string, s *S) error) error {
+	f.mu.Lock()
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	f.mu.Unlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		f.mu.Lock()
+		s, values := f.series[key], f.values[key]
+		f.mu.Unlock()
+		if err := fn(values, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *family[S]) header(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.kind)
+	return err
+}
+
+// value is a float64 updated atomically under a lock
+type value struct {
+	mu sync.Mutex
+	v  float64
+}
+
+func (v *value) add(delta float64) {
+	v.mu.Lock()
+	v.v += delta
+	v.mu.Unlock()
+}
+
+func (v *value) set(x float64) {
+	v.mu.Lock()
+	v.v = x
+	v.mu.Unlock()
+}
+
+func (v *value) get() float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.v
+}
+
+// CounterVec is a counter with labels
+type CounterVec struct {
+	f *family[value]
+}
+
+// NewCounter registers a counter with the given label names
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{f: newFamily(name, help, "counter", labels, func() *value { return &value{} })}
+	r.register(name, c)
+	return c
+}
+
+// Inc adds one to the series with the label values
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.f.with(labelValues).add(1)
+}
+
+// Add adds delta, which must not be negative, to the series with the label values
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic("metrics: counters cannot decrease")
+	}
+	c.f.with(labelValues).add(delta)
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	if err := c.f.header(w); err != nil {
+		return err
+	}
+	return c.f.each(func(values []string, v *value) error {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", c.f.name, formatLabels(c.f.labels, values, "", ""), formatFloat(v.get()))
+		return err
+	})
+}
+
+// GaugeVec is a gauge with labels
+type GaugeVec struct {
+	f *family[value]
+}
+
+// NewGauge registers a gauge with the given label names
+func (r *Registry) NewGauge(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{f: newFamily(name, help, "gauge", labels, func() *value { return &value{} })}
+	r.register(name, g)
+	return g
+}
+
+// Set sets the series with the label values
+func (g *GaugeVec) Set(x float64, labelValues ...string) {
+	g.f.with(labelValues).set(x)
+}
+
+func (g *GaugeVec) write(w io.Writer) error {
+	if err := g.f.header(w); err != nil {
+		return err
+	}
+	return g.f.each(func(values []string, v *value) error {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", g.f.name, formatLabels(g.f.labels, values, "", ""), formatFloat(v.get()))
+		return err
+	})
+}
+
+// histogram counts observations into cumulative buckets
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64 // Per bucket, the last for observations above every bound
+	sum    float64
+	count  uint64
+}
+
+// HistogramVec is a histogram with labels
+type HistogramVec struct {
+	f       *family[histogram]
+	buckets []float64
+}
+
+// NewHistogram registers a histogram with the given upper bucket bounds,
+// in increasing order, and label names
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &HistogramVec{buckets: bounds}
+	h.f = newFamily(name, help, "histogram", labels, func() *histogram {
+		return &histogram{counts: make([]uint64, len(bounds)+1)}
+	})
+	r.register(name, h)
+	return h
+}
+
+// Observe records x in the series with the label values
+func (h *HistogramVec) Observe(x float64, labelValues ...string) {
+	s := h.f.with(labelValues)
+	i := sort.SearchFloat64s(h.buckets, x)
+	s.mu.Lock()
+	s.counts[i]++
+	s.sum += x
+	s.count++
+	s.mu.Unlock()
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	if err := h.f.header(w); err != nil {
+		return err
+	}
+	return h.f.each(func(values []string, s *histogram) error {
+		s.mu.Lock()
+		counts := append([]uint64(nil), s.counts...)
+		sum, count := s.sum, s.count
+		s.mu.Unlock()
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += counts[i]
+			labels := formatLabels(h.f.labels, values, "le", formatFloat(bound))
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.f.name, labels, cumulative); err != nil {
+				return err
+			}
+		}
+		labels := formatLabels(h.f.labels, values, "le", "+Inf")
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.f.name, labels, count); err != nil {
+			return err
+		}
+		labels = formatLabels(h.f.labels, values, "", "")
+		_, err := fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n", h.f.name, labels, formatFloat(sum), h.f.name, labels, count)
+		return err
+	})
+}
+
+// formatLabels formats label pairs as {name="value",...}, with an extra
+// pair when extraName is set, or "" without any
+func formatLabels(names, values []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, name := range names {
+		pairs = append(pairs, name+`="`+escapeLabel(values[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+escapeLabel(extraValue)+`"`)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
+
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+func formatFloat(x float64) string {
+	switch {
+	case math.IsInf(x, 1):
+		return "+Inf"
+	case math.IsInf(x, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(x, 'g', -1, 64)
+}
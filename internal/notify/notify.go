@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Notifier defines the interface for delivering reminders and alerts
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// DesktopNotifier shows a native desktop notification
+type DesktopNotifier struct{}
+
+// Notify shows a notification using osascript on macOS or notify-send on Linux
+func (d DesktopNotifier) Notify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %q with title %q", message, title)
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		cmd = exec.Command("notify-send", title, message)
+	default:
+		return fmt.Errorf("desktop notifications are not supported on %s", runtime.GOOS)
+	}
+	return cmd.Run()
+}
+
+// WebhookNotifier posts a JSON payload to a webhook URL.
+// The payload carries both "text" (Slack, Mattermost) and "content" (Discord) fields.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a new webhook notifier
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify posts the notification to the webhook
+func (w *WebhookNotifier) Notify(title, message string) error {
+	text := title + ": " + message
+	payload, err := json.Marshal(map[string]string{
+		"title":   title,
+		"message": message,
+		"text":    text,
+		"content": text,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Multi delivers a notification to several notifiers, collecting any errors
+type Multi []Notifier
+
+// Notify sends the notification to every notifier
+func (m Multi) Notify(title, message string) error {
+	var errs []string
+	for _, n := range m {
+		if err := n.Notify(title, message); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("notification failed: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
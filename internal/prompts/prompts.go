@@ -0,0 +1,131 @@
+// Package prompts provides the prompt templates used by the AI features:
+// summaries, insights, and the dailylog_ai_assist actions. Defaults are
+// embedded in the binary; a file of the same name in the override directory,
+// e.g. ~/.dailyctl/prompts/weekly_retro.tmpl, replaces one without
+// recompiling.
+package prompts
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+// Extension is the file extension of prompt templates
+const Extension = ".tmpl"
+
+// Data holds the variables available to prompt templates
+type Data struct {
+	// Period describes the time covered, e.g. "2025-09-22 to 2025-09-28"
+	Period string
+	// Entries are the log entries the prompt is about
+	Entries []storage.DailyLogEntry
+	// Stats holds figures computed for the period, where the feature has them
+	Stats map[string]any
+	// StatusAverage is the average status of the rated entries
+	StatusAverage float64
+	// Tasks are the open tasks, for planning prompts
+	Tasks []storage.DailyLogEntry
+	// Facts is a plain-text digest of what was computed from the log
+	Facts string
+	// Prompt holds extra instructions supplied with the request
+	Prompt string
+}
+
+// funcs are the functions available to templates besides the builtins
+var funcs = template.FuncMap{
+	"join": strings.Join,
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+}
+
+// Library renders prompt templates, preferring overrides in its directory
+type Library struct {
+	dir string
+}
+
+// NewLibrary creates a library with overrides read from dir; an empty dir
+// uses only the embedded defaults
+func NewLibrary(dir string) *Library {
+	return &Library{dir: dir}
+}
+
+// DefaultDir returns the default override directory, ~/.dailyctl/prompts
+func DefaultDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".dailyctl", "prompts")
+}
+
+// Dir returns the override directory
+func (l *Library) Dir() string {
+	return l.dir
+}
+
+// Names lists the available prompt templates
+func Names() []string {
+	files, _ := fs.Glob(defaults, "templates/*"+Extension)
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, strings.TrimSuffix(filepath.Base(file), Extension))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Source returns the template text for name and whether it comes from an override
+func (l *Library) Source(name string) (string, bool, error) {
+	builtin, err := defaults.ReadFile("templates/" + name + Extension)
+	if err != nil {
+		return "", false, storage.NotFoundError{Resource: "prompt template", ID: name}
+	}
+
+	if l.dir != "" {
+		override, err := os.ReadFile(filepath.Join(l.dir, name+Extension))
+		if err == nil {
+			return string(override), true, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", false, fmt.Errorf("failed to read prompt override %s: %v", name, err)
+		}
+	}
+	return string(builtin), false, nil
+}
+
+// Render executes the named template with data. Overrides are read on every
+// call, so edits take effect without a restart.
+func (l *Library) Render(name string, data Data) (string, error) {
+	source, override, err := l.Source(name)
+	if err != nil {
+		return "", err
+	}
+
+	field := "prompt template " + name
+	if override {
+		field = filepath.Join(l.dir, name+Extension)
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=zero").Parse(source)
+	if err != nil {
+		return "", storage.ValidationError{Field: field, Message: err.Error()}
+	}
+
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", storage.ValidationError{Field: field, Message: err.Error()}
+	}
+	return strings.TrimSpace(rendered.String()), nil
+}
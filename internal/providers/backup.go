@@ -0,0 +1,479 @@
+package providers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// snapshotTimeFormat names snapshot files so they sort chronologically
+const snapshotTimeFormat = "20060102T150405Z"
+
+// SnapshotStore holds snapshot archives by name
+type SnapshotStore interface {
+	Put(name string, data []byte) error
+	Get(name string) ([]byte, error)
+	List() ([]string, error)
+	Delete(name string) error
+}
+
+// Snapshot describes one backup archive
+type Snapshot struct {
+	Name      string      `json:"name" yaml:"name"`
+	CreatedAt time.Time   `json:"created_at" yaml:"created_at"`
+	Days      []time.Time `json:"days" yaml:"days"`
+}
+
+// ArchiveBackupStorage implements BackupStorage as timestamped tar.gz snapshots
+// of day files. Days passed to BackupDay are staged and written as one snapshot
+// by Commit; restores use the newest snapshot that contains the day.
+type ArchiveBackupStorage struct {
+	store SnapshotStore
+
+	mu     sync.Mutex
+	staged map[string][]byte
+}
+
+// NewArchiveBackupStorage creates backup storage on top of a snapshot store
+func NewArchiveBackupStorage(store SnapshotStore) *ArchiveBackupStorage {
+	return &ArchiveBackupStorage{
+		store:  store,
+		staged: make(map[string][]byte),
+	}
+}
+
+// BackupDay stages a day's serialized log for the next snapshot
+func (a *ArchiveBackupStorage) BackupDay(date time.Time, data []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.staged[snapshotDayPath(date)] = append([]byte(nil), data...)
+	return nil
+}
+
+// Commit writes the staged days as a new snapshot. It returns nil if nothing is staged.
+func (a *ArchiveBackupStorage) Commit() (*Snapshot, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.staged) == 0 {
+		return nil, nil
+	}
+
+	createdAt := time.Now().UTC().Truncate(time.Second)
+	name := "dailylog-" + createdAt.Format(snapshotTimeFormat) + ".tar.gz"
+
+	archive, err := writeSnapshotArchive(a.staged, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.store.Put(name, archive); err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Name: name, CreatedAt: createdAt}
+	for dayPath := range a.staged {
+		if date, ok := parseSnapshotDayPath(dayPath); ok {
+			snapshot.Days = append(snapshot.Days, date)
+		}
+	}
+	sortDates(snapshot.Days)
+
+	a.staged = make(map[string][]byte)
+	return snapshot, nil
+}
+
+// Snapshots lists the stored snapshots, oldest first
+func (a *ArchiveBackupStorage) Snapshots() ([]Snapshot, error) {
+	names, err := a.snapshotNames()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(names))
+	for _, name := range names {
+		files, err := a.readSnapshot(name)
+		if err != nil {
+			return nil, err
+		}
+		snapshot := Snapshot{Name: name, CreatedAt: snapshotTime(name)}
+		for dayPath := range files {
+			if date, ok := parseSnapshotDayPath(dayPath); ok {
+				snapshot.Days = append(snapshot.Days, date)
+			}
+		}
+		sortDates(snapshot.Days)
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// RestoreDay returns a day's log from the newest snapshot containing it
+func (a *ArchiveBackupStorage) RestoreDay(date time.Time) ([]byte, error) {
+	names, err := a.snapshotNames()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(names) - 1; i >= 0; i-- {
+		data, err := a.RestoreDayFrom(names[i], date)
+		if err == nil {
+			return data, nil
+		}
+		if _, ok := err.(storage.NotFoundError); !ok {
+			return nil, err
+		}
+	}
+
+	return nil, storage.NotFoundError{
+		Resource: "day backup",
+		ID:       date.Format("2006-01-02"),
+	}
+}
+
+// RestoreDayFrom returns a day's log from a specific snapshot
+func (a *ArchiveBackupStorage) RestoreDayFrom(name string, date time.Time) ([]byte, error) {
+	files, err := a.readSnapshot(name)
+	if err != nil {
+		return nil, err
+	}
+	data, ok := files[snapshotDayPath(date)]
+	if !ok {
+		return nil, storage.NotFoundError{
+			Resource: "day backup",
+			ID:       date.Format("2006-01-02") + " in " + name,
+		}
+	}
+	return data, nil
+}
+
+// ListBackups returns every day present in at least one snapshot
+func (a *ArchiveBackupStorage) ListBackups() ([]time.Time, error) {
+	snapshots, err := a.Snapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[time.Time]bool)
+	var days []time.Time
+	for _, snapshot := range snapshots {
+		for _, day := range snapshot.Days {
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sortDates(days)
+	return days, nil
+}
+
+// DeleteBackup removes a day from every snapshot, deleting snapshots left empty
+func (a *ArchiveBackupStorage) DeleteBackup(date time.Time) error {
+	names, err := a.snapshotNames()
+	if err != nil {
+		return err
+	}
+
+	dayPath := snapshotDayPath(date)
+	for _, name := range names {
+		files, err := a.readSnapshot(name)
+		if err != nil {
+			return err
+		}
+		if _, ok := files[dayPath]; !ok {
+			continue
+		}
+		delete(files, dayPath)
+
+		if err := a.store.Delete(name); err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		archive, err := writeSnapshotArchive(files, snapshotTime(name))
+		if err != nil {
+			return err
+		}
+		if err := a.store.Put(name, archive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteSnapshot removes a whole snapshot
+func (a *ArchiveBackupStorage) DeleteSnapshot(name string) error {
+	return a.store.Delete(name)
+}
+
+func (a *ArchiveBackupStorage) snapshotNames() ([]string, error) {
+	all, err := a.store.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, name := range all {
+		if strings.HasPrefix(name, "dailylog-") && strings.HasSuffix(name, ".tar.gz") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (a *ArchiveBackupStorage) readSnapshot(name string) (map[string][]byte, error) {
+	data, err := a.store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %v", name, err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot %s: %v", name, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from snapshot %s: %v", header.Name, name, err)
+		}
+		files[header.Name] = content
+	}
+	return files, nil
+}
+
+func writeSnapshotArchive(files map[string][]byte, modTime time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		header := &tar.Header{
+			Name:    p,
+			Mode:    0o600,
+			Size:    int64(len(files[p])),
+			ModTime: modTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("failed to write snapshot: %v", err)
+		}
+		if _, err := tw.Write(files[p]); err != nil {
+			return nil, fmt.Errorf("failed to write snapshot: %v", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshotDayPath is the path of a day file inside a snapshot, matching the repository layout
+func snapshotDayPath(date time.Time) string {
+	return path.Join(date.Format("2006"), date.Format("01"), date.Format("2006-01-02.json"))
+}
+
+func parseSnapshotDayPath(dayPath string) (time.Time, bool) {
+	date, err := time.Parse("2006-01-02.json", path.Base(dayPath))
+	return date, err == nil
+}
+
+func snapshotTime(name string) time.Time {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, "dailylog-"), ".tar.gz")
+	t, _ := time.Parse(snapshotTimeFormat, stamp)
+	return t
+}
+
+func sortDates(dates []time.Time) {
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+}
+
+// LocalSnapshotStore keeps snapshots as files in a local directory
+type LocalSnapshotStore struct {
+	dir string
+}
+
+// NewLocalSnapshotStore creates a snapshot store in dir
+func NewLocalSnapshotStore(dir string) (*LocalSnapshotStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("backup directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %v", err)
+	}
+	return &LocalSnapshotStore{dir: dir}, nil
+}
+
+// Put writes a snapshot file
+func (l *LocalSnapshotStore) Put(name string, data []byte) error {
+	tmpPath := filepath.Join(l.dir, name+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot %s: %v", name, err)
+	}
+	return os.Rename(tmpPath, filepath.Join(l.dir, name))
+}
+
+// Get reads a snapshot file
+func (l *LocalSnapshotStore) Get(name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(l.dir, name))
+	if os.IsNotExist(err) {
+		return nil, storage.NotFoundError{Resource: "snapshot", ID: name}
+	}
+	return data, err
+}
+
+// List returns the names of files in the directory
+func (l *LocalSnapshotStore) List() ([]string, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Delete removes a snapshot file
+func (l *LocalSnapshotStore) Delete(name string) error {
+	err := os.Remove(filepath.Join(l.dir, name))
+	if os.IsNotExist(err) {
+		return storage.NotFoundError{Resource: "snapshot", ID: name}
+	}
+	return err
+}
+
+// GitHubSnapshotStore keeps snapshots as files in a directory of a GitHub repository
+type GitHubSnapshotStore struct {
+	provider *GitHubStorageProvider
+	dir      string
+}
+
+// NewGitHubSnapshotStore stores snapshots under dir in the provider's repository
+func NewGitHubSnapshotStore(provider *GitHubStorageProvider, dir string) *GitHubSnapshotStore {
+	if dir == "" {
+		dir = "backups"
+	}
+	return &GitHubSnapshotStore{provider: provider, dir: dir}
+}
+
+// Put commits a snapshot file
+func (g *GitHubSnapshotStore) Put(name string, data []byte) error {
+	p := g.provider
+	message := fmt.Sprintf("Add backup snapshot %s", name)
+	_, _, err := p.client.Repositories.CreateFile(
+		p.ctx, p.owner, p.repo, path.Join(g.dir, name),
+		&github.RepositoryContentFileOptions{
+			Message: &message,
+			Content: data,
+		},
+	)
+	if err != nil {
+		return storage.StorageError{
+			Operation: "BackupDay",
+			Message:   fmt.Sprintf("failed to upload snapshot %s", name),
+			Cause:     err,
+		}
+	}
+	return nil
+}
+
+// Get downloads a snapshot file
+func (g *GitHubSnapshotStore) Get(name string) ([]byte, error) {
+	p := g.provider
+	reader, _, err := p.client.Repositories.DownloadContents(
+		p.ctx, p.owner, p.repo, path.Join(g.dir, name), nil,
+	)
+	if err != nil {
+		return nil, storage.StorageError{
+			Operation: "RestoreDay",
+			Message:   fmt.Sprintf("failed to download snapshot %s", name),
+			Cause:     err,
+		}
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// List returns the names of files in the snapshot directory
+func (g *GitHubSnapshotStore) List() ([]string, error) {
+	contents, err := g.provider.listDir(g.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, content := range contents {
+		if content.GetType() == "file" {
+			names = append(names, content.GetName())
+		}
+	}
+	return names, nil
+}
+
+// Delete removes a snapshot file
+func (g *GitHubSnapshotStore) Delete(name string) error {
+	p := g.provider
+	filePath := path.Join(g.dir, name)
+
+	fileContent, _, _, err := p.client.Repositories.GetContents(p.ctx, p.owner, p.repo, filePath, nil)
+	if err != nil {
+		return storage.NotFoundError{Resource: "snapshot", ID: name}
+	}
+
+	message := fmt.Sprintf("Delete backup snapshot %s", name)
+	_, _, err = p.client.Repositories.DeleteFile(
+		p.ctx, p.owner, p.repo, filePath,
+		&github.RepositoryContentFileOptions{
+			Message: &message,
+			SHA:     fileContent.SHA,
+		},
+	)
+	if err != nil {
+		return storage.StorageError{
+			Operation: "DeleteBackup",
+			Message:   fmt.Sprintf("failed to delete snapshot %s", name),
+			Cause:     err,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"unicode/utf8"
+)
+
+// Cassette modes
+const (
+	CassetteRecord = "record" // Make requests and save them with their responses
+	CassetteReplay = "replay" // Answer requests from the file without the network
+)
+
+// Interaction is a recorded GitHub API request and its response. Request
+// headers aren't kept, so the token never ends up in a cassette.
+type Interaction struct {
+	Method   string              `json:"method"`
+	URL      string              `json:"url"`
+	Body     string              `json:"body,omitempty"`
+	Status   int                 `json:"status"`
+	Header   map[string][]string `json:"header,omitempty"`
+	Response string              `json:"response"`
+
+	// Base64 is set when the bodies are base64-encoded as they aren't UTF-8
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Cassette records the GitHub API interactions of every client to a file,
+// or replays them from one, so tests and debugging sessions run
+// deterministically without the live API. A request is replayed with the
+// first unused interaction of the same method and URL, so repeated reads of
+// a file get its recorded responses in order.
+type Cassette struct {
+	path string
+	mode string
+	base http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// githubCassette, if set, records or replays the requests of new GitHub clients
+var githubCassette *Cassette
+
+// UseCassette makes GitHub clients created from now on record to or replay
+// from the file at path, per mode. Recording starts a new file.
+func UseCassette(path, mode string) error {
+	cassette := &Cassette{path: path, mode: mode, base: http.DefaultTransport}
+	switch mode {
+	case CassetteRecord:
+		cassette.interactions = []Interaction{}
+		if err := cassette.save(); err != nil {
+			return err
+		}
+	case CassetteReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &cassette.interactions); err != nil {
+			return fmt.Errorf("failed to parse cassette %s: %w", path, err)
+		}
+		cassette.used = make([]bool, len(cassette.interactions))
+	default:
+		return fmt.Errorf("unknown cassette mode %q (use %s or %s)", mode, CassetteRecord, CassetteReplay)
+	}
+	githubCassette = cassette
+	return nil
+}
+
+// Replaying reports whether GitHub API requests are replayed from a
+// cassette, so no token is needed
+func Replaying() bool {
+	return githubCassette != nil && githubCassette.mode == CassetteReplay
+}
+
+// githubTransport returns the transport GitHub clients send requests through
+func githubTransport() http.RoundTripper {
+	if githubCassette != nil {
+		return githubCassette
+	}
+	return http.DefaultTransport
+}
+
+// RoundTrip implements http.RoundTripper
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if c.mode == CassetteReplay {
+		return c.replay(req)
+	}
+
+	resp, err := c.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{Method: req.Method, URL: req.URL.String(), Status: resp.StatusCode, Header: resp.Header.Clone()}
+	delete(interaction.Header, "Set-Cookie")
+	if utf8.Valid(body) && utf8.Valid(respBody) {
+		interaction.Body, interaction.Response = string(body), string(respBody)
+	} else {
+		interaction.Base64 = true
+		interaction.Body = base64.StdEncoding.EncodeToString(body)
+		interaction.Response = base64.StdEncoding.EncodeToString(respBody)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interactions = append(c.interactions, interaction)
+	if err := c.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// replay answers req with the first unused interaction matching it
+func (c *Cassette) replay(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	url := req.URL.String()
+	for i, interaction := range c.interactions {
+		if c.used[i] || interaction.Method != req.Method || interaction.URL != url {
+			continue
+		}
+		c.used[i] = true
+
+		body := []byte(interaction.Response)
+		if interaction.Base64 {
+			var err error
+			if body, err = base64.StdEncoding.DecodeString(interaction.Response); err != nil {
+				return nil, fmt.Errorf("invalid response body recorded for %s %s: %w", req.Method, url, err)
+			}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Status, http.StatusText(interaction.Status)),
+			StatusCode:    interaction.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header(interaction.Header).Clone(),
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded response for %s %s in %s", req.Method, url, c.path)
+}
+
+// save writes the interactions recorded so far
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,600 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// dayOperations implements the DailyLogStorage operations that are built on
+// reading and writing whole days, so a provider only needs to implement
+// DayStore and embed it
+type dayOperations struct {
+	days       storage.DayStore
+	visibility storage.VisibilityPolicy
+	locations  storage.LocationAliases
+	tags       storage.TagAliases
+	focus      storage.FocusRules
+	workHours  storage.WorkHoursConfig
+}
+
+// newDayOperations creates the day operations over days, with the
+// visibility policy and aliases from config
+func newDayOperations(days storage.DayStore, config storage.Config) dayOperations {
+	ops := dayOperations{
+		days:       days,
+		visibility: config.Visibility,
+		locations:  config.LocationAliases,
+		tags:       config.TagAliases,
+		focus:      config.Focus,
+		workHours:  config.WorkHours,
+	}
+	if len(ops.focus) == 0 {
+		ops.focus = storage.DefaultFocusRules
+	}
+	return ops
+}
+
+// CreateEntry creates a new log entry for a specific day
+func (o *dayOperations) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	// Get the day log
+	dayLog, err := o.days.GetDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create new entry with ID
+	entry := storage.NewEntry(req)
+
+	// Add entry to day log
+	dayLog.AddEntry(entry)
+
+	// Save the updated day log
+	if err := o.days.SaveDay(dayLog); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// UpdateEntry updates an existing log entry on the day its ID was made for.
+// Entries since moved to another day are edited with a DayEditSession there.
+func (o *dayOperations) UpdateEntry(req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	date, ok := storage.EntryIDTime(req.ID)
+	if !ok {
+		return nil, storage.ValidationError{Field: "id", Message: fmt.Sprintf("can't tell the day of entry %q from its ID", req.ID)}
+	}
+	session, err := storage.BeginDayEdit(o.days, date.Local())
+	if err != nil {
+		return nil, err
+	}
+	entry, err := session.Update(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Commit(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteEntry deletes a log entry from a specific day
+func (o *dayOperations) DeleteEntry(id string, date time.Time) error {
+	// Get the day log
+	dayLog, err := o.days.GetDay(date)
+	if err != nil {
+		return err
+	}
+
+	// Remove the entry
+	if !dayLog.RemoveEntry(id) {
+		return storage.NotFoundError{
+			Resource: "log entry",
+			ID:       id,
+		}
+	}
+
+	// Save the updated day log
+	return o.days.SaveDay(dayLog)
+}
+
+// GetEntry retrieves a specific log entry from a day
+func (o *dayOperations) GetEntry(id string, date time.Time) (*storage.DailyLogEntry, error) {
+	dayLog, err := o.days.GetDay(date)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range dayLog.Entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+
+	return nil, storage.NotFoundError{
+		Resource: "log entry",
+		ID:       id,
+	}
+}
+
+// SearchLogs searches through logs based on criteria
+func (o *dayOperations) SearchLogs(req storage.LogSearchRequest) (*storage.LogSearchResponse, error) {
+	// This is a simplified implementation - in reality, we'd need to iterate through files
+	// or maintain an index for efficient searching
+	response := &storage.LogSearchResponse{
+		Entries:     []storage.DailyLogEntry{},
+		TotalCount:  0,
+		SearchQuery: req,
+	}
+
+	// For now, search within a reasonable date range
+	startDate := time.Now().AddDate(0, -3, 0) // Last 3 months
+	endDate := time.Now()
+
+	if req.DateStart != nil {
+		startDate = *req.DateStart
+	}
+	if req.DateEnd != nil {
+		endDate = *req.DateEnd
+	}
+
+	if err := storage.ValidateAggregations(req.Aggregations); err != nil {
+		return nil, err
+	}
+	order, err := storage.ParseSortOrder(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// Days are visited oldest first, so the default order can stop at the
+	// limit; other orders and aggregations need every match
+	stopAtLimit := req.Limit > 0 && len(req.Aggregations) == 0 && order == storage.DefaultSortOrder
+	var matched []storage.DailyLogEntry
+
+	// Iterate through date range
+	total, done := rangeDays(startDate, endDate), 0
+	for d := startDate; d.Before(endDate) || d.Equal(endDate); d = d.AddDate(0, 0, 1) {
+		dayLog, err := o.days.GetDay(d)
+		done++
+		if req.Progress != nil {
+			req.Progress(done, total)
+		}
+		if err != nil {
+			continue // Skip days that don't exist or have errors
+		}
+
+		// Filter entries based on search criteria
+		var dayMatches []storage.DailyLogEntry
+		for _, entry := range dayLog.Entries {
+			if o.matchesSearchCriteria(entry, req) {
+				dayMatches = append(dayMatches, entry)
+			}
+		}
+		storage.SortEntries(dayMatches, storage.DefaultSortOrder)
+		matched = append(matched, dayMatches...)
+
+		if stopAtLimit && len(matched) >= req.Limit {
+			break
+		}
+	}
+
+	// Respect limit
+	storage.SortEntries(matched, order)
+	limited := matched
+	if req.Limit > 0 && len(limited) > req.Limit {
+		limited = limited[:req.Limit]
+	}
+	response.Entries = append(response.Entries, limited...)
+	response.TotalCount = len(response.Entries)
+
+	if len(req.Aggregations) > 0 {
+		totals := storage.SummarizeEntries(matched)
+		response.Totals = &totals
+		matched = o.locations.NormalizeEntries(matched)
+		matched = o.tags.NormalizeEntries(matched)
+		for _, groupBy := range req.Aggregations {
+			response.Aggregations = append(response.Aggregations, storage.AggregateEntries(matched, groupBy))
+		}
+	}
+
+	return response, nil
+}
+
+// GetDateRange retrieves all day logs within a date range
+func (o *dayOperations) GetDateRange(start, end time.Time) ([]storage.DayLog, error) {
+	return o.getDateRange(start, end, nil)
+}
+
+// getDateRange is GetDateRange, telling progress, if set, after each day
+func (o *dayOperations) getDateRange(start, end time.Time, progress storage.ProgressFunc) ([]storage.DayLog, error) {
+	var dayLogs []storage.DayLog
+
+	total, done := rangeDays(start, end), 0
+	for d := start; d.Before(end) || d.Equal(end); d = d.AddDate(0, 0, 1) {
+		dayLog, err := o.days.GetDay(d)
+		done++
+		if progress != nil {
+			progress(done, total)
+		}
+		if err != nil {
+			continue // Skip days that don't exist
+		}
+		if len(dayLog.Entries) > 0 {
+			dayLogs = append(dayLogs, *dayLog)
+		}
+	}
+
+	return dayLogs, nil
+}
+
+// rangeDays counts the days from start to end, inclusive
+func rangeDays(start, end time.Time) int {
+	days := 0
+	for d := start; d.Before(end) || d.Equal(end); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+	return days
+}
+
+// GetWeek retrieves a week's worth of logs
+func (o *dayOperations) GetWeek(date time.Time) (*storage.WeeklyLog, error) {
+	return o.getWeek(date, nil)
+}
+
+func (o *dayOperations) getWeek(date time.Time, progress storage.ProgressFunc) (*storage.WeeklyLog, error) {
+	// Calculate week start (Monday)
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		weekday = 7 // Sunday = 7
+	}
+	weekStart := date.AddDate(0, 0, -(weekday - 1))
+	weekEnd := weekStart.AddDate(0, 0, 6)
+
+	days, err := o.getDateRange(weekStart, weekEnd, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	weeklyLog := &storage.WeeklyLog{
+		WeekStart: weekStart,
+		WeekEnd:   weekEnd,
+		Days:      days,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// Calculate total entries
+	for _, day := range days {
+		weeklyLog.TotalEntries += day.TotalEntries
+	}
+
+	return weeklyLog, nil
+}
+
+// GetMonth retrieves a month's worth of logs
+func (o *dayOperations) GetMonth(year int, month int) (*storage.MonthlyLog, error) {
+	return o.getMonth(year, month, nil)
+}
+
+func (o *dayOperations) getMonth(year int, month int, progress storage.ProgressFunc) (*storage.MonthlyLog, error) {
+	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	days, err := o.getDateRange(monthStart, monthEnd, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	monthlyLog := &storage.MonthlyLog{
+		Month:     fmt.Sprintf("%04d-%02d", year, month),
+		Year:      year,
+		Days:      days,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// Calculate total entries
+	for _, day := range days {
+		monthlyLog.TotalEntries += day.TotalEntries
+	}
+
+	return monthlyLog, nil
+}
+
+// GenerateSummary generates a summary for the given request
+func (o *dayOperations) GenerateSummary(req storage.SummaryRequest) (*storage.SummaryResponse, error) {
+	// Basic implementation - this would integrate with AI in a real implementation
+	var summary string
+	var stats map[string]any
+	var entries []storage.DailyLogEntry
+	var days []storage.DayLog
+
+	switch req.Type {
+	case "day":
+		dayLog, err := o.days.GetDay(req.Date)
+		if err != nil {
+			return nil, err
+		}
+		filtered := o.tags.FilterDay(o.visibility.FilterDay(*dayLog, req.Audience), req.Tags)
+		dayLog = &filtered
+		summary = o.generateDaySummary(dayLog)
+		entries = dayLog.Entries
+		days = []storage.DayLog{*dayLog}
+		stats = map[string]any{
+			"total_entries":  dayLog.TotalEntries,
+			"status_average": dayLog.StatusAverage,
+		}
+
+	case "week":
+		weekLog, err := o.getWeek(req.Date, req.Progress)
+		if err != nil {
+			return nil, err
+		}
+		weekLog.Days, weekLog.TotalEntries = o.filterDays(weekLog.Days, req.Audience, req.Tags)
+		summary = o.generateWeekSummary(weekLog)
+		entries = daysEntries(weekLog.Days)
+		days = weekLog.Days
+		stats = map[string]any{
+			"total_entries": weekLog.TotalEntries,
+			"total_days":    len(weekLog.Days),
+		}
+		// A tag filter summarizes only part of the week, which says little about wellbeing
+		if len(req.Tags) == 0 {
+			check, err := o.checkWellbeing(weekLog, req.Audience)
+			if err != nil {
+				return nil, err
+			}
+			stats["wellbeing"] = check
+			stats["work_hours"] = storage.CheckWorkHours(entries, weekLog.WeekStart, weekLog.WeekStart.AddDate(0, 0, 6),
+				time.Now(), o.workHours, o.focus, o.tags)
+		}
+
+	case "month":
+		monthLog, err := o.getMonth(req.Date.Year(), int(req.Date.Month()), req.Progress)
+		if err != nil {
+			return nil, err
+		}
+		monthLog.Days, monthLog.TotalEntries = o.filterDays(monthLog.Days, req.Audience, req.Tags)
+		summary = o.generateMonthSummary(monthLog)
+		entries = daysEntries(monthLog.Days)
+		days = monthLog.Days
+		stats = map[string]any{
+			"total_entries": monthLog.TotalEntries,
+			"total_days":    len(monthLog.Days),
+		}
+	}
+
+	if metrics := storage.SummarizeMetrics(entries); len(metrics) > 0 && stats != nil {
+		stats["metrics"] = metrics
+		summary += " " + describeMetrics(metrics)
+	}
+	if weather := storage.SummarizeWeather(days); weather != nil && stats != nil {
+		stats["weather"] = weather
+		summary += " " + weather.String()
+	}
+	if focus := o.focus.Score(entries, o.tags); focus != nil && stats != nil {
+		stats["focus"] = focus
+		summary += " " + focus.String()
+	}
+	if check, ok := stats["wellbeing"].(storage.WellbeingCheck); ok && check.Score > 0 {
+		summary += " " + check.String()
+	}
+	if hours, ok := stats["work_hours"].(storage.WorkHoursReport); ok && hours.TotalMinutes > 0 {
+		summary += " " + hours.String()
+	}
+
+	response := &storage.SummaryResponse{
+		Summary:   summary,
+		Type:      req.Type,
+		Period:    req.Date.Format("2006-01-02"),
+		Stats:     stats,
+		CreatedAt: time.Now(),
+		Entries:   entries,
+	}
+	// A week or month leads with its highlights
+	if req.Type == "week" || req.Type == "month" {
+		response.Pinned = storage.PinnedEntries(entries)
+	}
+	return response, nil
+}
+
+// checkWellbeing checks the week's burnout risk against the weeks before it
+func (o *dayOperations) checkWellbeing(weekLog *storage.WeeklyLog, audience string) (storage.WellbeingCheck, error) {
+	start, _ := storage.WellbeingWindow(weekLog.WeekStart)
+	before, err := o.getDateRange(start, weekLog.WeekStart.AddDate(0, 0, -1), nil)
+	if err != nil {
+		return storage.WellbeingCheck{}, err
+	}
+	before, _ = o.filterDays(before, audience, nil)
+	entries := append(daysEntries(before), daysEntries(weekLog.Days)...)
+	return storage.CheckWellbeing(entries, weekLog.WeekStart, o.focus, o.tags), nil
+}
+
+// describeMetrics lists daily averages, e.g. "Daily averages: sleep 7.2h, steps 8400 steps."
+func describeMetrics(metrics []storage.MetricStats) string {
+	parts := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		parts = append(parts, m.Name+" "+m.String())
+	}
+	return "Daily averages: " + strings.Join(parts, ", ") + "."
+}
+
+// daysEntries collects the entries of several days in order
+func daysEntries(days []storage.DayLog) []storage.DailyLogEntry {
+	var entries []storage.DailyLogEntry
+	for _, day := range days {
+		entries = append(entries, day.Entries...)
+	}
+	return entries
+}
+
+// SaveSummary saves a summary to the appropriate location
+func (o *dayOperations) SaveSummary(summary *storage.SummaryResponse, targetType string, date time.Time) error {
+	// Save summary as metadata in the day/week/month file
+	switch targetType {
+	case "day":
+		dayLog, err := o.days.GetDay(date)
+		if err != nil {
+			return err
+		}
+		dayLog.DaySummary = summary.Summary
+		return o.days.SaveDay(dayLog)
+	}
+
+	return nil
+}
+
+// GetStats returns statistics for a date range
+func (o *dayOperations) GetStats(start, end time.Time) (map[string]any, error) {
+	days, err := o.GetDateRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	totalEntries := 0
+	totalDays := len(days)
+	statusSum := 0.0
+	statusCount := 0
+	totalDuration := 0
+	entriesByType := make(map[string]int)
+	entriesByTag := make(map[string]int)
+	durationByType := make(map[string]int)
+	durationByTag := make(map[string]int)
+	var entries []storage.DailyLogEntry
+
+	for _, day := range days {
+		entries = append(entries, day.Entries...)
+		totalEntries += day.TotalEntries
+		if day.StatusAverage > 0 {
+			statusSum += day.StatusAverage
+			statusCount++
+		}
+
+		for _, entry := range day.Entries {
+			duration := 0
+			if entry.Duration != nil {
+				duration = *entry.Duration
+			}
+			totalDuration += duration
+			entriesByType[entry.Type]++
+			durationByType[entry.Type] += duration
+			for _, tag := range entry.Tags {
+				entriesByTag[tag]++
+				durationByTag[tag] += duration
+			}
+		}
+	}
+
+	avgStatus := 0.0
+	if statusCount > 0 {
+		avgStatus = statusSum / float64(statusCount)
+	}
+	entriesPerDay := 0.0
+	if totalDays > 0 {
+		entriesPerDay = float64(totalEntries) / float64(totalDays)
+	}
+
+	stats := map[string]any{
+		"total_entries":          totalEntries,
+		"total_days":             totalDays,
+		"average_status":         avgStatus,
+		"entries_per_day":        entriesPerDay,
+		"total_duration_minutes": totalDuration,
+		"entries_by_type":        entriesByType,
+		"entries_by_tag":         entriesByTag,
+		"duration_by_type":       durationByType,
+		"duration_by_tag":        durationByTag,
+	}
+	if metrics := storage.SummarizeMetrics(entries); len(metrics) > 0 {
+		stats["metrics"] = metrics
+	}
+	if weather := storage.SummarizeWeather(days); weather != nil {
+		stats["weather"] = weather
+	}
+	if focus := o.focus.Score(entries, o.tags); focus != nil {
+		stats["focus"] = focus
+	}
+	return stats, nil
+}
+
+func (o *dayOperations) matchesSearchCriteria(entry storage.DailyLogEntry, req storage.LogSearchRequest) bool {
+	// Type filter
+	if req.Type != "" && entry.Type != req.Type {
+		return false
+	}
+
+	// Status range filter
+	if req.StatusMin != nil && entry.Status < *req.StatusMin {
+		return false
+	}
+	if req.StatusMax != nil && entry.Status > *req.StatusMax {
+		return false
+	}
+
+	if req.Author != "" && !strings.EqualFold(entry.Author, req.Author) {
+		return false
+	}
+	if req.Pinned && !entry.Pinned {
+		return false
+	}
+	if req.Unreviewed && entry.Reviewed() {
+		return false
+	}
+
+	// Location filter, treating aliases of a place as the same place
+	if req.Location != "" && !o.locations.SameLocation(entry.Location, req.Location) {
+		return false
+	}
+
+	// Text search in title and description
+	if req.SearchText != "" {
+		searchText := strings.ToLower(req.SearchText)
+		if !strings.Contains(strings.ToLower(entry.Title), searchText) &&
+			!strings.Contains(strings.ToLower(entry.Description), searchText) {
+			return false
+		}
+	}
+
+	// Tag filter, where a parent tag also matches its children
+	if len(req.Tags) > 0 && !o.tags.HasAnyTag(entry, req.Tags) {
+		return false
+	}
+
+	return true
+}
+
+// filterDays drops entries not visible to the audience or not matching the tag
+// filters, returning the days that still have entries and their total entry count
+func (o *dayOperations) filterDays(days []storage.DayLog, audience string, tags []string) ([]storage.DayLog, int) {
+	var filtered []storage.DayLog
+	total := 0
+	for _, day := range days {
+		visible := o.tags.FilterDay(o.visibility.FilterDay(day, audience), tags)
+		if len(visible.Entries) > 0 {
+			filtered = append(filtered, visible)
+			total += visible.TotalEntries
+		}
+	}
+	return filtered, total
+}
+
+func (o *dayOperations) generateDaySummary(dayLog *storage.DayLog) string {
+	if len(dayLog.Entries) == 0 {
+		return "No activities recorded for this day."
+	}
+
+	return fmt.Sprintf("Day had %d activities with an average status of %.1f",
+		dayLog.TotalEntries, dayLog.StatusAverage)
+}
+
+func (o *dayOperations) generateWeekSummary(weekLog *storage.WeeklyLog) string {
+	return fmt.Sprintf("Week had %d total activities across %d days",
+		weekLog.TotalEntries, len(weekLog.Days))
+}
+
+func (o *dayOperations) generateMonthSummary(monthLog *storage.MonthlyLog) string {
+	return fmt.Sprintf("Month had %d total activities across %d days",
+		monthLog.TotalEntries, len(monthLog.Days))
+}
@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// DemoDays is how many days of sample data SeedDemo writes, ending today
+const DemoDays = 14
+
+// demoEntry is a sample entry, logged at a time of day
+type demoEntry struct {
+	hour, minute int
+	req          storage.CreateLogEntryRequest
+}
+
+// demoWorkday and demoWeekend are the sample entries of a day, varied a
+// little by day number so the trend chart and stats aren't flat
+func demoWorkday(day int) []demoEntry {
+	entries := []demoEntry{
+		{7, 30, storage.CreateLogEntryRequest{Type: storage.EntryTypeMetric, Title: "Sleep", Metadata: storage.Metric{Name: "sleep", Value: 5.5 + float64((day*3)%5)/2, Unit: "h"}.Metadata(nil)}},
+		{9, 0, storage.CreateLogEntryRequest{Type: "meeting", Title: "Team standup", Tags: []string{"work", "meeting"}, Duration: intPtr(15), Location: "office"}},
+		{10, 30, storage.CreateLogEntryRequest{Type: "activity", Title: demoTasks[day%len(demoTasks)], Description: "Focused work on the current project", Tags: []string{"work", "project/dailylog"}, Status: intPtr(6 + day%4), Duration: intPtr(90 + 15*(day%3)), Location: "office"}},
+		{14, 0, storage.CreateLogEntryRequest{Type: "activity", Title: "Code review", Tags: []string{"work", "review"}, Status: intPtr(5 + day%3), Duration: intPtr(45)}},
+		{17, 30, storage.CreateLogEntryRequest{Type: "status", Title: "End of day check-in", Description: demoMoods[day%len(demoMoods)], Status: intPtr(5 + (day*3)%5)}},
+		{22, 0, storage.CreateLogEntryRequest{Type: storage.EntryTypeMetric, Title: "Steps", Metadata: storage.Metric{Name: "steps", Value: float64(6000 + 700*(day%6)), Unit: "steps"}.Metadata(nil)}},
+	}
+	if day%4 == 1 {
+		lesson := demoLessons[day/4%len(demoLessons)]
+		entries = append(entries, demoEntry{16, 0, storage.CreateLogEntryRequest{Type: storage.EntryTypeLesson, Title: lesson[0], Description: lesson[1], Tags: []string{"work"}}})
+	}
+	return entries
+}
+
+func demoWeekend(day int) []demoEntry {
+	return []demoEntry{
+		{8, 0, storage.CreateLogEntryRequest{Type: storage.EntryTypeMetric, Title: "Sleep", Metadata: storage.Metric{Name: "sleep", Value: 7 + float64(day%3)/2, Unit: "h"}.Metadata(nil)}},
+		{11, 0, storage.CreateLogEntryRequest{Type: "activity", Title: demoOutings[day%len(demoOutings)], Tags: []string{"personal", "outdoors"}, Status: intPtr(8), Duration: intPtr(120), Location: "park", Visibility: storage.VisibilityPrivate}},
+		{19, 0, storage.CreateLogEntryRequest{Type: "note", Title: "Read a few chapters", Tags: []string{"personal", "reading"}}},
+	}
+}
+
+var (
+	demoTasks   = []string{"Implemented the search API", "Fixed flaky integration tests", "Wrote the design doc", "Refactored the storage layer", "Paired on the release"}
+	demoMoods   = []string{"Productive day", "Lots of interruptions", "Good progress, a bit tired", "Felt stuck in the afternoon"}
+	demoOutings = []string{"Hike with friends", "Bike ride", "Farmers market"}
+	demoLessons = [][2]string{
+		{"Check the timezone before comparing dates", "A test failed only after 8pm: one side was UTC, the other local time."},
+		{"Small PRs get reviewed the same day", "The 40-file refactor sat for three days; split into four, each merged within hours."},
+		{"Write the rollback plan before the migration", "Rolling back took an hour longer than the migration because nobody had tried it."},
+	}
+)
+
+// SeedDemo fills store with DemoDays days of sample entries ending on today,
+// for demos and for trying the tool out
+func SeedDemo(store storage.DayStore, today time.Time) error {
+	today = dateOnly(today)
+	for i := DemoDays - 1; i >= 0; i-- {
+		date := today.AddDate(0, 0, -i)
+		entries := demoWorkday(i)
+		if weekday := date.Weekday(); weekday == time.Saturday || weekday == time.Sunday {
+			entries = demoWeekend(i)
+		}
+
+		dayLog, err := store.GetDay(date)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			e.req.Date = date.Add(time.Duration(e.hour)*time.Hour + time.Duration(e.minute)*time.Minute)
+			entry := storage.NewEntry(e.req)
+			// Lessons are the highlights worth revisiting
+			entry.Pinned = entry.Type == storage.EntryTypeLesson
+			// All but the last week has been through a weekly review
+			if i >= 7 {
+				reviewedAt := date.AddDate(0, 0, 7)
+				entry.ReviewedAt = &reviewedAt
+			}
+			dayLog.AddEntry(entry)
+		}
+		if err := store.SaveDay(dayLog); err != nil {
+			return err
+		}
+	}
+
+	if projects, ok := store.(storage.ProjectStore); ok {
+		list := &storage.ProjectList{}
+		_ = list.Add(storage.Project{
+			Name:        "dailylog",
+			Description: "The daily log tooling",
+			Tags:        []string{"project/dailylog"},
+			Start:       today.AddDate(0, 0, 1-DemoDays),
+			CreatedAt:   today.AddDate(0, 0, 1-DemoDays),
+		})
+		return projects.SaveProjects(list)
+	}
+	return nil
+}
+
+func intPtr(n int) *int {
+	return &n
+}
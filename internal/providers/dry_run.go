@@ -0,0 +1,150 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// stagedRevision marks a day that only exists in a dry run's staged writes,
+// so later writes to it are previewed as updates
+const stagedRevision = "dry-run"
+
+// PlannedWrite is a write a dry run would have made
+type PlannedWrite struct {
+	Date          string `json:"date" yaml:"date"`
+	CommitMessage string `json:"commit_message" yaml:"commit_message"`
+	Entries       int    `json:"entries" yaml:"entries"`
+	Delete        bool   `json:"delete,omitempty" yaml:"delete,omitempty"`
+}
+
+// DryRunProvider previews writes without making them. Reads are served by
+// the wrapped backend, overlaid with the days the dry run has changed so far;
+// writes are applied to those staged copies and recorded instead of saved.
+type DryRunProvider struct {
+	storage.DailyLogStorage
+
+	// Messages renders the previewed commit messages; nil uses the defaults
+	Messages *storage.CommitMessages
+
+	staged map[string]*storage.DayLog
+	writes []PlannedWrite
+}
+
+// NewDryRunProvider wraps backend so that nothing is written to it
+func NewDryRunProvider(backend storage.DailyLogStorage) *DryRunProvider {
+	return &DryRunProvider{
+		DailyLogStorage: backend,
+		staged:          make(map[string]*storage.DayLog),
+	}
+}
+
+// Writes returns the writes that would have been made, in order
+func (d *DryRunProvider) Writes() []PlannedWrite {
+	return d.writes
+}
+
+// GetDay returns the staged version of a day if the dry run changed it
+func (d *DryRunProvider) GetDay(date time.Time) (*storage.DayLog, error) {
+	if staged, ok := d.staged[date.Format("2006-01-02")]; ok {
+		return copyDayLog(staged), nil
+	}
+	return d.DailyLogStorage.GetDay(date)
+}
+
+// SaveDay records the save and stages the day
+func (d *DryRunProvider) SaveDay(dayLog *storage.DayLog) error {
+	message, err := d.Messages.Save(dayLog)
+	if err != nil {
+		return err
+	}
+	d.writes = append(d.writes, PlannedWrite{
+		Date:          dayLog.GetDateString(),
+		CommitMessage: message,
+		Entries:       len(dayLog.Entries),
+	})
+
+	staged := copyDayLog(dayLog)
+	if staged.Revision == "" {
+		staged.Revision = stagedRevision
+	}
+	d.staged[dayLog.GetDateString()] = staged
+	return nil
+}
+
+// DeleteDay records the deletion of a day
+func (d *DryRunProvider) DeleteDay(date time.Time) error {
+	dayLog, err := d.GetDay(date)
+	if err != nil {
+		return err
+	}
+	message, err := d.Messages.Delete(dayLog)
+	if err != nil {
+		return err
+	}
+	d.writes = append(d.writes, PlannedWrite{
+		Date:          date.Format("2006-01-02"),
+		CommitMessage: message,
+		Delete:        true,
+	})
+	d.staged[date.Format("2006-01-02")] = &storage.DayLog{Date: date, Entries: []storage.DailyLogEntry{}}
+	return nil
+}
+
+// CreateEntry returns the entry that would be created and records the save of its day
+func (d *DryRunProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	dayLog, err := d.GetDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := storage.NewEntry(req)
+	dayLog.AddEntry(entry)
+	if err := d.SaveDay(dayLog); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// UpdateEntry is not supported, as the entry's day is unknown
+func (d *DryRunProvider) UpdateEntry(req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	return nil, fmt.Errorf("UpdateEntry not implemented - requires date in request")
+}
+
+// DeleteEntry records the save of the day without the entry
+func (d *DryRunProvider) DeleteEntry(id string, date time.Time) error {
+	dayLog, err := d.GetDay(date)
+	if err != nil {
+		return err
+	}
+	if !dayLog.RemoveEntry(id) {
+		return storage.NotFoundError{Resource: "log entry", ID: id}
+	}
+	return d.SaveDay(dayLog)
+}
+
+// SaveSummary records the save of a day summary
+func (d *DryRunProvider) SaveSummary(summary *storage.SummaryResponse, targetType string, date time.Time) error {
+	if targetType != "day" {
+		return nil
+	}
+	dayLog, err := d.GetDay(date)
+	if err != nil {
+		return err
+	}
+	dayLog.DaySummary = summary.Summary
+	return d.SaveDay(dayLog)
+}
+
+// Backup does nothing in a dry run
+func (d *DryRunProvider) Backup() error {
+	return nil
+}
+
+// copyDayLog copies a day log so staged days can't be changed through callers
+func copyDayLog(dayLog *storage.DayLog) *storage.DayLog {
+	dayCopy := *dayLog
+	dayCopy.Entries = append([]storage.DailyLogEntry(nil), dayLog.Entries...)
+	return &dayCopy
+}
@@ -0,0 +1,68 @@
+package providers
+
+import (
+	"fmt"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Ways DuplicateProvider handles a new entry that duplicates an existing one
+const (
+	DuplicatesAllow = "allow"
+	DuplicatesWarn  = "warn"
+	DuplicatesSkip  = "skip"
+)
+
+// ValidateDuplicateMode checks a duplicate handling mode; empty means allow
+func ValidateDuplicateMode(mode string) error {
+	switch mode {
+	case "", DuplicatesAllow, DuplicatesWarn, DuplicatesSkip:
+		return nil
+	default:
+		return storage.ValidationError{
+			Field:   "duplicates",
+			Message: fmt.Sprintf("unknown mode %q (use allow, warn, or skip)", mode),
+		}
+	}
+}
+
+// DuplicateProvider checks new entries against the entries already logged
+// that day before creating them. In skip mode a duplicate is not created and
+// CreateEntry returns a storage.DuplicateError holding the existing entry; in
+// warn mode it is created anyway. See storage.FindDuplicate for what counts
+// as a duplicate.
+type DuplicateProvider struct {
+	storage.DailyLogStorage
+
+	skip   bool
+	window time.Duration
+
+	// OnDuplicate, if set, is told about duplicates created in warn mode
+	OnDuplicate func(req storage.CreateLogEntryRequest, existing storage.DailyLogEntry)
+}
+
+// NewDuplicateProvider checks new entries for duplicates within window,
+// skipping them when skip is set and only reporting them otherwise
+func NewDuplicateProvider(backend storage.DailyLogStorage, skip bool, window time.Duration) *DuplicateProvider {
+	return &DuplicateProvider{DailyLogStorage: backend, skip: skip, window: window}
+}
+
+// CreateEntry creates the entry unless it is a duplicate and duplicates are skipped
+func (p *DuplicateProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	dayLog, err := p.DailyLogStorage.GetDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := storage.FindDuplicate(dayLog.Entries, req, p.window)
+	if existing != nil {
+		if p.skip {
+			return nil, storage.DuplicateError{Existing: *existing}
+		}
+		if p.OnDuplicate != nil {
+			p.OnDuplicate(req, *existing)
+		}
+	}
+	return p.DailyLogStorage.CreateEntry(req)
+}
@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// MaxAttachmentSize is the largest file GitHub accepts in a repository
+const MaxAttachmentSize = 100 << 20
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// AttachmentPath returns the repository path a file attached to an entry
+// logged at t is stored at, e.g. "attachments/2025/09/29/153000-memo.m4a"
+// under the base path. Attachments live outside the day layout, so listing
+// days never sees them.
+func (g *GitHubStorageProvider) AttachmentPath(t time.Time, name string) string {
+	name = strings.Trim(unsafeNameChars.ReplaceAllString(path.Base(name), "-"), "-.")
+	if name == "" {
+		name = "attachment"
+	}
+	return path.Join(g.basePath, "attachments", t.Format("2006/01/02"), t.Format("150405")+"-"+name)
+}
+
+// SaveAttachment stores a file at filePath, which must not exist yet
+func (g *GitHubStorageProvider) SaveAttachment(filePath string, data []byte) error {
+	if len(data) > MaxAttachmentSize {
+		return storage.ValidationError{Field: "attachment", Message: fmt.Sprintf("must be at most %d MB (got %.1f MB)", MaxAttachmentSize>>20, float64(len(data))/(1<<20))}
+	}
+	branch, err := g.writeBranch()
+	if err != nil {
+		return err
+	}
+
+	message := "Add attachment " + path.Base(filePath)
+	if _, err := g.writeFile(filePath, data, "", message, branch); err != nil {
+		return storage.StorageError{
+			Operation: "SaveAttachment",
+			Message:   fmt.Sprintf("failed to save %s", filePath),
+			Cause:     err,
+		}
+	}
+	g.afterWrite()
+	return nil
+}
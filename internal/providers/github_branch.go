@@ -0,0 +1,205 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// branchState is what a provider has learned about its branches, shared
+// with its copies bound to a context
+type branchState struct {
+	mu                 sync.Mutex
+	created            bool
+	defaultBranch      string
+	pullRequestChecked time.Time
+}
+
+// defaultBranch returns the repository's default branch
+func (g *GitHubStorageProvider) defaultBranch() (string, error) {
+	g.state.mu.Lock()
+	defer g.state.mu.Unlock()
+
+	if g.state.defaultBranch == "" {
+		repository, _, err := g.client.Repositories.Get(g.ctx, g.owner, g.repo)
+		if err != nil {
+			return "", storage.StorageError{Operation: "GetRepository", Message: "failed to get repository", Cause: err}
+		}
+		g.state.defaultBranch = repository.GetDefaultBranch()
+	}
+	return g.state.defaultBranch, nil
+}
+
+// usesBranch reports whether logs go to a branch other than the default one
+func (g *GitHubStorageProvider) usesBranch() bool {
+	return g.branch != ""
+}
+
+// readRef returns the ref to read day files at: the configured branch, or
+// the default branch until the configured one has been created
+func (g *GitHubStorageProvider) readRef() string {
+	if !g.usesBranch() {
+		return ""
+	}
+	if exists, err := g.branchExists(); err == nil && !exists {
+		return ""
+	}
+	return g.branch
+}
+
+// branchExists reports whether the configured branch exists, remembering
+// the answer once it does
+func (g *GitHubStorageProvider) branchExists() (bool, error) {
+	g.state.mu.Lock()
+	defer g.state.mu.Unlock()
+
+	if g.state.created {
+		return true, nil
+	}
+	_, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, "heads/"+g.branch)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return false, nil
+		}
+		return false, storage.StorageError{Operation: "GetBranch", Message: fmt.Sprintf("failed to get branch %s", g.branch), Cause: err}
+	}
+	g.state.created = true
+	return true, nil
+}
+
+// writeBranch returns the branch to write to, creating the configured branch
+// from the head of the default branch the first time. It returns nil for the
+// default branch.
+func (g *GitHubStorageProvider) writeBranch() (*string, error) {
+	if !g.usesBranch() {
+		return nil, nil
+	}
+	exists, err := g.branchExists()
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return github.String(g.branch), nil
+	}
+
+	base, err := g.defaultBranch()
+	if err != nil {
+		return nil, err
+	}
+	head, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, "heads/"+base)
+	if err != nil {
+		return nil, storage.StorageError{Operation: "CreateBranch", Message: "failed to get " + base, Cause: err}
+	}
+	_, _, err = g.client.Git.CreateRef(g.ctx, g.owner, g.repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + g.branch),
+		Object: &github.GitObject{SHA: head.GetObject().SHA},
+	})
+	// Another client may have created it first
+	if err != nil && !isConflict(err) {
+		return nil, storage.StorageError{Operation: "CreateBranch", Message: fmt.Sprintf("failed to create branch %s", g.branch), Cause: err}
+	}
+
+	g.state.mu.Lock()
+	g.state.created = true
+	g.state.mu.Unlock()
+	return github.String(g.branch), nil
+}
+
+// headRef returns the ref that batch commits are made on, e.g. "heads/main".
+// Unless create is set, a configured branch that does not exist yet is not
+// created and the default branch is returned instead.
+func (g *GitHubStorageProvider) headRef(create bool) (string, error) {
+	if create {
+		branch, err := g.writeBranch()
+		if err != nil {
+			return "", err
+		}
+		if branch != nil {
+			return "heads/" + *branch, nil
+		}
+	} else if ref := g.readRef(); ref != "" {
+		return "heads/" + ref, nil
+	}
+	base, err := g.defaultBranch()
+	if err != nil {
+		return "", err
+	}
+	return "heads/" + base, nil
+}
+
+// pullRequestCheckInterval is how long auto-PR mode trusts that the pull
+// request it found or opened is still open
+const pullRequestCheckInterval = 10 * time.Minute
+
+// afterWrite opens a pull request from the configured branch into the
+// default branch after a write, in auto-PR mode, unless one is already open
+func (g *GitHubStorageProvider) afterWrite() {
+	if !g.autoPR || !g.usesBranch() {
+		return
+	}
+	g.state.mu.Lock()
+	recent := time.Since(g.state.pullRequestChecked) < pullRequestCheckInterval
+	g.state.mu.Unlock()
+	if recent {
+		return
+	}
+
+	url, err := g.ensurePullRequest()
+	if err != nil {
+		if g.OnPullRequestError != nil {
+			g.OnPullRequestError(err)
+		}
+		return
+	}
+	g.state.mu.Lock()
+	g.state.pullRequestChecked = time.Now()
+	g.state.mu.Unlock()
+	if url != "" && g.OnPullRequestOpened != nil {
+		g.OnPullRequestOpened(url)
+	}
+}
+
+// ensurePullRequest opens a pull request for the configured branch unless
+// one is open, returning the URL of the one it opened
+func (g *GitHubStorageProvider) ensurePullRequest() (string, error) {
+	base, err := g.defaultBranch()
+	if err != nil {
+		return "", err
+	}
+	if base == g.branch {
+		return "", nil
+	}
+
+	open, _, err := g.client.PullRequests.List(g.ctx, g.owner, g.repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  g.owner + ":" + g.branch,
+		Base:  base,
+	})
+	if err != nil {
+		return "", storage.StorageError{Operation: "PullRequest", Message: "failed to list pull requests", Cause: err}
+	}
+	if len(open) > 0 {
+		return "", nil
+	}
+
+	pr, _, err := g.client.PullRequests.Create(g.ctx, g.owner, g.repo, &github.NewPullRequest{
+		Title: github.String(PullRequestTitle(g.branch)),
+		Head:  github.String(g.branch),
+		Base:  github.String(base),
+		Body:  github.String("Daily log entries written to " + g.branch + "."),
+	})
+	if err != nil {
+		return "", storage.StorageError{Operation: "PullRequest", Message: fmt.Sprintf("failed to open a pull request for %s", g.branch), Cause: err}
+	}
+	return pr.GetHTMLURL(), nil
+}
+
+// PullRequestTitle returns the title of pull requests opened in auto-PR mode
+func PullRequestTitle(branch string) string {
+	return "Daily log updates from " + branch
+}
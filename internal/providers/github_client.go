@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/oauth2"
+
+	"dailylog/internal/metrics"
+	"dailylog/internal/storage"
+	"dailylog/internal/tracing"
+)
+
+// GitHub API metrics, recorded for every client
+var (
+	githubRequests = metrics.Default.NewCounter("dailylog_github_api_requests_total",
+		"GitHub API requests, by method and status code (\"error\" when there was no response).", "method", "status")
+	githubDuration = metrics.Default.NewHistogram("dailylog_github_api_request_duration_seconds",
+		"GitHub API request latency.", metrics.DefaultBuckets, "method")
+	githubRateRemaining = metrics.Default.NewGauge("dailylog_github_api_rate_limit_remaining",
+		"Requests left in the current GitHub API rate limit window, as last reported.")
+)
+
+// NewGitHubClient creates a GitHub API client authenticated with token.
+// baseURL, if set, targets GitHub Enterprise Server or a proxy instead of
+// github.com: a bare host such as https://github.example.com gets the
+// Enterprise /api/v3/ path, and any other URL is used as given. uploadURL
+// defaults to the matching upload endpoint: /api/uploads/ beside /api/v3/,
+// uploads.<domain> for an api.<domain> host, and baseURL itself otherwise.
+// Requests go through the cassette set by UseCassette, if any.
+func NewGitHubClient(token, baseURL, uploadURL string) (*github.Client, error) {
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+	client := github.NewClient(&http.Client{
+		Transport: &oauth2.Transport{Source: ts, Base: instrumentedTransport{base: githubTransport()}},
+	})
+	if baseURL == "" && uploadURL == "" {
+		return client, nil
+	}
+
+	base := client.BaseURL
+	if baseURL != "" {
+		var err error
+		if base, err = parseAPIURL("github.base_url", baseURL); err != nil {
+			return nil, err
+		}
+		if base.Path == "/" && !strings.HasPrefix(base.Host, "api.") {
+			base.Path = "/api/v3/"
+		}
+	}
+
+	var upload *url.URL
+	if uploadURL != "" {
+		var err error
+		if upload, err = parseAPIURL("github.upload_url", uploadURL); err != nil {
+			return nil, err
+		}
+	} else {
+		upload = defaultUploadURL(base)
+	}
+
+	client.BaseURL = base
+	client.UploadURL = upload
+	return client, nil
+}
+
+// defaultUploadURL returns the upload endpoint that goes with the API at base
+func defaultUploadURL(base *url.URL) *url.URL {
+	upload := *base
+	switch {
+	case strings.HasSuffix(base.Path, "/api/v3/"):
+		upload.Path = strings.TrimSuffix(base.Path, "v3/") + "uploads/"
+	case strings.HasPrefix(base.Host, "api."):
+		upload.Host = "uploads." + strings.TrimPrefix(base.Host, "api.")
+	}
+	return &upload
+}
+
+// parseAPIURL parses an absolute http(s) URL, ending its path with a slash
+// as go-github requires
+func parseAPIURL(field, raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, storage.ValidationError{
+			Field:   field,
+			Message: "must be an absolute http or https URL, such as https://github.example.com",
+		}
+	}
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+	return parsed, nil
+}
+
+// instrumentedTransport records GitHub API metrics for each request, and
+// traces it under any span in its context
+type instrumentedTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "GitHub "+req.Method, trace.SpanKindClient,
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.String("url.path", req.URL.Path))
+	defer span.End()
+
+	started := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	githubDuration.Observe(time.Since(started).Seconds(), req.Method)
+	if err != nil {
+		githubRequests.Inc(req.Method, "error")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	githubRequests.Inc(req.Method, strconv.Itoa(resp.StatusCode))
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		githubRateRemaining.Set(float64(remaining))
+	}
+	return resp, nil
+}
@@ -0,0 +1,133 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+)
+
+// errRevisionChanged reports a signed write whose file changed since it was
+// read; isConflict treats it like GitHub's stale SHA answer
+var errRevisionChanged = errors.New("file changed since it was read")
+
+// commitIdentity returns the author and committer configured for commits,
+// dated now, the committer defaulting to the author; nil leaves them to GitHub
+func (g *GitHubStorageProvider) commitIdentity() (author, committer *github.CommitAuthor) {
+	// Signatures cover whole seconds
+	now := &github.Timestamp{Time: time.Now().Truncate(time.Second)}
+	if g.commit.AuthorName != "" {
+		author = &github.CommitAuthor{Name: github.String(g.commit.AuthorName), Email: github.String(g.commit.AuthorEmail), Date: now}
+	}
+	committer = author
+	if g.commit.CommitterName != "" {
+		committer = &github.CommitAuthor{Name: github.String(g.commit.CommitterName), Email: github.String(g.commit.CommitterEmail), Date: now}
+	}
+	return author, committer
+}
+
+// commitOptions returns the options signing commits, if configured
+func (g *GitHubStorageProvider) commitOptions() *github.CreateCommitOptions {
+	if g.commit.SigningKey == "" {
+		return nil
+	}
+	return &github.CreateCommitOptions{Signer: gpgSigner(g.commit.SigningProgram, g.commit.SigningKey)}
+}
+
+// gpgSigner signs commits with key by running program (gpg if empty) the way git does
+func gpgSigner(program, key string) github.MessageSigner {
+	if program == "" {
+		program = "gpg"
+	}
+	return github.MessageSignerFunc(func(w io.Writer, r io.Reader) error {
+		var stderr bytes.Buffer
+		cmd := exec.Command(program, "--status-fd=2", "-bsau", key)
+		cmd.Stdin = r
+		cmd.Stdout = w
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed to sign the commit: %w: %s", program, err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	})
+}
+
+// createCommit creates a commit of tree on parents (none for a root commit)
+// with the configured identity and signature
+func (g *GitHubStorageProvider) createCommit(message, tree string, parents ...string) (*github.Commit, error) {
+	author, committer := g.commitIdentity()
+	parentCommits := make([]*github.Commit, 0, len(parents))
+	for _, parent := range parents {
+		parentCommits = append(parentCommits, &github.Commit{SHA: github.String(parent)})
+	}
+	commit, _, err := g.client.Git.CreateCommit(g.ctx, g.owner, g.repo, &github.Commit{
+		Message:   github.String(message),
+		Tree:      &github.Tree{SHA: github.String(tree)},
+		Parents:   parentCommits,
+		Author:    author,
+		Committer: committer,
+	}, g.commitOptions())
+	return commit, err
+}
+
+// commitFile writes content to filePath, or deletes it when content is nil,
+// in a commit made through the Git Data API so it can be signed. The file
+// must still be at revision (empty for no file), and the branch must not
+// move meanwhile; either is reported as a conflict. It returns the file's
+// new blob SHA.
+func (g *GitHubStorageProvider) commitFile(filePath string, content []byte, revision, message string) (string, error) {
+	ref, err := g.headRef(true)
+	if err != nil {
+		return "", err
+	}
+	head, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, ref)
+	if err != nil {
+		return "", err
+	}
+	parent, _, err := g.client.Git.GetCommit(g.ctx, g.owner, g.repo, head.GetObject().GetSHA())
+	if err != nil {
+		return "", err
+	}
+
+	current, _, _, err := g.client.Repositories.GetContents(g.ctx, g.owner, g.repo, filePath,
+		&github.RepositoryContentGetOptions{Ref: parent.GetSHA()})
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return "", err
+	}
+	if current.GetSHA() != revision {
+		return "", errRevisionChanged
+	}
+
+	entry := &github.TreeEntry{Path: github.String(filePath), Mode: github.String("100644"), Type: github.String("blob")}
+	// A tree entry without a SHA or content deletes the file
+	if content != nil {
+		blob, _, err := g.client.Git.CreateBlob(g.ctx, g.owner, g.repo, &github.Blob{
+			Content:  github.String(base64.StdEncoding.EncodeToString(content)),
+			Encoding: github.String("base64"),
+		})
+		if err != nil {
+			return "", err
+		}
+		entry.SHA = blob.SHA
+	}
+
+	tree, _, err := g.client.Git.CreateTree(g.ctx, g.owner, g.repo, parent.GetTree().GetSHA(), []*github.TreeEntry{entry})
+	if err != nil {
+		return "", err
+	}
+	commit, err := g.createCommit(message, tree.GetSHA(), parent.GetSHA())
+	if err != nil {
+		return "", err
+	}
+	head.Object.SHA = commit.SHA
+	if _, _, err := g.client.Git.UpdateRef(g.ctx, g.owner, g.repo, head, false); err != nil {
+		return "", err
+	}
+	return entry.GetSHA(), nil
+}
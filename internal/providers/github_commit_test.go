@@ -0,0 +1,52 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+func TestCommitIdentity(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            storage.CommitConfig
+		author, committer string
+	}{
+		{name: "none configured"},
+		{
+			name:   "committer defaults to the author",
+			config: storage.CommitConfig{AuthorName: "Ada", AuthorEmail: "ada@example.com"},
+			author: "Ada <ada@example.com>", committer: "Ada <ada@example.com>",
+		},
+		{
+			name: "both configured",
+			config: storage.CommitConfig{
+				AuthorName: "Ada", AuthorEmail: "ada@example.com",
+				CommitterName: "Journal Bot", CommitterEmail: "bot@example.com",
+			},
+			author: "Ada <ada@example.com>", committer: "Journal Bot <bot@example.com>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &GitHubStorageProvider{commit: tt.config}
+			author, committer := g.commitIdentity()
+			if got := formatIdentity(author); got != tt.author {
+				t.Errorf("author = %q, want %q", got, tt.author)
+			}
+			if got := formatIdentity(committer); got != tt.committer {
+				t.Errorf("committer = %q, want %q", got, tt.committer)
+			}
+		})
+	}
+}
+
+// formatIdentity writes an identity as git does, empty for none
+func formatIdentity(identity *github.CommitAuthor) string {
+	if identity == nil {
+		return ""
+	}
+	return identity.GetName() + " <" + identity.GetEmail() + ">"
+}
@@ -0,0 +1,127 @@
+package providers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// RecompressDays rewrites the given days that are stored in a format other
+// than format, all in a single commit on the branch logs are written to, and
+// returns the days rewritten. A day moving to another file extension has its
+// old file removed in the same commit. With dryRun it only returns the days
+// it would rewrite. If the branch moves while the commit is built, nothing is
+// written and running it again picks up the change.
+func (g *GitHubStorageProvider) RecompressDays(days []time.Time, format string, dryRun bool) ([]time.Time, error) {
+	if err := storage.ValidateDayFormat(format); err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = storage.DayFormatPretty
+	}
+
+	ref, err := g.headRef(!dryRun)
+	if err != nil {
+		return nil, err
+	}
+	head, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, ref)
+	if err != nil {
+		return nil, recompressError("failed to get "+ref, err)
+	}
+	parentSHA := head.GetObject().GetSHA()
+
+	// Read every day at the commit being built on, so no newer write is undone
+	var rewritten []time.Time
+	var entries []*github.TreeEntry
+	for _, date := range days {
+		fileContent, err := g.getDayFile(date, parentSHA)
+		if err != nil {
+			return nil, recompressError(fmt.Sprintf("failed to get day %s", date.Format("2006-01-02")), err)
+		}
+		if fileContent == nil {
+			continue
+		}
+		content, err := fileContent.GetContent()
+		if err != nil {
+			return nil, recompressError("failed to decode file content", err)
+		}
+		dayLog, stored, err := storage.DecodeDay([]byte(content))
+		if err != nil {
+			return nil, recompressError(fmt.Sprintf("failed to parse day %s", date.Format("2006-01-02")), err)
+		}
+		if stored == format {
+			continue
+		}
+		rewritten = append(rewritten, date)
+		if dryRun {
+			continue
+		}
+
+		data, err := storage.EncodeDay(dayLog, format)
+		if err != nil {
+			return nil, recompressError("failed to serialize day log", err)
+		}
+		blob, _, err := g.client.Git.CreateBlob(g.ctx, g.owner, g.repo, &github.Blob{
+			Content:  github.String(base64.StdEncoding.EncodeToString(data)),
+			Encoding: github.String("base64"),
+		})
+		if err != nil {
+			return nil, recompressError(fmt.Sprintf("failed to upload day %s", date.Format("2006-01-02")), err)
+		}
+
+		newPath := g.getDayFilePath(date, format)
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(newPath),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+			SHA:  blob.SHA,
+		})
+		if oldPath := fileContent.GetPath(); oldPath != newPath {
+			// A tree entry without a SHA or content deletes the file
+			entries = append(entries, &github.TreeEntry{
+				Path: github.String(oldPath),
+				Mode: github.String("100644"),
+				Type: github.String("blob"),
+			})
+		}
+	}
+	if dryRun || len(rewritten) == 0 {
+		return rewritten, nil
+	}
+
+	parent, _, err := g.client.Git.GetCommit(g.ctx, g.owner, g.repo, parentSHA)
+	if err != nil {
+		return nil, recompressError("failed to get "+ref, err)
+	}
+	tree, _, err := g.client.Git.CreateTree(g.ctx, g.owner, g.repo, parent.GetTree().GetSHA(), entries)
+	if err != nil {
+		return nil, recompressError("failed to create tree", err)
+	}
+	commit, err := g.createCommit(RecompressCommitMessage(len(rewritten), format), tree.GetSHA(), parentSHA)
+	if err != nil {
+		return nil, recompressError("failed to create commit", err)
+	}
+
+	head.Object.SHA = commit.SHA
+	if _, _, err := g.client.Git.UpdateRef(g.ctx, g.owner, g.repo, head, false); err != nil {
+		if isConflict(err) {
+			return nil, recompressError(ref+" changed while recompressing; run again", err)
+		}
+		return nil, recompressError("failed to update "+ref, err)
+	}
+	g.afterWrite()
+	return rewritten, nil
+}
+
+// RecompressCommitMessage returns the commit message used when recompressing days
+func RecompressCommitMessage(days int, format string) string {
+	return fmt.Sprintf("Recompress %d daily logs as %s", days, format)
+}
+
+func recompressError(message string, err error) error {
+	return storage.StorageError{Operation: "RecompressDays", Message: message, Cause: err}
+}
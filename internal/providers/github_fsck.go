@@ -0,0 +1,285 @@
+package providers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// DefaultFsckBatchSize is how many days Fsck repairs per commit
+const DefaultFsckBatchSize = 100
+
+// FsckProblem is a problem found in a day file
+type FsckProblem struct {
+	Path               string `json:"path" yaml:"path"`
+	Date               string `json:"date,omitempty" yaml:"date,omitempty"`
+	storage.DayProblem `yaml:",inline"`
+	// Fixed is set once the repair is committed; malformed files are never fixed
+	Fixed bool `json:"fixed" yaml:"fixed"`
+}
+
+// FsckResult lists the day files checked and the problems found in them
+type FsckResult struct {
+	Files    int           `json:"files" yaml:"files"`
+	Days     int           `json:"days" yaml:"days"`
+	Problems []FsckProblem `json:"problems" yaml:"problems"`
+	Commits  int           `json:"commits" yaml:"commits"`
+}
+
+// fsckFile is a day file as read by Fsck; day is nil if it's malformed
+type fsckFile struct {
+	path   string
+	mode   string
+	date   time.Time
+	day    *storage.DayLog
+	format string
+}
+
+// fsckRepair rewrites a day as one file at path, removing the others
+type fsckRepair struct {
+	path     string
+	mode     string
+	day      *storage.DayLog
+	format   string
+	remove   []string
+	problems int
+}
+
+// Fsck checks every day file under the storage root: that it can be read,
+// is where the layout and its format put it, is the only file of its day,
+// has a date and totals matching its entries, and that no entry ID is
+// missing or used twice. With fix, the problems found are repaired,
+// batchSize days per commit: a day stored twice is merged into one file,
+// files are moved, totals recalculated, repeated entries dropped, and
+// reused IDs replaced. Malformed files are only reported. The commits build
+// on the branch head that was checked and are only recorded if nothing was
+// pushed in the meantime, so an interrupted run can be started again to
+// finish. progress, if set, is told after each commit how many of the days
+// have been repaired.
+func (g *GitHubStorageProvider) Fsck(fix bool, batchSize int, progress func(repaired, total int)) (FsckResult, error) {
+	result := FsckResult{Problems: []FsckProblem{}}
+	if batchSize <= 0 {
+		batchSize = DefaultFsckBatchSize
+	}
+
+	ref, err := g.headRef(fix)
+	if err != nil {
+		return result, err
+	}
+	head, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, ref)
+	if err != nil {
+		return result, fsckError("failed to get "+ref, err)
+	}
+	parent, _, err := g.client.Git.GetCommit(g.ctx, g.owner, g.repo, head.GetObject().GetSHA())
+	if err != nil {
+		return result, fsckError("failed to get "+ref, err)
+	}
+	tree, _, err := g.client.Git.GetTree(g.ctx, g.owner, g.repo, parent.GetTree().GetSHA(), true)
+	if err != nil {
+		return result, fsckError("failed to list repository files", err)
+	}
+	if tree.GetTruncated() {
+		return result, fsckError("the repository has too many files to list in one request", nil)
+	}
+
+	files, err := g.readDayFiles(tree)
+	if err != nil {
+		return result, err
+	}
+	result.Files = len(files)
+
+	byDate := make(map[time.Time][]*fsckFile)
+	var dates []time.Time
+	for _, file := range files {
+		if file.day == nil {
+			result.Problems = append(result.Problems, FsckProblem{
+				Path:       file.path,
+				Date:       file.date.Format("2006-01-02"),
+				DayProblem: storage.DayProblem{Kind: storage.ProblemMalformed, Message: "not a readable day log; fix it by hand or with restore --history"},
+			})
+			continue
+		}
+		if byDate[file.date] == nil {
+			dates = append(dates, file.date)
+		}
+		byDate[file.date] = append(byDate[file.date], file)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	result.Days = len(dates)
+
+	repairs := make([]*fsckRepair, len(dates))
+	days := make([]*storage.DayLog, len(dates))
+	for i, date := range dates {
+		repairs[i] = g.planDayRepair(date, byDate[date], &result)
+		days[i] = repairs[i].day
+	}
+	for i, problems := range storage.RepairEntryIDs(days) {
+		repairs[i].problems += len(problems)
+		for _, problem := range problems {
+			result.Problems = append(result.Problems, FsckProblem{Path: repairs[i].path, Date: dates[i].Format("2006-01-02"), DayProblem: problem})
+		}
+	}
+	sort.SliceStable(result.Problems, func(i, j int) bool {
+		a, b := result.Problems[i], result.Problems[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		return a.Path < b.Path
+	})
+
+	var pending []*fsckRepair
+	for i, repair := range repairs {
+		if repair.problems > 0 {
+			storage.RepairDayLog(repair.day, dates[i])
+			pending = append(pending, repair)
+		}
+	}
+	if !fix || len(pending) == 0 {
+		return result, nil
+	}
+
+	// Problems are marked fixed by day, as each day is repaired whole
+	fixed := make(map[string]bool)
+	treeSHA, parentSHA := parent.GetTree().GetSHA(), parent.GetSHA()
+	for start := 0; start < len(pending); start += batchSize {
+		batch := pending[start:min(start+batchSize, len(pending))]
+		var entries []*github.TreeEntry
+		for _, repair := range batch {
+			data, err := storage.EncodeDay(repair.day, repair.format)
+			if err != nil {
+				return result, fsckError("failed to serialize day log", err)
+			}
+			blob, _, err := g.client.Git.CreateBlob(g.ctx, g.owner, g.repo, &github.Blob{
+				Content:  github.String(base64.StdEncoding.EncodeToString(data)),
+				Encoding: github.String("base64"),
+			})
+			if err != nil {
+				return result, fsckError(fmt.Sprintf("failed to upload day %s", repair.day.GetDateString()), err)
+			}
+			entries = append(entries, &github.TreeEntry{Path: github.String(repair.path), Mode: github.String(repair.mode), Type: github.String("blob"), SHA: blob.SHA})
+			for _, filePath := range repair.remove {
+				// A tree entry without a SHA or content deletes the file
+				entries = append(entries, &github.TreeEntry{Path: github.String(filePath), Mode: github.String("100644"), Type: github.String("blob")})
+			}
+		}
+
+		newTree, _, err := g.client.Git.CreateTree(g.ctx, g.owner, g.repo, treeSHA, entries)
+		if err != nil {
+			return result, fsckError("failed to create tree", err)
+		}
+		commit, err := g.createCommit(FsckCommitMessage(len(batch)), newTree.GetSHA(), parentSHA)
+		if err != nil {
+			return result, fsckError("failed to create commit", err)
+		}
+		head.Object.SHA = commit.SHA
+		if _, _, err := g.client.Git.UpdateRef(g.ctx, g.owner, g.repo, head, false); err != nil {
+			if isConflict(err) {
+				return result, fsckError(ref+" changed while repairing days; run again to finish", err)
+			}
+			return result, fsckError("failed to update "+ref, err)
+		}
+		treeSHA, parentSHA = newTree.GetSHA(), commit.GetSHA()
+		result.Commits++
+
+		for _, repair := range batch {
+			fixed[repair.day.GetDateString()] = true
+		}
+		for i := range result.Problems {
+			if result.Problems[i].Kind != storage.ProblemMalformed && fixed[result.Problems[i].Date] {
+				result.Problems[i].Fixed = true
+			}
+		}
+		g.afterWrite()
+		if progress != nil {
+			progress(min(start+batchSize, len(pending)), len(pending))
+		}
+	}
+	return result, nil
+}
+
+// planDayRepair checks the files of a day, adding what's wrong to result,
+// and returns its repair: the day's entries merged into the file the layout
+// puts it in, preferring one in the configured format
+func (g *GitHubStorageProvider) planDayRepair(date time.Time, files []*fsckFile, result *FsckResult) *fsckRepair {
+	keep, best := files[0], -1
+	for _, file := range files {
+		score := 0
+		if file.path == g.getDayFilePath(date, file.format) {
+			score++
+			if storage.DayFileName(date, file.format) == storage.DayFileName(date, g.dayFormat) {
+				score++
+			}
+		}
+		if score > best {
+			keep, best = file, score
+		}
+	}
+
+	repair := &fsckRepair{path: g.getDayFilePath(date, keep.format), mode: keep.mode, format: keep.format}
+	day := *keep.day
+	day.Entries = nil
+	report := func(file *fsckFile, problem storage.DayProblem) {
+		repair.problems++
+		result.Problems = append(result.Problems, FsckProblem{Path: file.path, Date: date.Format("2006-01-02"), DayProblem: problem})
+	}
+	for _, file := range files {
+		if file != keep {
+			report(file, storage.DayProblem{Kind: storage.ProblemDuplicateDay, Message: fmt.Sprintf("also stored at %s; merged into %s", keep.path, repair.path)})
+			repair.remove = append(repair.remove, file.path)
+		}
+		for _, problem := range storage.CheckDayLog(file.day, date) {
+			report(file, problem)
+		}
+		day.Entries = append(day.Entries, file.day.Entries...)
+	}
+	if keep.path != repair.path {
+		report(keep, storage.DayProblem{Kind: storage.ProblemWrongPath, Message: "should be at " + repair.path})
+		repair.remove = append(repair.remove, keep.path)
+	}
+	repair.day = &day
+	return repair
+}
+
+// readDayFiles reads the day files under the storage root in tree, in path order
+func (g *GitHubStorageProvider) readDayFiles(tree *github.Tree) ([]*fsckFile, error) {
+	root := path.Clean(g.basePath) + "/"
+	var files []*fsckFile
+	for _, entry := range tree.Entries {
+		filePath := entry.GetPath()
+		if entry.GetType() != "blob" || !strings.HasPrefix(filePath, root) {
+			continue
+		}
+		date, ok := storage.ParseDayFileName(path.Base(filePath))
+		if !ok {
+			continue
+		}
+
+		raw, _, err := g.client.Git.GetBlobRaw(g.ctx, g.owner, g.repo, entry.GetSHA())
+		if err != nil {
+			return nil, fsckError("failed to read "+filePath, err)
+		}
+		file := &fsckFile{path: filePath, mode: entry.GetMode(), date: date}
+		if day, format, err := storage.DecodeDay(raw); err == nil {
+			file.day, file.format = day, format
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files, nil
+}
+
+// FsckCommitMessage returns the commit message used when repairing days
+func FsckCommitMessage(days int) string {
+	return fmt.Sprintf("Repair %d daily logs", days)
+}
+
+func fsckError(message string, err error) error {
+	return storage.StorageError{Operation: "Fsck", Message: message, Cause: err}
+}
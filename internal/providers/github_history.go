@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// DayRevision is one committed version of a day file
+type DayRevision struct {
+	SHA     string    `json:"sha" yaml:"sha"`
+	Time    time.Time `json:"time" yaml:"time"`
+	Message string    `json:"message" yaml:"message"`
+}
+
+// DayHistory lists the commits that touched a day file, newest first
+func (g *GitHubStorageProvider) DayHistory(date time.Time) ([]DayRevision, error) {
+	return g.dayCommits(date, time.Time{}, 0)
+}
+
+// GetDayAsOf returns the day as it was stored at the given moment, along with
+// the commit it came from. It returns NotFoundError if the day did not exist then.
+func (g *GitHubStorageProvider) GetDayAsOf(date, asOf time.Time) (*storage.DayLog, *DayRevision, error) {
+	revisions, err := g.dayCommits(date, asOf, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, nil, storage.NotFoundError{
+			Resource: "day log",
+			ID:       fmt.Sprintf("%s as of %s", date.Format("2006-01-02"), asOf.Format(time.RFC3339)),
+		}
+	}
+	revision := revisions[0]
+
+	fileContent, err := g.getDayFile(date, revision.SHA)
+	if err != nil {
+		return nil, nil, storage.StorageError{
+			Operation: "GetDayAsOf",
+			Message:   fmt.Sprintf("failed to get day %s at %s", date.Format("2006-01-02"), revision.ShortSHA()),
+			Cause:     err,
+		}
+	}
+	if fileContent == nil {
+		// The newest commit before asOf deleted the file
+		return nil, &revision, storage.NotFoundError{
+			Resource: "day log",
+			ID:       fmt.Sprintf("%s as of %s (deleted in %s)", date.Format("2006-01-02"), asOf.Format(time.RFC3339), revision.ShortSHA()),
+		}
+	}
+
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return nil, nil, storage.StorageError{
+			Operation: "GetDayAsOf",
+			Message:   "failed to decode file content",
+			Cause:     err,
+		}
+	}
+
+	dayLog, _, err := storage.DecodeDay([]byte(content))
+	if err != nil {
+		return nil, nil, storage.StorageError{
+			Operation: "GetDayAsOf",
+			Message:   "failed to parse day log JSON",
+			Cause:     err,
+		}
+	}
+	return dayLog, &revision, nil
+}
+
+// dayCommits lists commits touching a day file, in any of its formats, up to
+// until (zero for now), newest first, stopping after limit commits (zero for all)
+func (g *GitHubStorageProvider) dayCommits(date, until time.Time, limit int) ([]DayRevision, error) {
+	var revisions []DayRevision
+	seen := make(map[string]bool)
+	for _, filePath := range g.dayFilePaths(date) {
+		pathRevisions, err := g.fileCommits(filePath, date, until, limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, revision := range pathRevisions {
+			// Recompressing a day touches both of its paths in one commit
+			if !seen[revision.SHA] {
+				seen[revision.SHA] = true
+				revisions = append(revisions, revision)
+			}
+		}
+	}
+
+	sort.SliceStable(revisions, func(i, j int) bool { return revisions[i].Time.After(revisions[j].Time) })
+	if limit > 0 && len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+	return revisions, nil
+}
+
+// fileCommits lists commits touching filePath, as dayCommits does for a day
+func (g *GitHubStorageProvider) fileCommits(filePath string, date, until time.Time, limit int) ([]DayRevision, error) {
+	opts := &github.CommitsListOptions{
+		SHA:         g.readRef(),
+		Path:        filePath,
+		Until:       until,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	if limit > 0 && limit < opts.PerPage {
+		opts.PerPage = limit
+	}
+
+	var revisions []DayRevision
+	for {
+		commits, resp, err := g.client.Repositories.ListCommits(g.ctx, g.owner, g.repo, opts)
+		if err != nil {
+			return nil, storage.StorageError{
+				Operation: "DayHistory",
+				Message:   fmt.Sprintf("failed to list history of %s", date.Format("2006-01-02")),
+				Cause:     err,
+			}
+		}
+
+		for _, commit := range commits {
+			revisions = append(revisions, DayRevision{
+				SHA:     commit.GetSHA(),
+				Time:    commit.GetCommit().GetCommitter().GetDate().Time,
+				Message: commit.GetCommit().GetMessage(),
+			})
+			if limit > 0 && len(revisions) >= limit {
+				return revisions, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return revisions, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// ShortSHA returns the abbreviated commit hash
+func (r DayRevision) ShortSHA() string {
+	if len(r.SHA) > 7 {
+		return r.SHA[:7]
+	}
+	return r.SHA
+}
@@ -0,0 +1,175 @@
+package providers
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// DefaultLayoutBatchSize is how many day files MigrateLayout moves per commit
+const DefaultLayoutBatchSize = 200
+
+// LayoutMove is a day file moved to another directory
+type LayoutMove struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+}
+
+// layoutMove is a pending move of a blob in the repository tree
+type layoutMove struct {
+	LayoutMove
+	sha  string
+	mode string
+}
+
+// MigrateLayout moves every day file under the storage root to the directory
+// layout puts it in, batchSize files per commit, and returns the files moved.
+// Files keep their content and format. Each commit builds on the latest head
+// of the branch logs are written to and is only recorded if nothing was pushed in the
+// meantime, so an interrupted run can be started again to finish. With dryRun
+// it only returns the moves it would make. progress, if set, is told after
+// each commit how many of the files have been moved.
+func (g *GitHubStorageProvider) MigrateLayout(layout string, batchSize int, dryRun bool, progress func(moved, total int)) ([]LayoutMove, error) {
+	if err := storage.ValidateLayout(layout); err != nil {
+		return nil, err
+	}
+	if layout == "" {
+		layout = storage.LayoutMonthly
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultLayoutBatchSize
+	}
+
+	ref, err := g.headRef(!dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := []LayoutMove{}
+	total := -1
+	for {
+		head, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, ref)
+		if err != nil {
+			return moved, layoutError("failed to get "+ref, err)
+		}
+		parent, _, err := g.client.Git.GetCommit(g.ctx, g.owner, g.repo, head.GetObject().GetSHA())
+		if err != nil {
+			return moved, layoutError("failed to get "+ref, err)
+		}
+		tree, _, err := g.client.Git.GetTree(g.ctx, g.owner, g.repo, parent.GetTree().GetSHA(), true)
+		if err != nil {
+			return moved, layoutError("failed to list repository files", err)
+		}
+		if tree.GetTruncated() {
+			return moved, layoutError("the repository has too many files to list in one request", nil)
+		}
+
+		pending, err := g.pendingLayoutMoves(tree, layout)
+		if err != nil {
+			return moved, err
+		}
+		if total < 0 {
+			total = len(pending)
+		}
+		if dryRun {
+			for _, move := range pending {
+				moved = append(moved, move.LayoutMove)
+			}
+			return moved, nil
+		}
+		if len(pending) == 0 {
+			return moved, nil
+		}
+
+		batch := pending[:min(batchSize, len(pending))]
+		var entries []*github.TreeEntry
+		for _, move := range batch {
+			entries = append(entries,
+				&github.TreeEntry{Path: github.String(move.To), Mode: github.String(move.mode), Type: github.String("blob"), SHA: github.String(move.sha)},
+				// A tree entry without a SHA or content deletes the file
+				&github.TreeEntry{Path: github.String(move.From), Mode: github.String(move.mode), Type: github.String("blob")},
+			)
+		}
+
+		newTree, _, err := g.client.Git.CreateTree(g.ctx, g.owner, g.repo, parent.GetTree().GetSHA(), entries)
+		if err != nil {
+			return moved, layoutError("failed to create tree", err)
+		}
+		commit, err := g.createCommit(MigrateLayoutCommitMessage(len(batch), layout), newTree.GetSHA(), parent.GetSHA())
+		if err != nil {
+			return moved, layoutError("failed to create commit", err)
+		}
+		head.Object.SHA = commit.SHA
+		if _, _, err := g.client.Git.UpdateRef(g.ctx, g.owner, g.repo, head, false); err != nil {
+			if isConflict(err) {
+				return moved, layoutError(ref+" changed while moving files; run again to finish", err)
+			}
+			return moved, layoutError("failed to update "+ref, err)
+		}
+
+		for _, move := range batch {
+			moved = append(moved, move.LayoutMove)
+		}
+		g.afterWrite()
+		if progress != nil {
+			progress(len(moved), total)
+		}
+	}
+}
+
+// pendingLayoutMoves lists the day files in tree that are not where layout
+// puts them, in path order
+func (g *GitHubStorageProvider) pendingLayoutMoves(tree *github.Tree, layout string) ([]layoutMove, error) {
+	root := path.Clean(g.basePath) + "/"
+
+	existing := make(map[string]bool)
+	for _, entry := range tree.Entries {
+		existing[entry.GetPath()] = true
+	}
+
+	var pending []layoutMove
+	for _, entry := range tree.Entries {
+		filePath := entry.GetPath()
+		if entry.GetType() != "blob" || !strings.HasPrefix(filePath, root) {
+			continue
+		}
+		name := path.Base(filePath)
+		date, ok := storage.ParseDayFileName(name)
+		if !ok {
+			continue
+		}
+
+		target := path.Join(root, storage.DayDir(date, layout), name)
+		if target == filePath {
+			continue
+		}
+		if existing[target] {
+			return nil, storage.StorageError{
+				Operation: "MigrateLayout",
+				Message:   fmt.Sprintf("both %s and %s exist; remove one before migrating", filePath, target),
+			}
+		}
+		pending = append(pending, layoutMove{
+			LayoutMove: LayoutMove{From: filePath, To: target},
+			sha:        entry.GetSHA(),
+			mode:       entry.GetMode(),
+		})
+	}
+
+	sort.Slice(pending, func(i, j int) bool { return pending[i].From < pending[j].From })
+	return pending, nil
+}
+
+// MigrateLayoutCommitMessage returns the commit message used when moving day files
+func MigrateLayoutCommitMessage(files int, layout string) string {
+	return fmt.Sprintf("Move %d daily logs to the %s layout", files, layout)
+}
+
+func layoutError(message string, err error) error {
+	return storage.StorageError{Operation: "MigrateLayout", Message: message, Cause: err}
+}
@@ -0,0 +1,95 @@
+package providers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// PublishFiles replaces the contents of branch with files, keyed by path, in
+// a single commit with the configured identity and signature. A branch that
+// does not exist yet is created without history, as GitHub Pages branches
+// usually are. It returns false without committing when the branch already
+// holds exactly these files.
+func (g *GitHubStorageProvider) PublishFiles(branch string, files map[string][]byte, message string) (bool, error) {
+	if branch == "" {
+		return false, storage.ValidationError{Field: "branch", Message: "is required"}
+	}
+
+	var parents []string
+	var parentTree string
+	head, _, err := g.client.Git.GetRef(g.ctx, g.owner, g.repo, "heads/"+branch)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		return false, publishError("failed to get branch "+branch, err)
+	}
+	if err == nil {
+		parent, _, err := g.client.Git.GetCommit(g.ctx, g.owner, g.repo, head.GetObject().GetSHA())
+		if err != nil {
+			return false, publishError("failed to get branch "+branch, err)
+		}
+		parents = append(parents, parent.GetSHA())
+		parentTree = parent.GetTree().GetSHA()
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	entries := make([]*github.TreeEntry, 0, len(paths))
+	for _, path := range paths {
+		blob, _, err := g.client.Git.CreateBlob(g.ctx, g.owner, g.repo, &github.Blob{
+			Content:  github.String(base64.StdEncoding.EncodeToString(files[path])),
+			Encoding: github.String("base64"),
+		})
+		if err != nil {
+			return false, publishError("failed to upload "+path, err)
+		}
+		entries = append(entries, &github.TreeEntry{
+			Path: github.String(path),
+			Mode: github.String("100644"),
+			Type: github.String("blob"),
+			SHA:  blob.SHA,
+		})
+	}
+
+	// Without a base tree, files not in this tree are dropped from the branch
+	tree, _, err := g.client.Git.CreateTree(g.ctx, g.owner, g.repo, "", entries)
+	if err != nil {
+		return false, publishError("failed to create tree", err)
+	}
+	if tree.GetSHA() == parentTree {
+		return false, nil
+	}
+
+	commit, err := g.createCommit(message, tree.GetSHA(), parents...)
+	if err != nil {
+		return false, publishError("failed to create commit", err)
+	}
+	if head == nil {
+		_, _, err = g.client.Git.CreateRef(g.ctx, g.owner, g.repo, &github.Reference{
+			Ref:    github.String("refs/heads/" + branch),
+			Object: &github.GitObject{SHA: commit.SHA},
+		})
+	} else {
+		head.Object.SHA = commit.SHA
+		_, _, err = g.client.Git.UpdateRef(g.ctx, g.owner, g.repo, head, false)
+	}
+	if err != nil {
+		if isConflict(err) {
+			return false, publishError(fmt.Sprintf("branch %s changed while publishing; run it again", branch), err)
+		}
+		return false, publishError("failed to update branch "+branch, err)
+	}
+	return true, nil
+}
+
+func publishError(message string, cause error) error {
+	return storage.StorageError{Operation: "PublishFiles", Message: message, Cause: cause}
+}
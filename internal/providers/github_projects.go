@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v57/github"
+
+	"dailylog/internal/storage"
+)
+
+// projectsFileName is the file under the base path holding the project list
+const projectsFileName = "projects.json"
+
+// GetProjects reads the project list; it is empty until a project is created
+func (g *GitHubStorageProvider) GetProjects() (*storage.ProjectList, error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref := g.readRef(); ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+	filePath := path.Join(g.basePath, projectsFileName)
+	fileContent, _, _, err := g.client.Repositories.GetContents(g.ctx, g.owner, g.repo, filePath, opts)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return &storage.ProjectList{Projects: []storage.Project{}}, nil
+		}
+		return nil, projectsError("GetProjects", "failed to get "+filePath, err)
+	}
+
+	content, err := fileContent.GetContent()
+	if err != nil {
+		return nil, projectsError("GetProjects", "failed to decode "+filePath, err)
+	}
+	list := &storage.ProjectList{}
+	if err := json.Unmarshal([]byte(content), list); err != nil {
+		return nil, projectsError("GetProjects", "failed to parse "+filePath, err)
+	}
+	if list.Projects == nil {
+		list.Projects = []storage.Project{}
+	}
+	list.Revision = fileContent.GetSHA()
+	return list, nil
+}
+
+// SaveProjects writes the project list, only over the revision it was read at
+func (g *GitHubStorageProvider) SaveProjects(list *storage.ProjectList) error {
+	branch, err := g.writeBranch()
+	if err != nil {
+		return err
+	}
+	content, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return projectsError("SaveProjects", "failed to serialize projects", err)
+	}
+
+	filePath := path.Join(g.basePath, projectsFileName)
+	revision, err := g.writeFile(filePath, append(content, '\n'), list.Revision, "Update projects", branch)
+	if err != nil {
+		if isConflict(err) {
+			return projectsError("SaveProjects", "projects changed while saving; try again", err)
+		}
+		return projectsError("SaveProjects", fmt.Sprintf("failed to save %s", filePath), err)
+	}
+	list.Revision = revision
+	g.afterWrite()
+	return nil
+}
+
+func projectsError(operation, message string, err error) error {
+	return storage.StorageError{Operation: operation, Message: message, Cause: err}
+}
@@ -3,31 +3,54 @@ package providers
 import (
 	"context"
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/go-github/v57/github"
-	"golang.org/x/oauth2"
 
 	"dailylog/internal/storage"
+	"dailylog/internal/tracing"
 )
 
 // GitHubStorageProvider implements DailyLogStorage using GitHub as the backend
 type GitHubStorageProvider struct {
-	client   *github.Client
-	ctx      context.Context
-	repo     string
-	owner    string
-	basePath string
-	token    string
+	dayOperations
+
+	client    *github.Client
+	ctx       context.Context
+	repo      string
+	owner     string
+	basePath  string
+	dayFormat string
+	layout    string
+	token     string
+	commit    storage.CommitConfig
+	messages  *storage.CommitMessages
+
+	// branch, if set, is written to instead of the default branch; with
+	// autoPR a pull request into the default branch is kept open for it
+	branch string
+	autoPR bool
+	state  *branchState
+
+	// OnPullRequestOpened, if set, is told about pull requests opened in auto-PR mode
+	OnPullRequestOpened func(url string)
+
+	// OnPullRequestError, if set, is told when auto-PR mode fails to open a
+	// pull request; the write itself has succeeded
+	OnPullRequestError func(err error)
 }
 
 // NewGitHubStorageProvider creates a new GitHub storage provider
 func NewGitHubStorageProvider(config storage.Config) (*GitHubStorageProvider, error) {
-	if config.GitHubToken == "" {
+	if config.GitHubToken == "" && !Replaying() {
 		return nil, fmt.Errorf("GitHub token is required")
 	}
 	if config.GitHubRepo == "" {
@@ -41,53 +64,86 @@ func NewGitHubStorageProvider(config storage.Config) (*GitHubStorageProvider, er
 	}
 	owner, repo := parts[0], parts[1]
 
-	// Create OAuth2 token source
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: config.GitHubToken})
-	tc := oauth2.NewClient(context.Background(), ts)
-
 	// Create GitHub client
-	client := github.NewClient(tc)
+	client, err := NewGitHubClient(config.GitHubToken, config.GitHubBaseURL, config.GitHubUploadURL)
+	if err != nil {
+		return nil, err
+	}
 
 	basePath := config.GitHubPath
 	if basePath == "" {
 		basePath = "daily-logs"
 	}
+	if err := storage.ValidateDayFormat(config.DayFormat); err != nil {
+		return nil, err
+	}
+	dayFormat := config.DayFormat
+	if dayFormat == "" {
+		dayFormat = storage.DayFormatPretty
+	}
+	if err := storage.ValidateLayout(config.Layout); err != nil {
+		return nil, err
+	}
+	layout := config.Layout
+	if layout == "" {
+		layout = storage.LayoutMonthly
+	}
+	if err := config.Commit.Validate(); err != nil {
+		return nil, err
+	}
+	messages, err := storage.NewCommitMessages(config.Commit)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GitHubStorageProvider{
+		client:    client,
+		ctx:       context.Background(),
+		repo:      repo,
+		owner:     owner,
+		basePath:  basePath,
+		dayFormat: dayFormat,
+		layout:    layout,
+		token:     config.GitHubToken,
+		branch:    config.GitHubBranch,
+		autoPR:    config.GitHubAutoPR,
+		commit:    config.Commit,
+		messages:  messages,
+		state:     &branchState{},
+	}
+	g.dayOperations = newDayOperations(g, config)
+	return g, nil
+}
 
-	return &GitHubStorageProvider{
-		client:   client,
-		ctx:      context.Background(),
-		repo:     repo,
-		owner:    owner,
-		basePath: basePath,
-		token:    config.GitHubToken,
-	}, nil
+// CommitMessages returns the templates commit messages are rendered from
+func (g *GitHubStorageProvider) CommitMessages() *storage.CommitMessages {
+	return g.messages
 }
 
-// GetDay retrieves a day's log from GitHub
-func (g *GitHubStorageProvider) GetDay(date time.Time) (*storage.DayLog, error) {
-	filePath := g.getDayFilePath(date)
+// GetDay retrieves a day's log from GitHub, in whichever format it is stored
+func (g *GitHubStorageProvider) GetDay(date time.Time) (_ *storage.DayLog, err error) {
+	g, span := g.startSpan("GetDay", dateAttribute(date))
+	defer func() { tracing.End(span, err) }()
 
-	fileContent, _, _, err := g.client.Repositories.GetContents(
-		g.ctx, g.owner, g.repo, filePath, nil,
-	)
+	fileContent, err := g.getDayFile(date, "")
 	if err != nil {
-		if strings.Contains(err.Error(), "404") {
-			// Create new day log if it doesn't exist
-			dayLog := &storage.DayLog{
-				Date:         date,
-				Entries:      []storage.DailyLogEntry{},
-				TotalEntries: 0,
-				CreatedAt:    time.Now(),
-				UpdatedAt:    time.Now(),
-			}
-			return dayLog, nil
-		}
 		return nil, storage.StorageError{
 			Operation: "GetDay",
 			Message:   fmt.Sprintf("failed to get day %s", date.Format("2006-01-02")),
 			Cause:     err,
 		}
 	}
+	if fileContent == nil {
+		// Create new day log if it doesn't exist
+		dayLog := &storage.DayLog{
+			Date:         date,
+			Entries:      []storage.DailyLogEntry{},
+			TotalEntries: 0,
+			CreatedAt:    time.Now(),
+			UpdatedAt:    time.Now(),
+		}
+		return dayLog, nil
+	}
 
 	// Decode the content
 	content, err := base64.StdEncoding.DecodeString(*fileContent.Content)
@@ -99,428 +155,300 @@ func (g *GitHubStorageProvider) GetDay(date time.Time) (*storage.DayLog, error)
 		}
 	}
 
-	var dayLog storage.DayLog
-	if err := json.Unmarshal(content, &dayLog); err != nil {
+	dayLog, format, err := storage.DecodeDay(content)
+	if err != nil {
 		return nil, storage.StorageError{
 			Operation: "GetDay",
 			Message:   "failed to parse day log JSON",
 			Cause:     err,
 		}
 	}
+	dayLog.Revision = fileContent.GetSHA()
+	dayLog.Format = format
 
-	return &dayLog, nil
+	return dayLog, nil
 }
 
-// SaveDay saves a day's log to GitHub
-func (g *GitHubStorageProvider) SaveDay(dayLog *storage.DayLog) error {
-	filePath := g.getDayFilePath(dayLog.Date)
+// maxSaveAttempts bounds how often SaveDay retries after merging a conflicting write
+const maxSaveAttempts = 3
 
-	// Convert to JSON
-	content, err := dayLog.ToJSON()
-	if err != nil {
-		return storage.StorageError{
-			Operation: "SaveDay",
-			Message:   "failed to serialize day log",
-			Cause:     err,
-		}
-	}
+// SaveDay saves a day's log to GitHub.
+// The write is conditional on the revision the day was read at; if another
+// client saved the day in the meantime, the two versions are merged at the
+// entry level and the save is retried. On success dayLog holds what was stored.
+// A day already stored with another file extension keeps its format, so it
+// stays one file; RecompressDays converts it.
+func (g *GitHubStorageProvider) SaveDay(dayLog *storage.DayLog) (err error) {
+	g, span := g.startSpan("SaveDay", dateAttribute(dayLog.Date))
+	defer func() { tracing.End(span, err) }()
 
-	// Check if file exists to get SHA
-	var sha *string
-	existingFile, _, _, err := g.client.Repositories.GetContents(
-		g.ctx, g.owner, g.repo, filePath, nil,
-	)
-	if err == nil && existingFile != nil {
-		sha = existingFile.SHA
+	branch, err := g.writeBranch()
+	if err != nil {
+		return err
 	}
 
-	// Create commit message
-	commitMessage := fmt.Sprintf("Update daily log for %s", dayLog.GetDateString())
-	if sha == nil {
-		commitMessage = fmt.Sprintf("Create daily log for %s", dayLog.GetDateString())
-	}
+	for attempt := 1; ; attempt++ {
+		format := g.dayFormat
+		if dayLog.Revision != "" &&
+			storage.DayFileName(dayLog.Date, dayLog.Format) != storage.DayFileName(dayLog.Date, format) {
+			format = dayLog.Format
+		}
+		filePath := g.getDayFilePath(dayLog.Date, format)
 
-	// Create or update the file
-	_, _, err = g.client.Repositories.CreateFile(
-		g.ctx, g.owner, g.repo, filePath,
-		&github.RepositoryContentFileOptions{
-			Message: &commitMessage,
-			Content: content,
-			SHA:     sha,
-		},
-	)
+		content, err := storage.EncodeDay(dayLog, format)
+		if err != nil {
+			return storage.StorageError{
+				Operation: "SaveDay",
+				Message:   "failed to serialize day log",
+				Cause:     err,
+			}
+		}
 
-	if err != nil {
-		return storage.StorageError{
-			Operation: "SaveDay",
-			Message:   fmt.Sprintf("failed to save day %s", dayLog.GetDateString()),
-			Cause:     err,
+		commitMessage, err := g.messages.Save(dayLog)
+		if err != nil {
+			return storage.StorageError{
+				Operation: "SaveDay",
+				Message:   "failed to render commit message",
+				Cause:     err,
+			}
 		}
-	}
 
-	return nil
-}
+		// Only overwrite the revision we read; an empty one means we expect to create the file
+		revision, err := g.writeFile(filePath, content, dayLog.Revision, commitMessage, branch)
+		if err == nil {
+			dayLog.Revision = revision
+			dayLog.Format = format
+			g.afterWrite()
+			return nil
+		}
 
-// DeleteDay deletes a day's log from GitHub
-func (g *GitHubStorageProvider) DeleteDay(date time.Time) error {
-	filePath := g.getDayFilePath(date)
+		if !isConflict(err) || attempt >= maxSaveAttempts {
+			return storage.StorageError{
+				Operation: "SaveDay",
+				Message:   fmt.Sprintf("failed to save day %s", dayLog.GetDateString()),
+				Cause:     err,
+			}
+		}
 
-	// Get the file to obtain its SHA
-	fileContent, _, _, err := g.client.Repositories.GetContents(
-		g.ctx, g.owner, g.repo, filePath, nil,
-	)
-	if err != nil {
-		return storage.NotFoundError{
-			Resource: "day log",
-			ID:       date.Format("2006-01-02"),
+		merged, err := g.mergeWithRemote(dayLog)
+		if err != nil {
+			return err
 		}
+		*dayLog = *merged
+	}
+}
+
+// writeFile creates or updates filePath at revision and returns its new blob
+// SHA. Commits are made through the contents API unless they are signed.
+func (g *GitHubStorageProvider) writeFile(filePath string, content []byte, revision, message string, branch *string) (string, error) {
+	if g.commit.SigningKey != "" {
+		return g.commitFile(filePath, content, revision, message)
 	}
 
-	// Delete the file
-	commitMessage := fmt.Sprintf("Delete daily log for %s", date.Format("2006-01-02"))
-	_, _, err = g.client.Repositories.DeleteFile(
+	var sha *string
+	if revision != "" {
+		sha = github.String(revision)
+	}
+	author, committer := g.commitIdentity()
+	resp, _, err := g.client.Repositories.CreateFile(
 		g.ctx, g.owner, g.repo, filePath,
 		&github.RepositoryContentFileOptions{
-			Message: &commitMessage,
-			SHA:     fileContent.SHA,
+			Message:   &message,
+			Content:   content,
+			SHA:       sha,
+			Branch:    branch,
+			Author:    author,
+			Committer: committer,
 		},
 	)
-
 	if err != nil {
-		return storage.StorageError{
-			Operation: "DeleteDay",
-			Message:   fmt.Sprintf("failed to delete day %s", date.Format("2006-01-02")),
-			Cause:     err,
-		}
+		return "", err
 	}
-
-	return nil
+	return resp.GetContent().GetSHA(), nil
 }
 
-// CreateEntry creates a new log entry for a specific day
-func (g *GitHubStorageProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
-	// Get the day log
-	dayLog, err := g.GetDay(req.Date)
+// mergeWithRemote merges local changes with the currently stored version of the day
+func (g *GitHubStorageProvider) mergeWithRemote(local *storage.DayLog) (*storage.DayLog, error) {
+	remote, err := g.GetDay(local.Date)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create new entry with ID
-	entry := storage.DailyLogEntry{
-		ID:          g.generateEntryID(),
-		Timestamp:   req.Date,
-		Type:        req.Type,
-		Title:       req.Title,
-		Description: req.Description,
-		Tags:        req.Tags,
-		Location:    req.Location,
-		Metadata:    req.Metadata,
-	}
-
-	if req.Status != nil {
-		entry.Status = *req.Status
-	}
-	if req.Priority != nil {
-		entry.Priority = *req.Priority
-	}
-	if req.Duration != nil {
-		entry.Duration = req.Duration
-	}
-
-	// Add entry to day log
-	dayLog.AddEntry(entry)
-
-	// Save the updated day log
-	if err := g.SaveDay(dayLog); err != nil {
-		return nil, err
+	var base *storage.DayLog
+	if local.Revision != "" {
+		raw, _, err := g.client.Git.GetBlobRaw(g.ctx, g.owner, g.repo, local.Revision)
+		if err != nil {
+			return nil, storage.StorageError{
+				Operation: "SaveDay",
+				Message:   fmt.Sprintf("failed to fetch base revision of %s for merge", local.GetDateString()),
+				Cause:     err,
+			}
+		}
+		if base, _, err = storage.DecodeDay(raw); err != nil {
+			return nil, storage.StorageError{
+				Operation: "SaveDay",
+				Message:   "failed to parse base revision for merge",
+				Cause:     err,
+			}
+		}
 	}
 
-	return &entry, nil
+	merged := storage.MergeDayLogs(base, local, remote)
+	merged.Revision = remote.Revision
+	merged.Format = remote.Format
+	return merged, nil
 }
 
-// UpdateEntry updates an existing log entry
-func (g *GitHubStorageProvider) UpdateEntry(req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
-	// Find the day that contains this entry
-	// For now, we'll need to search recent days or require the date to be provided
-	// This is a limitation of the current design - we should include date in the request
-	return nil, fmt.Errorf("UpdateEntry not implemented - requires date in request")
-}
+// DeleteDay deletes a day's log from GitHub
+func (g *GitHubStorageProvider) DeleteDay(date time.Time) (err error) {
+	g, span := g.startSpan("DeleteDay", dateAttribute(date))
+	defer func() { tracing.End(span, err) }()
 
-// DeleteEntry deletes a log entry from a specific day
-func (g *GitHubStorageProvider) DeleteEntry(id string, date time.Time) error {
-	// Get the day log
-	dayLog, err := g.GetDay(date)
+	branch, err := g.writeBranch()
 	if err != nil {
 		return err
 	}
 
-	// Remove the entry
-	if !dayLog.RemoveEntry(id) {
+	// Get the file to obtain its path and SHA
+	fileContent, err := g.getDayFile(date, "")
+	if err != nil || fileContent == nil {
 		return storage.NotFoundError{
-			Resource: "log entry",
-			ID:       id,
+			Resource: "day log",
+			ID:       date.Format("2006-01-02"),
 		}
 	}
 
-	// Save the updated day log
-	return g.SaveDay(dayLog)
-}
-
-// GetEntry retrieves a specific log entry from a day
-func (g *GitHubStorageProvider) GetEntry(id string, date time.Time) (*storage.DailyLogEntry, error) {
-	dayLog, err := g.GetDay(date)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, entry := range dayLog.Entries {
-		if entry.ID == id {
-			return &entry, nil
+	// The message describes the day being deleted
+	dayLog := &storage.DayLog{Date: date}
+	if content, err := fileContent.GetContent(); err == nil {
+		if stored, _, err := storage.DecodeDay([]byte(content)); err == nil {
+			dayLog = stored
 		}
 	}
-
-	return nil, storage.NotFoundError{
-		Resource: "log entry",
-		ID:       id,
-	}
-}
-
-// SearchLogs searches through logs based on criteria
-func (g *GitHubStorageProvider) SearchLogs(req storage.LogSearchRequest) (*storage.LogSearchResponse, error) {
-	// This is a simplified implementation - in reality, we'd need to iterate through files
-	// or maintain an index for efficient searching
-	response := &storage.LogSearchResponse{
-		Entries:     []storage.DailyLogEntry{},
-		TotalCount:  0,
-		SearchQuery: req,
+	commitMessage, err := g.messages.Delete(dayLog)
+	if err != nil {
+		return storage.StorageError{
+			Operation: "DeleteDay",
+			Message:   "failed to render commit message",
+			Cause:     err,
+		}
 	}
 
-	// For now, search within a reasonable date range
-	startDate := time.Now().AddDate(0, -3, 0) // Last 3 months
-	endDate := time.Now()
-
-	if req.DateStart != nil {
-		startDate = *req.DateStart
-	}
-	if req.DateEnd != nil {
-		endDate = *req.DateEnd
+	// Delete the file
+	if g.commit.SigningKey != "" {
+		_, err = g.commitFile(fileContent.GetPath(), nil, fileContent.GetSHA(), commitMessage)
+	} else {
+		author, committer := g.commitIdentity()
+		_, _, err = g.client.Repositories.DeleteFile(
+			g.ctx, g.owner, g.repo, fileContent.GetPath(),
+			&github.RepositoryContentFileOptions{
+				Message:   &commitMessage,
+				SHA:       fileContent.SHA,
+				Branch:    branch,
+				Author:    author,
+				Committer: committer,
+			},
+		)
 	}
 
-	// Iterate through date range
-	for d := startDate; d.Before(endDate) || d.Equal(endDate); d = d.AddDate(0, 0, 1) {
-		dayLog, err := g.GetDay(d)
-		if err != nil {
-			continue // Skip days that don't exist or have errors
-		}
-
-		// Filter entries based on search criteria
-		for _, entry := range dayLog.Entries {
-			if g.matchesSearchCriteria(entry, req) {
-				response.Entries = append(response.Entries, entry)
-				response.TotalCount++
-
-				// Respect limit
-				if req.Limit > 0 && response.TotalCount >= req.Limit {
-					return response, nil
-				}
-			}
+	if err != nil {
+		return storage.StorageError{
+			Operation: "DeleteDay",
+			Message:   fmt.Sprintf("failed to delete day %s", date.Format("2006-01-02")),
+			Cause:     err,
 		}
 	}
 
-	return response, nil
+	g.afterWrite()
+	return nil
 }
 
-// GetDateRange retrieves all day logs within a date range
-func (g *GitHubStorageProvider) GetDateRange(start, end time.Time) ([]storage.DayLog, error) {
-	var dayLogs []storage.DayLog
+// ListDays lists all available days within a date range.
+// A zero start or end leaves that side of the range open.
+func (g *GitHubStorageProvider) ListDays(start, end time.Time) (_ []time.Time, err error) {
+	g, span := g.startSpan("ListDays")
+	defer func() { tracing.End(span, err) }()
 
-	for d := start; d.Before(end) || d.Equal(end); d = d.AddDate(0, 0, 1) {
-		dayLog, err := g.GetDay(d)
-		if err != nil {
-			continue // Skip days that don't exist
-		}
-		if len(dayLog.Entries) > 0 {
-			dayLogs = append(dayLogs, *dayLog)
+	var dates []time.Time
+	seen := make(map[time.Time]bool)
+	addDays := func(files []*github.RepositoryContent) {
+		for _, file := range files {
+			// A day may briefly be stored in two formats while it is recompressed
+			day, ok := storage.ParseDayFileName(file.GetName())
+			if file.GetType() != "file" || !ok || seen[day] {
+				continue
+			}
+			if (!start.IsZero() && day.Before(dateOnly(start))) || (!end.IsZero() && day.After(dateOnly(end))) {
+				continue
+			}
+			seen[day] = true
+			dates = append(dates, day)
 		}
 	}
 
-	return dayLogs, nil
-}
-
-// GetWeek retrieves a week's worth of logs
-func (g *GitHubStorageProvider) GetWeek(date time.Time) (*storage.WeeklyLog, error) {
-	// Calculate week start (Monday)
-	weekday := int(date.Weekday())
-	if weekday == 0 {
-		weekday = 7 // Sunday = 7
-	}
-	weekStart := date.AddDate(0, 0, -(weekday - 1))
-	weekEnd := weekStart.AddDate(0, 0, 6)
-
-	days, err := g.GetDateRange(weekStart, weekEnd)
-	if err != nil {
-		return nil, err
-	}
-
-	weeklyLog := &storage.WeeklyLog{
-		WeekStart: weekStart,
-		WeekEnd:   weekEnd,
-		Days:      days,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Calculate total entries
-	for _, day := range days {
-		weeklyLog.TotalEntries += day.TotalEntries
-	}
-
-	return weeklyLog, nil
-}
-
-// GetMonth retrieves a month's worth of logs
-func (g *GitHubStorageProvider) GetMonth(year int, month int) (*storage.MonthlyLog, error) {
-	monthStart := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	monthEnd := monthStart.AddDate(0, 1, -1)
-
-	days, err := g.GetDateRange(monthStart, monthEnd)
+	// Walk the directory layout rather than probing every day
+	top, err := g.listDir(g.basePath)
 	if err != nil {
 		return nil, err
 	}
-
-	monthlyLog := &storage.MonthlyLog{
-		Month:     fmt.Sprintf("%04d-%02d", year, month),
-		Year:      year,
-		Days:      days,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-	// Calculate total entries
-	for _, day := range days {
-		monthlyLog.TotalEntries += day.TotalEntries
+	if g.layout == storage.LayoutFlat {
+		addDays(top)
 	}
 
-	return monthlyLog, nil
-}
-
-// GenerateSummary generates a summary for the given request
-func (g *GitHubStorageProvider) GenerateSummary(req storage.SummaryRequest) (*storage.SummaryResponse, error) {
-	// Basic implementation - this would integrate with AI in a real implementation
-	var summary string
-	var stats map[string]any
-
-	switch req.Type {
-	case "day":
-		dayLog, err := g.GetDay(req.Date)
-		if err != nil {
-			return nil, err
-		}
-		summary = g.generateDaySummary(dayLog)
-		stats = map[string]any{
-			"total_entries":  dayLog.TotalEntries,
-			"status_average": dayLog.StatusAverage,
+	for _, year := range top {
+		if year.GetType() != "dir" || g.layout == storage.LayoutFlat {
+			continue
 		}
-
-	case "week":
-		weekLog, err := g.GetWeek(req.Date)
+		yearNum, err := strconv.Atoi(year.GetName())
 		if err != nil {
-			return nil, err
+			continue
+		}
+		// An ISO week year can start in December and end in January
+		firstYear, lastYear := yearNum, yearNum
+		if g.layout == storage.LayoutWeekly {
+			firstYear, lastYear = yearNum-1, yearNum+1
 		}
-		summary = g.generateWeekSummary(weekLog)
-		stats = map[string]any{
-			"total_entries": weekLog.TotalEntries,
-			"total_days":    len(weekLog.Days),
+		if (!start.IsZero() && lastYear < start.Year()) || (!end.IsZero() && firstYear > end.Year()) {
+			continue
 		}
 
-	case "month":
-		monthLog, err := g.GetMonth(req.Date.Year(), int(req.Date.Month()))
+		periods, err := g.listDir(year.GetPath())
 		if err != nil {
 			return nil, err
 		}
-		summary = g.generateMonthSummary(monthLog)
-		stats = map[string]any{
-			"total_entries": monthLog.TotalEntries,
-			"total_days":    len(monthLog.Days),
+		if g.layout == storage.LayoutYearly {
+			addDays(periods)
+			continue
 		}
-	}
 
-	return &storage.SummaryResponse{
-		Summary:   summary,
-		Type:      req.Type,
-		Period:    req.Date.Format("2006-01-02"),
-		Stats:     stats,
-		CreatedAt: time.Now(),
-	}, nil
-}
+		for _, period := range periods {
+			if period.GetType() != "dir" {
+				continue
+			}
+			if g.layout == storage.LayoutMonthly {
+				monthStart, err := time.Parse("2006-01", year.GetName()+"-"+period.GetName())
+				if err != nil {
+					continue
+				}
+				if (!start.IsZero() && monthStart.AddDate(0, 1, 0).Before(dateOnly(start))) ||
+					(!end.IsZero() && monthStart.After(dateOnly(end))) {
+					continue
+				}
+			}
 
-// SaveSummary saves a summary to the appropriate location
-func (g *GitHubStorageProvider) SaveSummary(summary *storage.SummaryResponse, targetType string, date time.Time) error {
-	// Save summary as metadata in the day/week/month file
-	switch targetType {
-	case "day":
-		dayLog, err := g.GetDay(date)
-		if err != nil {
-			return err
+			files, err := g.listDir(period.GetPath())
+			if err != nil {
+				return nil, err
+			}
+			addDays(files)
 		}
-		dayLog.DaySummary = summary.Summary
-		return g.SaveDay(dayLog)
 	}
 
-	return nil
-}
-
-// ListDays lists all available days within a date range
-func (g *GitHubStorageProvider) ListDays(start, end time.Time) ([]time.Time, error) {
-	var dates []time.Time
-
-	// List files in the repository to find existing days
-	// This is a simplified implementation
-	for d := start; d.Before(end) || d.Equal(end); d = d.AddDate(0, 0, 1) {
-		_, _, _, err := g.client.Repositories.GetContents(
-			g.ctx, g.owner, g.repo, g.getDayFilePath(d), nil,
-		)
-		if err == nil {
-			dates = append(dates, d)
-		}
-	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
 
 	return dates, nil
 }
 
-// GetStats returns statistics for a date range
-func (g *GitHubStorageProvider) GetStats(start, end time.Time) (map[string]any, error) {
-	days, err := g.GetDateRange(start, end)
-	if err != nil {
-		return nil, err
-	}
-
-	totalEntries := 0
-	totalDays := len(days)
-	statusSum := 0.0
-	statusCount := 0
-
-	for _, day := range days {
-		totalEntries += day.TotalEntries
-		if day.StatusAverage > 0 {
-			statusSum += day.StatusAverage
-			statusCount++
-		}
-	}
-
-	avgStatus := 0.0
-	if statusCount > 0 {
-		avgStatus = statusSum / float64(statusCount)
-	}
-
-	return map[string]any{
-		"total_entries":   totalEntries,
-		"total_days":      totalDays,
-		"average_status":  avgStatus,
-		"entries_per_day": float64(totalEntries) / float64(totalDays),
-	}, nil
-}
-
 // Backup creates a backup of all data
 func (g *GitHubStorageProvider) Backup() error {
 	// GitHub itself is the backup - this could create a separate backup repo
@@ -543,74 +471,143 @@ func (g *GitHubStorageProvider) HealthCheck() error {
 
 // Helper methods
 
-func (g *GitHubStorageProvider) getDayFilePath(date time.Time) string {
-	return path.Join(g.basePath, date.Format("2006"), date.Format("01"), date.Format("2006-01-02.json"))
+func (g *GitHubStorageProvider) getDayFilePath(date time.Time, format string) string {
+	return path.Join(g.basePath, storage.DayDir(date, g.layout), storage.DayFileName(date, format))
 }
 
-func (g *GitHubStorageProvider) generateEntryID() string {
-	return fmt.Sprintf("entry_%d", time.Now().UnixNano())
-}
-
-func (g *GitHubStorageProvider) matchesSearchCriteria(entry storage.DailyLogEntry, req storage.LogSearchRequest) bool {
-	// Type filter
-	if req.Type != "" && entry.Type != req.Type {
-		return false
+// dayFilePaths returns the paths a day file may be stored at, the one for
+// the configured format first
+func (g *GitHubStorageProvider) dayFilePaths(date time.Time) []string {
+	other := storage.DayFormatGzip
+	if g.dayFormat == storage.DayFormatGzip {
+		other = storage.DayFormatPretty
 	}
+	return []string{g.getDayFilePath(date, g.dayFormat), g.getDayFilePath(date, other)}
+}
 
-	// Status range filter
-	if req.StatusMin != nil && entry.Status < *req.StatusMin {
-		return false
+// getDayFile fetches a day's file at ref (empty for the branch logs are
+// read from), looking for every format it may be stored in. It returns nil if
+// there is none.
+func (g *GitHubStorageProvider) getDayFile(date time.Time, ref string) (*github.RepositoryContent, error) {
+	if ref == "" {
+		ref = g.readRef()
 	}
-	if req.StatusMax != nil && entry.Status > *req.StatusMax {
-		return false
+	var opts *github.RepositoryContentGetOptions
+	if ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
 	}
-
-	// Text search in title and description
-	if req.SearchText != "" {
-		searchText := strings.ToLower(req.SearchText)
-		if !strings.Contains(strings.ToLower(entry.Title), searchText) &&
-			!strings.Contains(strings.ToLower(entry.Description), searchText) {
-			return false
+	for _, filePath := range g.dayFilePaths(date) {
+		fileContent, _, _, err := g.client.Repositories.GetContents(g.ctx, g.owner, g.repo, filePath, opts)
+		if err == nil {
+			return fileContent, nil
+		}
+		if !strings.Contains(err.Error(), "404") {
+			return nil, err
 		}
 	}
+	return nil, nil
+}
 
-	// Tag filter
-	if len(req.Tags) > 0 {
-		hasTag := false
-		for _, reqTag := range req.Tags {
-			for _, entryTag := range entry.Tags {
-				if entryTag == reqTag {
-					hasTag = true
-					break
-				}
-			}
-			if hasTag {
-				break
-			}
+// DayChanged reports whether a day's file changed since the GitHub ETag
+// etag (empty for none), returning its current ETag, which is empty while
+// the day has no file. Only the ETag is compared, so an unchanged day costs
+// a 304 response, which doesn't count against the rate limit.
+func (g *GitHubStorageProvider) DayChanged(date time.Time, etag string) (string, bool, error) {
+	query := url.Values{}
+	if ref := g.readRef(); ref != "" {
+		query.Set("ref", ref)
+	}
+	for _, filePath := range g.dayFilePaths(date) {
+		u := fmt.Sprintf("repos/%s/%s/contents/%s", g.owner, g.repo, (&url.URL{Path: filePath}).String())
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+		req, err := g.client.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return etag, false, err
+		}
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
 		}
-		if !hasTag {
-			return false
+		resp, err := g.client.Do(g.ctx, req, nil)
+		if resp != nil && resp.StatusCode == http.StatusNotModified {
+			return etag, false, nil
 		}
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return etag, false, storage.StorageError{
+				Operation: "DayChanged",
+				Message:   fmt.Sprintf("failed to check day %s", date.Format("2006-01-02")),
+				Cause:     err,
+			}
+		}
+		current := resp.Header.Get("ETag")
+		return current, current != etag, nil
 	}
-
-	return true
+	return "", etag != "", nil
 }
 
-func (g *GitHubStorageProvider) generateDaySummary(dayLog *storage.DayLog) string {
-	if len(dayLog.Entries) == 0 {
-		return "No activities recorded for this day."
+// isConflict reports whether a write failed because the file changed underneath us.
+// GitHub answers 409 for a stale SHA and 422 when a SHA is missing for an existing file.
+func isConflict(err error) bool {
+	if errors.Is(err, errRevisionChanged) {
+		return true
+	}
+	var ghErr *github.ErrorResponse
+	if errors.As(err, &ghErr) && ghErr.Response != nil {
+		return ghErr.Response.StatusCode == http.StatusConflict ||
+			ghErr.Response.StatusCode == http.StatusUnprocessableEntity
 	}
+	return false
+}
 
-	return fmt.Sprintf("Day had %d activities with an average status of %.1f",
-		dayLog.TotalEntries, dayLog.StatusAverage)
+// IsRateLimited reports whether err was caused by GitHub's primary or
+// secondary rate limit
+func IsRateLimited(err error) bool {
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateErr) || errors.As(err, &abuseErr)
 }
 
-func (g *GitHubStorageProvider) generateWeekSummary(weekLog *storage.WeeklyLog) string {
-	return fmt.Sprintf("Week had %d total activities across %d days",
-		weekLog.TotalEntries, len(weekLog.Days))
+// IsUnauthorized reports whether GitHub rejected the token, either because it
+// is invalid (401) or lacks access to the repository (403)
+func IsUnauthorized(err error) bool {
+	if IsRateLimited(err) {
+		return false
+	}
+	var ghErr *github.ErrorResponse
+	if errors.As(err, &ghErr) && ghErr.Response != nil {
+		return ghErr.Response.StatusCode == http.StatusUnauthorized ||
+			ghErr.Response.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// listDir lists a directory in the repository, treating a missing directory as empty
+func (g *GitHubStorageProvider) listDir(dirPath string) ([]*github.RepositoryContent, error) {
+	var opts *github.RepositoryContentGetOptions
+	if ref := g.readRef(); ref != "" {
+		opts = &github.RepositoryContentGetOptions{Ref: ref}
+	}
+	_, contents, _, err := g.client.Repositories.GetContents(
+		g.ctx, g.owner, g.repo, dirPath, opts,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil, nil
+		}
+		return nil, storage.StorageError{
+			Operation: "ListDays",
+			Message:   fmt.Sprintf("failed to list %s", dirPath),
+			Cause:     err,
+		}
+	}
+	return contents, nil
 }
 
-func (g *GitHubStorageProvider) generateMonthSummary(monthLog *storage.MonthlyLog) string {
-	return fmt.Sprintf("Month had %d total activities across %d days",
-		monthLog.TotalEntries, len(monthLog.Days))
+// dateOnly truncates a time to midnight UTC, matching how day files are parsed
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
 }
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"dailylog/internal/storage"
+	"dailylog/internal/tracing"
+)
+
+// WithContext returns the provider making its GitHub API requests under
+// ctx, so they are traced as part of the request that made them
+func (g *GitHubStorageProvider) WithContext(ctx context.Context) storage.DailyLogStorage {
+	return g.bind(ctx)
+}
+
+// bind returns a copy of the provider making its requests under ctx. The
+// copy shares what the provider has learned about its branches.
+func (g *GitHubStorageProvider) bind(ctx context.Context) *GitHubStorageProvider {
+	bound := *g
+	bound.ctx = ctx
+	bound.dayOperations.days = &bound
+	return &bound
+}
+
+// startSpan starts a span for a storage operation, returning the provider
+// to carry it out with, whose GitHub API requests are traced under the span
+func (g *GitHubStorageProvider) startSpan(operation string, attrs ...attribute.KeyValue) (*GitHubStorageProvider, trace.Span) {
+	attrs = append(attrs, attribute.String("dailylog.storage", storage.StorageTypeGitHub),
+		attribute.String("dailylog.github.repo", g.owner+"/"+g.repo))
+	ctx, span := tracing.Start(g.ctx, "storage."+operation, trace.SpanKindInternal, attrs...)
+	if !span.IsRecording() {
+		return g, span
+	}
+	return g.bind(ctx), span
+}
+
+// dateAttribute records the day a storage operation is for
+func dateAttribute(date time.Time) attribute.KeyValue {
+	return attribute.String("dailylog.date", date.Format("2006-01-02"))
+}
@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"dailylog/internal/storage"
+)
+
+func TestGitHubSpansUnderRequest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	// The request reaches GitHub through the wrappers the MCP server uses
+	ctx, request := provider.Tracer("test").Start(context.Background(), "tools/call")
+	store := storage.WithContext(ctx, NewReadOnlyProvider(NewHookedProvider(newContentsStore(t), ValidationHook())))
+	if _, err := store.ListDays(time.Time{}, time.Time{}); err != nil {
+		t.Fatalf("ListDays: %v", err)
+	}
+	request.End()
+
+	var operation sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "storage.ListDays" {
+			operation = span
+		}
+	}
+	if operation == nil {
+		t.Fatal("no storage.ListDays span")
+	}
+	if operation.Parent().SpanID() != request.SpanContext().SpanID() {
+		t.Errorf("storage.ListDays is not under the request")
+	}
+	calls := 0
+	for _, span := range recorder.Ended() {
+		if span.Name() != "GitHub GET" {
+			continue
+		}
+		calls++
+		if span.Parent().SpanID() != operation.SpanContext().SpanID() {
+			t.Errorf("GitHub GET %v is not under storage.ListDays", span.Attributes())
+		}
+	}
+	if calls == 0 {
+		t.Error("no GitHub API request spans")
+	}
+}
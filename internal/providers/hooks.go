@@ -0,0 +1,281 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Middleware wraps storage to add behaviour around it, like the providers in
+// this package that take a backend
+type Middleware func(next storage.DailyLogStorage) storage.DailyLogStorage
+
+// Chain wraps backend in middleware, the first outermost
+func Chain(backend storage.DailyLogStorage, middleware ...Middleware) storage.DailyLogStorage {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		backend = middleware[i](backend)
+	}
+	return backend
+}
+
+// Hook runs around entry writes, so features such as validation, redaction,
+// webhooks, and tagging can be added without a provider of their own. Before
+// functions may change the request, or refuse the write by returning an
+// error; after functions see what was written. Unset functions are skipped.
+type Hook struct {
+	// Name identifies the hook in the errors it returns
+	Name string
+
+	BeforeCreate func(req *storage.CreateLogEntryRequest) error
+	AfterCreate  func(entry *storage.DailyLogEntry)
+	BeforeUpdate func(req *storage.UpdateLogEntryRequest) error
+	AfterUpdate  func(entry *storage.DailyLogEntry)
+	BeforeDelete func(id string, date time.Time) error
+	AfterDelete  func(id string, date time.Time)
+}
+
+// BeforeOnly returns the hook without its after functions, for previews
+// that write nothing for them to act on
+func (h Hook) BeforeOnly() Hook {
+	return Hook{Name: h.Name, BeforeCreate: h.BeforeCreate, BeforeUpdate: h.BeforeUpdate, BeforeDelete: h.BeforeDelete}
+}
+
+// HookedProvider runs hooks around the entry writes of the storage it
+// wraps: before functions in the order the hooks were added, and after
+// functions, once the write succeeded, in reverse order
+type HookedProvider struct {
+	storage.DailyLogStorage
+
+	hooks []Hook
+}
+
+// NewHookedProvider runs hooks around the entry writes of backend
+func NewHookedProvider(backend storage.DailyLogStorage, hooks ...Hook) *HookedProvider {
+	return &HookedProvider{DailyLogStorage: backend, hooks: hooks}
+}
+
+// Hooks is the middleware running hooks around entry writes
+func Hooks(hooks ...Hook) Middleware {
+	return func(next storage.DailyLogStorage) storage.DailyLogStorage {
+		return NewHookedProvider(next, hooks...)
+	}
+}
+
+// WithContext returns the provider running its hooks around writes to its
+// backend under ctx
+func (p *HookedProvider) WithContext(ctx context.Context) storage.DailyLogStorage {
+	return NewHookedProvider(storage.WithContext(ctx, p.DailyLogStorage), p.hooks...)
+}
+
+// Use adds hooks after those already added
+func (p *HookedProvider) Use(hooks ...Hook) {
+	p.hooks = append(p.hooks, hooks...)
+}
+
+// CreateEntry runs the create hooks around creating the entry
+func (p *HookedProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	if err := p.beforeCreate(&req); err != nil {
+		return nil, err
+	}
+	entry, err := p.DailyLogStorage.CreateEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	p.afterCreate(entry)
+	return entry, nil
+}
+
+// UpdateEntry runs the update hooks around updating the entry
+func (p *HookedProvider) UpdateEntry(req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	if err := p.beforeUpdate(&req); err != nil {
+		return nil, err
+	}
+	entry, err := p.DailyLogStorage.UpdateEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	p.afterUpdate(entry)
+	return entry, nil
+}
+
+// DeleteEntry runs the delete hooks around deleting the entry
+func (p *HookedProvider) DeleteEntry(id string, date time.Time) error {
+	if err := p.beforeDelete(id, date); err != nil {
+		return err
+	}
+	if err := p.DailyLogStorage.DeleteEntry(id, date); err != nil {
+		return err
+	}
+	p.afterDelete(id, date)
+	return nil
+}
+
+// SaveDay runs the hooks of the entries a whole day's save adds, changes,
+// and removes, as edit sessions save pins, reviews, splits, merges, and
+// moves. The stored day is read first to tell which those are.
+func (p *HookedProvider) SaveDay(dayLog *storage.DayLog) error {
+	stored, err := p.DailyLogStorage.GetDay(dayLog.Date)
+	if err != nil {
+		return err
+	}
+	previous := make(map[string]storage.DailyLogEntry, len(stored.Entries))
+	for _, entry := range stored.Entries {
+		previous[entry.ID] = entry
+	}
+
+	var created, updated []storage.DailyLogEntry
+	kept := make(map[string]bool, len(dayLog.Entries))
+	for _, entry := range dayLog.Entries {
+		kept[entry.ID] = true
+		old, ok := previous[entry.ID]
+		switch {
+		case !ok:
+			req := entry.CreateRequest()
+			if err := p.beforeCreate(&req); err != nil {
+				return err
+			}
+			created = append(created, entry.Recreate(req))
+		case !reflect.DeepEqual(old, entry):
+			req := storage.UpdateRequest(old, entry)
+			if err := p.beforeUpdate(&req); err != nil {
+				return err
+			}
+			updated = append(updated, req.Apply(entry))
+		}
+	}
+	var removed []string
+	for _, entry := range stored.Entries {
+		if !kept[entry.ID] {
+			if err := p.beforeDelete(entry.ID, dayLog.Date); err != nil {
+				return err
+			}
+			removed = append(removed, entry.ID)
+		}
+	}
+
+	// Keep what the before hooks changed
+	for _, entry := range append(created, updated...) {
+		dayLog.UpdateEntry(entry.ID, entry)
+	}
+	if err := p.DailyLogStorage.SaveDay(dayLog); err != nil {
+		return err
+	}
+	for i := range created {
+		p.afterCreate(&created[i])
+	}
+	for i := range updated {
+		p.afterUpdate(&updated[i])
+	}
+	for _, id := range removed {
+		p.afterDelete(id, dayLog.Date)
+	}
+	return nil
+}
+
+func (p *HookedProvider) beforeCreate(req *storage.CreateLogEntryRequest) error {
+	for _, hook := range p.hooks {
+		if hook.BeforeCreate != nil {
+			if err := hook.BeforeCreate(req); err != nil {
+				return hookError(hook, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *HookedProvider) beforeUpdate(req *storage.UpdateLogEntryRequest) error {
+	for _, hook := range p.hooks {
+		if hook.BeforeUpdate != nil {
+			if err := hook.BeforeUpdate(req); err != nil {
+				return hookError(hook, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *HookedProvider) beforeDelete(id string, date time.Time) error {
+	for _, hook := range p.hooks {
+		if hook.BeforeDelete != nil {
+			if err := hook.BeforeDelete(id, date); err != nil {
+				return hookError(hook, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (p *HookedProvider) afterCreate(entry *storage.DailyLogEntry) {
+	for i := len(p.hooks) - 1; i >= 0; i-- {
+		if p.hooks[i].AfterCreate != nil {
+			p.hooks[i].AfterCreate(entry)
+		}
+	}
+}
+
+func (p *HookedProvider) afterUpdate(entry *storage.DailyLogEntry) {
+	for i := len(p.hooks) - 1; i >= 0; i-- {
+		if p.hooks[i].AfterUpdate != nil {
+			p.hooks[i].AfterUpdate(entry)
+		}
+	}
+}
+
+func (p *HookedProvider) afterDelete(id string, date time.Time) {
+	for i := len(p.hooks) - 1; i >= 0; i-- {
+		if p.hooks[i].AfterDelete != nil {
+			p.hooks[i].AfterDelete(id, date)
+		}
+	}
+}
+
+// hookError names the hook that refused a write; validation errors are
+// returned as they are, so they still read as bad input
+func hookError(hook Hook, err error) error {
+	if _, ok := err.(storage.ValidationError); ok || hook.Name == "" {
+		return err
+	}
+	return fmt.Errorf("%s hook: %w", hook.Name, err)
+}
+
+// ValidationHook refuses entries with a status outside 1-10, a priority
+// outside 1-5, or an unknown visibility
+func ValidationHook() Hook {
+	return Hook{
+		Name: "validation",
+		BeforeCreate: func(req *storage.CreateLogEntryRequest) error {
+			return validateEntryFields(req.Status, req.Priority, req.Visibility)
+		},
+		BeforeUpdate: func(req *storage.UpdateLogEntryRequest) error {
+			return validateEntryFields(req.Status, req.Priority, req.Visibility)
+		},
+	}
+}
+
+// AuthorHook attributes new entries to author, unless they name their own,
+// so a team sharing one log can tell whose each entry is
+func AuthorHook(author string) Hook {
+	return Hook{
+		Name: "author",
+		BeforeCreate: func(req *storage.CreateLogEntryRequest) error {
+			if req.Author == "" {
+				req.Author = author
+			}
+			return nil
+		},
+	}
+}
+
+// validateEntryFields checks the ranged fields of a new or updated entry
+func validateEntryFields(status, priority *int, visibility string) error {
+	if status != nil && (*status < 1 || *status > 10) {
+		return storage.ValidationError{Field: "status", Message: "must be between 1 and 10"}
+	}
+	if priority != nil && (*priority < 1 || *priority > 5) {
+		return storage.ValidationError{Field: "priority", Message: "must be between 1 and 5"}
+	}
+	return storage.ValidateVisibility(visibility)
+}
@@ -0,0 +1,211 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// recordingHook records the entry events it sees
+type recordingHook struct {
+	events []string
+}
+
+func (r *recordingHook) hook() Hook {
+	return Hook{
+		Name:        "recorder",
+		AfterCreate: func(entry *storage.DailyLogEntry) { r.events = append(r.events, "create "+entry.Title) },
+		AfterUpdate: func(entry *storage.DailyLogEntry) { r.events = append(r.events, "update "+entry.Title) },
+		AfterDelete: func(id string, date time.Time) { r.events = append(r.events, "delete "+id) },
+	}
+}
+
+func newHookedMemory(t *testing.T, hooks ...Hook) (*HookedProvider, time.Time) {
+	t.Helper()
+	date := time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+	return NewHookedProvider(NewMemoryStorageProvider(storage.Config{}), hooks...), date
+}
+
+func TestHookedProviderCreateEntry(t *testing.T) {
+	recorder := &recordingHook{}
+	store, date := newHookedMemory(t, ValidationHook(), AuthorHook("alice"), recorder.hook())
+
+	entry, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Standup"})
+	if err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+	if entry.Author != "alice" {
+		t.Errorf("Author = %q, want alice", entry.Author)
+	}
+	if len(recorder.events) != 1 || recorder.events[0] != "create Standup" {
+		t.Errorf("events = %v, want [create Standup]", recorder.events)
+	}
+
+	status := 11
+	_, err = store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Bad", Status: &status})
+	var validation storage.ValidationError
+	if !errors.As(err, &validation) || validation.Field != "status" {
+		t.Errorf("CreateEntry with status 11: err = %v, want a status validation error", err)
+	}
+}
+
+func TestHookedProviderSessionEdits(t *testing.T) {
+	tests := []struct {
+		name   string
+		edit   func(*storage.DayEditSession, string) error
+		events func(id string) []string
+	}{
+		{
+			name: "update",
+			edit: func(s *storage.DayEditSession, id string) error {
+				pinned := true
+				_, err := s.Update(storage.UpdateLogEntryRequest{ID: id, Pinned: &pinned})
+				return err
+			},
+			events: func(string) []string { return []string{"update Write docs, review PR"} },
+		},
+		{
+			name: "split",
+			edit: func(s *storage.DayEditSession, id string) error {
+				_, err := s.Split(id, []string{"Write docs", "review PR"})
+				return err
+			},
+			events: func(string) []string { return []string{"create review PR", "update Write docs"} },
+		},
+		{
+			name:   "move",
+			edit:   func(s *storage.DayEditSession, id string) error { return s.Move(id, 1) },
+			events: func(string) []string { return nil },
+		},
+		{
+			name:   "remove",
+			edit:   func(s *storage.DayEditSession, id string) error { return s.Remove(id) },
+			events: func(id string) []string { return []string{"delete " + id} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingHook{}
+			store, date := newHookedMemory(t, ValidationHook(), AuthorHook("alice"), recorder.hook())
+			entry, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Write docs, review PR"})
+			if err != nil {
+				t.Fatalf("CreateEntry: %v", err)
+			}
+			if _, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date.Add(time.Hour), Type: "note", Title: "Lunch"}); err != nil {
+				t.Fatalf("CreateEntry: %v", err)
+			}
+			recorder.events = nil
+
+			session, err := storage.BeginDayEdit(store, date)
+			if err != nil {
+				t.Fatalf("BeginDayEdit: %v", err)
+			}
+			if err := tt.edit(session, entry.ID); err != nil {
+				t.Fatalf("edit: %v", err)
+			}
+			if err := session.Commit(); err != nil {
+				t.Fatalf("Commit: %v", err)
+			}
+
+			want := tt.events(entry.ID)
+			if len(recorder.events) != len(want) {
+				t.Fatalf("events = %v, want %v", recorder.events, want)
+			}
+			for i := range want {
+				if recorder.events[i] != want[i] {
+					t.Errorf("events = %v, want %v", recorder.events, want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestHookedProviderSaveDayRefused(t *testing.T) {
+	refuse := Hook{
+		Name:         "refuse",
+		BeforeUpdate: func(req *storage.UpdateLogEntryRequest) error { return errors.New("no edits") },
+	}
+	store, date := newHookedMemory(t, refuse)
+	entry, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Standup"})
+	if err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+
+	session, err := storage.BeginDayEdit(store, date)
+	if err != nil {
+		t.Fatalf("BeginDayEdit: %v", err)
+	}
+	if _, err := session.Update(storage.UpdateLogEntryRequest{ID: entry.ID, Title: "Retro"}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := session.Commit(); err == nil {
+		t.Fatal("Commit: want the hook's error")
+	}
+
+	day, err := store.GetDay(date)
+	if err != nil {
+		t.Fatalf("GetDay: %v", err)
+	}
+	if day.Entries[0].Title != "Standup" {
+		t.Errorf("Title = %q after a refused save, want Standup", day.Entries[0].Title)
+	}
+}
+
+func TestHookedProviderSaveDayKeepsBeforeChanges(t *testing.T) {
+	tag := Hook{
+		Name: "tag",
+		BeforeCreate: func(req *storage.CreateLogEntryRequest) error {
+			req.Tags = append(req.Tags, "imported")
+			return nil
+		},
+	}
+	store, date := newHookedMemory(t, AuthorHook("alice"), tag)
+
+	day, err := store.GetDay(date)
+	if err != nil {
+		t.Fatalf("GetDay: %v", err)
+	}
+	entry := storage.NewEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Standup"})
+	entry.Pinned = true
+	day.AddEntry(entry)
+	if err := store.SaveDay(day); err != nil {
+		t.Fatalf("SaveDay: %v", err)
+	}
+
+	day, err = store.GetDay(date)
+	if err != nil {
+		t.Fatalf("GetDay: %v", err)
+	}
+	saved := day.Entries[0]
+	if saved.ID != entry.ID || !saved.Pinned {
+		t.Errorf("saved %s pinned=%v, want %s pinned", saved.ID, saved.Pinned, entry.ID)
+	}
+	if saved.Author != "alice" || len(saved.Tags) != 1 || saved.Tags[0] != "imported" {
+		t.Errorf("saved author %q tags %v, want alice [imported]", saved.Author, saved.Tags)
+	}
+}
+
+func TestDayOperationsUpdateEntry(t *testing.T) {
+	store, date := newHookedMemory(t, ValidationHook())
+	entry, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Standup"})
+	if err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+
+	updated, err := store.UpdateEntry(storage.UpdateLogEntryRequest{ID: entry.ID, Title: "Retro"})
+	if err != nil {
+		t.Fatalf("UpdateEntry: %v", err)
+	}
+	if updated.Title != "Retro" {
+		t.Errorf("Title = %q, want Retro", updated.Title)
+	}
+
+	status := 0
+	if _, err := store.UpdateEntry(storage.UpdateLogEntryRequest{ID: entry.ID, Status: &status}); err == nil {
+		t.Error("UpdateEntry with status 0: want a validation error")
+	}
+}
@@ -0,0 +1,23 @@
+package providers
+
+import "dailylog/internal/storage"
+
+// IssueLinkProvider links new entries to the issue whose key their title
+// mentions, e.g. "Fixed ENG-123", recording its key and URL in metadata.
+// Entries that link an issue already are left alone.
+type IssueLinkProvider struct {
+	storage.DailyLogStorage
+
+	trackers storage.IssueTrackers
+}
+
+// NewIssueLinkProvider links entries to issues of trackers
+func NewIssueLinkProvider(backend storage.DailyLogStorage, trackers storage.IssueTrackers) *IssueLinkProvider {
+	return &IssueLinkProvider{DailyLogStorage: backend, trackers: trackers}
+}
+
+// CreateEntry links the entry to its issue and creates it
+func (p *IssueLinkProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	p.trackers.LinkRequest(&req)
+	return p.DailyLogStorage.CreateEntry(req)
+}
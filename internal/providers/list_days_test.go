@@ -0,0 +1,130 @@
+package providers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// tokyo is ahead of UTC, where a day's local times fall partly on the
+// previous UTC day
+var tokyo = time.FixedZone("JST", 9*60*60)
+
+// listDaysCases end ranges at a local time of day, as callers pass now or
+// the end of a week or month
+var listDaysCases = []struct {
+	name       string
+	start, end time.Time
+	want       []string
+}{
+	{
+		name:  "end in the morning east of UTC",
+		start: time.Date(2025, 3, 3, 0, 0, 0, 0, tokyo),
+		end:   time.Date(2025, 3, 4, 8, 0, 0, 0, tokyo),
+		want:  []string{"2025-03-03", "2025-03-04"},
+	},
+	{
+		name:  "end of the month east of UTC",
+		start: time.Date(2025, 3, 31, 0, 0, 0, 0, tokyo),
+		end:   time.Date(2025, 4, 1, 0, 0, 0, 0, tokyo).Add(-time.Nanosecond),
+		want:  []string{"2025-03-31"},
+	},
+	{
+		name:  "first of the month east of UTC",
+		start: time.Date(2025, 4, 1, 0, 0, 0, 0, tokyo),
+		end:   time.Date(2025, 4, 1, 7, 0, 0, 0, tokyo),
+		want:  []string{"2025-04-01"},
+	},
+	{
+		name: "open range",
+		want: []string{"2025-03-03", "2025-03-04", "2025-03-31", "2025-04-01"},
+	},
+}
+
+var listDaysStored = []string{"2025-03-03", "2025-03-04", "2025-03-31", "2025-04-01"}
+
+func formatDays(days []time.Time) string {
+	var formatted []string
+	for _, day := range days {
+		formatted = append(formatted, day.Format("2006-01-02"))
+	}
+	return strings.Join(formatted, ",")
+}
+
+func TestMemoryListDaysIncludesEndDay(t *testing.T) {
+	store := NewMemoryStorageProvider(storage.Config{})
+	for _, day := range listDaysStored {
+		date, _ := time.ParseInLocation("2006-01-02", day, tokyo)
+		if _, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date.Add(9 * time.Hour), Type: "note", Title: "x"}); err != nil {
+			t.Fatalf("CreateEntry: %v", err)
+		}
+	}
+
+	for _, tt := range listDaysCases {
+		t.Run(tt.name, func(t *testing.T) {
+			days, err := store.ListDays(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("ListDays: %v", err)
+			}
+			if got, want := formatDays(days), strings.Join(tt.want, ","); got != want {
+				t.Errorf("ListDays = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+// newContentsStore serves listDaysStored through the GitHub contents API,
+// over a monthly layout: logs/2025/03/2025-03-03.json
+func newContentsStore(t *testing.T) *GitHubStorageProvider {
+	t.Helper()
+	dirs := map[string][]map[string]string{
+		"logs":      {{"type": "dir", "name": "2025", "path": "logs/2025"}},
+		"logs/2025": {{"type": "dir", "name": "03", "path": "logs/2025/03"}, {"type": "dir", "name": "04", "path": "logs/2025/04"}},
+	}
+	for _, day := range listDaysStored {
+		dir := "logs/2025/" + day[5:7]
+		dirs[dir] = append(dirs[dir], map[string]string{"type": "file", "name": day + ".json", "path": dir + "/" + day + ".json"})
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/owner/repo/contents/")
+		contents, ok := dirs[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(contents)
+	}))
+	t.Cleanup(server.Close)
+
+	store, err := NewGitHubStorageProvider(storage.Config{
+		StorageType:   storage.StorageTypeGitHub,
+		GitHubRepo:    "owner/repo",
+		GitHubToken:   "token",
+		GitHubPath:    "logs",
+		GitHubBaseURL: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewGitHubStorageProvider: %v", err)
+	}
+	return store
+}
+
+func TestGitHubListDaysIncludesEndDay(t *testing.T) {
+	store := newContentsStore(t)
+	for _, tt := range listDaysCases {
+		t.Run(tt.name, func(t *testing.T) {
+			days, err := store.ListDays(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("ListDays: %v", err)
+			}
+			if got, want := formatDays(days), strings.Join(tt.want, ","); got != want {
+				t.Errorf("ListDays = %s, want %s", got, want)
+			}
+		})
+	}
+}
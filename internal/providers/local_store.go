@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// LocalDayStore keeps day logs as JSON files on the local filesystem, using the
+// same YYYY/MM/YYYY-MM-DD.json layout as the GitHub repository
+type LocalDayStore struct {
+	basePath string
+}
+
+// NewLocalDayStore creates a day store rooted at basePath
+func NewLocalDayStore(basePath string) (*LocalDayStore, error) {
+	if basePath == "" {
+		return nil, fmt.Errorf("local storage path is required")
+	}
+	if err := os.MkdirAll(basePath, 0o700); err != nil {
+		return nil, storage.StorageError{
+			Operation: "NewLocalDayStore",
+			Message:   fmt.Sprintf("failed to create %s", basePath),
+			Cause:     err,
+		}
+	}
+	return &LocalDayStore{basePath: basePath}, nil
+}
+
+// GetDay reads a day's log, returning an empty log if the day has no file
+func (l *LocalDayStore) GetDay(date time.Time) (*storage.DayLog, error) {
+	data, err := os.ReadFile(l.dayFilePath(date))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &storage.DayLog{
+				Date:      date,
+				Entries:   []storage.DailyLogEntry{},
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}, nil
+		}
+		return nil, storage.StorageError{
+			Operation: "GetDay",
+			Message:   fmt.Sprintf("failed to read day %s", date.Format("2006-01-02")),
+			Cause:     err,
+		}
+	}
+
+	var dayLog storage.DayLog
+	if err := dayLog.FromJSON(data); err != nil {
+		return nil, storage.StorageError{
+			Operation: "GetDay",
+			Message:   "failed to parse day log JSON",
+			Cause:     err,
+		}
+	}
+	return &dayLog, nil
+}
+
+// SaveDay writes a day's log, replacing the file atomically
+func (l *LocalDayStore) SaveDay(dayLog *storage.DayLog) error {
+	content, err := dayLog.ToJSON()
+	if err != nil {
+		return storage.StorageError{
+			Operation: "SaveDay",
+			Message:   "failed to serialize day log",
+			Cause:     err,
+		}
+	}
+
+	filePath := l.dayFilePath(dayLog.Date)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return storage.StorageError{
+			Operation: "SaveDay",
+			Message:   fmt.Sprintf("failed to create directory for %s", dayLog.GetDateString()),
+			Cause:     err,
+		}
+	}
+
+	tmpPath := filePath + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o600); err != nil {
+		return storage.StorageError{
+			Operation: "SaveDay",
+			Message:   fmt.Sprintf("failed to save day %s", dayLog.GetDateString()),
+			Cause:     err,
+		}
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return storage.StorageError{
+			Operation: "SaveDay",
+			Message:   fmt.Sprintf("failed to save day %s", dayLog.GetDateString()),
+			Cause:     err,
+		}
+	}
+	return nil
+}
+
+// DeleteDay removes a day's log
+func (l *LocalDayStore) DeleteDay(date time.Time) error {
+	if err := os.Remove(l.dayFilePath(date)); err != nil {
+		if os.IsNotExist(err) {
+			return storage.NotFoundError{
+				Resource: "day log",
+				ID:       date.Format("2006-01-02"),
+			}
+		}
+		return storage.StorageError{
+			Operation: "DeleteDay",
+			Message:   fmt.Sprintf("failed to delete day %s", date.Format("2006-01-02")),
+			Cause:     err,
+		}
+	}
+	return nil
+}
+
+// ListDays returns the days that have a log file within [start, end].
+// A zero start or end leaves that side of the range open.
+func (l *LocalDayStore) ListDays(start, end time.Time) ([]time.Time, error) {
+	matches, err := filepath.Glob(filepath.Join(l.basePath, "[0-9][0-9][0-9][0-9]", "[0-9][0-9]", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var dates []time.Time
+	for _, match := range matches {
+		date, err := time.Parse("2006-01-02.json", filepath.Base(match))
+		if err != nil {
+			continue
+		}
+		if (!start.IsZero() && date.Before(dateOnly(start))) || (!end.IsZero() && date.After(dateOnly(end))) {
+			continue
+		}
+		dates = append(dates, date)
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+func (l *LocalDayStore) dayFilePath(date time.Time) string {
+	return filepath.Join(l.basePath, date.Format("2006"), date.Format("01"), date.Format("2006-01-02.json"))
+}
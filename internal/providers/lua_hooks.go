@@ -0,0 +1,120 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// runLua runs the hook's Lua script for event. The script sees the input as
+// the table entry, and event and hook as strings. It fails by raising an
+// error or returning false and a reason. A before hook's changes to entry
+// are made to the request it was given.
+func (s ScriptHook) runLua(event string, input any) error {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout())
+	defer cancel()
+	L := lua.NewState()
+	defer L.Close()
+	L.SetContext(ctx)
+	L.SetGlobal("event", lua.LString(event))
+	L.SetGlobal("hook", lua.LString(s.Name))
+	L.SetGlobal("entry", toLua(L, value))
+
+	top := L.GetTop()
+	if err := L.DoFile(s.Script); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", s.timeout())
+		}
+		return err
+	}
+	if L.GetTop() > top && L.Get(top+1) == lua.LFalse {
+		reason := "refused"
+		if L.GetTop() > top+1 {
+			reason = L.Get(top + 2).String()
+		}
+		return fmt.Errorf("%s", reason)
+	}
+
+	if !s.Before || reflect.ValueOf(input).Kind() != reflect.Pointer {
+		return nil
+	}
+	changed, err := json.Marshal(fromLua(L.GetGlobal("entry")))
+	if err != nil {
+		return fmt.Errorf("invalid entry: %w", err)
+	}
+	result := reflect.New(reflect.TypeOf(input).Elem())
+	if err := json.Unmarshal(changed, result.Interface()); err != nil {
+		return fmt.Errorf("invalid entry: %w", err)
+	}
+	reflect.ValueOf(input).Elem().Set(result.Elem())
+	return nil
+}
+
+// toLua converts a value decoded from JSON to Lua
+func toLua(L *lua.LState, value any) lua.LValue {
+	switch value := value.(type) {
+	case bool:
+		return lua.LBool(value)
+	case float64:
+		return lua.LNumber(value)
+	case string:
+		return lua.LString(value)
+	case []any:
+		table := L.NewTable()
+		for _, item := range value {
+			table.Append(toLua(L, item))
+		}
+		return table
+	case map[string]any:
+		table := L.NewTable()
+		for k, v := range value {
+			table.RawSetString(k, toLua(L, v))
+		}
+		return table
+	}
+	return lua.LNil
+}
+
+// fromLua converts a Lua value to one that encodes as JSON. Tables with
+// keys 1 to n are arrays; empty tables are null, as they may be either.
+func fromLua(value lua.LValue) any {
+	switch value := value.(type) {
+	case lua.LBool:
+		return bool(value)
+	case lua.LNumber:
+		return float64(value)
+	case lua.LString:
+		return string(value)
+	case *lua.LTable:
+		count := 0
+		value.ForEach(func(lua.LValue, lua.LValue) { count++ })
+		if count == 0 {
+			return nil
+		}
+		if n := value.MaxN(); n == count {
+			items := make([]any, 0, n)
+			for i := 1; i <= n; i++ {
+				items = append(items, fromLua(value.RawGetInt(i)))
+			}
+			return items
+		}
+		fields := make(map[string]any, count)
+		value.ForEach(func(k, v lua.LValue) {
+			fields[k.String()] = fromLua(v)
+		})
+		return fields
+	}
+	return nil
+}
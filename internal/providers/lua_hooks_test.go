@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// writeScript writes a Lua script to a temporary file and returns its path
+func writeScript(t *testing.T, source string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hook.lua")
+	if err := os.WriteFile(path, []byte(source), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLuaHookChangesRequest(t *testing.T) {
+	hook := ScriptHook{Name: "tagger", Before: true, Script: writeScript(t, `
+		if event ~= "create" or hook ~= "tagger" then error("unexpected event " .. event) end
+		entry.title = string.upper(entry.title)
+		table.insert(entry.tags, "work")
+		entry.metadata = {checked = "yes"}
+	`)}
+
+	status := 7
+	req := storage.CreateLogEntryRequest{Date: time.Now(), Type: "activity", Title: "standup", Tags: []string{"team"}, Status: &status}
+	if err := hook.Hook(nil).BeforeCreate(&req); err != nil {
+		t.Fatalf("BeforeCreate: %v", err)
+	}
+	if req.Title != "STANDUP" {
+		t.Errorf("Title = %q, want STANDUP", req.Title)
+	}
+	if strings.Join(req.Tags, ",") != "team,work" {
+		t.Errorf("Tags = %v, want [team work]", req.Tags)
+	}
+	if req.Status == nil || *req.Status != 7 {
+		t.Errorf("Status = %v, want 7", req.Status)
+	}
+	if req.Metadata["checked"] != "yes" {
+		t.Errorf("Metadata = %v, want checked=yes", req.Metadata)
+	}
+}
+
+func TestLuaHookFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		timeout time.Duration
+		want    string
+	}{
+		{name: "refused", source: `return false, "no customers"`, want: "no customers"},
+		{name: "error", source: `error("broken")`, want: "broken"},
+		{name: "timeout", source: `while true do end`, timeout: 50 * time.Millisecond, want: "timed out"},
+		{name: "allowed", source: `return true`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hook := ScriptHook{Name: tt.name, Before: true, OnFailure: FailureAbort, Timeout: tt.timeout, Script: writeScript(t, tt.source)}
+			req := storage.CreateLogEntryRequest{Type: "activity", Title: "Acme call"}
+			err := hook.Hook(nil).BeforeCreate(&req)
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("BeforeCreate: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("BeforeCreate: err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScriptHookValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		hook  ScriptHook
+		valid bool
+	}{
+		{name: "command", hook: ScriptHook{Name: "a", Command: "cat"}, valid: true},
+		{name: "script", hook: ScriptHook{Name: "a", Script: "hook.lua"}, valid: true},
+		{name: "neither", hook: ScriptHook{Name: "a"}},
+		{name: "both", hook: ScriptHook{Name: "a", Command: "cat", Script: "hook.lua"}},
+		{name: "abort after", hook: ScriptHook{Name: "a", Command: "cat", OnFailure: FailureAbort}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.hook.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package providers
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// MemoryStorageProvider implements DailyLogStorage in memory, for trying the
+// tool out and for tests that shouldn't need the network or a token.
+// Everything is lost when the process exits.
+type MemoryStorageProvider struct {
+	dayOperations
+
+	mu       sync.RWMutex
+	days     map[string]*storage.DayLog
+	projects []storage.Project
+	revision int
+}
+
+// NewMemoryStorageProvider creates an empty in-memory storage provider
+func NewMemoryStorageProvider(config storage.Config) *MemoryStorageProvider {
+	m := &MemoryStorageProvider{days: make(map[string]*storage.DayLog)}
+	m.dayOperations = newDayOperations(m, config)
+	return m
+}
+
+// GetDay returns a copy of a day's log, or an empty log if there is none
+func (m *MemoryStorageProvider) GetDay(date time.Time) (*storage.DayLog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if dayLog, ok := m.days[date.Format("2006-01-02")]; ok {
+		return copyDayLog(dayLog), nil
+	}
+	return &storage.DayLog{
+		Date:      date,
+		Entries:   []storage.DailyLogEntry{},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+// SaveDay stores a copy of a day's log; on success dayLog holds its new revision
+func (m *MemoryStorageProvider) SaveDay(dayLog *storage.DayLog) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revision++
+	dayLog.Revision = strconv.Itoa(m.revision)
+	dayLog.UpdatedAt = time.Now()
+	m.days[dayLog.GetDateString()] = copyDayLog(dayLog)
+	return nil
+}
+
+// DeleteDay removes a day's log
+func (m *MemoryStorageProvider) DeleteDay(date time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := date.Format("2006-01-02")
+	if _, ok := m.days[key]; !ok {
+		return storage.NotFoundError{Resource: "day log", ID: key}
+	}
+	delete(m.days, key)
+	return nil
+}
+
+// ListDays lists the stored days within a date range.
+// A zero start or end leaves that side of the range open.
+func (m *MemoryStorageProvider) ListDays(start, end time.Time) ([]time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var dates []time.Time
+	for key := range m.days {
+		day, err := time.Parse("2006-01-02", key)
+		if err != nil {
+			continue
+		}
+		if (!start.IsZero() && day.Before(dateOnly(start))) || (!end.IsZero() && day.After(dateOnly(end))) {
+			continue
+		}
+		dates = append(dates, day)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dates, nil
+}
+
+// GetProjects returns a copy of the project list
+func (m *MemoryStorageProvider) GetProjects() (*storage.ProjectList, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return &storage.ProjectList{Projects: append([]storage.Project{}, m.projects...)}, nil
+}
+
+// SaveProjects stores a copy of the project list
+func (m *MemoryStorageProvider) SaveProjects(list *storage.ProjectList) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.projects = append([]storage.Project{}, list.Projects...)
+	return nil
+}
+
+// Backup does nothing, as there is nothing to keep
+func (m *MemoryStorageProvider) Backup() error {
+	return nil
+}
+
+// HealthCheck always succeeds
+func (m *MemoryStorageProvider) HealthCheck() error {
+	return nil
+}
@@ -0,0 +1,326 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// mirrorQueueSize bounds how many pending mirror writes may be buffered
+const mirrorQueueSize = 64
+
+// mirrorOp is a day to copy to (or delete from) the mirror
+type mirrorOp struct {
+	date   time.Time
+	delete bool
+}
+
+// errMirrorClosed reports a write made after Close, which is not mirrored
+var errMirrorClosed = errors.New("mirror closed; day not replicated")
+
+// mirrorWorker is the background replication shared by a provider and the
+// copies WithContext returns
+type mirrorWorker struct {
+	ops  chan mirrorOp
+	done chan struct{}
+
+	// mu guards closed: enqueue holds it to read, Close to write, so no op
+	// is sent once ops is closed
+	mu     sync.RWMutex
+	closed bool
+
+	errsMu sync.Mutex
+	errs   []error
+}
+
+// MirroredProvider writes to a primary backend and replicates every changed
+// day to a secondary store in the background. Reads are served by the primary.
+// Mirror failures never fail the primary write; they are reported by Close
+// and can be repaired with VerifyMirror and CopyDay.
+type MirroredProvider struct {
+	storage.DailyLogStorage
+
+	mirror storage.DayStore
+	worker *mirrorWorker
+
+	// OnMirrorError, if set, is called when a day fails to replicate: from
+	// the background worker, or with errMirrorClosed for a write after Close
+	OnMirrorError func(date time.Time, err error)
+}
+
+// NewMirroredProvider creates a provider that mirrors primary into mirror
+func NewMirroredProvider(primary storage.DailyLogStorage, mirror storage.DayStore) *MirroredProvider {
+	m := &MirroredProvider{
+		DailyLogStorage: primary,
+		mirror:          mirror,
+		worker: &mirrorWorker{
+			ops:  make(chan mirrorOp, mirrorQueueSize),
+			done: make(chan struct{}),
+		},
+	}
+	go m.run()
+	return m
+}
+
+// WithContext returns the provider writing to its primary under ctx. Its
+// writes are mirrored by this provider's worker; close this provider, not
+// the one returned.
+func (m *MirroredProvider) WithContext(ctx context.Context) storage.DailyLogStorage {
+	return &MirroredProvider{
+		DailyLogStorage: storage.WithContext(ctx, m.DailyLogStorage),
+		mirror:          m.mirror,
+		worker:          m.worker,
+		OnMirrorError:   m.OnMirrorError,
+	}
+}
+
+// Mirror returns the secondary store
+func (m *MirroredProvider) Mirror() storage.DayStore {
+	return m.mirror
+}
+
+// SaveDay saves a day to the primary and schedules it for mirroring
+func (m *MirroredProvider) SaveDay(dayLog *storage.DayLog) error {
+	if err := m.DailyLogStorage.SaveDay(dayLog); err != nil {
+		return err
+	}
+	m.enqueue(mirrorOp{date: dayLog.Date})
+	return nil
+}
+
+// DeleteDay deletes a day from the primary and schedules its removal from the mirror
+func (m *MirroredProvider) DeleteDay(date time.Time) error {
+	if err := m.DailyLogStorage.DeleteDay(date); err != nil {
+		return err
+	}
+	m.enqueue(mirrorOp{date: date, delete: true})
+	return nil
+}
+
+// CreateEntry creates an entry in the primary and schedules its day for mirroring
+func (m *MirroredProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	entry, err := m.DailyLogStorage.CreateEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	m.enqueue(mirrorOp{date: req.Date})
+	return entry, nil
+}
+
+// UpdateEntry updates an entry in the primary and schedules its day for mirroring
+func (m *MirroredProvider) UpdateEntry(req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	entry, err := m.DailyLogStorage.UpdateEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	m.enqueue(mirrorOp{date: entry.Timestamp})
+	return entry, nil
+}
+
+// DeleteEntry deletes an entry from the primary and schedules its day for mirroring
+func (m *MirroredProvider) DeleteEntry(id string, date time.Time) error {
+	if err := m.DailyLogStorage.DeleteEntry(id, date); err != nil {
+		return err
+	}
+	m.enqueue(mirrorOp{date: date})
+	return nil
+}
+
+// SaveSummary saves a summary to the primary and schedules its day for mirroring
+func (m *MirroredProvider) SaveSummary(summary *storage.SummaryResponse, targetType string, date time.Time) error {
+	if err := m.DailyLogStorage.SaveSummary(summary, targetType, date); err != nil {
+		return err
+	}
+	if targetType == "day" {
+		m.enqueue(mirrorOp{date: date})
+	}
+	return nil
+}
+
+// Backup copies every day in the primary to the mirror
+func (m *MirroredProvider) Backup() error {
+	days, err := m.DailyLogStorage.ListDays(time.Time{}, time.Time{})
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		if err := CopyDay(m.DailyLogStorage, m.mirror, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close waits for pending mirror writes to finish and returns any that
+// failed. Writes made after Close still reach the primary but are not
+// mirrored.
+func (m *MirroredProvider) Close() error {
+	w := m.worker
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.ops)
+	}
+	w.mu.Unlock()
+	<-w.done
+
+	w.errsMu.Lock()
+	defer w.errsMu.Unlock()
+	return errors.Join(w.errs...)
+}
+
+func (m *MirroredProvider) enqueue(op mirrorOp) {
+	w := m.worker
+	w.mu.RLock()
+	closed := w.closed
+	if !closed {
+		w.ops <- op
+	}
+	w.mu.RUnlock()
+	if closed && m.OnMirrorError != nil {
+		m.OnMirrorError(op.date, errMirrorClosed)
+	}
+}
+
+func (m *MirroredProvider) run() {
+	w := m.worker
+	defer close(w.done)
+
+	for op := range w.ops {
+		var err error
+		if op.delete {
+			err = m.mirror.DeleteDay(op.date)
+			var notFound storage.NotFoundError
+			if errors.As(err, &notFound) {
+				err = nil
+			}
+		} else {
+			err = CopyDay(m.DailyLogStorage, m.mirror, op.date)
+		}
+
+		if err != nil {
+			w.errsMu.Lock()
+			w.errs = append(w.errs, err)
+			w.errsMu.Unlock()
+			if m.OnMirrorError != nil {
+				m.OnMirrorError(op.date, err)
+			}
+		}
+	}
+}
+
+// CopyDay replaces a day in dst with the version stored in src
+func CopyDay(src, dst storage.DayStore, date time.Time) error {
+	dayLog, err := src.GetDay(date)
+	if err != nil {
+		return err
+	}
+
+	// Write against the destination's own revision so conditional stores accept it
+	current, err := dst.GetDay(date)
+	if err != nil {
+		return err
+	}
+	dayLog.Revision = current.Revision
+	dayLog.Format = current.Format
+
+	return dst.SaveDay(dayLog)
+}
+
+// MirrorReport describes how a mirror differs from its primary
+type MirrorReport struct {
+	Checked         int      `json:"checked" yaml:"checked"`
+	MissingInMirror []string `json:"missing_in_mirror" yaml:"missing_in_mirror"`
+	ExtraInMirror   []string `json:"extra_in_mirror" yaml:"extra_in_mirror"`
+	Different       []string `json:"different" yaml:"different"`
+}
+
+// InSync reports whether the mirror matches the primary
+func (r *MirrorReport) InSync() bool {
+	return len(r.MissingInMirror) == 0 && len(r.ExtraInMirror) == 0 && len(r.Different) == 0
+}
+
+// VerifyMirror compares the days stored in primary and mirror within [start, end].
+// Days are compared by their entries and day summary; bookkeeping timestamps are ignored.
+func VerifyMirror(primary, mirror storage.DayStore, start, end time.Time) (*MirrorReport, error) {
+	primaryDays, err := primary.ListDays(start, end)
+	if err != nil {
+		return nil, err
+	}
+	mirrorDays, err := mirror.ListDays(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	inMirror := make(map[string]bool, len(mirrorDays))
+	for _, day := range mirrorDays {
+		inMirror[day.Format("2006-01-02")] = true
+	}
+
+	report := &MirrorReport{}
+	inPrimary := make(map[string]bool, len(primaryDays))
+	for _, day := range primaryDays {
+		key := day.Format("2006-01-02")
+		inPrimary[key] = true
+		report.Checked++
+
+		if !inMirror[key] {
+			report.MissingInMirror = append(report.MissingInMirror, key)
+			continue
+		}
+
+		same, err := sameDayContent(primary, mirror, day)
+		if err != nil {
+			return nil, err
+		}
+		if !same {
+			report.Different = append(report.Different, key)
+		}
+	}
+
+	for _, day := range mirrorDays {
+		if key := day.Format("2006-01-02"); !inPrimary[key] {
+			report.ExtraInMirror = append(report.ExtraInMirror, key)
+		}
+	}
+
+	return report, nil
+}
+
+func sameDayContent(a, b storage.DayStore, date time.Time) (bool, error) {
+	dayA, err := a.GetDay(date)
+	if err != nil {
+		return false, err
+	}
+	dayB, err := b.GetDay(date)
+	if err != nil {
+		return false, err
+	}
+
+	contentA, err := dayContent(dayA)
+	if err != nil {
+		return false, err
+	}
+	contentB, err := dayContent(dayB)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(contentA, contentB), nil
+}
+
+// dayContent serializes the parts of a day that users author
+func dayContent(dayLog *storage.DayLog) ([]byte, error) {
+	entries := dayLog.Entries
+	if len(entries) == 0 {
+		entries = nil
+	}
+	return json.Marshal(struct {
+		Entries    []storage.DailyLogEntry `json:"entries"`
+		DaySummary string                  `json:"day_summary"`
+	}{entries, dayLog.DaySummary})
+}
@@ -0,0 +1,59 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+func TestMirroredProviderMirrors(t *testing.T) {
+	primary, mirror := NewMemoryStorageProvider(storage.Config{}), NewMemoryStorageProvider(storage.Config{})
+	store := NewMirroredProvider(primary, mirror)
+	date := time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+
+	if _, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Standup"}); err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	report, err := VerifyMirror(primary, mirror, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("VerifyMirror: %v", err)
+	}
+	if !report.InSync() || report.Checked != 1 {
+		t.Errorf("report %+v, want one day in sync", report)
+	}
+}
+
+func TestMirroredProviderWriteAfterClose(t *testing.T) {
+	primary, mirror := NewMemoryStorageProvider(storage.Config{}), NewMemoryStorageProvider(storage.Config{})
+	store := NewMirroredProvider(primary, mirror)
+	var dropped []error
+	store.OnMirrorError = func(date time.Time, err error) { dropped = append(dropped, err) }
+	bound := store.WithContext(context.Background())
+	date := time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if _, err := store.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "activity", Title: "Late"}); err != nil {
+		t.Fatalf("CreateEntry after Close: %v", err)
+	}
+	if err := bound.DeleteDay(date); err != nil {
+		t.Fatalf("DeleteDay after Close through a bound copy: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+
+	if len(dropped) != 2 || !errors.Is(dropped[0], errMirrorClosed) || !errors.Is(dropped[1], errMirrorClosed) {
+		t.Errorf("OnMirrorError got %v, want both writes reported as not mirrored", dropped)
+	}
+	if days, _ := mirror.ListDays(time.Time{}, time.Time{}); len(days) != 0 {
+		t.Errorf("mirror has %v, want nothing written after Close", days)
+	}
+}
@@ -0,0 +1,281 @@
+package providers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Queued operation types
+const (
+	QueueOpCreate = "create"
+	QueueOpDelete = "delete"
+)
+
+// QueuedOperation is a write recorded while the backend was unreachable
+type QueuedOperation struct {
+	Op       string                 `json:"op"`
+	Date     time.Time              `json:"date"`
+	Entry    *storage.DailyLogEntry `json:"entry,omitempty"`
+	EntryID  string                 `json:"entry_id,omitempty"`
+	QueuedAt time.Time              `json:"queued_at"`
+}
+
+// OfflineQueueProvider wraps a storage backend and queues entry writes to a
+// local journal file when the backend cannot be reached. Queued writes are
+// replayed, oldest first, by Sync or automatically before the next write.
+type OfflineQueueProvider struct {
+	storage.DailyLogStorage
+
+	queuePath string
+	mu        sync.Mutex
+
+	// OnQueued, if set, is called whenever a write is queued instead of stored
+	OnQueued func(op QueuedOperation, cause error)
+}
+
+// NewOfflineQueueProvider creates a new offline queue around a backend
+func NewOfflineQueueProvider(backend storage.DailyLogStorage, queuePath string) (*OfflineQueueProvider, error) {
+	if queuePath == "" {
+		return nil, fmt.Errorf("offline queue path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(queuePath), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create offline queue directory: %v", err)
+	}
+
+	return &OfflineQueueProvider{
+		DailyLogStorage: backend,
+		queuePath:       queuePath,
+	}, nil
+}
+
+// CreateEntry creates an entry, queuing it locally if the backend is unreachable
+func (o *OfflineQueueProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	if _, err := o.Sync(); err != nil && !isUnreachable(err) {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	queued, err := o.pendingLocked()
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep ordering intact: while older writes are still queued, queue this one too
+	if len(queued) == 0 {
+		entry, err := o.DailyLogStorage.CreateEntry(req)
+		if err == nil || !isUnreachable(err) {
+			return entry, err
+		}
+		return o.queueCreate(req, err)
+	}
+
+	return o.queueCreate(req, fmt.Errorf("%d earlier writes still queued", len(queued)))
+}
+
+// DeleteEntry deletes an entry, queuing the deletion locally if the backend is unreachable
+func (o *OfflineQueueProvider) DeleteEntry(id string, date time.Time) error {
+	if _, err := o.Sync(); err != nil && !isUnreachable(err) {
+		return err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	queued, err := o.pendingLocked()
+	if err != nil {
+		return err
+	}
+
+	cause := fmt.Errorf("%d earlier writes still queued", len(queued))
+	if len(queued) == 0 {
+		err := o.DailyLogStorage.DeleteEntry(id, date)
+		if err == nil || !isUnreachable(err) {
+			return err
+		}
+		cause = err
+	}
+
+	return o.enqueue(QueuedOperation{
+		Op:       QueueOpDelete,
+		Date:     date,
+		EntryID:  id,
+		QueuedAt: time.Now(),
+	}, cause)
+}
+
+// Pending returns the operations waiting to be synced
+func (o *OfflineQueueProvider) Pending() ([]QueuedOperation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.pendingLocked()
+}
+
+// Sync replays queued operations against the backend, oldest first.
+// It stops at the first failure, keeping the remaining operations queued,
+// and returns how many operations were applied.
+func (o *OfflineQueueProvider) Sync() (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	ops, err := o.pendingLocked()
+	if err != nil || len(ops) == 0 {
+		return 0, err
+	}
+
+	for i, op := range ops {
+		if err := o.apply(op); err != nil {
+			if writeErr := o.writeQueueLocked(ops[i:]); writeErr != nil {
+				return i, writeErr
+			}
+			return i, err
+		}
+	}
+
+	return len(ops), o.writeQueueLocked(nil)
+}
+
+// apply replays one operation. SaveDay merges with any concurrent changes,
+// and replays are idempotent so a partially completed sync can be retried.
+func (o *OfflineQueueProvider) apply(op QueuedOperation) error {
+	dayLog, err := o.DailyLogStorage.GetDay(op.Date)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case QueueOpCreate:
+		if op.Entry == nil {
+			return nil
+		}
+		for _, existing := range dayLog.Entries {
+			if existing.ID == op.Entry.ID {
+				return nil
+			}
+		}
+		dayLog.AddEntry(*op.Entry)
+	case QueueOpDelete:
+		if !dayLog.RemoveEntry(op.EntryID) {
+			return nil
+		}
+	default:
+		return fmt.Errorf("unknown queued operation: %s", op.Op)
+	}
+
+	return o.DailyLogStorage.SaveDay(dayLog)
+}
+
+func (o *OfflineQueueProvider) queueCreate(req storage.CreateLogEntryRequest, cause error) (*storage.DailyLogEntry, error) {
+	entry := storage.NewEntry(req)
+	err := o.enqueue(QueuedOperation{
+		Op:       QueueOpCreate,
+		Date:     req.Date,
+		Entry:    &entry,
+		QueuedAt: time.Now(),
+	}, cause)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (o *OfflineQueueProvider) enqueue(op QueuedOperation, cause error) error {
+	f, err := os.OpenFile(o.queuePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open offline queue: %v", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to encode queued operation: %v", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write offline queue: %v", err)
+	}
+
+	if o.OnQueued != nil {
+		o.OnQueued(op, cause)
+	}
+	return nil
+}
+
+func (o *OfflineQueueProvider) pendingLocked() ([]QueuedOperation, error) {
+	f, err := os.Open(o.queuePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open offline queue: %v", err)
+	}
+	defer f.Close()
+
+	var ops []QueuedOperation
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var op QueuedOperation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			return nil, fmt.Errorf("corrupt offline queue entry: %v", err)
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read offline queue: %v", err)
+	}
+	return ops, nil
+}
+
+// writeQueueLocked atomically replaces the queue with the given operations
+func (o *OfflineQueueProvider) writeQueueLocked(ops []QueuedOperation) error {
+	if len(ops) == 0 {
+		if err := os.Remove(o.queuePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear offline queue: %v", err)
+		}
+		return nil
+	}
+
+	tmpPath := o.queuePath + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite offline queue: %v", err)
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, op := range ops {
+		if err := encoder.Encode(op); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to rewrite offline queue: %v", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to rewrite offline queue: %v", err)
+	}
+
+	return os.Rename(tmpPath, o.queuePath)
+}
+
+// isUnreachable reports whether an error means the backend could not be reached,
+// as opposed to the backend rejecting the request
+func isUnreachable(err error) bool {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,283 @@
+package providers
+
+import (
+	"errors"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// fakeBackend is memory storage that can be taken offline, failing as
+// GitHub does when it can't be reached, and whose saves are conditional
+// on the revision read, as GitHub's are, so concurrent writes conflict
+type fakeBackend struct {
+	*MemoryStorageProvider
+
+	offline bool
+
+	// beforeSave, if set, runs before each save, e.g. to have another
+	// client write the day first
+	beforeSave func()
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{MemoryStorageProvider: NewMemoryStorageProvider(storage.Config{})}
+}
+
+func (f *fakeBackend) unreachable() error {
+	return &url.Error{Op: "Get", URL: "https://api.github.com", Err: errors.New("connection refused")}
+}
+
+func (f *fakeBackend) GetDay(date time.Time) (*storage.DayLog, error) {
+	if f.offline {
+		return nil, f.unreachable()
+	}
+	return f.MemoryStorageProvider.GetDay(date)
+}
+
+func (f *fakeBackend) SaveDay(dayLog *storage.DayLog) error {
+	if f.offline {
+		return f.unreachable()
+	}
+	if f.beforeSave != nil {
+		f.beforeSave()
+	}
+	current, err := f.MemoryStorageProvider.GetDay(dayLog.Date)
+	if err != nil {
+		return err
+	}
+	if current.Revision != dayLog.Revision {
+		return storage.StorageError{Operation: "SaveDay", Message: "day changed since it was read"}
+	}
+	return f.MemoryStorageProvider.SaveDay(dayLog)
+}
+
+func (f *fakeBackend) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	if f.offline {
+		return nil, f.unreachable()
+	}
+	return f.MemoryStorageProvider.CreateEntry(req)
+}
+
+func (f *fakeBackend) DeleteEntry(id string, date time.Time) error {
+	if f.offline {
+		return f.unreachable()
+	}
+	return f.MemoryStorageProvider.DeleteEntry(id, date)
+}
+
+// otherClient writes an entry to the backend directly, as another machine would
+func (f *fakeBackend) otherClient(t *testing.T, date time.Time, title string) storage.DailyLogEntry {
+	t.Helper()
+	entry, err := f.MemoryStorageProvider.CreateEntry(storage.CreateLogEntryRequest{Date: date, Type: "note", Title: title})
+	if err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+	return *entry
+}
+
+// titles lists the titles of the stored day's entries
+func (f *fakeBackend) titles(t *testing.T, date time.Time) string {
+	t.Helper()
+	day, err := f.MemoryStorageProvider.GetDay(date)
+	if err != nil {
+		t.Fatalf("GetDay: %v", err)
+	}
+	var titles []string
+	for _, entry := range day.Entries {
+		titles = append(titles, entry.Title)
+	}
+	return strings.Join(titles, ",")
+}
+
+func newOfflineQueue(t *testing.T, backend storage.DailyLogStorage) *OfflineQueueProvider {
+	t.Helper()
+	queue, err := NewOfflineQueueProvider(backend, filepath.Join(t.TempDir(), "queue", "pending.jsonl"))
+	if err != nil {
+		t.Fatalf("NewOfflineQueueProvider: %v", err)
+	}
+	return queue
+}
+
+// queueDate is the day the queued entries are for
+var queueDate = time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+
+func TestOfflineQueueEnqueues(t *testing.T) {
+	backend := newFakeBackend()
+	kept := backend.otherClient(t, queueDate, "Kept")
+	queue := newOfflineQueue(t, backend)
+	var causes []error
+	queue.OnQueued = func(op QueuedOperation, cause error) { causes = append(causes, cause) }
+
+	backend.offline = true
+	entry, err := queue.CreateEntry(storage.CreateLogEntryRequest{Date: queueDate, Type: "activity", Title: "Standup"})
+	if err != nil {
+		t.Fatalf("CreateEntry offline: %v", err)
+	}
+	if entry.ID == "" || entry.Title != "Standup" {
+		t.Errorf("queued entry %+v, want an ID and the title", entry)
+	}
+	if err := queue.DeleteEntry(kept.ID, queueDate); err != nil {
+		t.Fatalf("DeleteEntry offline: %v", err)
+	}
+
+	pending, err := queue.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Op != QueueOpCreate || pending[0].Entry.ID != entry.ID ||
+		pending[1].Op != QueueOpDelete || pending[1].EntryID != kept.ID {
+		t.Fatalf("pending %+v, want the create then the delete", pending)
+	}
+	if len(causes) != 2 || !isUnreachable(causes[0]) {
+		t.Errorf("OnQueued causes %v, want the backend being unreachable", causes)
+	}
+	if got := backend.titles(t, queueDate); got != "Kept" {
+		t.Errorf("stored %q while offline, want Kept", got)
+	}
+
+	// Back online, the next write replays the queue first
+	backend.offline = false
+	if _, err := queue.CreateEntry(storage.CreateLogEntryRequest{Date: queueDate.Add(time.Hour), Type: "note", Title: "Lunch"}); err != nil {
+		t.Fatalf("CreateEntry online: %v", err)
+	}
+	if got := backend.titles(t, queueDate); got != "Standup,Lunch" {
+		t.Errorf("stored %q, want Standup,Lunch", got)
+	}
+	if pending, _ := queue.Pending(); len(pending) != 0 {
+		t.Errorf("pending %+v after replay, want none", pending)
+	}
+}
+
+func TestOfflineQueueOnlyQueuesUnreachable(t *testing.T) {
+	backend := newFakeBackend()
+	queue := newOfflineQueue(t, backend)
+
+	// Deleting an entry that isn't there is the backend's answer, not an outage
+	err := queue.DeleteEntry(storage.NewEntryIDAt(queueDate), queueDate)
+	var notFound storage.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("DeleteEntry of a missing entry: err = %v, want NotFoundError", err)
+	}
+	if pending, _ := queue.Pending(); len(pending) != 0 {
+		t.Errorf("pending %+v, want none", pending)
+	}
+}
+
+func TestOfflineQueueSyncOrder(t *testing.T) {
+	backend := newFakeBackend()
+	queue := newOfflineQueue(t, backend)
+
+	backend.offline = true
+	first, err := queue.CreateEntry(storage.CreateLogEntryRequest{Date: queueDate, Type: "activity", Title: "First"})
+	if err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+	if _, err := queue.CreateEntry(storage.CreateLogEntryRequest{Date: queueDate.Add(time.Hour), Type: "activity", Title: "Second"}); err != nil {
+		t.Fatalf("CreateEntry: %v", err)
+	}
+	if err := queue.DeleteEntry(first.ID, queueDate); err != nil {
+		t.Fatalf("DeleteEntry: %v", err)
+	}
+	if applied, err := queue.Sync(); !isUnreachable(err) || applied != 0 {
+		t.Fatalf("Sync offline = %d, %v, want 0 and unreachable", applied, err)
+	}
+
+	backend.offline = false
+	applied, err := queue.Sync()
+	if err != nil || applied != 3 {
+		t.Fatalf("Sync = %d, %v, want 3", applied, err)
+	}
+	if got := backend.titles(t, queueDate); got != "Second" {
+		t.Errorf("stored %q, want Second", got)
+	}
+}
+
+func TestOfflineQueueReplayConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		// meanwhile changes the backend while the writes are queued, given
+		// the queued entry and the one stored before going offline
+		meanwhile func(t *testing.T, backend *fakeBackend, queued, stored storage.DailyLogEntry)
+		// failedSyncs is how many syncs fail on a conflict before one succeeds
+		failedSyncs int
+		want        string
+	}{
+		{
+			name: "day changed by another client",
+			meanwhile: func(t *testing.T, b *fakeBackend, _, _ storage.DailyLogEntry) {
+				b.otherClient(t, queueDate.Add(time.Minute), "Other")
+			},
+			want: "Other,Queued",
+		},
+		{
+			name: "create already replayed",
+			meanwhile: func(t *testing.T, b *fakeBackend, queued, _ storage.DailyLogEntry) {
+				day, _ := b.MemoryStorageProvider.GetDay(queueDate)
+				day.AddEntry(queued)
+				_ = b.MemoryStorageProvider.SaveDay(day)
+			},
+			want: "Queued",
+		},
+		{
+			name: "delete already made",
+			meanwhile: func(t *testing.T, b *fakeBackend, _, stored storage.DailyLogEntry) {
+				_ = b.MemoryStorageProvider.DeleteEntry(stored.ID, queueDate)
+			},
+			want: "Queued",
+		},
+		{
+			name: "another client saves during the replay",
+			meanwhile: func(t *testing.T, b *fakeBackend, _, _ storage.DailyLogEntry) {
+				b.beforeSave = func() {
+					b.beforeSave = nil
+					b.otherClient(t, queueDate.Add(time.Minute), "Other")
+				}
+			},
+			failedSyncs: 1,
+			want:        "Other,Queued",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := newFakeBackend()
+			stored := backend.otherClient(t, queueDate.Add(-time.Hour), "Stored")
+			queue := newOfflineQueue(t, backend)
+
+			backend.offline = true
+			queued, err := queue.CreateEntry(storage.CreateLogEntryRequest{Date: queueDate, Type: "activity", Title: "Queued"})
+			if err != nil {
+				t.Fatalf("CreateEntry: %v", err)
+			}
+			if err := queue.DeleteEntry(stored.ID, queueDate); err != nil {
+				t.Fatalf("DeleteEntry: %v", err)
+			}
+			backend.offline = false
+			tt.meanwhile(t, backend, *queued, stored)
+
+			for i := 0; i < tt.failedSyncs; i++ {
+				applied, err := queue.Sync()
+				var storageErr storage.StorageError
+				if !errors.As(err, &storageErr) || applied != 0 {
+					t.Fatalf("Sync = %d, %v, want 0 and the conflict", applied, err)
+				}
+				if pending, _ := queue.Pending(); len(pending) != 2 {
+					t.Fatalf("pending %+v after a conflict, want both writes kept", pending)
+				}
+			}
+			if applied, err := queue.Sync(); err != nil || applied != 2 {
+				t.Fatalf("Sync = %d, %v, want 2", applied, err)
+			}
+			if got := backend.titles(t, queueDate); got != tt.want {
+				t.Errorf("stored %q, want %q", got, tt.want)
+			}
+			if pending, _ := queue.Pending(); len(pending) != 0 {
+				t.Errorf("pending %+v after sync, want none", pending)
+			}
+		})
+	}
+}
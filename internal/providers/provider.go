@@ -0,0 +1,23 @@
+package providers
+
+import (
+	"fmt"
+
+	"dailylog/internal/storage"
+)
+
+// NewStorageProvider creates the storage provider of config.StorageType,
+// GitHub if it is empty
+func NewStorageProvider(config storage.Config) (storage.DailyLogStorage, error) {
+	switch config.StorageType {
+	case "", storage.StorageTypeGitHub:
+		return NewGitHubStorageProvider(config)
+	case storage.StorageTypeMemory:
+		return NewMemoryStorageProvider(config), nil
+	default:
+		return nil, storage.ValidationError{
+			Field:   "storage_type",
+			Message: fmt.Sprintf("unknown storage type %q (use github or memory)", config.StorageType),
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"context"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// ReadOnlyProvider serves reads from the wrapped backend and refuses every
+// write with an error wrapping storage.ErrReadOnly
+type ReadOnlyProvider struct {
+	storage.DailyLogStorage
+}
+
+// NewReadOnlyProvider wraps backend so that nothing can be written to it
+func NewReadOnlyProvider(backend storage.DailyLogStorage) *ReadOnlyProvider {
+	return &ReadOnlyProvider{DailyLogStorage: backend}
+}
+
+// WithContext returns the provider reading from its backend under ctx
+func (r *ReadOnlyProvider) WithContext(ctx context.Context) storage.DailyLogStorage {
+	return NewReadOnlyProvider(storage.WithContext(ctx, r.DailyLogStorage))
+}
+
+func refuseWrite(operation string) error {
+	return storage.StorageError{Operation: operation, Message: "write refused", Cause: storage.ErrReadOnly}
+}
+
+// SaveDay refuses to save the day
+func (r *ReadOnlyProvider) SaveDay(dayLog *storage.DayLog) error {
+	return refuseWrite("SaveDay")
+}
+
+// DeleteDay refuses to delete the day
+func (r *ReadOnlyProvider) DeleteDay(date time.Time) error {
+	return refuseWrite("DeleteDay")
+}
+
+// CreateEntry refuses to create the entry
+func (r *ReadOnlyProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	return nil, refuseWrite("CreateEntry")
+}
+
+// UpdateEntry refuses to update the entry
+func (r *ReadOnlyProvider) UpdateEntry(req storage.UpdateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	return nil, refuseWrite("UpdateEntry")
+}
+
+// DeleteEntry refuses to delete the entry
+func (r *ReadOnlyProvider) DeleteEntry(id string, date time.Time) error {
+	return refuseWrite("DeleteEntry")
+}
+
+// SaveSummary refuses to save the summary
+func (r *ReadOnlyProvider) SaveSummary(summary *storage.SummaryResponse, targetType string, date time.Time) error {
+	return refuseWrite("SaveSummary")
+}
+
+// Backup refuses to write a backup
+func (r *ReadOnlyProvider) Backup() error {
+	return refuseWrite("Backup")
+}
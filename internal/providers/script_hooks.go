@@ -0,0 +1,186 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Entry events script hooks run on
+const (
+	EventCreate = "create"
+	EventUpdate = "update"
+	EventDelete = "delete"
+)
+
+// What a script hook's failure does
+const (
+	FailureWarn   = "warn"   // Report it; the default
+	FailureIgnore = "ignore" // Say nothing
+	FailureAbort  = "abort"  // Refuse the write (before hooks only)
+)
+
+// DefaultScriptTimeout is how long a script hook may run when no timeout is set
+const DefaultScriptTimeout = 10 * time.Second
+
+// ScriptHook runs a shell command or Lua script on entry events, such as
+// posting new entries to a chat channel. The command gets the entry as JSON
+// on stdin, or for a before hook the request, and DAILYLOG_EVENT and
+// DAILYLOG_HOOK in its environment. A deletion is given as {"id": ...,
+// "date": ...}. The command's output is discarded, except stderr, which is
+// reported if it fails. A Lua script gets the same as globals; see runLua.
+type ScriptHook struct {
+	Name    string        `mapstructure:"name" json:"name"`
+	Command string        `mapstructure:"command" json:"command,omitempty"`
+	Script  string        `mapstructure:"script" json:"script,omitempty"`   // Path to a Lua script, instead of a command
+	Events  []string      `mapstructure:"events" json:"events,omitempty"`   // Default: create and update
+	Timeout time.Duration `mapstructure:"timeout" json:"timeout,omitempty"` // Default: DefaultScriptTimeout
+
+	// Before runs the command before the write rather than after it, so
+	// with OnFailure set to abort, a failure refuses the write
+	Before    bool   `mapstructure:"before" json:"before,omitempty"`
+	OnFailure string `mapstructure:"on_failure" json:"on_failure,omitempty"`
+}
+
+// Validate checks the hook's command, events, and failure policy
+func (s ScriptHook) Validate() error {
+	field := "hooks." + s.Name
+	if s.Name == "" {
+		return storage.ValidationError{Field: "hooks", Message: "every hook needs a name"}
+	}
+	if (strings.TrimSpace(s.Command) == "") == (s.Script == "") {
+		return storage.ValidationError{Field: field, Message: "set either command or script"}
+	}
+	for _, event := range s.Events {
+		switch event {
+		case EventCreate, EventUpdate, EventDelete:
+		default:
+			return storage.ValidationError{Field: field, Message: fmt.Sprintf("unknown event %q (use create, update, or delete)", event)}
+		}
+	}
+	switch s.OnFailure {
+	case "", FailureWarn, FailureIgnore:
+	case FailureAbort:
+		if !s.Before {
+			return storage.ValidationError{Field: field, Message: "on_failure: abort needs before: true, as the write is already made"}
+		}
+	default:
+		return storage.ValidationError{Field: field, Message: fmt.Sprintf("unknown on_failure %q (use warn, ignore, or abort)", s.OnFailure)}
+	}
+	if s.Timeout < 0 {
+		return storage.ValidationError{Field: field, Message: "timeout can't be negative"}
+	}
+	return nil
+}
+
+// Hook returns the hook running the command. onWarn, if set, is told of
+// failures under the warn policy.
+func (s ScriptHook) Hook(onWarn func(name string, err error)) Hook {
+	hook := Hook{Name: s.Name}
+	handles := func(event string) bool {
+		if len(s.Events) == 0 {
+			return event == EventCreate || event == EventUpdate
+		}
+		for _, e := range s.Events {
+			if e == event {
+				return true
+			}
+		}
+		return false
+	}
+	// run runs the command and applies the failure policy, returning the
+	// error only if it should refuse the write
+	run := func(event string, input any) error {
+		err := s.Run(event, input)
+		switch {
+		case err == nil, s.OnFailure == FailureIgnore:
+			return nil
+		case s.OnFailure == FailureAbort:
+			return err
+		}
+		if onWarn != nil {
+			onWarn(s.Name, err)
+		}
+		return nil
+	}
+
+	deletion := func(id string, date time.Time) map[string]string {
+		return map[string]string{"id": id, "date": date.Format("2006-01-02")}
+	}
+	if s.Before {
+		if handles(EventCreate) {
+			hook.BeforeCreate = func(req *storage.CreateLogEntryRequest) error { return run(EventCreate, req) }
+		}
+		if handles(EventUpdate) {
+			hook.BeforeUpdate = func(req *storage.UpdateLogEntryRequest) error { return run(EventUpdate, req) }
+		}
+		if handles(EventDelete) {
+			hook.BeforeDelete = func(id string, date time.Time) error { return run(EventDelete, deletion(id, date)) }
+		}
+		return hook
+	}
+	if handles(EventCreate) {
+		hook.AfterCreate = func(entry *storage.DailyLogEntry) { _ = run(EventCreate, entry) }
+	}
+	if handles(EventUpdate) {
+		hook.AfterUpdate = func(entry *storage.DailyLogEntry) { _ = run(EventUpdate, entry) }
+	}
+	if handles(EventDelete) {
+		hook.AfterDelete = func(id string, date time.Time) { _ = run(EventDelete, deletion(id, date)) }
+	}
+	return hook
+}
+
+// timeout is how long the hook may run
+func (s ScriptHook) timeout() time.Duration {
+	if s.Timeout == 0 {
+		return DefaultScriptTimeout
+	}
+	return s.Timeout
+}
+
+// Run runs the command for event with input as JSON on stdin, or the Lua
+// script with input as entry
+func (s ScriptHook) Run(event string, input any) error {
+	if s.Script != "" {
+		return s.runLua(event, input)
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	timeout := s.timeout()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.Command)
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", s.Command)
+	}
+	cmd.Stdin = bytes.NewReader(data)
+	// Don't wait for children of a killed shell still holding stderr open
+	cmd.WaitDelay = time.Second
+	cmd.Env = append(os.Environ(), "DAILYLOG_EVENT="+event, "DAILYLOG_HOOK="+s.Name)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
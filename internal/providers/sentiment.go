@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+
+	"dailylog/internal/sentiment"
+	"dailylog/internal/storage"
+)
+
+// Metadata keys written by SentimentProvider
+const (
+	MetadataSentiment      = "sentiment"
+	MetadataSentimentScore = "sentiment_score"
+)
+
+// SentimentProvider analyzes the sentiment of new entries before storing
+// them. Entries of the configured types get sentiment metadata, and their
+// status (mood) is filled in from the score when it wasn't given. Entries
+// that already carry sentiment metadata are left alone.
+type SentimentProvider struct {
+	storage.DailyLogStorage
+
+	analyzer sentiment.Analyzer
+	types    map[string]bool
+
+	// OnError, if set, is told when analysis fails; the entry is stored without sentiment
+	OnError func(err error)
+}
+
+// NewSentimentProvider analyzes entries of the given types with analyzer; no
+// types means every type except summaries
+func NewSentimentProvider(backend storage.DailyLogStorage, analyzer sentiment.Analyzer, types []string) *SentimentProvider {
+	p := &SentimentProvider{DailyLogStorage: backend, analyzer: analyzer}
+	if len(types) > 0 {
+		p.types = make(map[string]bool, len(types))
+		for _, entryType := range types {
+			p.types[strings.TrimSpace(entryType)] = true
+		}
+	}
+	return p
+}
+
+// CreateEntry tags the entry with its sentiment and creates it
+func (p *SentimentProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	if p.analyzes(req) {
+		if err := p.tag(&req); err != nil && p.OnError != nil {
+			p.OnError(err)
+		}
+	}
+	return p.DailyLogStorage.CreateEntry(req)
+}
+
+// analyzes reports whether req should be analyzed
+func (p *SentimentProvider) analyzes(req storage.CreateLogEntryRequest) bool {
+	if _, done := req.Metadata[MetadataSentiment]; done {
+		return false
+	}
+	if p.types == nil {
+		return req.Type != "summary"
+	}
+	return p.types[req.Type]
+}
+
+// tag adds sentiment metadata to req and a status if it has none
+func (p *SentimentProvider) tag(req *storage.CreateLogEntryRequest) error {
+	text := strings.TrimSpace(req.Title + "\n" + req.Description)
+	if text == "" {
+		return nil
+	}
+	result, err := p.analyzer.Analyze(text)
+	if err != nil {
+		return err
+	}
+
+	metadata := make(map[string]string, len(req.Metadata)+2)
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	metadata[MetadataSentiment] = result.Label
+	metadata[MetadataSentimentScore] = fmt.Sprintf("%.2f", result.Score)
+	req.Metadata = metadata
+
+	if req.Status == nil && result.Confident {
+		status := result.Status()
+		req.Status = &status
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"dailylog/internal/storage"
+	"dailylog/internal/weather"
+)
+
+// WeatherProvider records the weather in a day's metadata the first time an
+// entry is created on it. The weather is looked up at the location most of
+// the day's entries were logged at, falling back to the default location
+// when there is none or it cannot be found. Days in the future are left alone.
+type WeatherProvider struct {
+	storage.DailyLogStorage
+
+	source   weather.Source
+	location string
+
+	// OnError, if set, is told when the weather cannot be recorded; the entry is stored regardless
+	OnError func(err error)
+}
+
+// NewWeatherProvider records weather from source, at location unless the
+// entries say otherwise
+func NewWeatherProvider(backend storage.DailyLogStorage, source weather.Source, location string) *WeatherProvider {
+	return &WeatherProvider{DailyLogStorage: backend, source: source, location: location}
+}
+
+// CreateEntry creates the entry and records the day's weather if it has none
+func (p *WeatherProvider) CreateEntry(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	entry, err := p.DailyLogStorage.CreateEntry(req)
+	if err != nil {
+		return entry, err
+	}
+	if _, _, err := p.Enrich(entry.Timestamp, false); err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+	return entry, nil
+}
+
+// Enrich records the weather of date's day unless it has some already, or
+// always with refresh. It returns the day's weather and whether it was saved.
+func (p *WeatherProvider) Enrich(date time.Time, refresh bool) (*storage.Weather, bool, error) {
+	if date.After(time.Now()) {
+		return nil, false, nil
+	}
+	dayLog, err := p.DailyLogStorage.GetDay(date)
+	if err != nil {
+		return nil, false, err
+	}
+	if w, ok := storage.WeatherOf(*dayLog); ok && !refresh {
+		return &w, false, nil
+	}
+
+	w, err := p.lookup(*dayLog, date)
+	if err != nil {
+		return nil, false, err
+	}
+	storage.SetWeather(dayLog, w)
+	if err := p.DailyLogStorage.SaveDay(dayLog); err != nil {
+		return nil, false, fmt.Errorf("failed to save the weather: %w", err)
+	}
+	return &w, true, nil
+}
+
+// lookup tries the day's own location, then the default
+func (p *WeatherProvider) lookup(dayLog storage.DayLog, date time.Time) (storage.Weather, error) {
+	location := storage.DayLocation(dayLog)
+	if location != "" {
+		w, err := p.source.Lookup(location, date)
+		if err == nil || !errors.Is(err, weather.ErrUnknownLocation) || p.location == "" {
+			return w, err
+		}
+	}
+	if p.location == "" {
+		return storage.Weather{}, fmt.Errorf("no location to look up the weather for %s (set weather.location)", date.Format("2006-01-02"))
+	}
+	return p.source.Lookup(p.location, date)
+}
@@ -0,0 +1,38 @@
+package sentiment
+
+// negations flip the weight of the word that follows
+var negations = map[string]bool{
+	"not": true, "no": true, "never": true, "without": true, "hardly": true,
+	"don't": true, "didn't": true, "isn't": true, "wasn't": true, "aren't": true,
+	"weren't": true, "can't": true, "couldn't": true, "won't": true, "wouldn't": true,
+}
+
+// lexicon weights words from -3 (strongly negative) to 3 (strongly positive),
+// chosen for the kind of things people write in a work and life log
+var lexicon = map[string]float64{
+	// Positive
+	"good": 2, "great": 3, "excellent": 3, "awesome": 3, "amazing": 3, "fantastic": 3,
+	"wonderful": 3, "happy": 3, "glad": 2, "pleased": 2, "proud": 2, "excited": 3,
+	"fun": 2, "enjoyed": 2, "enjoy": 2, "love": 3, "loved": 3, "like": 1, "liked": 1,
+	"nice": 2, "productive": 2, "progress": 2, "success": 2, "successful": 2,
+	"finished": 1, "completed": 1, "shipped": 2, "solved": 2, "fixed": 1, "win": 2,
+	"won": 2, "breakthrough": 3, "calm": 1, "relaxed": 2, "rested": 2, "energized": 2,
+	"motivated": 2, "focused": 1, "smooth": 1, "easy": 1, "helpful": 2, "thanks": 1,
+	"grateful": 2, "thankful": 2, "celebrate": 3, "celebrated": 3, "better": 1,
+	"best": 3, "improved": 2, "clear": 1, "confident": 2, "satisfied": 2, "relieved": 2,
+	"optimistic": 2, "hopeful": 2, "inspired": 2, "appreciated": 2, "well": 1,
+
+	// Negative
+	"bad": -2, "terrible": -3, "awful": -3, "horrible": -3, "sad": -2, "unhappy": -2,
+	"angry": -3, "annoyed": -2, "annoying": -2, "frustrated": -2, "frustrating": -2,
+	"upset": -2, "stressed": -2, "stress": -2, "stressful": -2, "anxious": -2,
+	"worried": -2, "worry": -2, "tired": -2, "exhausted": -3, "drained": -2,
+	"burnout": -3, "overwhelmed": -3, "sick": -2, "ill": -2, "pain": -2, "hurt": -2,
+	"failed": -2, "failure": -2, "fail": -2, "broken": -2, "blocked": -2, "blocker": -2,
+	"stuck": -2, "struggled": -2, "struggling": -2, "problem": -1, "problems": -1,
+	"issue": -1, "issues": -1, "bug": -1, "bugs": -1, "outage": -2, "incident": -1,
+	"late": -1, "delayed": -1, "slow": -1, "boring": -2, "bored": -2, "disappointed": -2,
+	"disappointing": -2, "worse": -2, "worst": -3, "hate": -3, "hated": -3,
+	"difficult": -1, "hard": -1, "conflict": -2, "argument": -2, "lonely": -2,
+	"sleepless": -2, "rough": -2, "mess": -2, "wasted": -2, "distracted": -1,
+}
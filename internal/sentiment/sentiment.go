@@ -0,0 +1,152 @@
+// Package sentiment scores the sentiment of log entry text, either locally
+// with a small word list or with an AI provider.
+package sentiment
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"dailylog/internal/storage"
+)
+
+// Sentiment labels
+const (
+	Positive = "positive"
+	Negative = "negative"
+	Neutral  = "neutral"
+)
+
+// neutralBand is how far from zero a score must be to count as positive or negative
+const neutralBand = 0.1
+
+// Result is the sentiment of a piece of text
+type Result struct {
+	// Label is positive, negative, or neutral
+	Label string
+	// Score runs from -1 (most negative) to 1 (most positive)
+	Score float64
+	// Confident is false when the text gave little to go on, e.g. no
+	// sentiment-bearing words; such results shouldn't stand in for a mood
+	Confident bool
+}
+
+// Status maps the score onto the 1-10 status scale
+func (r Result) Status() int {
+	status := int(math.Round(5 + r.Score*5))
+	if status < 1 {
+		return 1
+	}
+	if status > 10 {
+		return 10
+	}
+	return status
+}
+
+// Analyzer scores the sentiment of text
+type Analyzer interface {
+	Analyze(text string) (Result, error)
+}
+
+// newResult labels a score
+func newResult(score float64, confident bool) Result {
+	score = math.Max(-1, math.Min(1, score))
+	label := Neutral
+	if score >= neutralBand {
+		label = Positive
+	} else if score <= -neutralBand {
+		label = Negative
+	}
+	return Result{Label: label, Score: score, Confident: confident}
+}
+
+// Lexicon is a local analyzer that sums the weights of known words, flipping
+// a word's weight when it follows a negation such as "not"
+type Lexicon struct {
+	words map[string]float64
+}
+
+// NewLexicon creates a local analyzer with the built-in word list
+func NewLexicon() *Lexicon {
+	return &Lexicon{words: lexicon}
+}
+
+// Analyze scores text without leaving the machine
+func (l *Lexicon) Analyze(text string) (Result, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+
+	sum, matched := 0.0, 0
+	for i, word := range words {
+		weight, ok := l.words[word]
+		if !ok {
+			continue
+		}
+		if negated(words, i) {
+			weight = -weight / 2
+		}
+		sum += weight
+		matched++
+	}
+	if matched == 0 {
+		return newResult(0, false), nil
+	}
+
+	// Normalise so a handful of strong words reaches the ends of the scale
+	return newResult(sum/math.Sqrt(sum*sum+8), true), nil
+}
+
+// negated reports whether one of the two words before words[i] negates it
+func negated(words []string, i int) bool {
+	for j := i - 1; j >= 0 && j >= i-2; j-- {
+		if negations[words[j]] {
+			return true
+		}
+	}
+	return false
+}
+
+// AIAnalyzer asks an AI provider for sentiment through AnalyzeStatus, reading
+// a "sentiment_score" (-1 to 1) or "sentiment" label from its response
+type AIAnalyzer struct {
+	provider storage.AIProvider
+}
+
+// NewAIAnalyzer creates an analyzer backed by provider
+func NewAIAnalyzer(provider storage.AIProvider) *AIAnalyzer {
+	return &AIAnalyzer{provider: provider}
+}
+
+// Analyze sends text to the AI provider as a single note
+func (a *AIAnalyzer) Analyze(text string) (Result, error) {
+	analysis, err := a.provider.AnalyzeStatus([]storage.DailyLogEntry{{Type: "note", Description: text}})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to analyze sentiment: %v", err)
+	}
+
+	if score, ok := analysis["sentiment_score"].(float64); ok {
+		return newResult(score, true), nil
+	}
+	if label, _ := analysis["sentiment"].(string); label != "" {
+		if result, ok := FromLabel(label); ok {
+			return result, nil
+		}
+	}
+	return Result{}, fmt.Errorf("AI provider returned no sentiment")
+}
+
+// FromLabel returns the result for a positive, negative, or neutral label,
+// as a model answers
+func FromLabel(label string) (Result, bool) {
+	switch strings.ToLower(strings.Trim(strings.TrimSpace(label), ".!\"'")) {
+	case Positive:
+		return newResult(0.5, true), true
+	case Negative:
+		return newResult(-0.5, true), true
+	case Neutral:
+		return newResult(0, true), true
+	}
+	return Result{}, false
+}
@@ -0,0 +1,256 @@
+package site
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+// maxTags is how many of a month's most used tags its page lists
+const maxTags = 5
+
+// Month is a month of the journal, with its statistics
+type Month struct {
+	Key    string // 2025-09
+	Title  string // September 2025
+	Totals storage.AggregationBucket
+	Tags   []storage.AggregationBucket
+	Days   []storage.DayLog // Oldest first
+}
+
+// Tag is a tag's page. Its days hold only the entries with the tag or one
+// of its children, so "work" also lists entries tagged "work/projectx".
+type Tag struct {
+	Name  string
+	Slug  string
+	Count int
+	Days  []storage.DayLog // Newest first
+}
+
+// Site is the journal to render. It renders the same for the same days, so
+// publishing unchanged days changes nothing.
+type Site struct {
+	Title  string
+	Months []Month // Newest first
+	Tags   []Tag   // By name
+}
+
+// New arranges the entries of days into months and tags. Days without
+// entries are left out.
+func New(title string, days []storage.DayLog) *Site {
+	s := &Site{Title: title}
+
+	days = append([]storage.DayLog(nil), days...)
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+
+	months := make(map[string]*Month)
+	var monthKeys []string
+	tags := make(map[string]*Tag)
+	slugs := make(map[string]string)
+	for _, day := range days {
+		if len(day.Entries) == 0 {
+			continue
+		}
+		entries := append([]storage.DailyLogEntry(nil), day.Entries...)
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+		day.Entries = entries
+
+		key := day.Date.Format("2006-01")
+		month, ok := months[key]
+		if !ok {
+			month = &Month{Key: key, Title: day.Date.Format("January 2006")}
+			months[key] = month
+			monthKeys = append(monthKeys, key)
+		}
+		month.Days = append(month.Days, day)
+
+		tagged := make(map[string][]storage.DailyLogEntry)
+		var names []string
+		for _, entry := range entries {
+			for _, name := range tagNames(entry.Tags) {
+				if _, ok := tagged[name]; !ok {
+					names = append(names, name)
+				}
+				tagged[name] = append(tagged[name], entry)
+			}
+		}
+		for _, name := range names {
+			tag, ok := tags[name]
+			if !ok {
+				tag = &Tag{Name: name, Slug: uniqueSlug(name, slugs)}
+				tags[name] = tag
+			}
+			tag.Count += len(tagged[name])
+			tag.Days = append([]storage.DayLog{{Date: day.Date, Entries: tagged[name]}}, tag.Days...)
+		}
+	}
+
+	for i := len(monthKeys) - 1; i >= 0; i-- {
+		month := months[monthKeys[i]]
+		var entries []storage.DailyLogEntry
+		for _, day := range month.Days {
+			entries = append(entries, day.Entries...)
+		}
+		month.Totals = storage.SummarizeEntries(entries)
+		month.Tags = storage.AggregateEntries(entries, storage.GroupByTag).Buckets
+		if len(month.Tags) > maxTags {
+			month.Tags = month.Tags[:maxTags]
+		}
+		s.Months = append(s.Months, *month)
+	}
+	for _, tag := range tags {
+		s.Tags = append(s.Tags, *tag)
+	}
+	sort.Slice(s.Tags, func(i, j int) bool { return s.Tags[i].Name < s.Tags[j].Name })
+	return s
+}
+
+// tagNames returns tags and their parents, once each
+func tagNames(tags []string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, tag := range tags {
+		for _, name := range storage.TagAncestors(tag) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// slug turns a tag into a file name, e.g. work/projectx into work-projectx
+func slug(tag string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(tag) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	if s := strings.Trim(b.String(), "-"); s != "" {
+		return s
+	}
+	return "tag"
+}
+
+// uniqueSlug returns the slug of tag, numbered if another tag already has it
+func uniqueSlug(tag string, taken map[string]string) string {
+	base := slug(tag)
+	s := base
+	for n := 2; taken[s] != "" && taken[s] != tag; n++ {
+		s = fmt.Sprintf("%s-%d", base, n)
+	}
+	taken[s] = tag
+	return s
+}
+
+// page is the data of a rendered page; Root leads from it back to the site root
+type page struct {
+	*Site
+	Root    string
+	Heading string
+	Month   Month
+	Tag     Tag
+}
+
+// entryList is the data of the "entries" template
+type entryList struct {
+	Root    string
+	Entries []storage.DailyLogEntry
+}
+
+// Render returns the site's files, by path relative to its root
+func (s *Site) Render() (map[string][]byte, error) {
+	slugs := make(map[string]string, len(s.Tags))
+	for _, tag := range s.Tags {
+		slugs[tag.Name] = tag.Slug
+	}
+
+	tmpl, err := template.New("site").Funcs(template.FuncMap{
+		"day":     func(t time.Time) string { return t.Format("Monday, January 2") },
+		"date":    func(t time.Time) string { return t.Format("2006-01-02") },
+		"month":   func(t time.Time) string { return t.Format("2006-01") },
+		"time":    func(t time.Time) string { return t.Format("15:04") },
+		"minutes": formatMinutes,
+		"slug":    func(tag string) string { return slugs[tag] },
+		"entries": func(root string, entries []storage.DailyLogEntry) entryList {
+			return entryList{Root: root, Entries: entries}
+		},
+	}).ParseFS(templateFiles, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse site templates: %v", err)
+	}
+
+	files := make(map[string][]byte)
+	style, err := templateFiles.ReadFile("templates/style.css")
+	if err != nil {
+		return nil, err
+	}
+	files["style.css"] = style
+	// Keeps GitHub Pages from running the site through Jekyll
+	files[".nojekyll"] = []byte{}
+
+	render := func(path, name string, data page) error {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+			return fmt.Errorf("failed to render %s: %v", path, err)
+		}
+		files[path] = buf.Bytes()
+		return nil
+	}
+
+	if err := render("index.html", "index.html", page{Site: s}); err != nil {
+		return nil, err
+	}
+	for _, month := range s.Months {
+		if err := render("months/"+month.Key+".html", "month.html", page{Site: s, Root: "../", Heading: month.Title, Month: month}); err != nil {
+			return nil, err
+		}
+	}
+	for _, tag := range s.Tags {
+		if err := render("tags/"+tag.Slug+".html", "tag.html", page{Site: s, Root: "../", Heading: "#" + tag.Name, Tag: tag}); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Write writes files under dir, creating directories as needed
+func Write(dir string, files map[string][]byte) error {
+	for path, content := range files {
+		target := filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %v", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", target, err)
+		}
+	}
+	return nil
+}
+
+// formatMinutes formats a duration in minutes as e.g. "3h 20m"
+func formatMinutes(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
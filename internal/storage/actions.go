@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MetadataSourceID links a task extracted from an entry back to that entry
+const MetadataSourceID = "source_id"
+
+// DefaultActionPriority is the priority given to extracted tasks, so they
+// count as open tasks
+const DefaultActionPriority = 3
+
+// actionPatterns match lines that hold an action item, capturing its text:
+// "TODO: ...", "- [ ] ...", "Action: ...", "AI: ...", "Follow up: ...", and
+// "Next step: ...", after an optional list bullet
+var actionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)^(?:[-*•]\s*)?(?:todo|action(?: item)?|ai|follow[ -]?up|next steps?)\s*[:\-]\s*(.+)$`),
+	regexp.MustCompile(`^(?:[-*•]\s*)?\[ \]\s*(.+)$`),
+	regexp.MustCompile(`(?i)\bTODO:\s*(.+)$`),
+}
+
+// Action is an action item found in an entry
+type Action struct {
+	Text     string `json:"text" yaml:"text"`
+	SourceID string `json:"source_id" yaml:"source_id"`
+	Source   string `json:"source" yaml:"source"`
+	// Timestamp is the source entry's, which its task is logged at
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	// Visibility is the source entry's, which its task inherits
+	Visibility string `json:"visibility,omitempty" yaml:"visibility,omitempty"`
+}
+
+// ExtractActions finds the action items in entries' descriptions, plus the
+// action items recorded on meetings, in order and without repeats per entry
+func ExtractActions(entries []DailyLogEntry) []Action {
+	var actions []Action
+	for _, entry := range entries {
+		seen := make(map[string]bool)
+		add := func(text string) {
+			text = strings.TrimSpace(text)
+			if text == "" || seen[strings.ToLower(text)] {
+				return
+			}
+			seen[strings.ToLower(text)] = true
+			actions = append(actions, Action{
+				Text:       text,
+				SourceID:   entry.ID,
+				Source:     entry.Title,
+				Timestamp:  entry.Timestamp,
+				Visibility: entry.Visibility,
+			})
+		}
+
+		for _, line := range strings.Split(entry.Description, "\n") {
+			if text, ok := ParseActionLine(line); ok {
+				add(text)
+			}
+		}
+		if entry.Type == EntryTypeMeeting {
+			for _, item := range MeetingOf(entry).ActionItems {
+				add(item)
+			}
+		}
+	}
+	return actions
+}
+
+// ParseActionLine returns the action item on a line, if it holds one
+func ParseActionLine(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	for _, pattern := range actionPatterns {
+		if match := pattern.FindStringSubmatch(line); match != nil {
+			return strings.TrimSpace(match[1]), true
+		}
+	}
+	return "", false
+}
+
+// ActionTaskRequests returns create requests for task notes, one per action
+// that doesn't already have a task linked to its source entry in existing
+func ActionTaskRequests(actions []Action, existing []DailyLogEntry, priority int) []CreateLogEntryRequest {
+	linked := make(map[string]bool)
+	for _, entry := range existing {
+		if key, ok := taskKey(entry); ok {
+			linked[key] = true
+		}
+	}
+
+	var requests []CreateLogEntryRequest
+	for _, action := range actions {
+		key := action.SourceID + "\n" + strings.ToLower(action.Text)
+		if linked[key] {
+			continue
+		}
+		linked[key] = true
+
+		taskPriority := priority
+		requests = append(requests, CreateLogEntryRequest{
+			Date:        action.Timestamp,
+			Type:        "note",
+			Title:       action.Text,
+			Description: "Action item from " + action.Source,
+			Tags:        []string{"task"},
+			Priority:    &taskPriority,
+			Visibility:  action.Visibility,
+			Metadata:    map[string]string{MetadataSourceID: action.SourceID},
+		})
+	}
+	return requests
+}
+
+// OpenTaskLookback is how many days back open tasks are looked for
+const OpenTaskLookback = 7
+
+// OpenTasks collects the open tasks of days: notes with a priority, then
+// meeting action items, each by priority
+func OpenTasks(days []DayLog) []DailyLogEntry {
+	var tasks []DailyLogEntry
+	for _, day := range days {
+		for _, entry := range day.Entries {
+			if entry.Type == "note" && entry.Priority > 0 {
+				tasks = append(tasks, entry)
+			}
+		}
+		tasks = append(tasks, ActionItemTasks(day.Entries)...)
+	}
+	tasks = UniqueTasks(tasks)
+	sort.SliceStable(tasks, func(i, j int) bool { return taskRank(tasks[i]) < taskRank(tasks[j]) })
+	return tasks
+}
+
+// taskRank orders open tasks by priority, with unprioritized action items last
+func taskRank(task DailyLogEntry) int {
+	if task.Priority == 0 {
+		return 6
+	}
+	return task.Priority
+}
+
+// UniqueTasks drops tasks linked to the same source entry with the same
+// title as an earlier one, such as a meeting's action item that has already
+// been extracted into a task note
+func UniqueTasks(tasks []DailyLogEntry) []DailyLogEntry {
+	seen := make(map[string]bool)
+	unique := make([]DailyLogEntry, 0, len(tasks))
+	for _, task := range tasks {
+		if key, ok := taskKey(task); ok {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		unique = append(unique, task)
+	}
+	return unique
+}
+
+// taskKey identifies a task by its source entry and title
+func taskKey(task DailyLogEntry) (string, bool) {
+	source := task.Metadata[MetadataSourceID]
+	if source == "" {
+		return "", false
+	}
+	return source + "\n" + strings.ToLower(task.Title), true
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Aggregation group-by keys
+const (
+	GroupByTag      = "tag"
+	GroupByType     = "type"
+	GroupByDay      = "day"
+	GroupByWeek     = "week"
+	GroupByProject  = "project"
+	GroupByLocation = "location"
+)
+
+// MetadataProject is the entry metadata key holding the project name
+const MetadataProject = "project"
+
+// noValueKey buckets entries without a project or location
+const noValueKey = "(none)"
+
+// AggregationBucket holds the rollup of the entries in one group
+type AggregationBucket struct {
+	Key             string  `json:"key"`
+	Count           int     `json:"count"`
+	TotalDuration   int     `json:"total_duration"` // Minutes
+	AverageStatus   float64 `json:"average_status,omitempty"`
+	AveragePriority float64 `json:"average_priority,omitempty"`
+	// Metrics rolls up the group's metric entries
+	Metrics []MetricStats `json:"metrics,omitempty"`
+
+	statusSum, statusCount     int
+	prioritySum, priorityCount int
+	metricEntries              []DailyLogEntry
+}
+
+// Aggregation is a set of buckets for one group-by key
+type Aggregation struct {
+	GroupBy string              `json:"group_by"`
+	Buckets []AggregationBucket `json:"buckets"`
+}
+
+// ValidateAggregations checks that every group-by key is known
+func ValidateAggregations(groupBys []string) error {
+	for _, groupBy := range groupBys {
+		switch groupBy {
+		case GroupByTag, GroupByType, GroupByDay, GroupByWeek, GroupByProject, GroupByLocation:
+		default:
+			return ValidationError{
+				Field:   "aggregations",
+				Message: fmt.Sprintf("must be one of tag, type, day, week, project, location (got %q)", groupBy),
+			}
+		}
+	}
+	return nil
+}
+
+// SummarizeEntries rolls all entries up into a single bucket
+func SummarizeEntries(entries []DailyLogEntry) AggregationBucket {
+	bucket := AggregationBucket{Key: "all"}
+	for _, entry := range entries {
+		bucket.add(entry)
+	}
+	bucket.finish()
+	return bucket
+}
+
+// AggregateEntries groups entries by the given key and rolls up each group.
+// Entries with several tags count towards each of them, and hierarchical tags
+// also count once towards each parent, so "work" includes "work/projectx".
+// Day and week buckets are ordered chronologically, all others by count.
+func AggregateEntries(entries []DailyLogEntry, groupBy string) Aggregation {
+	buckets := make(map[string]*AggregationBucket)
+	bucketFor := func(key string) *AggregationBucket {
+		if bucket, ok := buckets[key]; ok {
+			return bucket
+		}
+		bucket := &AggregationBucket{Key: key}
+		buckets[key] = bucket
+		return bucket
+	}
+
+	for _, entry := range entries {
+		switch groupBy {
+		case GroupByTag:
+			counted := make(map[string]bool)
+			for _, tag := range entry.Tags {
+				for _, key := range TagAncestors(tag) {
+					if !counted[key] {
+						counted[key] = true
+						bucketFor(key).add(entry)
+					}
+				}
+			}
+		case GroupByType:
+			bucketFor(entry.Type).add(entry)
+		case GroupByDay:
+			bucketFor(entry.Timestamp.Format("2006-01-02")).add(entry)
+		case GroupByWeek:
+			year, week := entry.Timestamp.ISOWeek()
+			bucketFor(fmt.Sprintf("%04d-W%02d", year, week)).add(entry)
+		case GroupByProject:
+			project := entry.Metadata[MetadataProject]
+			if project == "" {
+				project = noValueKey
+			}
+			bucketFor(project).add(entry)
+		case GroupByLocation:
+			location := strings.TrimSpace(entry.Location)
+			if location == "" {
+				location = noValueKey
+			}
+			bucketFor(location).add(entry)
+		}
+	}
+
+	aggregation := Aggregation{
+		GroupBy: groupBy,
+		Buckets: make([]AggregationBucket, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		bucket.finish()
+		aggregation.Buckets = append(aggregation.Buckets, *bucket)
+	}
+
+	sort.Slice(aggregation.Buckets, func(i, j int) bool {
+		a, b := aggregation.Buckets[i], aggregation.Buckets[j]
+		if groupBy != GroupByDay && groupBy != GroupByWeek && a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Key < b.Key
+	})
+
+	return aggregation
+}
+
+func (b *AggregationBucket) add(entry DailyLogEntry) {
+	b.Count++
+	if entry.Duration != nil {
+		b.TotalDuration += *entry.Duration
+	}
+	if entry.Status > 0 {
+		b.statusSum += entry.Status
+		b.statusCount++
+	}
+	if entry.Priority > 0 {
+		b.prioritySum += entry.Priority
+		b.priorityCount++
+	}
+	if entry.Type == EntryTypeMetric {
+		b.metricEntries = append(b.metricEntries, entry)
+	}
+}
+
+func (b *AggregationBucket) finish() {
+	if b.statusCount > 0 {
+		b.AverageStatus = float64(b.statusSum) / float64(b.statusCount)
+	}
+	if b.priorityCount > 0 {
+		b.AveragePriority = float64(b.prioritySum) / float64(b.priorityCount)
+	}
+	if len(b.metricEntries) > 0 {
+		b.Metrics = SummarizeMetrics(b.metricEntries)
+	}
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// TagNonBillable keeps an entry off invoices
+const TagNonBillable = "non-billable"
+
+// BillingRate is the hourly rate of the entries it matches: those of Project,
+// by their project metadata or the project's tags, and with one of Tags, each
+// only if set. A rate with neither matches every entry.
+type BillingRate struct {
+	Project string   `mapstructure:"project" json:"project,omitempty"`
+	Tags    []string `mapstructure:"tags" json:"tags,omitempty"`
+	Rate    float64  `mapstructure:"rate" json:"rate"`
+}
+
+// BillingConfig sets the rates invoices are priced at
+type BillingConfig struct {
+	Currency string        `mapstructure:"currency" json:"currency,omitempty"`
+	Rates    []BillingRate `mapstructure:"rates" json:"rates,omitempty"`
+	// RoundTo rounds each line's time up to a multiple of this many minutes
+	RoundTo int `mapstructure:"round_to" json:"round_to,omitempty"`
+}
+
+// RateFor returns the rate of the first rule the entry matches. Projects
+// named by rules are looked up in projects for their tags.
+func (c BillingConfig) RateFor(entry DailyLogEntry, projects *ProjectList, tags TagAliases) (float64, bool) {
+	for _, rule := range c.Rates {
+		if rule.Project != "" {
+			project := &Project{Name: rule.Project}
+			if projects != nil {
+				if found, ok := projects.Find(rule.Project); ok {
+					project = found
+				}
+			}
+			if !project.Includes(entry, tags) {
+				continue
+			}
+		}
+		if len(rule.Tags) > 0 && !tags.HasAnyTag(entry, rule.Tags) {
+			continue
+		}
+		return rule.Rate, true
+	}
+	return 0, false
+}
+
+// InvoiceLine is a billed item: an entry, or with merging, every entry of
+// the same title at the same rate
+type InvoiceLine struct {
+	Date     string   `json:"date" yaml:"date"` // The first day of a merged line
+	Title    string   `json:"title" yaml:"title"`
+	Minutes  int      `json:"minutes" yaml:"minutes"`
+	Rate     float64  `json:"rate" yaml:"rate"`
+	Amount   float64  `json:"amount" yaml:"amount"`
+	Unrated  bool     `json:"unrated,omitempty" yaml:"unrated,omitempty"`
+	EntryIDs []string `json:"entry_ids" yaml:"entry_ids"`
+}
+
+// Invoice is the billable time of a period, priced line by line
+type Invoice struct {
+	Project  string        `json:"project,omitempty" yaml:"project,omitempty"`
+	Period   string        `json:"period" yaml:"period"`
+	Currency string        `json:"currency,omitempty" yaml:"currency,omitempty"`
+	Lines    []InvoiceLine `json:"lines" yaml:"lines"`
+	Minutes  int           `json:"minutes" yaml:"minutes"`
+	Total    float64       `json:"total" yaml:"total"`
+	// Unrated counts the lines no rate matched, which are billed at 0
+	Unrated int `json:"unrated" yaml:"unrated"`
+}
+
+// BuildInvoice prices the entries with a duration, skipping those tagged
+// non-billable. With merge, entries of the same title and rate are billed
+// as one line.
+func BuildInvoice(entries []DailyLogEntry, config BillingConfig, projects *ProjectList, tags TagAliases, merge bool) Invoice {
+	invoice := Invoice{Currency: config.Currency, Lines: []InvoiceLine{}}
+	merged := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Duration == nil || *entry.Duration <= 0 || tags.HasAnyTag(entry, []string{TagNonBillable}) {
+			continue
+		}
+		rate, rated := config.RateFor(entry, projects, tags)
+		key := fmt.Sprintf("%s\x00%g\x00%t", strings.ToLower(strings.TrimSpace(entry.Title)), rate, rated)
+		if i, ok := merged[key]; ok && merge {
+			invoice.Lines[i].Minutes += *entry.Duration
+			invoice.Lines[i].EntryIDs = append(invoice.Lines[i].EntryIDs, entry.ID)
+			continue
+		}
+		merged[key] = len(invoice.Lines)
+		invoice.Lines = append(invoice.Lines, InvoiceLine{
+			Date:     entry.Timestamp.Format("2006-01-02"),
+			Title:    entry.Title,
+			Minutes:  *entry.Duration,
+			Rate:     rate,
+			Unrated:  !rated,
+			EntryIDs: []string{entry.ID},
+		})
+	}
+
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		if config.RoundTo > 0 {
+			line.Minutes = (line.Minutes + config.RoundTo - 1) / config.RoundTo * config.RoundTo
+		}
+		line.Amount = math.Round(float64(line.Minutes)/60*line.Rate*100) / 100
+		invoice.Minutes += line.Minutes
+		invoice.Total += line.Amount
+		if line.Unrated {
+			invoice.Unrated++
+		}
+	}
+	invoice.Total = math.Round(invoice.Total*100) / 100
+	return invoice
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// CommitConfig shapes the commits made when writing day files
+type CommitConfig struct {
+	// SaveTemplate and DeleteTemplate are text/template commit messages for
+	// saving and deleting a day, executed with CommitData; empty uses the defaults
+	SaveTemplate   string `json:"save_template,omitempty"`
+	DeleteTemplate string `json:"delete_template,omitempty"`
+	// AuthorName and AuthorEmail override the token owner as commit author
+	AuthorName  string `json:"author_name,omitempty"`
+	AuthorEmail string `json:"author_email,omitempty"`
+	// CommitterName and CommitterEmail default to the author
+	CommitterName  string `json:"committer_name,omitempty"`
+	CommitterEmail string `json:"committer_email,omitempty"`
+	// SigningKey, if set, is the key commits are signed with by SigningProgram (gpg if empty)
+	SigningKey     string `json:"signing_key,omitempty"`
+	SigningProgram string `json:"signing_program,omitempty"`
+}
+
+// Validate checks that identities are complete and templates execute
+func (c CommitConfig) Validate() error {
+	if (c.AuthorName == "") != (c.AuthorEmail == "") {
+		return ValidationError{Field: "commit.author", Message: "needs both a name and an email"}
+	}
+	if (c.CommitterName == "") != (c.CommitterEmail == "") {
+		return ValidationError{Field: "commit.committer", Message: "needs both a name and an email"}
+	}
+	if c.SigningKey != "" && c.AuthorName == "" {
+		return ValidationError{Field: "commit.signing_key", Message: "signed commits need commit.author_name and commit.author_email"}
+	}
+	_, err := NewCommitMessages(c)
+	return err
+}
+
+// CommitData is what commit message templates are executed with
+type CommitData struct {
+	Action  string          // "Create", "Update", or "Delete"
+	Date    string          // The day, as 2006-01-02
+	Count   int             // Entries in the day
+	Titles  []string        // Entry titles, in order
+	Types   []string        // Distinct entry types, in order of first appearance
+	Entries []DailyLogEntry // The day's entries
+}
+
+// NewCommitData describes saving dayLog, or deleting it with action "Delete"
+func NewCommitData(action string, dayLog *DayLog) CommitData {
+	data := CommitData{
+		Action:  action,
+		Date:    dayLog.GetDateString(),
+		Count:   len(dayLog.Entries),
+		Titles:  []string{},
+		Types:   []string{},
+		Entries: dayLog.Entries,
+	}
+	seen := make(map[string]bool)
+	for _, entry := range dayLog.Entries {
+		data.Titles = append(data.Titles, entry.Title)
+		if !seen[entry.Type] {
+			seen[entry.Type] = true
+			data.Types = append(data.Types, entry.Type)
+		}
+	}
+	return data
+}
+
+// commitFuncs are the functions available to commit message templates
+var commitFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	// plural formats a count with the singular or plural noun, e.g. plural .Count "entry" "entries"
+	"plural": func(n int, one, many string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, one)
+		}
+		return fmt.Sprintf("%d %s", n, many)
+	},
+}
+
+// Default commit messages, as templates
+const (
+	DefaultSaveTemplate   = "{{.Action}} daily log for {{.Date}}"
+	DefaultDeleteTemplate = "Delete daily log for {{.Date}}"
+)
+
+// CommitMessages renders commit messages from templates
+type CommitMessages struct {
+	save   *template.Template
+	delete *template.Template
+}
+
+// NewCommitMessages parses the templates in config, checking that they
+// execute against a sample day
+func NewCommitMessages(config CommitConfig) (*CommitMessages, error) {
+	save, err := parseCommitTemplate("commit.save_template", config.SaveTemplate, DefaultSaveTemplate)
+	if err != nil {
+		return nil, err
+	}
+	del, err := parseCommitTemplate("commit.delete_template", config.DeleteTemplate, DefaultDeleteTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &CommitMessages{save: save, delete: del}, nil
+}
+
+func parseCommitTemplate(field, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(field).Funcs(commitFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, ValidationError{Field: field, Message: err.Error()}
+	}
+
+	sample := &DayLog{
+		Date:    time.Now(),
+		Entries: []DailyLogEntry{{ID: "sample", Type: "note", Title: "Sample"}},
+	}
+	if _, err := executeCommitTemplate(tmpl, NewCommitData("Update", sample)); err != nil {
+		return nil, ValidationError{Field: field, Message: err.Error()}
+	}
+	return tmpl, nil
+}
+
+// Save returns the commit message for saving dayLog: a create for a day not
+// yet stored, an update otherwise. A nil CommitMessages uses the defaults.
+func (m *CommitMessages) Save(dayLog *DayLog) (string, error) {
+	action := "Update"
+	if dayLog.Revision == "" {
+		action = "Create"
+	}
+	if m == nil {
+		return fmt.Sprintf("%s daily log for %s", action, dayLog.GetDateString()), nil
+	}
+	return executeCommitTemplate(m.save, NewCommitData(action, dayLog))
+}
+
+// Delete returns the commit message for deleting dayLog. A nil
+// CommitMessages uses the default.
+func (m *CommitMessages) Delete(dayLog *DayLog) (string, error) {
+	if m == nil {
+		return fmt.Sprintf("Delete daily log for %s", dayLog.GetDateString()), nil
+	}
+	return executeCommitTemplate(m.delete, NewCommitData("Delete", dayLog))
+}
+
+func executeCommitTemplate(tmpl *template.Template, data CommitData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	message := strings.TrimSpace(buf.String())
+	if message == "" {
+		return "", fmt.Errorf("%s produced an empty commit message", tmpl.Name())
+	}
+	return message, nil
+}
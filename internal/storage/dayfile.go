@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Formats a day log can be stored in
+const (
+	DayFormatPretty  = "pretty"  // Indented JSON in a .json file
+	DayFormatCompact = "compact" // Single-line JSON in a .json file
+	DayFormatGzip    = "gzip"    // Compact JSON gzip-compressed in a .json.gz file
+)
+
+// Day file name extensions
+const (
+	dayFileExt     = ".json"
+	gzipDayFileExt = ".json.gz"
+)
+
+// ValidateDayFormat checks a day file format; empty means pretty
+func ValidateDayFormat(format string) error {
+	switch format {
+	case "", DayFormatPretty, DayFormatCompact, DayFormatGzip:
+		return nil
+	default:
+		return ValidationError{
+			Field:   "format",
+			Message: fmt.Sprintf("unknown day file format %q (use pretty, compact, or gzip)", format),
+		}
+	}
+}
+
+// DayFileName returns the name of the file holding date's log in format
+func DayFileName(date time.Time, format string) string {
+	if format == DayFormatGzip {
+		return date.Format("2006-01-02") + gzipDayFileExt
+	}
+	return date.Format("2006-01-02") + dayFileExt
+}
+
+// ParseDayFileName returns the day a day file in any format is for
+func ParseDayFileName(name string) (time.Time, bool) {
+	day, found := strings.CutSuffix(name, gzipDayFileExt)
+	if !found {
+		if day, found = strings.CutSuffix(name, dayFileExt); !found {
+			return time.Time{}, false
+		}
+	}
+	date, err := time.Parse("2006-01-02", day)
+	return date, err == nil
+}
+
+// EncodeDay serializes a day log in format
+func EncodeDay(dayLog *DayLog, format string) ([]byte, error) {
+	switch format {
+	case DayFormatCompact:
+		return json.Marshal(dayLog)
+	case DayFormatGzip:
+		data, err := json.Marshal(dayLog)
+		if err != nil {
+			return nil, err
+		}
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write(data); err != nil {
+			return nil, err
+		}
+		if err := writer.Close(); err != nil {
+			return nil, err
+		}
+		return compressed.Bytes(), nil
+	default:
+		return dayLog.ToJSON()
+	}
+}
+
+// DecodeDay parses a day file in any format and returns it with the format
+// it was stored in
+func DecodeDay(data []byte) (*DayLog, string, error) {
+	format := DayFormatPretty
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, "", err
+		}
+		if data, err = io.ReadAll(reader); err != nil {
+			return nil, "", err
+		}
+		format = DayFormatGzip
+	} else if !bytes.Contains(bytes.TrimSpace(data), []byte("\n")) {
+		format = DayFormatCompact
+	}
+
+	var dayLog DayLog
+	if err := dayLog.FromJSON(data); err != nil {
+		return nil, "", err
+	}
+	return &dayLog, format, nil
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"slices"
+	"sort"
+)
+
+// PeriodDiff compares the entries of two days or periods, before and after
+type PeriodDiff struct {
+	Added     []DailyLogEntry `json:"added" yaml:"added"`
+	Removed   []DailyLogEntry `json:"removed" yaml:"removed"`
+	Changed   []EntryChange   `json:"changed" yaml:"changed"`
+	Unchanged int             `json:"unchanged" yaml:"unchanged"`
+	// Status averages are 0 for a period without status ratings
+	StatusBefore  float64           `json:"status_before" yaml:"status_before"`
+	StatusAfter   float64           `json:"status_after" yaml:"status_after"`
+	MinutesBefore int               `json:"minutes_before" yaml:"minutes_before"`
+	MinutesAfter  int               `json:"minutes_after" yaml:"minutes_after"`
+	GroupBy       string            `json:"group_by" yaml:"group_by"`
+	Allocation    []AllocationShift `json:"allocation" yaml:"allocation"`
+}
+
+// EntryChange is an entry logged in both periods with different details
+type EntryChange struct {
+	Before DailyLogEntry `json:"before" yaml:"before"`
+	After  DailyLogEntry `json:"after" yaml:"after"`
+	// Fields are the details that differ, such as "status" or "tags"
+	Fields []string `json:"fields" yaml:"fields"`
+}
+
+// AllocationShift is the time tracked for one group in each period
+type AllocationShift struct {
+	Key    string `json:"key" yaml:"key"`
+	Before int    `json:"before" yaml:"before"` // Minutes
+	After  int    `json:"after" yaml:"after"`   // Minutes
+	Delta  int    `json:"delta" yaml:"delta"`   // Minutes
+}
+
+// DiffEntries compares the entries of two periods. Entries of the same type
+// and title, ignoring case, are the same entry, paired in time order; those
+// with a different status, priority, duration, location, tags, or
+// description have changed. Time is allocated by groupBy, as for
+// AggregateEntries, with the largest shifts first.
+func DiffEntries(before, after []DailyLogEntry, groupBy string) PeriodDiff {
+	diff := PeriodDiff{
+		Added:   []DailyLogEntry{},
+		Removed: []DailyLogEntry{},
+		Changed: []EntryChange{},
+		GroupBy: groupBy,
+	}
+	before = append([]DailyLogEntry(nil), before...)
+	after = append([]DailyLogEntry(nil), after...)
+	SortEntries(before, DefaultSortOrder)
+	SortEntries(after, DefaultSortOrder)
+
+	// Entries before by type and title, in order, to be taken by entries after
+	earlier := make(map[string][]int)
+	for i, entry := range before {
+		key := diffKey(entry)
+		earlier[key] = append(earlier[key], i)
+	}
+	matched := make(map[int]bool)
+	for _, entry := range after {
+		key := diffKey(entry)
+		if len(earlier[key]) == 0 {
+			diff.Added = append(diff.Added, entry)
+			continue
+		}
+		i := earlier[key][0]
+		earlier[key] = earlier[key][1:]
+		matched[i] = true
+		if fields := changedFields(before[i], entry); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, EntryChange{Before: before[i], After: entry, Fields: fields})
+		} else {
+			diff.Unchanged++
+		}
+	}
+	for i, entry := range before {
+		if !matched[i] {
+			diff.Removed = append(diff.Removed, entry)
+		}
+	}
+
+	totalBefore, totalAfter := SummarizeEntries(before), SummarizeEntries(after)
+	diff.StatusBefore, diff.StatusAfter = totalBefore.AverageStatus, totalAfter.AverageStatus
+	diff.MinutesBefore, diff.MinutesAfter = totalBefore.TotalDuration, totalAfter.TotalDuration
+	diff.Allocation = allocationShifts(AggregateEntries(before, groupBy), AggregateEntries(after, groupBy))
+	return diff
+}
+
+// diffKey identifies an entry across periods by its type and title
+func diffKey(entry DailyLogEntry) string {
+	return entry.Type + "\x00" + planKey(entry.Title)
+}
+
+// changedFields lists the details that differ between two entries
+func changedFields(before, after DailyLogEntry) []string {
+	var fields []string
+	if before.Status != after.Status {
+		fields = append(fields, "status")
+	}
+	if before.Priority != after.Priority {
+		fields = append(fields, "priority")
+	}
+	if durationMinutes(before) != durationMinutes(after) {
+		fields = append(fields, "duration")
+	}
+	if before.Location != after.Location {
+		fields = append(fields, "location")
+	}
+	beforeTags, afterTags := slices.Clone(before.Tags), slices.Clone(after.Tags)
+	slices.Sort(beforeTags)
+	slices.Sort(afterTags)
+	if !slices.Equal(beforeTags, afterTags) {
+		fields = append(fields, "tags")
+	}
+	if before.Description != after.Description {
+		fields = append(fields, "description")
+	}
+	return fields
+}
+
+// durationMinutes is an entry's duration, 0 if it has none
+func durationMinutes(entry DailyLogEntry) int {
+	if entry.Duration == nil {
+		return 0
+	}
+	return *entry.Duration
+}
+
+// allocationShifts pairs the tracked time of each group in two aggregations
+func allocationShifts(before, after Aggregation) []AllocationShift {
+	shifts := make(map[string]*AllocationShift)
+	shiftFor := func(key string) *AllocationShift {
+		if shift, ok := shifts[key]; ok {
+			return shift
+		}
+		shift := &AllocationShift{Key: key}
+		shifts[key] = shift
+		return shift
+	}
+	for _, bucket := range before.Buckets {
+		if bucket.TotalDuration > 0 {
+			shiftFor(bucket.Key).Before = bucket.TotalDuration
+		}
+	}
+	for _, bucket := range after.Buckets {
+		if bucket.TotalDuration > 0 {
+			shiftFor(bucket.Key).After = bucket.TotalDuration
+		}
+	}
+
+	result := make([]AllocationShift, 0, len(shifts))
+	for _, shift := range shifts {
+		shift.Delta = shift.After - shift.Before
+		result = append(result, *shift)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := abs(result[i].Delta), abs(result[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// abs is the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"strings"
+	"time"
+)
+
+// Metadata keys that identify where an entry came from
+const (
+	MetadataSource     = "source"
+	MetadataExternalID = "external_id"
+)
+
+// DefaultDuplicateWindow is how close in time two entries with the same
+// title must be to count as duplicates
+const DefaultDuplicateWindow = 15 * time.Minute
+
+// DuplicateError reports that an entry was not created because it duplicates Existing
+type DuplicateError struct {
+	Existing DailyLogEntry
+}
+
+func (e DuplicateError) Error() string {
+	return "duplicate of existing entry " + e.Existing.ID + ": " + e.Existing.Title
+}
+
+// FindDuplicate returns the entry in entries that req would duplicate, or nil.
+// An entry is a duplicate when it has the same source and external ID, or
+// the same type and title (ignoring case and spacing) and a timestamp within
+// window of the request's. A window of 0 or less matches anywhere on the day.
+func FindDuplicate(entries []DailyLogEntry, req CreateLogEntryRequest, window time.Duration) *DailyLogEntry {
+	if externalID := req.Metadata[MetadataExternalID]; externalID != "" {
+		for i, entry := range entries {
+			if entry.Metadata[MetadataExternalID] == externalID &&
+				entry.Metadata[MetadataSource] == req.Metadata[MetadataSource] {
+				return &entries[i]
+			}
+		}
+	}
+
+	title := normalizeTitle(req.Title)
+	if title == "" {
+		return nil
+	}
+	for i, entry := range entries {
+		if entry.Type != req.Type || normalizeTitle(entry.Title) != title {
+			continue
+		}
+		if window <= 0 || absDuration(entry.Timestamp.Sub(req.Date)) <= window {
+			return &entries[i]
+		}
+	}
+	return nil
+}
+
+// normalizeTitle lowercases a title and collapses its whitespace
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,177 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Focus categories of the default rules
+const (
+	FocusDeepWork = "deep_work"
+	FocusMeetings = "meetings"
+	FocusAdmin    = "admin"
+	FocusBreaks   = "breaks"
+)
+
+// FocusRule puts the entries it matches in a category. An entry matches when
+// it has one of Tags, is one of Types, and lasts from MinDuration to
+// MaxDuration minutes, each only if set.
+type FocusRule struct {
+	Category    string   `mapstructure:"category" json:"category"`
+	Tags        []string `mapstructure:"tags" json:"tags,omitempty"`
+	Types       []string `mapstructure:"types" json:"types,omitempty"`
+	MinDuration int      `mapstructure:"min_duration" json:"min_duration,omitempty"`
+	MaxDuration int      `mapstructure:"max_duration" json:"max_duration,omitempty"`
+}
+
+// FocusRules classify entries by the first rule they match. Categories
+// other than the standard four are reported as they are.
+type FocusRules []FocusRule
+
+// DefaultFocusRules count meetings, tagged admin and breaks, and activities
+// of 45 minutes or more as deep work, shorter ones as admin
+var DefaultFocusRules = FocusRules{
+	{Category: FocusBreaks, Tags: []string{"break", "lunch", "personal"}},
+	{Category: FocusMeetings, Types: []string{EntryTypeMeeting}},
+	{Category: FocusMeetings, Tags: []string{"meeting"}},
+	{Category: FocusAdmin, Tags: []string{"admin", "email"}},
+	{Category: FocusDeepWork, Tags: []string{"focus", "deep-work"}},
+	{Category: FocusDeepWork, Types: []string{"activity"}, MinDuration: 45},
+	{Category: FocusAdmin, Types: []string{"activity"}},
+}
+
+// Classify returns the category of the first rule the entry matches, or ""
+func (r FocusRules) Classify(entry DailyLogEntry, tags TagAliases) string {
+	duration := 0
+	if entry.Duration != nil {
+		duration = *entry.Duration
+	}
+	for _, rule := range r {
+		if len(rule.Tags) > 0 && !tags.HasAnyTag(entry, rule.Tags) {
+			continue
+		}
+		if len(rule.Types) > 0 && !containsFold(rule.Types, entry.Type) {
+			continue
+		}
+		if (rule.MinDuration > 0 && duration < rule.MinDuration) || (rule.MaxDuration > 0 && duration > rule.MaxDuration) {
+			continue
+		}
+		return rule.Category
+	}
+	return ""
+}
+
+// FocusScore is the tracked time in each focus category over a period
+type FocusScore struct {
+	Period  string         `json:"period"`  // The day, e.g. 2025-09-29, the ISO week, e.g. 2025-W40, or "all"
+	Minutes map[string]int `json:"minutes"` // By category
+	// Ratio is deep work's share of the time outside breaks, 0-1
+	Ratio float64 `json:"ratio"`
+}
+
+// FocusReport is the focus over a period, and on each day and week of it
+type FocusReport struct {
+	FocusScore `yaml:",inline"`
+	Days       []FocusScore `json:"days"`
+	Weeks      []FocusScore `json:"weeks"`
+}
+
+// Score classifies the entries with a duration and totals their time, or
+// returns nil when none has a category
+func (r FocusRules) Score(entries []DailyLogEntry, tags TagAliases) *FocusReport {
+	total := FocusScore{Period: "all", Minutes: make(map[string]int)}
+	days := make(map[string]*FocusScore)
+	weeks := make(map[string]*FocusScore)
+	scoreFor := func(scores map[string]*FocusScore, period string) *FocusScore {
+		if score, ok := scores[period]; ok {
+			return score
+		}
+		score := &FocusScore{Period: period, Minutes: make(map[string]int)}
+		scores[period] = score
+		return score
+	}
+
+	for _, entry := range entries {
+		if entry.Duration == nil || *entry.Duration <= 0 {
+			continue
+		}
+		category := r.Classify(entry, tags)
+		if category == "" {
+			continue
+		}
+		year, week := entry.Timestamp.ISOWeek()
+		for _, score := range []*FocusScore{
+			&total,
+			scoreFor(days, entry.Timestamp.Format("2006-01-02")),
+			scoreFor(weeks, fmt.Sprintf("%04d-W%02d", year, week)),
+		} {
+			score.Minutes[category] += *entry.Duration
+		}
+	}
+	if len(total.Minutes) == 0 {
+		return nil
+	}
+
+	total.finish()
+	return &FocusReport{FocusScore: total, Days: sortedScores(days), Weeks: sortedScores(weeks)}
+}
+
+// WorkMinutes is the time outside breaks
+func (s FocusScore) WorkMinutes() int {
+	minutes := 0
+	for category, m := range s.Minutes {
+		if category != FocusBreaks {
+			minutes += m
+		}
+	}
+	return minutes
+}
+
+// String describes the score, e.g. "Focus: 62% deep work, 3h meetings, 1.5h admin."
+func (s FocusScore) String() string {
+	parts := []string{fmt.Sprintf("%.0f%% deep work", s.Ratio*100)}
+	categories := make([]string, 0, len(s.Minutes))
+	for category := range s.Minutes {
+		if category != FocusDeepWork {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		parts = append(parts, fmt.Sprintf("%sh %s", formatHours(s.Minutes[category]), strings.ReplaceAll(category, "_", " ")))
+	}
+	return "Focus: " + strings.Join(parts, ", ") + "."
+}
+
+func (s *FocusScore) finish() {
+	if work := s.WorkMinutes(); work > 0 {
+		s.Ratio = float64(s.Minutes[FocusDeepWork]) / float64(work)
+	}
+}
+
+// sortedScores finishes the scores and orders them by period
+func sortedScores(scores map[string]*FocusScore) []FocusScore {
+	sorted := make([]FocusScore, 0, len(scores))
+	for _, score := range scores {
+		score.finish()
+		sorted = append(sorted, *score)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Period < sorted[j].Period })
+	return sorted
+}
+
+// formatHours writes minutes as hours, e.g. "1.5"
+func formatHours(minutes int) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", float64(minutes)/60), ".0")
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Kinds of problem found in stored day files
+const (
+	ProblemMalformed     = "malformed"      // The file can't be read as a day log
+	ProblemWrongPath     = "wrong_path"     // The file isn't where the layout and its format put it
+	ProblemDuplicateDay  = "duplicate_day"  // Another file holds the same day
+	ProblemDuplicateID   = "duplicate_id"   // An entry ID is missing or used more than once
+	ProblemDate          = "date"           // The date inside doesn't match the file name
+	ProblemTotalEntries  = "total_entries"  // total_entries doesn't count the entries
+	ProblemStatusAverage = "status_average" // status_average doesn't match the entries
+)
+
+// DayProblem is something wrong with a stored day log
+type DayProblem struct {
+	Kind    string `json:"kind" yaml:"kind"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// CheckDayLog lists what's inconsistent within the log of date: its date
+// and the totals derived from its entries
+func CheckDayLog(dayLog *DayLog, date time.Time) []DayProblem {
+	var problems []DayProblem
+	if got, want := dayLog.Date.Format("2006-01-02"), date.Format("2006-01-02"); got != want {
+		problems = append(problems, DayProblem{ProblemDate, fmt.Sprintf("date is %s, not %s", got, want)})
+	}
+	if dayLog.TotalEntries != len(dayLog.Entries) {
+		problems = append(problems, DayProblem{ProblemTotalEntries,
+			fmt.Sprintf("total_entries is %d, but there are %d entries", dayLog.TotalEntries, len(dayLog.Entries))})
+	}
+	expected := *dayLog
+	expected.calculateStatusAverage()
+	if math.Abs(expected.StatusAverage-dayLog.StatusAverage) > 1e-9 {
+		problems = append(problems, DayProblem{ProblemStatusAverage,
+			fmt.Sprintf("status_average is %.2f, but the entries average %.2f", dayLog.StatusAverage, expected.StatusAverage)})
+	}
+	return problems
+}
+
+// RepairDayLog sets the date of the log of date and recalculates its totals
+func RepairDayLog(dayLog *DayLog, date time.Time) {
+	if dayLog.Date.Format("2006-01-02") != date.Format("2006-01-02") {
+		dayLog.Date = date
+	}
+	if dayLog.Entries == nil {
+		dayLog.Entries = []DailyLogEntry{}
+	}
+	sort.SliceStable(dayLog.Entries, func(i, j int) bool {
+		return dayLog.Entries[i].Timestamp.Before(dayLog.Entries[j].Timestamp)
+	})
+	dayLog.TotalEntries = len(dayLog.Entries)
+	dayLog.calculateStatusAverage()
+}
+
+// RepairEntryIDs makes entry IDs unique across days, taken in order: an
+// entry identical to an earlier one with its ID is dropped as a copy, and any
+// other entry without an ID or reusing one gets a new ID. It returns the
+// problems it repaired by the index of their day.
+func RepairEntryIDs(days []*DayLog) map[int][]DayProblem {
+	problems := make(map[int][]DayProblem)
+	seen := make(map[string]DailyLogEntry)
+	for i, dayLog := range days {
+		kept := dayLog.Entries[:0]
+		for _, entry := range dayLog.Entries {
+			first, reused := seen[entry.ID]
+			switch {
+			case entry.ID == "":
+				entry.ID = NewEntryIDAt(entry.Timestamp)
+				problems[i] = append(problems[i], DayProblem{ProblemDuplicateID,
+					fmt.Sprintf("entry %q has no ID; given %s", entry.Title, entry.ID)})
+			case reused && reflect.DeepEqual(entry, first):
+				problems[i] = append(problems[i], DayProblem{ProblemDuplicateID,
+					fmt.Sprintf("entry %s is a copy of an earlier one; dropped", entry.ID)})
+				continue
+			case reused:
+				id := NewEntryIDAt(entry.Timestamp)
+				problems[i] = append(problems[i], DayProblem{ProblemDuplicateID,
+					fmt.Sprintf("entry %s %q reuses an earlier ID; given %s", entry.ID, entry.Title, id)})
+				entry.ID = id
+			}
+			seen[entry.ID] = entry
+			kept = append(kept, entry)
+		}
+		dayLog.Entries = kept
+	}
+	return problems
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry IDs are ULIDs: 26 Crockford base32 characters holding a 48-bit
+// millisecond timestamp followed by 80 random bits. They sort by time and
+// don't collide across machines. The timestamp is the entry's own time, so
+// an ID prefix narrows down which days can hold the entry.
+//
+// Older entries have IDs of the form entry_<unix nanoseconds>, which remain
+// valid everywhere.
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+const (
+	ulidLength     = 26
+	ulidTimeLength = 10
+	legacyIDPrefix = "entry_"
+)
+
+// ShortIDLength is the shortest prefix shown for an entry ID
+const ShortIDLength = 8
+
+// maxPrefixSpan bounds the time range an ID prefix may cover, so resolving
+// it never scans more than a week of days
+const maxPrefixSpan = 7 * 24 * time.Hour
+
+// NewEntryID generates a new entry ID for an entry made now
+func NewEntryID() string {
+	return NewEntryIDAt(time.Now())
+}
+
+// NewEntryIDAt generates a new entry ID for an entry at t
+func NewEntryIDAt(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	var id [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 0; i < 6; i++ {
+		id[i] = byte(ms >> (8 * (5 - i)))
+	}
+	if _, err := rand.Read(id[6:]); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to the clock
+		nanos := uint64(time.Now().UnixNano())
+		for i := 6; i < 16; i++ {
+			id[i] = byte(nanos >> (8 * (i % 8)))
+		}
+	}
+
+	// 128 bits in 26 characters of 5 bits, with two leading zero bits
+	var encoded [ulidLength]byte
+	for i := range encoded {
+		value := 0
+		for bit := i * 5; bit < i*5+5; bit++ {
+			value <<= 1
+			if pos := bit - 2; pos >= 0 && id[pos/8]&(0x80>>(pos%8)) != 0 {
+				value |= 1
+			}
+		}
+		encoded[i] = crockfordAlphabet[value]
+	}
+	return string(encoded[:])
+}
+
+// IsLegacyEntryID reports whether id uses the older entry_<unix nanoseconds> form
+func IsLegacyEntryID(id string) bool {
+	return strings.HasPrefix(id, legacyIDPrefix)
+}
+
+// EntryIDTime returns the time encoded in an entry ID: the entry's time for
+// ULIDs, and its creation time for legacy IDs
+func EntryIDTime(id string) (time.Time, bool) {
+	start, _, ok := idPrefixRange(id)
+	return start, ok
+}
+
+// ShortEntryIDs maps each ID to its shortest prefix of at least ShortIDLength
+// characters that no other ID in the list shares. Legacy IDs are kept whole.
+func ShortEntryIDs(ids []string) map[string]string {
+	short := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if IsLegacyEntryID(id) || len(id) <= ShortIDLength {
+			short[id] = id
+			continue
+		}
+		length := ShortIDLength
+		for _, other := range ids {
+			if other == id {
+				continue
+			}
+			for length < len(id) && strings.HasPrefix(other, id[:length]) {
+				length++
+			}
+		}
+		short[id] = id[:length]
+	}
+	return short
+}
+
+// ResolveEntryID returns the one entry whose ID is id or starts with it
+func ResolveEntryID(id string, entries []DailyLogEntry) (*DailyLogEntry, error) {
+	id = normalizeEntryID(id)
+
+	var matches []DailyLogEntry
+	for _, entry := range entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+		if strings.HasPrefix(entry.ID, id) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, NotFoundError{Resource: "log entry", ID: id}
+	case 1:
+		return &matches[0], nil
+	default:
+		var ids []string
+		for _, match := range matches {
+			ids = append(ids, match.ID)
+		}
+		return nil, ValidationError{
+			Field:   "id",
+			Message: fmt.Sprintf("%q matches %d entries (%s); use a longer prefix", id, len(matches), strings.Join(ids, ", ")),
+		}
+	}
+}
+
+// FindEntry looks up an entry by its full ID or an ID prefix such as
+// "01J3F2", reading only the days the ID's timestamp allows. A prefix must be
+// long enough to narrow the search to a week. Legacy IDs hold the creation
+// time, so a backdated legacy entry is only found with its date and GetEntry.
+func FindEntry(store DailyLogStorage, id string) (*DailyLogEntry, error) {
+	id = normalizeEntryID(id)
+	start, end, ok := idPrefixRange(id)
+	if !ok {
+		return nil, ValidationError{Field: "id", Message: fmt.Sprintf("%q is not an entry ID or ID prefix", id)}
+	}
+	if end.Sub(start) > maxPrefixSpan {
+		return nil, ValidationError{Field: "id", Message: fmt.Sprintf("prefix %q is too short; use at least %d characters", id, minPrefixLength(id))}
+	}
+
+	// The stored day follows the entry's own time zone, so allow a day either side
+	var candidates []DailyLogEntry
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -1)
+	for d := first; !d.After(end.AddDate(0, 0, 1)); d = d.AddDate(0, 0, 1) {
+		dayLog, err := store.GetDay(d)
+		if err != nil {
+			continue // Skip days that don't exist
+		}
+		candidates = append(candidates, dayLog.Entries...)
+	}
+
+	return ResolveEntryID(id, candidates)
+}
+
+// minPrefixLength returns the length of the shortest prefix of an ID of
+// id's form that FindEntry takes, narrowing the search to maxPrefixSpan
+func minPrefixLength(id string) int {
+	if IsLegacyEntryID(id) {
+		span := time.Duration(1)
+		digits := 19
+		for span*10 <= maxPrefixSpan {
+			span *= 10
+			digits--
+		}
+		return len(legacyIDPrefix) + digits
+	}
+	length := ulidTimeLength
+	for length > 1 && time.Duration(1)<<(5*(ulidTimeLength-length+1))*time.Millisecond <= maxPrefixSpan {
+		length--
+	}
+	return length
+}
+
+// normalizeEntryID uppercases ULIDs, which are case-insensitive
+func normalizeEntryID(id string) string {
+	id = strings.TrimSpace(id)
+	if IsLegacyEntryID(id) {
+		return id
+	}
+	return strings.ToUpper(id)
+}
+
+// idPrefixRange returns the earliest and latest time an ID starting with
+// prefix can encode
+func idPrefixRange(prefix string) (time.Time, time.Time, bool) {
+	if IsLegacyEntryID(prefix) {
+		digits := strings.TrimPrefix(prefix, legacyIDPrefix)
+		if digits == "" || len(digits) > 19 {
+			return time.Time{}, time.Time{}, false
+		}
+		low, err1 := strconv.ParseInt(digits+strings.Repeat("0", 19-len(digits)), 10, 64)
+		high, err2 := strconv.ParseInt(digits+strings.Repeat("9", 19-len(digits)), 10, 64)
+		if err1 != nil || err2 != nil {
+			return time.Time{}, time.Time{}, false
+		}
+		return time.Unix(0, low), time.Unix(0, high), true
+	}
+
+	prefix = strings.ToUpper(prefix)
+	if prefix == "" || len(prefix) > ulidLength || prefix[0] > '7' {
+		return time.Time{}, time.Time{}, false
+	}
+	for _, c := range prefix {
+		if !strings.ContainsRune(crockfordAlphabet, c) {
+			return time.Time{}, time.Time{}, false
+		}
+	}
+
+	timePart := prefix
+	if len(timePart) > ulidTimeLength {
+		timePart = timePart[:ulidTimeLength]
+	}
+	padding := ulidTimeLength - len(timePart)
+	low := decodeCrockford(timePart + strings.Repeat("0", padding))
+	high := decodeCrockford(timePart + strings.Repeat("Z", padding))
+	return time.UnixMilli(int64(low)), time.UnixMilli(int64(high)), true
+}
+
+// decodeCrockford decodes Crockford base32 characters into a number
+func decodeCrockford(s string) uint64 {
+	var value uint64
+	for _, c := range s {
+		value = value<<5 | uint64(strings.IndexRune(crockfordAlphabet, c))
+	}
+	return value
+}
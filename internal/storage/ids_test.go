@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewEntryIDAt(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{name: "epoch", at: time.UnixMilli(0), want: "0000000000"},
+		{name: "ULID spec example", at: time.UnixMilli(1469918176385), want: "01ARYZ6S41"},
+		{name: "latest time", at: time.UnixMilli(1<<48 - 1), want: "7ZZZZZZZZZ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id := NewEntryIDAt(tt.at)
+			if len(id) != ulidLength || !strings.HasPrefix(id, tt.want) {
+				t.Fatalf("NewEntryIDAt = %s, want %d characters starting %s", id, ulidLength, tt.want)
+			}
+			for _, c := range id {
+				if !strings.ContainsRune(crockfordAlphabet, c) {
+					t.Errorf("%s has %q, not a Crockford base32 character", id, c)
+				}
+			}
+			if got, ok := EntryIDTime(id); !ok || !got.Equal(tt.at) {
+				t.Errorf("EntryIDTime(%s) = %v, %v, want %v", id, got, ok, tt.at)
+			}
+		})
+	}
+}
+
+func TestNewEntryIDAtOrder(t *testing.T) {
+	at := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	first, again, later := NewEntryIDAt(at), NewEntryIDAt(at), NewEntryIDAt(at.Add(time.Millisecond))
+	if first == again {
+		t.Errorf("two IDs at the same time are both %s", first)
+	}
+	if first[:ulidTimeLength] != again[:ulidTimeLength] {
+		t.Errorf("IDs at the same time %s and %s have different timestamps", first, again)
+	}
+	if later <= first || later <= again {
+		t.Errorf("ID a millisecond later %s does not sort after %s and %s", later, first, again)
+	}
+}
+
+func TestIDPrefixRange(t *testing.T) {
+	spec := time.UnixMilli(1469918176385)
+	tests := []struct {
+		name      string
+		prefix    string
+		low, high time.Time
+		ok        bool
+	}{
+		{name: "whole timestamp", prefix: "01ARYZ6S41", low: spec, high: spec, ok: true},
+		{name: "whole ID", prefix: "01ARYZ6S41TSV4RRFFQ69G5FAV", low: spec, high: spec, ok: true},
+		{name: "lowercase", prefix: "01aryz6s41", low: spec, high: spec, ok: true},
+		{
+			name: "five characters", prefix: "01ARY",
+			low: time.UnixMilli(1469918176385 &^ (1<<25 - 1)), high: time.UnixMilli(1469918176385 | (1<<25 - 1)), ok: true,
+		},
+		{name: "legacy", prefix: "entry_1741078800", low: time.Unix(1741078800, 0), high: time.Unix(1741078800, 999999999), ok: true},
+		{name: "empty", prefix: ""},
+		{name: "past the last timestamp", prefix: "8"},
+		{name: "not Crockford base32", prefix: "01ARU"},
+		{name: "too long", prefix: "01ARYZ6S41TSV4RRFFQ69G5FAV0"},
+		{name: "legacy without digits", prefix: "entry_"},
+		{name: "legacy not digits", prefix: "entry_17x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low, high, ok := idPrefixRange(tt.prefix)
+			if ok != tt.ok {
+				t.Fatalf("idPrefixRange(%q) ok = %v, want %v", tt.prefix, ok, tt.ok)
+			}
+			if ok && (!low.Equal(tt.low) || !high.Equal(tt.high)) {
+				t.Errorf("idPrefixRange(%q) = %v to %v, want %v to %v", tt.prefix, low, high, tt.low, tt.high)
+			}
+		})
+	}
+}
+
+// dayReader serves days from memory and records which it was asked for
+type dayReader struct {
+	DailyLogStorage
+
+	days map[string][]DailyLogEntry
+	read []string
+}
+
+func (r *dayReader) GetDay(date time.Time) (*DayLog, error) {
+	key := date.Format("2006-01-02")
+	r.read = append(r.read, key)
+	return &DayLog{Date: date, Entries: r.days[key]}, nil
+}
+
+func TestFindEntry(t *testing.T) {
+	at := time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+	standup := DailyLogEntry{ID: NewEntryIDAt(at), Title: "Standup"}
+	retro := DailyLogEntry{ID: NewEntryIDAt(at), Title: "Retro"}
+	review := DailyLogEntry{ID: NewEntryIDAt(at.Add(time.Minute)), Title: "Review"}
+	legacy := DailyLogEntry{ID: "entry_1740990000000000000", Title: "Legacy"}
+	store := &dayReader{days: map[string][]DailyLogEntry{
+		at.Format("2006-01-02"):                       {standup, retro, review},
+		time.Unix(1740990000, 0).Format("2006-01-02"): {legacy},
+	}}
+
+	tests := []struct {
+		name  string
+		id    string
+		want  string
+		error string
+	}{
+		{name: "whole ID", id: standup.ID, want: "Standup"},
+		{name: "lowercase", id: strings.ToLower(review.ID), want: "Review"},
+		{name: "unique prefix", id: review.ID[:20], want: "Review"},
+		{name: "ambiguous prefix", id: standup.ID[:ulidTimeLength], error: "matches 2 entries"},
+		{name: "not found", id: standup.ID[:10] + "0000000000000000", error: "not found"},
+		{name: "too short", id: standup.ID[:4], error: "use at least 5 characters"},
+		{name: "shortest prefix", id: standup.ID[:5], error: "matches 3 entries"},
+		{name: "legacy", id: legacy.ID, want: "Legacy"},
+		{name: "legacy too short", id: "entry_1741", error: "use at least 11 characters"},
+		{name: "not an ID", id: "standup", error: "not an entry ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.read = nil
+			entry, err := FindEntry(store, tt.id)
+			if tt.error != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.error) {
+					t.Fatalf("FindEntry(%q) err = %v, want %q", tt.id, err, tt.error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindEntry(%q): %v", tt.id, err)
+			}
+			if entry.Title != tt.want {
+				t.Errorf("FindEntry(%q) = %s, want %s", tt.id, entry.Title, tt.want)
+			}
+			if len(store.read) > 3 {
+				t.Errorf("FindEntry(%q) read %d days %v, want the entry's day and one either side", tt.id, len(store.read), store.read)
+			}
+		})
+	}
+}
+
+func TestFindEntryScansPrefixRange(t *testing.T) {
+	at := time.Date(2025, 3, 4, 9, 0, 0, 0, time.Local)
+	store := &dayReader{}
+	// Five characters cover about nine hours, so at most two days and one either side
+	_, _ = FindEntry(store, NewEntryIDAt(at)[:5])
+	if len(store.read) < 3 || len(store.read) > 4 {
+		t.Fatalf("read days %v, want three or four", store.read)
+	}
+	for _, day := range store.read {
+		date, _ := time.ParseInLocation("2006-01-02", day, time.Local)
+		if date.Before(at.AddDate(0, 0, -2)) || date.After(at.AddDate(0, 0, 2)) {
+			t.Errorf("read %s, far from %s", day, at.Format("2006-01-02"))
+		}
+	}
+}
+
+func TestResolveEntryID(t *testing.T) {
+	entries := []DailyLogEntry{
+		{ID: "01JNE3ZQ80AAAAAAAAAAAAAAAA", Title: "first"},
+		{ID: "01JNE3ZQ80AAAAAAAAAAAAAAAB", Title: "second"},
+		{ID: "01JNE3ZQ80AAAAAAAAAAAAAAAA0", Title: "longer"},
+	}
+	tests := []struct {
+		name      string
+		id        string
+		want      string
+		ambiguous bool
+	}{
+		{name: "exact match over a longer ID", id: "01JNE3ZQ80AAAAAAAAAAAAAAAA", want: "first"},
+		{name: "unique prefix", id: "01JNE3ZQ80AAAAAAAAAAAAAAAB", want: "second"},
+		{name: "shared prefix", id: "01JNE3ZQ80", ambiguous: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := ResolveEntryID(tt.id, entries)
+			if tt.ambiguous {
+				var validation ValidationError
+				if !errors.As(err, &validation) || !strings.Contains(validation.Message, "matches 3 entries") {
+					t.Fatalf("ResolveEntryID(%q) err = %v, want an ambiguity error", tt.id, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveEntryID(%q): %v", tt.id, err)
+			}
+			if entry.Title != tt.want {
+				t.Errorf("ResolveEntryID(%q) = %s, want %s", tt.id, entry.Title, tt.want)
+			}
+		})
+	}
+
+	var notFound NotFoundError
+	if _, err := ResolveEntryID("01JNE3ZQ81", entries); !errors.As(err, &notFound) {
+		t.Errorf("ResolveEntryID of an unknown prefix: err = %v, want NotFoundError", err)
+	}
+}
+
+func TestShortEntryIDs(t *testing.T) {
+	ids := []string{"01JNE3ZQ80AAAAAAAAAAAAAAAA", "01JNE3ZQ80AAAAAAAAAAAAAAAB", "01JNE4ZQ80AAAAAAAAAAAAAAAA", "entry_1741078800000000000"}
+	short := ShortEntryIDs(ids)
+	want := map[string]string{
+		ids[0]: "01JNE3ZQ80AAAAAAAAAAAAAAAA",
+		ids[1]: "01JNE3ZQ80AAAAAAAAAAAAAAAB",
+		ids[2]: "01JNE4ZQ",
+		ids[3]: "entry_1741078800000000000",
+	}
+	for id, prefix := range want {
+		if short[id] != prefix {
+			t.Errorf("short ID of %s = %s, want %s", id, short[id], prefix)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"context"
+	"errors"
 	"time"
 )
 
@@ -34,6 +36,31 @@ type DailyLogStorage interface {
 	HealthCheck() error
 }
 
+// DayStore is the day-level subset of storage needed to hold a copy of the
+// archive, such as a replication mirror
+type DayStore interface {
+	GetDay(date time.Time) (*DayLog, error)
+	SaveDay(dayLog *DayLog) error
+	DeleteDay(date time.Time) error
+	ListDays(start, end time.Time) ([]time.Time, error)
+}
+
+// ContextBinder is storage that can make its calls under a request's
+// context, so they are traced as part of the request
+type ContextBinder interface {
+	WithContext(ctx context.Context) DailyLogStorage
+}
+
+// WithContext returns store making its calls under ctx, or store itself if
+// it can't. The calls are not cancelled with ctx: a write the request
+// started is finished.
+func WithContext(ctx context.Context, store DailyLogStorage) DailyLogStorage {
+	if binder, ok := store.(ContextBinder); ok {
+		return binder.WithContext(context.WithoutCancel(ctx))
+	}
+	return store
+}
+
 // BackupStorage defines the interface for backup operations
 type BackupStorage interface {
 	BackupDay(date time.Time, data []byte) error
@@ -51,18 +78,36 @@ type AIProvider interface {
 	ImproveWording(text string) (string, error)
 }
 
+// Storage types
+const (
+	StorageTypeGitHub = "github" // Day files in a GitHub repository
+	StorageTypeMemory = "memory" // In memory, lost when the process exits
+)
+
 // Config represents the configuration for the daily log storage
 type Config struct {
-	StorageType     string `json:"storage_type"` // "github", "local", "cloud"
-	GitHubRepo      string `json:"github_repo"`  // "username/repo"
-	GitHubToken     string `json:"github_token"` // Personal access token
-	GitHubPath      string `json:"github_path"`  // Path within repo
-	LocalPath       string `json:"local_path"`   // Local storage path
-	BackupEnabled   bool   `json:"backup_enabled"`
-	BackupFrequency string `json:"backup_frequency"` // "daily", "weekly"
-	AIEnabled       bool   `json:"ai_enabled"`
-	AIProvider      string `json:"ai_provider"` // "openai", "anthropic"
-	AIAPIKey        string `json:"ai_api_key"`
+	StorageType     string           `json:"storage_type"`      // StorageTypeGitHub or StorageTypeMemory
+	GitHubRepo      string           `json:"github_repo"`       // "username/repo"
+	GitHubToken     string           `json:"github_token"`      // Personal access token
+	GitHubPath      string           `json:"github_path"`       // Path within repo
+	GitHubBaseURL   string           `json:"github_base_url"`   // API URL of GitHub Enterprise Server or a proxy
+	GitHubUploadURL string           `json:"github_upload_url"` // Upload URL, if not derived from GitHubBaseURL
+	GitHubBranch    string           `json:"github_branch"`     // Branch to write to (default: the repo's default branch)
+	GitHubAutoPR    bool             `json:"github_auto_pr"`    // Keep a pull request open from GitHubBranch
+	DayFormat       string           `json:"day_format"`        // "pretty", "compact", "gzip"
+	Layout          string           `json:"layout"`            // "monthly", "quarterly", "weekly", "yearly", "flat"
+	LocalPath       string           `json:"local_path"`        // Local storage path
+	BackupEnabled   bool             `json:"backup_enabled"`
+	BackupFrequency string           `json:"backup_frequency"` // "daily", "weekly"
+	AIEnabled       bool             `json:"ai_enabled"`
+	AIProvider      string           `json:"ai_provider"` // "openai", "anthropic"
+	AIAPIKey        string           `json:"ai_api_key"`
+	Visibility      VisibilityPolicy `json:"visibility"`       // Audience filtering for reports
+	LocationAliases LocationAliases  `json:"location_aliases"` // Alternative names for the same place
+	TagAliases      TagAliases       `json:"tag_aliases"`      // Alternative names for tags
+	Focus           FocusRules       `json:"focus"`            // Classify entries for focus scoring (default: DefaultFocusRules)
+	WorkHours       WorkHoursConfig  `json:"work_hours"`       // Targets and limits weekly summaries check work time against
+	Commit          CommitConfig     `json:"commit"`           // Commit messages, identity, and signing
 }
 
 // ValidationError represents a validation error
@@ -89,6 +134,11 @@ func (e StorageError) Error() string {
 	return e.Operation + ": " + e.Message
 }
 
+// Unwrap returns the underlying cause so errors.Is and errors.As can inspect it
+func (e StorageError) Unwrap() error {
+	return e.Cause
+}
+
 // NotFoundError represents a not found error
 type NotFoundError struct {
 	Resource string `json:"resource"`
@@ -98,3 +148,6 @@ type NotFoundError struct {
 func (e NotFoundError) Error() string {
 	return e.Resource + " not found: " + e.ID
 }
+
+// ErrReadOnly is the cause of writes refused by read-only storage
+var ErrReadOnly = errors.New("storage is read-only")
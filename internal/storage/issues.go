@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Metadata keys of an entry's linked issue
+const (
+	MetadataIssue    = "issue"     // Its key, e.g. "ENG-123"
+	MetadataIssueURL = "issue_url" // Where it's tracked
+)
+
+// Issue trackers an IssueTracker can link to
+const (
+	TrackerJira   = "jira"
+	TrackerLinear = "linear"
+)
+
+// issueKeyPattern matches issue keys as Jira and Linear write them: a
+// project or team key, a dash, and a number
+var issueKeyPattern = regexp.MustCompile(`\b([A-Z][A-Z0-9_]*)-([1-9][0-9]*)\b`)
+
+// IssueTracker links the issue keys of some projects to where they're
+// tracked: {URL}/browse/{key} for Jira, and for Linear,
+// https://linear.app/{Workspace}/issue/{key}
+type IssueTracker struct {
+	Kind      string   `mapstructure:"kind" json:"kind" yaml:"kind"`                                    // jira or linear
+	URL       string   `mapstructure:"url" json:"url,omitempty" yaml:"url,omitempty"`                   // Jira site, e.g. https://acme.atlassian.net
+	Workspace string   `mapstructure:"workspace" json:"workspace,omitempty" yaml:"workspace,omitempty"` // Linear workspace
+	Projects  []string `mapstructure:"projects" json:"projects,omitempty" yaml:"projects,omitempty"`    // Keys linked; none links any
+}
+
+// Validate checks the tracker has what its links need
+func (t IssueTracker) Validate() error {
+	switch t.Kind {
+	case TrackerJira:
+		if t.URL == "" {
+			return ValidationError{Field: "issues.trackers", Message: "a jira tracker needs a url"}
+		}
+	case TrackerLinear:
+		if t.Workspace == "" {
+			return ValidationError{Field: "issues.trackers", Message: "a linear tracker needs a workspace"}
+		}
+	default:
+		return ValidationError{Field: "issues.trackers", Message: fmt.Sprintf("kind must be jira or linear, not %q", t.Kind)}
+	}
+	return nil
+}
+
+// IssueURL returns where the issue with key is tracked
+func (t IssueTracker) IssueURL(key string) string {
+	if t.Kind == TrackerLinear {
+		return "https://linear.app/" + t.Workspace + "/issue/" + key
+	}
+	return strings.TrimRight(t.URL, "/") + "/browse/" + key
+}
+
+// tracks reports whether the tracker links issues of the project
+func (t IssueTracker) tracks(project string) bool {
+	if len(t.Projects) == 0 {
+		return true
+	}
+	for _, p := range t.Projects {
+		if strings.EqualFold(strings.TrimSpace(p), project) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueTrackers link issue keys in entry titles to the first tracker of
+// their project
+type IssueTrackers []IssueTracker
+
+// Link finds the first issue key in title that a tracker links, returning
+// the key and its URL
+func (t IssueTrackers) Link(title string) (string, string, bool) {
+	for _, match := range issueKeyPattern.FindAllStringSubmatch(title, -1) {
+		for _, tracker := range t {
+			if tracker.tracks(match[1]) {
+				return match[0], tracker.IssueURL(match[0]), true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// LinkRequest adds the issue of req's title to its metadata, unless it links
+// one already. It reports whether it did.
+func (t IssueTrackers) LinkRequest(req *CreateLogEntryRequest) bool {
+	if req.Metadata[MetadataIssue] != "" {
+		return false
+	}
+	key, url, ok := t.Link(req.Title)
+	if !ok {
+		return false
+	}
+	metadata := make(map[string]string, len(req.Metadata)+2)
+	for k, v := range req.Metadata {
+		metadata[k] = v
+	}
+	metadata[MetadataIssue] = key
+	metadata[MetadataIssueURL] = url
+	req.Metadata = metadata
+	return true
+}
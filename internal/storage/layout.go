@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"fmt"
+	"path"
+	"time"
+)
+
+// Layouts arranging day files in directories under the storage root
+const (
+	LayoutMonthly   = "monthly"   // 2025/09/2025-09-29.json, the default
+	LayoutQuarterly = "quarterly" // 2025/Q3/2025-09-29.json
+	LayoutWeekly    = "weekly"    // 2025/W40/2025-09-29.json, by ISO week
+	LayoutYearly    = "yearly"    // 2025/2025-09-29.json
+	LayoutFlat      = "flat"      // 2025-09-29.json
+)
+
+// ValidateLayout checks a day file layout; empty means monthly
+func ValidateLayout(layout string) error {
+	switch layout {
+	case "", LayoutMonthly, LayoutQuarterly, LayoutWeekly, LayoutYearly, LayoutFlat:
+		return nil
+	default:
+		return ValidationError{
+			Field:   "layout",
+			Message: fmt.Sprintf("unknown layout %q (use monthly, quarterly, weekly, yearly, or flat)", layout),
+		}
+	}
+}
+
+// DayDir returns the directory holding date's file in layout, relative to
+// the storage root; empty for the root itself
+func DayDir(date time.Time, layout string) string {
+	switch layout {
+	case LayoutFlat:
+		return ""
+	case LayoutYearly:
+		return date.Format("2006")
+	case LayoutQuarterly:
+		return fmt.Sprintf("%d/Q%d", date.Year(), (int(date.Month())+2)/3)
+	case LayoutWeekly:
+		year, week := date.ISOWeek()
+		return fmt.Sprintf("%d/W%02d", year, week)
+	default:
+		return path.Join(date.Format("2006"), date.Format("01"))
+	}
+}
@@ -0,0 +1,14 @@
+package storage
+
+// EntryTypeLesson is the type of entries recording a lesson learned: the
+// title is what was learned, the description its context
+const EntryTypeLesson = "lesson"
+
+// TagLesson marks an entry of another type as a lesson learned
+const TagLesson = "lesson"
+
+// IsLesson reports whether an entry is a lesson learned: of the lesson type,
+// or tagged lesson or with a tag aliased to it
+func IsLesson(entry DailyLogEntry, tags TagAliases) bool {
+	return entry.Type == EntryTypeLesson || tags.HasAnyTag(entry, []string{TagLesson})
+}
@@ -0,0 +1,42 @@
+package storage
+
+import "strings"
+
+// LocationAliases maps a canonical location name to other names for the same
+// place, e.g. "office" to ["HQ", "Main St"]. Matching is case-insensitive.
+type LocationAliases map[string][]string
+
+// Normalize returns the canonical name for a location, or the trimmed
+// location itself if it has no alias
+func (a LocationAliases) Normalize(location string) string {
+	location = strings.TrimSpace(location)
+	for canonical, aliases := range a {
+		if strings.EqualFold(location, canonical) {
+			return canonical
+		}
+		for _, alias := range aliases {
+			if strings.EqualFold(location, strings.TrimSpace(alias)) {
+				return canonical
+			}
+		}
+	}
+	return location
+}
+
+// SameLocation reports whether two locations name the same place
+func (a LocationAliases) SameLocation(x, y string) bool {
+	return strings.EqualFold(a.Normalize(x), a.Normalize(y))
+}
+
+// NormalizeEntries returns copies of the entries with canonical location names
+func (a LocationAliases) NormalizeEntries(entries []DailyLogEntry) []DailyLogEntry {
+	if len(a) == 0 {
+		return entries
+	}
+	normalized := make([]DailyLogEntry, len(entries))
+	for i, entry := range entries {
+		entry.Location = a.Normalize(entry.Location)
+		normalized[i] = entry
+	}
+	return normalized
+}
@@ -0,0 +1,95 @@
+package storage
+
+import "strings"
+
+// EntryTypeMeeting is the type of meeting entries, which keep their
+// attendees, decisions, and action items in metadata
+const EntryTypeMeeting = "meeting"
+
+// Metadata keys of meeting entries. Each holds a list, one item per line.
+const (
+	MetadataAttendees   = "attendees"
+	MetadataDecisions   = "decisions"
+	MetadataActionItems = "action_items"
+)
+
+// Meeting holds the structured details of a meeting entry
+type Meeting struct {
+	Attendees   []string `json:"attendees,omitempty" yaml:"attendees,omitempty"`
+	Decisions   []string `json:"decisions,omitempty" yaml:"decisions,omitempty"`
+	ActionItems []string `json:"action_items,omitempty" yaml:"action_items,omitempty"`
+}
+
+// MeetingOf reads the meeting details from an entry's metadata
+func MeetingOf(entry DailyLogEntry) Meeting {
+	return Meeting{
+		Attendees:   metadataList(entry.Metadata[MetadataAttendees]),
+		Decisions:   metadataList(entry.Metadata[MetadataDecisions]),
+		ActionItems: metadataList(entry.Metadata[MetadataActionItems]),
+	}
+}
+
+// IsEmpty reports whether no details are set
+func (m Meeting) IsEmpty() bool {
+	return len(m.Attendees) == 0 && len(m.Decisions) == 0 && len(m.ActionItems) == 0
+}
+
+// Metadata returns a copy of metadata with the meeting details added
+func (m Meeting) Metadata(metadata map[string]string) map[string]string {
+	merged := make(map[string]string, len(metadata)+3)
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	for key, items := range map[string][]string{
+		MetadataAttendees:   m.Attendees,
+		MetadataDecisions:   m.Decisions,
+		MetadataActionItems: m.ActionItems,
+	} {
+		if list := cleanList(items); len(list) > 0 {
+			merged[key] = strings.Join(list, "\n")
+		}
+	}
+	return merged
+}
+
+// ActionItemTasks turns the action items of meeting entries into open tasks:
+// notes titled with the action item, timestamped with the meeting, and
+// linked to it through source_id metadata
+func ActionItemTasks(entries []DailyLogEntry) []DailyLogEntry {
+	var tasks []DailyLogEntry
+	for _, entry := range entries {
+		if entry.Type != EntryTypeMeeting {
+			continue
+		}
+		for _, item := range MeetingOf(entry).ActionItems {
+			tasks = append(tasks, DailyLogEntry{
+				Timestamp:   entry.Timestamp,
+				Type:        "note",
+				Title:       item,
+				Description: "Action item from " + entry.Title,
+				Tags:        entry.Tags,
+				Visibility:  entry.Visibility,
+				Author:      entry.Author,
+				Metadata:    map[string]string{MetadataSourceID: entry.ID},
+			})
+		}
+	}
+	return tasks
+}
+
+// metadataList splits a one-item-per-line metadata value
+func metadataList(value string) []string {
+	return cleanList(strings.Split(value, "\n"))
+}
+
+// cleanList trims items and drops empty ones; items can't span lines
+func cleanList(items []string) []string {
+	var list []string
+	for _, item := range items {
+		item = strings.Join(strings.Fields(item), " ")
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"maps"
+	"reflect"
+	"time"
+)
+
+// MergeDayLogs performs a three-way, entry-level merge of concurrent edits to a day.
+// base is the version both sides started from (nil if the day did not exist),
+// local holds our unsaved changes, and remote is the version currently stored.
+//
+// Entries are matched by ID. Changes made on only one side win; when both
+// sides changed the same entry, the local version wins. Entries deleted on
+// one side stay deleted unless the other side modified them. Entries keep
+// their local order, so local moves survive; entries added remotely follow
+// the entry they follow remotely.
+func MergeDayLogs(base, local, remote *DayLog) *DayLog {
+	baseEntries := indexEntries(base)
+	localEntries := indexEntries(local)
+	remoteEntries := indexEntries(remote)
+
+	var merged []DailyLogEntry
+
+	for _, entry := range local.Entries {
+		baseEntry, inBase := baseEntries[entry.ID]
+		remoteEntry, inRemote := remoteEntries[entry.ID]
+
+		switch {
+		case inRemote && inBase && reflect.DeepEqual(entry, baseEntry):
+			// Unchanged locally: take whatever the remote side has
+			merged = append(merged, remoteEntry)
+		case inRemote:
+			merged = append(merged, entry)
+		case inBase && reflect.DeepEqual(entry, baseEntry):
+			// Deleted remotely and untouched locally
+		default:
+			// New locally, or modified locally after a remote delete
+			merged = append(merged, entry)
+		}
+	}
+
+	// at is where the next remote entry goes: after the last one kept
+	at := 0
+	for _, entry := range remote.Entries {
+		if _, inLocal := localEntries[entry.ID]; inLocal {
+			if i := entryIndex(merged, entry.ID); i >= 0 {
+				at = i + 1
+			}
+			continue
+		}
+		baseEntry, inBase := baseEntries[entry.ID]
+		if inBase && reflect.DeepEqual(entry, baseEntry) {
+			// Deleted locally and untouched remotely
+			continue
+		}
+		merged = append(merged[:at], append([]DailyLogEntry{entry}, merged[at:]...)...)
+		at++
+	}
+
+	result := *remote
+	result.Entries = merged
+	result.Metadata = maps.Clone(remote.Metadata)
+	result.TotalEntries = len(merged)
+	result.UpdatedAt = time.Now()
+
+	if base == nil || local.DaySummary != base.DaySummary {
+		if local.DaySummary != "" {
+			result.DaySummary = local.DaySummary
+		}
+	}
+	if base == nil || !reflect.DeepEqual(local.Metadata, base.Metadata) {
+		if result.Metadata == nil && len(local.Metadata) > 0 {
+			result.Metadata = make(map[string]any, len(local.Metadata))
+		}
+		for key, value := range local.Metadata {
+			result.Metadata[key] = value
+		}
+	}
+
+	result.calculateStatusAverage()
+	return &result
+}
+
+// entryIndex returns the position of the entry with id in entries, or -1
+func entryIndex(entries []DailyLogEntry, id string) int {
+	for i, entry := range entries {
+		if entry.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexEntries(dayLog *DayLog) map[string]DailyLogEntry {
+	index := make(map[string]DailyLogEntry)
+	if dayLog == nil {
+		return index
+	}
+	for _, entry := range dayLog.Entries {
+		index[entry.ID] = entry
+	}
+	return index
+}
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// mergeEntry returns an entry with id, titled title, logged later the
+// later its id is in the alphabet, so moves put entries out of time order
+func mergeEntry(id, title string) DailyLogEntry {
+	logged := time.Date(2025, 3, 4, 9, int(id[0]-'a'), 0, 0, time.UTC)
+	return DailyLogEntry{ID: id, Type: "activity", Title: title, Timestamp: logged}
+}
+
+// mergeDay returns a day of entries written as id or id=title, e.g. "a b=edited"
+func mergeDay(entries string) *DayLog {
+	day := &DayLog{Date: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), Entries: []DailyLogEntry{}}
+	for _, field := range strings.Fields(entries) {
+		id, title, found := strings.Cut(field, "=")
+		if !found {
+			title = id
+		}
+		day.Entries = append(day.Entries, mergeEntry(id, title))
+	}
+	return day
+}
+
+// formatMerged writes a merged day's entries the way mergeDay reads them
+func formatMerged(day *DayLog) string {
+	var fields []string
+	for _, entry := range day.Entries {
+		if entry.Title == entry.ID {
+			fields = append(fields, entry.ID)
+		} else {
+			fields = append(fields, entry.ID+"="+entry.Title)
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+func TestMergeDayLogsEntries(t *testing.T) {
+	tests := []struct {
+		name                string
+		base, local, remote string
+		newDay              bool
+		want                string
+	}{
+		{name: "no changes", base: "a b c", local: "a b c", remote: "a b c", want: "a b c"},
+		{name: "local add", base: "a b c", local: "a b c d", remote: "a b c", want: "a b c d"},
+		{name: "remote add", base: "a b c", local: "a b c", remote: "a b c e", want: "a b c e"},
+		{name: "remote add in the middle", base: "a b c", local: "a b c", remote: "a e b c", want: "a e b c"},
+		{name: "remote add at the start", base: "a b c", local: "a b c", remote: "e a b c", want: "e a b c"},
+		{name: "both add", base: "a b c", local: "a b c d", remote: "a b c e", want: "a b c e d"},
+		{name: "local move", base: "a b c", local: "c a b", remote: "a b c", want: "c a b"},
+		{name: "local move, remote add", base: "a b c", local: "c a b", remote: "a b e c", want: "c a b e"},
+		{name: "local move, remote edit", base: "a b c", local: "c a b", remote: "a b=remote c", want: "c a b=remote"},
+		{name: "local edit", base: "a b c", local: "a b=local c", remote: "a b c", want: "a b=local c"},
+		{name: "remote edit", base: "a b c", local: "a b c", remote: "a b=remote c", want: "a b=remote c"},
+		{name: "both edit", base: "a b c", local: "a b=local c", remote: "a b=remote c", want: "a b=local c"},
+		{name: "local delete", base: "a b c", local: "a c", remote: "a b c", want: "a c"},
+		{name: "remote delete", base: "a b c", local: "a b c", remote: "a c", want: "a c"},
+		{name: "both delete", base: "a b c", local: "a c", remote: "a b", want: "a"},
+		{name: "local edit, remote delete", base: "a b c", local: "a b=local c", remote: "a c", want: "a b=local c"},
+		{name: "remote edit, local delete", base: "a b c", local: "a c", remote: "a b=remote c", want: "a b=remote c"},
+		{name: "local add, remote delete", base: "a b c", local: "a b c d", remote: "a c", want: "a c d"},
+		{name: "remote add, local delete", base: "a b c", local: "a c", remote: "a b c e", want: "a c e"},
+		{name: "new day on both sides", newDay: true, local: "d", remote: "e", want: "e d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var base *DayLog
+			if !tt.newDay {
+				base = mergeDay(tt.base)
+			}
+			merged := MergeDayLogs(base, mergeDay(tt.local), mergeDay(tt.remote))
+			if got := formatMerged(merged); got != tt.want {
+				t.Errorf("merged %s, want %s", got, tt.want)
+			}
+			if merged.TotalEntries != len(merged.Entries) {
+				t.Errorf("TotalEntries = %d, want %d", merged.TotalEntries, len(merged.Entries))
+			}
+		})
+	}
+}
+
+func TestMergeDayLogsMetadata(t *testing.T) {
+	base, local, remote := mergeDay("a"), mergeDay("a"), mergeDay("a")
+	base.Metadata = map[string]any{"mood": "ok"}
+	local.Metadata = map[string]any{"mood": "good"}
+	remote.Metadata = map[string]any{"mood": "ok", "weather": "rain"}
+	remote.DaySummary = "remote summary"
+
+	merged := MergeDayLogs(base, local, remote)
+	if merged.Metadata["mood"] != "good" || merged.Metadata["weather"] != "rain" {
+		t.Errorf("merged metadata %v, want mood good and weather rain", merged.Metadata)
+	}
+	if remote.Metadata["mood"] != "ok" {
+		t.Errorf("remote metadata changed to %v by the merge", remote.Metadata)
+	}
+	if merged.DaySummary != "remote summary" {
+		t.Errorf("DaySummary = %q, want the remote one", merged.DaySummary)
+	}
+
+	merged.Metadata["weather"] = "sun"
+	if remote.Metadata["weather"] != "rain" {
+		t.Error("merged metadata shares the remote map")
+	}
+}
@@ -0,0 +1,331 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EntryTypeMetric is the type of health metric entries, such as sleep or
+// steps, which keep their name, value, and unit in metadata
+const EntryTypeMetric = "metric"
+
+// Metadata keys of metric entries
+const (
+	MetadataMetric      = "metric"
+	MetadataMetricValue = "value"
+	MetadataMetricUnit  = "unit"
+)
+
+// metricUnits are the units known metrics are stored in; values given in
+// other units are converted
+var metricUnits = map[string]string{
+	"sleep":  "h",
+	"steps":  "steps",
+	"weight": "kg",
+}
+
+// summedMetrics add up over a day rather than average out
+var summedMetrics = map[string]bool{
+	"steps": true,
+}
+
+// KnownMetrics returns the names of the metrics with a standard unit
+func KnownMetrics() []string {
+	names := make([]string, 0, len(metricUnits))
+	for name := range metricUnits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Metric is a numeric measurement such as 7.5 hours of sleep
+type Metric struct {
+	Name  string  `json:"name" yaml:"name"`
+	Value float64 `json:"value" yaml:"value"`
+	Unit  string  `json:"unit,omitempty" yaml:"unit,omitempty"`
+}
+
+var metricValuePattern = regexp.MustCompile(`^([-+]?\d+(?:\.\d+)?)\s*([a-zA-Z%]*)$`)
+
+// ParseMetric reads a metric's value and unit from e.g. "7.5h", "7h30m",
+// "8000", or "180lb". Known metrics are converted to their standard unit, and
+// take it when none is given.
+func ParseMetric(name, value string) (Metric, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return Metric{}, ValidationError{Field: "metric", Message: fmt.Sprintf("must be a single word (got %q)", name)}
+	}
+	value = strings.TrimSpace(value)
+	want := metricUnits[name]
+
+	m := Metric{Name: name}
+	if d, err := time.ParseDuration(value); err == nil && strings.IndexFunc(value, isUnitLetter) >= 0 {
+		m.Value, m.Unit = d.Hours(), "h"
+	} else if match := metricValuePattern.FindStringSubmatch(value); match != nil {
+		m.Value, _ = strconv.ParseFloat(match[1], 64)
+		m.Unit = normalizeUnit(match[2])
+	} else {
+		return Metric{}, ValidationError{Field: "value", Message: fmt.Sprintf("must be a number with an optional unit, e.g. 7.5h or 82kg (got %q)", value)}
+	}
+
+	if want == "" || m.Unit == want {
+		return m, nil
+	}
+	switch {
+	case m.Unit == "":
+		m.Unit = want
+	case m.Unit == "lb" && want == "kg":
+		m.Value, m.Unit = m.Value*0.45359237, want
+	default:
+		return Metric{}, ValidationError{Field: "value", Message: fmt.Sprintf("%s is measured in %s (got %s)", name, want, m.Unit)}
+	}
+	return m, nil
+}
+
+func isUnitLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// normalizeUnit maps spellings of a unit to one
+func normalizeUnit(unit string) string {
+	unit = strings.ToLower(unit)
+	switch unit {
+	case "hr", "hrs", "hour", "hours":
+		return "h"
+	case "lbs", "pound", "pounds":
+		return "lb"
+	case "kgs", "kilo", "kilos":
+		return "kg"
+	case "step":
+		return "steps"
+	}
+	return unit
+}
+
+// MetricOf reads the metric from a metric entry's metadata
+func MetricOf(entry DailyLogEntry) (Metric, bool) {
+	if entry.Type != EntryTypeMetric || entry.Metadata[MetadataMetric] == "" {
+		return Metric{}, false
+	}
+	value, err := strconv.ParseFloat(entry.Metadata[MetadataMetricValue], 64)
+	if err != nil {
+		return Metric{}, false
+	}
+	return Metric{Name: entry.Metadata[MetadataMetric], Value: value, Unit: entry.Metadata[MetadataMetricUnit]}, true
+}
+
+// Metadata returns a copy of metadata with the metric added
+func (m Metric) Metadata(metadata map[string]string) map[string]string {
+	merged := make(map[string]string, len(metadata)+3)
+	for key, value := range metadata {
+		merged[key] = value
+	}
+	merged[MetadataMetric] = m.Name
+	merged[MetadataMetricValue] = formatMetricValue(m.Value)
+	if m.Unit != "" {
+		merged[MetadataMetricUnit] = m.Unit
+	}
+	return merged
+}
+
+// String formats the value and unit, e.g. "7.5h" or "8000 steps"
+func (m Metric) String() string {
+	return formatMetricAmount(m.Value, m.Unit)
+}
+
+// Title is the title of the metric's entry, e.g. "Sleep: 7.5h"
+func (m Metric) Title() string {
+	if m.Name == "" {
+		return m.String()
+	}
+	return strings.ToUpper(m.Name[:1]) + m.Name[1:] + ": " + m.String()
+}
+
+func formatMetricAmount(value float64, unit string) string {
+	switch unit {
+	case "":
+		return formatMetricValue(value)
+	case "h":
+		return formatMetricValue(value) + "h"
+	}
+	return formatMetricValue(value) + " " + unit
+}
+
+// formatMetricValue formats a value with at most two decimals
+func formatMetricValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// MetricStats rolls up one metric's values over a period. A day's value is
+// the sum of its values for metrics that add up, such as steps, and their
+// average for the others.
+type MetricStats struct {
+	Name         string  `json:"name" yaml:"name"`
+	Unit         string  `json:"unit,omitempty" yaml:"unit,omitempty"`
+	Count        int     `json:"count" yaml:"count"`
+	Days         int     `json:"days" yaml:"days"`
+	DailyAverage float64 `json:"daily_average" yaml:"daily_average"`
+	Min          float64 `json:"min" yaml:"min"` // Of the daily values
+	Max          float64 `json:"max" yaml:"max"`
+}
+
+// String formats the daily average and range, e.g. "7.2h (6h-8.5h)"
+func (s MetricStats) String() string {
+	if s.Min == s.Max {
+		return formatMetricAmount(s.DailyAverage, s.Unit)
+	}
+	return fmt.Sprintf("%s (%s-%s)", formatMetricAmount(s.DailyAverage, s.Unit),
+		formatMetricValue(s.Min), formatMetricAmount(s.Max, s.Unit))
+}
+
+// SummarizeMetrics rolls up the metric entries among entries, by metric name
+func SummarizeMetrics(entries []DailyLogEntry) []MetricStats {
+	counts := make(map[string]int)
+	units := make(map[string]string)
+	for _, entry := range entries {
+		if m, ok := MetricOf(entry); ok {
+			counts[m.Name]++
+			units[m.Name] = m.Unit
+		}
+	}
+
+	daily := DailyMetricValues(entries)
+	stats := make([]MetricStats, 0, len(daily))
+	for name, days := range daily {
+		s := MetricStats{Name: name, Unit: units[name], Count: counts[name], Days: len(days), Min: math.Inf(1), Max: math.Inf(-1)}
+		for _, value := range days {
+			s.DailyAverage += value
+			s.Min = math.Min(s.Min, value)
+			s.Max = math.Max(s.Max, value)
+		}
+		s.DailyAverage /= float64(len(days))
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// DailyMetricValues returns each metric's value per day (YYYY-MM-DD)
+func DailyMetricValues(entries []DailyLogEntry) map[string]map[string]float64 {
+	sums := make(map[string]map[string]float64)
+	counts := make(map[string]map[string]int)
+	for _, entry := range entries {
+		m, ok := MetricOf(entry)
+		if !ok {
+			continue
+		}
+		day := entry.Timestamp.Format("2006-01-02")
+		if sums[m.Name] == nil {
+			sums[m.Name] = make(map[string]float64)
+			counts[m.Name] = make(map[string]int)
+		}
+		sums[m.Name][day] += m.Value
+		counts[m.Name][day]++
+	}
+	for name, days := range sums {
+		if summedMetrics[name] {
+			continue
+		}
+		for day := range days {
+			days[day] /= float64(counts[name][day])
+		}
+	}
+	return sums
+}
+
+// minCorrelationDays is how many days with both a metric and a status a
+// correlation needs to mean anything
+const minCorrelationDays = 5
+
+// MetricCorrelation is how closely a metric moved with status (mood) from
+// day to day
+type MetricCorrelation struct {
+	Metric      string  `json:"metric" yaml:"metric"`
+	Days        int     `json:"days" yaml:"days"`
+	Correlation float64 `json:"correlation" yaml:"correlation"` // Pearson's r, -1 to 1
+}
+
+// Strength describes the correlation, e.g. "moderate positive"
+func (c MetricCorrelation) Strength() string {
+	r := math.Abs(c.Correlation)
+	var strength string
+	switch {
+	case r >= 0.7:
+		strength = "strong"
+	case r >= 0.4:
+		strength = "moderate"
+	case r >= 0.2:
+		strength = "weak"
+	default:
+		return "no clear"
+	}
+	if c.Correlation < 0 {
+		return strength + " negative"
+	}
+	return strength + " positive"
+}
+
+// CorrelateMetricsWithStatus correlates each metric's daily value with the
+// day's average status, over the days that have both. Metrics logged on too
+// few such days, or that never changed, are left out.
+func CorrelateMetricsWithStatus(entries []DailyLogEntry) []MetricCorrelation {
+	statusSum := make(map[string]int)
+	statusCount := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Status > 0 {
+			day := entry.Timestamp.Format("2006-01-02")
+			statusSum[day] += entry.Status
+			statusCount[day]++
+		}
+	}
+
+	var correlations []MetricCorrelation
+	for name, days := range DailyMetricValues(entries) {
+		var xs, ys []float64
+		for day, value := range days {
+			if statusCount[day] > 0 {
+				xs = append(xs, value)
+				ys = append(ys, float64(statusSum[day])/float64(statusCount[day]))
+			}
+		}
+		if len(xs) < minCorrelationDays {
+			continue
+		}
+		if r, ok := pearson(xs, ys); ok {
+			correlations = append(correlations, MetricCorrelation{Metric: name, Days: len(xs), Correlation: r})
+		}
+	}
+	sort.Slice(correlations, func(i, j int) bool { return correlations[i].Metric < correlations[j].Metric })
+	return correlations
+}
+
+// pearson returns the correlation coefficient of xs and ys, or false when
+// either never varies
+func pearson(xs, ys []float64) (float64, bool) {
+	n := float64(len(xs))
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= n
+	meanY /= n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0, false
+	}
+	return cov / math.Sqrt(varX*varY), true
+}
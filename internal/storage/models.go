@@ -2,6 +2,7 @@ package storage
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 )
 
@@ -9,7 +10,7 @@ import (
 type DailyLogEntry struct {
 	ID          string            `json:"id"`
 	Timestamp   time.Time         `json:"timestamp"`
-	Type        string            `json:"type"` // "activity", "status", "note", "summary"
+	Type        string            `json:"type"` // "activity", "status", "note", "summary", "meeting", "metric", "lesson"
 	Title       string            `json:"title"`
 	Description string            `json:"description"`
 	Tags        []string          `json:"tags,omitempty"`
@@ -17,9 +18,23 @@ type DailyLogEntry struct {
 	Priority    int               `json:"priority,omitempty"` // 1-5 scale
 	Duration    *int              `json:"duration,omitempty"` // minutes
 	Location    string            `json:"location,omitempty"`
+	Visibility  string            `json:"visibility,omitempty"`  // "private", "team", "public"
+	Author      string            `json:"author,omitempty"`      // Who logged it, in a log shared by a team
+	Pinned      bool              `json:"pinned,omitempty"`      // A highlight worth revisiting
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"` // When it was last looked over in a review
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
+// Metadata keys of entries logged from recordings, photos, and emails
+const (
+	MetadataAttachment  = "attachment"     // Repository path of the file attached to the entry
+	MetadataAttachments = "attachments"    // Paths of all its files, one per line, when there are several
+	MetadataTranscriber = "transcribed_by" // Model that transcribed the recording
+	MetadataLatitude    = "latitude"       // Where the photo was taken, in decimal degrees
+	MetadataLongitude   = "longitude"
+	MetadataCamera      = "camera" // Camera that took the photo
+)
+
 // DayLog represents all activities and entries for a single day
 type DayLog struct {
 	Date          time.Time       `json:"date"`
@@ -30,6 +45,14 @@ type DayLog struct {
 	CreatedAt     time.Time       `json:"created_at"`
 	UpdatedAt     time.Time       `json:"updated_at"`
 	Metadata      map[string]any  `json:"metadata,omitempty"`
+
+	// Revision identifies the stored version this log was read from (e.g. a blob SHA).
+	// Providers use it to detect concurrent writes; it is never serialized.
+	Revision string `json:"-"`
+
+	// Format is the file format the log was read in, such as DayFormatGzip;
+	// empty for a day not stored yet. It is never serialized.
+	Format string `json:"-"`
 }
 
 // WeeklyLog represents a week's worth of daily logs
@@ -67,16 +90,33 @@ type LogSearchRequest struct {
 	StatusMin  *int              `json:"status_min,omitempty"`
 	StatusMax  *int              `json:"status_max,omitempty"`
 	SearchText string            `json:"search_text,omitempty"`
+	Location   string            `json:"location,omitempty"`
+	Author     string            `json:"author,omitempty"`     // Only entries by this author
+	Pinned     bool              `json:"pinned,omitempty"`     // Only pinned entries
+	Unreviewed bool              `json:"unreviewed,omitempty"` // Only entries not yet reviewed
 	Limit      int               `json:"limit,omitempty"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
+	// Aggregations lists group-by keys (tag, type, day, week) to roll up.
+	// Rollups cover every matching entry, not just the first Limit.
+	Aggregations []string `json:"aggregations,omitempty"`
+	// Sort orders the results before Limit applies; see ParseSortOrder
+	Sort string `json:"sort,omitempty"`
+	// Progress, if set, is told as each day of the range is read
+	Progress ProgressFunc `json:"-" yaml:"-"`
 }
 
+// ProgressFunc is told how many of the total days a range read has covered
+type ProgressFunc func(done, total int)
+
 // LogSearchResponse represents the result of a log search
 type LogSearchResponse struct {
-	Entries     []DailyLogEntry  `json:"entries"`
-	Days        []DayLog         `json:"days,omitempty"`
-	TotalCount  int              `json:"total_count"`
-	SearchQuery LogSearchRequest `json:"search_query"`
+	Entries      []DailyLogEntry  `json:"entries"`
+	Days         []DayLog         `json:"days,omitempty"`
+	TotalCount   int              `json:"total_count"`
+	SearchQuery  LogSearchRequest `json:"search_query"`
+	Aggregations []Aggregation    `json:"aggregations,omitempty"`
+	// Totals rolls up every matching entry; set when aggregations are requested
+	Totals *AggregationBucket `json:"totals,omitempty"`
 }
 
 // CreateLogEntryRequest represents a request to create a new log entry
@@ -90,6 +130,8 @@ type CreateLogEntryRequest struct {
 	Priority    *int              `json:"priority,omitempty"`
 	Duration    *int              `json:"duration,omitempty"`
 	Location    string            `json:"location,omitempty"`
+	Visibility  string            `json:"visibility,omitempty"`
+	Author      string            `json:"author,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
@@ -104,6 +146,9 @@ type UpdateLogEntryRequest struct {
 	Priority    *int              `json:"priority,omitempty"`
 	Duration    *int              `json:"duration,omitempty"`
 	Location    string            `json:"location,omitempty"`
+	Visibility  string            `json:"visibility,omitempty"`
+	Pinned      *bool             `json:"pinned,omitempty"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty"` // A zero time marks the entry unreviewed
 	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
@@ -115,6 +160,10 @@ type SummaryRequest struct {
 	EndDate   *time.Time `json:"end_date,omitempty"`
 	UseAI     bool       `json:"use_ai"`
 	Prompt    string     `json:"prompt,omitempty"`
+	Audience  string     `json:"audience,omitempty"` // only include entries visible to this audience
+	Tags      []string   `json:"tags,omitempty"`     // only include entries with one of these tags or their children
+	// Progress, if set, is told as each day of a week or month is read
+	Progress ProgressFunc `json:"-" yaml:"-"`
 }
 
 // SummaryResponse represents the result of a summary generation
@@ -125,6 +174,39 @@ type SummaryResponse struct {
 	Stats     map[string]any    `json:"stats"`
 	CreatedAt time.Time         `json:"created_at"`
 	Metadata  map[string]string `json:"metadata,omitempty"`
+	// Pinned are the pinned entries of a week or month, its highlights
+	Pinned []DailyLogEntry `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+
+	// Entries are the entries summarized, after audience and tag filtering
+	Entries []DailyLogEntry `json:"-" yaml:"-"`
+}
+
+// NewEntry builds an entry with a fresh ID from a create request
+func NewEntry(req CreateLogEntryRequest) DailyLogEntry {
+	entry := DailyLogEntry{
+		ID:          NewEntryIDAt(req.Date),
+		Timestamp:   req.Date,
+		Type:        req.Type,
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Location:    req.Location,
+		Visibility:  req.Visibility,
+		Author:      req.Author,
+		Metadata:    req.Metadata,
+	}
+
+	if req.Status != nil {
+		entry.Status = *req.Status
+	}
+	if req.Priority != nil {
+		entry.Priority = *req.Priority
+	}
+	if req.Duration != nil {
+		entry.Duration = req.Duration
+	}
+
+	return entry
 }
 
 // Utility methods for DayLog
@@ -194,12 +276,12 @@ func (d *DayLog) GetEntriesByType(entryType string) []DailyLogEntry {
 	return entries
 }
 
-// GetEntriesByTag returns all entries containing a specific tag
+// GetEntriesByTag returns all entries containing a specific tag or one of its children
 func (d *DayLog) GetEntriesByTag(tag string) []DailyLogEntry {
 	var entries []DailyLogEntry
 	for _, entry := range d.Entries {
 		for _, entryTag := range entry.Tags {
-			if entryTag == tag {
+			if TagMatches(tag, entryTag) {
 				entries = append(entries, entry)
 				break
 			}
@@ -208,6 +290,28 @@ func (d *DayLog) GetEntriesByTag(tag string) []DailyLogEntry {
 	return entries
 }
 
+// AuthorEntries returns the entries by author among entries, in order
+func AuthorEntries(entries []DailyLogEntry, author string) []DailyLogEntry {
+	var matched []DailyLogEntry
+	for _, entry := range entries {
+		if strings.EqualFold(entry.Author, author) {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// PinnedEntries returns the pinned entries among entries, in order
+func PinnedEntries(entries []DailyLogEntry) []DailyLogEntry {
+	var pinned []DailyLogEntry
+	for _, entry := range entries {
+		if entry.Pinned {
+			pinned = append(pinned, entry)
+		}
+	}
+	return pinned
+}
+
 // ToJSON converts the DayLog to JSON
 func (d *DayLog) ToJSON() ([]byte, error) {
 	return json.MarshalIndent(d, "", "  ")
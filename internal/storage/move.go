@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// MoveEntry moves an entry, found by ID or ID prefix on day from, to day to.
+// It keeps its ID, time of day, and everything else. The entry is saved on
+// its new day before it is removed from the old one, so a failure part way
+// leaves it on both days rather than on neither.
+func MoveEntry(store DayStore, id string, from, to time.Time) (*DailyLogEntry, error) {
+	return relocateEntry(store, id, from, to, false)
+}
+
+// CopyEntry copies an entry, found by ID or ID prefix on day from, to day to
+// at the same time of day. The copy gets a new ID.
+func CopyEntry(store DayStore, id string, from, to time.Time) (*DailyLogEntry, error) {
+	return relocateEntry(store, id, from, to, true)
+}
+
+func relocateEntry(store DayStore, id string, from, to time.Time, keepSource bool) (*DailyLogEntry, error) {
+	if from.Format("2006-01-02") == to.Format("2006-01-02") {
+		return nil, ValidationError{Field: "to", Message: "must be a different day than the entry's"}
+	}
+
+	source, err := BeginDayEdit(store, from)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := ResolveEntryID(id, source.Day().Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := *existing
+	entry.Timestamp = onDay(entry.Timestamp, to)
+	if keepSource {
+		entry.ID = NewEntryIDAt(entry.Timestamp)
+	}
+
+	target, err := BeginDayEdit(store, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := target.Insert(entry); err != nil {
+		return nil, err
+	}
+	if err := target.Commit(); err != nil {
+		return nil, err
+	}
+	if keepSource {
+		return &entry, nil
+	}
+
+	if err := source.Remove(existing.ID); err != nil {
+		return nil, err
+	}
+	if err := source.Commit(); err != nil {
+		return nil, fmt.Errorf("entry %s was added to %s but not removed from %s: %w",
+			entry.ID, to.Format("2006-01-02"), from.Format("2006-01-02"), err)
+	}
+	return &entry, nil
+}
+
+// onDay returns t moved to day's date, keeping its time of day and zone
+func onDay(t, day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultOnThisDayYears is how many years back "on this day" looks by default
+const DefaultOnThisDayYears = 5
+
+// Anniversary is an earlier date that falls on the same calendar day
+type Anniversary struct {
+	Date time.Time
+	// Ago describes how long before, e.g. "1 year ago" or "3 months ago"
+	Ago string
+}
+
+// OnThisDay returns the same calendar date in each of the previous years and
+// months, most recent first. Months without that day, such as February for
+// the 31st, are skipped, as are months that coincide with one of the years.
+func OnThisDay(date time.Time, years, months int) []Anniversary {
+	var anniversaries []Anniversary
+	for n := 1; n <= months; n++ {
+		if n%12 == 0 && n/12 <= years {
+			continue
+		}
+		if earlier, ok := sameDayBefore(date, 0, n); ok {
+			anniversaries = append(anniversaries, Anniversary{Date: earlier, Ago: ago(n, "month")})
+		}
+	}
+	for n := 1; n <= years; n++ {
+		if earlier, ok := sameDayBefore(date, n, 0); ok {
+			anniversaries = append(anniversaries, Anniversary{Date: earlier, Ago: ago(n, "year")})
+		}
+	}
+
+	sort.Slice(anniversaries, func(i, j int) bool {
+		return anniversaries[i].Date.After(anniversaries[j].Date)
+	})
+	return anniversaries
+}
+
+// sameDayBefore returns the date years and months before date, if that month has the same day
+func sameDayBefore(date time.Time, years, months int) (time.Time, bool) {
+	first := time.Date(date.Year()-years, date.Month()-time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	earlier := first.AddDate(0, 0, date.Day()-1)
+	return earlier, earlier.Month() == first.Month()
+}
+
+func ago(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"strings"
+	"time"
+)
+
+// MetadataPlanned marks an entry logged in advance as a plan rather than as
+// something done, with the value "true"
+const MetadataPlanned = "planned"
+
+// IsPlanned reports whether an entry is a plan
+func IsPlanned(entry DailyLogEntry) bool {
+	return entry.Metadata[MetadataPlanned] == "true"
+}
+
+// PlanItem is a plan and, if it was done, the entry logged for it
+type PlanItem struct {
+	Plan   DailyLogEntry  `json:"plan" yaml:"plan"`
+	DoneBy *DailyLogEntry `json:"done_by,omitempty" yaml:"done_by,omitempty"`
+}
+
+// PlanComparison compares the plans of a period with what was logged in it
+type PlanComparison struct {
+	Planned int `json:"planned" yaml:"planned"`
+	Done    int `json:"done" yaml:"done"`
+	// CompletionRate is the share of plans done, 0-1, out of those done or
+	// slipped; plans not due yet don't count
+	CompletionRate float64         `json:"completion_rate" yaml:"completion_rate"`
+	Completed      []PlanItem      `json:"completed" yaml:"completed"`
+	Slipped        []PlanItem      `json:"slipped" yaml:"slipped"`
+	Upcoming       []PlanItem      `json:"upcoming" yaml:"upcoming"`
+	Unplanned      []DailyLogEntry `json:"unplanned" yaml:"unplanned"`
+}
+
+// ComparePlans matches the plans among entries with the entries logged for
+// them: a plan is done by an entry of the period with the same title,
+// ignoring case, each entry doing at most one plan. Plans for days before
+// today that weren't done have slipped. Activities and meetings that did no
+// plan are unplanned work.
+func ComparePlans(entries []DailyLogEntry, today time.Time) PlanComparison {
+	comparison := PlanComparison{
+		Completed: []PlanItem{},
+		Slipped:   []PlanItem{},
+		Upcoming:  []PlanItem{},
+		Unplanned: []DailyLogEntry{},
+	}
+	entries = append([]DailyLogEntry(nil), entries...)
+	SortEntries(entries, DefaultSortOrder)
+
+	// Logged entries by title, in order, to be taken by plans
+	logged := make(map[string][]int)
+	for i, entry := range entries {
+		if !IsPlanned(entry) {
+			key := planKey(entry.Title)
+			logged[key] = append(logged[key], i)
+		}
+	}
+
+	used := make(map[int]bool)
+	dueBefore := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	for _, entry := range entries {
+		if !IsPlanned(entry) {
+			continue
+		}
+		comparison.Planned++
+		item := PlanItem{Plan: entry}
+		key := planKey(entry.Title)
+		if candidates := logged[key]; len(candidates) > 0 {
+			used[candidates[0]] = true
+			logged[key] = candidates[1:]
+			item.DoneBy = &entries[candidates[0]]
+		}
+
+		switch {
+		case item.DoneBy != nil:
+			comparison.Done++
+			comparison.Completed = append(comparison.Completed, item)
+		case entry.Timestamp.Before(dueBefore):
+			comparison.Slipped = append(comparison.Slipped, item)
+		default:
+			comparison.Upcoming = append(comparison.Upcoming, item)
+		}
+	}
+
+	for i, entry := range entries {
+		if !used[i] && !IsPlanned(entry) && (entry.Type == "activity" || entry.Type == EntryTypeMeeting) {
+			comparison.Unplanned = append(comparison.Unplanned, entry)
+		}
+	}
+
+	if due := comparison.Done + len(comparison.Slipped); due > 0 {
+		comparison.CompletionRate = float64(comparison.Done) / float64(due)
+	}
+	return comparison
+}
+
+// planKey normalizes a title for matching plans with what was logged
+func planKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
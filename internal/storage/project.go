@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Project is a long-running piece of work. An entry belongs to it when its
+// project metadata names it, or when it has one of the project's tags or
+// their children.
+type Project struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Start is the first day reported on by default
+	Start     time.Time `json:"start" yaml:"start"`
+	CreatedAt time.Time `json:"created_at" yaml:"created_at"`
+}
+
+// ProjectList is the stored list of projects, in name order
+type ProjectList struct {
+	Projects []Project `json:"projects"`
+
+	// Revision identifies the stored version the list was read from; it is never serialized
+	Revision string `json:"-"`
+}
+
+// ProjectStore is storage that keeps a list of projects alongside the logs
+type ProjectStore interface {
+	GetProjects() (*ProjectList, error)
+	// SaveProjects stores the list, failing if it changed since it was read
+	SaveProjects(list *ProjectList) error
+}
+
+// Find returns the project named name, ignoring case
+func (l *ProjectList) Find(name string) (*Project, bool) {
+	for i := range l.Projects {
+		if strings.EqualFold(l.Projects[i].Name, strings.TrimSpace(name)) {
+			return &l.Projects[i], true
+		}
+	}
+	return nil, false
+}
+
+// Add adds a project, keeping the list in name order
+func (l *ProjectList) Add(project Project) error {
+	project.Name = strings.TrimSpace(project.Name)
+	if project.Name == "" {
+		return ValidationError{Field: "name", Message: "is required"}
+	}
+	if _, exists := l.Find(project.Name); exists {
+		return ValidationError{Field: "name", Message: fmt.Sprintf("project %q already exists", project.Name)}
+	}
+	l.Projects = append(l.Projects, project)
+	sort.Slice(l.Projects, func(i, j int) bool {
+		return strings.ToLower(l.Projects[i].Name) < strings.ToLower(l.Projects[j].Name)
+	})
+	return nil
+}
+
+// Includes reports whether an entry belongs to the project
+func (p Project) Includes(entry DailyLogEntry, tags TagAliases) bool {
+	if strings.EqualFold(strings.TrimSpace(entry.Metadata[MetadataProject]), p.Name) {
+		return true
+	}
+	return len(p.Tags) > 0 && tags.HasAnyTag(entry, p.Tags)
+}
+
+// ProjectEntries returns the entries belonging to the project, in order
+func (p Project) ProjectEntries(entries []DailyLogEntry, tags TagAliases) []DailyLogEntry {
+	var included []DailyLogEntry
+	for _, entry := range entries {
+		if p.Includes(entry, tags) {
+			included = append(included, entry)
+		}
+	}
+	return included
+}
+
+// ProjectReport rolls up a project's entries over a period
+type ProjectReport struct {
+	Project Project           `json:"project" yaml:"project"`
+	Period  string            `json:"period" yaml:"period"`
+	Totals  AggregationBucket `json:"totals" yaml:"totals"`
+	// FirstEntry and LastEntry are when the project's entries of the period were logged
+	FirstEntry *time.Time          `json:"first_entry,omitempty" yaml:"first_entry,omitempty"`
+	LastEntry  *time.Time          `json:"last_entry,omitempty" yaml:"last_entry,omitempty"`
+	Weeks      []AggregationBucket `json:"weeks" yaml:"weeks"`
+	Types      []AggregationBucket `json:"types" yaml:"types"`
+	// Summaries are the project's summary entries and lessons, oldest first
+	Summaries []DailyLogEntry `json:"summaries,omitempty" yaml:"summaries,omitempty"`
+	Entries   []DailyLogEntry `json:"entries" yaml:"entries"`
+}
+
+// ReportProject rolls up the entries of the project among entries
+func ReportProject(project Project, entries []DailyLogEntry, tags TagAliases) ProjectReport {
+	report := ProjectReport{Project: project, Entries: project.ProjectEntries(entries, tags)}
+	if report.Entries == nil {
+		report.Entries = []DailyLogEntry{}
+	}
+	report.Totals = SummarizeEntries(report.Entries)
+	report.Weeks = AggregateEntries(report.Entries, GroupByWeek).Buckets
+	report.Types = AggregateEntries(report.Entries, GroupByType).Buckets
+	for _, entry := range report.Entries {
+		if entry.Type == "summary" || IsLesson(entry, tags) {
+			report.Summaries = append(report.Summaries, entry)
+		}
+		if report.FirstEntry == nil || entry.Timestamp.Before(*report.FirstEntry) {
+			logged := entry.Timestamp
+			report.FirstEntry = &logged
+		}
+		if report.LastEntry == nil || entry.Timestamp.After(*report.LastEntry) {
+			logged := entry.Timestamp
+			report.LastEntry = &logged
+		}
+	}
+	return report
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseQuickEntry parses one-line capture text such as
+// "Fixed prod bug #work #incident @office !p2 ~45m status:7" into a create
+// request. #word adds a tag, @word sets the location, !pN (or !N) the
+// priority, ~45m or ~1h30m the duration (bare numbers are minutes), and
+// status:N the status. The remaining words form the title. Date and Type are
+// left for the caller to set.
+func ParseQuickEntry(text string) (CreateLogEntryRequest, error) {
+	var req CreateLogEntryRequest
+	var title []string
+
+	for _, word := range strings.Fields(text) {
+		lower := strings.ToLower(word)
+		switch {
+		case len(word) > 1 && word[0] == '#':
+			req.Tags = append(req.Tags, word[1:])
+
+		case len(word) > 1 && word[0] == '@':
+			req.Location = word[1:]
+
+		case len(word) > 1 && word[0] == '!':
+			priority, err := strconv.Atoi(strings.TrimPrefix(lower[1:], "p"))
+			if err != nil || priority < 1 || priority > 5 {
+				return req, ValidationError{
+					Field:   "priority",
+					Message: fmt.Sprintf("%q must be !p1 to !p5", word),
+				}
+			}
+			req.Priority = &priority
+
+		case len(word) > 1 && word[0] == '~':
+			duration, err := ParseDurationMinutes(word[1:])
+			if err != nil {
+				return req, ValidationError{
+					Field:   "duration",
+					Message: fmt.Sprintf("%q must be minutes or a duration like ~45m or ~1h30m", word),
+				}
+			}
+			req.Duration = &duration
+
+		case strings.HasPrefix(lower, "status:"):
+			status, err := strconv.Atoi(word[len("status:"):])
+			if err != nil || status < 1 || status > 10 {
+				return req, ValidationError{
+					Field:   "status",
+					Message: fmt.Sprintf("%q must be status:1 to status:10", word),
+				}
+			}
+			req.Status = &status
+
+		default:
+			title = append(title, word)
+		}
+	}
+
+	req.Title = strings.Join(title, " ")
+	if req.Title == "" {
+		return req, ValidationError{Field: "title", Message: "quick entry needs some text besides markers"}
+	}
+	return req, nil
+}
+
+// ParseDurationMinutes returns the minutes in "45", "45m", "2h", or "1h30m"
+func ParseDurationMinutes(s string) (int, error) {
+	if minutes, err := strconv.Atoi(s); err == nil && minutes > 0 {
+		return minutes, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < time.Minute {
+		return 0, fmt.Errorf("invalid duration: %s", s)
+	}
+	return int(d.Minutes()), nil
+}
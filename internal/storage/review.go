@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReviewBacklog counts the entries of a period still to be reviewed
+type ReviewBacklog struct {
+	Entries    int `json:"entries" yaml:"entries"`
+	Reviewed   int `json:"reviewed" yaml:"reviewed"`
+	Unreviewed int `json:"unreviewed" yaml:"unreviewed"`
+	// OldestUnreviewed is when the earliest entry not yet reviewed was logged
+	OldestUnreviewed *time.Time `json:"oldest_unreviewed,omitempty" yaml:"oldest_unreviewed,omitempty"`
+	// Weeks breaks the backlog down by ISO week, such as "2025-W39"
+	Weeks []ReviewWeek `json:"weeks" yaml:"weeks"`
+}
+
+// ReviewWeek is the review backlog of one week
+type ReviewWeek struct {
+	Week       string `json:"week" yaml:"week"`
+	Entries    int    `json:"entries" yaml:"entries"`
+	Unreviewed int    `json:"unreviewed" yaml:"unreviewed"`
+}
+
+// Reviewed reports whether the entry has been marked reviewed
+func (e DailyLogEntry) Reviewed() bool {
+	return e.ReviewedAt != nil && !e.ReviewedAt.IsZero()
+}
+
+// UnreviewedEntries returns the entries not yet marked reviewed, in order
+func UnreviewedEntries(entries []DailyLogEntry) []DailyLogEntry {
+	var unreviewed []DailyLogEntry
+	for _, entry := range entries {
+		if !entry.Reviewed() {
+			unreviewed = append(unreviewed, entry)
+		}
+	}
+	return unreviewed
+}
+
+// SummarizeReviews counts the reviewed and unreviewed entries, overall and
+// by week
+func SummarizeReviews(entries []DailyLogEntry) ReviewBacklog {
+	backlog := ReviewBacklog{Weeks: []ReviewWeek{}}
+	weeks := make(map[string]*ReviewWeek)
+	for _, entry := range entries {
+		year, number := entry.Timestamp.ISOWeek()
+		key := fmt.Sprintf("%04d-W%02d", year, number)
+		week := weeks[key]
+		if week == nil {
+			week = &ReviewWeek{Week: key}
+			weeks[key] = week
+		}
+
+		backlog.Entries++
+		week.Entries++
+		if entry.Reviewed() {
+			backlog.Reviewed++
+			continue
+		}
+		backlog.Unreviewed++
+		week.Unreviewed++
+		if backlog.OldestUnreviewed == nil || entry.Timestamp.Before(*backlog.OldestUnreviewed) {
+			logged := entry.Timestamp
+			backlog.OldestUnreviewed = &logged
+		}
+	}
+
+	for _, week := range weeks {
+		backlog.Weeks = append(backlog.Weeks, *week)
+	}
+	sort.Slice(backlog.Weeks, func(i, j int) bool { return backlog.Weeks[i].Week < backlog.Weeks[j].Week })
+	return backlog
+}
@@ -0,0 +1,364 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DayEditSession applies changes to one day's log in memory and saves them
+// all at once with Commit, so a bulk edit costs one read and one write
+// instead of one of each per change. Entries are looked up by full ID or an
+// unambiguous ID prefix.
+type DayEditSession struct {
+	store   DayStore
+	day     *DayLog
+	changed bool
+}
+
+// BeginDayEdit loads the day to edit. A day with no log yet starts empty.
+func BeginDayEdit(store DayStore, date time.Time) (*DayEditSession, error) {
+	dayLog, err := store.GetDay(date)
+	if err != nil {
+		return nil, err
+	}
+	return &DayEditSession{store: store, day: dayLog}, nil
+}
+
+// Day returns the day as edited so far
+func (s *DayEditSession) Day() *DayLog {
+	return s.day
+}
+
+// Changed reports whether there are edits not yet committed
+func (s *DayEditSession) Changed() bool {
+	return s.changed
+}
+
+// Add adds a new entry. A request without a date is timestamped now on the
+// session's day; one dated another day is rejected.
+func (s *DayEditSession) Add(req CreateLogEntryRequest) (*DailyLogEntry, error) {
+	if req.Date.IsZero() {
+		now := time.Now()
+		req.Date = time.Date(s.day.Date.Year(), s.day.Date.Month(), s.day.Date.Day(),
+			now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location())
+	} else if req.Date.Format("2006-01-02") != s.day.GetDateString() {
+		return nil, ValidationError{
+			Field:   "date",
+			Message: fmt.Sprintf("entry is dated %s but this session edits %s", req.Date.Format("2006-01-02"), s.day.GetDateString()),
+		}
+	}
+
+	entry := NewEntry(req)
+	s.day.AddEntry(entry)
+	s.changed = true
+	return &entry, nil
+}
+
+// Insert adds an existing entry as it is, keeping its ID. It must be
+// timestamped on the session's day and not already be in the log.
+func (s *DayEditSession) Insert(entry DailyLogEntry) error {
+	if entry.Timestamp.Format("2006-01-02") != s.day.GetDateString() {
+		return ValidationError{
+			Field:   "date",
+			Message: fmt.Sprintf("entry is dated %s but this session edits %s", entry.Timestamp.Format("2006-01-02"), s.day.GetDateString()),
+		}
+	}
+	for _, existing := range s.day.Entries {
+		if existing.ID == entry.ID {
+			return ValidationError{Field: "id", Message: fmt.Sprintf("entry %s is already logged on %s", entry.ID, s.day.GetDateString())}
+		}
+	}
+
+	s.day.AddEntry(entry)
+	s.changed = true
+	return nil
+}
+
+// Update changes the fields set in req on an existing entry. New metadata
+// keys are merged in, and a key set to "" is removed.
+func (s *DayEditSession) Update(req UpdateLogEntryRequest) (*DailyLogEntry, error) {
+	existing, err := ResolveEntryID(req.ID, s.day.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := req.Apply(*existing)
+	s.day.UpdateEntry(entry.ID, entry)
+	s.changed = true
+	return &entry, nil
+}
+
+// Remove removes an entry
+func (s *DayEditSession) Remove(id string) error {
+	existing, err := ResolveEntryID(id, s.day.Entries)
+	if err != nil {
+		return err
+	}
+
+	s.day.RemoveEntry(existing.ID)
+	s.changed = true
+	return nil
+}
+
+// Move moves an entry to position index, counted from 0; an index past
+// the end moves it last
+func (s *DayEditSession) Move(id string, index int) error {
+	existing, err := ResolveEntryID(id, s.day.Entries)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		return ValidationError{Field: "index", Message: "must not be negative"}
+	}
+
+	entry := *existing
+	s.day.RemoveEntry(entry.ID)
+	if index > len(s.day.Entries) {
+		index = len(s.day.Entries)
+	}
+	s.day.Entries = append(s.day.Entries[:index], append([]DailyLogEntry{entry}, s.day.Entries[index:]...)...)
+	s.day.TotalEntries = len(s.day.Entries)
+	s.day.calculateStatusAverage()
+	s.changed = true
+	return nil
+}
+
+// Split splits an entry into one entry per part, in its place; see SplitEntry
+func (s *DayEditSession) Split(id string, parts []string) ([]DailyLogEntry, error) {
+	existing, err := ResolveEntryID(id, s.day.Entries)
+	if err != nil {
+		return nil, err
+	}
+	split, err := SplitEntry(*existing, parts)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, entry := range s.day.Entries {
+		if entry.ID == existing.ID {
+			rest := append(split[1:], s.day.Entries[i+1:]...)
+			s.day.Entries = append(s.day.Entries[:i:i], split[0])
+			s.day.Entries = append(s.day.Entries, rest...)
+			break
+		}
+	}
+	s.day.TotalEntries = len(s.day.Entries)
+	s.day.UpdatedAt = time.Now()
+	s.day.calculateStatusAverage()
+	s.changed = true
+	return split, nil
+}
+
+// Merge merges entries into the earliest of them; see MergeEntries. Links
+// from the day's other entries to the merged ones move to the result.
+func (s *DayEditSession) Merge(ids []string, title string) (*DailyLogEntry, error) {
+	var entries []DailyLogEntry
+	seen := map[string]bool{}
+	for _, id := range ids {
+		existing, err := ResolveEntryID(id, s.day.Entries)
+		if err != nil {
+			return nil, err
+		}
+		if seen[existing.ID] {
+			return nil, ValidationError{Field: "ids", Message: fmt.Sprintf("entry %s is listed more than once", existing.ID)}
+		}
+		seen[existing.ID] = true
+		entries = append(entries, *existing)
+	}
+	merged, err := MergeEntries(entries, title)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.ID != merged.ID {
+			s.day.RemoveEntry(entry.ID)
+		}
+	}
+	s.day.UpdateEntry(merged.ID, merged)
+	for i, entry := range s.day.Entries {
+		if source := entry.Metadata[MetadataSourceID]; seen[source] && source != merged.ID {
+			s.day.Entries[i].Metadata = copyMetadata(entry.Metadata)
+			s.day.Entries[i].Metadata[MetadataSourceID] = merged.ID
+		}
+	}
+	s.changed = true
+	return &merged, nil
+}
+
+// Sort reorders the day's entries
+func (s *DayEditSession) Sort(order SortOrder) {
+	SortEntries(s.day.Entries, order)
+	s.day.UpdatedAt = time.Now()
+	s.changed = true
+}
+
+// Commit saves the edited day in a single write. It does nothing when
+// nothing has changed, and the session can keep editing afterwards.
+func (s *DayEditSession) Commit() error {
+	if !s.changed {
+		return nil
+	}
+	if err := s.store.SaveDay(s.day); err != nil {
+		return err
+	}
+	s.changed = false
+	return nil
+}
+
+// CreateRequest returns the request NewEntry would make e from, but for its
+// ID, pin, and review, for hooks on entries saved with a whole day
+func (e DailyLogEntry) CreateRequest() CreateLogEntryRequest {
+	req := CreateLogEntryRequest{
+		Date:        e.Timestamp,
+		Type:        e.Type,
+		Title:       e.Title,
+		Description: e.Description,
+		Tags:        e.Tags,
+		Duration:    e.Duration,
+		Location:    e.Location,
+		Visibility:  e.Visibility,
+		Author:      e.Author,
+		Metadata:    e.Metadata,
+	}
+	if e.Status != 0 {
+		status := e.Status
+		req.Status = &status
+	}
+	if e.Priority != 0 {
+		priority := e.Priority
+		req.Priority = &priority
+	}
+	return req
+}
+
+// Recreate returns e with the fields of req, as changed by hooks, keeping
+// its ID, time, pin, and review
+func (e DailyLogEntry) Recreate(req CreateLogEntryRequest) DailyLogEntry {
+	entry := NewEntry(req)
+	entry.ID, entry.Timestamp = e.ID, e.Timestamp
+	entry.Pinned, entry.ReviewedAt = e.Pinned, e.ReviewedAt
+	return entry
+}
+
+// UpdateRequest returns a request with the fields that differ between from
+// and to, so that applied to to, it changes nothing. Cleared fields are
+// left out, as a request can't clear them.
+func UpdateRequest(from, to DailyLogEntry) UpdateLogEntryRequest {
+	req := UpdateLogEntryRequest{ID: to.ID}
+	if to.Type != from.Type {
+		req.Type = to.Type
+	}
+	if to.Title != from.Title {
+		req.Title = to.Title
+	}
+	if to.Description != from.Description {
+		req.Description = to.Description
+	}
+	if !slices.Equal(to.Tags, from.Tags) {
+		req.Tags = to.Tags
+	}
+	if to.Status != from.Status && to.Status != 0 {
+		status := to.Status
+		req.Status = &status
+	}
+	if to.Priority != from.Priority && to.Priority != 0 {
+		priority := to.Priority
+		req.Priority = &priority
+	}
+	if to.Duration != nil && (from.Duration == nil || *to.Duration != *from.Duration) {
+		duration := *to.Duration
+		req.Duration = &duration
+	}
+	if to.Location != from.Location {
+		req.Location = to.Location
+	}
+	if to.Visibility != from.Visibility {
+		req.Visibility = to.Visibility
+	}
+	if to.Pinned != from.Pinned {
+		pinned := to.Pinned
+		req.Pinned = &pinned
+	}
+	switch {
+	case to.ReviewedAt == nil && from.ReviewedAt != nil:
+		req.ReviewedAt = &time.Time{}
+	case to.ReviewedAt != nil && (from.ReviewedAt == nil || !to.ReviewedAt.Equal(*from.ReviewedAt)):
+		reviewedAt := *to.ReviewedAt
+		req.ReviewedAt = &reviewedAt
+	}
+	for k, v := range to.Metadata {
+		if from.Metadata[k] != v {
+			if req.Metadata == nil {
+				req.Metadata = map[string]string{}
+			}
+			req.Metadata[k] = v
+		}
+	}
+	for k := range from.Metadata {
+		if _, ok := to.Metadata[k]; !ok {
+			if req.Metadata == nil {
+				req.Metadata = map[string]string{}
+			}
+			req.Metadata[k] = ""
+		}
+	}
+	return req
+}
+
+// Apply returns entry with the fields set in req changed
+func (req UpdateLogEntryRequest) Apply(entry DailyLogEntry) DailyLogEntry {
+	if req.Type != "" {
+		entry.Type = req.Type
+	}
+	if req.Title != "" {
+		entry.Title = req.Title
+	}
+	if req.Description != "" {
+		entry.Description = req.Description
+	}
+	if req.Tags != nil {
+		entry.Tags = req.Tags
+	}
+	if req.Status != nil {
+		entry.Status = *req.Status
+	}
+	if req.Priority != nil {
+		entry.Priority = *req.Priority
+	}
+	if req.Duration != nil {
+		entry.Duration = req.Duration
+	}
+	if req.Location != "" {
+		entry.Location = req.Location
+	}
+	if req.Visibility != "" {
+		entry.Visibility = req.Visibility
+	}
+	if req.Pinned != nil {
+		entry.Pinned = *req.Pinned
+	}
+	if req.ReviewedAt != nil {
+		entry.ReviewedAt = nil
+		if !req.ReviewedAt.IsZero() {
+			reviewedAt := *req.ReviewedAt
+			entry.ReviewedAt = &reviewedAt
+		}
+	}
+	if len(req.Metadata) > 0 {
+		metadata := make(map[string]string, len(entry.Metadata)+len(req.Metadata))
+		for k, v := range entry.Metadata {
+			metadata[k] = v
+		}
+		for k, v := range req.Metadata {
+			if v == "" {
+				delete(metadata, k)
+			} else {
+				metadata[k] = v
+			}
+		}
+		entry.Metadata = metadata
+	}
+	return entry
+}
@@ -0,0 +1,161 @@
+package storage
+
+import "sort"
+
+// MetricSleep is the metric of the hours slept
+const MetricSleep = "sleep"
+
+// sleepMorningEnd is the hour until which sleep is taken to be logged for
+// the night before; sleep logged later is for the coming night
+const sleepMorningEnd = 12
+
+// SleepDay is a night's sleep and how the day after it went
+type SleepDay struct {
+	Date           string  `json:"date" yaml:"date"`                         // The day after the night
+	Sleep          float64 `json:"sleep" yaml:"sleep"`                       // Hours
+	Status         float64 `json:"status,omitempty" yaml:"status,omitempty"` // The day's average status, 0 if none
+	TrackedMinutes int     `json:"tracked_minutes" yaml:"tracked_minutes"`
+}
+
+// SleepBand rolls up the days after nights of about the same length
+type SleepBand struct {
+	Label          string  `json:"label" yaml:"label"` // e.g. "7-8h"
+	Days           int     `json:"days" yaml:"days"`
+	AverageStatus  float64 `json:"average_status,omitempty" yaml:"average_status,omitempty"`
+	AverageTracked float64 `json:"average_tracked_minutes" yaml:"average_tracked_minutes"`
+}
+
+// SleepAnalysis cross-references sleep with the mood and productivity of
+// the next day
+type SleepAnalysis struct {
+	Days         []SleepDay  `json:"days" yaml:"days"`
+	AverageSleep float64     `json:"average_sleep" yaml:"average_sleep"`
+	Bands        []SleepBand `json:"bands" yaml:"bands"`
+	// Mood correlates sleep with the day's average status, and Productivity
+	// with its tracked time; each is nil with too few days to tell
+	Mood         *MetricCorrelation `json:"mood,omitempty" yaml:"mood,omitempty"`
+	Productivity *MetricCorrelation `json:"productivity,omitempty" yaml:"productivity,omitempty"`
+}
+
+// sleepBands are the bands nights are grouped in, by their lower bound in hours
+var sleepBands = []struct {
+	label string
+	from  float64
+}{
+	{"< 6h", 0},
+	{"6-7h", 6},
+	{"7-8h", 7},
+	{"8h+", 8},
+}
+
+// AnalyzeSleep joins the sleep metric entries among entries with the status
+// and tracked time of the day after each night. Sleep logged before noon is
+// for the night before that day, and later, for the night after it. Days
+// after a night that logged no status or tracked time count only towards
+// what they did log.
+func AnalyzeSleep(entries []DailyLogEntry) SleepAnalysis {
+	sleepSum := make(map[string]float64)
+	sleepCount := make(map[string]int)
+	statusSum := make(map[string]int)
+	statusCount := make(map[string]int)
+	tracked := make(map[string]int)
+	for _, entry := range entries {
+		day := entry.Timestamp.Format("2006-01-02")
+		if m, ok := MetricOf(entry); ok {
+			if m.Name == MetricSleep {
+				if entry.Timestamp.Hour() >= sleepMorningEnd {
+					day = entry.Timestamp.AddDate(0, 0, 1).Format("2006-01-02")
+				}
+				sleepSum[day] += m.Value
+				sleepCount[day]++
+			}
+			continue
+		}
+		if IsPlanned(entry) {
+			continue
+		}
+		if entry.Status > 0 {
+			statusSum[day] += entry.Status
+			statusCount[day]++
+		}
+		if entry.Duration != nil {
+			tracked[day] += *entry.Duration
+		}
+	}
+
+	analysis := SleepAnalysis{Days: []SleepDay{}, Bands: []SleepBand{}}
+	for day, sum := range sleepSum {
+		d := SleepDay{Date: day, Sleep: sum / float64(sleepCount[day]), TrackedMinutes: tracked[day]}
+		if statusCount[day] > 0 {
+			d.Status = float64(statusSum[day]) / float64(statusCount[day])
+		}
+		analysis.Days = append(analysis.Days, d)
+		analysis.AverageSleep += d.Sleep
+	}
+	if len(analysis.Days) == 0 {
+		return analysis
+	}
+	analysis.AverageSleep /= float64(len(analysis.Days))
+	sort.Slice(analysis.Days, func(i, j int) bool { return analysis.Days[i].Date < analysis.Days[j].Date })
+
+	var sleepByMood, moods, sleepByTracked, trackedTimes []float64
+	bands := make([]SleepBand, len(sleepBands))
+	statusDays := make([]int, len(sleepBands))
+	trackedDays := make([]int, len(sleepBands))
+	for _, d := range analysis.Days {
+		if d.Status > 0 {
+			sleepByMood = append(sleepByMood, d.Sleep)
+			moods = append(moods, d.Status)
+		}
+		if d.TrackedMinutes > 0 {
+			sleepByTracked = append(sleepByTracked, d.Sleep)
+			trackedTimes = append(trackedTimes, float64(d.TrackedMinutes))
+		}
+
+		band := 0
+		for i, b := range sleepBands {
+			if d.Sleep >= b.from {
+				band = i
+			}
+		}
+		bands[band].Days++
+		bands[band].AverageStatus += d.Status
+		bands[band].AverageTracked += float64(d.TrackedMinutes)
+		if d.Status > 0 {
+			statusDays[band]++
+		}
+		if d.TrackedMinutes > 0 {
+			trackedDays[band]++
+		}
+	}
+	for i, band := range bands {
+		if band.Days == 0 {
+			continue
+		}
+		band.Label = sleepBands[i].label
+		if statusDays[i] > 0 {
+			band.AverageStatus /= float64(statusDays[i])
+		}
+		if trackedDays[i] > 0 {
+			band.AverageTracked /= float64(trackedDays[i])
+		}
+		analysis.Bands = append(analysis.Bands, band)
+	}
+
+	analysis.Mood = correlateSleep("status", sleepByMood, moods)
+	analysis.Productivity = correlateSleep("tracked time", sleepByTracked, trackedTimes)
+	return analysis
+}
+
+// correlateSleep correlates sleep with another daily value, or returns nil
+// with too few days or without variation
+func correlateSleep(name string, sleep, values []float64) *MetricCorrelation {
+	if len(sleep) < minCorrelationDays {
+		return nil
+	}
+	r, ok := pearson(sleep, values)
+	if !ok {
+		return nil
+	}
+	return &MetricCorrelation{Metric: name, Days: len(sleep), Correlation: r}
+}
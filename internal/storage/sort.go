@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+)
+
+// Sort keys for entries
+const (
+	SortByTime     = "time"
+	SortByStatus   = "status"
+	SortByPriority = "priority"
+)
+
+// SortOrder orders entries by a key, ascending unless Descending is set
+type SortOrder struct {
+	Key        string
+	Descending bool
+}
+
+// DefaultSortOrder lists entries oldest first
+var DefaultSortOrder = SortOrder{Key: SortByTime}
+
+// ParseSortOrder parses a sort spec such as "time", "time:desc", "status",
+// or "priority:asc". "mood" is another name for status. Without a direction,
+// time sorts oldest first, status best mood first, and priority most urgent
+// first. An empty spec is the default order.
+func ParseSortOrder(spec string) (SortOrder, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		return DefaultSortOrder, nil
+	}
+
+	key, direction, _ := strings.Cut(spec, ":")
+	var order SortOrder
+	switch key {
+	case SortByTime:
+		order = SortOrder{Key: SortByTime}
+	case SortByStatus, "mood":
+		order = SortOrder{Key: SortByStatus, Descending: true}
+	case SortByPriority:
+		order = SortOrder{Key: SortByPriority}
+	default:
+		return SortOrder{}, ValidationError{
+			Field:   "sort",
+			Message: "unknown sort key " + key + " (use time, status, or priority)",
+		}
+	}
+
+	switch direction {
+	case "":
+	case "asc":
+		order.Descending = false
+	case "desc":
+		order.Descending = true
+	default:
+		return SortOrder{}, ValidationError{
+			Field:   "sort",
+			Message: "unknown sort direction " + direction + " (use asc or desc)",
+		}
+	}
+	return order, nil
+}
+
+// String returns the spec ParseSortOrder reads back as the same order
+func (o SortOrder) String() string {
+	if o.Descending {
+		return o.Key + ":desc"
+	}
+	return o.Key + ":asc"
+}
+
+// SortEntries sorts entries in place. Entries without a status or priority
+// come last when sorting by it, and ties keep time order.
+func SortEntries(entries []DailyLogEntry, order SortOrder) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		switch order.Key {
+		case SortByStatus:
+			if a.Status != b.Status {
+				return rankedBefore(a.Status, b.Status, order.Descending)
+			}
+		case SortByPriority:
+			if a.Priority != b.Priority {
+				return rankedBefore(a.Priority, b.Priority, order.Descending)
+			}
+		default:
+			if order.Descending {
+				return a.Timestamp.After(b.Timestamp)
+			}
+		}
+		return a.Timestamp.Before(b.Timestamp)
+	})
+}
+
+// rankedBefore compares two different ratings, where 0 means unset and sorts last
+func rankedBefore(a, b int, descending bool) bool {
+	switch {
+	case a == 0:
+		return false
+	case b == 0:
+		return true
+	case descending:
+		return a > b
+	default:
+		return a < b
+	}
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Metadata keys recording which entries a split or merged entry came from
+const (
+	MetadataSplitFrom  = "split_from"
+	MetadataMergedFrom = "merged_from"
+)
+
+// SplitText cuts text at each delimiter into the parts of a split entry,
+// dropping empty parts
+func SplitText(text, delimiter string) []string {
+	var parts []string
+	for _, part := range strings.Split(text, delimiter) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+// SplitEntry splits entry into one entry per part. The first line of a part
+// is its title and the rest its description. The first part keeps the
+// entry's ID, so links to it still work; the others get new IDs and a
+// split_from link back to it. All parts keep the entry's time, type, tags,
+// ratings, and metadata, and share its duration evenly.
+func SplitEntry(entry DailyLogEntry, parts []string) ([]DailyLogEntry, error) {
+	if len(parts) < 2 {
+		return nil, ValidationError{Field: "parts", Message: "an entry must be split into at least two parts"}
+	}
+
+	var minutes, extra int
+	if entry.Duration != nil {
+		minutes, extra = *entry.Duration/len(parts), *entry.Duration%len(parts)
+	}
+
+	split := make([]DailyLogEntry, len(parts))
+	for i, part := range parts {
+		title, description, _ := strings.Cut(strings.TrimSpace(part), "\n")
+		if title = strings.TrimSpace(title); title == "" {
+			return nil, ValidationError{Field: "parts", Message: fmt.Sprintf("part %d has no title", i+1)}
+		}
+
+		piece := entry
+		piece.Title = title
+		piece.Description = strings.TrimSpace(description)
+		piece.Tags = append([]string(nil), entry.Tags...)
+		piece.Metadata = copyMetadata(entry.Metadata)
+		if entry.Duration != nil {
+			duration := minutes
+			if i < extra {
+				duration++
+			}
+			piece.Duration = &duration
+		}
+		if i > 0 {
+			piece.ID = NewEntryIDAt(entry.Timestamp)
+			if piece.Metadata == nil {
+				piece.Metadata = map[string]string{}
+			}
+			piece.Metadata[MetadataSplitFrom] = entry.ID
+		}
+		split[i] = piece
+	}
+	return split, nil
+}
+
+// MergeEntries combines entries into one. The earliest entry keeps its ID,
+// time, type, and metadata, and gets a merged_from list of the other IDs.
+// The others' titles and descriptions are appended to its description; tags
+// are combined, durations added up, statuses averaged, and the most urgent
+// priority kept. An empty title keeps the earliest entry's.
+func MergeEntries(entries []DailyLogEntry, title string) (DailyLogEntry, error) {
+	if len(entries) < 2 {
+		return DailyLogEntry{}, ValidationError{Field: "ids", Message: "at least two entries are needed to merge"}
+	}
+
+	entries = append([]DailyLogEntry(nil), entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	merged := entries[0]
+	if title = strings.TrimSpace(title); title != "" {
+		merged.Title = title
+	}
+	merged.Metadata = copyMetadata(merged.Metadata)
+	if merged.Metadata == nil {
+		merged.Metadata = map[string]string{}
+	}
+
+	var sections []string
+	if merged.Description != "" {
+		sections = append(sections, merged.Description)
+	}
+	var mergedIDs []string
+	if previous := merged.Metadata[MetadataMergedFrom]; previous != "" {
+		mergedIDs = strings.Split(previous, ",")
+	}
+	tags := map[string]bool{}
+	for _, tag := range merged.Tags {
+		tags[tag] = true
+	}
+	var statusTotal, statusCount int
+	if merged.Status > 0 {
+		statusTotal, statusCount = merged.Status, 1
+	}
+
+	for _, entry := range entries[1:] {
+		section := entry.Title
+		if entry.Description != "" {
+			section += "\n" + entry.Description
+		}
+		sections = append(sections, section)
+		mergedIDs = append(mergedIDs, entry.ID)
+
+		for _, tag := range entry.Tags {
+			if !tags[tag] {
+				tags[tag] = true
+				merged.Tags = append(merged.Tags, tag)
+			}
+		}
+		if entry.Duration != nil {
+			total := *entry.Duration
+			if merged.Duration != nil {
+				total += *merged.Duration
+			}
+			merged.Duration = &total
+		}
+		if entry.Status > 0 {
+			statusTotal += entry.Status
+			statusCount++
+		}
+		if entry.Priority > 0 && (merged.Priority == 0 || entry.Priority < merged.Priority) {
+			merged.Priority = entry.Priority
+		}
+		for key, value := range entry.Metadata {
+			if _, ok := merged.Metadata[key]; !ok && key != MetadataMergedFrom {
+				merged.Metadata[key] = value
+			}
+		}
+	}
+
+	merged.Description = strings.Join(sections, "\n\n")
+	if statusCount > 0 {
+		merged.Status = int(math.Round(float64(statusTotal) / float64(statusCount)))
+	}
+	merged.Metadata[MetadataMergedFrom] = strings.Join(mergedIDs, ",")
+	return merged, nil
+}
+
+func copyMetadata(metadata map[string]string) map[string]string {
+	if metadata == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(metadata))
+	for key, value := range metadata {
+		copied[key] = value
+	}
+	return copied
+}
@@ -0,0 +1,110 @@
+package storage
+
+import "strings"
+
+// TagSeparator separates the levels of a hierarchical tag such as "work/projectx"
+const TagSeparator = "/"
+
+// TagAliases maps a canonical tag to other names for it, e.g.
+// "work/projectx" to ["px"]. Aliases match case-insensitively and carry
+// their children along, so "px/api" becomes "work/projectx/api".
+type TagAliases map[string][]string
+
+// Normalize returns the canonical form of a tag
+func (a TagAliases) Normalize(tag string) string {
+	tag = strings.Trim(strings.TrimSpace(tag), TagSeparator)
+	for canonical, aliases := range a {
+		for _, alias := range aliases {
+			alias = strings.Trim(strings.TrimSpace(alias), TagSeparator)
+			if alias == "" || len(tag) < len(alias) || !strings.EqualFold(tag[:len(alias)], alias) {
+				continue
+			}
+			if rest := tag[len(alias):]; rest == "" || strings.HasPrefix(rest, TagSeparator) {
+				return canonical + rest
+			}
+		}
+	}
+	return tag
+}
+
+// NormalizeTags returns the canonical forms of tags, dropping duplicates
+func (a TagAliases) NormalizeTags(tags []string) []string {
+	if len(a) == 0 || len(tags) == 0 {
+		return tags
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = a.Normalize(tag)
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// NormalizeEntries returns copies of the entries with canonical tags
+func (a TagAliases) NormalizeEntries(entries []DailyLogEntry) []DailyLogEntry {
+	if len(a) == 0 {
+		return entries
+	}
+	normalized := make([]DailyLogEntry, len(entries))
+	for i, entry := range entries {
+		entry.Tags = a.NormalizeTags(entry.Tags)
+		normalized[i] = entry
+	}
+	return normalized
+}
+
+// HasAnyTag reports whether any of the entry's tags matches any of the
+// filters, after resolving aliases on both sides
+func (a TagAliases) HasAnyTag(entry DailyLogEntry, filters []string) bool {
+	for _, filter := range filters {
+		filter = a.Normalize(filter)
+		for _, tag := range entry.Tags {
+			if TagMatches(filter, a.Normalize(tag)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FilterDay returns a copy of the day with only the entries matching one of
+// the tag filters; no filters keep every entry
+func (a TagAliases) FilterDay(dayLog DayLog, filters []string) DayLog {
+	if len(filters) == 0 {
+		return dayLog
+	}
+	filtered := dayLog
+	filtered.Entries = nil
+	for _, entry := range dayLog.Entries {
+		if a.HasAnyTag(entry, filters) {
+			filtered.Entries = append(filtered.Entries, entry)
+		}
+	}
+	filtered.TotalEntries = len(filtered.Entries)
+	filtered.calculateStatusAverage()
+	return filtered
+}
+
+// TagMatches reports whether tag is filter itself or one of its children,
+// so "work" matches "work" and "work/projectx" but not "workout"
+func TagMatches(filter, tag string) bool {
+	return tag == filter || strings.HasPrefix(tag, filter+TagSeparator)
+}
+
+// TagAncestors returns a tag and each of its parents, outermost first:
+// "work/projectx/api" gives "work", "work/projectx", "work/projectx/api"
+func TagAncestors(tag string) []string {
+	parts := strings.Split(tag, TagSeparator)
+	ancestors := make([]string, 0, len(parts))
+	for i := range parts {
+		if parts[i] == "" {
+			continue
+		}
+		ancestors = append(ancestors, strings.Join(parts[:i+1], TagSeparator))
+	}
+	return ancestors
+}
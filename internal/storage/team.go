@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TeamLog is a teammate's log in a repository of their own, read for team
+// standups. Its entries without an author are Member's.
+type TeamLog struct {
+	Member string `mapstructure:"member" json:"member"`
+	Repo   string `mapstructure:"repo" json:"repo"`
+	Path   string `mapstructure:"path" json:"path,omitempty"`     // Default: github.path
+	Layout string `mapstructure:"layout" json:"layout,omitempty"` // Default: github.layout
+}
+
+// Validate checks that the log names its member and an owner/repo repository
+func (l TeamLog) Validate() error {
+	if l.Member == "" {
+		return ValidationError{Field: "team.logs", Message: fmt.Sprintf("the log in %q has no member", l.Repo)}
+	}
+	if owner, repo, ok := strings.Cut(l.Repo, "/"); !ok || owner == "" || repo == "" {
+		return ValidationError{Field: "team.logs", Message: fmt.Sprintf("%s's repo must be owner/repo (got %q)", l.Member, l.Repo)}
+	}
+	return ValidateLayout(l.Layout)
+}
+
+// AttributeTo sets the author of the entries that have none to member
+func AttributeTo(entries []DailyLogEntry, member string) []DailyLogEntry {
+	for i := range entries {
+		if entries[i].Author == "" {
+			entries[i].Author = member
+		}
+	}
+	return entries
+}
@@ -0,0 +1,90 @@
+package storage
+
+import "fmt"
+
+// Visibility levels, from most to least open
+const (
+	VisibilityPublic  = "public"
+	VisibilityTeam    = "team"
+	VisibilityPrivate = "private"
+)
+
+// visibilityRank orders levels so that a higher rank is more restricted
+var visibilityRank = map[string]int{
+	VisibilityPublic:  0,
+	VisibilityTeam:    1,
+	VisibilityPrivate: 2,
+}
+
+// ValidateVisibility checks that a visibility level is known (empty is allowed)
+func ValidateVisibility(visibility string) error {
+	if visibility == "" {
+		return nil
+	}
+	if _, ok := visibilityRank[visibility]; !ok {
+		return ValidationError{
+			Field:   "visibility",
+			Message: fmt.Sprintf("must be one of public, team, private (got %q)", visibility),
+		}
+	}
+	return nil
+}
+
+// VisibilityPolicy decides the effective visibility of entries.
+// An entry is as restricted as the most restricted of its own level and its tags' levels.
+type VisibilityPolicy struct {
+	// Default applies to entries without an explicit visibility (team if empty)
+	Default string `json:"default,omitempty"`
+	// Tags maps tag names to the minimum visibility of entries carrying them
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Effective returns the visibility level that applies to an entry
+func (p VisibilityPolicy) Effective(entry DailyLogEntry) string {
+	level := entry.Visibility
+	if level == "" {
+		level = p.Default
+	}
+	if level == "" {
+		level = VisibilityTeam
+	}
+
+	// A tag's level also covers its children, e.g. "health" covers "health/sleep"
+	for _, tag := range entry.Tags {
+		for _, ancestor := range TagAncestors(tag) {
+			if tagLevel, ok := p.Tags[ancestor]; ok && visibilityRank[tagLevel] > visibilityRank[level] {
+				level = tagLevel
+			}
+		}
+	}
+	return level
+}
+
+// VisibleTo reports whether an entry may be shown to the given audience.
+// An empty audience means private, i.e. everything is visible.
+func (p VisibilityPolicy) VisibleTo(entry DailyLogEntry, audience string) bool {
+	if audience == "" {
+		audience = VisibilityPrivate
+	}
+	return visibilityRank[p.Effective(entry)] <= visibilityRank[audience]
+}
+
+// Filter returns the entries visible to the given audience
+func (p VisibilityPolicy) Filter(entries []DailyLogEntry, audience string) []DailyLogEntry {
+	filtered := make([]DailyLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		if p.VisibleTo(entry, audience) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// FilterDay returns a copy of the day log containing only entries visible to the audience
+func (p VisibilityPolicy) FilterDay(dayLog DayLog, audience string) DayLog {
+	filtered := dayLog
+	filtered.Entries = p.Filter(dayLog.Entries, audience)
+	filtered.TotalEntries = len(filtered.Entries)
+	filtered.calculateStatusAverage()
+	return filtered
+}
@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MetadataWeather is the day metadata key holding the day's weather
+const MetadataWeather = "weather"
+
+// Weather is a day's weather at the place it was logged from
+type Weather struct {
+	Location      string  `json:"location" yaml:"location"`
+	Condition     string  `json:"condition" yaml:"condition"`               // e.g. "clear", "rain"
+	Code          int     `json:"code" yaml:"code"`                         // WMO weather code
+	TempMax       float64 `json:"temp_max" yaml:"temp_max"`                 // °C
+	TempMin       float64 `json:"temp_min" yaml:"temp_min"`                 // °C
+	Precipitation float64 `json:"precipitation" yaml:"precipitation"`       // mm
+	Source        string  `json:"source,omitempty" yaml:"source,omitempty"` // Service it came from
+}
+
+// String describes the weather, e.g. "rain, 12-18°C, 4.2mm"
+func (w Weather) String() string {
+	s := fmt.Sprintf("%s, %s-%s°C", w.Condition, formatMetricValue(w.TempMin), formatMetricValue(w.TempMax))
+	if w.Precipitation > 0 {
+		s += ", " + formatMetricValue(w.Precipitation) + "mm"
+	}
+	return s
+}
+
+// WeatherOf reads the weather from a day's metadata. Metadata read back from
+// storage holds it as a plain map, so it is decoded again.
+func WeatherOf(day DayLog) (Weather, bool) {
+	switch value := day.Metadata[MetadataWeather].(type) {
+	case Weather:
+		return value, true
+	case *Weather:
+		return *value, value != nil
+	case map[string]any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return Weather{}, false
+		}
+		var w Weather
+		if err := json.Unmarshal(data, &w); err != nil || w.Condition == "" {
+			return Weather{}, false
+		}
+		return w, true
+	}
+	return Weather{}, false
+}
+
+// SetWeather records the weather in a day's metadata
+func SetWeather(day *DayLog, w Weather) {
+	if day.Metadata == nil {
+		day.Metadata = make(map[string]any)
+	}
+	day.Metadata[MetadataWeather] = w
+}
+
+// WeatherSummary rolls up the weather of the days in a period that have it
+type WeatherSummary struct {
+	Days          int            `json:"days" yaml:"days"`
+	Conditions    map[string]int `json:"conditions" yaml:"conditions"` // Days per condition
+	AverageHigh   float64        `json:"average_high" yaml:"average_high"`
+	AverageLow    float64        `json:"average_low" yaml:"average_low"`
+	Precipitation float64        `json:"precipitation" yaml:"precipitation"` // Total mm
+}
+
+// SummarizeWeather rolls up the weather of days, or returns nil when none has any
+func SummarizeWeather(days []DayLog) *WeatherSummary {
+	s := &WeatherSummary{Conditions: make(map[string]int)}
+	for _, day := range days {
+		w, ok := WeatherOf(day)
+		if !ok {
+			continue
+		}
+		s.Days++
+		s.Conditions[w.Condition]++
+		s.AverageHigh += w.TempMax
+		s.AverageLow += w.TempMin
+		s.Precipitation += w.Precipitation
+	}
+	if s.Days == 0 {
+		return nil
+	}
+	s.AverageHigh /= float64(s.Days)
+	s.AverageLow /= float64(s.Days)
+	return s
+}
+
+// String describes the weather, e.g. "Weather: mostly clear (4 of 7 days),
+// highs averaging 18°C, 12mm of precipitation."
+func (s WeatherSummary) String() string {
+	if s.Days == 1 {
+		for condition := range s.Conditions {
+			return fmt.Sprintf("Weather: %s, %s-%s°C, %smm of precipitation.", condition,
+				formatMetricValue(s.AverageLow), formatMetricValue(s.AverageHigh), formatMetricValue(s.Precipitation))
+		}
+	}
+	conditions := make([]string, 0, len(s.Conditions))
+	for condition := range s.Conditions {
+		conditions = append(conditions, condition)
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		if s.Conditions[conditions[i]] != s.Conditions[conditions[j]] {
+			return s.Conditions[conditions[i]] > s.Conditions[conditions[j]]
+		}
+		return conditions[i] < conditions[j]
+	})
+	return fmt.Sprintf("Weather: mostly %s (%d of %d days), highs averaging %s°C, %smm of precipitation.",
+		conditions[0], s.Conditions[conditions[0]], s.Days, formatMetricValue(s.AverageHigh), formatMetricValue(s.Precipitation))
+}
+
+// ConditionStatus is the average status (mood) on days of one weather condition
+type ConditionStatus struct {
+	Condition     string  `json:"condition" yaml:"condition"`
+	Days          int     `json:"days" yaml:"days"`
+	AverageStatus float64 `json:"average_status" yaml:"average_status"`
+}
+
+// WeatherStatus relates status (mood) to the weather
+type WeatherStatus struct {
+	Conditions   []ConditionStatus   `json:"conditions" yaml:"conditions"`
+	Correlations []MetricCorrelation `json:"correlations,omitempty" yaml:"correlations,omitempty"`
+}
+
+// Weather factors correlated with status
+const (
+	WeatherFactorHigh          = "high temperature"
+	WeatherFactorPrecipitation = "precipitation"
+)
+
+// CorrelateWeatherWithStatus averages each day's status by weather condition,
+// most common first, and correlates it with the high temperature and
+// precipitation, over the days that have both a status and weather. It
+// returns nil when there are no such days.
+func CorrelateWeatherWithStatus(days []DayLog) *WeatherStatus {
+	type conditionTotal struct {
+		days int
+		sum  float64
+	}
+	conditions := make(map[string]*conditionTotal)
+	var highs, precipitation, statuses []float64
+	for _, day := range days {
+		w, ok := WeatherOf(day)
+		if !ok {
+			continue
+		}
+		sum, count := 0, 0
+		for _, entry := range day.Entries {
+			if entry.Status > 0 {
+				sum += entry.Status
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		status := float64(sum) / float64(count)
+		if conditions[w.Condition] == nil {
+			conditions[w.Condition] = &conditionTotal{}
+		}
+		conditions[w.Condition].days++
+		conditions[w.Condition].sum += status
+		highs = append(highs, w.TempMax)
+		precipitation = append(precipitation, w.Precipitation)
+		statuses = append(statuses, status)
+	}
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	result := &WeatherStatus{}
+	for condition, total := range conditions {
+		result.Conditions = append(result.Conditions, ConditionStatus{
+			Condition:     condition,
+			Days:          total.days,
+			AverageStatus: total.sum / float64(total.days),
+		})
+	}
+	sort.Slice(result.Conditions, func(i, j int) bool {
+		a, b := result.Conditions[i], result.Conditions[j]
+		if a.Days != b.Days {
+			return a.Days > b.Days
+		}
+		return a.Condition < b.Condition
+	})
+
+	if len(statuses) >= minCorrelationDays {
+		for _, factor := range []struct {
+			name   string
+			values []float64
+		}{{WeatherFactorHigh, highs}, {WeatherFactorPrecipitation, precipitation}} {
+			if r, ok := pearson(factor.values, statuses); ok {
+				result.Correlations = append(result.Correlations, MetricCorrelation{Metric: factor.name, Days: len(statuses), Correlation: r})
+			}
+		}
+	}
+	return result
+}
+
+// DayLocation returns the location most of a day's entries were logged at,
+// or "" when none has one
+func DayLocation(day DayLog) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, entry := range day.Entries {
+		location := strings.TrimSpace(entry.Location)
+		if location == "" {
+			continue
+		}
+		counts[location]++
+		if counts[location] > counts[best] || counts[location] == counts[best] && location < best {
+			best = location
+		}
+	}
+	return best
+}
@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// WellbeingBaselineWeeks is how many weeks before the one checked it is
+// compared with
+const WellbeingBaselineWeeks = 4
+
+// Wellbeing signals, each comparing the week with the baseline weeks
+const (
+	SignalMoodDecline = "mood_decline" // Average status dropped by a point or more
+	SignalMeetingTime = "meeting_time" // Meeting time rose by a quarter, and an hour or more
+	SignalBreakTime   = "break_time"   // Break time shrank by a quarter
+	SignalLateEntries = "late_entries" // Three or more entries late in the evening, more than before
+)
+
+// Thresholds of the wellbeing signals
+const (
+	moodDrop           = 1.0
+	meetingRiseFactor  = 1.25
+	breakShrinkFactor  = 0.75
+	minChangeMinutes   = 60 // Of meetings
+	minBaselineMinutes = 60 // Of breaks
+	lateEntriesMin     = 3
+	lateEveningStart   = 21 // Entries from 9pm
+	lateEveningEnd     = 5  // to 5am are late
+)
+
+// Risk levels of a wellbeing check, by how many signals fired
+const (
+	RiskLow      = "low"      // None or one
+	RiskModerate = "moderate" // Two
+	RiskHigh     = "high"     // Three or more
+)
+
+// WellbeingSignal compares one figure of the week with its weekly average
+// over the baseline weeks
+type WellbeingSignal struct {
+	Name      string  `json:"name" yaml:"name"`
+	Triggered bool    `json:"triggered" yaml:"triggered"`
+	Week      float64 `json:"week" yaml:"week"`
+	Baseline  float64 `json:"baseline" yaml:"baseline"`
+	Message   string  `json:"message" yaml:"message"`
+}
+
+// WellbeingCheck is a week's burnout risk: how many of the early warning
+// signals fired
+type WellbeingCheck struct {
+	WeekStart string            `json:"week_start" yaml:"week_start"`
+	WeekEnd   string            `json:"week_end" yaml:"week_end"`
+	Risk      string            `json:"risk" yaml:"risk"`
+	Score     int               `json:"score" yaml:"score"` // Signals triggered
+	Signals   []WellbeingSignal `json:"signals" yaml:"signals"`
+}
+
+// WellbeingWindow returns the days CheckWellbeing needs entries from for
+// the week starting on weekStart: the baseline weeks and the week itself
+func WellbeingWindow(weekStart time.Time) (time.Time, time.Time) {
+	return weekStart.AddDate(0, 0, -7*WellbeingBaselineWeeks), weekStart.AddDate(0, 0, 6)
+}
+
+// weekFigures are what a week's signals are worked out from
+type weekFigures struct {
+	entries                int
+	statusSum, statusCount int
+	meetingMinutes         int
+	breakMinutes           int
+	lateEntries            int
+}
+
+// CheckWellbeing compares the week starting on weekStart with the weeks
+// before it, among those with any entries, for a declining mood, more time
+// in meetings, less in breaks, and more entries late in the evening.
+// Meetings and breaks are classified by the focus rules.
+func CheckWellbeing(entries []DailyLogEntry, weekStart time.Time, rules FocusRules, tags TagAliases) WellbeingCheck {
+	weekStart = time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, weekStart.Location())
+	weeks := make([]weekFigures, WellbeingBaselineWeeks+1)
+	for _, entry := range entries {
+		if entry.Type == EntryTypeMetric || IsPlanned(entry) {
+			continue
+		}
+		t := entry.Timestamp.In(weekStart.Location())
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, weekStart.Location())
+		index := int(math.Floor(day.Sub(weekStart).Hours()/24/7)) + WellbeingBaselineWeeks
+		if index < 0 || index >= len(weeks) {
+			continue
+		}
+
+		w := &weeks[index]
+		w.entries++
+		if entry.Status > 0 {
+			w.statusSum += entry.Status
+			w.statusCount++
+		}
+		if entry.Duration != nil {
+			switch rules.Classify(entry, tags) {
+			case FocusMeetings:
+				w.meetingMinutes += *entry.Duration
+			case FocusBreaks:
+				w.breakMinutes += *entry.Duration
+			}
+		}
+		if hour := t.Hour(); hour >= lateEveningStart || hour < lateEveningEnd {
+			w.lateEntries++
+		}
+	}
+
+	week := weeks[WellbeingBaselineWeeks]
+	var baseline weekFigures
+	baselineWeeks := 0
+	for _, w := range weeks[:WellbeingBaselineWeeks] {
+		if w.entries == 0 {
+			continue
+		}
+		baselineWeeks++
+		baseline.statusSum += w.statusSum
+		baseline.statusCount += w.statusCount
+		baseline.meetingMinutes += w.meetingMinutes
+		baseline.breakMinutes += w.breakMinutes
+		baseline.lateEntries += w.lateEntries
+	}
+	perWeek := func(total int) float64 {
+		if baselineWeeks == 0 {
+			return 0
+		}
+		return float64(total) / float64(baselineWeeks)
+	}
+
+	check := WellbeingCheck{
+		WeekStart: weekStart.Format("2006-01-02"),
+		WeekEnd:   weekStart.AddDate(0, 0, 6).Format("2006-01-02"),
+		Signals:   []WellbeingSignal{moodSignal(week, baseline)},
+	}
+
+	meetings := WellbeingSignal{Name: SignalMeetingTime, Week: float64(week.meetingMinutes), Baseline: perWeek(baseline.meetingMinutes)}
+	meetings.Triggered = baselineWeeks > 0 && meetings.Week >= meetings.Baseline*meetingRiseFactor && meetings.Week-meetings.Baseline >= minChangeMinutes
+	meetings.Message = fmt.Sprintf("%.0f minutes in meetings, against a usual %.0f", meetings.Week, meetings.Baseline)
+
+	breaks := WellbeingSignal{Name: SignalBreakTime, Week: float64(week.breakMinutes), Baseline: perWeek(baseline.breakMinutes)}
+	breaks.Triggered = breaks.Baseline >= minBaselineMinutes && breaks.Week <= breaks.Baseline*breakShrinkFactor
+	breaks.Message = fmt.Sprintf("%.0f minutes of breaks, against a usual %.0f", breaks.Week, breaks.Baseline)
+
+	late := WellbeingSignal{Name: SignalLateEntries, Week: float64(week.lateEntries), Baseline: perWeek(baseline.lateEntries)}
+	late.Triggered = late.Week >= lateEntriesMin && late.Week > late.Baseline
+	late.Message = fmt.Sprintf("%.0f entries logged late in the evening, against a usual %.1f", late.Week, late.Baseline)
+
+	check.Signals = append(check.Signals, meetings, breaks, late)
+	for _, signal := range check.Signals {
+		if signal.Triggered {
+			check.Score++
+		}
+	}
+	switch {
+	case check.Score >= 3:
+		check.Risk = RiskHigh
+	case check.Score == 2:
+		check.Risk = RiskModerate
+	default:
+		check.Risk = RiskLow
+	}
+	return check
+}
+
+// moodSignal compares the week's average status with the baseline's
+func moodSignal(week, baseline weekFigures) WellbeingSignal {
+	signal := WellbeingSignal{Name: SignalMoodDecline, Message: "not enough statuses logged to compare"}
+	if week.statusCount == 0 || baseline.statusCount == 0 {
+		return signal
+	}
+	signal.Week = float64(week.statusSum) / float64(week.statusCount)
+	signal.Baseline = float64(baseline.statusSum) / float64(baseline.statusCount)
+	signal.Triggered = signal.Baseline-signal.Week >= moodDrop
+	signal.Message = fmt.Sprintf("average status %.1f, against a usual %.1f", signal.Week, signal.Baseline)
+	return signal
+}
+
+// String describes the risk and the signals that fired, e.g. "Burnout
+// risk: moderate (mood_decline: average status 5.2, against a usual 6.8; ...)."
+func (c WellbeingCheck) String() string {
+	var fired []string
+	for _, signal := range c.Signals {
+		if signal.Triggered {
+			fired = append(fired, signal.Name+": "+signal.Message)
+		}
+	}
+	if len(fired) == 0 {
+		return "Burnout risk: " + c.Risk + "."
+	}
+	return fmt.Sprintf("Burnout risk: %s (%s).", c.Risk, strings.Join(fired, "; "))
+}
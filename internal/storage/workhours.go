@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkHoursConfig sets the work time, in minutes, a day and a week are
+// expected to have and may not go over. Zero leaves a limit unchecked.
+type WorkHoursConfig struct {
+	DailyTarget  int `json:"daily_target_minutes,omitempty" yaml:"daily_target_minutes,omitempty"`
+	WeeklyTarget int `json:"weekly_target_minutes,omitempty" yaml:"weekly_target_minutes,omitempty"`
+	DailyMax     int `json:"daily_max_minutes,omitempty" yaml:"daily_max_minutes,omitempty"`
+	WeeklyMax    int `json:"weekly_max_minutes,omitempty" yaml:"weekly_max_minutes,omitempty"`
+}
+
+// DefaultWorkHours expects 8 hour days and 40 hour weeks, and holds them to
+// the common legal limits of 10 hours a day and 48 a week
+var DefaultWorkHours = WorkHoursConfig{
+	DailyTarget:  8 * 60,
+	WeeklyTarget: 40 * 60,
+	DailyMax:     10 * 60,
+	WeeklyMax:    48 * 60,
+}
+
+// WorkHoursPeriod is the work time of a day, or of a week by its Monday.
+// Under is only set for weekdays and weeks that have ended.
+type WorkHoursPeriod struct {
+	Start   string `json:"start" yaml:"start"`
+	Minutes int    `json:"minutes" yaml:"minutes"`
+	Over    bool   `json:"over_max,omitempty" yaml:"over_max,omitempty"`
+	Under   bool   `json:"under_target,omitempty" yaml:"under_target,omitempty"`
+}
+
+// WorkHoursReport checks the work time of a range of days against targets
+// and limits
+type WorkHoursReport struct {
+	Period       string            `json:"period" yaml:"period"`
+	Limits       WorkHoursConfig   `json:"limits" yaml:"limits"`
+	TotalMinutes int               `json:"total_minutes" yaml:"total_minutes"`
+	Days         []WorkHoursPeriod `json:"days" yaml:"days"`
+	Weeks        []WorkHoursPeriod `json:"weeks" yaml:"weeks"`
+	DaysOver     int               `json:"days_over_max" yaml:"days_over_max"`
+	WeeksOver    int               `json:"weeks_over_max" yaml:"weeks_over_max"`
+}
+
+// IsWork reports whether an entry's duration counts as work time: it is
+// not a metric, planned, or classified as a break by the focus rules
+func IsWork(entry DailyLogEntry, rules FocusRules, tags TagAliases) bool {
+	if entry.Duration == nil || *entry.Duration <= 0 || entry.Type == EntryTypeMetric || IsPlanned(entry) {
+		return false
+	}
+	return rules.Classify(entry, tags) != FocusBreaks
+}
+
+// CheckWorkHours sums the work time of each day from start to end, and of
+// each week they touch, and flags those over the limits. Days and weeks
+// still in progress at now are not flagged under their target.
+func CheckWorkHours(entries []DailyLogEntry, start, end, now time.Time, config WorkHoursConfig, rules FocusRules, tags TagAliases) WorkHoursReport {
+	report := WorkHoursReport{
+		Period: fmt.Sprintf("%s to %s", start.Format("2006-01-02"), end.Format("2006-01-02")),
+		Limits: config,
+		Days:   []WorkHoursPeriod{},
+		Weeks:  []WorkHoursPeriod{},
+	}
+	dayMinutes := make(map[string]int)
+	for _, entry := range entries {
+		if IsWork(entry, rules, tags) {
+			dayMinutes[entry.Timestamp.In(start.Location()).Format("2006-01-02")] += *entry.Duration
+		}
+	}
+	today := now.In(start.Location()).Format("2006-01-02")
+
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		day := WorkHoursPeriod{Start: key, Minutes: dayMinutes[key]}
+		weekday := d.Weekday() != time.Saturday && d.Weekday() != time.Sunday
+		day.Over = config.DailyMax > 0 && day.Minutes > config.DailyMax
+		day.Under = config.DailyTarget > 0 && weekday && key < today && day.Minutes < config.DailyTarget
+		if day.Over {
+			report.DaysOver++
+		}
+		report.TotalMinutes += day.Minutes
+		report.Days = append(report.Days, day)
+
+		// Weeks run Monday to Sunday, counted in full even past the range
+		if d.Equal(start) || d.Weekday() == time.Monday {
+			monday := d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7))
+			week := WorkHoursPeriod{Start: monday.Format("2006-01-02")}
+			for w := monday; w.Before(monday.AddDate(0, 0, 7)); w = w.AddDate(0, 0, 1) {
+				week.Minutes += dayMinutes[w.Format("2006-01-02")]
+			}
+			sunday := monday.AddDate(0, 0, 6).Format("2006-01-02")
+			week.Over = config.WeeklyMax > 0 && week.Minutes > config.WeeklyMax
+			week.Under = config.WeeklyTarget > 0 && sunday < today && week.Minutes < config.WeeklyTarget
+			if week.Over {
+				report.WeeksOver++
+			}
+			report.Weeks = append(report.Weeks, week)
+		}
+	}
+	return report
+}
+
+// String describes the work time against the limits, e.g. "Work hours:
+// 46.5h against a 40h target, 2 days over 10h (2025-09-30 11h, 2025-10-01 10.5h)."
+func (r WorkHoursReport) String() string {
+	text := fmt.Sprintf("Work hours: %sh", formatHours(r.TotalMinutes))
+	if len(r.Weeks) == 1 && r.Limits.WeeklyTarget > 0 {
+		text += fmt.Sprintf(" against a %sh target", formatHours(r.Limits.WeeklyTarget))
+	}
+	if r.DaysOver > 0 {
+		var over []string
+		for _, day := range r.Days {
+			if day.Over {
+				over = append(over, fmt.Sprintf("%s %sh", day.Start, formatHours(day.Minutes)))
+			}
+		}
+		text += fmt.Sprintf(", %s over %sh (%s)", countOf(r.DaysOver, "day"), formatHours(r.Limits.DailyMax), strings.Join(over, ", "))
+	}
+	if r.WeeksOver > 0 {
+		text += fmt.Sprintf(", %s over %sh", countOf(r.WeeksOver, "week"), formatHours(r.Limits.WeeklyMax))
+	}
+	return text + "."
+}
+
+// countOf writes a count of things, e.g. "1 day" or "2 days"
+func countOf(n int, thing string) string {
+	if n == 1 {
+		return "1 " + thing
+	}
+	return fmt.Sprintf("%d %ss", n, thing)
+}
@@ -0,0 +1,205 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"dailylog/internal/storage"
+)
+
+// Source is the source recorded in the metadata of entries captured by the bot
+const Source = "telegram"
+
+// pollTimeout is how long each long poll waits for messages
+const pollTimeout = 50 * time.Second
+
+// retryDelay is how long to wait after failing to fetch messages
+const retryDelay = 5 * time.Second
+
+// Attacher stores a file attached to an entry logged at t, returning its path
+type Attacher func(t time.Time, name string, data []byte) (string, error)
+
+// Bot logs a note for each message sent to it from an allowed chat: the
+// first line is the title and the rest the description, and #hashtags
+// become tags. A photo is attached to its note, with the caption as text.
+type Bot struct {
+	client *Client
+	store  storage.DailyLogStorage
+	chats  map[int64]bool
+
+	// Attach, if set, stores photos; without it, photos are logged without them
+	Attach Attacher
+	// OnLogged, if set, is told about each entry logged
+	OnLogged func(entry *storage.DailyLogEntry)
+	// OnError, if set, is told about failures the bot carries on after
+	OnError func(err error)
+}
+
+// NewBot logs messages from the chats with the IDs given to store
+func NewBot(client *Client, store storage.DailyLogStorage, chats []int64) *Bot {
+	b := &Bot{client: client, store: store, chats: make(map[int64]bool, len(chats))}
+	for _, chat := range chats {
+		b.chats[chat] = true
+	}
+	return b
+}
+
+// Run polls for messages and logs them until ctx is done. Each message is
+// confirmed once handled, so messages sent while the bot isn't running are
+// logged when it starts again, for as long as Telegram keeps them (a day).
+func (b *Bot) Run(ctx context.Context) error {
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, pollTimeout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			b.report(err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			if update.Message != nil {
+				b.handle(ctx, update.Message)
+			}
+		}
+	}
+}
+
+// handle logs a message and replies with what was logged
+func (b *Bot) handle(ctx context.Context, msg *Message) {
+	var reply string
+	switch {
+	case !b.chats[msg.Chat.ID]:
+		reply = fmt.Sprintf("This chat isn't allowed to log entries. Add its ID, %d, to telegram.chats.", msg.Chat.ID)
+	case strings.HasPrefix(msg.Text, "/"):
+		// /start and other commands
+		reply = "Send a message to log it as a note: the first line is the title, and #hashtags become tags. Photos are attached, with the caption as text."
+	default:
+		entry, warning, err := b.log(ctx, msg)
+		if err != nil {
+			reply = "Not logged: " + err.Error()
+			break
+		}
+		reply = "✓ Logged " + entry.Title
+		if warning != "" {
+			reply += "\n⚠ " + warning
+		}
+		if b.OnLogged != nil {
+			b.OnLogged(entry)
+		}
+	}
+	if err := b.client.SendMessage(ctx, msg.Chat.ID, msg.MessageID, reply); err != nil {
+		b.report(err)
+	}
+}
+
+// log logs msg as a note, returning a warning if its photo couldn't be attached
+func (b *Bot) log(ctx context.Context, msg *Message) (*storage.DailyLogEntry, string, error) {
+	req, err := NoteRequest(msg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var warning string
+	if len(msg.Photo) > 0 {
+		photo := msg.Photo[len(msg.Photo)-1]
+		if attachment, err := b.attach(ctx, req.Date, photo); err != nil {
+			warning = "photo not attached: " + err.Error()
+			b.report(err)
+		} else {
+			req.Metadata[storage.MetadataAttachment] = attachment
+		}
+	}
+
+	entry, err := b.store.CreateEntry(req)
+	if err != nil {
+		return nil, "", err
+	}
+	return entry, warning, nil
+}
+
+// attach downloads photo and stores it
+func (b *Bot) attach(ctx context.Context, t time.Time, photo PhotoSize) (string, error) {
+	if b.Attach == nil {
+		return "", errors.New("attachments are not supported by this storage")
+	}
+	data, err := b.client.Download(ctx, photo.FileID)
+	if err != nil {
+		return "", err
+	}
+	return b.Attach(t, "telegram-"+photo.FileUniqueID+".jpg", data)
+}
+
+// NoteRequest turns a message into a note: the first line of its text, or
+// its caption, is the title, the rest the description, and #hashtags tags.
+// A photo without a caption is titled "Photo".
+func NoteRequest(msg *Message) (storage.CreateLogEntryRequest, error) {
+	text := msg.Text
+	if text == "" {
+		text = msg.Caption
+	}
+
+	var tags []string
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		var words []string
+		for _, word := range strings.Fields(line) {
+			if tag := strings.TrimRightFunc(strings.TrimPrefix(word, "#"), unicode.IsPunct); strings.HasPrefix(word, "#") && tag != "" {
+				tags = append(tags, tag)
+				continue
+			}
+			words = append(words, word)
+		}
+		lines = append(lines, strings.Join(words, " "))
+	}
+
+	title, description, _ := strings.Cut(strings.TrimSpace(strings.Join(lines, "\n")), "\n")
+	if title == "" && len(msg.Photo) > 0 {
+		title = "Photo"
+	}
+	if title == "" {
+		return storage.CreateLogEntryRequest{}, storage.ValidationError{Field: "title", Message: "the message needs some text besides hashtags"}
+	}
+
+	req := storage.CreateLogEntryRequest{
+		Date:        time.Unix(msg.Date, 0),
+		Type:        "note",
+		Title:       title,
+		Description: strings.TrimSpace(description),
+		Tags:        tags,
+		Metadata: map[string]string{
+			storage.MetadataSource:     Source,
+			storage.MetadataExternalID: strconv.FormatInt(msg.Chat.ID, 10) + "/" + strconv.FormatInt(msg.MessageID, 10),
+		},
+	}
+	if msg.Date == 0 {
+		req.Date = time.Now()
+	}
+	// Whoever sent the message wrote the entry, not the bot's owner
+	if msg.From != nil {
+		req.Author = msg.From.Username
+		if req.Author == "" {
+			req.Author = msg.From.FirstName
+		}
+	}
+	return req, nil
+}
+
+// report passes err to OnError, if set
+func (b *Bot) report(err error) {
+	if b.OnError != nil {
+		b.OnError(err)
+	}
+}
@@ -0,0 +1,175 @@
+// Package telegram captures entries from messages sent to a Telegram bot,
+// received by long polling the Bot API.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DefaultAPIURL is the Telegram Bot API, used unless another is configured
+const DefaultAPIURL = "https://api.telegram.org"
+
+// maxFileSize is the largest file the Bot API lets bots download
+const maxFileSize = 20 << 20
+
+// Client calls the Bot API as the bot with a token
+type Client struct {
+	client *http.Client
+	token  string
+	apiURL string
+}
+
+// NewClient creates a client for the bot with token, at apiURL or, if
+// empty, DefaultAPIURL
+func NewClient(token, apiURL string) (*Client, error) {
+	if token == "" {
+		return nil, fmt.Errorf("Telegram bot token is required")
+	}
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{client: &http.Client{}, token: token, apiURL: apiURL}, nil
+}
+
+// Update is something that happened to the bot; only messages are asked for
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+// Message is a message sent to the bot
+type Message struct {
+	MessageID int64       `json:"message_id"`
+	Date      int64       `json:"date"` // Unix time
+	Chat      Chat        `json:"chat"`
+	From      *User       `json:"from"`
+	Text      string      `json:"text"`
+	Caption   string      `json:"caption"`
+	Photo     []PhotoSize `json:"photo"` // Smallest first
+}
+
+// Chat is where a message was sent
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// User is who sent a message
+type User struct {
+	ID        int64  `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+}
+
+// PhotoSize is one of the sizes a photo is available in
+type PhotoSize struct {
+	FileID       string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	FileSize     int    `json:"file_size"`
+}
+
+// GetUpdates waits up to timeout for messages after offset, the update ID
+// after the last one handled. Asking for later updates confirms earlier ones.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatInt(offset, 10))
+	params.Set("timeout", strconv.Itoa(int(timeout.Seconds())))
+	params.Set("allowed_updates", `["message"]`)
+
+	// Give the server time to answer after the poll times out
+	ctx, cancel := context.WithTimeout(ctx, timeout+30*time.Second)
+	defer cancel()
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", params, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// Download returns the contents of the file with fileID
+func (c *Client) Download(ctx context.Context, fileID string) ([]byte, error) {
+	var file struct {
+		FilePath string `json:"file_path"`
+		FileSize int    `json:"file_size"`
+	}
+	if err := c.call(ctx, "getFile", url.Values{"file_id": {fileID}}, &file); err != nil {
+		return nil, err
+	}
+	if file.FilePath == "" || file.FileSize > maxFileSize {
+		return nil, fmt.Errorf("file is too big to download (at most %d MB)", maxFileSize>>20)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/file/bot"+c.token+"/"+file.FilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", redact(err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+}
+
+// SendMessage replies to a message in chat
+func (c *Client) SendMessage(ctx context.Context, chatID, replyTo int64, text string) error {
+	params := url.Values{}
+	params.Set("chat_id", strconv.FormatInt(chatID, 10))
+	params.Set("text", text)
+	if replyTo != 0 {
+		params.Set("reply_parameters", fmt.Sprintf(`{"message_id":%d,"allow_sending_without_reply":true}`, replyTo))
+	}
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+// call calls a Bot API method, decoding its result into result if set
+func (c *Client) call(ctx context.Context, method string, params url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+"/bot"+c.token+"/"+method, bytes.NewBufferString(params.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("telegram %s failed: %w", method, redact(err))
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return fmt.Errorf("invalid telegram %s response (%s): %w", method, resp.Status, err)
+	}
+	if !reply.OK {
+		return fmt.Errorf("telegram %s failed: %s", method, reply.Description)
+	}
+	if result != nil {
+		if err := json.Unmarshal(reply.Result, result); err != nil {
+			return fmt.Errorf("invalid telegram %s response: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// redact keeps the bot token, which is part of every URL, out of errors
+func redact(err error) error {
+	if uerr, ok := err.(*url.Error); ok {
+		return fmt.Errorf("%s: %w", uerr.Op, uerr.Err)
+	}
+	return err
+}
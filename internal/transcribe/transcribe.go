@@ -0,0 +1,220 @@
+// Package transcribe turns audio recordings such as voice memos into text,
+// with the Whisper API or a local whisper.cpp.
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Providers
+const (
+	ProviderOpenAI     = "openai"
+	ProviderWhisperCpp = "whisper-cpp"
+)
+
+// Defaults for the providers
+const (
+	DefaultAPIURL       = "https://api.openai.com/v1/audio/transcriptions"
+	DefaultAPIModel     = "whisper-1"
+	DefaultWhisperCpp   = "whisper-cli"
+	DefaultFFmpegBinary = "ffmpeg"
+)
+
+// Transcriber turns an audio file into text
+type Transcriber interface {
+	// Name identifies the transcriber in entry metadata
+	Name() string
+	Transcribe(path string) (string, error)
+}
+
+// Options configures a transcriber; empty fields take the provider's defaults
+type Options struct {
+	Provider string // openai (default) or whisper-cpp
+	APIKey   string // openai
+	URL      string // openai: an OpenAI compatible transcription endpoint
+	Model    string // openai: model name; whisper-cpp: path to the model file
+	Binary   string // whisper-cpp: the whisper.cpp command
+	Language string // e.g. "en"; empty to detect
+}
+
+// New creates the transcriber for opts.Provider
+func New(opts Options) (Transcriber, error) {
+	switch opts.Provider {
+	case "", ProviderOpenAI:
+		return NewWhisperAPI(opts)
+	case ProviderWhisperCpp:
+		return NewWhisperCpp(opts)
+	}
+	return nil, fmt.Errorf("unknown transcription provider %q (use %s or %s)", opts.Provider, ProviderOpenAI, ProviderWhisperCpp)
+}
+
+// WhisperAPI transcribes with the OpenAI Whisper API, or another service
+// with the same interface
+type WhisperAPI struct {
+	client   *http.Client
+	ctx      context.Context
+	apiKey   string
+	url      string
+	model    string
+	language string
+}
+
+// NewWhisperAPI creates a Whisper API transcriber
+func NewWhisperAPI(opts Options) (*WhisperAPI, error) {
+	if opts.APIKey == "" {
+		return nil, fmt.Errorf("an API key is required")
+	}
+	w := &WhisperAPI{
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		ctx:      context.Background(),
+		apiKey:   opts.APIKey,
+		url:      opts.URL,
+		model:    opts.Model,
+		language: opts.Language,
+	}
+	if w.url == "" {
+		w.url = DefaultAPIURL
+	}
+	if w.model == "" {
+		w.model = DefaultAPIModel
+	}
+	return w, nil
+}
+
+// Name returns the model used
+func (w *WhisperAPI) Name() string {
+	return w.model
+}
+
+// Transcribe uploads the audio file and returns its transcript
+func (w *WhisperAPI) Transcribe(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return "", fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	_ = form.WriteField("model", w.model)
+	_ = form.WriteField("response_format", "json")
+	if w.language != "" {
+		_ = form.WriteField("language", w.language)
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(w.ctx, http.MethodPost, w.url, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+w.apiKey)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("transcription request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Text  string `json:"text"`
+		Error *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to decode transcription: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != nil && result.Error.Message != "" {
+			return "", fmt.Errorf("transcription failed: %s (status %d)", result.Error.Message, resp.StatusCode)
+		}
+		return "", fmt.Errorf("transcription failed with status %d", resp.StatusCode)
+	}
+	return strings.TrimSpace(result.Text), nil
+}
+
+// WhisperCpp transcribes locally with whisper.cpp. Audio other than WAV is
+// converted to the 16 kHz mono WAV it reads with ffmpeg first.
+type WhisperCpp struct {
+	binary   string
+	model    string
+	language string
+	ffmpeg   string
+}
+
+// NewWhisperCpp creates a whisper.cpp transcriber
+func NewWhisperCpp(opts Options) (*WhisperCpp, error) {
+	if opts.Model == "" {
+		return nil, fmt.Errorf("the path to a whisper.cpp model is required")
+	}
+	w := &WhisperCpp{binary: opts.Binary, model: opts.Model, language: opts.Language, ffmpeg: DefaultFFmpegBinary}
+	if w.binary == "" {
+		w.binary = DefaultWhisperCpp
+	}
+	return w, nil
+}
+
+// Name returns the model file used
+func (w *WhisperCpp) Name() string {
+	return "whisper.cpp/" + filepath.Base(w.model)
+}
+
+// Transcribe runs whisper.cpp on the audio file and returns its transcript
+func (w *WhisperCpp) Transcribe(path string) (string, error) {
+	input := path
+	if !strings.EqualFold(filepath.Ext(path), ".wav") {
+		dir, err := os.MkdirTemp("", "dailyctl-voice-")
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(dir)
+
+		input = filepath.Join(dir, "audio.wav")
+		convert := exec.Command(w.ffmpeg, "-nostdin", "-loglevel", "error", "-i", path,
+			"-ar", "16000", "-ac", "1", "-c:a", "pcm_s16le", input)
+		if output, err := convert.CombinedOutput(); err != nil {
+			return "", fmt.Errorf("failed to convert %s to WAV with %s: %v %s", path, w.ffmpeg, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	args := []string{"-m", w.model, "-f", input, "--no-timestamps", "--no-prints"}
+	if w.language != "" {
+		args = append(args, "-l", w.language)
+	}
+	var stdout, stderr bytes.Buffer
+	run := exec.Command(w.binary, args...)
+	run.Stdout, run.Stderr = &stdout, &stderr
+	if err := run.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %v %s", w.binary, err, strings.TrimSpace(stderr.String()))
+	}
+
+	// whisper.cpp prints one segment per line
+	var lines []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " "), nil
+}
@@ -0,0 +1,238 @@
+// Package weather looks up the weather of past days, for recording alongside
+// the log of a day.
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Open-Meteo endpoints, used unless others are configured
+const (
+	DefaultForecastURL = "https://api.open-meteo.com/v1/forecast"
+	DefaultArchiveURL  = "https://archive-api.open-meteo.com/v1/archive"
+	DefaultGeocodeURL  = "https://geocoding-api.open-meteo.com/v1/search"
+)
+
+// forecastDays is how far back the forecast endpoint serves past days; older
+// days come from the archive
+const forecastDays = 60
+
+// ErrUnknownLocation is returned for a location that cannot be geocoded, such
+// as "home" or "office"
+var ErrUnknownLocation = errors.New("unknown location")
+
+// Source looks up the weather of a day at a location
+type Source interface {
+	Lookup(location string, date time.Time) (storage.Weather, error)
+}
+
+// Options configures an Open-Meteo compatible service; empty URLs use Open-Meteo's
+type Options struct {
+	ForecastURL string
+	ArchiveURL  string
+	GeocodeURL  string
+}
+
+// Client looks up daily weather from an Open-Meteo compatible service.
+// Locations are place names, geocoded once per client, or "latitude,longitude".
+type Client struct {
+	client  *http.Client
+	ctx     context.Context
+	options Options
+
+	mu     sync.Mutex
+	places map[string]*place // nil for locations that were not found
+}
+
+type place struct {
+	latitude, longitude float64
+}
+
+// NewClient creates a weather client
+func NewClient(opts Options) *Client {
+	if opts.ForecastURL == "" {
+		opts.ForecastURL = DefaultForecastURL
+	}
+	if opts.ArchiveURL == "" {
+		opts.ArchiveURL = DefaultArchiveURL
+	}
+	if opts.GeocodeURL == "" {
+		opts.GeocodeURL = DefaultGeocodeURL
+	}
+	return &Client{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		ctx:     context.Background(),
+		options: opts,
+		places:  make(map[string]*place),
+	}
+}
+
+// Lookup returns the weather of date at location
+func (c *Client) Lookup(location string, date time.Time) (storage.Weather, error) {
+	at, err := c.geocode(location)
+	if err != nil {
+		return storage.Weather{}, err
+	}
+
+	endpoint := c.options.ForecastURL
+	if time.Since(date) > forecastDays*24*time.Hour {
+		endpoint = c.options.ArchiveURL
+	}
+	day := date.Format("2006-01-02")
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(at.latitude, 'f', 4, 64))
+	params.Set("longitude", strconv.FormatFloat(at.longitude, 'f', 4, 64))
+	params.Set("daily", "weather_code,temperature_2m_max,temperature_2m_min,precipitation_sum")
+	params.Set("timezone", "auto")
+	params.Set("start_date", day)
+	params.Set("end_date", day)
+
+	var forecast struct {
+		Daily struct {
+			Time          []string   `json:"time"`
+			WeatherCode   []*int     `json:"weather_code"`
+			TempMax       []*float64 `json:"temperature_2m_max"`
+			TempMin       []*float64 `json:"temperature_2m_min"`
+			Precipitation []*float64 `json:"precipitation_sum"`
+		} `json:"daily"`
+	}
+	if err := c.get(endpoint, params, &forecast); err != nil {
+		return storage.Weather{}, fmt.Errorf("failed to get the weather for %s: %v", day, err)
+	}
+	daily := forecast.Daily
+	if len(daily.Time) == 0 || len(daily.WeatherCode) == 0 || daily.WeatherCode[0] == nil {
+		return storage.Weather{}, fmt.Errorf("no weather for %s at %s", day, location)
+	}
+
+	w := storage.Weather{
+		Location:  location,
+		Code:      *daily.WeatherCode[0],
+		Condition: Condition(*daily.WeatherCode[0]),
+		Source:    hostOf(endpoint),
+	}
+	if len(daily.TempMax) > 0 && daily.TempMax[0] != nil {
+		w.TempMax = *daily.TempMax[0]
+	}
+	if len(daily.TempMin) > 0 && daily.TempMin[0] != nil {
+		w.TempMin = *daily.TempMin[0]
+	}
+	if len(daily.Precipitation) > 0 && daily.Precipitation[0] != nil {
+		w.Precipitation = *daily.Precipitation[0]
+	}
+	return w, nil
+}
+
+var coordinatesPattern = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)\s*,\s*(-?\d+(?:\.\d+)?)\s*$`)
+
+// geocode finds a location's coordinates
+func (c *Client) geocode(location string) (place, error) {
+	if match := coordinatesPattern.FindStringSubmatch(location); match != nil {
+		latitude, _ := strconv.ParseFloat(match[1], 64)
+		longitude, _ := strconv.ParseFloat(match[2], 64)
+		return place{latitude, longitude}, nil
+	}
+
+	key := strings.ToLower(strings.TrimSpace(location))
+	if key == "" {
+		return place{}, fmt.Errorf("%w: no location given", ErrUnknownLocation)
+	}
+	c.mu.Lock()
+	cached, ok := c.places[key]
+	c.mu.Unlock()
+	if ok && cached == nil {
+		return place{}, fmt.Errorf("%w: %s", ErrUnknownLocation, location)
+	}
+	if ok {
+		return *cached, nil
+	}
+
+	params := url.Values{}
+	params.Set("name", location)
+	params.Set("count", "1")
+	var result struct {
+		Results []struct {
+			Latitude  float64 `json:"latitude"`
+			Longitude float64 `json:"longitude"`
+		} `json:"results"`
+	}
+	if err := c.get(c.options.GeocodeURL, params, &result); err != nil {
+		return place{}, fmt.Errorf("failed to look up %s: %v", location, err)
+	}
+
+	var found *place
+	if len(result.Results) > 0 {
+		found = &place{result.Results[0].Latitude, result.Results[0].Longitude}
+	}
+	c.mu.Lock()
+	c.places[key] = found
+	c.mu.Unlock()
+	if found == nil {
+		return place{}, fmt.Errorf("%w: %s", ErrUnknownLocation, location)
+	}
+	return *found, nil
+}
+
+func (c *Client) get(endpoint string, params url.Values, out any) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Reason string `json:"reason"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Reason != "" {
+			return fmt.Errorf("%s (status %d)", apiErr.Reason, resp.StatusCode)
+		}
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func hostOf(endpoint string) string {
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return endpoint
+}
+
+// Condition names a WMO weather code
+func Condition(code int) string {
+	switch {
+	case code == 0:
+		return "clear"
+	case code <= 2:
+		return "partly cloudy"
+	case code == 3:
+		return "cloudy"
+	case code == 45 || code == 48:
+		return "fog"
+	case code >= 51 && code <= 57:
+		return "drizzle"
+	case code >= 61 && code <= 67, code >= 80 && code <= 82:
+		return "rain"
+	case code >= 71 && code <= 77, code == 85 || code == 86:
+		return "snow"
+	case code >= 95:
+		return "thunderstorm"
+	}
+	return "unknown"
+}
@@ -0,0 +1,232 @@
+package web
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailylog/internal/datetime"
+	"dailylog/internal/storage"
+)
+
+// CaptureSource is the source recorded in the metadata of entries captured
+// through /capture, unless the request names another
+const CaptureSource = "capture"
+
+// captureMaxBody bounds the size of a capture request
+const captureMaxBody = 64 << 10
+
+// CaptureHandler logs an entry from a few query, form, or JSON parameters,
+// for Apple Shortcuts, Android Tasker, bookmarklets, and other simple clients:
+//
+//	text         a quick entry, as 'dailyctl q' takes it
+//	title        the title, instead of or after text
+//	description  the description
+//	type         activity (the default), status, note, summary, meeting, lesson
+//	tags         comma-separated tags, added to any #tags in text
+//	location, status, duration, datetime, visibility
+//	source       recorded in the entry's source metadata, default "capture"
+//	author       who wrote the entry, default the server's author
+//	x-success    a URL to redirect to once logged, with id and title added
+//	x-error      a URL to redirect to on failure, with errorMessage added
+//
+// Requests must carry the token, as ?token= or a bearer token. The reply is
+// JSON, or plain text with format=text, for showing in a notification.
+type CaptureHandler struct {
+	storage storage.DailyLogStorage
+	token   string
+}
+
+// NewCaptureHandler logs entries to store from requests carrying token
+func NewCaptureHandler(store storage.DailyLogStorage, token string) (*CaptureHandler, error) {
+	if token == "" {
+		return nil, fmt.Errorf("capture token is required")
+	}
+	return &CaptureHandler{storage: store, token: token}, nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *CaptureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !hasToken(r, h.token) {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, captureMaxBody)
+	if err := parseCaptureForm(r); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	plain := r.Form.Get("format") == "text"
+	entry, err := h.log(r.Form)
+	if err != nil {
+		if target := r.Form.Get("x-error"); target != "" {
+			redirect(w, r, target, url.Values{"errorMessage": {err.Error()}})
+			return
+		}
+		if plain {
+			http.Error(w, "Not logged: "+err.Error(), errorStatus(err))
+			return
+		}
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+
+	if target := r.Form.Get("x-success"); target != "" {
+		redirect(w, r, target, url.Values{"id": {entry.ID}, "title": {entry.Title}})
+		return
+	}
+	if plain {
+		fmt.Fprintf(w, "Logged %s: %s\n", entry.Type, entry.Title)
+		return
+	}
+	writeJSON(w, map[string]string{"status": "logged", "id": entry.ID, "type": entry.Type, "title": entry.Title})
+}
+
+// log logs the entry described by form
+func (h *CaptureHandler) log(form url.Values) (*storage.DailyLogEntry, error) {
+	req, err := captureRequest(form, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return h.storage.CreateEntry(req)
+}
+
+// parseCaptureForm fills r.Form from the query and a form or, as Shortcuts
+// sends by default, JSON object body
+func parseCaptureForm(r *http.Request) error {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		if err := r.ParseMultipartForm(captureMaxBody); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			return fmt.Errorf("invalid form: %w", err)
+		}
+		return nil
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	r.Form = r.URL.Query()
+	for key, value := range body {
+		switch value := value.(type) {
+		case string:
+			r.Form.Set(key, value)
+		case float64, bool:
+			r.Form.Set(key, fmt.Sprint(value))
+		case []any:
+			// e.g. tags as a list
+			var items []string
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+			r.Form.Set(key, strings.Join(items, ","))
+		}
+	}
+	return nil
+}
+
+// captureRequest builds a create request from capture parameters
+func captureRequest(form url.Values, now time.Time) (storage.CreateLogEntryRequest, error) {
+	var req storage.CreateLogEntryRequest
+	var err error
+	if text := strings.TrimSpace(form.Get("text")); text != "" {
+		if req, err = storage.ParseQuickEntry(text); err != nil {
+			return req, err
+		}
+	}
+	if title := strings.TrimSpace(form.Get("title")); title != "" {
+		req.Title = strings.TrimSpace(req.Title + " " + title)
+	}
+	if req.Title == "" {
+		return req, storage.ValidationError{Field: "title", Message: "text or title is required"}
+	}
+
+	req.Type = "activity"
+	if value := strings.ToLower(strings.TrimSpace(form.Get("type"))); value != "" {
+		req.Type = ""
+		for _, t := range entryTypes {
+			if value == t {
+				req.Type = t
+			}
+		}
+		if req.Type == "" {
+			return req, storage.ValidationError{Field: "type", Message: fmt.Sprintf("must be one of %s (got %q)", strings.Join(entryTypes, ", "), value)}
+		}
+	}
+
+	req.Description = strings.TrimSpace(form.Get("description"))
+	for _, tag := range strings.Split(form.Get("tags"), ",") {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+			req.Tags = append(req.Tags, tag)
+		}
+	}
+	if location := strings.TrimSpace(form.Get("location")); location != "" {
+		req.Location = location
+	}
+	if value := form.Get("status"); value != "" {
+		status, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || status < 1 || status > 10 {
+			return req, storage.ValidationError{Field: "status", Message: fmt.Sprintf("must be between 1 and 10 (got %q)", value)}
+		}
+		req.Status = &status
+	}
+	if value := form.Get("duration"); value != "" {
+		duration, err := storage.ParseDurationMinutes(strings.TrimSpace(value))
+		if err != nil {
+			return req, storage.ValidationError{Field: "duration", Message: fmt.Sprintf("must be minutes or a duration like 45m or 1h30m (got %q)", value)}
+		}
+		req.Duration = &duration
+	}
+	req.Visibility = form.Get("visibility")
+	if err := storage.ValidateVisibility(req.Visibility); err != nil {
+		return req, err
+	}
+
+	req.Date = now
+	if value := form.Get("datetime"); value != "" {
+		if req.Date, err = datetime.Parse(value, now); err != nil {
+			return req, storage.ValidationError{Field: "datetime", Message: err.Error()}
+		}
+	}
+
+	source := strings.TrimSpace(form.Get("source"))
+	if source == "" {
+		source = CaptureSource
+	}
+	req.Metadata = map[string]string{storage.MetadataSource: source}
+	req.Author = strings.TrimSpace(form.Get("author"))
+	return req, nil
+}
+
+// redirect sends the client to target with params added to its query, as
+// x-callback-url clients expect
+func redirect(w http.ResponseWriter, r *http.Request, target string, params url.Values) {
+	u, err := url.Parse(target)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid callback URL: %s", target))
+		return
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	http.Redirect(w, r, u.String(), http.StatusSeeOther)
+}
+
+// hasToken reports whether r carries token, as ?token= or a bearer token
+func hasToken(r *http.Request, token string) bool {
+	value := r.URL.Query().Get("token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		value = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(value), []byte(token)) == 1
+}
@@ -0,0 +1,185 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dailylog/internal/mailin"
+	"dailylog/internal/storage"
+)
+
+// maxEmailSize bounds an inbound email, attachments included
+const maxEmailSize = 30 << 20
+
+// EmailHandler logs emails forwarded by a mail service as notes: the subject
+// is the title, the body the description, and attached files are stored
+// with the entry. Requests must carry the token, as ?token= or a bearer
+// token, and, if senders is set, the email must be from one of them.
+//
+// The email is the request body as a raw message (message/rfc822), or the
+// "email" (SendGrid) or "body-mime" (Mailgun) field of a form.
+type EmailHandler struct {
+	storage storage.DailyLogStorage
+	token   string
+	senders map[string]bool
+
+	// Attach, if set, stores the files attached to an email for an entry
+	// logged at t, returning their path; without it, files are dropped
+	Attach func(t time.Time, name string, data []byte) (string, error)
+}
+
+// NewEmailHandler logs emails to store from requests carrying token, sent
+// from the addresses given, or any
+func NewEmailHandler(store storage.DailyLogStorage, token string, senders []string) (*EmailHandler, error) {
+	if token == "" {
+		return nil, fmt.Errorf("email token is required")
+	}
+	h := &EmailHandler{storage: store, token: token}
+	for _, sender := range senders {
+		if sender = strings.ToLower(strings.TrimSpace(sender)); sender != "" {
+			if h.senders == nil {
+				h.senders = make(map[string]bool)
+			}
+			h.senders[sender] = true
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *EmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !hasToken(r, h.token) {
+		writeError(w, http.StatusUnauthorized, "invalid token")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxEmailSize)
+	raw, err := readEmail(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	msg, err := mailin.Parse(raw)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Answer refusals with success, so the mail service doesn't retry them
+	if h.senders != nil && !h.senders[msg.From] {
+		writeJSON(w, map[string]string{"status": "ignored", "reason": "sender not allowed"})
+		return
+	}
+	req, err := msg.Request()
+	if err != nil {
+		writeJSON(w, map[string]string{"status": "ignored", "reason": err.Error()})
+		return
+	}
+
+	if existing, err := h.logged(req); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if existing != nil {
+		writeJSON(w, map[string]string{"status": "duplicate", "id": existing.ID, "title": existing.Title})
+		return
+	}
+
+	var attachments, failed []string
+	for _, attachment := range msg.Attachments {
+		if h.Attach == nil {
+			failed = append(failed, attachment.Name)
+			continue
+		}
+		path, err := h.Attach(req.Date, attachment.Name, attachment.Data)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to store %s: %v", attachment.Name, err))
+			return
+		}
+		attachments = append(attachments, path)
+	}
+	if len(attachments) > 0 {
+		req.Metadata[storage.MetadataAttachment] = attachments[0]
+	}
+	if len(attachments) > 1 {
+		req.Metadata[storage.MetadataAttachments] = strings.Join(attachments, "\n")
+	}
+
+	entry, err := h.storage.CreateEntry(req)
+	if err != nil {
+		writeError(w, errorStatus(err), err.Error())
+		return
+	}
+	response := map[string]any{"status": "logged", "id": entry.ID, "title": entry.Title}
+	if len(failed) > 0 {
+		response["dropped_attachments"] = failed
+	}
+	writeJSON(w, response)
+}
+
+// logged returns the entry already logged from the same email, if any, as
+// when the mail service retries a delivery
+func (h *EmailHandler) logged(req storage.CreateLogEntryRequest) (*storage.DailyLogEntry, error) {
+	id := req.Metadata[storage.MetadataExternalID]
+	if id == "" {
+		return nil, nil
+	}
+	dayLog, err := h.storage.GetDay(req.Date)
+	if err != nil {
+		return nil, err
+	}
+	for i, entry := range dayLog.Entries {
+		if entry.Metadata[storage.MetadataSource] == mailin.Source && entry.Metadata[storage.MetadataExternalID] == id {
+			return &dayLog.Entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// readEmail returns the raw email in the request. A body sent as a form
+// without either field, as by curl --data-binary, is taken as the email.
+func readEmail(r *http.Request) ([]byte, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxEmailSize); err != nil {
+			return nil, fmt.Errorf("invalid form: %w", err)
+		}
+		return emailField(r.PostForm)
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read email: %w", err)
+	}
+	if mediaType == "application/x-www-form-urlencoded" {
+		if form, err := url.ParseQuery(string(data)); err == nil {
+			if raw, err := emailField(form); err == nil {
+				return raw, nil
+			}
+		}
+	}
+	return data, nil
+}
+
+// emailField returns the raw email in a form
+func emailField(form url.Values) ([]byte, error) {
+	for _, field := range []string{"email", "body-mime"} {
+		if value := form.Get(field); value != "" {
+			return []byte(value), nil
+		}
+	}
+	return nil, fmt.Errorf(`no "email" or "body-mime" field with the raw email`)
+}
+
+// errorStatus is the HTTP status of a failure to store an entry
+func errorStatus(err error) int {
+	var invalid storage.ValidationError
+	if errors.As(err, &invalid) {
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// ReadinessTTL is how long a storage health check result is reused, so
+// frequent probes don't each make a GitHub API request
+const ReadinessTTL = 15 * time.Second
+
+// Healthz reports that the process is up, for liveness probes
+func Healthz(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, map[string]string{"status": "ok"})
+}
+
+// Readiness reports whether storage is reachable, for readiness probes, and
+// stops reporting ready once the server starts shutting down
+type Readiness struct {
+	storage  storage.DailyLogStorage
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// NewReadiness creates a readiness check of the given storage
+func NewReadiness(store storage.DailyLogStorage) *Readiness {
+	return &Readiness{storage: store}
+}
+
+// Drain marks the server as shutting down, so it is no longer ready
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+// Check runs the storage health check, or returns the result of a recent one
+func (r *Readiness) Check() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.checked.IsZero() || time.Since(r.checked) > ReadinessTTL {
+		r.err = r.storage.HealthCheck()
+		r.checked = time.Now()
+	}
+	return r.err
+}
+
+// ServeHTTP implements http.Handler
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	if r.draining.Load() {
+		writeError(w, http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+	if err := r.Check(); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, map[string]string{"status": "ready"})
+}
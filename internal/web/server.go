@@ -0,0 +1,212 @@
+package web
+
+import (
+	"crypto/subtle"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// Server serves the read-only dashboard and its JSON API
+type Server struct {
+	storage   storage.DailyLogStorage
+	mux       *http.ServeMux
+	readiness *Readiness
+	token     string
+}
+
+// DaySummary is the per-day data used by the calendar and trend chart
+type DaySummary struct {
+	Date          string  `json:"date"`
+	TotalEntries  int     `json:"total_entries"`
+	StatusAverage float64 `json:"status_average,omitempty"`
+}
+
+// NewServer creates a new dashboard server backed by the given storage
+func NewServer(store storage.DailyLogStorage) *Server {
+	s := &Server{
+		storage:   store,
+		mux:       http.NewServeMux(),
+		readiness: NewReadiness(store),
+	}
+
+	static, _ := fs.Sub(staticFiles, "static")
+	s.Handle("GET /", http.FileServer(http.FS(static)))
+	s.Handle("GET /api/day", http.HandlerFunc(s.handleDay))
+	s.Handle("GET /api/days", http.HandlerFunc(s.handleDays))
+	s.Handle("GET /api/search", http.HandlerFunc(s.handleSearch))
+	s.mux.HandleFunc("GET /healthz", Healthz)
+	s.mux.Handle("GET /readyz", s.readiness)
+
+	return s
+}
+
+// RequireToken makes the dashboard, its API, and handlers added with Handle
+// require token, as the password of HTTP basic auth, which browsers prompt
+// for, or as a bearer token. Health checks and webhooks stay open.
+func (s *Server) RequireToken(token string) {
+	s.token = token
+}
+
+// Handle registers an additional handler behind the dashboard's token, e.g. for metrics
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, s.protect(handler))
+}
+
+// HandleWebhook registers a handler that checks its own secret, such as a
+// Slack signature or capture token, so it is served without the dashboard's
+func (s *Server) HandleWebhook(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// protect refuses requests without the token, if one is required
+func (s *Server) protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" && !s.authorized(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="dailylog"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// authorized reports whether r carries the token, as a basic auth password
+// (with any user name) or a bearer token
+func (s *Server) authorized(r *http.Request) bool {
+	_, value, ok := r.BasicAuth()
+	if bearer, isBearer := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); !ok && isBearer {
+		value = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(value), []byte(s.token)) == 1
+}
+
+// Drain makes /readyz fail, so load balancers stop sending requests before
+// the server shuts down
+func (s *Server) Drain() {
+	s.readiness.Drain()
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleDay returns the full day log for ?date=YYYY-MM-DD (defaults to today)
+func (s *Server) handleDay(w http.ResponseWriter, r *http.Request) {
+	date := time.Now()
+	if value := r.URL.Query().Get("date"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid date format: %s", value))
+			return
+		}
+		date = parsed
+	}
+
+	dayLog, err := s.storage.GetDay(date)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, dayLog)
+}
+
+// handleDays returns per-day totals and status averages for ?start=&end=
+func (s *Server) handleDays(w http.ResponseWriter, r *http.Request) {
+	start, end, err := parseRange(r, 30)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	days, err := s.storage.GetDateRange(start, end)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	summaries := make([]DaySummary, 0, len(days))
+	for _, day := range days {
+		summaries = append(summaries, DaySummary{
+			Date:          day.GetDateString(),
+			TotalEntries:  day.TotalEntries,
+			StatusAverage: day.StatusAverage,
+		})
+	}
+
+	writeJSON(w, summaries)
+}
+
+// handleSearch searches entries for ?q= within ?start=&end=
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	start, end, err := parseRange(r, 90)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := s.storage.SearchLogs(storage.LogSearchRequest{
+		SearchText: r.URL.Query().Get("q"),
+		DateStart:  &start,
+		DateEnd:    &end,
+		Limit:      200,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, result)
+}
+
+// parseRange reads ?start= and ?end=, defaulting to the last defaultDays days
+func parseRange(r *http.Request, defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -defaultDays)
+
+	if value := r.URL.Query().Get("start"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid start date format: %s", value)
+		}
+		start = parsed
+	}
+	if value := r.URL.Query().Get("end"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid end date format: %s", value)
+		}
+		end = parsed
+	}
+	if start.After(end) {
+		return start, end, fmt.Errorf("start date cannot be after end date")
+	}
+	if end.Sub(start) > 366*24*time.Hour {
+		return start, end, fmt.Errorf("date range cannot exceed one year")
+	}
+
+	return start, end, nil
+}
+
+func writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -0,0 +1,53 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"dailylog/internal/providers"
+	"dailylog/internal/storage"
+)
+
+func TestServerRequireToken(t *testing.T) {
+	dashboard := NewServer(providers.NewMemoryStorageProvider(storage.Config{}))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	dashboard.Handle("GET /metrics", ok)
+	dashboard.HandleWebhook("POST /capture", ok)
+	dashboard.RequireToken("secret")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   func(r *http.Request)
+		want   int
+	}{
+		{name: "dashboard", method: "GET", path: "/", want: http.StatusUnauthorized},
+		{name: "day", method: "GET", path: "/api/day", want: http.StatusUnauthorized},
+		{name: "days", method: "GET", path: "/api/days", want: http.StatusUnauthorized},
+		{name: "search", method: "GET", path: "/api/search?q=x", want: http.StatusUnauthorized},
+		{name: "metrics", method: "GET", path: "/metrics", want: http.StatusUnauthorized},
+		{name: "wrong password", method: "GET", path: "/api/days", auth: func(r *http.Request) { r.SetBasicAuth("me", "guess") }, want: http.StatusUnauthorized},
+		{name: "basic auth", method: "GET", path: "/api/days", auth: func(r *http.Request) { r.SetBasicAuth("me", "secret") }, want: http.StatusOK},
+		{name: "bearer", method: "GET", path: "/api/days", auth: func(r *http.Request) { r.Header.Set("Authorization", "Bearer secret") }, want: http.StatusOK},
+		{name: "health", method: "GET", path: "/healthz", want: http.StatusOK},
+		{name: "webhook checks its own secret", method: "POST", path: "/capture", want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.auth != nil {
+				tt.auth(req)
+			}
+			rec := httptest.NewRecorder()
+			dashboard.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("%s %s = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+			}
+			if rec.Code == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 without WWW-Authenticate, so browsers won't prompt")
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dailylog/internal/storage"
+)
+
+// Metadata keys of entries logged from Slack
+const (
+	SlackSource         = "slack"
+	MetadataSlackUser   = "slack_user"
+	MetadataSlackUserID = "slack_user_id"
+)
+
+// slackMaxAge is how old a request's timestamp may be before it's taken
+// for a replay
+const slackMaxAge = 5 * time.Minute
+
+// slackMaxBody bounds the size of a slash command request
+const slackMaxBody = 64 << 10
+
+// slackChannel matches a channel mention as Slack escapes it, e.g.
+// <#C024BE7LR|general>, so "#general" can still be a tag
+var slackChannel = regexp.MustCompile(`<#[A-Z0-9]+\|([^>]+)>`)
+
+// entryTypes are the entry types that can be logged over HTTP
+var entryTypes = []string{"activity", "status", "note", "summary", storage.EntryTypeMeeting, storage.EntryTypeLesson}
+
+// slackUsage answers /dailylog help and commands without text
+const slackUsage = "Log an entry: `/dailylog [type] text #tag @location !p2 ~45m status:7`\n" +
+	"Types: activity (the default), status, note, summary, meeting, lesson.\n" +
+	"e.g. `/dailylog note \"Pairing went well\" #team`"
+
+// SlackHandler logs entries from a Slack slash command, e.g.
+// /dailylog note "Pairing went well" #team. Requests must be signed with
+// the app's signing secret and, if teams is set, come from one of those
+// workspaces. The reply is shown only to whoever ran the command.
+type SlackHandler struct {
+	storage       storage.DailyLogStorage
+	signingSecret []byte
+	teams         map[string]bool
+}
+
+// NewSlackHandler logs entries to store from slash commands signed with
+// signingSecret, from the workspaces with the team IDs given, or any
+func NewSlackHandler(store storage.DailyLogStorage, signingSecret string, teams []string) (*SlackHandler, error) {
+	if signingSecret == "" {
+		return nil, fmt.Errorf("Slack signing secret is required")
+	}
+	h := &SlackHandler{storage: store, signingSecret: []byte(signingSecret)}
+	for _, team := range teams {
+		if team = strings.TrimSpace(team); team != "" {
+			if h.teams == nil {
+				h.teams = make(map[string]bool)
+			}
+			h.teams[team] = true
+		}
+	}
+	return h, nil
+}
+
+// ServeHTTP implements http.Handler
+func (h *SlackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, slackMaxBody))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	if err := h.verify(r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	if h.teams != nil && !h.teams[form.Get("team_id")] {
+		http.Error(w, "workspace not allowed", http.StatusForbidden)
+		return
+	}
+
+	req, err := parseSlackCommand(form.Get("text"))
+	if errors.Is(err, errSlackUsage) {
+		writeSlackReply(w, slackUsage)
+		return
+	}
+	if err != nil {
+		writeSlackReply(w, "Not logged: "+err.Error()+"\n"+slackUsage)
+		return
+	}
+	req.Date = time.Now()
+	req.Metadata = map[string]string{
+		storage.MetadataSource: SlackSource,
+		MetadataSlackUser:      form.Get("user_name"),
+		MetadataSlackUserID:    form.Get("user_id"),
+	}
+	// Whoever ran the command wrote the entry, not the server's owner
+	req.Author = form.Get("user_name")
+
+	entry, err := h.storage.CreateEntry(req)
+	if err != nil {
+		writeSlackReply(w, "Not logged: "+err.Error())
+		return
+	}
+	writeSlackReply(w, fmt.Sprintf("Logged %s: %s", entry.Type, entry.Title))
+}
+
+// verify checks the request was signed by Slack with the signing secret, and recently
+func (h *SlackHandler) verify(header http.Header, body []byte) error {
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing request timestamp")
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > slackMaxAge || age < -slackMaxAge {
+		return fmt.Errorf("stale request")
+	}
+
+	mac := hmac.New(sha256.New, h.signingSecret)
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// errSlackUsage asks for the usage to be shown
+var errSlackUsage = errors.New("usage")
+
+// parseSlackCommand turns a slash command's text into a create request: an
+// optional entry type, activity by default, then a quick entry as
+// 'dailyctl q' takes it. Quotes around the title are dropped.
+func parseSlackCommand(text string) (storage.CreateLogEntryRequest, error) {
+	text = strings.TrimSpace(slackChannel.ReplaceAllString(text, "#$1"))
+	if text == "" || strings.EqualFold(text, "help") {
+		return storage.CreateLogEntryRequest{}, errSlackUsage
+	}
+
+	entryType := "activity"
+	if first, rest, _ := strings.Cut(text, " "); first != "" {
+		for _, t := range entryTypes {
+			if strings.EqualFold(first, t) {
+				entryType, text = t, rest
+				break
+			}
+		}
+	}
+
+	req, err := storage.ParseQuickEntry(text)
+	if err != nil {
+		return req, err
+	}
+	req.Title = strings.Trim(req.Title, `"'“”‘’ `)
+	if req.Title == "" {
+		return req, storage.ValidationError{Field: "title", Message: "quick entry needs some text besides markers"}
+	}
+	req.Type = entryType
+	return req, nil
+}
+
+// writeSlackReply answers a slash command with a message only its user sees
+func writeSlackReply(w http.ResponseWriter, text string) {
+	writeJSON(w, map[string]string{"response_type": "ephemeral", "text": text})
+}